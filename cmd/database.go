@@ -69,6 +69,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] two-factor recovery code table maintained successfully")
 
+	err = datastore.Container.UserStore.SyncStructs(new(models.UserPasskey))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] user passkey table maintained successfully")
+
 	err = datastore.Container.TokenStore.SyncStructs(new(models.TokenRecord))
 
 	if err != nil {
@@ -77,6 +85,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] token record table maintained successfully")
 
+	err = datastore.Container.TokenStore.SyncStructs(new(models.DuplicateCheckRecord))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] duplicate check record table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.Account))
 
 	if err != nil {
@@ -133,6 +149,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction template table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionScheduleException))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction schedule exception table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionPictureInfo))
 
 	if err != nil {
@@ -181,5 +205,93 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] insights explorer table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Budget))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] budget table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSplit))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction split table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestmentSecurity))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] investment security table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestmentTransaction))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] investment transaction table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.SecurityPrice))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] security price table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionRule))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction rule table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Webhook))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] webhook table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.WebhookDelivery))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] webhook delivery table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.ExchangeRateHistory))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] exchange rate history table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.Ledger))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] ledger table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.LedgerMember))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] ledger member table maintained successfully")
+
 	return nil
 }
@@ -210,6 +210,25 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "user-set-role",
+			Usage:  "Set user role",
+			Action: bindAction(setUserRole),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.IntFlag{
+					Name:     "role",
+					Aliases:  []string{"r"},
+					Required: true,
+					Usage:    "Specific user role (0: Normal, 1: Administrator)",
+				},
+			},
+		},
 		{
 			Name:   "user-delete",
 			Usage:  "Delete specified user",
@@ -236,6 +255,38 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "user-passkey-list",
+			Usage:  "List all user passkeys",
+			Action: bindAction(listUserPasskeys),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+			},
+		},
+		{
+			Name:   "user-passkey-revoke",
+			Usage:  "Revoke the specified user passkey",
+			Action: bindAction(revokeUserPasskey),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.Int64Flag{
+					Name:     "id",
+					Aliases:  []string{"i"},
+					Required: true,
+					Usage:    "Specific passkey id",
+				},
+			},
+		},
 		{
 			Name:   "user-session-list",
 			Usage:  "List all user sessions",
@@ -272,6 +323,12 @@ var UserData = &cli.Command{
 					Required: true,
 					Usage:    "Token expiration time in seconds (0 - 4294967295, 0 means no expiration).",
 				},
+				&cli.StringFlag{
+					Name:     "scopes",
+					Aliases:  []string{"s"},
+					Required: false,
+					Usage:    "Specific token scopes separated by commas. For api token, supports \"user_profile:read\", \"accounts:read\", \"accounts:write\", \"transactions:read\", \"transactions:write\", \"basic_data:read\", \"basic_data:write\", \"budgets:read\", \"budgets:write\", \"investments:read\", \"investments:write\", \"statistics:read\", \"exchange_rates:read\", \"data:export\" and \"data:import\", default is full access. For mcp token, supports \"basic_data:read\", \"transactions:read\", \"statistics:read\" and \"transactions:write\", default is all scopes",
+				},
 			},
 		},
 		{
@@ -385,7 +442,45 @@ var UserData = &cli.Command{
 					Name:     "type",
 					Aliases:  []string{"t"},
 					Required: false,
-					Usage:    "Export file type, support csv or tsv, default is csv",
+					Usage:    "Export file type, support csv, tsv, ofx, qif_ymd, qif_mdy, qif_dmy, gnucash or beancount, default is csv",
+				},
+			},
+		},
+		{
+			Name:   "user-backup",
+			Usage:  "Backup user all data (including custom icons and transaction pictures) to archive file",
+			Action: bindAction(backupUserData),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Specific backup archive file path (e.g. backup.zip)",
+				},
+			},
+		},
+		{
+			Name:   "user-restore",
+			Usage:  "Restore user all data from backup archive file to specified user which does not have any data",
+			Action: bindAction(restoreUserData),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Specific backup archive file path (e.g. backup.zip)",
 				},
 			},
 		},
@@ -653,6 +748,27 @@ func setUserEmailUnverified(c *core.CliContext) error {
 	return nil
 }
 
+func setUserRole(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	role := models.UserRole(c.Int("role"))
+	err = clis.UserData.SetUserRole(c, username, role)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.setUserRole] error occurs when setting user role")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.setUserRole] user \"%s\" role has been set to \"%s\"", username, role)
+
+	return nil
+}
+
 func deleteUser(c *core.CliContext) error {
 	_, err := initializeSystem(c)
 
@@ -693,6 +809,53 @@ func disableUser2FA(c *core.CliContext) error {
 	return nil
 }
 
+func listUserPasskeys(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	passkeys, err := clis.UserData.ListUserPasskeys(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.listUserPasskeys] error occurs when getting user passkeys")
+		return err
+	}
+
+	for i := 0; i < len(passkeys); i++ {
+		printPasskeyInfo(passkeys[i])
+
+		if i < len(passkeys)-1 {
+			fmt.Printf("---\n")
+		}
+	}
+
+	return nil
+}
+
+func revokeUserPasskey(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	passkeyId := c.Int64("id")
+	err = clis.UserData.RevokeUserPasskey(c, username, passkeyId)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.revokeUserPasskey] error occurs when revoking user passkey")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.revokeUserPasskey] passkey \"id:%d\" of user \"%s\" has been revoked successfully", passkeyId, username)
+
+	return nil
+}
+
 func listUserTokens(c *core.CliContext) error {
 	_, err := initializeSystem(c)
 
@@ -744,7 +907,21 @@ func createNewUserToken(c *core.CliContext) error {
 		return nil
 	}
 
-	token, tokenString, err := clis.UserData.CreateNewUserToken(c, username, tokenType, expiresInSeconds)
+	var apiTokenScopes core.APITokenScopes
+	var mcpTokenScopes core.MCPTokenScopes
+
+	if tokenType == "api" {
+		apiTokenScopes, err = core.ParseAPITokenScopes(c.String("scopes"))
+	} else if tokenType == "mcp" {
+		mcpTokenScopes, err = core.ParseMCPTokenScopes(c.String("scopes"))
+	}
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.createNewUserToken] %s", err.Error())
+		return nil
+	}
+
+	token, tokenString, err := clis.UserData.CreateNewUserToken(c, username, tokenType, expiresInSeconds, apiTokenScopes, mcpTokenScopes)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.createNewUserToken] error occurs when creating user token")
@@ -858,7 +1035,7 @@ func exportUserTransaction(c *core.CliContext) error {
 		fileType = "csv"
 	}
 
-	if fileType != "csv" && fileType != "tsv" {
+	if fileType != "csv" && fileType != "tsv" && fileType != "ofx" && fileType != "qif_ymd" && fileType != "qif_mdy" && fileType != "qif_dmy" && fileType != "gnucash" && fileType != "beancount" {
 		log.CliErrorf(c, "[user_data.exportUserTransaction] export file type is not supported")
 		return errs.ErrNotSupported
 	}
@@ -945,6 +1122,92 @@ func importUserTransaction(c *core.CliContext) error {
 	return nil
 }
 
+func backupUserData(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	filePath := c.String("file")
+
+	if filePath == "" {
+		log.CliErrorf(c, "[user_data.backupUserData] backup file path is unspecified")
+		return os.ErrNotExist
+	}
+
+	fileExists, err := utils.IsExists(filePath)
+
+	if fileExists {
+		log.CliErrorf(c, "[user_data.backupUserData] specified file path already exists")
+		return os.ErrExist
+	}
+
+	log.CliInfof(c, "[user_data.backupUserData] starting backing up user \"%s\" data", username)
+
+	content, err := clis.UserData.BackupUserData(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.backupUserData] error occurs when backing up user data")
+		return err
+	}
+
+	err = utils.WriteFile(filePath, content)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.backupUserData] failed to write to %s", filePath)
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.backupUserData] user data has been backed up to %s", filePath)
+
+	return nil
+}
+
+func restoreUserData(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	filePath := c.String("file")
+
+	if filePath == "" {
+		log.CliErrorf(c, "[user_data.restoreUserData] backup file path is not specified")
+		return os.ErrNotExist
+	}
+
+	fileExists, err := utils.IsExists(filePath)
+
+	if !fileExists {
+		log.CliErrorf(c, "[user_data.restoreUserData] backup file does not exist")
+		return os.ErrNotExist
+	}
+
+	data, err := os.ReadFile(filePath)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.restoreUserData] failed to load backup file")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.restoreUserData] start restoring data to user \"%s\"", username)
+
+	err = clis.UserData.RestoreUserData(c, username, data)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.restoreUserData] error occurs when restoring user data")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.restoreUserData] data has been restored to user \"%s\"", username)
+
+	return nil
+}
+
 func printUserInfo(user *models.User) {
 	fmt.Printf("[Uid] %d\n", user.Uid)
 	fmt.Printf("[Username] %s\n", user.Username)
@@ -975,6 +1238,7 @@ func printUserInfo(user *models.User) {
 	fmt.Printf("[ExpenseAmountColor] %s (%d)\n", user.ExpenseAmountColor, user.ExpenseAmountColor)
 	fmt.Printf("[IncomeAmountColor] %s (%d)\n", user.IncomeAmountColor, user.IncomeAmountColor)
 	fmt.Printf("[FeatureRestriction] %s (%d)\n", user.FeatureRestriction, user.FeatureRestriction)
+	fmt.Printf("[Role] %s (%d)\n", user.Role, user.Role)
 	fmt.Printf("[Deleted] %t\n", user.Deleted)
 	fmt.Printf("[EmailVerified] %t\n", user.EmailVerified)
 	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(user.CreatedUnixTime), user.CreatedUnixTime)
@@ -992,6 +1256,18 @@ func printUserInfo(user *models.User) {
 	}
 }
 
+func printPasskeyInfo(passkey *models.UserPasskey) {
+	fmt.Printf("[Id] %d\n", passkey.PasskeyId)
+	fmt.Printf("[Name] %s\n", passkey.Name)
+	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(passkey.CreatedUnixTime), passkey.CreatedUnixTime)
+
+	if passkey.LastUsedUnixTime > 0 {
+		fmt.Printf("[LastUsedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(passkey.LastUsedUnixTime), passkey.LastUsedUnixTime)
+	} else {
+		fmt.Printf("[LastUsedAt] Never\n")
+	}
+}
+
 func printTokenInfo(token *models.TokenRecord) {
 	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(token.CreatedUnixTime), token.CreatedUnixTime)
 	fmt.Printf("[ExpiredAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(token.ExpiredUnixTime), token.ExpiredUnixTime)
@@ -4,13 +4,17 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/mail"
 	"github.com/mayswind/ezbookkeeping/pkg/requestid"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
@@ -43,6 +47,23 @@ var Utilities = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "backfill-exchange-rates",
+			Usage:  "Save the historical exchange rates of current data source to the exchange rate history",
+			Action: bindAction(backfillExchangeRates),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "start-date",
+					Required: true,
+					Usage:    "Start date (format: yyyy-mm-dd)",
+				},
+				&cli.StringFlag{
+					Name:     "end-date",
+					Required: false,
+					Usage:    "End date (format: yyyy-mm-dd), default is today",
+				},
+			},
+		},
 	},
 }
 
@@ -102,6 +123,58 @@ func sendTestMail(c *core.CliContext) error {
 	return nil
 }
 
+func backfillExchangeRates(c *core.CliContext) error {
+	config, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	if !exchangerates.Container.SupportsHistoricalExchangeRates() {
+		return errs.ErrExchangeRatesDataSourceNotSupportHistoryData
+	}
+
+	startTime, err := utils.ParseFromLongDateFirstTime(c.String("start-date"), 0)
+
+	if err != nil {
+		log.CliErrorf(c, "[utility.backfillExchangeRates] failed to parse start date \"%s\", because %s", c.String("start-date"), err.Error())
+		return errs.ErrExchangeRateHistoryDateInvalid
+	}
+
+	endTime := time.Now().UTC()
+
+	if c.String("end-date") != "" {
+		endTime, err = utils.ParseFromLongDateFirstTime(c.String("end-date"), 0)
+
+		if err != nil {
+			log.CliErrorf(c, "[utility.backfillExchangeRates] failed to parse end date \"%s\", because %s", c.String("end-date"), err.Error())
+			return errs.ErrExchangeRateHistoryDateInvalid
+		}
+	}
+
+	if endTime.Before(startTime) {
+		return errs.ErrExchangeRateHistoryDateRangeInvalid
+	}
+
+	exchangeRateResps, err := exchangerates.Container.GetHistoricalExchangeRates(c, startTime, endTime)
+
+	if err != nil {
+		log.CliErrorf(c, "[utility.backfillExchangeRates] failed to get historical exchange rates, because %s", err.Error())
+		return err
+	}
+
+	savedDays, err := services.ExchangeRateHistories.SaveExchangeRates(c, config.ExchangeRatesDataSource, exchangeRateResps)
+
+	if err != nil {
+		log.CliErrorf(c, "[utility.backfillExchangeRates] failed to save historical exchange rates, because %s", err.Error())
+		return err
+	}
+
+	log.CliInfof(c, "[utility.backfillExchangeRates] exchange rates of %d days have been saved", savedDays)
+
+	return nil
+}
+
 func printRequestIdInfo(requestId string, requestIdInfo *requestid.RequestIdInfo, newRequestIdInfo *requestid.RequestIdInfo) {
 	fmt.Printf("[RequestId] %s\n", requestId)
 	fmt.Printf("[ServerUniqId] %d (Current Server %d)\n", requestIdInfo.ServerUniqId, newRequestIdInfo.ServerUniqId)
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"path/filepath"
 	"time"
@@ -15,7 +18,9 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/mayswind/ezbookkeeping/pkg/api"
+	"github.com/mayswind/ezbookkeeping/pkg/auth/ldap"
 	"github.com/mayswind/ezbookkeeping/pkg/auth/oauth2"
+	"github.com/mayswind/ezbookkeeping/pkg/auth/saml"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/cron"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -80,6 +85,20 @@ func startWebServer(c *core.CliContext) error {
 		return err
 	}
 
+	err = saml.InitializeSAMLServiceProvider(config)
+
+	if err != nil {
+		log.BootErrorf(c, "[webserver.startWebServer] initializes saml 2.0 service provider failed, because %s", err.Error())
+		return err
+	}
+
+	err = ldap.InitializeLDAPAuthentication(config)
+
+	if err != nil {
+		log.BootErrorf(c, "[webserver.startWebServer] initializes ldap authentication failed, because %s", err.Error())
+		return err
+	}
+
 	err = cron.InitializeCronJobSchedulerContainer(c, config, true)
 
 	if err != nil {
@@ -189,6 +208,7 @@ func startWebServer(c *core.CliContext) error {
 	if config.AvatarProvider == core.USER_AVATAR_PROVIDER_INTERNAL {
 		avatarRoute := router.Group("/avatar")
 		avatarRoute.Use(bindMiddleware(middlewares.JWTAuthorizationByQueryString(config), config))
+		avatarRoute.Use(bindMiddleware(middlewares.LedgerMemberAuthorization(config), config))
 		{
 			avatarRoute.GET("/:fileName", bindImage(api.Users.UserGetAvatarHandler, config))
 		}
@@ -197,6 +217,7 @@ func startWebServer(c *core.CliContext) error {
 	if config.EnableTransactionPictures {
 		pictureRoute := router.Group("/pictures")
 		pictureRoute.Use(bindMiddleware(middlewares.JWTAuthorizationByQueryString(config), config))
+		pictureRoute.Use(bindMiddleware(middlewares.LedgerMemberAuthorization(config), config))
 		{
 			pictureRoute.GET("/:fileName", bindImage(api.TransactionPictures.TransactionPictureGetHandler, config))
 		}
@@ -205,6 +226,7 @@ func startWebServer(c *core.CliContext) error {
 	if config.EnableUserCustomIcon {
 		customIconRoute := router.Group("/icons")
 		customIconRoute.Use(bindMiddleware(middlewares.JWTAuthorizationByQueryString(config), config))
+		customIconRoute.Use(bindMiddleware(middlewares.LedgerMemberAuthorization(config), config))
 		{
 			customIconRoute.GET("/:fileName", bindImage(api.UserCustomIcons.CustomIconGetHandler, config))
 		}
@@ -256,18 +278,23 @@ func startWebServer(c *core.CliContext) error {
 		mcpRoute.Use(bindMiddleware(middlewares.RequestLog, config))
 		mcpRoute.Use(bindMiddleware(middlewares.MCPServerIpLimit(config), config))
 		mcpRoute.Use(bindMiddleware(middlewares.JWTMCPAuthorization(config), config))
+		mcpRoute.Use(bindMiddleware(middlewares.MCPSessionValidation, config))
 		{
 			mcpRoute.POST("", bindJSONRPCApi(map[string]core.JSONRPCApiHandlerFunc{
-				"initialize":     api.ModelContextProtocols.InitializeHandler,
-				"resources/list": api.ModelContextProtocols.ListResourcesHandler,
-				"resources/read": api.ModelContextProtocols.ReadResourceHandler,
-				"tools/list":     api.ModelContextProtocols.ListToolsHandler,
-				"tools/call":     api.ModelContextProtocols.CallToolHandler,
-				"ping":           api.ModelContextProtocols.PingHandler,
+				"initialize":               api.ModelContextProtocols.InitializeHandler,
+				"resources/list":           api.ModelContextProtocols.ListResourcesHandler,
+				"resources/templates/list": api.ModelContextProtocols.ListResourceTemplatesHandler,
+				"resources/read":           api.ModelContextProtocols.ReadResourceHandler,
+				"prompts/list":             api.ModelContextProtocols.ListPromptsHandler,
+				"prompts/get":              api.ModelContextProtocols.GetPromptHandler,
+				"tools/list":               api.ModelContextProtocols.ListToolsHandler,
+				"tools/call":               api.ModelContextProtocols.CallToolHandler,
+				"ping":                     api.ModelContextProtocols.PingHandler,
 			}, map[string]int{
 				"notifications/initialized": http.StatusAccepted,
 			}, config))
-			mcpRoute.GET("", bindApi(api.Default.MethodNotAllowed, config))
+			mcpRoute.GET("", bindEventStreamApi(api.ModelContextProtocols.EventStreamHandler, config))
+			mcpRoute.DELETE("", bindApi(api.ModelContextProtocols.DeleteSessionHandler, config))
 		}
 	}
 
@@ -281,6 +308,17 @@ func startWebServer(c *core.CliContext) error {
 		}
 	}
 
+	if config.EnableSAMLLogin {
+		samlRoute := router.Group("/saml")
+		samlRoute.Use(bindMiddleware(middlewares.RequestId(config), config))
+		samlRoute.Use(bindMiddleware(middlewares.RequestLog, config))
+		{
+			samlRoute.GET("/metadata", bindXml(api.SAMLAuthentications.MetadataHandler, config))
+			samlRoute.GET("/login", bindRedirect(api.SAMLAuthentications.LoginHandler, config))
+			samlRoute.POST("/acs", bindRedirect(api.SAMLAuthentications.AssertionConsumerHandler, config))
+		}
+	}
+
 	apiRoute := router.Group("/api")
 
 	apiRoute.Use(bindMiddleware(middlewares.RequestId(config), config))
@@ -290,16 +328,30 @@ func startWebServer(c *core.CliContext) error {
 			apiRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.Authorizations.AuthorizeHandler, config))
 		}
 
-		if config.EnableInternalAuth && config.EnableTwoFactor {
+		if (config.EnableInternalAuth || config.EnableLDAPLogin) && config.EnableTwoFactor {
 			twoFactorRoute := apiRoute.Group("/2fa")
 			twoFactorRoute.Use(bindMiddleware(middlewares.JWTTwoFactorAuthorization(config), config))
 			{
 				twoFactorRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeHandler, config))
 				twoFactorRoute.POST("/recovery.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeByRecoveryCodeHandler, config))
+
+				if config.EnablePasskey {
+					twoFactorRoute.POST("/passkey/begin.json", bindApi(api.Authorizations.TwoFactorPasskeyAuthorizeBeginHandler, config))
+					twoFactorRoute.POST("/passkey/authorize.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorPasskeyAuthorizeHandler, config))
+				}
 			}
 		}
 
-		if config.EnableOAuth2Login {
+		if config.EnableInternalAuth && config.EnablePasskey {
+			apiRoute.POST("/passkey/authorize/begin.json", bindApi(api.Authorizations.PasskeyAuthorizeBeginHandler, config))
+			apiRoute.POST("/passkey/authorize.json", bindApiWithTokenUpdate(api.Authorizations.PasskeyAuthorizeHandler, config))
+		}
+
+		if config.EnableLDAPLogin {
+			apiRoute.POST("/ldap/authorize.json", bindApiWithTokenUpdate(api.Authorizations.LDAPAuthorizeHandler, config))
+		}
+
+		if config.EnableOAuth2Login || config.EnableSAMLLogin {
 			oauth2Route := apiRoute.Group("/oauth2")
 			oauth2Route.Use(bindMiddleware(middlewares.JWTOAuth2CallbackAuthorization(config), config))
 			{
@@ -331,11 +383,18 @@ func startWebServer(c *core.CliContext) error {
 			}
 		}
 
+		ledgerInvitationRoute := apiRoute.Group("/ledger_invitations/accept")
+		ledgerInvitationRoute.Use(bindMiddleware(middlewares.JWTLedgerInvitationAuthorization(config), config))
+		{
+			ledgerInvitationRoute.POST("/by_token.json", bindApi(api.Ledgers.LedgerInvitationAcceptByTokenHandler, config))
+		}
+
 		apiRoute.GET("/logout.json", bindApiWithTokenUpdate(api.Tokens.TokenRevokeCurrentHandler, config))
 
 		apiV1Route := apiRoute.Group("/v1")
 		apiV1Route.Use(bindMiddleware(middlewares.JWTAuthorizationByHeader(config), config))
 		apiV1Route.Use(bindMiddleware(middlewares.APITokenIpLimit(config), config))
+		apiV1Route.Use(bindMiddleware(middlewares.LedgerMemberAuthorization(config), config))
 		{
 			// Tokens
 			apiV1Route.GET("/tokens/list.json", bindApi(api.Tokens.TokenListHandler, config))
@@ -359,7 +418,7 @@ func startWebServer(c *core.CliContext) error {
 			}
 
 			// External Authentications
-			if config.EnableOAuth2Login {
+			if config.EnableOAuth2Login || config.EnableSAMLLogin {
 				apiV1Route.GET("/users/external_auth/list.json", bindApi(api.UserExternalAuths.ExternalAuthListHandler, config))
 				apiV1Route.POST("/users/external_auth/unlink.json", bindApi(api.UserExternalAuths.UnlinkExternalAuthHandler, config))
 			}
@@ -378,6 +437,14 @@ func startWebServer(c *core.CliContext) error {
 				apiV1Route.POST("/users/2fa/recovery/regenerate.json", bindApi(api.TwoFactorAuthorizations.TwoFactorRecoveryCodeRegenerateHandler, config))
 			}
 
+			// Passkeys
+			if config.EnableInternalAuth && config.EnablePasskey {
+				apiV1Route.GET("/users/passkeys/list.json", bindApi(api.UserPasskeys.PasskeyListHandler, config))
+				apiV1Route.POST("/users/passkeys/register/begin.json", bindApi(api.UserPasskeys.PasskeyRegisterBeginHandler, config))
+				apiV1Route.POST("/users/passkeys/register/finish.json", bindApi(api.UserPasskeys.PasskeyRegisterFinishHandler, config))
+				apiV1Route.POST("/users/passkeys/delete.json", bindApi(api.UserPasskeys.PasskeyDeleteHandler, config))
+			}
+
 			// Data
 			apiV1Route.GET("/data/statistics.json", bindApi(api.DataManagements.DataStatisticsHandler, config))
 			apiV1Route.POST("/data/clear/all.json", bindApi(api.DataManagements.ClearAllDataHandler, config))
@@ -387,6 +454,17 @@ func startWebServer(c *core.CliContext) error {
 			if config.EnableDataExport {
 				apiV1Route.GET("/data/export.csv", bindCsv(api.DataManagements.ExportDataToEzbookkeepingCSVHandler, config))
 				apiV1Route.GET("/data/export.tsv", bindTsv(api.DataManagements.ExportDataToEzbookkeepingTSVHandler, config))
+				apiV1Route.GET("/data/export.ofx", bindOfx(api.DataManagements.ExportDataToOFXHandler, config))
+				apiV1Route.GET("/data/export_ymd.qif", bindQif(api.DataManagements.ExportDataToQIFYearMonthDayHandler, config))
+				apiV1Route.GET("/data/export_mdy.qif", bindQif(api.DataManagements.ExportDataToQIFMonthDayYearHandler, config))
+				apiV1Route.GET("/data/export_dmy.qif", bindQif(api.DataManagements.ExportDataToQIFDayMonthYearHandler, config))
+				apiV1Route.GET("/data/export.gnucash", bindGnuCash(api.DataManagements.ExportDataToGnuCashHandler, config))
+				apiV1Route.GET("/data/export.beancount", bindBeancount(api.DataManagements.ExportDataToBeancountHandler, config))
+				apiV1Route.GET("/data/backup.zip", bindZip(api.DataManagements.BackupDataHandler, config))
+			}
+
+			if config.EnableDataImport {
+				apiV1Route.POST("/data/restore.json", bindApi(api.DataManagements.RestoreDataHandler, config))
 			}
 
 			// Accounts
@@ -400,6 +478,11 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/accounts/delete.json", bindApi(api.Accounts.AccountDeleteHandler, config))
 			apiV1Route.POST("/accounts/sub_account/delete.json", bindApi(api.Accounts.SubAccountDeleteHandler, config))
 
+			// Loans
+			apiV1Route.GET("/accounts/loan/get.json", bindApi(api.Loans.LoanGetHandler, config))
+			apiV1Route.POST("/accounts/loan/set.json", bindApi(api.Loans.LoanSetHandler, config))
+			apiV1Route.POST("/accounts/loan/delete.json", bindApi(api.Loans.LoanDeleteHandler, config))
+
 			// Transactions
 			apiV1Route.GET("/transactions/count.json", bindApi(api.Transactions.TransactionCountHandler, config))
 			apiV1Route.GET("/transactions/list.json", bindApi(api.Transactions.TransactionListHandler, config))
@@ -410,6 +493,7 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.GET("/transactions/statistics/trends.json", bindApi(api.Transactions.TransactionStatisticsTrendsHandler, config))
 			apiV1Route.GET("/transactions/statistics/asset_trends.json", bindApi(api.Transactions.TransactionStatisticsAssetTrendsHandler, config))
 			apiV1Route.GET("/transactions/amounts.json", bindApi(api.Transactions.TransactionAmountsHandler, config))
+			apiV1Route.GET("/transactions/forecast.json", bindApi(api.Transactions.TransactionForecastHandler, config))
 			apiV1Route.GET("/transactions/get.json", bindApi(api.Transactions.TransactionGetHandler, config))
 			apiV1Route.POST("/transactions/add.json", bindApi(api.Transactions.TransactionCreateHandler, config))
 			apiV1Route.POST("/transactions/modify.json", bindApi(api.Transactions.TransactionModifyHandler, config))
@@ -471,6 +555,45 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/transaction/templates/hide.json", bindApi(api.TransactionTemplates.TemplateHideHandler, config))
 			apiV1Route.POST("/transaction/templates/move.json", bindApi(api.TransactionTemplates.TemplateMoveHandler, config))
 			apiV1Route.POST("/transaction/templates/delete.json", bindApi(api.TransactionTemplates.TemplateDeleteHandler, config))
+			apiV1Route.GET("/transaction/templates/schedule/preview.json", bindApi(api.TransactionTemplates.TemplateSchedulePreviewHandler, config))
+			apiV1Route.GET("/transaction/templates/schedule/exceptions/list.json", bindApi(api.TransactionTemplates.TemplateScheduleExceptionListHandler, config))
+			apiV1Route.POST("/transaction/templates/schedule/exceptions/add.json", bindApi(api.TransactionTemplates.TemplateScheduleExceptionCreateHandler, config))
+			apiV1Route.POST("/transaction/templates/schedule/exceptions/delete.json", bindApi(api.TransactionTemplates.TemplateScheduleExceptionDeleteHandler, config))
+
+			// Transaction Rules
+			apiV1Route.GET("/transaction/rules/list.json", bindApi(api.TransactionRules.RuleListHandler, config))
+			apiV1Route.GET("/transaction/rules/get.json", bindApi(api.TransactionRules.RuleGetHandler, config))
+			apiV1Route.POST("/transaction/rules/add.json", bindApi(api.TransactionRules.RuleCreateHandler, config))
+			apiV1Route.POST("/transaction/rules/modify.json", bindApi(api.TransactionRules.RuleModifyHandler, config))
+			apiV1Route.POST("/transaction/rules/hide.json", bindApi(api.TransactionRules.RuleHideHandler, config))
+			apiV1Route.POST("/transaction/rules/move.json", bindApi(api.TransactionRules.RuleMoveHandler, config))
+			apiV1Route.POST("/transaction/rules/delete.json", bindApi(api.TransactionRules.RuleDeleteHandler, config))
+			apiV1Route.POST("/transaction/rules/apply.json", bindApi(api.TransactionRules.RuleApplyHandler, config))
+
+			// Webhooks
+			if config.EnableWebhook {
+				apiV1Route.GET("/webhooks/list.json", bindApi(api.Webhooks.WebhookListHandler, config))
+				apiV1Route.GET("/webhooks/get.json", bindApi(api.Webhooks.WebhookGetHandler, config))
+				apiV1Route.POST("/webhooks/add.json", bindApi(api.Webhooks.WebhookCreateHandler, config))
+				apiV1Route.POST("/webhooks/modify.json", bindApi(api.Webhooks.WebhookModifyHandler, config))
+				apiV1Route.POST("/webhooks/delete.json", bindApi(api.Webhooks.WebhookDeleteHandler, config))
+				apiV1Route.GET("/webhooks/deliveries/list.json", bindApi(api.Webhooks.WebhookDeliveryListHandler, config))
+				apiV1Route.POST("/webhooks/deliveries/replay.json", bindApi(api.Webhooks.WebhookDeliveryReplayHandler, config))
+			}
+
+			// Ledgers
+			apiV1Route.GET("/ledgers/list.json", bindApi(api.Ledgers.LedgerListHandler, config))
+			apiV1Route.GET("/ledgers/get.json", bindApi(api.Ledgers.LedgerGetHandler, config))
+			apiV1Route.POST("/ledgers/add.json", bindApi(api.Ledgers.LedgerCreateHandler, config))
+			apiV1Route.POST("/ledgers/modify.json", bindApi(api.Ledgers.LedgerModifyHandler, config))
+			apiV1Route.POST("/ledgers/delete.json", bindApi(api.Ledgers.LedgerDeleteHandler, config))
+			apiV1Route.POST("/ledgers/members/invite.json", bindApi(api.Ledgers.LedgerMemberInviteHandler, config))
+			apiV1Route.POST("/ledgers/members/modify.json", bindApi(api.Ledgers.LedgerMemberModifyHandler, config))
+			apiV1Route.POST("/ledgers/members/remove.json", bindApi(api.Ledgers.LedgerMemberRemoveHandler, config))
+			apiV1Route.GET("/ledgers/members/statistics.json", bindApi(api.Ledgers.LedgerMemberStatisticsHandler, config))
+			apiV1Route.GET("/ledgers/invitations/list.json", bindApi(api.Ledgers.LedgerInvitationListHandler, config))
+			apiV1Route.POST("/ledgers/invitations/accept.json", bindApi(api.Ledgers.LedgerInvitationAcceptHandler, config))
+			apiV1Route.POST("/ledgers/invitations/decline.json", bindApi(api.Ledgers.LedgerInvitationDeclineHandler, config))
 
 			// Insights Explorers
 			apiV1Route.GET("/insights/explorers/list.json", bindApi(api.InsightsExplorers.InsightsExplorerListHandler, config))
@@ -481,6 +604,30 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/insights/explorers/move.json", bindApi(api.InsightsExplorers.InsightsExplorerMoveHandler, config))
 			apiV1Route.POST("/insights/explorers/delete.json", bindApi(api.InsightsExplorers.InsightsExplorerDeleteHandler, config))
 
+			// Budgets
+			apiV1Route.GET("/budgets/list.json", bindApi(api.Budgets.BudgetListHandler, config))
+			apiV1Route.GET("/budgets/get.json", bindApi(api.Budgets.BudgetGetHandler, config))
+			apiV1Route.GET("/budgets/progress.json", bindApi(api.Budgets.BudgetProgressHandler, config))
+			apiV1Route.POST("/budgets/add.json", bindApi(api.Budgets.BudgetCreateHandler, config))
+			apiV1Route.POST("/budgets/modify.json", bindApi(api.Budgets.BudgetModifyHandler, config))
+			apiV1Route.POST("/budgets/hide.json", bindApi(api.Budgets.BudgetHideHandler, config))
+			apiV1Route.POST("/budgets/move.json", bindApi(api.Budgets.BudgetMoveHandler, config))
+			apiV1Route.POST("/budgets/delete.json", bindApi(api.Budgets.BudgetDeleteHandler, config))
+
+			// Investments
+			apiV1Route.GET("/investments/securities/list.json", bindApi(api.Investments.SecurityListHandler, config))
+			apiV1Route.POST("/investments/securities/add.json", bindApi(api.Investments.SecurityCreateHandler, config))
+			apiV1Route.POST("/investments/securities/modify.json", bindApi(api.Investments.SecurityModifyHandler, config))
+			apiV1Route.POST("/investments/securities/delete.json", bindApi(api.Investments.SecurityDeleteHandler, config))
+			apiV1Route.GET("/investments/transactions/list.json", bindApi(api.Investments.InvestmentTransactionListHandler, config))
+			apiV1Route.POST("/investments/transactions/add.json", bindApi(api.Investments.InvestmentTransactionCreateHandler, config))
+			apiV1Route.POST("/investments/transactions/delete.json", bindApi(api.Investments.InvestmentTransactionDeleteHandler, config))
+			apiV1Route.GET("/investments/prices/list.json", bindApi(api.Investments.SecurityPriceListHandler, config))
+			apiV1Route.POST("/investments/prices/add.json", bindApi(api.Investments.SecurityPriceCreateHandler, config))
+			apiV1Route.POST("/investments/prices/delete.json", bindApi(api.Investments.SecurityPriceDeleteHandler, config))
+			apiV1Route.POST("/investments/prices/update.json", bindApi(api.Investments.SecurityPriceUpdateHandler, config))
+			apiV1Route.GET("/investments/holdings.json", bindApi(api.Investments.InvestmentHoldingListHandler, config))
+
 			// Large Language Models
 			if config.TextRecognitionLLMConfig != nil && config.TextRecognitionLLMConfig.LLMProvider != "" {
 				if config.TransactionFromAITextRecognition {
@@ -504,11 +651,37 @@ func startWebServer(c *core.CliContext) error {
 
 			// Exchange Rates
 			apiV1Route.GET("/exchange_rates/latest.json", bindApi(api.ExchangeRates.LatestExchangeRateHandler, config))
+			apiV1Route.GET("/exchange_rates/historical.json", bindApi(api.ExchangeRates.HistoricalExchangeRateHandler, config))
 			apiV1Route.POST("/exchange_rates/user_custom/update.json", bindApi(api.ExchangeRates.UserCustomExchangeRateUpdateHandler, config))
 			apiV1Route.POST("/exchange_rates/user_custom/delete.json", bindApi(api.ExchangeRates.UserCustomExchangeRateDeleteHandler, config))
 
 			// System
 			apiV1Route.GET("/systems/version.json", bindApi(api.Systems.VersionHandler, config))
+
+			// Administrator
+			adminRoute := apiV1Route.Group("/admin")
+			adminRoute.Use(bindMiddleware(middlewares.AdministratorAuthorization(config), config))
+			{
+				adminRoute.GET("/users/list.json", bindApi(api.Admins.UserListHandler, config))
+				adminRoute.GET("/users/get.json", bindApi(api.Admins.UserGetHandler, config))
+				adminRoute.GET("/users/data/statistics.json", bindApi(api.Admins.UserDataStatisticsHandler, config))
+				adminRoute.POST("/users/enable.json", bindApi(api.Admins.UserEnableHandler, config))
+				adminRoute.POST("/users/disable.json", bindApi(api.Admins.UserDisableHandler, config))
+				adminRoute.POST("/users/role/update.json", bindApi(api.Admins.UserRoleUpdateHandler, config))
+				adminRoute.POST("/users/feature_restrictions/set.json", bindApi(api.Admins.UserFeatureRestrictionSetHandler, config))
+				adminRoute.POST("/users/feature_restrictions/add.json", bindApi(api.Admins.UserFeatureRestrictionAddHandler, config))
+				adminRoute.POST("/users/feature_restrictions/remove.json", bindApi(api.Admins.UserFeatureRestrictionRemoveHandler, config))
+				adminRoute.POST("/users/email/set_verified.json", bindApi(api.Admins.UserEmailVerifiedSetHandler, config))
+				adminRoute.POST("/users/email/set_unverified.json", bindApi(api.Admins.UserEmailUnverifiedSetHandler, config))
+
+				if config.EnableUserVerifyEmail {
+					adminRoute.POST("/users/email/resend_verify_email.json", bindApi(api.Admins.UserVerifyEmailResendHandler, config))
+				}
+
+				adminRoute.GET("/users/tokens/list.json", bindApi(api.Admins.UserTokenListHandler, config))
+				adminRoute.POST("/users/tokens/revoke.json", bindApi(api.Admins.UserTokenRevokeHandler, config))
+				adminRoute.POST("/users/tokens/revoke_all.json", bindApi(api.Admins.UserTokenRevokeAllHandler, config))
+			}
 		}
 	}
 
@@ -594,36 +767,118 @@ func bindJSONRPCApi(fns map[string]core.JSONRPCApiHandlerFunc, skipMethods map[s
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
 
-		var jsonRPCRequest core.JSONRPCRequest
-		reqErr := c.ShouldBindBodyWithJSON(&jsonRPCRequest)
+		body, reqErr := io.ReadAll(c.Request.Body)
+
+		if reqErr != nil {
+			utils.PrintJSONRPCErrorResult(c, nil, errs.NewIncompleteOrIncorrectSubmissionError(reqErr))
+			return
+		}
+
+		body = bytes.TrimSpace(body)
+		isBatchRequest := len(body) > 0 && body[0] == '['
+		var jsonRPCRequests []*core.JSONRPCRequest
+
+		if isBatchRequest {
+			reqErr = json.Unmarshal(body, &jsonRPCRequests)
+		} else {
+			jsonRPCRequest := &core.JSONRPCRequest{}
+			reqErr = json.Unmarshal(body, jsonRPCRequest)
+			jsonRPCRequests = append(jsonRPCRequests, jsonRPCRequest)
+		}
 
 		if reqErr != nil {
 			utils.PrintJSONRPCErrorResult(c, nil, errs.NewIncompleteOrIncorrectSubmissionError(reqErr))
 			return
 		}
 
-		if skipMethods != nil {
-			httpStatusCode, exists := skipMethods[jsonRPCRequest.Method]
+		if len(jsonRPCRequests) < 1 {
+			utils.PrintJSONRPCErrorResult(c, nil, errs.ErrIncompleteOrIncorrectSubmission)
+			return
+		}
+
+		eventStreamStarted := false
+
+		if utils.IsEventStreamAccepted(c) {
+			c.SetJSONRPCNotificationSender(func(notification *core.JSONRPCNotification) {
+				if !eventStreamStarted {
+					utils.SetEventStreamHeader(c)
+					c.Status(http.StatusOK)
+					eventStreamStarted = true
+				}
+
+				utils.WriteEventStreamJsonSuccessResult(c, notification)
+			})
+		}
+
+		jsonRPCResponses := make([]*core.JSONRPCResponse, 0, len(jsonRPCRequests))
+		var lastErr *errs.Error
+		skippedHttpStatusCode := http.StatusAccepted
 
-			if exists {
-				c.AbortWithStatus(httpStatusCode)
-				return
+		for i := 0; i < len(jsonRPCRequests); i++ {
+			jsonRPCRequest := jsonRPCRequests[i]
+
+			if jsonRPCRequest == nil || jsonRPCRequest.IsResponse() {
+				continue
+			}
+
+			if skipMethods != nil {
+				httpStatusCode, exists := skipMethods[jsonRPCRequest.Method]
+
+				if exists {
+					skippedHttpStatusCode = httpStatusCode
+					continue
+				}
+			}
+
+			if jsonRPCRequest.IsNotification() {
+				continue
+			}
+
+			fn, exists := fns[jsonRPCRequest.Method]
+
+			if !exists {
+				lastErr = errs.ErrApiNotFound
+				jsonRPCResponses = append(jsonRPCResponses, utils.GetJSONRPCErrorResponse(jsonRPCRequest, lastErr))
+				continue
+			}
+
+			result, err := fn(c, jsonRPCRequest)
+
+			if err != nil {
+				lastErr = err
+				jsonRPCResponses = append(jsonRPCResponses, utils.GetJSONRPCErrorResponse(jsonRPCRequest, err))
+			} else {
+				jsonRPCResponses = append(jsonRPCResponses, core.NewJSONRPCResponse(jsonRPCRequest.ID, result))
 			}
 		}
 
-		fn, exists := fns[jsonRPCRequest.Method]
+		if eventStreamStarted {
+			if lastErr != nil {
+				c.SetResponseError(lastErr)
+			}
+
+			for i := 0; i < len(jsonRPCResponses); i++ {
+				utils.WriteEventStreamJsonSuccessResult(c, jsonRPCResponses[i])
+			}
+
+			return
+		}
 
-		if !exists {
-			utils.PrintJSONRPCErrorResult(c, &jsonRPCRequest, errs.ErrApiNotFound)
+		if len(jsonRPCResponses) < 1 {
+			c.AbortWithStatus(skippedHttpStatusCode)
 			return
 		}
 
-		result, err := fn(c, &jsonRPCRequest)
+		if isBatchRequest {
+			if lastErr != nil {
+				c.SetResponseError(lastErr)
+			}
 
-		if err != nil {
-			utils.PrintJSONRPCErrorResult(c, &jsonRPCRequest, err)
+			utils.PrintJSONRPCBatchResult(c, jsonRPCResponses)
+		} else if lastErr != nil {
+			utils.PrintJSONRPCErrorResult(c, jsonRPCRequests[0], lastErr)
 		} else {
-			utils.PrintJSONRPCSuccessResult(c, &jsonRPCRequest, result)
+			utils.PrintJSONRPCSuccessResult(c, jsonRPCRequests[0], jsonRPCResponses[0].Result)
 		}
 	}
 }
@@ -631,11 +886,14 @@ func bindJSONRPCApi(fns map[string]core.JSONRPCApiHandlerFunc, skipMethods map[s
 func bindEventStreamApi(fn core.EventStreamApiHandlerFunc, config *settings.Config) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
-		utils.SetEventStreamHeader(c)
 		err := fn(c)
 
 		if err != nil {
-			utils.WriteEventStreamJsonErrorResult(c, err)
+			if c.Writer.Written() {
+				utils.WriteEventStreamJsonErrorResult(c, err)
+			} else {
+				utils.PrintJsonErrorResult(c, err)
+			}
 		}
 	}
 }
@@ -653,6 +911,19 @@ func bindCachedJs(fn core.DataHandlerFunc, config *settings.Config, store persis
 	})
 }
 
+func bindXml(fn core.DataHandlerFunc, config *settings.Config) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/xml; charset=utf-8", fileName, result)
+		}
+	}
+}
+
 func bindCsv(fn core.DataHandlerFunc, config *settings.Config) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
@@ -679,6 +950,71 @@ func bindTsv(fn core.DataHandlerFunc, config *settings.Config) gin.HandlerFunc {
 	}
 }
 
+func bindOfx(fn core.DataHandlerFunc, config *settings.Config) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/x-ofx; charset=utf-8", fileName, result)
+		}
+	}
+}
+
+func bindQif(fn core.DataHandlerFunc, config *settings.Config) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/qif; charset=utf-8", fileName, result)
+		}
+	}
+}
+
+func bindZip(fn core.DataHandlerFunc, config *settings.Config) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/zip", fileName, result)
+		}
+	}
+}
+
+func bindGnuCash(fn core.DataHandlerFunc, config *settings.Config) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/gzip", fileName, result)
+		}
+	}
+}
+
+func bindBeancount(fn core.DataHandlerFunc, config *settings.Config) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "text/plain; charset=utf-8", fileName, result)
+		}
+	}
+}
+
 func bindImage(fn core.ImageHandlerFunc, config *settings.Config) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx, config.TrustedProxyIPs)
@@ -8,6 +8,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mcp"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
@@ -319,6 +320,7 @@ func (a *AccountsApi) AccountCreateHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.notifyMCPResourcesListChanged(uid)
 	log.Infof(c, "[accounts.AccountCreateHandler] user \"uid:%d\" has created a new account \"id:%d\" successfully", uid, mainAccount.AccountId)
 
 	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_ACCOUNT, uid, accountCreateReq.ClientSessionId, utils.Int64ToString(mainAccount.AccountId))
@@ -607,6 +609,7 @@ func (a *AccountsApi) AccountModifyHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.notifyMCPResourcesListChanged(uid)
 	log.Infof(c, "[accounts.AccountModifyHandler] user \"uid:%d\" has updated account \"id:%d\" successfully", uid, accountModifyReq.Id)
 
 	if len(toAddAccounts) > 0 {
@@ -756,6 +759,7 @@ func (a *AccountsApi) AccountHideHandler(c *core.WebContext) (any, *errs.Error)
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.notifyMCPResourcesListChanged(uid)
 	log.Infof(c, "[accounts.AccountHideHandler] user \"uid:%d\" has hidden account \"id:%d\"", uid, accountHideReq.Id)
 	return true, nil
 }
@@ -791,6 +795,7 @@ func (a *AccountsApi) AccountMoveHandler(c *core.WebContext) (any, *errs.Error)
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.notifyMCPResourcesListChanged(uid)
 	log.Infof(c, "[accounts.AccountMoveHandler] user \"uid:%d\" has moved accounts", uid)
 	return true, nil
 }
@@ -813,6 +818,7 @@ func (a *AccountsApi) AccountDeleteHandler(c *core.WebContext) (any, *errs.Error
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.notifyMCPResourcesListChanged(uid)
 	log.Infof(c, "[accounts.AccountDeleteHandler] user \"uid:%d\" has deleted account \"id:%d\"", uid, accountDeleteReq.Id)
 	return true, nil
 }
@@ -835,10 +841,15 @@ func (a *AccountsApi) SubAccountDeleteHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.notifyMCPResourcesListChanged(uid)
 	log.Infof(c, "[accounts.SubAccountDeleteHandler] user \"uid:%d\" has deleted sub-account \"id:%d\"", uid, accountDeleteReq.Id)
 	return true, nil
 }
 
+func (a *AccountsApi) notifyMCPResourcesListChanged(uid int64) {
+	mcp.Sessions.NotifyUser(uid, core.NewJSONRPCNotification(mcp.MCPNotificationMethodResourcesListChanged, nil))
+}
+
 func (a *AccountsApi) createNewAccountModel(uid int64, accountCreateReq *models.AccountCreateRequest, balance int64, isSubAccount bool, order int32) *models.Account {
 	accountExtend := &models.AccountExtend{}
 
@@ -901,6 +912,10 @@ func (a *AccountsApi) getToUpdateAccount(user *models.User, accountModifyReq *mo
 	newAccountExtend := &models.AccountExtend{}
 	newAccountExtend.LastReconciledTime = accountModifyReq.LastReconciledTime
 
+	if oldAccount.Extend != nil {
+		newAccountExtend.Loan = oldAccount.Extend.Loan
+	}
+
 	if !isSubAccount && accountModifyReq.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
 		newAccountExtend.CreditCardStatementDate = &accountModifyReq.CreditCardStatementDate
 	}
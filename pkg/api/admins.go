@@ -0,0 +1,576 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// AdminsApi represents administrator api
+type AdminsApi struct {
+	ApiUsingConfig
+	users           *services.UserService
+	tokens          *services.TokenService
+	pictures        *services.TransactionPictureService
+	userCustomIcons *services.UserCustomIconService
+	dataManagements *DataManagementsApi
+}
+
+// Initialize an administrator api singleton instance
+var (
+	Admins = &AdminsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		users:           services.Users,
+		tokens:          services.Tokens,
+		pictures:        services.TransactionPictures,
+		userCustomIcons: services.UserCustomIcons,
+		dataManagements: DataManagements,
+	}
+)
+
+// UserListHandler returns the users which match the keyword by page
+func (a *AdminsApi) UserListHandler(c *core.WebContext) (any, *errs.Error) {
+	var userListReq models.AdminUserListRequest
+	err := c.ShouldBindQuery(&userListReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	totalCount, err := a.users.GetUserCountByKeyword(c, userListReq.Keyword)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserListHandler] failed to get user count, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	users, err := a.users.GetUsersByKeywordByPage(c, userListReq.Keyword, userListReq.Page, userListReq.Count)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserListHandler] failed to get users, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	userResps := make([]*models.AdminUserInfoResponse, len(users))
+
+	for i := 0; i < len(users); i++ {
+		userResps[i] = users[i].ToAdminUserInfoResponse()
+	}
+
+	return &models.AdminUserInfoPageWrapperResponse{
+		Items:      userResps,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// UserGetHandler returns the info of the specified user
+func (a *AdminsApi) UserGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var userGetReq models.AdminUserGetRequest
+	err := c.ShouldBindQuery(&userGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, userGetReq.Id, false)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	return user.ToAdminUserInfoResponse(), nil
+}
+
+// UserDataStatisticsHandler returns the data statistics of the specified user
+func (a *AdminsApi) UserDataStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
+	var userGetReq models.AdminUserGetRequest
+	err := c.ShouldBindQuery(&userGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserDataStatisticsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, userGetReq.Id, false)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	dataStatisticsResp, errResp := a.dataManagements.getDataStatistics(c, user.Uid)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	adminDataStatisticsResp := &models.AdminUserDataStatisticsResponse{
+		DataStatisticsResponse: dataStatisticsResp,
+	}
+
+	if a.CurrentConfig().EnableTransactionPictures {
+		adminDataStatisticsResp.TotalTransactionPictureSize, err = a.pictures.GetTotalTransactionPicturesSizeByUid(c, user.Uid)
+
+		if err != nil {
+			log.Errorf(c, "[admins.UserDataStatisticsHandler] failed to get total transaction picture size for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.ErrOperationFailed
+		}
+	}
+
+	if a.CurrentConfig().EnableUserCustomIcon {
+		adminDataStatisticsResp.TotalCustomIconSize, err = a.userCustomIcons.GetTotalCustomIconsSizeByUid(c, user.Uid)
+
+		if err != nil {
+			log.Errorf(c, "[admins.UserDataStatisticsHandler] failed to get total custom icon size for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.ErrOperationFailed
+		}
+	}
+
+	if a.CurrentConfig().AvatarProvider == core.USER_AVATAR_PROVIDER_INTERNAL {
+		adminDataStatisticsResp.AvatarSize, err = a.users.GetUserAvatarSize(c, user)
+
+		if err != nil {
+			log.Errorf(c, "[admins.UserDataStatisticsHandler] failed to get avatar size for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.ErrOperationFailed
+		}
+	}
+
+	return adminDataStatisticsResp, nil
+}
+
+// UserEnableHandler sets the specified user enabled
+func (a *AdminsApi) UserEnableHandler(c *core.WebContext) (any, *errs.Error) {
+	var userReq models.AdminUserIdRequest
+	err := c.ShouldBindJSON(&userReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserEnableHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, userReq.Id, true)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	err = a.users.EnableUser(c, user.Username)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserEnableHandler] failed to set user \"uid:%d\" enabled, because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserEnableHandler] administrator \"uid:%d\" has set user \"uid:%d\" enabled", c.GetCurrentOperatorUid(), user.Uid)
+	return true, nil
+}
+
+// UserDisableHandler sets the specified user disabled
+func (a *AdminsApi) UserDisableHandler(c *core.WebContext) (any, *errs.Error) {
+	var userReq models.AdminUserIdRequest
+	err := c.ShouldBindJSON(&userReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserDisableHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, userReq.Id, true)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	err = a.users.DisableUser(c, user.Username)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserDisableHandler] failed to set user \"uid:%d\" disabled, because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserDisableHandler] administrator \"uid:%d\" has set user \"uid:%d\" disabled", c.GetCurrentOperatorUid(), user.Uid)
+	return true, nil
+}
+
+// UserRoleUpdateHandler updates the role of the specified user
+func (a *AdminsApi) UserRoleUpdateHandler(c *core.WebContext) (any, *errs.Error) {
+	var userRoleUpdateReq models.AdminUserRoleUpdateRequest
+	err := c.ShouldBindJSON(&userRoleUpdateReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserRoleUpdateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !userRoleUpdateReq.Role.IsValid() {
+		return nil, errs.ErrUserRoleInvalid
+	}
+
+	user, errResp := a.getUser(c, userRoleUpdateReq.Id, true)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	err = a.users.UpdateUserRole(c, user.Username, userRoleUpdateReq.Role)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserRoleUpdateHandler] failed to update role of user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserRoleUpdateHandler] administrator \"uid:%d\" has set role of user \"uid:%d\" to \"%s\"", c.GetCurrentOperatorUid(), user.Uid, userRoleUpdateReq.Role)
+	return true, nil
+}
+
+// UserFeatureRestrictionSetHandler replaces the feature restrictions of the specified user
+func (a *AdminsApi) UserFeatureRestrictionSetHandler(c *core.WebContext) (any, *errs.Error) {
+	var featureRestrictionReq models.AdminUserFeatureRestrictionUpdateRequest
+	err := c.ShouldBindJSON(&featureRestrictionReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserFeatureRestrictionSetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, featureRestrictionReq.Id, true)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	featureRestrictions := core.NewUserFeatureRestrictions(featureRestrictionReq.FeatureRestrictions)
+	err = a.users.UpdateUserFeatureRestriction(c, user.Username, featureRestrictions)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserFeatureRestrictionSetHandler] failed to set feature restrictions of user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserFeatureRestrictionSetHandler] administrator \"uid:%d\" has set feature restrictions of user \"uid:%d\" to \"%s\"", c.GetCurrentOperatorUid(), user.Uid, featureRestrictions)
+	return true, nil
+}
+
+// UserFeatureRestrictionAddHandler adds feature restrictions to the specified user
+func (a *AdminsApi) UserFeatureRestrictionAddHandler(c *core.WebContext) (any, *errs.Error) {
+	var featureRestrictionReq models.AdminUserFeatureRestrictionUpdateRequest
+	err := c.ShouldBindJSON(&featureRestrictionReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserFeatureRestrictionAddHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	featureRestrictions := core.NewUserFeatureRestrictions(featureRestrictionReq.FeatureRestrictions)
+
+	if featureRestrictions == 0 {
+		return nil, errs.ErrUserFeatureRestrictionTypeEmpty
+	}
+
+	user, errResp := a.getUser(c, featureRestrictionReq.Id, true)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	err = a.users.AddUserFeatureRestriction(c, user.Username, featureRestrictions)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserFeatureRestrictionAddHandler] failed to add feature restrictions to user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserFeatureRestrictionAddHandler] administrator \"uid:%d\" has added feature restrictions \"%s\" to user \"uid:%d\"", c.GetCurrentOperatorUid(), featureRestrictions, user.Uid)
+	return true, nil
+}
+
+// UserFeatureRestrictionRemoveHandler removes feature restrictions from the specified user
+func (a *AdminsApi) UserFeatureRestrictionRemoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var featureRestrictionReq models.AdminUserFeatureRestrictionUpdateRequest
+	err := c.ShouldBindJSON(&featureRestrictionReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserFeatureRestrictionRemoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	featureRestrictions := core.NewUserFeatureRestrictions(featureRestrictionReq.FeatureRestrictions)
+
+	if featureRestrictions == 0 {
+		return nil, errs.ErrUserFeatureRestrictionTypeEmpty
+	}
+
+	user, errResp := a.getUser(c, featureRestrictionReq.Id, true)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	err = a.users.RemoveUserFeatureRestriction(c, user.Username, featureRestrictions)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserFeatureRestrictionRemoveHandler] failed to remove feature restrictions from user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserFeatureRestrictionRemoveHandler] administrator \"uid:%d\" has removed feature restrictions \"%s\" from user \"uid:%d\"", c.GetCurrentOperatorUid(), featureRestrictions, user.Uid)
+	return true, nil
+}
+
+// UserEmailVerifiedSetHandler sets the email address of the specified user verified
+func (a *AdminsApi) UserEmailVerifiedSetHandler(c *core.WebContext) (any, *errs.Error) {
+	var userReq models.AdminUserIdRequest
+	err := c.ShouldBindJSON(&userReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserEmailVerifiedSetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, userReq.Id, false)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	err = a.users.SetUserEmailVerified(c, user.Username)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserEmailVerifiedSetHandler] failed to set email address of user \"uid:%d\" verified, because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserEmailVerifiedSetHandler] administrator \"uid:%d\" has set email address of user \"uid:%d\" verified", c.GetCurrentOperatorUid(), user.Uid)
+	return true, nil
+}
+
+// UserEmailUnverifiedSetHandler sets the email address of the specified user unverified
+func (a *AdminsApi) UserEmailUnverifiedSetHandler(c *core.WebContext) (any, *errs.Error) {
+	var userReq models.AdminUserIdRequest
+	err := c.ShouldBindJSON(&userReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserEmailUnverifiedSetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, userReq.Id, false)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	err = a.users.SetUserEmailUnverified(c, user.Username)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserEmailUnverifiedSetHandler] failed to set email address of user \"uid:%d\" unverified, because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserEmailUnverifiedSetHandler] administrator \"uid:%d\" has set email address of user \"uid:%d\" unverified", c.GetCurrentOperatorUid(), user.Uid)
+	return true, nil
+}
+
+// UserVerifyEmailResendHandler sends a new email with account activation link to the specified user
+func (a *AdminsApi) UserVerifyEmailResendHandler(c *core.WebContext) (any, *errs.Error) {
+	if !a.CurrentConfig().EnableUserVerifyEmail {
+		return nil, errs.ErrEmailValidationNotAllowed
+	}
+
+	var userReq models.AdminUserIdRequest
+	err := c.ShouldBindJSON(&userReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserVerifyEmailResendHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, userReq.Id, false)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	if user.EmailVerified {
+		log.Warnf(c, "[admins.UserVerifyEmailResendHandler] user \"uid:%d\" email has been verified", user.Uid)
+		return nil, errs.ErrEmailIsVerified
+	}
+
+	if !a.CurrentConfig().EnableSMTP {
+		return nil, errs.ErrSMTPServerNotEnabled
+	}
+
+	token, _, err := a.tokens.CreateEmailVerifyTokenWithoutUserAgent(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserVerifyEmailResendHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	go func() {
+		err = a.users.SendVerifyEmail(user, token, c.GetClientLocale())
+
+		if err != nil {
+			log.Warnf(c, "[admins.UserVerifyEmailResendHandler] cannot send email to \"%s\", because %s", user.Email, err.Error())
+		}
+	}()
+
+	return true, nil
+}
+
+// UserTokenListHandler returns available token list of the specified user
+func (a *AdminsApi) UserTokenListHandler(c *core.WebContext) (any, *errs.Error) {
+	var userGetReq models.AdminUserGetRequest
+	err := c.ShouldBindQuery(&userGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserTokenListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, userGetReq.Id, false)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	tokens, err := a.tokens.GetAllUnexpiredNormalAndMCPTokensByUid(c, user.Uid)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserTokenListHandler] failed to get all tokens for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tokenResps := make(models.TokenInfoResponseSlice, len(tokens))
+	claims := c.GetTokenClaims()
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		tokenResp := &models.TokenInfoResponse{
+			TokenId:   a.tokens.GenerateTokenId(token),
+			TokenType: token.TokenType,
+			UserAgent: token.UserAgent,
+			LastSeen:  token.LastSeenUnixTime,
+		}
+
+		if token.Uid == claims.Uid && utils.Int64ToString(token.UserTokenId) == claims.UserTokenId && token.CreatedUnixTime == claims.IssuedAt {
+			tokenResp.IsCurrent = true
+		}
+
+		if token.TokenType == core.USER_TOKEN_TYPE_API && token.UserAgent != core.TokenUserAgentCreatedViaCli {
+			tokenResp.UserAgent = core.TokenUserAgentForAPI
+		} else if token.TokenType == core.USER_TOKEN_TYPE_MCP && token.UserAgent != core.TokenUserAgentCreatedViaCli {
+			tokenResp.UserAgent = core.TokenUserAgentForMCP
+		}
+
+		tokenResps[i] = tokenResp
+	}
+
+	sort.Sort(tokenResps)
+
+	return tokenResps, nil
+}
+
+// UserTokenRevokeHandler revokes the specified token of the specified user
+func (a *AdminsApi) UserTokenRevokeHandler(c *core.WebContext) (any, *errs.Error) {
+	var tokenRevokeReq models.AdminUserTokenRevokeRequest
+	err := c.ShouldBindJSON(&tokenRevokeReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserTokenRevokeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, tokenRevokeReq.Id, false)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	tokenRecord, err := a.tokens.ParseFromTokenId(tokenRevokeReq.TokenId)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[admins.UserTokenRevokeHandler] failed to parse token \"id:%s\", because %s", tokenRevokeReq.TokenId, err.Error())
+		}
+
+		return nil, errs.Or(err, errs.ErrInvalidTokenId)
+	}
+
+	if tokenRecord.Uid != user.Uid {
+		log.Warnf(c, "[admins.UserTokenRevokeHandler] token \"id:%s\" is not owned by user \"uid:%d\"", tokenRevokeReq.TokenId, user.Uid)
+		return nil, errs.ErrInvalidTokenId
+	}
+
+	err = a.tokens.DeleteToken(c, tokenRecord)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserTokenRevokeHandler] failed to revoke token \"id:%s\" for user \"uid:%d\", because %s", tokenRevokeReq.TokenId, user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserTokenRevokeHandler] administrator \"uid:%d\" has revoked token \"id:%s\" of user \"uid:%d\"", c.GetCurrentOperatorUid(), tokenRevokeReq.TokenId, user.Uid)
+	return true, nil
+}
+
+// UserTokenRevokeAllHandler revokes all tokens of the specified user
+func (a *AdminsApi) UserTokenRevokeAllHandler(c *core.WebContext) (any, *errs.Error) {
+	var userReq models.AdminUserIdRequest
+	err := c.ShouldBindJSON(&userReq)
+
+	if err != nil {
+		log.Warnf(c, "[admins.UserTokenRevokeAllHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	user, errResp := a.getUser(c, userReq.Id, true)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	now := time.Now().Unix()
+	err = a.tokens.DeleteTokensBeforeTime(c, user.Uid, now)
+
+	if err != nil {
+		log.Errorf(c, "[admins.UserTokenRevokeAllHandler] failed to revoke all tokens for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[admins.UserTokenRevokeAllHandler] administrator \"uid:%d\" has revoked all tokens of user \"uid:%d\"", c.GetCurrentOperatorUid(), user.Uid)
+	return true, nil
+}
+
+func (a *AdminsApi) getUser(c *core.WebContext, uid int64, notAllowCurrentUser bool) (*models.User, *errs.Error) {
+	if notAllowCurrentUser && uid == c.GetCurrentOperatorUid() {
+		log.Warnf(c, "[admins.getUser] administrator \"uid:%d\" cannot perform this operation on itself", uid)
+		return nil, errs.ErrCannotOperateOnCurrentUser
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[admins.getUser] failed to get user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.Or(err, errs.ErrUserNotFound)
+	}
+
+	if user.IsLedgerBook() {
+		log.Warnf(c, "[admins.getUser] user \"uid:%d\" is the book of shared ledger and cannot be operated by administrator", uid)
+		return nil, errs.ErrUserNotFound
+	}
+
+	return user, nil
+}
@@ -1,12 +1,15 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/pquerna/otp/totp"
 
+	"github.com/mayswind/ezbookkeeping/pkg/auth/ldap"
 	"github.com/mayswind/ezbookkeeping/pkg/avatars"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
@@ -28,6 +31,7 @@ type AuthorizationsApi struct {
 	tokens                  *services.TokenService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
 	userExternalAuths       *services.UserExternalAuthService
+	userPasskeys            *services.UserPasskeyService
 }
 
 // Initialize a authorization api singleton instance
@@ -55,6 +59,7 @@ var (
 		tokens:                  services.Tokens,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
 		userExternalAuths:       services.UserExternalAuths,
+		userPasskeys:            services.UserPasskeys,
 	}
 )
 
@@ -122,15 +127,11 @@ func (a *AuthorizationsApi) AuthorizeHandler(c *core.WebContext) (any, *errs.Err
 		log.Warnf(c, "[authorizations.AuthorizeHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
 	}
 
-	twoFactorEnable := a.tokens.CurrentConfig().EnableTwoFactor
+	twoFactorEnable, err := a.isTwoFactorAuthorizationRequired(c, user)
 
-	if twoFactorEnable {
-		twoFactorEnable, err = a.twoFactorAuthorizations.ExistsTwoFactorSetting(c, user.Uid)
-
-		if err != nil {
-			log.Errorf(c, "[authorizations.AuthorizeHandler] failed to check two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
-			return nil, errs.Or(err, errs.ErrSystemError)
-		}
+	if err != nil {
+		log.Errorf(c, "[authorizations.AuthorizeHandler] failed to check two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrSystemError)
 	}
 
 	var token string
@@ -171,7 +172,7 @@ func (a *AuthorizationsApi) AuthorizeHandler(c *core.WebContext) (any, *errs.Err
 
 // TwoFactorAuthorizeHandler verifies and authorizes current 2fa login by passcode
 func (a *AuthorizationsApi) TwoFactorAuthorizeHandler(c *core.WebContext) (any, *errs.Error) {
-	if !a.CurrentConfig().EnableInternalAuth {
+	if !a.CurrentConfig().EnableInternalAuth && !a.CurrentConfig().EnableLDAPLogin {
 		return nil, errs.ErrCannotLoginByPassword
 	}
 
@@ -271,7 +272,7 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeHandler(c *core.WebContext) (any,
 
 // TwoFactorAuthorizeByRecoveryCodeHandler verifies and authorizes current 2fa login by recovery code
 func (a *AuthorizationsApi) TwoFactorAuthorizeByRecoveryCodeHandler(c *core.WebContext) (any, *errs.Error) {
-	if !a.CurrentConfig().EnableInternalAuth {
+	if !a.CurrentConfig().EnableInternalAuth && !a.CurrentConfig().EnableLDAPLogin {
 		return nil, errs.ErrCannotLoginByPassword
 	}
 
@@ -368,9 +369,510 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeByRecoveryCodeHandler(c *core.WebC
 	return authResp, nil
 }
 
-// OAuth2CallbackAuthorizeHandler verifies and authorizes current OAuth 2.0 callback login
+// PasskeyAuthorizeBeginHandler returns the credential request options for passwordless login by passkey
+func (a *AuthorizationsApi) PasskeyAuthorizeBeginHandler(c *core.WebContext) (any, *errs.Error) {
+	if !a.CurrentConfig().EnableInternalAuth {
+		return nil, errs.ErrCannotLoginByPassword
+	}
+
+	err := a.CheckFailureCount(c, 0)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeBeginHandler] cannot begin passkey login, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrFailureCountLimitReached)
+	}
+
+	assertion, session, err := a.userPasskeys.BeginDiscoverableLogin(c)
+
+	if err != nil {
+		log.Errorf(c, "[authorizations.PasskeyAuthorizeBeginHandler] failed to begin passkey login, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.SetPasskeyCeremonySession(c, 0, session)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return assertion, nil
+}
+
+// PasskeyAuthorizeHandler verifies and authorizes current passwordless login by passkey
+func (a *AuthorizationsApi) PasskeyAuthorizeHandler(c *core.WebContext) (any, *errs.Error) {
+	if !a.CurrentConfig().EnableInternalAuth {
+		return nil, errs.ErrCannotLoginByPassword
+	}
+
+	var credential models.UserPasskeyLoginRequest
+	err := c.ShouldBindJSON(&credential)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.ErrPasskeyCredentialInvalid
+	}
+
+	err = a.CheckFailureCount(c, 0)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] cannot login by passkey, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrFailureCountLimitReached)
+	}
+
+	credentialAssertion, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(credential.Credential))
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] failed to parse passkey credential, because %s", err.Error())
+		return nil, errs.ErrPasskeyCredentialInvalid
+	}
+
+	session, err := a.GetAndRemovePasskeyCeremonySession(c, 0, credentialAssertion.Response.CollectedClientData.Challenge)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] failed to get passkey login session, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrPasskeyCeremonyExpired)
+	}
+
+	passkeyUser, passkey, updatedCredential, err := a.userPasskeys.FinishDiscoverableLogin(c, session, credentialAssertion, func(uid int64) (*models.PasskeyUser, error) {
+		return a.getPasskeyUser(c, uid)
+	})
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] failed to verify passkey credential, because %s", err.Error())
+
+		failureCheckErr := a.CheckAndIncreaseFailureCount(c, 0)
+
+		if failureCheckErr != nil {
+			log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] cannot login by passkey, because %s", failureCheckErr.Error())
+			return nil, errs.Or(failureCheckErr, errs.ErrFailureCountLimitReached)
+		}
+
+		return nil, errs.Or(err, errs.ErrPasskeyVerificationFailed)
+	}
+
+	user := passkeyUser.User
+
+	if user.Disabled {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] login failed for user \"uid:%d\", because user is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_PASSKEY_LOGIN) {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] login failed for user \"uid:%d\", because user is not permitted to login by passkey", user.Uid)
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	if a.CurrentConfig().EnableUserForceVerifyEmail && !user.EmailVerified {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] login failed for user \"uid:%d\", because user has not verified email", user.Uid)
+		return nil, errs.ErrEmailIsNotVerified
+	}
+
+	err = a.userPasskeys.UpdatePasskeyAfterLogin(c, passkey, updatedCredential)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] failed to update passkey \"id:%d\" for user \"uid:%d\", because %s", passkey.PasskeyId, user.Uid, err.Error())
+	}
+
+	err = a.users.UpdateUserLastLoginTime(c, user.Uid)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	token, claims, err := a.tokens.CreateToken(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[authorizations.PasskeyAuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	c.SetTextualToken(token)
+	c.SetTokenClaims(claims)
+	c.SetTokenContext("")
+
+	userApplicationCloudSettings, err := a.userAppCloudSettings.GetUserApplicationCloudSettingsByUid(c, user.Uid)
+	var applicationCloudSettingSlice *models.ApplicationCloudSettingSlice = nil
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.PasskeyAuthorizeHandler] failed to get latest user application cloud settings for user \"uid:%d\", because %s", user.Uid, err.Error())
+	} else if userApplicationCloudSettings != nil && len(userApplicationCloudSettings.Settings) > 0 {
+		applicationCloudSettingSlice = &userApplicationCloudSettings.Settings
+	}
+
+	log.Infof(c, "[authorizations.PasskeyAuthorizeHandler] user \"uid:%d\" has logged in via passkey \"id:%d\", token will be expired at %d", user.Uid, passkey.PasskeyId, claims.ExpiresAt)
+
+	authResp := a.getAuthResponse(c, token, false, user, applicationCloudSettingSlice)
+	return authResp, nil
+}
+
+// TwoFactorPasskeyAuthorizeBeginHandler returns the credential request options for current 2fa login by passkey
+func (a *AuthorizationsApi) TwoFactorPasskeyAuthorizeBeginHandler(c *core.WebContext) (any, *errs.Error) {
+	if !a.CurrentConfig().EnableInternalAuth && !a.CurrentConfig().EnableLDAPLogin {
+		return nil, errs.ErrCannotLoginByPassword
+	}
+
+	uid := c.GetCurrentUid()
+	err := a.CheckFailureCount(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeBeginHandler] cannot auth for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrFailureCountLimitReached)
+	}
+
+	passkeyUser, err := a.getPasskeyUser(c, uid)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if passkeyUser.User.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_PASSKEY_LOGIN) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	if len(passkeyUser.Passkeys) < 1 {
+		return nil, errs.ErrPasskeyNotFound
+	}
+
+	assertion, session, err := a.userPasskeys.BeginLogin(c, passkeyUser)
+
+	if err != nil {
+		log.Errorf(c, "[authorizations.TwoFactorPasskeyAuthorizeBeginHandler] failed to begin passkey login for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.SetPasskeyCeremonySession(c, uid, session)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return assertion, nil
+}
+
+// TwoFactorPasskeyAuthorizeHandler verifies and authorizes current 2fa login by passkey
+func (a *AuthorizationsApi) TwoFactorPasskeyAuthorizeHandler(c *core.WebContext) (any, *errs.Error) {
+	if !a.CurrentConfig().EnableInternalAuth && !a.CurrentConfig().EnableLDAPLogin {
+		return nil, errs.ErrCannotLoginByPassword
+	}
+
+	var credential models.UserPasskeyLoginRequest
+	err := c.ShouldBindJSON(&credential)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.ErrPasskeyCredentialInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.CheckFailureCount(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] cannot auth for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrFailureCountLimitReached)
+	}
+
+	credentialAssertion, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(credential.Credential))
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] failed to parse passkey credential for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrPasskeyCredentialInvalid
+	}
+
+	session, err := a.GetAndRemovePasskeyCeremonySession(c, uid, credentialAssertion.Response.CollectedClientData.Challenge)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] failed to get passkey login session for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrPasskeyCeremonyExpired)
+	}
+
+	passkeyUser, err := a.getPasskeyUser(c, uid)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	user := passkeyUser.User
+
+	if user.Disabled {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] user \"uid:%d\" is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_PASSKEY_LOGIN) {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] user \"uid:%d\" is not permitted to login by passkey", user.Uid)
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	if a.CurrentConfig().EnableUserForceVerifyEmail && !user.EmailVerified {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] user \"uid:%d\" has not verified email", user.Uid)
+		return nil, errs.ErrEmailIsNotVerified
+	}
+
+	passkey, updatedCredential, err := a.userPasskeys.FinishLogin(c, passkeyUser, session, credentialAssertion)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] failed to verify passkey credential for user \"uid:%d\", because %s", uid, err.Error())
+
+		failureCheckErr := a.CheckAndIncreaseFailureCount(c, uid)
+
+		if failureCheckErr != nil {
+			log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] cannot auth for user \"uid:%d\", because %s", uid, failureCheckErr.Error())
+			return nil, errs.Or(failureCheckErr, errs.ErrFailureCountLimitReached)
+		}
+
+		return nil, errs.Or(err, errs.ErrPasskeyVerificationFailed)
+	}
+
+	err = a.userPasskeys.UpdatePasskeyAfterLogin(c, passkey, updatedCredential)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] failed to update passkey \"id:%d\" for user \"uid:%d\", because %s", passkey.PasskeyId, user.Uid, err.Error())
+	}
+
+	oldTokenClaims := c.GetTokenClaims()
+	err = a.tokens.DeleteTokenByClaims(c, oldTokenClaims)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] failed to revoke temporary token \"utid:%s\" for user \"uid:%d\", because %s", oldTokenClaims.UserTokenId, user.Uid, err.Error())
+	}
+
+	token, claims, err := a.tokens.CreateToken(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	c.SetTextualToken(token)
+	c.SetTokenClaims(claims)
+	c.SetTokenContext("")
+
+	userApplicationCloudSettings, err := a.userAppCloudSettings.GetUserApplicationCloudSettingsByUid(c, user.Uid)
+	var applicationCloudSettingSlice *models.ApplicationCloudSettingSlice = nil
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] failed to get latest user application cloud settings for user \"uid:%d\", because %s", user.Uid, err.Error())
+	} else if userApplicationCloudSettings != nil && len(userApplicationCloudSettings.Settings) > 0 {
+		applicationCloudSettingSlice = &userApplicationCloudSettings.Settings
+	}
+
+	log.Infof(c, "[authorizations.TwoFactorPasskeyAuthorizeHandler] user \"uid:%d\" has authorized two-factor via passkey \"id:%d\", token will be expired at %d", user.Uid, passkey.PasskeyId, claims.ExpiresAt)
+
+	authResp := a.getAuthResponse(c, token, false, user, applicationCloudSettingSlice)
+	return authResp, nil
+}
+
+// LDAPAuthorizeHandler verifies and authorizes current login request by ldap server
+func (a *AuthorizationsApi) LDAPAuthorizeHandler(c *core.WebContext) (any, *errs.Error) {
+	if !a.CurrentConfig().EnableLDAPLogin {
+		return nil, errs.ErrLDAPNotEnabled
+	}
+
+	var credential models.LDAPLoginRequest
+	err := c.ShouldBindJSON(&credential)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.ErrLoginNameOrPasswordInvalid
+	}
+
+	err = a.CheckFailureCount(c, 0)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] cannot login for ldap user \"%s\", because %s", credential.LoginName, err.Error())
+		return nil, errs.Or(err, errs.ErrFailureCountLimitReached)
+	}
+
+	ldapUserInfo, err := ldap.AuthenticateUser(c, credential.LoginName, credential.Password)
+
+	if errs.IsCustomError(err) && errors.Is(err, errs.ErrLoginNameOrPasswordWrong) {
+		failureCheckErr := a.CheckAndIncreaseFailureCount(c, 0)
+
+		if failureCheckErr != nil {
+			log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] cannot login for ldap user \"%s\", because %s", credential.LoginName, failureCheckErr.Error())
+			return nil, errs.Or(failureCheckErr, errs.ErrFailureCountLimitReached)
+		}
+	}
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] login failed for ldap user \"%s\", because %s", credential.LoginName, err.Error())
+		return nil, errs.Or(err, errs.ErrLoginNameOrPasswordWrong)
+	}
+
+	log.Infof(c, "[authorizations.LDAPAuthorizeHandler] ldap user info, dn: %s, userName: %s, email: %s", ldapUserInfo.DN, ldapUserInfo.UserName, ldapUserInfo.Email)
+
+	if ldapUserInfo.UserName == "" {
+		log.Errorf(c, "[authorizations.LDAPAuthorizeHandler] invalid ldap user info, userName is empty")
+		return nil, errs.ErrLDAPUserNameEmpty
+	}
+
+	if ldapUserInfo.Email == "" {
+		log.Errorf(c, "[authorizations.LDAPAuthorizeHandler] invalid ldap user info, email is empty")
+		return nil, errs.ErrLDAPEmailEmpty
+	}
+
+	userExternalAuth, err := a.userExternalAuths.GetUserExternalAuthByExternalUserName(c, ldapUserInfo.UserName, core.USER_EXTERNAL_AUTH_TYPE_LDAP)
+
+	if err != nil && !errors.Is(err, errs.ErrUserExternalAuthNotFound) {
+		log.Errorf(c, "[authorizations.LDAPAuthorizeHandler] failed to get user external auth, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var user *models.User
+
+	if err == nil { // user already bound to ldap user
+		user, err = a.users.GetUserById(c, userExternalAuth.Uid)
+
+		if err != nil {
+			log.Errorf(c, "[authorizations.LDAPAuthorizeHandler] failed to get user by id %d, because %s", userExternalAuth.Uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	} else { // errors.Is(err, errs.ErrUserExternalAuthNotFound) // user not bound to ldap user, try to register new user
+		user, err = a.users.GetUserByUsername(c, ldapUserInfo.UserName)
+
+		if err != nil && !errors.Is(err, errs.ErrUserNotFound) {
+			log.Errorf(c, "[authorizations.LDAPAuthorizeHandler] failed to get user, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		if user != nil {
+			log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] user \"%s\" already exists and is not bound to ldap user \"%s\"", user.Username, ldapUserInfo.DN)
+			return nil, errs.ErrLDAPUserAlreadyExistsButNotBound
+		}
+
+		if !a.CurrentConfig().EnableUserRegister || !a.CurrentConfig().LDAPAutoRegister {
+			return nil, errs.ErrLDAPAutoRegistrationNotEnabled
+		}
+
+		userName := ldapUserInfo.UserName
+		email := ldapUserInfo.Email
+		nickName := ldapUserInfo.NickName
+
+		if nickName == "" {
+			nickName = userName
+		}
+
+		if !utils.IsValidUsername(userName) {
+			return nil, errs.ErrUserNameIsInvalid
+		}
+
+		if !utils.IsValidEmail(email) {
+			return nil, errs.ErrEmailIsInvalid
+		}
+
+		if !utils.IsValidNickName(nickName) {
+			return nil, errs.ErrNickNameIsInvalid
+		}
+
+		user = &models.User{
+			Username:             userName,
+			Email:                email,
+			Nickname:             nickName,
+			DefaultCurrency:      "USD",
+			FiscalYearStart:      core.FISCAL_YEAR_START_DEFAULT,
+			TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_ALL,
+			FeatureRestriction:   a.CurrentConfig().DefaultFeatureRestrictions,
+		}
+
+		err = a.users.CreateUser(c, user, true)
+
+		if err != nil {
+			log.Errorf(c, "[authorizations.LDAPAuthorizeHandler] failed to create user \"%s\", because %s", user.Username, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		log.Infof(c, "[authorizations.LDAPAuthorizeHandler] user \"%s\" has registered successfully, uid is %d", user.Username, user.Uid)
+
+		userExternalAuth = &models.UserExternalAuth{
+			Uid:              user.Uid,
+			ExternalAuthType: core.USER_EXTERNAL_AUTH_TYPE_LDAP,
+			ExternalUsername: ldapUserInfo.UserName,
+			ExternalEmail:    ldapUserInfo.Email,
+		}
+
+		err = a.userExternalAuths.CreateUserExternalAuth(c, userExternalAuth)
+
+		if err != nil {
+			log.Errorf(c, "[authorizations.LDAPAuthorizeHandler] failed to create user external auth for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		log.Infof(c, "[authorizations.LDAPAuthorizeHandler] user external auth has been created for user \"uid:%d\"", user.Uid)
+	}
+
+	if user.Disabled {
+		log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] login failed for user \"%s\", because user is disabled", user.Username)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if a.CurrentConfig().EnableUserForceVerifyEmail && !user.EmailVerified {
+		hasValidEmailVerifyToken, err := a.tokens.ExistsValidTokenByType(c, user.Uid, core.USER_TOKEN_TYPE_EMAIL_VERIFY)
+
+		if err != nil {
+			log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] failed check whether user \"uid:%d\" has valid verify email token, because %s", user.Uid, err.Error())
+			hasValidEmailVerifyToken = false
+		}
+
+		log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] login failed for user \"%s\", because user has not verified email", user.Username)
+
+		return nil, errs.NewErrorWithContext(errs.ErrEmailIsNotVerified, map[string]any{
+			"email":                    user.Email,
+			"hasValidEmailVerifyToken": hasValidEmailVerifyToken,
+		})
+	}
+
+	err = a.users.UpdateUserLastLoginTime(c, user.Uid)
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	twoFactorEnable, err := a.isTwoFactorAuthorizationRequired(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[authorizations.LDAPAuthorizeHandler] failed to check two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrSystemError)
+	}
+
+	var token string
+	var claims *core.UserTokenClaims
+
+	if twoFactorEnable {
+		token, claims, err = a.tokens.CreateRequire2FAToken(c, user)
+	} else {
+		token, claims, err = a.tokens.CreateToken(c, user)
+	}
+
+	if err != nil {
+		log.Errorf(c, "[authorizations.LDAPAuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	if !twoFactorEnable {
+		c.SetTextualToken(token)
+	}
+
+	c.SetTokenClaims(claims)
+	c.SetTokenContext("")
+
+	userApplicationCloudSettings, err := a.userAppCloudSettings.GetUserApplicationCloudSettingsByUid(c, user.Uid)
+	var applicationCloudSettingSlice *models.ApplicationCloudSettingSlice = nil
+
+	if err != nil {
+		log.Warnf(c, "[authorizations.LDAPAuthorizeHandler] failed to get latest user application cloud settings for user \"uid:%d\", because %s", user.Uid, err.Error())
+	} else if userApplicationCloudSettings != nil && len(userApplicationCloudSettings.Settings) > 0 {
+		applicationCloudSettingSlice = &userApplicationCloudSettings.Settings
+	}
+
+	log.Infof(c, "[authorizations.LDAPAuthorizeHandler] user \"uid:%d\" has logged in via ldap, token type is %d, token will be expired at %d", user.Uid, claims.Type, claims.ExpiresAt)
+
+	authResp := a.getAuthResponse(c, token, twoFactorEnable, user, applicationCloudSettingSlice)
+	return authResp, nil
+}
+
+// OAuth2CallbackAuthorizeHandler verifies and authorizes current OAuth 2.0 or SAML 2.0 callback login
 func (a *AuthorizationsApi) OAuth2CallbackAuthorizeHandler(c *core.WebContext) (any, *errs.Error) {
-	if !a.CurrentConfig().EnableOAuth2Login {
+	if !a.CurrentConfig().EnableOAuth2Login && !a.CurrentConfig().EnableSAMLLogin {
 		return nil, errs.ErrOAuth2NotEnabled
 	}
 
@@ -395,6 +897,15 @@ func (a *AuthorizationsApi) OAuth2CallbackAuthorizeHandler(c *core.WebContext) (
 		return nil, errs.ErrInvalidOAuth2Provider
 	}
 
+	if tokenContext.ExternalAuthType.GetCategory() == core.USER_EXTERNAL_AUTH_TYPE_CATEOGRY_OAUTH2 && !a.CurrentConfig().EnableOAuth2Login {
+		return nil, errs.ErrOAuth2NotEnabled
+	} else if tokenContext.ExternalAuthType.GetCategory() == core.USER_EXTERNAL_AUTH_TYPE_CATEOGRY_SAML && !a.CurrentConfig().EnableSAMLLogin {
+		return nil, errs.ErrSAMLNotEnabled
+	} else if tokenContext.ExternalAuthType.GetCategory() == core.USER_EXTERNAL_AUTH_TYPE_CATEOGRY_LDAP {
+		log.Warnf(c, "[authorizations.OAuth2CallbackAuthorizeHandler] external auth type \"%s\" does not support callback login", tokenContext.ExternalAuthType)
+		return nil, errs.ErrInvalidOAuth2Provider
+	}
+
 	uid := c.GetCurrentUid()
 	err = a.CheckFailureCount(c, uid)
 
@@ -564,6 +1075,32 @@ func (a *AuthorizationsApi) getAuthResponse(c *core.WebContext, token string, ne
 	}
 }
 
+func (a *AuthorizationsApi) isTwoFactorAuthorizationRequired(c *core.WebContext, user *models.User) (bool, error) {
+	config := a.CurrentConfig()
+
+	if !config.EnableTwoFactor {
+		return false, nil
+	}
+
+	hasTwoFactorSetting, err := a.twoFactorAuthorizations.ExistsTwoFactorSetting(c, user.Uid)
+
+	if err != nil {
+		return false, err
+	}
+
+	hasPasskey := false
+
+	if !hasTwoFactorSetting && config.EnablePasskey && !user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_PASSKEY_LOGIN) {
+		hasPasskey, err = a.userPasskeys.ExistsPasskey(c, user.Uid)
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return isTwoFactorAuthorizationRequired(config, user, hasTwoFactorSetting, hasPasskey), nil
+}
+
 func (a *AuthorizationsApi) is2FAPasscodeUsed(c *core.WebContext, uid int64, passcode string) (bool, string) {
 	passcodeHash := utils.MD5EncodeToStringWithUidAndSalt([]byte(passcode), uid, a.CurrentConfig().SecretKey)
 	found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_2FA_PASSCODE, uid, passcodeHash)
@@ -579,3 +1116,40 @@ func (a *AuthorizationsApi) is2FAPasscodeUsed(c *core.WebContext, uid int64, pas
 func (a *AuthorizationsApi) update2FAPasscodeUsed(c *core.WebContext, uid int64, passcodeHash string) {
 	a.SetSubmissionRemarkWithCustomExpiration(duplicatechecker.DUPLICATE_CHECKER_TYPE_2FA_PASSCODE, uid, passcodeHash, utils.Int64ToString(time.Now().Unix()), 30*time.Second)
 }
+
+func (a *AuthorizationsApi) getPasskeyUser(c *core.WebContext, uid int64) (*models.PasskeyUser, error) {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[authorizations.getPasskeyUser] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	passkeys, err := a.userPasskeys.GetAllPasskeysByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[authorizations.getPasskeyUser] failed to get all passkeys for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	return &models.PasskeyUser{
+		User:     user,
+		Passkeys: passkeys,
+	}, nil
+}
+
+// isTwoFactorAuthorizationRequired returns whether the user should pass two-factor authorization after login, which can be done by either totp passcode or passkey
+func isTwoFactorAuthorizationRequired(config *settings.Config, user *models.User, hasTwoFactorSetting bool, hasPasskey bool) bool {
+	if !config.EnableTwoFactor {
+		return false
+	}
+
+	if hasTwoFactorSetting {
+		return true
+	}
+
+	return hasPasskey && config.EnablePasskey && !user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_PASSKEY_LOGIN)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestIsTwoFactorAuthorizationRequired_TwoFactorDisabled(t *testing.T) {
+	config := &settings.Config{EnableTwoFactor: false, EnablePasskey: true}
+	user := &models.User{Uid: 1}
+
+	assert.False(t, isTwoFactorAuthorizationRequired(config, user, true, true))
+}
+
+func TestIsTwoFactorAuthorizationRequired_UserWithTwoFactorSetting(t *testing.T) {
+	config := &settings.Config{EnableTwoFactor: true, EnablePasskey: false}
+	user := &models.User{Uid: 1}
+
+	assert.True(t, isTwoFactorAuthorizationRequired(config, user, true, false))
+	assert.True(t, isTwoFactorAuthorizationRequired(config, user, true, true))
+}
+
+func TestIsTwoFactorAuthorizationRequired_UserWithOnlyPasskey(t *testing.T) {
+	config := &settings.Config{EnableTwoFactor: true, EnablePasskey: true}
+	user := &models.User{Uid: 1}
+
+	assert.True(t, isTwoFactorAuthorizationRequired(config, user, false, true))
+}
+
+func TestIsTwoFactorAuthorizationRequired_UserWithOnlyPasskeyAndPasskeyDisabled(t *testing.T) {
+	config := &settings.Config{EnableTwoFactor: true, EnablePasskey: false}
+	user := &models.User{Uid: 1}
+
+	assert.False(t, isTwoFactorAuthorizationRequired(config, user, false, true))
+}
+
+func TestIsTwoFactorAuthorizationRequired_UserWithOnlyPasskeyAndPasskeyLoginRestricted(t *testing.T) {
+	config := &settings.Config{EnableTwoFactor: true, EnablePasskey: true}
+	user := &models.User{Uid: 1, FeatureRestriction: core.UserFeatureRestrictions(0).Add(core.USER_FEATURE_RESTRICTION_TYPE_PASSKEY_LOGIN)}
+
+	assert.False(t, isTwoFactorAuthorizationRequired(config, user, false, true))
+}
+
+func TestIsTwoFactorAuthorizationRequired_UserWithoutTwoFactorSettingOrPasskey(t *testing.T) {
+	config := &settings.Config{EnableTwoFactor: true, EnablePasskey: true}
+	user := &models.User{Uid: 1}
+
+	assert.False(t, isTwoFactorAuthorizationRequired(config, user, false, false))
+}
@@ -1,10 +1,13 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
+
 	"github.com/mayswind/ezbookkeeping/pkg/avatars"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
@@ -16,6 +19,7 @@ import (
 )
 
 const internalTransactionPictureUrlFormat = "%spictures/%d.%s"
+const passkeyCeremonySessionExpiration = 5 * time.Minute
 
 // ApiUsingConfig represents an api that need to use config
 type ApiUsingConfig struct {
@@ -199,6 +203,46 @@ func (a *ApiUsingDuplicateChecker) CheckAndIncreaseFailureCount(c *core.WebConte
 	return nil
 }
 
+// SetPasskeyCeremonySession saves the passkey ceremony session of the specified user by the current duplicate checker
+func (a *ApiUsingDuplicateChecker) SetPasskeyCeremonySession(c *core.WebContext, uid int64, session *webauthn.SessionData) error {
+	sessionData, err := json.Marshal(session)
+
+	if err != nil {
+		log.Errorf(c, "[base.SetPasskeyCeremonySession] failed to marshal passkey ceremony session for user \"uid:%d\", because %s", uid, err.Error())
+		return errs.ErrOperationFailed
+	}
+
+	a.container.SetSubmissionRemarkWithCustomExpiration(duplicatechecker.DUPLICATE_CHECKER_TYPE_PASSKEY_CEREMONY, uid, session.Challenge, string(sessionData), passkeyCeremonySessionExpiration)
+
+	return nil
+}
+
+// GetAndRemovePasskeyCeremonySession returns the passkey ceremony session of the specified user and challenge, and removes it from the current duplicate checker
+func (a *ApiUsingDuplicateChecker) GetAndRemovePasskeyCeremonySession(c *core.WebContext, uid int64, challenge string) (*webauthn.SessionData, error) {
+	if challenge == "" {
+		return nil, errs.ErrPasskeyCeremonyExpired
+	}
+
+	found, sessionData := a.container.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_PASSKEY_CEREMONY, uid, challenge)
+
+	if !found {
+		return nil, errs.ErrPasskeyCeremonyExpired
+	}
+
+	// each ceremony session can only be used once
+	a.container.RemoveSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_PASSKEY_CEREMONY, uid, challenge)
+
+	session := &webauthn.SessionData{}
+	err := json.Unmarshal([]byte(sessionData), session)
+
+	if err != nil {
+		log.Errorf(c, "[base.GetAndRemovePasskeyCeremonySession] failed to unmarshal passkey ceremony session for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrPasskeyCeremonyExpired
+	}
+
+	return session, nil
+}
+
 // ApiUsingAvatarProvider represents an api that need to use avatar provider
 type ApiUsingAvatarProvider struct {
 	container *avatars.AvatarProviderContainer
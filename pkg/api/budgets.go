@@ -0,0 +1,477 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// BudgetsApi represents budget api
+type BudgetsApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	budgets    *services.BudgetService
+	users      *services.UserService
+	accounts   *services.AccountService
+	categories *services.TransactionCategoryService
+}
+
+// Initialize a budget api singleton instance
+var (
+	Budgets = &BudgetsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		budgets:    services.Budgets,
+		users:      services.Users,
+		accounts:   services.Accounts,
+		categories: services.TransactionCategories,
+	}
+)
+
+// BudgetListHandler returns budget list of current user
+func (a *BudgetsApi) BudgetListHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetListReq models.BudgetListRequest
+	err := c.ShouldBindQuery(&budgetListReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	budgets, err := a.budgets.GetAllBudgetsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetListHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetResps := make(models.BudgetInfoResponseSlice, 0, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		if budgetListReq.VisibleOnly && budgets[i].Hidden {
+			continue
+		}
+
+		budgetResps = append(budgetResps, budgets[i].ToBudgetInfoResponse())
+	}
+
+	sort.Sort(budgetResps)
+
+	return budgetResps, nil
+}
+
+// BudgetGetHandler returns one specific budget of current user
+func (a *BudgetsApi) BudgetGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetGetReq models.BudgetGetRequest
+	err := c.ShouldBindQuery(&budgetGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetGetHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return budget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetProgressHandler returns the budget versus actual spending of current period for one specific budget or all visible budgets of current user
+func (a *BudgetsApi) BudgetProgressHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetProgressReq models.BudgetProgressRequest
+	err := c.ShouldBindQuery(&budgetProgressReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetProgressHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetProgressHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	var budgets []*models.Budget
+
+	if budgetProgressReq.Id > 0 {
+		budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetProgressReq.Id)
+
+		if err != nil {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetProgressReq.Id, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		budgets = []*models.Budget{budget}
+	} else {
+		allBudgets, err := a.budgets.GetAllBudgetsByUid(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		for i := 0; i < len(allBudgets); i++ {
+			if !allBudgets[i].Hidden {
+				budgets = append(budgets, allBudgets[i])
+			}
+		}
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountMap := a.accounts.GetAccountMapByList(accounts)
+	currentTime := budgetProgressReq.Time
+
+	if currentTime <= 0 {
+		currentTime = time.Now().Unix()
+	}
+
+	totalAmountsCache := make(map[[2]int64][]*models.TransactionTotalAmount)
+	progressResps := make(models.BudgetProgressResponseSlice, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budget := budgets[i]
+		progress, err := a.budgets.GetBudgetProgress(c, uid, budget, accountMap, currentTime, clientTimezone, user.FirstDayOfWeek, user.FiscalYearStart, totalAmountsCache)
+
+		if err != nil {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get progress of budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		progressResps[i] = budget.ToBudgetProgressResponse(progress.PeriodStartTime, progress.PeriodEndTime, progress.RolloverAmount, progress.SpentAmount, progress.OtherCurrencySpentAmounts)
+	}
+
+	sort.Sort(progressResps)
+
+	return progressResps, nil
+}
+
+// BudgetCreateHandler saves a new budget by request parameters for current user
+func (a *BudgetsApi) BudgetCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetCreateReq models.BudgetCreateRequest
+	err := c.ShouldBindJSON(&budgetCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !budgetCreateReq.PeriodType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] budget period type invalid, type is %d", budgetCreateReq.PeriodType)
+		return nil, errs.ErrBudgetPeriodTypeInvalid
+	}
+
+	if !budgetCreateReq.TargetType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] budget target type invalid, type is %d", budgetCreateReq.TargetType)
+		return nil, errs.ErrBudgetTargetTypeInvalid
+	}
+
+	if budgetCreateReq.LimitAmount <= 0 {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] budget limit amount invalid, amount is %d", budgetCreateReq.LimitAmount)
+		return nil, errs.ErrBudgetLimitAmountInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.checkBudgetTarget(c, uid, budgetCreateReq.TargetType, budgetCreateReq.TargetId, budgetCreateReq.Currency)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] budget target \"id:%d\" is invalid for user \"uid:%d\", because %s", budgetCreateReq.TargetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	maxOrderId, err := a.budgets.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budget := a.createNewBudgetModel(uid, &budgetCreateReq, maxOrderId+1)
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && budgetCreateReq.ClientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_BUDGET, uid, budgetCreateReq.ClientSessionId)
+
+		if found {
+			log.Infof(c, "[budgets.BudgetCreateHandler] another budget \"id:%s\" has been created for user \"uid:%d\"", remark, uid)
+			budgetId, err := utils.StringToInt64(remark)
+
+			if err == nil {
+				budget, err = a.budgets.GetBudgetByBudgetId(c, uid, budgetId)
+
+				if err != nil {
+					log.Errorf(c, "[budgets.BudgetCreateHandler] failed to get existed budget \"id:%d\" for user \"uid:%d\", because %s", budgetId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
+				return budget.ToBudgetInfoResponse(), nil
+			}
+		}
+	}
+
+	err = a.budgets.CreateBudget(c, budget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCreateHandler] failed to create budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetCreateHandler] user \"uid:%d\" has created a new budget \"id:%d\" successfully", uid, budget.BudgetId)
+
+	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_BUDGET, uid, budgetCreateReq.ClientSessionId, utils.Int64ToString(budget.BudgetId))
+
+	return budget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetModifyHandler saves an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetModifyReq models.BudgetModifyRequest
+	err := c.ShouldBindJSON(&budgetModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !budgetModifyReq.PeriodType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] budget period type invalid, type is %d", budgetModifyReq.PeriodType)
+		return nil, errs.ErrBudgetPeriodTypeInvalid
+	}
+
+	if !budgetModifyReq.TargetType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] budget target type invalid, type is %d", budgetModifyReq.TargetType)
+		return nil, errs.ErrBudgetTargetTypeInvalid
+	}
+
+	if budgetModifyReq.LimitAmount <= 0 {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] budget limit amount invalid, amount is %d", budgetModifyReq.LimitAmount)
+		return nil, errs.ErrBudgetLimitAmountInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newBudget := &models.Budget{
+		BudgetId:    budget.BudgetId,
+		Uid:         uid,
+		Name:        budgetModifyReq.Name,
+		PeriodType:  budgetModifyReq.PeriodType,
+		TargetType:  budgetModifyReq.TargetType,
+		TargetId:    budgetModifyReq.TargetId,
+		Currency:    budgetModifyReq.Currency,
+		LimitAmount: budgetModifyReq.LimitAmount,
+		Rollover:    budgetModifyReq.Rollover,
+		StartTime:   budgetModifyReq.StartTime,
+		Comment:     budgetModifyReq.Comment,
+		Hidden:      budgetModifyReq.Hidden,
+	}
+
+	if newBudget.StartTime <= 0 {
+		newBudget.StartTime = budget.StartTime
+	}
+
+	if newBudget.Name == budget.Name &&
+		newBudget.PeriodType == budget.PeriodType &&
+		newBudget.TargetType == budget.TargetType &&
+		newBudget.TargetId == budget.TargetId &&
+		newBudget.Currency == budget.Currency &&
+		newBudget.LimitAmount == budget.LimitAmount &&
+		newBudget.Rollover == budget.Rollover &&
+		newBudget.StartTime == budget.StartTime &&
+		newBudget.Comment == budget.Comment &&
+		newBudget.Hidden == budget.Hidden {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.checkBudgetTarget(c, uid, newBudget.TargetType, newBudget.TargetId, newBudget.Currency)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] budget target \"id:%d\" is invalid for user \"uid:%d\", because %s", newBudget.TargetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.budgets.ModifyBudget(c, newBudget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to update budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetModifyHandler] user \"uid:%d\" has updated budget \"id:%d\" successfully", uid, budgetModifyReq.Id)
+
+	newBudget.DisplayOrder = budget.DisplayOrder
+
+	return newBudget.ToBudgetInfoResponse(), nil
+}
+
+// BudgetHideHandler hides a budget by request parameters for current user
+func (a *BudgetsApi) BudgetHideHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetHideReq models.BudgetHideRequest
+	err := c.ShouldBindJSON(&budgetHideReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetHideHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.budgets.HideBudget(c, uid, []int64{budgetHideReq.Id}, budgetHideReq.Hidden)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetHideHandler] failed to hide budget \"id:%d\" for user \"uid:%d\", because %s", budgetHideReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetHideHandler] user \"uid:%d\" has hidden budget \"id:%d\"", uid, budgetHideReq.Id)
+	return true, nil
+}
+
+// BudgetMoveHandler moves display order of existed budgets by request parameters for current user
+func (a *BudgetsApi) BudgetMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetMoveReq models.BudgetMoveRequest
+	err := c.ShouldBindJSON(&budgetMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	budgets := make([]*models.Budget, len(budgetMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(budgetMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := budgetMoveReq.NewDisplayOrders[i]
+		budget := &models.Budget{
+			Uid:          uid,
+			BudgetId:     newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		budgets[i] = budget
+	}
+
+	err = a.budgets.ModifyBudgetDisplayOrders(c, uid, budgets)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetMoveHandler] failed to move budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetMoveHandler] user \"uid:%d\" has moved budgets", uid)
+	return true, nil
+}
+
+// BudgetDeleteHandler deletes an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetDeleteReq models.BudgetDeleteRequest
+	err := c.ShouldBindJSON(&budgetDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.budgets.DeleteBudget(c, uid, budgetDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetDeleteHandler] failed to delete budget \"id:%d\" for user \"uid:%d\", because %s", budgetDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetDeleteHandler] user \"uid:%d\" has deleted budget \"id:%d\"", uid, budgetDeleteReq.Id)
+	return true, nil
+}
+
+func (a *BudgetsApi) checkBudgetTarget(c *core.WebContext, uid int64, targetType models.BudgetTargetType, targetId int64, currency string) error {
+	if targetType == models.BUDGET_TARGET_TYPE_CATEGORY {
+		category, err := a.categories.GetCategoryByCategoryId(c, uid, targetId)
+
+		if err != nil {
+			return err
+		}
+
+		if category.Type != models.CATEGORY_TYPE_EXPENSE {
+			return errs.ErrBudgetCategoryNotExpense
+		}
+	} else if targetType == models.BUDGET_TARGET_TYPE_ACCOUNT {
+		account, err := a.accounts.GetAccountByAccountId(c, uid, targetId)
+
+		if err != nil {
+			return err
+		}
+
+		if account.Type == models.ACCOUNT_TYPE_SINGLE_ACCOUNT && account.Currency != currency {
+			return errs.ErrBudgetCurrencyMismatch
+		}
+	} else {
+		return errs.ErrBudgetTargetTypeInvalid
+	}
+
+	return nil
+}
+
+func (a *BudgetsApi) createNewBudgetModel(uid int64, budgetCreateReq *models.BudgetCreateRequest, order int32) *models.Budget {
+	startTime := budgetCreateReq.StartTime
+
+	if startTime <= 0 {
+		startTime = time.Now().Unix()
+	}
+
+	return &models.Budget{
+		Uid:          uid,
+		Name:         budgetCreateReq.Name,
+		PeriodType:   budgetCreateReq.PeriodType,
+		TargetType:   budgetCreateReq.TargetType,
+		TargetId:     budgetCreateReq.TargetId,
+		Currency:     budgetCreateReq.Currency,
+		LimitAmount:  budgetCreateReq.LimitAmount,
+		Rollover:     budgetCreateReq.Rollover,
+		StartTime:    startTime,
+		Comment:      budgetCreateReq.Comment,
+		DisplayOrder: order,
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"strings"
 	"time"
@@ -31,9 +32,14 @@ type DataManagementsApi struct {
 	tagGroups               *services.TransactionTagGroupService
 	pictures                *services.TransactionPictureService
 	templates               *services.TransactionTemplateService
+	rules                   *services.TransactionRuleService
+	webhooks                *services.WebhookService
 	userCustomIcons         *services.UserCustomIconService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
 	insightsExploreres      *services.InsightsExplorerService
+	budgets                 *services.BudgetService
+	investments             *services.InvestmentService
+	userDataBackups         *services.UserDataBackupService
 }
 
 // Initialize a data management api singleton instance
@@ -51,98 +57,176 @@ var (
 		tagGroups:               services.TransactionTagGroups,
 		pictures:                services.TransactionPictures,
 		templates:               services.TransactionTemplates,
+		rules:                   services.TransactionRules,
+		webhooks:                services.Webhooks,
 		userCustomIcons:         services.UserCustomIcons,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
 		insightsExploreres:      services.InsightsExplorers,
+		budgets:                 services.Budgets,
+		investments:             services.Investments,
+		userDataBackups:         services.UserDataBackups,
 	}
 )
 
 // ExportDataToEzbookkeepingCSVHandler returns exported data in csv format
 func (a *DataManagementsApi) ExportDataToEzbookkeepingCSVHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
-	return a.getExportedFileContent(c, "csv")
+	return a.getExportedFileContent(c, "csv", "csv")
 }
 
 // ExportDataToEzbookkeepingTSVHandler returns exported data in csv format
 func (a *DataManagementsApi) ExportDataToEzbookkeepingTSVHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
-	return a.getExportedFileContent(c, "tsv")
+	return a.getExportedFileContent(c, "tsv", "tsv")
 }
 
-// DataStatisticsHandler returns user data statistics
-func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
-	uid := c.GetCurrentUid()
-	totalAccountCount, err := a.accounts.GetTotalAccountCountByUid(c, uid)
+// ExportDataToOFXHandler returns exported data in open financial exchange (ofx) format
+func (a *DataManagementsApi) ExportDataToOFXHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "ofx", "ofx")
+}
 
-	if err != nil {
-		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total account count for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+// ExportDataToBeancountHandler returns exported data in Beancount format
+func (a *DataManagementsApi) ExportDataToBeancountHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "beancount", "beancount")
+}
+
+// ExportDataToQIFYearMonthDayHandler returns exported data in quicken interchange format (qif) with year-month-day date format
+func (a *DataManagementsApi) ExportDataToQIFYearMonthDayHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "qif_ymd", "qif")
+}
+
+// ExportDataToQIFMonthDayYearHandler returns exported data in quicken interchange format (qif) with month-day-year date format
+func (a *DataManagementsApi) ExportDataToQIFMonthDayYearHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "qif_mdy", "qif")
+}
+
+// ExportDataToQIFDayMonthYearHandler returns exported data in quicken interchange format (qif) with day-month-year date format
+func (a *DataManagementsApi) ExportDataToQIFDayMonthYearHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "qif_dmy", "qif")
+}
+
+// ExportDataToGnuCashHandler returns exported data in gzip compressed GnuCash xml format
+func (a *DataManagementsApi) ExportDataToGnuCashHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "gnucash", "gnucash")
+}
+
+// BackupDataHandler returns the backup archive which contains all data of current user
+func (a *DataManagementsApi) BackupDataHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	if !a.CurrentConfig().EnableDataExport {
+		return nil, "", errs.ErrDataExportNotAllowed
 	}
 
-	totalTransactionCategoryCount, err := a.categories.GetTotalCategoryCountByUid(c, uid)
+	clientTimezone, err := c.GetClientTimezone()
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction category count for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+		log.Warnf(c, "[data_managements.BackupDataHandler] cannot get client timezone, because %s", err.Error())
+		clientTimezone = time.Local
 	}
 
-	totalTransactionTagCount, err := a.tags.GetTotalTagCountByUid(c, uid)
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction tag count for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[data_managements.BackupDataHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, "", errs.ErrUserNotFound
 	}
 
-	totalTransactionCount, err := a.transactions.GetTotalTransactionCountByUid(c, uid)
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_EXPORT_TRANSACTION) {
+		return nil, "", errs.ErrNotPermittedToPerformThisAction
+	}
+
+	result, err := a.userDataBackups.ExportBackupArchive(c, uid)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction count for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+		log.Errorf(c, "[data_managements.BackupDataHandler] failed to backup data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	totalTransactionPictureCount, err := a.pictures.GetTotalTransactionPicturesCountByUid(c, uid)
+	fileName := a.getFileName(user, clientTimezone, "zip")
+
+	return result, fileName, nil
+}
+
+// RestoreDataHandler restores all data in the uploaded backup archive to current user which does not have any data
+func (a *DataManagementsApi) RestoreDataHandler(c *core.WebContext) (any, *errs.Error) {
+	if !a.CurrentConfig().EnableDataImport {
+		return nil, errs.ErrDataImportNotAllowed
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction picture count for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[data_managements.RestoreDataHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
 	}
 
-	totalExplorationCount, err := a.insightsExploreres.GetTotalExplorationsCountByUid(c, uid)
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	form, err := c.MultipartForm()
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total exploration count for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+		log.Errorf(c, "[data_managements.RestoreDataHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrParameterInvalid
 	}
 
-	totalTransactionTemplateCount, err := a.templates.GetTotalNormalTemplateCountByUid(c, uid)
+	backupFiles := form.File["file"]
+
+	if len(backupFiles) < 1 {
+		log.Warnf(c, "[data_managements.RestoreDataHandler] there is no backup file in request for user \"uid:%d\"", uid)
+		return nil, errs.ErrNoFilesUpload
+	}
+
+	if backupFiles[0].Size < 1 {
+		log.Warnf(c, "[data_managements.RestoreDataHandler] the size of backup file in request is zero for user \"uid:%d\"", uid)
+		return nil, errs.ErrUploadedFileEmpty
+	}
+
+	if backupFiles[0].Size > int64(a.CurrentConfig().MaxImportFileSize) {
+		log.Warnf(c, "[data_managements.RestoreDataHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of import file for user \"uid:%d\"", backupFiles[0].Size, a.CurrentConfig().MaxImportFileSize, uid)
+		return nil, errs.ErrExceedMaxUploadFileSize
+	}
+
+	backupFile, err := backupFiles[0].Open()
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total transaction template count for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[data_managements.RestoreDataHandler] failed to get backup file from request for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.ErrOperationFailed
 	}
 
-	totalScheduledTransactionCount, err := a.templates.GetTotalScheduledTemplateCountByUid(c, uid)
+	defer backupFile.Close()
+	fileData, err := io.ReadAll(backupFile)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total scheduled transaction count for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+		log.Errorf(c, "[data_managements.RestoreDataHandler] failed to read backup file data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	totalCustomIconCount, err := a.userCustomIcons.GetTotalCustomIconsCountByUid(c, uid)
+	backup, err := a.userDataBackups.RestoreBackupArchive(c, uid, fileData)
 
 	if err != nil {
-		log.Errorf(c, "[data_managements.DataStatisticsHandler] failed to get total custom icon count for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrOperationFailed
+		log.Errorf(c, "[data_managements.RestoreDataHandler] failed to restore data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	dataStatisticsResp := &models.DataStatisticsResponse{
-		TotalAccountCount:              totalAccountCount,
-		TotalTransactionCategoryCount:  totalTransactionCategoryCount,
-		TotalTransactionTagCount:       totalTransactionTagCount,
-		TotalTransactionCount:          totalTransactionCount,
-		TotalTransactionPictureCount:   totalTransactionPictureCount,
-		TotalExplorationCount:          totalExplorationCount,
-		TotalTransactionTemplateCount:  totalTransactionTemplateCount,
-		TotalScheduledTransactionCount: totalScheduledTransactionCount,
-		TotalCustomIconCount:           totalCustomIconCount,
+	log.Infof(c, "[data_managements.RestoreDataHandler] user \"uid:%d\" has restored data from backup file", uid)
+
+	return backup.ToUserDataRestoreResponse(), nil
+}
+
+// DataStatisticsHandler returns user data statistics
+func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	dataStatisticsResp, err := a.getDataStatistics(c, uid)
+
+	if err != nil {
+		return nil, err
 	}
 
 	return dataStatisticsResp, nil
@@ -233,6 +317,34 @@ func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	err = a.rules.DeleteAllRules(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction rules, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.webhooks.DeleteAllWebhooks(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all webhooks, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.budgets.DeleteAllBudgets(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all budgets, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.investments.DeleteAllInvestments(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all investments, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	log.Infof(c, "[data_managements.ClearAllDataHandler] user \"uid:%d\" has cleared all data", uid)
 	return true, nil
 }
@@ -332,7 +444,86 @@ func (a *DataManagementsApi) ClearAllTransactionsByAccountHandler(c *core.WebCon
 	return true, nil
 }
 
-func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType string) ([]byte, string, *errs.Error) {
+func (a *DataManagementsApi) getDataStatistics(c *core.WebContext, uid int64) (*models.DataStatisticsResponse, *errs.Error) {
+	totalAccountCount, err := a.accounts.GetTotalAccountCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getDataStatistics] failed to get total account count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalTransactionCategoryCount, err := a.categories.GetTotalCategoryCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getDataStatistics] failed to get total transaction category count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalTransactionTagCount, err := a.tags.GetTotalTagCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getDataStatistics] failed to get total transaction tag count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalTransactionCount, err := a.transactions.GetTotalTransactionCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getDataStatistics] failed to get total transaction count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalTransactionPictureCount, err := a.pictures.GetTotalTransactionPicturesCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getDataStatistics] failed to get total transaction picture count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalExplorationCount, err := a.insightsExploreres.GetTotalExplorationsCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getDataStatistics] failed to get total exploration count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalTransactionTemplateCount, err := a.templates.GetTotalNormalTemplateCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getDataStatistics] failed to get total transaction template count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalScheduledTransactionCount, err := a.templates.GetTotalScheduledTemplateCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getDataStatistics] failed to get total scheduled transaction count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	totalCustomIconCount, err := a.userCustomIcons.GetTotalCustomIconsCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.getDataStatistics] failed to get total custom icon count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	dataStatisticsResp := &models.DataStatisticsResponse{
+		TotalAccountCount:              totalAccountCount,
+		TotalTransactionCategoryCount:  totalTransactionCategoryCount,
+		TotalTransactionTagCount:       totalTransactionTagCount,
+		TotalTransactionCount:          totalTransactionCount,
+		TotalTransactionPictureCount:   totalTransactionPictureCount,
+		TotalExplorationCount:          totalExplorationCount,
+		TotalTransactionTemplateCount:  totalTransactionTemplateCount,
+		TotalScheduledTransactionCount: totalScheduledTransactionCount,
+		TotalCustomIconCount:           totalCustomIconCount,
+	}
+
+	return dataStatisticsResp, nil
+}
+
+func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType string, fileExtension string) ([]byte, string, *errs.Error) {
 	if !a.CurrentConfig().EnableDataExport {
 		return nil, "", errs.ErrDataExportNotAllowed
 	}
@@ -456,7 +647,7 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		return nil, "", errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	fileName := a.getFileName(user, clientTimezone, fileType)
+	fileName := a.getFileName(user, clientTimezone, fileExtension)
 
 	return result, fileName, nil
 }
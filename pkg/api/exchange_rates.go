@@ -8,6 +8,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // ExchangeRatesApi represents exchange rate api
@@ -15,6 +16,7 @@ type ExchangeRatesApi struct {
 	ApiUsingConfig
 	users                   *services.UserService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
+	exchangeRateHistories   *services.ExchangeRateHistoryService
 }
 
 // Initialize a exchange rate api singleton instance
@@ -25,6 +27,7 @@ var (
 		},
 		users:                   services.Users,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
+		exchangeRateHistories:   services.ExchangeRateHistories,
 	}
 )
 
@@ -39,6 +42,40 @@ func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.WebContext) (any, *
 	return exchangeRateResponse, nil
 }
 
+// HistoricalExchangeRateHandler returns the exchange rate data of specified date from the exchange rate history
+func (a *ExchangeRatesApi) HistoricalExchangeRateHandler(c *core.WebContext) (any, *errs.Error) {
+	var historicalExchangeRateReq models.HistoricalExchangeRateRequest
+	err := c.ShouldBindQuery(&historicalExchangeRateReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	dataSource := a.CurrentConfig().ExchangeRatesDataSource
+
+	if dataSource == settings.UserCustomExchangeRatesDataSource {
+		return nil, errs.ErrExchangeRatesDataSourceNotSupportHistoryData
+	}
+
+	rateTime, err := utils.ParseFromLongDateFirstTime(historicalExchangeRateReq.Date, 0)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRateHandler] failed to parse date \"%s\", because %s", historicalExchangeRateReq.Date, err.Error())
+		return nil, errs.ErrExchangeRateHistoryDateInvalid
+	}
+
+	rateDate := utils.FormatUnixTimeToNumericYearMonthDay(rateTime.Unix(), rateTime.Location())
+	exchangeRateHistories, err := a.exchangeRateHistories.GetExchangeRatesByDate(c, dataSource, rateDate)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.HistoricalExchangeRateHandler] failed to get exchange rate history of \"%s\", because %s", historicalExchangeRateReq.Date, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return models.ToHistoricalExchangeRateResponse(exchangeRateHistories), nil
+}
+
 // UserCustomExchangeRateUpdateHandler updates user custom exchange rates data by request parameters for current user
 func (a *ExchangeRatesApi) UserCustomExchangeRateUpdateHandler(c *core.WebContext) (any, *errs.Error) {
 	var customExchangeRateUpdateReq models.UserCustomExchangeRateUpdateRequest
@@ -0,0 +1,621 @@
+package api
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/securityprices"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maxSecurityPriceDataSourceNameLength = 32
+
+// InvestmentsApi represents investment api
+type InvestmentsApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	investments    *services.InvestmentService
+	securityPrices *services.SecurityPriceService
+	accounts       *services.AccountService
+}
+
+// Initialize an investment api singleton instance
+var (
+	Investments = &InvestmentsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		investments:    services.Investments,
+		securityPrices: services.SecurityPrices,
+		accounts:       services.Accounts,
+	}
+)
+
+// SecurityListHandler returns security list of current user
+func (a *InvestmentsApi) SecurityListHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityListReq models.InvestmentSecurityListRequest
+	err := c.ShouldBindQuery(&securityListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	securities, err := a.getSecurities(c, uid, securityListReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityListHandler] failed to get securities for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	securityResps := make(models.InvestmentSecurityInfoResponseSlice, len(securities))
+
+	for i := 0; i < len(securities); i++ {
+		securityResps[i] = securities[i].ToInvestmentSecurityInfoResponse()
+	}
+
+	sort.Sort(securityResps)
+
+	return securityResps, nil
+}
+
+// SecurityCreateHandler saves a new security by request parameters for current user
+func (a *InvestmentsApi) SecurityCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityCreateReq models.InvestmentSecurityCreateRequest
+	err := c.ShouldBindJSON(&securityCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.checkInvestmentAccount(c, uid, securityCreateReq.AccountId)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityCreateHandler] account \"id:%d\" is invalid for user \"uid:%d\", because %s", securityCreateReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	security := &models.InvestmentSecurity{
+		Uid:       uid,
+		AccountId: securityCreateReq.AccountId,
+		Symbol:    strings.TrimSpace(securityCreateReq.Symbol),
+		Name:      securityCreateReq.Name,
+		Comment:   securityCreateReq.Comment,
+	}
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && securityCreateReq.ClientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_SECURITY, uid, securityCreateReq.ClientSessionId)
+
+		if found {
+			log.Infof(c, "[investments.SecurityCreateHandler] another security \"id:%s\" has been created for user \"uid:%d\"", remark, uid)
+			securityId, err := utils.StringToInt64(remark)
+
+			if err == nil {
+				security, err = a.investments.GetSecurityBySecurityId(c, uid, securityId)
+
+				if err != nil {
+					log.Errorf(c, "[investments.SecurityCreateHandler] failed to get existed security \"id:%d\" for user \"uid:%d\", because %s", securityId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
+				return security.ToInvestmentSecurityInfoResponse(), nil
+			}
+		}
+	}
+
+	err = a.investments.CreateSecurity(c, security)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityCreateHandler] failed to create security \"id:%d\" for user \"uid:%d\", because %s", security.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityCreateHandler] user \"uid:%d\" has created a new security \"id:%d\" successfully", uid, security.SecurityId)
+
+	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_SECURITY, uid, securityCreateReq.ClientSessionId, utils.Int64ToString(security.SecurityId))
+
+	return security.ToInvestmentSecurityInfoResponse(), nil
+}
+
+// SecurityModifyHandler saves an existed security by request parameters for current user
+func (a *InvestmentsApi) SecurityModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityModifyReq models.InvestmentSecurityModifyRequest
+	err := c.ShouldBindJSON(&securityModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	security, err := a.investments.GetSecurityBySecurityId(c, uid, securityModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityModifyHandler] failed to get security \"id:%d\" for user \"uid:%d\", because %s", securityModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newSecurity := &models.InvestmentSecurity{
+		SecurityId: security.SecurityId,
+		Uid:        uid,
+		AccountId:  security.AccountId,
+		Symbol:     strings.TrimSpace(securityModifyReq.Symbol),
+		Name:       securityModifyReq.Name,
+		Comment:    securityModifyReq.Comment,
+	}
+
+	if newSecurity.Symbol == security.Symbol &&
+		newSecurity.Name == security.Name &&
+		newSecurity.Comment == security.Comment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.investments.ModifySecurity(c, newSecurity)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityModifyHandler] failed to update security \"id:%d\" for user \"uid:%d\", because %s", securityModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityModifyHandler] user \"uid:%d\" has updated security \"id:%d\" successfully", uid, securityModifyReq.Id)
+
+	return newSecurity.ToInvestmentSecurityInfoResponse(), nil
+}
+
+// SecurityDeleteHandler deletes an existed security by request parameters for current user
+func (a *InvestmentsApi) SecurityDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var securityDeleteReq models.InvestmentSecurityDeleteRequest
+	err := c.ShouldBindJSON(&securityDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.investments.DeleteSecurity(c, uid, securityDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityDeleteHandler] failed to delete security \"id:%d\" for user \"uid:%d\", because %s", securityDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityDeleteHandler] user \"uid:%d\" has deleted security \"id:%d\"", uid, securityDeleteReq.Id)
+	return true, nil
+}
+
+// InvestmentTransactionListHandler returns investment transaction list of specified security for current user
+func (a *InvestmentsApi) InvestmentTransactionListHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionListReq models.InvestmentTransactionListRequest
+	err := c.ShouldBindQuery(&transactionListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentTransactionListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	transactions, err := a.investments.GetInvestmentTransactionsBySecurityId(c, uid, transactionListReq.SecurityId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentTransactionListHandler] failed to get investment transactions of security \"id:%d\" for user \"uid:%d\", because %s", transactionListReq.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionResps := make([]*models.InvestmentTransactionInfoResponse, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transactionResps[i] = transactions[i].ToInvestmentTransactionInfoResponse()
+	}
+
+	return transactionResps, nil
+}
+
+// InvestmentTransactionCreateHandler saves a new buy, sell or dividend transaction by request parameters for current user
+func (a *InvestmentsApi) InvestmentTransactionCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionCreateReq models.InvestmentTransactionCreateRequest
+	err := c.ShouldBindJSON(&transactionCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentTransactionCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !transactionCreateReq.Type.IsValid() {
+		log.Warnf(c, "[investments.InvestmentTransactionCreateHandler] investment transaction type invalid, type is %d", transactionCreateReq.Type)
+		return nil, errs.ErrInvestmentTransactionTypeInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	security, err := a.investments.GetSecurityBySecurityId(c, uid, transactionCreateReq.SecurityId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentTransactionCreateHandler] failed to get security \"id:%d\" for user \"uid:%d\", because %s", transactionCreateReq.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transaction := &models.InvestmentTransaction{
+		Uid:             uid,
+		SecurityId:      security.SecurityId,
+		AccountId:       security.AccountId,
+		Type:            transactionCreateReq.Type,
+		TransactionTime: transactionCreateReq.TransactionTime,
+		Quantity:        transactionCreateReq.Quantity,
+		Amount:          transactionCreateReq.Amount,
+		Fee:             transactionCreateReq.Fee,
+		Comment:         transactionCreateReq.Comment,
+	}
+
+	if !transaction.IsQuantityValid() {
+		log.Warnf(c, "[investments.InvestmentTransactionCreateHandler] investment transaction quantity invalid, type is %d, quantity is %d", transaction.Type, transaction.Quantity)
+		return nil, errs.ErrInvestmentTransactionQuantityInvalid
+	}
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && transactionCreateReq.ClientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_INVESTMENT_TRANSACTION, uid, transactionCreateReq.ClientSessionId)
+
+		if found {
+			log.Infof(c, "[investments.InvestmentTransactionCreateHandler] another investment transaction \"id:%s\" has been created for user \"uid:%d\"", remark, uid)
+			transactionId, err := utils.StringToInt64(remark)
+
+			if err == nil {
+				transaction, err = a.investments.GetInvestmentTransactionByInvestmentTransactionId(c, uid, transactionId)
+
+				if err != nil {
+					log.Errorf(c, "[investments.InvestmentTransactionCreateHandler] failed to get existed investment transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
+				return transaction.ToInvestmentTransactionInfoResponse(), nil
+			}
+		}
+	}
+
+	err = a.investments.CreateInvestmentTransaction(c, transaction)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentTransactionCreateHandler] failed to create investment transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.InvestmentTransactionId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.InvestmentTransactionCreateHandler] user \"uid:%d\" has created a new investment transaction \"id:%d\" successfully", uid, transaction.InvestmentTransactionId)
+
+	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_INVESTMENT_TRANSACTION, uid, transactionCreateReq.ClientSessionId, utils.Int64ToString(transaction.InvestmentTransactionId))
+
+	return transaction.ToInvestmentTransactionInfoResponse(), nil
+}
+
+// InvestmentTransactionDeleteHandler deletes an existed investment transaction by request parameters for current user
+func (a *InvestmentsApi) InvestmentTransactionDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var transactionDeleteReq models.InvestmentTransactionDeleteRequest
+	err := c.ShouldBindJSON(&transactionDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentTransactionDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.investments.DeleteInvestmentTransaction(c, uid, transactionDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentTransactionDeleteHandler] failed to delete investment transaction \"id:%d\" for user \"uid:%d\", because %s", transactionDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.InvestmentTransactionDeleteHandler] user \"uid:%d\" has deleted investment transaction \"id:%d\"", uid, transactionDeleteReq.Id)
+	return true, nil
+}
+
+// SecurityPriceListHandler returns price history of specified security for current user
+func (a *InvestmentsApi) SecurityPriceListHandler(c *core.WebContext) (any, *errs.Error) {
+	var priceListReq models.SecurityPriceListRequest
+	err := c.ShouldBindQuery(&priceListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityPriceListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	prices, err := a.securityPrices.GetSecurityPricesBySecurityId(c, uid, priceListReq.SecurityId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceListHandler] failed to get prices of security \"id:%d\" for user \"uid:%d\", because %s", priceListReq.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	priceResps := make(models.SecurityPriceInfoResponseSlice, len(prices))
+
+	for i := 0; i < len(prices); i++ {
+		priceResps[i] = prices[i].ToSecurityPriceInfoResponse()
+	}
+
+	sort.Sort(priceResps)
+
+	return priceResps, nil
+}
+
+// SecurityPriceCreateHandler saves a new security price which is set manually for current user
+func (a *InvestmentsApi) SecurityPriceCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var priceCreateReq models.SecurityPriceCreateRequest
+	err := c.ShouldBindJSON(&priceCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityPriceCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	security, err := a.investments.GetSecurityBySecurityId(c, uid, priceCreateReq.SecurityId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceCreateHandler] failed to get security \"id:%d\" for user \"uid:%d\", because %s", priceCreateReq.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	price := &models.SecurityPrice{
+		SecurityId: security.SecurityId,
+		PriceTime:  priceCreateReq.PriceTime,
+		Price:      priceCreateReq.Price,
+		DataSource: models.SecurityPriceManualDataSource,
+	}
+
+	err = a.securityPrices.CreateSecurityPrices(c, uid, []*models.SecurityPrice{price})
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceCreateHandler] failed to create price of security \"id:%d\" for user \"uid:%d\", because %s", security.SecurityId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityPriceCreateHandler] user \"uid:%d\" has created a new price \"id:%d\" of security \"id:%d\" successfully", uid, price.PriceId, security.SecurityId)
+
+	return price.ToSecurityPriceInfoResponse(), nil
+}
+
+// SecurityPriceDeleteHandler deletes an existed security price by request parameters for current user
+func (a *InvestmentsApi) SecurityPriceDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var priceDeleteReq models.SecurityPriceDeleteRequest
+	err := c.ShouldBindJSON(&priceDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityPriceDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.securityPrices.DeleteSecurityPrice(c, uid, priceDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceDeleteHandler] failed to delete security price \"id:%d\" for user \"uid:%d\", because %s", priceDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityPriceDeleteHandler] user \"uid:%d\" has deleted security price \"id:%d\"", uid, priceDeleteReq.Id)
+	return true, nil
+}
+
+// SecurityPriceUpdateHandler saves the latest prices of securities from the security prices data source for current user
+func (a *InvestmentsApi) SecurityPriceUpdateHandler(c *core.WebContext) (any, *errs.Error) {
+	var priceUpdateReq models.SecurityPriceUpdateRequest
+	err := c.ShouldBindJSON(&priceUpdateReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.SecurityPriceUpdateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !securityprices.Container.IsEnabled() {
+		return nil, errs.ErrSecurityPricesDataSourceNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+	securities, err := a.getSecurities(c, uid, priceUpdateReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceUpdateHandler] failed to get securities for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if len(securities) < 1 {
+		return make(models.SecurityPriceInfoResponseSlice, 0), nil
+	}
+
+	symbols := make([]string, 0, len(securities))
+	symbolExists := make(map[string]bool, len(securities))
+
+	for i := 0; i < len(securities); i++ {
+		symbol := strings.ToUpper(securities[i].Symbol)
+
+		if symbolExists[symbol] {
+			continue
+		}
+
+		symbols = append(symbols, securities[i].Symbol)
+		symbolExists[symbol] = true
+	}
+
+	latestPriceResp, err := securityprices.Container.GetLatestSecurityPrices(c, uid, symbols, a.CurrentConfig())
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceUpdateHandler] failed to get latest security prices for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	latestPriceMap := make(map[string]*models.LatestSecurityPrice, len(latestPriceResp.Prices))
+
+	for i := 0; i < len(latestPriceResp.Prices); i++ {
+		latestPriceMap[strings.ToUpper(latestPriceResp.Prices[i].Symbol)] = latestPriceResp.Prices[i]
+	}
+
+	existedLatestPrices, err := a.securityPrices.GetLatestSecurityPricesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceUpdateHandler] failed to get existed latest security prices for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	dataSource := latestPriceResp.DataSource
+
+	if len(dataSource) > maxSecurityPriceDataSourceNameLength {
+		dataSource = dataSource[:maxSecurityPriceDataSourceNameLength]
+	}
+
+	newPrices := make([]*models.SecurityPrice, 0, len(securities))
+
+	for i := 0; i < len(securities); i++ {
+		security := securities[i]
+		latestPrice, exists := latestPriceMap[strings.ToUpper(security.Symbol)]
+
+		if !exists {
+			continue
+		}
+
+		existedLatestPrice, exists := existedLatestPrices[security.SecurityId]
+
+		if exists && existedLatestPrice.PriceTime >= latestPrice.UpdateTime {
+			continue
+		}
+
+		price, err := latestPrice.ToSecurityPrice(uid, security.SecurityId, dataSource)
+
+		if err != nil {
+			log.Warnf(c, "[investments.SecurityPriceUpdateHandler] cannot parse price \"%s\" of symbol \"%s\", because %s", latestPrice.Price, latestPrice.Symbol, err.Error())
+			continue
+		}
+
+		newPrices = append(newPrices, price)
+	}
+
+	if len(newPrices) < 1 && len(latestPriceResp.Prices) < 1 {
+		return nil, errs.ErrSecurityPricesDataSourceNoAvailablePrices
+	}
+
+	err = a.securityPrices.CreateSecurityPrices(c, uid, newPrices)
+
+	if err != nil {
+		log.Errorf(c, "[investments.SecurityPriceUpdateHandler] failed to save latest security prices for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[investments.SecurityPriceUpdateHandler] user \"uid:%d\" has updated %d security prices from data source", uid, len(newPrices))
+
+	priceResps := make(models.SecurityPriceInfoResponseSlice, len(newPrices))
+
+	for i := 0; i < len(newPrices); i++ {
+		priceResps[i] = newPrices[i].ToSecurityPriceInfoResponse()
+	}
+
+	sort.Sort(priceResps)
+
+	return priceResps, nil
+}
+
+// InvestmentHoldingListHandler returns the holdings, cost basis, market value and gains of all securities for current user
+func (a *InvestmentsApi) InvestmentHoldingListHandler(c *core.WebContext) (any, *errs.Error) {
+	var holdingListReq models.InvestmentHoldingListRequest
+	err := c.ShouldBindQuery(&holdingListReq)
+
+	if err != nil {
+		log.Warnf(c, "[investments.InvestmentHoldingListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	securities, err := a.getSecurities(c, uid, holdingListReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentHoldingListHandler] failed to get securities for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactions, err := a.investments.GetAllInvestmentTransactionsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentHoldingListHandler] failed to get investment transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	latestPrices, err := a.securityPrices.GetLatestSecurityPricesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[investments.InvestmentHoldingListHandler] failed to get latest security prices for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionMap := a.investments.GetInvestmentTransactionListMapBySecurityId(transactions)
+	holdingResps := make([]*models.InvestmentHoldingResponse, 0, len(securities))
+
+	for i := 0; i < len(securities); i++ {
+		security := securities[i]
+		securityTransactions := transactionMap[security.SecurityId]
+		holding, err := securityTransactions.ToInvestmentHolding(security.SecurityId)
+
+		if err != nil {
+			log.Errorf(c, "[investments.InvestmentHoldingListHandler] failed to calculate holding of security \"id:%d\" for user \"uid:%d\", because %s", security.SecurityId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		latestPrice := latestPrices[security.SecurityId]
+
+		for j := len(securityTransactions) - 1; j >= 0; j-- {
+			impliedPrice := securityTransactions[j].ToImpliedSecurityPrice()
+
+			if impliedPrice == nil {
+				continue
+			}
+
+			if latestPrice == nil || impliedPrice.PriceTime > latestPrice.PriceTime {
+				latestPrice = impliedPrice
+			}
+
+			break
+		}
+
+		holdingResps = append(holdingResps, holding.ToInvestmentHoldingResponse(security, latestPrice))
+	}
+
+	return holdingResps, nil
+}
+
+func (a *InvestmentsApi) getSecurities(c *core.WebContext, uid int64, accountId int64) ([]*models.InvestmentSecurity, error) {
+	if accountId > 0 {
+		return a.investments.GetSecuritiesByAccountId(c, uid, accountId)
+	}
+
+	return a.investments.GetAllSecuritiesByUid(c, uid)
+}
+
+func (a *InvestmentsApi) checkInvestmentAccount(c *core.WebContext, uid int64, accountId int64) error {
+	account, err := a.accounts.GetAccountByAccountId(c, uid, accountId)
+
+	if err != nil {
+		return err
+	}
+
+	if account.Hidden {
+		return errs.ErrCannotUseHiddenAccount
+	}
+
+	if account.Category != models.ACCOUNT_CATEGORY_INVESTMENT || account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		return errs.ErrSecurityAccountNotInvestmentAccount
+	}
+
+	return nil
+}
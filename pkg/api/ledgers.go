@@ -0,0 +1,652 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maximumMemberCountPerLedger = 20
+
+// LedgersApi represents shared ledger api
+type LedgersApi struct {
+	ApiUsingConfig
+	ledgers      *services.LedgerService
+	users        *services.UserService
+	tokens       *services.TokenService
+	transactions *services.TransactionService
+}
+
+// Initialize a shared ledger api singleton instance
+var (
+	Ledgers = &LedgersApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ledgers:      services.Ledgers,
+		users:        services.Users,
+		tokens:       services.Tokens,
+		transactions: services.Transactions,
+	}
+)
+
+// LedgerListHandler returns all shared ledgers which current user has joined
+func (a *LedgersApi) LedgerListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentOperatorUid()
+	members, err := a.ledgers.GetAcceptedMembersByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerListHandler] failed to get ledger members for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ledgerIds := make([]int64, len(members))
+
+	for i := 0; i < len(members); i++ {
+		ledgerIds[i] = members[i].LedgerId
+	}
+
+	ledgers, err := a.ledgers.GetLedgersByLedgerIds(c, ledgerIds)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerListHandler] failed to get ledgers for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ledgerResps := make(models.LedgerInfoResponseSlice, 0, len(members))
+
+	for i := 0; i < len(members); i++ {
+		ledger, exists := ledgers[members[i].LedgerId]
+
+		if !exists {
+			continue
+		}
+
+		ledgerResps = append(ledgerResps, ledger.ToLedgerInfoResponse(members[i].Role))
+	}
+
+	sort.Sort(ledgerResps)
+
+	return ledgerResps, nil
+}
+
+// LedgerGetHandler returns one specific shared ledger and its members
+func (a *LedgersApi) LedgerGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerGetReq models.LedgerGetRequest
+	err := c.ShouldBindQuery(&ledgerGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	ledger, currentMember, err := a.getLedgerAndCurrentMember(c, ledgerGetReq.Id, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerGetHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	members, err := a.ledgers.GetMembersByLedgerId(c, ledger.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerGetHandler] failed to get members of ledger \"id:%d\" for user \"uid:%d\", because %s", ledger.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	memberUids := make([]int64, len(members))
+
+	for i := 0; i < len(members); i++ {
+		memberUids[i] = members[i].Uid
+	}
+
+	users, err := a.users.GetUsersByUids(c, memberUids)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerGetHandler] failed to get member users of ledger \"id:%d\" for user \"uid:%d\", because %s", ledger.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ledgerResp := ledger.ToLedgerInfoResponse(currentMember.Role)
+	ledgerResp.Members = make([]*models.LedgerMemberInfoResponse, len(members))
+
+	for i := 0; i < len(members); i++ {
+		ledgerResp.Members[i] = members[i].ToLedgerMemberInfoResponse(users[members[i].Uid])
+	}
+
+	return ledgerResp, nil
+}
+
+// LedgerCreateHandler saves a new shared ledger with a dedicated book by request parameters
+func (a *LedgersApi) LedgerCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerCreateReq models.LedgerCreateRequest
+	err := c.ShouldBindJSON(&ledgerCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[ledgers.LedgerCreateHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	ledger := &models.Ledger{
+		Uid:  uid,
+		Name: ledgerCreateReq.Name,
+	}
+
+	err = a.ledgers.CreateLedger(c, ledger, user)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerCreateHandler] failed to create ledger for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerCreateHandler] user \"uid:%d\" has created a new ledger \"id:%d\" successfully", uid, ledger.LedgerId)
+
+	return ledger.ToLedgerInfoResponse(models.LEDGER_MEMBER_ROLE_OWNER), nil
+}
+
+// LedgerModifyHandler saves an existed shared ledger by request parameters
+func (a *LedgersApi) LedgerModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerModifyReq models.LedgerModifyRequest
+	err := c.ShouldBindJSON(&ledgerModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	ledger, currentMember, err := a.getLedgerAndCurrentMember(c, ledgerModifyReq.Id, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerModifyHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if !currentMember.Role.CanManageMembers() {
+		log.Warnf(c, "[ledgers.LedgerModifyHandler] user \"uid:%d\" cannot modify ledger \"id:%d\"", uid, ledger.LedgerId)
+		return nil, errs.ErrLedgerOperationNotPermitted
+	}
+
+	if ledger.Name == ledgerModifyReq.Name {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	newLedger := &models.Ledger{
+		LedgerId: ledger.LedgerId,
+		Name:     ledgerModifyReq.Name,
+	}
+
+	err = a.ledgers.ModifyLedger(c, newLedger)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerModifyHandler] failed to update ledger \"id:%d\" for user \"uid:%d\", because %s", ledger.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerModifyHandler] user \"uid:%d\" has updated ledger \"id:%d\" successfully", uid, ledger.LedgerId)
+
+	ledger.Name = newLedger.Name
+	ledger.UpdatedUnixTime = newLedger.UpdatedUnixTime
+
+	return ledger.ToLedgerInfoResponse(currentMember.Role), nil
+}
+
+// LedgerDeleteHandler deletes an existed shared ledger which is created by current user
+func (a *LedgersApi) LedgerDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var ledgerDeleteReq models.LedgerDeleteRequest
+	err := c.ShouldBindJSON(&ledgerDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	err = a.ledgers.DeleteLedger(c, uid, ledgerDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerDeleteHandler] failed to delete ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerDeleteHandler] user \"uid:%d\" has deleted ledger \"id:%d\"", uid, ledgerDeleteReq.Id)
+	return true, nil
+}
+
+// LedgerMemberInviteHandler invites a user to the shared ledger by email
+func (a *LedgersApi) LedgerMemberInviteHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberInviteReq models.LedgerMemberInviteRequest
+	err := c.ShouldBindJSON(&memberInviteReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerMemberInviteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !memberInviteReq.Role.CanBeGrantedToMember() {
+		return nil, errs.ErrLedgerMemberRoleInvalid
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	ledger, currentMember, err := a.getLedgerAndCurrentMember(c, memberInviteReq.LedgerId, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberInviteHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", memberInviteReq.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if !currentMember.Role.CanManageMembers() {
+		log.Warnf(c, "[ledgers.LedgerMemberInviteHandler] user \"uid:%d\" cannot invite members to ledger \"id:%d\"", uid, ledger.LedgerId)
+		return nil, errs.ErrLedgerOperationNotPermitted
+	}
+
+	inviter, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[ledgers.LedgerMemberInviteHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	invitee, err := a.users.GetUserByEmail(c, memberInviteReq.Email)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[ledgers.LedgerMemberInviteHandler] failed to get invited user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if invitee.Uid == uid {
+		return nil, errs.ErrCannotInviteSelfToLedger
+	}
+
+	if invitee.Disabled {
+		log.Warnf(c, "[ledgers.LedgerMemberInviteHandler] invited user \"uid:%d\" is disabled", invitee.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	members, err := a.ledgers.GetMembersByLedgerId(c, ledger.LedgerId)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberInviteHandler] failed to get members of ledger \"id:%d\" for user \"uid:%d\", because %s", ledger.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if len(members) >= maximumMemberCountPerLedger {
+		return nil, errs.ErrLedgerMemberCountExceedsLimit
+	}
+
+	member := &models.LedgerMember{
+		LedgerId:   ledger.LedgerId,
+		Uid:        invitee.Uid,
+		Role:       memberInviteReq.Role,
+		InviterUid: uid,
+	}
+
+	err = a.ledgers.InviteMember(c, member)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberInviteHandler] failed to invite user \"uid:%d\" to ledger \"id:%d\" for user \"uid:%d\", because %s", invitee.Uid, ledger.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerMemberInviteHandler] user \"uid:%d\" has invited user \"uid:%d\" to ledger \"id:%d\" successfully", uid, invitee.Uid, ledger.LedgerId)
+
+	if a.CurrentConfig().EnableSMTP {
+		token, _, err := a.tokens.CreateLedgerInvitationToken(c, invitee, member.MemberId)
+
+		if err != nil {
+			log.Warnf(c, "[ledgers.LedgerMemberInviteHandler] failed to create invitation token for user \"uid:%d\", because %s", invitee.Uid, err.Error())
+		} else {
+			go func() {
+				err = a.ledgers.SendLedgerInvitationEmail(c, inviter, invitee, ledger, member.Role, token, c.GetClientLocale())
+
+				if err != nil {
+					log.Warnf(c, "[ledgers.LedgerMemberInviteHandler] cannot send email to \"%s\", because %s", invitee.Email, err.Error())
+				}
+			}()
+		}
+	}
+
+	return member.ToLedgerMemberInfoResponse(invitee), nil
+}
+
+// LedgerMemberModifyHandler modifies the role of an existed shared ledger member
+func (a *LedgersApi) LedgerMemberModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberModifyReq models.LedgerMemberModifyRequest
+	err := c.ShouldBindJSON(&memberModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerMemberModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !memberModifyReq.Role.CanBeGrantedToMember() {
+		return nil, errs.ErrLedgerMemberRoleInvalid
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	member, err := a.ledgers.GetMemberByMemberId(c, memberModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberModifyHandler] failed to get ledger member \"id:%d\" for user \"uid:%d\", because %s", memberModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ledger, currentMember, err := a.getLedgerAndCurrentMember(c, member.LedgerId, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberModifyHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", member.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if !currentMember.Role.CanManageMembers() {
+		log.Warnf(c, "[ledgers.LedgerMemberModifyHandler] user \"uid:%d\" cannot modify members of ledger \"id:%d\"", uid, ledger.LedgerId)
+		return nil, errs.ErrLedgerOperationNotPermitted
+	}
+
+	if member.Uid == ledger.Uid {
+		return nil, errs.ErrCannotModifyLedgerCreator
+	}
+
+	if member.Role == memberModifyReq.Role {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	member.Role = memberModifyReq.Role
+	err = a.ledgers.ModifyMemberRole(c, member)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberModifyHandler] failed to update ledger member \"id:%d\" for user \"uid:%d\", because %s", member.MemberId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerMemberModifyHandler] user \"uid:%d\" has updated the role of ledger member \"id:%d\" to \"%d\" successfully", uid, member.MemberId, member.Role)
+
+	return true, nil
+}
+
+// LedgerMemberRemoveHandler removes an existed shared ledger member, or lets current user leave the shared ledger
+func (a *LedgersApi) LedgerMemberRemoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var memberRemoveReq models.LedgerMemberRemoveRequest
+	err := c.ShouldBindJSON(&memberRemoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerMemberRemoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	member, err := a.ledgers.GetMemberByMemberId(c, memberRemoveReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberRemoveHandler] failed to get ledger member \"id:%d\" for user \"uid:%d\", because %s", memberRemoveReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ledger, currentMember, err := a.getLedgerAndCurrentMember(c, member.LedgerId, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberRemoveHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", member.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if member.Uid != uid && !currentMember.Role.CanManageMembers() {
+		log.Warnf(c, "[ledgers.LedgerMemberRemoveHandler] user \"uid:%d\" cannot remove members of ledger \"id:%d\"", uid, ledger.LedgerId)
+		return nil, errs.ErrLedgerOperationNotPermitted
+	}
+
+	if member.Uid == ledger.Uid {
+		return nil, errs.ErrCannotModifyLedgerCreator
+	}
+
+	err = a.ledgers.RemoveMember(c, ledger.LedgerId, member.MemberId)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberRemoveHandler] failed to remove ledger member \"id:%d\" for user \"uid:%d\", because %s", member.MemberId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerMemberRemoveHandler] user \"uid:%d\" has removed ledger member \"id:%d\" from ledger \"id:%d\"", uid, member.MemberId, ledger.LedgerId)
+	return true, nil
+}
+
+// LedgerMemberStatisticsHandler returns the income and expense statistics of every member in every account of the shared ledger
+func (a *LedgersApi) LedgerMemberStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
+	var statisticsReq models.LedgerMemberStatisticsRequest
+	err := c.ShouldBindQuery(&statisticsReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerMemberStatisticsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	ledger, _, err := a.getLedgerAndCurrentMember(c, statisticsReq.Id, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberStatisticsHandler] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", statisticsReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	totalAmounts, err := a.transactions.GetCreatorsTotalIncomeAndExpense(c, ledger.BookUid, statisticsReq.StartTime, statisticsReq.EndTime)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberStatisticsHandler] failed to get member statistics of ledger \"id:%d\" for user \"uid:%d\", because %s", ledger.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	creatorUids := make([]int64, 0)
+	statisticResps := make([]*models.LedgerMemberStatisticResponse, 0)
+	statisticRespMap := make(map[int64]*models.LedgerMemberStatisticResponse)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmount := totalAmounts[i]
+		statisticResp, exists := statisticRespMap[totalAmount.CreatorUid]
+
+		if !exists {
+			statisticResp = &models.LedgerMemberStatisticResponse{
+				Uid:      totalAmount.CreatorUid,
+				Accounts: make([]*models.LedgerMemberAccountStatisticResponse, 0),
+			}
+
+			statisticRespMap[totalAmount.CreatorUid] = statisticResp
+			statisticResps = append(statisticResps, statisticResp)
+			creatorUids = append(creatorUids, totalAmount.CreatorUid)
+		}
+
+		statisticResp.Accounts = append(statisticResp.Accounts, &models.LedgerMemberAccountStatisticResponse{
+			AccountId:        totalAmount.AccountId,
+			IncomeAmount:     totalAmount.TotalIncomeAmount,
+			ExpenseAmount:    totalAmount.TotalExpenseAmount,
+			TransactionCount: totalAmount.TransactionCount,
+		})
+	}
+
+	users, err := a.users.GetUsersByUids(c, creatorUids)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerMemberStatisticsHandler] failed to get member users of ledger \"id:%d\" for user \"uid:%d\", because %s", ledger.LedgerId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	for i := 0; i < len(statisticResps); i++ {
+		if user, exists := users[statisticResps[i].Uid]; exists {
+			statisticResps[i].Nickname = user.Nickname
+		}
+	}
+
+	return statisticResps, nil
+}
+
+// LedgerInvitationListHandler returns all pending shared ledger invitations of current user
+func (a *LedgersApi) LedgerInvitationListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentOperatorUid()
+	invitations, err := a.ledgers.GetPendingInvitationsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerInvitationListHandler] failed to get ledger invitations for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ledgerIds := make([]int64, len(invitations))
+	inviterUids := make([]int64, len(invitations))
+
+	for i := 0; i < len(invitations); i++ {
+		ledgerIds[i] = invitations[i].LedgerId
+		inviterUids[i] = invitations[i].InviterUid
+	}
+
+	ledgers, err := a.ledgers.GetLedgersByLedgerIds(c, ledgerIds)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerInvitationListHandler] failed to get ledgers for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	inviters, err := a.users.GetUsersByUids(c, inviterUids)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerInvitationListHandler] failed to get inviters for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	invitationResps := make([]*models.LedgerInvitationInfoResponse, 0, len(invitations))
+
+	for i := 0; i < len(invitations); i++ {
+		invitation := invitations[i]
+		ledger, exists := ledgers[invitation.LedgerId]
+
+		if !exists {
+			continue
+		}
+
+		invitationResp := &models.LedgerInvitationInfoResponse{
+			Id:         invitation.MemberId,
+			LedgerId:   ledger.LedgerId,
+			LedgerName: ledger.Name,
+			Role:       invitation.Role,
+			CreatedAt:  invitation.CreatedUnixTime,
+		}
+
+		if inviter, exists := inviters[invitation.InviterUid]; exists {
+			invitationResp.InviterNickname = inviter.Nickname
+		}
+
+		invitationResps = append(invitationResps, invitationResp)
+	}
+
+	return invitationResps, nil
+}
+
+// LedgerInvitationAcceptHandler accepts the shared ledger invitation of current user
+func (a *LedgersApi) LedgerInvitationAcceptHandler(c *core.WebContext) (any, *errs.Error) {
+	var invitationAcceptReq models.LedgerInvitationAcceptRequest
+	err := c.ShouldBindJSON(&invitationAcceptReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerInvitationAcceptHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	err = a.ledgers.AcceptInvitation(c, uid, invitationAcceptReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerInvitationAcceptHandler] failed to accept ledger invitation \"id:%d\" for user \"uid:%d\", because %s", invitationAcceptReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerInvitationAcceptHandler] user \"uid:%d\" has accepted ledger invitation \"id:%d\"", uid, invitationAcceptReq.Id)
+	return true, nil
+}
+
+// LedgerInvitationDeclineHandler declines the shared ledger invitation of current user
+func (a *LedgersApi) LedgerInvitationDeclineHandler(c *core.WebContext) (any, *errs.Error) {
+	var invitationDeclineReq models.LedgerInvitationDeclineRequest
+	err := c.ShouldBindJSON(&invitationDeclineReq)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerInvitationDeclineHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentOperatorUid()
+	err = a.ledgers.DeclineInvitation(c, uid, invitationDeclineReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerInvitationDeclineHandler] failed to decline ledger invitation \"id:%d\" for user \"uid:%d\", because %s", invitationDeclineReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[ledgers.LedgerInvitationDeclineHandler] user \"uid:%d\" has declined ledger invitation \"id:%d\"", uid, invitationDeclineReq.Id)
+	return true, nil
+}
+
+// LedgerInvitationAcceptByTokenHandler accepts the shared ledger invitation by the invitation token in email
+func (a *LedgersApi) LedgerInvitationAcceptByTokenHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentOperatorUid()
+	memberId, err := utils.StringToInt64(c.GetTokenContext())
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerInvitationAcceptByTokenHandler] failed to parse ledger member id in token for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrLedgerInvitationTokenIsInvalidOrExpired
+	}
+
+	err = a.ledgers.AcceptInvitation(c, uid, memberId)
+
+	if err != nil {
+		log.Errorf(c, "[ledgers.LedgerInvitationAcceptByTokenHandler] failed to accept ledger invitation \"id:%d\" for user \"uid:%d\", because %s", memberId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tokenClaims := c.GetTokenClaims()
+	err = a.tokens.DeleteTokenByClaims(c, tokenClaims)
+
+	if err != nil {
+		log.Warnf(c, "[ledgers.LedgerInvitationAcceptByTokenHandler] failed to revoke ledger invitation token \"utid:%s\" for user \"uid:%d\", because %s", tokenClaims.UserTokenId, uid, err.Error())
+	}
+
+	log.Infof(c, "[ledgers.LedgerInvitationAcceptByTokenHandler] user \"uid:%d\" has accepted ledger invitation \"id:%d\"", uid, memberId)
+	return true, nil
+}
+
+func (a *LedgersApi) getLedgerAndCurrentMember(c *core.WebContext, ledgerId int64, uid int64) (*models.Ledger, *models.LedgerMember, error) {
+	ledger, err := a.ledgers.GetLedgerByLedgerId(c, ledgerId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	member, err := a.ledgers.GetAcceptedMember(c, ledger.LedgerId, uid)
+
+	if err == errs.ErrLedgerMemberNotFound {
+		return nil, nil, errs.ErrLedgerNotFound
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	return ledger, member, nil
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// LoansApi represents loan api
+type LoansApi struct {
+	ApiUsingConfig
+	accounts   *services.AccountService
+	categories *services.TransactionCategoryService
+	templates  *services.TransactionTemplateService
+}
+
+// Initialize a loan api singleton instance
+var (
+	Loans = &LoansApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		accounts:   services.Accounts,
+		categories: services.TransactionCategories,
+		templates:  services.TransactionTemplates,
+	}
+)
+
+// LoanGetHandler returns the loan definition, amortization schedule, remaining balance and total interest of specified debt account for current user
+func (a *LoansApi) LoanGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var loanGetReq models.AccountLoanGetRequest
+	err := c.ShouldBindQuery(&loanGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	account, err := a.accounts.GetAccountByAccountId(c, uid, loanGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanGetHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", loanGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if account.Extend == nil || account.Extend.Loan == nil {
+		return nil, errs.ErrLoanNotFound
+	}
+
+	return account.Extend.Loan.ToAccountLoanInfoResponse(account, time.Now().Unix()), nil
+}
+
+// LoanSetHandler saves the loan definition of specified debt account and creates the scheduled payment template for current user
+func (a *LoansApi) LoanSetHandler(c *core.WebContext) (any, *errs.Error) {
+	var loanSetReq models.AccountLoanSetRequest
+	err := c.ShouldBindJSON(&loanSetReq)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanSetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	startTime, err := utils.ParseFromLongDateFirstTime(loanSetReq.StartDate, loanSetReq.UtcOffset)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanSetHandler] failed to parse start date \"%s\", because %s", loanSetReq.StartDate, err.Error())
+		return nil, errs.ErrLoanStartDateInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	account, err := a.accounts.GetAccountByAccountId(c, uid, loanSetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanSetHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", loanSetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if account.Category != models.ACCOUNT_CATEGORY_DEBT || account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		return nil, errs.ErrLoanAccountNotDebtAccount
+	}
+
+	paymentAccount, err := a.accounts.GetAccountByAccountId(c, uid, loanSetReq.PaymentAccountId)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanSetHandler] failed to get payment account \"id:%d\" for user \"uid:%d\", because %s", loanSetReq.PaymentAccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if paymentAccount.AccountId == account.AccountId || paymentAccount.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT || paymentAccount.Hidden {
+		return nil, errs.ErrLoanPaymentAccountInvalid
+	}
+
+	if paymentAccount.Currency != account.Currency {
+		return nil, errs.ErrLoanPaymentAccountCurrencyNotMatch
+	}
+
+	err = a.checkLoanCategory(c, uid, loanSetReq.PrincipalCategoryId, models.CATEGORY_TYPE_TRANSFER, errs.ErrLoanPrincipalCategoryInvalid)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.checkLoanCategory(c, uid, loanSetReq.InterestCategoryId, models.CATEGORY_TYPE_EXPENSE, errs.ErrLoanInterestCategoryInvalid)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	maxOrderId, err := a.templates.GetMaxDisplayOrder(c, uid, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanSetHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	loan := &models.AccountLoanInfo{
+		Principal:           loanSetReq.Principal,
+		AnnualInterestRate:  loanSetReq.AnnualInterestRate,
+		TermMonths:          loanSetReq.TermMonths,
+		PaymentDay:          loanSetReq.PaymentDay,
+		StartTime:           startTime.Unix(),
+		TimezoneUtcOffset:   loanSetReq.UtcOffset,
+		PaymentAccountId:    loanSetReq.PaymentAccountId,
+		PrincipalCategoryId: loanSetReq.PrincipalCategoryId,
+		InterestCategoryId:  loanSetReq.InterestCategoryId,
+	}
+
+	paymentTemplate := loan.ToPaymentTransactionTemplate(uid, account.AccountId, account.Name, maxOrderId+1)
+	err = a.accounts.SetAccountLoan(c, uid, account, loan, paymentTemplate)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanSetHandler] failed to set loan for account \"id:%d\" of user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[loans.LoanSetHandler] user \"uid:%d\" has set loan for account \"id:%d\" with payment template \"id:%d\"", uid, account.AccountId, paymentTemplate.TemplateId)
+
+	return loan.ToAccountLoanInfoResponse(account, time.Now().Unix()), nil
+}
+
+// LoanDeleteHandler removes the loan definition of specified debt account and deletes the scheduled payment template for current user
+func (a *LoansApi) LoanDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var loanDeleteReq models.AccountLoanDeleteRequest
+	err := c.ShouldBindJSON(&loanDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	account, err := a.accounts.GetAccountByAccountId(c, uid, loanDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanDeleteHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", loanDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.accounts.DeleteAccountLoan(c, uid, account)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanDeleteHandler] failed to delete loan of account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[loans.LoanDeleteHandler] user \"uid:%d\" has deleted loan of account \"id:%d\"", uid, account.AccountId)
+	return true, nil
+}
+
+func (a *LoansApi) checkLoanCategory(c *core.WebContext, uid int64, categoryId int64, categoryType models.TransactionCategoryType, invalidError *errs.Error) error {
+	category, err := a.categories.GetCategoryByCategoryId(c, uid, categoryId)
+
+	if err != nil {
+		log.Errorf(c, "[loans.checkLoanCategory] failed to get category \"id:%d\" for user \"uid:%d\", because %s", categoryId, uid, err.Error())
+		return err
+	}
+
+	if category.Type != categoryType || category.ParentCategoryId == models.LevelOneTransactionCategoryParentId || category.Hidden {
+		return invalidError
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+	"net/http"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -14,6 +16,7 @@ import (
 )
 
 const mcpServerName = core.ApplicationName + "-mcp"
+const mcpEventStreamKeepAliveInterval = 30 * time.Second
 
 // ModelContextProtocolAPI represents model context protocol api
 type ModelContextProtocolAPI struct {
@@ -93,12 +96,28 @@ func (a *ModelContextProtocolAPI) InitializeHandler(c *core.WebContext, jsonRPCR
 		protocolVersion = mcp.LatestSupportedMCPVersion
 	}
 
+	session, err := mcp.Sessions.CreateSession(uid, string(protocolVersion))
+
+	if err != nil {
+		log.Errorf(c, "[model_context_protocols.InitializeHandler] failed to create mcp session for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	c.Header(mcp.MCPSessionIdHeaderName, session.SessionId)
+
 	initResp := mcp.MCPInitializeResponse{
 		ProtocolVersion: string(protocolVersion),
 		Capabilities: &mcp.MCPCapabilities{
+			Resources: &mcp.MCPResourceCapabilities{
+				Subscribe:   false,
+				ListChanged: true,
+			},
 			Tools: &mcp.MCPToolCapabilities{
 				ListChanged: false,
 			},
+			Prompts: &mcp.MCPPromptCapabilities{
+				ListChanged: false,
+			},
 		},
 		ServerInfo: &mcp.MCPImplementation{
 			Name:    mcpServerName,
@@ -124,13 +143,55 @@ func (a *ModelContextProtocolAPI) ListResourcesHandler(c *core.WebContext, jsonR
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
+	resources, err := mcp.Container.ListResources(c, user, a.getCurrentTokenScopes(c), a)
+
+	if err != nil {
+		log.Errorf(c, "[model_context_protocols.ListResourcesHandler] failed to list resources for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	listResourcesResp := mcp.MCPListResourcesResponse{
-		Resources: make([]*mcp.MCPResource, 0),
+		Resources: resources,
 	}
 
 	return listResourcesResp, nil
 }
 
+// ListResourceTemplatesHandler returns the list of resource templates for model context protocol
+func (a *ModelContextProtocolAPI) ListResourceTemplatesHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.ListResourceTemplatesHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	tokenScopes := a.getCurrentTokenScopes(c)
+	resourceTemplates := mcp.Container.GetMCPResourceTemplates()
+	finalResourceTemplates := make([]*mcp.MCPResourceTemplate, 0, len(resourceTemplates))
+
+	for i := 0; i < len(resourceTemplates); i++ {
+		requiredScope, exists := mcp.Container.GetMCPResourceRequiredScope(resourceTemplates[i].Name)
+
+		if !exists || !tokenScopes.Contains(requiredScope) {
+			continue
+		}
+
+		finalResourceTemplates = append(finalResourceTemplates, resourceTemplates[i])
+	}
+
+	listResourceTemplatesResp := mcp.MCPListResourceTemplatesResponse{
+		ResourceTemplates: finalResourceTemplates,
+	}
+
+	return listResourceTemplatesResp, nil
+}
+
 // ReadResourceHandler returns the resource details for a specific resource in model context protocol
 func (a *ModelContextProtocolAPI) ReadResourceHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
 	var readResourceReq mcp.MCPReadResourceRequest
@@ -155,7 +216,18 @@ func (a *ModelContextProtocolAPI) ReadResourceHandler(c *core.WebContext, jsonRP
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
-	return nil, errs.ErrApiNotFound
+	if requiredScope, exists := mcp.Container.GetMCPResourceRequiredScopeByURI(readResourceReq.URI); exists && !a.getCurrentTokenScopes(c).Contains(requiredScope) {
+		log.Warnf(c, "[model_context_protocols.ReadResourceHandler] user \"uid:%d\" cannot read resource \"%s\", because current token does not have scope \"%s\"", uid, readResourceReq.URI, requiredScope)
+		return nil, errs.ErrMCPTokenScopeNotPermitted
+	}
+
+	result, err := mcp.Container.ReadResource(c, &readResourceReq, user, a.CurrentConfig(), a)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, nil
 }
 
 // ListToolsHandler returns the list of tools for model context protocol
@@ -173,11 +245,18 @@ func (a *ModelContextProtocolAPI) ListToolsHandler(c *core.WebContext, jsonRPCRe
 	}
 
 	mcpVersion := a.getMCPVersion(c)
+	tokenScopes := a.getCurrentTokenScopes(c)
 	toolsInfo := mcp.Container.GetMCPTools()
-	finalToolsInfos := make([]*mcp.MCPTool, len(toolsInfo))
+	finalToolsInfos := make([]*mcp.MCPTool, 0, len(toolsInfo))
 
 	for i := 0; i < len(toolsInfo); i++ {
-		finalToolsInfos[i] = &mcp.MCPTool{
+		requiredScope, exists := mcp.Container.GetMCPToolRequiredScope(toolsInfo[i].Name)
+
+		if !exists || !tokenScopes.Contains(requiredScope) {
+			continue
+		}
+
+		toolInfo := &mcp.MCPTool{
 			Name:        toolsInfo[i].Name,
 			InputSchema: toolsInfo[i].InputSchema,
 			Title:       toolsInfo[i].Title,
@@ -185,8 +264,10 @@ func (a *ModelContextProtocolAPI) ListToolsHandler(c *core.WebContext, jsonRPCRe
 		}
 
 		if mcpVersion >= string(mcp.ToolResultStructuredContentMinVersion) {
-			finalToolsInfos[i].OutputSchema = toolsInfo[i].OutputSchema
+			toolInfo.OutputSchema = toolsInfo[i].OutputSchema
 		}
+
+		finalToolsInfos = append(finalToolsInfos, toolInfo)
 	}
 
 	listToolsResp := mcp.MCPListToolsResponse{
@@ -220,6 +301,11 @@ func (a *ModelContextProtocolAPI) CallToolHandler(c *core.WebContext, jsonRPCReq
 		return nil, errs.ErrIncompleteOrIncorrectSubmission
 	}
 
+	if requiredScope, exists := mcp.Container.GetMCPToolRequiredScope(callToolReq.Name); exists && !a.getCurrentTokenScopes(c).Contains(requiredScope) {
+		log.Warnf(c, "[model_context_protocols.CallToolHandler] user \"uid:%d\" cannot call tool \"%s\", because current token does not have scope \"%s\"", uid, callToolReq.Name, requiredScope)
+		return nil, errs.ErrMCPTokenScopeNotPermitted
+	}
+
 	result, err := mcp.Container.HandleTool(c, &callToolReq, user, a.CurrentConfig(), a)
 
 	if err != nil {
@@ -229,6 +315,159 @@ func (a *ModelContextProtocolAPI) CallToolHandler(c *core.WebContext, jsonRPCReq
 	return result, nil
 }
 
+// ListPromptsHandler returns the list of prompts for model context protocol
+func (a *ModelContextProtocolAPI) ListPromptsHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.ListPromptsHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	tokenScopes := a.getCurrentTokenScopes(c)
+	prompts := mcp.Container.GetMCPPrompts()
+	finalPrompts := make([]*mcp.MCPPrompt, 0, len(prompts))
+
+	for i := 0; i < len(prompts); i++ {
+		requiredScope, exists := mcp.Container.GetMCPPromptRequiredScope(prompts[i].Name)
+
+		if !exists || !tokenScopes.Contains(requiredScope) {
+			continue
+		}
+
+		finalPrompts = append(finalPrompts, prompts[i])
+	}
+
+	listPromptsResp := mcp.MCPListPromptsResponse{
+		Prompts: finalPrompts,
+	}
+
+	return listPromptsResp, nil
+}
+
+// GetPromptHandler returns the messages of a specific prompt for model context protocol
+func (a *ModelContextProtocolAPI) GetPromptHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.GetPromptHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	var getPromptReq mcp.MCPGetPromptRequest
+
+	if jsonRPCRequest.Params != nil {
+		if err := json.Unmarshal(jsonRPCRequest.Params, &getPromptReq); err != nil {
+			return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	if requiredScope, exists := mcp.Container.GetMCPPromptRequiredScope(getPromptReq.Name); exists && !a.getCurrentTokenScopes(c).Contains(requiredScope) {
+		log.Warnf(c, "[model_context_protocols.GetPromptHandler] user \"uid:%d\" cannot get prompt \"%s\", because current token does not have scope \"%s\"", uid, getPromptReq.Name, requiredScope)
+		return nil, errs.ErrMCPTokenScopeNotPermitted
+	}
+
+	result, err := mcp.Container.GetPrompt(c, &getPromptReq, user, a.CurrentConfig(), a)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, nil
+}
+
+// EventStreamHandler opens an event stream for the mcp session to receive the notifications sent by server
+func (a *ModelContextProtocolAPI) EventStreamHandler(c *core.WebContext) *errs.Error {
+	if !utils.IsEventStreamAccepted(c) {
+		return errs.ErrMethodNotAllowed
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.EventStreamHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return errs.ErrNotPermittedToPerformThisAction
+	}
+
+	sessionId := c.GetHeader(mcp.MCPSessionIdHeaderName)
+
+	if sessionId == "" {
+		return errs.ErrMCPSessionIdIsEmpty
+	}
+
+	notifications, unsubscribe := mcp.Sessions.Subscribe(uid, sessionId)
+
+	if notifications == nil {
+		return errs.ErrMCPSessionNotFound
+	}
+
+	defer unsubscribe()
+
+	utils.SetEventStreamHeader(c)
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	log.Infof(c, "[model_context_protocols.EventStreamHandler] user \"uid:%d\" has opened event stream of mcp session \"%s\"", uid, sessionId)
+
+	keepAliveTicker := time.NewTicker(mcpEventStreamKeepAliveInterval)
+	defer keepAliveTicker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return nil
+		case notification, ok := <-notifications:
+			if !ok {
+				return nil
+			}
+
+			utils.WriteEventStreamJsonSuccessResult(c, notification)
+		case <-keepAliveTicker.C:
+			if mcp.Sessions.GetSession(uid, sessionId) == nil {
+				return nil
+			}
+
+			utils.WriteEventStreamKeepAlive(c)
+		}
+
+		if c.IsAborted() {
+			return nil
+		}
+	}
+}
+
+// DeleteSessionHandler terminates the mcp session
+func (a *ModelContextProtocolAPI) DeleteSessionHandler(c *core.WebContext) (any, *errs.Error) {
+	sessionId := c.GetHeader(mcp.MCPSessionIdHeaderName)
+
+	if sessionId == "" {
+		return nil, errs.ErrMCPSessionIdIsEmpty
+	}
+
+	mcp.Sessions.RemoveSession(c.GetCurrentUid(), sessionId)
+
+	log.Infof(c, "[model_context_protocols.DeleteSessionHandler] user \"uid:%d\" has terminated mcp session \"%s\"", c.GetCurrentUid(), sessionId)
+
+	return true, nil
+}
+
 // PingHandler return the ping response for model context protocol
 func (a *ModelContextProtocolAPI) PingHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
 	return core.O{}, nil
@@ -259,7 +498,44 @@ func (a *ModelContextProtocolAPI) GetUserService() *services.UserService {
 	return a.users
 }
 
-// getMCPVersion returns the MCP protocol version from the request header
+// getMCPVersion returns the MCP protocol version from the request header or the negotiated version of current session
 func (a *ModelContextProtocolAPI) getMCPVersion(c *core.WebContext) string {
-	return c.GetHeader(mcp.MCPProtocolVersionHeaderName)
+	mcpVersion := c.GetHeader(mcp.MCPProtocolVersionHeaderName)
+
+	if mcpVersion != "" {
+		return mcpVersion
+	}
+
+	sessionId := c.GetHeader(mcp.MCPSessionIdHeaderName)
+
+	if sessionId == "" {
+		return ""
+	}
+
+	session := mcp.Sessions.GetSession(c.GetCurrentUid(), sessionId)
+
+	if session == nil {
+		return ""
+	}
+
+	return session.ProtocolVersion
+}
+
+// getCurrentTokenScopes returns the scopes of current mcp token, the token created without scopes has all the scopes
+func (a *ModelContextProtocolAPI) getCurrentTokenScopes(c *core.WebContext) core.MCPTokenScopes {
+	tokenContext := c.GetTokenContext()
+
+	if tokenContext == "" {
+		return core.AllMCPTokenScopes
+	}
+
+	var mcpTokenContext models.MCPTokenContext
+	err := json.Unmarshal([]byte(tokenContext), &mcpTokenContext)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.getCurrentTokenScopes] failed to parse mcp token context, because %s", err.Error())
+		return core.MCPTokenScopes{}
+	}
+
+	return mcpTokenContext.Scopes
 }
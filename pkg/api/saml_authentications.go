@@ -0,0 +1,368 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/auth/saml"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// SAMLAuthenticationApi represents SAML 2.0 authentication api
+type SAMLAuthenticationApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	users             *services.UserService
+	tokens            *services.TokenService
+	userExternalAuths *services.UserExternalAuthService
+}
+
+// Initialize a SAML 2.0 authentication api singleton instance
+var (
+	SAMLAuthentications = &SAMLAuthenticationApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		users:             services.Users,
+		tokens:            services.Tokens,
+		userExternalAuths: services.UserExternalAuths,
+	}
+)
+
+// MetadataHandler returns the SAML 2.0 service provider metadata
+func (a *SAMLAuthenticationApi) MetadataHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	metadata, err := saml.GetSAMLMetadata(c)
+
+	if err != nil {
+		log.Errorf(c, "[saml_authentications.MetadataHandler] failed to generate saml metadata, because %s", err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return metadata, "", nil
+}
+
+// LoginHandler handles user login request via SAML 2.0
+func (a *SAMLAuthenticationApi) LoginHandler(c *core.WebContext) (string, *errs.Error) {
+	var samlLoginReq models.SAMLLoginRequest
+	err := c.ShouldBindQuery(&samlLoginReq)
+
+	if err != nil {
+		log.Warnf(c, "[saml_authentications.LoginHandler] parse request failed, because %s", err.Error())
+		return a.redirectToFailedCallbackPage(c, errs.NewIncompleteOrIncorrectSubmissionError(err))
+	}
+
+	if samlLoginReq.Platform != "mobile" && samlLoginReq.Platform != "desktop" {
+		return a.redirectToFailedCallbackPage(c, errs.ErrInvalidSAMLLoginRequest)
+	}
+
+	if strings.Contains(samlLoginReq.ClientSessionId, "|") {
+		return a.redirectToFailedCallbackPage(c, errs.ErrInvalidSAMLLoginRequest)
+	}
+
+	found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_SAML_REDIRECT, 0, samlLoginReq.ClientSessionId)
+
+	if found {
+		log.Errorf(c, "[saml_authentications.LoginHandler] another saml request \"%s\" has been processing for client session id \"%s\"", remark, samlLoginReq.ClientSessionId)
+		return a.redirectToFailedCallbackPage(c, errs.ErrRepeatedRequest)
+	}
+
+	uid := int64(0)
+
+	if samlLoginReq.Token != "" {
+		_, claims, _, err := a.tokens.ParseToken(c, samlLoginReq.Token)
+
+		if err != nil {
+			log.Errorf(c, "[saml_authentications.LoginHandler] failed to parse token, because %s", err.Error())
+			return a.redirectToFailedCallbackPage(c, errs.ErrInvalidToken)
+		}
+
+		uid = claims.Uid
+		user, err := a.users.GetUserById(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[saml_authentications.LoginHandler] failed to get user by id %d, because %s", uid, err.Error())
+			return a.redirectToFailedCallbackPage(c, errs.Or(err, errs.ErrOperationFailed))
+		}
+
+		if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_SAML_LOGIN) {
+			return a.redirectToFailedCallbackPage(c, errs.ErrNotPermittedToPerformThisAction)
+		}
+	}
+
+	verifier, err := utils.GetRandomNumberOrLowercaseLetter(64)
+
+	if err != nil {
+		log.Errorf(c, "[saml_authentications.LoginHandler] failed to generate random string for saml relay state, because %s", err.Error())
+		return a.redirectToFailedCallbackPage(c, errs.ErrSystemError)
+	}
+
+	relayState := fmt.Sprintf("%s|%s|%s", samlLoginReq.Platform, samlLoginReq.ClientSessionId, verifier)
+	redirectUrl, requestId, err := saml.GetSAMLAuthnRequestUrl(c, relayState)
+
+	if err != nil {
+		log.Errorf(c, "[saml_authentications.LoginHandler] failed to get saml authentication request url, because %s", err.Error())
+		return a.redirectToFailedCallbackPage(c, errs.Or(err, errs.ErrSystemError))
+	}
+
+	remark = fmt.Sprintf("%s|%s|%d|%s|%s", samlLoginReq.Platform, samlLoginReq.ClientSessionId, uid, verifier, requestId)
+	a.SetSubmissionRemarkWithCustomExpiration(duplicatechecker.DUPLICATE_CHECKER_TYPE_SAML_REDIRECT, 0, samlLoginReq.ClientSessionId, remark, a.CurrentConfig().SAMLStateExpiredTimeDuration)
+
+	return redirectUrl, nil
+}
+
+// AssertionConsumerHandler handles the SAML 2.0 response posted by identity provider
+func (a *SAMLAuthenticationApi) AssertionConsumerHandler(c *core.WebContext) (string, *errs.Error) {
+	var samlAcsReq models.SAMLAssertionConsumerRequest
+	err := c.ShouldBind(&samlAcsReq)
+
+	if err != nil {
+		log.Warnf(c, "[saml_authentications.AssertionConsumerHandler] parse request failed, because %s", err.Error())
+		return a.redirectToFailedCallbackPage(c, errs.NewIncompleteOrIncorrectSubmissionError(err))
+	}
+
+	if samlAcsReq.SAMLResponse == "" {
+		return a.redirectToFailedCallbackPage(c, errs.ErrMissingSAMLResponse)
+	}
+
+	relayStateParts := strings.Split(samlAcsReq.RelayState, "|")
+
+	if len(relayStateParts) != 3 {
+		return a.redirectToFailedCallbackPage(c, errs.ErrInvalidSAMLRelayState)
+	}
+
+	platform := relayStateParts[0]
+	clientSessionId := relayStateParts[1]
+
+	if platform != "mobile" && platform != "desktop" {
+		return a.redirectToFailedCallbackPage(c, errs.ErrInvalidSAMLLoginRequest)
+	}
+
+	found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_SAML_REDIRECT, 0, clientSessionId)
+
+	if !found {
+		log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] cannot find saml request in duplicate checker for client session id \"%s\"", clientSessionId)
+		return a.redirectToFailedCallbackPage(c, errs.ErrInvalidSAMLRelayState)
+	}
+
+	remarkParts := strings.Split(remark, "|")
+
+	if len(remarkParts) != 5 || remarkParts[0] != platform || remarkParts[1] != clientSessionId || remarkParts[3] != relayStateParts[2] {
+		log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] invalid saml request \"%s\" in duplicate checker for client session id \"%s\"", remark, clientSessionId)
+		return a.redirectToFailedCallbackPage(c, errs.ErrInvalidSAMLRelayState)
+	}
+
+	uid, err := utils.StringToInt64(remarkParts[2])
+
+	if err != nil {
+		log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] invalid uid \"%s\" in saml request \"%s\"", remarkParts[2], remark)
+		return a.redirectToFailedCallbackPage(c, errs.ErrInvalidSAMLRelayState)
+	}
+
+	requestId := remarkParts[4]
+	a.RemoveSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_SAML_REDIRECT, 0, clientSessionId)
+
+	samlUserInfo, err := saml.ParseSAMLResponse(c, samlAcsReq.SAMLResponse, []string{requestId})
+
+	if err != nil {
+		log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to parse saml response, because %s", err.Error())
+		return a.redirectToFailedCallbackPage(c, errs.Or(err, errs.ErrInvalidSAMLResponse))
+	}
+
+	log.Infof(c, "[saml_authentications.AssertionConsumerHandler] saml user info, nameId: %s, userName: %s, email: %s", samlUserInfo.NameID, samlUserInfo.UserName, samlUserInfo.Email)
+
+	if a.CurrentConfig().SAMLUserIdentifier == settings.SAMLUserIdentifierEmail && samlUserInfo.Email == "" {
+		log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] invalid saml user info, email is empty")
+		return a.redirectToFailedCallbackPage(c, errs.ErrSAMLEmailEmpty)
+	}
+
+	if a.CurrentConfig().SAMLUserIdentifier == settings.SAMLUserIdentifierUsername && samlUserInfo.UserName == "" {
+		log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] invalid saml user info, userName is empty")
+		return a.redirectToFailedCallbackPage(c, errs.ErrSAMLUserNameEmpty)
+	}
+
+	userExternalAuthType := core.USER_EXTERNAL_AUTH_TYPE_SAML
+	var userExternalAuth *models.UserExternalAuth
+
+	if a.CurrentConfig().SAMLUserIdentifier == settings.SAMLUserIdentifierEmail {
+		userExternalAuth, err = a.userExternalAuths.GetUserExternalAuthByExternalEmail(c, samlUserInfo.Email, userExternalAuthType)
+	} else if a.CurrentConfig().SAMLUserIdentifier == settings.SAMLUserIdentifierUsername {
+		userExternalAuth, err = a.userExternalAuths.GetUserExternalAuthByExternalUserName(c, samlUserInfo.UserName, userExternalAuthType)
+	} else {
+		return a.redirectToFailedCallbackPage(c, errs.ErrNotSupported)
+	}
+
+	if err != nil && !errors.Is(err, errs.ErrUserExternalAuthNotFound) {
+		log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to get user external auth, because %s", err.Error())
+		return a.redirectToFailedCallbackPage(c, errs.Or(err, errs.ErrOperationFailed))
+	}
+
+	if uid != 0 && userExternalAuth != nil && userExternalAuth.Uid != uid {
+		log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] saml external auth has been bound to another user \"uid:%d\", current user \"uid:%d\"", userExternalAuth.Uid, uid)
+		return a.redirectToFailedCallbackPage(c, errs.ErrSAMLUserAlreadyBoundToAnotherUser)
+	}
+
+	var user *models.User
+
+	if err == nil { // user already bound to external auth, redirect to success page
+		user, err = a.users.GetUserById(c, userExternalAuth.Uid)
+
+		if err != nil {
+			log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to get user by id %d, because %s", userExternalAuth.Uid, err.Error())
+			return a.redirectToFailedCallbackPage(c, errs.Or(err, errs.ErrOperationFailed))
+		}
+	} else { // errors.Is(err, errs.ErrUserExternalAuthNotFound) // user not bound to external auth, try to bind or register new user
+		if uid != 0 {
+			user, err = a.users.GetUserById(c, uid)
+		} else if a.CurrentConfig().SAMLUserIdentifier == settings.SAMLUserIdentifierEmail {
+			user, err = a.users.GetUserByEmail(c, samlUserInfo.Email)
+		} else {
+			user, err = a.users.GetUserByUsername(c, samlUserInfo.UserName)
+		}
+
+		if err != nil && !errors.Is(err, errs.ErrUserNotFound) {
+			log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to get user, because %s", err.Error())
+			return a.redirectToFailedCallbackPage(c, errs.Or(err, errs.ErrOperationFailed))
+		}
+
+		if user == nil && a.CurrentConfig().EnableUserRegister && a.CurrentConfig().SAMLAutoRegister {
+			if samlUserInfo.UserName == "" {
+				return a.redirectToFailedCallbackPage(c, errs.ErrSAMLUserNameEmptyCannotRegister)
+			}
+
+			if samlUserInfo.Email == "" {
+				return a.redirectToFailedCallbackPage(c, errs.ErrSAMLEmailEmptyCannotRegister)
+			}
+
+			nickName := samlUserInfo.NickName
+
+			if nickName == "" {
+				nickName = samlUserInfo.UserName
+			}
+
+			if !utils.IsValidUsername(samlUserInfo.UserName) {
+				return a.redirectToFailedCallbackPage(c, errs.ErrUserNameIsInvalid)
+			}
+
+			if !utils.IsValidEmail(samlUserInfo.Email) {
+				return a.redirectToFailedCallbackPage(c, errs.ErrEmailIsInvalid)
+			}
+
+			if !utils.IsValidNickName(nickName) {
+				return a.redirectToFailedCallbackPage(c, errs.ErrNickNameIsInvalid)
+			}
+
+			user = &models.User{
+				Username:             samlUserInfo.UserName,
+				Email:                samlUserInfo.Email,
+				Nickname:             nickName,
+				DefaultCurrency:      "USD",
+				FiscalYearStart:      core.FISCAL_YEAR_START_DEFAULT,
+				TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_ALL,
+				FeatureRestriction:   a.CurrentConfig().DefaultFeatureRestrictions,
+			}
+
+			if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_SAML_LOGIN) {
+				return a.redirectToFailedCallbackPage(c, errs.ErrNotPermittedToPerformThisAction)
+			}
+
+			err = a.users.CreateUser(c, user, true)
+
+			if err != nil {
+				log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to create user \"%s\", because %s", user.Username, err.Error())
+				return a.redirectToFailedCallbackPage(c, errs.Or(err, errs.ErrOperationFailed))
+			}
+
+			log.Infof(c, "[saml_authentications.AssertionConsumerHandler] user \"%s\" has registered successfully, uid is %d", user.Username, user.Uid)
+
+			userExternalAuth = &models.UserExternalAuth{
+				Uid:              user.Uid,
+				ExternalAuthType: userExternalAuthType,
+				ExternalUsername: samlUserInfo.UserName,
+				ExternalEmail:    samlUserInfo.Email,
+			}
+
+			err = a.userExternalAuths.CreateUserExternalAuth(c, userExternalAuth)
+
+			if err != nil {
+				log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to create user external auth for user \"uid:%d\", because %s", user.Uid, err.Error())
+				return a.redirectToFailedCallbackPage(c, errs.Or(err, errs.ErrOperationFailed))
+			}
+
+			log.Infof(c, "[saml_authentications.AssertionConsumerHandler] user external auth has been created for user \"uid:%d\"", user.Uid)
+		} else if user == nil {
+			return a.redirectToFailedCallbackPage(c, errs.ErrSAMLAutoRegistrationNotEnabled)
+		}
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_SAML_LOGIN) {
+		return a.redirectToFailedCallbackPage(c, errs.ErrNotPermittedToPerformThisAction)
+	}
+
+	if userExternalAuth == nil {
+		tokenContext, err := json.Marshal(&models.OAuth2CallbackTokenContext{
+			ExternalAuthType: userExternalAuthType,
+			ExternalUsername: samlUserInfo.UserName,
+			ExternalEmail:    samlUserInfo.Email,
+		})
+
+		if err != nil {
+			log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to marshal saml callback verify token context, because %s", err.Error())
+			return a.redirectToFailedCallbackPage(c, errs.ErrOperationFailed)
+		}
+
+		token, _, err := a.tokens.CreateOAuth2CallbackRequireVerifyToken(c, user, string(tokenContext))
+
+		if err != nil {
+			log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to create saml callback verify token, because %s", err.Error())
+			return a.redirectToFailedCallbackPage(c, errs.ErrTokenGenerating)
+		}
+
+		return a.redirectToVerifyCallbackPage(c, platform, userExternalAuthType, user.Username, token)
+	} else {
+		tokenContext, err := json.Marshal(&models.OAuth2CallbackTokenContext{
+			ExternalAuthType: userExternalAuthType,
+		})
+
+		if err != nil {
+			log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to marshal saml callback token context, because %s", err.Error())
+			return a.redirectToFailedCallbackPage(c, errs.ErrOperationFailed)
+		}
+
+		token, _, err := a.tokens.CreateOAuth2CallbackToken(c, user, string(tokenContext))
+
+		if err != nil {
+			log.Errorf(c, "[saml_authentications.AssertionConsumerHandler] failed to create saml callback token, because %s", err.Error())
+			return a.redirectToFailedCallbackPage(c, errs.ErrTokenGenerating)
+		}
+
+		return a.redirectToSuccessCallbackPage(c, platform, userExternalAuthType, token)
+	}
+}
+
+func (a *SAMLAuthenticationApi) redirectToSuccessCallbackPage(c *core.WebContext, platform string, externalAuthType core.UserExternalAuthType, token string) (string, *errs.Error) {
+	return fmt.Sprintf(oauth2CallbackPageUrlSuccessFormat, a.CurrentConfig().RootUrl, platform, externalAuthType, url.QueryEscape(token)), nil
+}
+
+func (a *SAMLAuthenticationApi) redirectToVerifyCallbackPage(c *core.WebContext, platform string, externalAuthType core.UserExternalAuthType, userName string, token string) (string, *errs.Error) {
+	return fmt.Sprintf(oauth2CallbackPageUrlNeedVerifyFormat, a.CurrentConfig().RootUrl, platform, externalAuthType, userName, url.QueryEscape(token)), nil
+}
+
+func (a *SAMLAuthenticationApi) redirectToFailedCallbackPage(c *core.WebContext, err *errs.Error) (string, *errs.Error) {
+	return fmt.Sprintf(oauth2CallbackPageUrlFailedFormat, a.CurrentConfig().RootUrl, err.Code(), url.QueryEscape(utils.GetDisplayErrorMessage(err))), nil
+}
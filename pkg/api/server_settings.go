@@ -41,7 +41,10 @@ func (a *ServerSettingsApi) ServerSettingsJavascriptHandler(c *core.WebContext)
 
 	a.appendBooleanSetting(builder, "a", config.EnableInternalAuth)
 	a.appendBooleanSetting(builder, "o", config.EnableOAuth2Login)
+	a.appendBooleanSetting(builder, "l", config.EnableLDAPLogin)
+	a.appendBooleanSetting(builder, "sa", config.EnableSAMLLogin)
 	a.appendBooleanSetting(builder, "r", config.EnableInternalAuth && config.EnableUserRegister)
+	a.appendBooleanSetting(builder, "pk", config.EnableInternalAuth && config.EnablePasskey)
 	a.appendBooleanSetting(builder, "f", config.EnableInternalAuth && config.EnableUserForgetPassword)
 	a.appendBooleanSetting(builder, "t", config.EnableAPIToken)
 	a.appendBooleanSetting(builder, "v", config.EnableUserVerifyEmail)
@@ -61,6 +64,10 @@ func (a *ServerSettingsApi) ServerSettingsJavascriptHandler(c *core.WebContext)
 		a.appendBooleanSetting(builder, "mcp", config.EnableMCPServer)
 	}
 
+	if config.EnableWebhook {
+		a.appendBooleanSetting(builder, "wh", config.EnableWebhook)
+	}
+
 	if config.TextRecognitionLLMConfig != nil && config.TextRecognitionLLMConfig.LLMProvider != "" {
 		if config.TransactionFromAITextRecognition {
 			a.appendBooleanSetting(builder, "llmtr", config.TransactionFromAITextRecognition)
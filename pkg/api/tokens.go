@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"sort"
 	"time"
 
@@ -113,14 +114,44 @@ func (a *TokensApi) TokenGenerateAPIHandler(c *core.WebContext) (any, *errs.Erro
 		return nil, errs.ErrUserPasswordWrong
 	}
 
-	token, claims, err := a.tokens.CreateAPIToken(c, user, generateAPITokenReq.ExpiredInSeconds)
+	apiTokenScopes := make(core.APITokenScopes, 0, len(generateAPITokenReq.Scopes))
+
+	for i := 0; i < len(generateAPITokenReq.Scopes); i++ {
+		scope := generateAPITokenReq.Scopes[i]
+
+		if !scope.IsValid() {
+			log.Warnf(c, "[tokens.TokenGenerateAPIHandler] api token scope \"%s\" is invalid", scope)
+			return nil, errs.ErrAPITokenScopeInvalid
+		}
+
+		if !apiTokenScopes.Contains(scope) {
+			apiTokenScopes = append(apiTokenScopes, scope)
+		}
+	}
+
+	tokenContext := ""
+
+	if len(apiTokenScopes) > 0 {
+		tokenContextBytes, err := json.Marshal(&models.APITokenContext{
+			Scopes: apiTokenScopes,
+		})
+
+		if err != nil {
+			log.Errorf(c, "[tokens.TokenGenerateAPIHandler] failed to marshal api token context, because %s", err.Error())
+			return nil, errs.ErrTokenGenerating
+		}
+
+		tokenContext = string(tokenContextBytes)
+	}
+
+	token, claims, err := a.tokens.CreateAPIToken(c, user, generateAPITokenReq.ExpiredInSeconds, tokenContext)
 
 	if err != nil {
 		log.Errorf(c, "[tokens.TokenGenerateAPIHandler] failed to create api token for user \"uid:%d\", because %s", user.Uid, err.Error())
 		return nil, errs.Or(err, errs.ErrTokenGenerating)
 	}
 
-	log.Infof(c, "[tokens.TokenGenerateAPIHandler] user \"uid:%d\" has generated api token, new token will be expired at %d", user.Uid, claims.ExpiresAt)
+	log.Infof(c, "[tokens.TokenGenerateAPIHandler] user \"uid:%d\" has generated api token with scopes \"%s\", new token will be expired at %d", user.Uid, apiTokenScopes.String(), claims.ExpiresAt)
 
 	generateAPITokenResp := &models.TokenGenerateAPIResponse{
 		Token:      token,
@@ -160,14 +191,42 @@ func (a *TokensApi) TokenGenerateMCPHandler(c *core.WebContext) (any, *errs.Erro
 		return nil, errs.ErrUserPasswordWrong
 	}
 
-	token, claims, err := a.tokens.CreateMCPToken(c, user, generateMCPTokenReq.ExpiredInSeconds)
+	mcpTokenScopes := make(core.MCPTokenScopes, 0, len(generateMCPTokenReq.Scopes))
+
+	for i := 0; i < len(generateMCPTokenReq.Scopes); i++ {
+		scope := generateMCPTokenReq.Scopes[i]
+
+		if !scope.IsValid() {
+			log.Warnf(c, "[tokens.TokenGenerateMCPHandler] mcp token scope \"%s\" is invalid", scope)
+			return nil, errs.ErrMCPTokenScopeInvalid
+		}
+
+		if !mcpTokenScopes.Contains(scope) {
+			mcpTokenScopes = append(mcpTokenScopes, scope)
+		}
+	}
+
+	if len(mcpTokenScopes) < 1 {
+		mcpTokenScopes = core.AllMCPTokenScopes
+	}
+
+	tokenContext, err := json.Marshal(&models.MCPTokenContext{
+		Scopes: mcpTokenScopes,
+	})
+
+	if err != nil {
+		log.Errorf(c, "[tokens.TokenGenerateMCPHandler] failed to marshal mcp token context, because %s", err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	token, claims, err := a.tokens.CreateMCPToken(c, user, generateMCPTokenReq.ExpiredInSeconds, string(tokenContext))
 
 	if err != nil {
 		log.Errorf(c, "[tokens.TokenGenerateMCPHandler] failed to create mcp token for user \"uid:%d\", because %s", user.Uid, err.Error())
 		return nil, errs.Or(err, errs.ErrTokenGenerating)
 	}
 
-	log.Infof(c, "[tokens.TokenGenerateMCPHandler] user \"uid:%d\" has generated mcp token, new token will be expired at %d", user.Uid, claims.ExpiresAt)
+	log.Infof(c, "[tokens.TokenGenerateMCPHandler] user \"uid:%d\" has generated mcp token with scopes \"%s\", new token will be expired at %d", user.Uid, mcpTokenScopes.String(), claims.ExpiresAt)
 
 	generateMCPTokenResp := &models.TokenGenerateMCPResponse{
 		Token:  token,
@@ -0,0 +1,537 @@
+package api
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maximumTagsCountOfTransactionRule = 10
+const pageCountForApplyingTransactionRules = 1000
+
+// TransactionRulesApi represents transaction rule api
+type TransactionRulesApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	rules                 *services.TransactionRuleService
+	transactions          *services.TransactionService
+	transactionCategories *services.TransactionCategoryService
+	transactionTags       *services.TransactionTagService
+	transactionSplits     *services.TransactionSplitService
+	accounts              *services.AccountService
+}
+
+// Initialize a transaction rule api singleton instance
+var (
+	TransactionRules = &TransactionRulesApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		rules:                 services.TransactionRules,
+		transactions:          services.Transactions,
+		transactionCategories: services.TransactionCategories,
+		transactionTags:       services.TransactionTags,
+		transactionSplits:     services.TransactionSplits,
+		accounts:              services.Accounts,
+	}
+)
+
+// RuleListHandler returns transaction rule list of current user
+func (a *TransactionRulesApi) RuleListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	rules, err := a.rules.GetAllRulesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleListHandler] failed to get transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleResps := make(models.TransactionRuleInfoResponseSlice, len(rules))
+
+	for i := 0; i < len(rules); i++ {
+		ruleResps[i] = rules[i].ToTransactionRuleInfoResponse()
+	}
+
+	sort.Sort(ruleResps)
+
+	return ruleResps, nil
+}
+
+// RuleGetHandler returns one specific transaction rule of current user
+func (a *TransactionRulesApi) RuleGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleGetReq models.TransactionRuleGetRequest
+	err := c.ShouldBindQuery(&ruleGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ruleGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleGetHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return rule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleCreateHandler saves a new transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleCreateReq models.TransactionRuleCreateRequest
+	err := c.ShouldBindJSON(&ruleCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if len(ruleCreateReq.TagIds) > maximumTagsCountOfTransactionRule {
+		return nil, errs.ErrTransactionRuleHasTooManyTags
+	}
+
+	uid := c.GetCurrentUid()
+
+	maxOrderId, err := a.rules.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	rule := &models.TransactionRule{
+		Uid:                  uid,
+		Name:                 ruleCreateReq.Name,
+		TransactionType:      ruleCreateReq.TransactionType,
+		DescriptionPattern:   ruleCreateReq.DescriptionPattern,
+		CounterpartyPattern:  ruleCreateReq.CounterpartyPattern,
+		MinAmount:            ruleCreateReq.MinAmount,
+		MaxAmount:            ruleCreateReq.MaxAmount,
+		AccountId:            ruleCreateReq.AccountId,
+		CategoryId:           ruleCreateReq.CategoryId,
+		TagIds:               strings.Join(ruleCreateReq.TagIds, ","),
+		DestinationAccountId: ruleCreateReq.DestinationAccountId,
+		NewDescription:       ruleCreateReq.NewDescription,
+		DisplayOrder:         maxOrderId + 1,
+	}
+
+	err = a.checkRule(c, uid, rule)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && ruleCreateReq.ClientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION_RULE, uid, ruleCreateReq.ClientSessionId)
+
+		if found {
+			log.Infof(c, "[transaction_rules.RuleCreateHandler] another transaction rule \"id:%s\" has been created for user \"uid:%d\"", remark, uid)
+			ruleId, err := utils.StringToInt64(remark)
+
+			if err == nil {
+				rule, err = a.rules.GetRuleByRuleId(c, uid, ruleId)
+
+				if err != nil {
+					log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to get existed transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
+				return rule.ToTransactionRuleInfoResponse(), nil
+			}
+		}
+	}
+
+	err = a.rules.CreateRule(c, rule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to create transaction rule \"id:%d\" for user \"uid:%d\", because %s", rule.RuleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleCreateHandler] user \"uid:%d\" has created a new transaction rule \"id:%d\" successfully", uid, rule.RuleId)
+
+	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION_RULE, uid, ruleCreateReq.ClientSessionId, utils.Int64ToString(rule.RuleId))
+
+	return rule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleModifyHandler saves an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleModifyReq models.TransactionRuleModifyRequest
+	err := c.ShouldBindJSON(&ruleModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if len(ruleModifyReq.TagIds) > maximumTagsCountOfTransactionRule {
+		return nil, errs.ErrTransactionRuleHasTooManyTags
+	}
+
+	uid := c.GetCurrentUid()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ruleModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newRule := &models.TransactionRule{
+		RuleId:               rule.RuleId,
+		Uid:                  uid,
+		Name:                 ruleModifyReq.Name,
+		TransactionType:      ruleModifyReq.TransactionType,
+		DescriptionPattern:   ruleModifyReq.DescriptionPattern,
+		CounterpartyPattern:  ruleModifyReq.CounterpartyPattern,
+		MinAmount:            ruleModifyReq.MinAmount,
+		MaxAmount:            ruleModifyReq.MaxAmount,
+		AccountId:            ruleModifyReq.AccountId,
+		CategoryId:           ruleModifyReq.CategoryId,
+		TagIds:               strings.Join(ruleModifyReq.TagIds, ","),
+		DestinationAccountId: ruleModifyReq.DestinationAccountId,
+		NewDescription:       ruleModifyReq.NewDescription,
+		DisplayOrder:         rule.DisplayOrder,
+		Hidden:               ruleModifyReq.Hidden,
+	}
+
+	if newRule.Name == rule.Name &&
+		newRule.TransactionType == rule.TransactionType &&
+		newRule.DescriptionPattern == rule.DescriptionPattern &&
+		newRule.CounterpartyPattern == rule.CounterpartyPattern &&
+		a.isAmountEquals(newRule.MinAmount, rule.MinAmount) &&
+		a.isAmountEquals(newRule.MaxAmount, rule.MaxAmount) &&
+		newRule.AccountId == rule.AccountId &&
+		newRule.CategoryId == rule.CategoryId &&
+		newRule.TagIds == rule.TagIds &&
+		newRule.DestinationAccountId == rule.DestinationAccountId &&
+		newRule.NewDescription == rule.NewDescription &&
+		newRule.Hidden == rule.Hidden {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.checkRule(c, uid, newRule)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.rules.ModifyRule(c, newRule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to update transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleModifyHandler] user \"uid:%d\" has updated transaction rule \"id:%d\" successfully", uid, ruleModifyReq.Id)
+
+	return newRule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleHideHandler hides a transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleHideHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleHideReq models.TransactionRuleHideRequest
+	err := c.ShouldBindJSON(&ruleHideReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleHideHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.rules.HideRule(c, uid, []int64{ruleHideReq.Id}, ruleHideReq.Hidden)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleHideHandler] failed to hide transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleHideReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleHideHandler] user \"uid:%d\" has hidden transaction rule \"id:%d\"", uid, ruleHideReq.Id)
+	return true, nil
+}
+
+// RuleMoveHandler moves display order of existed transaction rules by request parameters for current user
+func (a *TransactionRulesApi) RuleMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleMoveReq models.TransactionRuleMoveRequest
+	err := c.ShouldBindJSON(&ruleMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	rules := make([]*models.TransactionRule, len(ruleMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(ruleMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := ruleMoveReq.NewDisplayOrders[i]
+		rule := &models.TransactionRule{
+			Uid:          uid,
+			RuleId:       newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		rules[i] = rule
+	}
+
+	err = a.rules.ModifyRuleDisplayOrders(c, uid, rules)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleMoveHandler] failed to move transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleMoveHandler] user \"uid:%d\" has moved transaction rules", uid)
+	return true, nil
+}
+
+// RuleDeleteHandler deletes an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleDeleteReq models.TransactionRuleDeleteRequest
+	err := c.ShouldBindJSON(&ruleDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.rules.DeleteRule(c, uid, ruleDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleDeleteHandler] failed to delete transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleDeleteHandler] user \"uid:%d\" has deleted transaction rule \"id:%d\"", uid, ruleDeleteReq.Id)
+	return true, nil
+}
+
+// RuleApplyHandler applies the specified transaction rule or all enabled transaction rules to the existed transactions of current user
+func (a *TransactionRulesApi) RuleApplyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleApplyReq models.TransactionRuleApplyRequest
+	err := c.ShouldBindJSON(&ruleApplyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleApplyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	var rules []*models.TransactionRule
+
+	if ruleApplyReq.Id > 0 {
+		rule, err := a.rules.GetRuleByRuleId(c, uid, ruleApplyReq.Id)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleApplyReq.Id, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		// the specified rule is applied even if it is disabled
+		rule.Hidden = false
+		rules = append(rules, rule)
+	} else {
+		rules, err = a.rules.GetAllRulesByUid(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tags, err := a.transactionTags.GetAllTagsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleEngine := models.NewTransactionRuleEngine(rules, a.transactionCategories.GetCategoryMapByList(categories), a.accounts.GetAccountMapByList(accounts), a.transactionTags.GetTagMapByList(tags))
+	result := &models.TransactionRuleApplyResponse{}
+
+	if ruleEngine.RuleCount() < 1 {
+		return result, nil
+	}
+
+	transactions, err := a.transactions.GetAllTransactions(c, uid, pageCountForApplyingTransactionRules, true)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allTransactionTagIds, err := a.transactionTags.GetAllTagIdsMapOfAllTransactions(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get transaction tag ids for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allTransactionSplits, err := a.transactionSplits.GetAllSplitsOfAllTransactions(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleApplyHandler] failed to get transaction splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		currentTagIds := allTransactionTagIds[transaction.TransactionId]
+		changed, addTagIds := ruleEngine.ApplyToTransaction(transaction, currentTagIds, len(allTransactionSplits[transaction.TransactionId]) > 0)
+
+		if !changed {
+			continue
+		}
+
+		err = a.transactions.ModifyTransaction(c, transaction, false, len(currentTagIds), addTagIds, nil, nil, nil, nil)
+
+		if err != nil {
+			log.Warnf(c, "[transaction_rules.RuleApplyHandler] failed to apply transaction rules to transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
+			result.FailedCount++
+			continue
+		}
+
+		result.UpdatedCount++
+	}
+
+	log.Infof(c, "[transaction_rules.RuleApplyHandler] user \"uid:%d\" has applied transaction rules to %d transactions, %d transactions failed", uid, result.UpdatedCount, result.FailedCount)
+
+	return result, nil
+}
+
+func (a *TransactionRulesApi) checkRule(c *core.WebContext, uid int64, rule *models.TransactionRule) error {
+	if rule.TransactionType != 0 && rule.TransactionType != models.TRANSACTION_TYPE_INCOME && rule.TransactionType != models.TRANSACTION_TYPE_EXPENSE && rule.TransactionType != models.TRANSACTION_TYPE_TRANSFER {
+		return errs.ErrTransactionRuleTransactionTypeInvalid
+	}
+
+	if !rule.HasCondition() {
+		return errs.ErrTransactionRuleHasNoCondition
+	}
+
+	if !rule.HasAction() {
+		return errs.ErrTransactionRuleHasNoAction
+	}
+
+	if rule.DescriptionPattern != "" {
+		if _, err := regexp.Compile(rule.DescriptionPattern); err != nil {
+			return errs.ErrTransactionRuleDescriptionPatternInvalid
+		}
+	}
+
+	if rule.CounterpartyPattern != "" {
+		if _, err := regexp.Compile(rule.CounterpartyPattern); err != nil {
+			return errs.ErrTransactionRuleCounterpartyPatternInvalid
+		}
+	}
+
+	if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+		return errs.ErrTransactionRuleAmountRangeInvalid
+	}
+
+	if rule.AccountId > 0 {
+		_, err := a.accounts.GetAccountByAccountId(c, uid, rule.AccountId)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_rules.checkRule] failed to get account \"id:%d\" for user \"uid:%d\", because %s", rule.AccountId, uid, err.Error())
+			return err
+		}
+	}
+
+	if rule.CategoryId > 0 {
+		category, err := a.transactionCategories.GetCategoryByCategoryId(c, uid, rule.CategoryId)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_rules.checkRule] failed to get category \"id:%d\" for user \"uid:%d\", because %s", rule.CategoryId, uid, err.Error())
+			return err
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			return errs.ErrTransactionRuleCategoryInvalid
+		}
+
+		if (rule.TransactionType == models.TRANSACTION_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_INCOME) ||
+			(rule.TransactionType == models.TRANSACTION_TYPE_EXPENSE && category.Type != models.CATEGORY_TYPE_EXPENSE) ||
+			(rule.TransactionType == models.TRANSACTION_TYPE_TRANSFER && category.Type != models.CATEGORY_TYPE_TRANSFER) {
+			return errs.ErrTransactionRuleCategoryInvalid
+		}
+	}
+
+	if rule.DestinationAccountId > 0 {
+		if rule.TransactionType != 0 && rule.TransactionType != models.TRANSACTION_TYPE_TRANSFER {
+			return errs.ErrTransactionRuleDestinationAccountInvalid
+		}
+
+		account, err := a.accounts.GetAccountByAccountId(c, uid, rule.DestinationAccountId)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_rules.checkRule] failed to get destination account \"id:%d\" for user \"uid:%d\", because %s", rule.DestinationAccountId, uid, err.Error())
+			return err
+		}
+
+		if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			return errs.ErrTransactionRuleDestinationAccountInvalid
+		}
+	}
+
+	if rule.TagIds != "" {
+		tagIds, err := utils.StringArrayToInt64Array(strings.Split(rule.TagIds, ","))
+
+		if err != nil {
+			return errs.ErrTransactionRuleTagInvalid
+		}
+
+		tagIds = utils.ToUniqueInt64Slice(tagIds)
+		tagMap, err := a.transactionTags.GetTagsByTagIds(c, uid, tagIds)
+
+		if err != nil {
+			log.Errorf(c, "[transaction_rules.checkRule] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
+			return err
+		}
+
+		if len(tagMap) < len(tagIds) {
+			return errs.ErrTransactionRuleTagInvalid
+		}
+	}
+
+	return nil
+}
+
+func (a *TransactionRulesApi) isAmountEquals(amount1 *int64, amount2 *int64) bool {
+	if amount1 == nil || amount2 == nil {
+		return amount1 == nil && amount2 == nil
+	}
+
+	return *amount1 == *amount2
+}
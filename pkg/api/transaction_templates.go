@@ -16,6 +16,9 @@ import (
 )
 
 const maximumTagsCountOfTemplate = 10
+const maximumScheduledFrequencyLength = 100
+const defaultScheduledOccurrencesPreviewCount = 10
+const maximumScheduledOccurrencesPreviewYears = 20
 
 // TransactionTemplatesApi represents transaction template api
 type TransactionTemplatesApi struct {
@@ -155,6 +158,23 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 		if *templateCreateReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS && templateCreateReq.ScheduledStartDate == nil {
 			return nil, errs.ErrScheduledTransactionStartDateRequired
 		}
+
+		if *templateCreateReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE {
+			rule, err := utils.ParseRecurrenceRule(*templateCreateReq.ScheduledFrequency)
+
+			if err != nil || len(rule.String()) > maximumScheduledFrequencyLength {
+				log.Warnf(c, "[transaction_templates.TemplateCreateHandler] scheduled frequency rule \"%s\" is invalid", *templateCreateReq.ScheduledFrequency)
+				return nil, errs.ErrScheduledTransactionFrequencyInvalid
+			}
+
+			if templateCreateReq.ScheduledStartDate == nil {
+				return nil, errs.ErrScheduledTransactionStartDateRequired
+			}
+		}
+
+		if templateCreateReq.ScheduledAdjustmentPolicy != nil && !templateCreateReq.ScheduledAdjustmentPolicy.IsValid() {
+			return nil, errs.ErrScheduledTransactionAdjustmentPolicyInvalid
+		}
 	}
 
 	if len(templateCreateReq.TagIds) > maximumTagsCountOfTemplate {
@@ -266,6 +286,23 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 		if *templateModifyReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS && templateModifyReq.ScheduledStartDate == nil {
 			return nil, errs.ErrScheduledTransactionStartDateRequired
 		}
+
+		if *templateModifyReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE {
+			rule, err := utils.ParseRecurrenceRule(*templateModifyReq.ScheduledFrequency)
+
+			if err != nil || len(rule.String()) > maximumScheduledFrequencyLength {
+				log.Warnf(c, "[transaction_templates.TemplateModifyHandler] scheduled frequency rule \"%s\" is invalid", *templateModifyReq.ScheduledFrequency)
+				return nil, errs.ErrScheduledTransactionFrequencyInvalid
+			}
+
+			if templateModifyReq.ScheduledStartDate == nil {
+				return nil, errs.ErrScheduledTransactionStartDateRequired
+			}
+		}
+
+		if templateModifyReq.ScheduledAdjustmentPolicy != nil && !templateModifyReq.ScheduledAdjustmentPolicy.IsValid() {
+			return nil, errs.ErrScheduledTransactionAdjustmentPolicyInvalid
+		}
 	}
 
 	if len(templateModifyReq.TagIds) > maximumTagsCountOfTemplate {
@@ -289,10 +326,23 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 
 	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
 		newTemplate.ScheduledFrequencyType = *templateModifyReq.ScheduledFrequencyType
-		newTemplate.ScheduledFrequency = a.getOrderedFrequencyValues(*templateModifyReq.ScheduledFrequency)
+		newTemplate.ScheduledFrequency = a.getScheduledFrequency(newTemplate.ScheduledFrequencyType, *templateModifyReq.ScheduledFrequency)
 		newTemplate.ScheduledAt = a.getUTCScheduledAt(*templateModifyReq.ScheduledTimezoneUtcOffset)
 		newTemplate.ScheduledTimezoneUtcOffset = *templateModifyReq.ScheduledTimezoneUtcOffset
 
+		if templateModifyReq.ScheduledAdjustmentPolicy != nil {
+			newTemplate.ScheduledAdjustmentPolicy = *templateModifyReq.ScheduledAdjustmentPolicy
+		}
+
+		scheduledHolidays, err := a.getNormalizedScheduledHolidays(templateModifyReq.ScheduledHolidays)
+
+		if err != nil {
+			log.Warnf(c, "[transaction_templates.TemplateModifyHandler] failed to parse scheduled holidays for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		newTemplate.ScheduledHolidays = scheduledHolidays
+
 		if templateModifyReq.ScheduledStartDate != nil {
 			startTime, err := utils.ParseFromLongDateFirstTime(*templateModifyReq.ScheduledStartDate, *templateModifyReq.ScheduledTimezoneUtcOffset)
 
@@ -340,7 +390,9 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 				newTemplate.ScheduledStartTime == template.ScheduledStartTime &&
 				newTemplate.ScheduledEndTime == template.ScheduledEndTime &&
 				newTemplate.ScheduledAt == template.ScheduledAt &&
-				newTemplate.ScheduledTimezoneUtcOffset == template.ScheduledTimezoneUtcOffset {
+				newTemplate.ScheduledTimezoneUtcOffset == template.ScheduledTimezoneUtcOffset &&
+				newTemplate.ScheduledAdjustmentPolicy == template.ScheduledAdjustmentPolicy &&
+				newTemplate.ScheduledHolidays == template.ScheduledHolidays {
 				return nil, errs.ErrNothingWillBeUpdated
 			}
 		}
@@ -481,6 +533,240 @@ func (a *TransactionTemplatesApi) TemplateDeleteHandler(c *core.WebContext) (any
 	return true, nil
 }
 
+// TemplateSchedulePreviewHandler returns the next occurrences of one specific scheduled transaction template of current user
+func (a *TransactionTemplatesApi) TemplateSchedulePreviewHandler(c *core.WebContext) (any, *errs.Error) {
+	var previewReq models.TransactionTemplateSchedulePreviewRequest
+	err := c.ShouldBindQuery(&previewReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateSchedulePreviewHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	if previewReq.Count < 1 {
+		previewReq.Count = defaultScheduledOccurrencesPreviewCount
+	}
+
+	uid := c.GetCurrentUid()
+	template, exceptions, err := a.getScheduledTemplateAndExceptions(c, uid, previewReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateSchedulePreviewHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", previewReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	occurrences, err := a.getNextScheduledOccurrences(template, exceptions, time.Now(), previewReq.Count)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateSchedulePreviewHandler] failed to compute occurrences of template \"id:%d\" for user \"uid:%d\", because %s", previewReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	occurrenceResps := make([]*models.TransactionScheduleOccurrenceResponse, len(occurrences))
+
+	for i := 0; i < len(occurrences); i++ {
+		occurrenceResps[i] = occurrences[i].ToTransactionScheduleOccurrenceResponse(template)
+	}
+
+	return occurrenceResps, nil
+}
+
+// TemplateScheduleExceptionListHandler returns all occurrence exceptions of one specific scheduled transaction template of current user
+func (a *TransactionTemplatesApi) TemplateScheduleExceptionListHandler(c *core.WebContext) (any, *errs.Error) {
+	var exceptionListReq models.TransactionScheduleExceptionListRequest
+	err := c.ShouldBindQuery(&exceptionListReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateScheduleExceptionListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+	template, exceptions, err := a.getScheduledTemplateAndExceptions(c, uid, exceptionListReq.TemplateId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateScheduleExceptionListHandler] failed to get exceptions of template \"id:%d\" for user \"uid:%d\", because %s", exceptionListReq.TemplateId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	exceptionResps := make([]*models.TransactionScheduleExceptionInfoResponse, len(exceptions))
+
+	for i := 0; i < len(exceptions); i++ {
+		exceptionResps[i] = exceptions[i].ToTransactionScheduleExceptionInfoResponse(template.Type)
+	}
+
+	return exceptionResps, nil
+}
+
+// TemplateScheduleExceptionCreateHandler skips, postpones or modifies the amount of a single occurrence of scheduled transaction template for current user
+func (a *TransactionTemplatesApi) TemplateScheduleExceptionCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var exceptionCreateReq models.TransactionScheduleExceptionCreateRequest
+	err := c.ShouldBindJSON(&exceptionCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateScheduleExceptionCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	if !exceptionCreateReq.Type.IsValid() {
+		log.Warnf(c, "[transaction_templates.TemplateScheduleExceptionCreateHandler] exception type invalid, type is %d", exceptionCreateReq.Type)
+		return nil, errs.ErrScheduledTransactionExceptionTypeInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, exceptionCreateReq.TemplateId)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateScheduleExceptionCreateHandler] failed to get template \"id:%d\" for user \"uid:%d\", because %s", exceptionCreateReq.TemplateId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if template.TemplateType != models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
+		return nil, errs.ErrTransactionTemplateTypeInvalid
+	}
+
+	occurrenceTime, err := utils.ParseFromLongDateFirstTime(exceptionCreateReq.OccurrenceDate, template.ScheduledTimezoneUtcOffset)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateScheduleExceptionCreateHandler] failed to parse occurrence date for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrScheduledTransactionOccurrenceNotFound
+	}
+
+	isRuleDate, err := template.IsScheduledRuleDate(occurrenceTime)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateScheduleExceptionCreateHandler] failed to check occurrence date of template \"id:%d\" for user \"uid:%d\", because %s", template.TemplateId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if !isRuleDate {
+		return nil, errs.ErrScheduledTransactionOccurrenceNotFound
+	}
+
+	templateTimezone := template.GetScheduledTimezone()
+
+	exception := &models.TransactionScheduleException{
+		Uid:            uid,
+		TemplateId:     template.TemplateId,
+		OccurrenceDate: utils.FormatUnixTimeToNumericYearMonthDay(occurrenceTime.Unix(), templateTimezone),
+		Type:           exceptionCreateReq.Type,
+	}
+
+	if exceptionCreateReq.Type == models.TRANSACTION_SCHEDULE_EXCEPTION_TYPE_POSTPONE {
+		postponedTime, err := utils.ParseFromLongDateFirstTime(exceptionCreateReq.PostponedDate, template.ScheduledTimezoneUtcOffset)
+
+		if err != nil || !postponedTime.After(occurrenceTime) || (template.ScheduledEndTime != nil && postponedTime.Unix() > *template.ScheduledEndTime) {
+			return nil, errs.ErrScheduledTransactionPostponedDateInvalid
+		}
+
+		exception.PostponedDate = utils.FormatUnixTimeToNumericYearMonthDay(postponedTime.Unix(), templateTimezone)
+	} else if exceptionCreateReq.Type == models.TRANSACTION_SCHEDULE_EXCEPTION_TYPE_MODIFY_AMOUNT {
+		exception.Amount = exceptionCreateReq.SourceAmount
+
+		if template.Type == models.TRANSACTION_TYPE_TRANSFER {
+			exception.RelatedAccountAmount = exceptionCreateReq.DestinationAmount
+		}
+	}
+
+	err = a.templates.CreateScheduleException(c, exception)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateScheduleExceptionCreateHandler] failed to create exception of template \"id:%d\" for user \"uid:%d\", because %s", template.TemplateId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_templates.TemplateScheduleExceptionCreateHandler] user \"uid:%d\" has created a new exception \"id:%d\" of template \"id:%d\" successfully", uid, exception.ExceptionId, template.TemplateId)
+
+	return exception.ToTransactionScheduleExceptionInfoResponse(template.Type), nil
+}
+
+// TemplateScheduleExceptionDeleteHandler deletes an existed occurrence exception of scheduled transaction template for current user
+func (a *TransactionTemplatesApi) TemplateScheduleExceptionDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var exceptionDeleteReq models.TransactionScheduleExceptionDeleteRequest
+	err := c.ShouldBindJSON(&exceptionDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateScheduleExceptionDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.templates.DeleteScheduleException(c, uid, exceptionDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateScheduleExceptionDeleteHandler] failed to delete exception \"id:%d\" for user \"uid:%d\", because %s", exceptionDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_templates.TemplateScheduleExceptionDeleteHandler] user \"uid:%d\" has deleted exception \"id:%d\"", uid, exceptionDeleteReq.Id)
+	return true, nil
+}
+
+func (a *TransactionTemplatesApi) getScheduledTemplateAndExceptions(c *core.WebContext, uid int64, templateId int64) (*models.TransactionTemplate, []*models.TransactionScheduleException, error) {
+	template, err := a.templates.GetTemplateByTemplateId(c, uid, templateId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if template.TemplateType != models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
+		return nil, nil, errs.ErrTransactionTemplateTypeInvalid
+	}
+
+	exceptions, err := a.templates.GetScheduleExceptionsByTemplateId(c, uid, templateId)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return template, exceptions, nil
+}
+
+func (a *TransactionTemplatesApi) getNextScheduledOccurrences(template *models.TransactionTemplate, exceptions []*models.TransactionScheduleException, now time.Time, count int) ([]*models.TransactionScheduleOccurrence, error) {
+	result := make([]*models.TransactionScheduleOccurrence, 0, count)
+	fromDate := now.In(template.GetScheduledTimezone())
+
+	for i := 0; i < maximumScheduledOccurrencesPreviewYears && len(result) < count; i++ {
+		if template.ScheduledEndTime != nil && fromDate.Unix() > *template.ScheduledEndTime {
+			break
+		}
+
+		toDate := fromDate.AddDate(1, 0, -1)
+		occurrences, err := template.GetScheduledOccurrences(exceptions, fromDate, toDate)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(occurrences) && len(result) < count; j++ {
+			// the occurrences earlier than now have already been created today
+			if occurrences[j].TransactionUnixTime >= now.Unix() {
+				result = append(result, occurrences[j])
+			}
+		}
+
+		fromDate = toDate.AddDate(0, 0, 1)
+	}
+
+	return result, nil
+}
+
 func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCreateReq *models.TransactionTemplateCreateRequest, order int32) (*models.TransactionTemplate, error) {
 	template := &models.TransactionTemplate{
 		Uid:                  uid,
@@ -500,10 +786,22 @@ func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCrea
 
 	if templateCreateReq.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
 		template.ScheduledFrequencyType = *templateCreateReq.ScheduledFrequencyType
-		template.ScheduledFrequency = a.getOrderedFrequencyValues(*templateCreateReq.ScheduledFrequency)
+		template.ScheduledFrequency = a.getScheduledFrequency(template.ScheduledFrequencyType, *templateCreateReq.ScheduledFrequency)
 		template.ScheduledAt = a.getUTCScheduledAt(*templateCreateReq.ScheduledTimezoneUtcOffset)
 		template.ScheduledTimezoneUtcOffset = *templateCreateReq.ScheduledTimezoneUtcOffset
 
+		if templateCreateReq.ScheduledAdjustmentPolicy != nil {
+			template.ScheduledAdjustmentPolicy = *templateCreateReq.ScheduledAdjustmentPolicy
+		}
+
+		scheduledHolidays, err := a.getNormalizedScheduledHolidays(templateCreateReq.ScheduledHolidays)
+
+		if err != nil {
+			return nil, err
+		}
+
+		template.ScheduledHolidays = scheduledHolidays
+
 		if templateCreateReq.ScheduledStartDate != nil {
 			startTime, err := utils.ParseFromLongDateFirstTime(*templateCreateReq.ScheduledStartDate, *templateCreateReq.ScheduledTimezoneUtcOffset)
 
@@ -544,6 +842,42 @@ func (a *TransactionTemplatesApi) getUTCScheduledAt(scheduledTimezoneUtcOffset i
 	return int16(minutesElapsedOfDayInUtc)
 }
 
+func (a *TransactionTemplatesApi) getScheduledFrequency(frequencyType models.TransactionScheduleFrequencyType, frequencyValue string) string {
+	if frequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE {
+		rule, err := utils.ParseRecurrenceRule(frequencyValue)
+
+		if err != nil {
+			return frequencyValue
+		}
+
+		return rule.String()
+	}
+
+	return a.getOrderedFrequencyValues(frequencyValue)
+}
+
+func (a *TransactionTemplatesApi) getNormalizedScheduledHolidays(holidays []string) (string, error) {
+	normalizedHolidays := make([]string, 0, len(holidays))
+	holidayExistMap := make(map[string]bool, len(holidays))
+
+	for i := 0; i < len(holidays); i++ {
+		holiday, err := models.ParseScheduledHoliday(holidays[i])
+
+		if err != nil {
+			return "", err
+		}
+
+		if _, exists := holidayExistMap[holiday]; !exists {
+			normalizedHolidays = append(normalizedHolidays, holiday)
+			holidayExistMap[holiday] = true
+		}
+	}
+
+	sort.Strings(normalizedHolidays)
+
+	return strings.Join(normalizedHolidays, ","), nil
+}
+
 func (a *TransactionTemplatesApi) getOrderedFrequencyValues(frequencyValue string) string {
 	if frequencyValue == "" {
 		return ""
@@ -27,6 +27,10 @@ import (
 
 const pageCountForAccountStatement = 1000
 const pageCountForMovingAccountTransactions = 1000
+const pageCountForDetectingDuplicateTransactions = 1000
+const maxTransactionIdsCountForLoadSplitsByIds = 1000
+const defaultTransactionForecastMonths = 3
+const defaultTransactionForecastHistoricalMonths = 3
 
 // TransactionsApi represents transaction api
 type TransactionsApi struct {
@@ -36,8 +40,13 @@ type TransactionsApi struct {
 	transactionCategories *services.TransactionCategoryService
 	transactionTags       *services.TransactionTagService
 	transactionPictures   *services.TransactionPictureService
+	transactionSplits     *services.TransactionSplitService
+	transactionRules      *services.TransactionRuleService
+	transactionTemplates  *services.TransactionTemplateService
+	investments           *services.InvestmentService
 	accounts              *services.AccountService
 	users                 *services.UserService
+	exchangeRateHistories *services.ExchangeRateHistoryService
 }
 
 // Initialize a transaction api singleton instance
@@ -56,8 +65,13 @@ var (
 		transactionCategories: services.TransactionCategories,
 		transactionTags:       services.TransactionTags,
 		transactionPictures:   services.TransactionPictures,
+		transactionSplits:     services.TransactionSplits,
+		transactionRules:      services.TransactionRules,
+		transactionTemplates:  services.TransactionTemplates,
+		investments:           services.Investments,
 		accounts:              services.Accounts,
 		users:                 services.Users,
+		exchangeRateHistories: services.ExchangeRateHistories,
 	}
 )
 
@@ -563,7 +577,28 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 	}
 
 	uid := c.GetCurrentUid()
-	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, statisticReq.StartTime, statisticReq.EndTime, tagFilters, noTags, statisticReq.Keyword, statisticReq.MatchMode, clientTimezone, statisticReq.UseTransactionTimezone)
+	var exchangeRateConverter *models.HistoricalExchangeRateConverter
+
+	if statisticReq.UseHistoricalExchangeRates {
+		startRateDate := int32(0)
+		endRateDate := int32(0)
+
+		if statisticReq.StartTime > 0 {
+			startRateDate = utils.FormatUnixTimeToNumericYearMonthDay(statisticReq.StartTime, clientTimezone)
+		}
+
+		if statisticReq.EndTime > 0 {
+			endRateDate = utils.FormatUnixTimeToNumericYearMonthDay(statisticReq.EndTime, clientTimezone)
+		}
+
+		exchangeRateConverter, err = a.getHistoricalExchangeRateConverter(c, uid, startRateDate, endRateDate)
+
+		if err != nil {
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, statisticReq.StartTime, statisticReq.EndTime, tagFilters, noTags, statisticReq.Keyword, statisticReq.MatchMode, clientTimezone, statisticReq.UseTransactionTimezone, exchangeRateConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -585,6 +620,10 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 			TotalAmount: totalAmountItem.Amount.String(),
 		}
 
+		if totalAmountItem.AmountInDefaultCurrency != nil && !totalAmountItem.ExchangeRateMissing {
+			statisticResp.Items[i].TotalAmountInDefaultCurrency = totalAmountItem.AmountInDefaultCurrency.String()
+		}
+
 		if totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 			statisticResp.Items[i].RelatedAccountId = totalAmountItem.RelatedAccountId
 			statisticResp.Items[i].RelatedAccountType, _ = totalAmountItem.Type.ToTransactionRelatedAccountType()
@@ -631,7 +670,28 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 	}
 
 	uid := c.GetCurrentUid()
-	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, startYear, startMonth, endYear, endMonth, tagFilters, noTags, statisticTrendsReq.Keyword, statisticTrendsReq.MatchMode, clientTimezone, statisticTrendsReq.UseTransactionTimezone)
+	var exchangeRateConverter *models.HistoricalExchangeRateConverter
+
+	if statisticTrendsReq.UseHistoricalExchangeRates {
+		startRateDate := int32(0)
+		endRateDate := int32(0)
+
+		if startYear > 0 && startMonth > 0 {
+			startRateDate = startYear*10000 + startMonth*100 + 1
+		}
+
+		if endYear > 0 && endMonth > 0 {
+			endRateDate = endYear*10000 + endMonth*100 + 31
+		}
+
+		exchangeRateConverter, err = a.getHistoricalExchangeRateConverter(c, uid, startRateDate, endRateDate)
+
+		if err != nil {
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, startYear, startMonth, endYear, endMonth, tagFilters, noTags, statisticTrendsReq.Keyword, statisticTrendsReq.MatchMode, clientTimezone, statisticTrendsReq.UseTransactionTimezone, exchangeRateConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -655,6 +715,10 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 				TotalAmount: totalAmountItem.Amount.String(),
 			}
 
+			if totalAmountItem.AmountInDefaultCurrency != nil && !totalAmountItem.ExchangeRateMissing {
+				monthlyStatisticResp.Items[i].TotalAmountInDefaultCurrency = totalAmountItem.AmountInDefaultCurrency.String()
+			}
+
 			if totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || totalAmountItem.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 				monthlyStatisticResp.Items[i].RelatedAccountId = totalAmountItem.RelatedAccountId
 				monthlyStatisticResp.Items[i].RelatedAccountType, _ = totalAmountItem.Type.ToTransactionRelatedAccountType()
@@ -708,6 +772,8 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 	}
 
 	statisticAssetTrendsResp := make(models.TransactionStatisticAssetTrendsResponseItemSlice, 0)
+	accountFirstYearMonthDays := make(map[int64]int32)
+	accountOpeningBalances := make(map[int64]string)
 
 	for yearMonthDay, dailyAccountBalances := range accountDailyBalances {
 		dailyStatisticResp := &models.TransactionStatisticAssetTrendsResponseItem{
@@ -724,11 +790,36 @@ func (a *TransactionsApi) TransactionStatisticsAssetTrendsHandler(c *core.WebCon
 				AccountOpeningBalance: accountBalance.AccountOpeningBalance.String(),
 				AccountClosingBalance: accountBalance.AccountClosingBalance.String(),
 			}
+
+			if firstYearMonthDay, exists := accountFirstYearMonthDays[accountBalance.AccountId]; !exists || yearMonthDay < firstYearMonthDay {
+				accountFirstYearMonthDays[accountBalance.AccountId] = yearMonthDay
+				accountOpeningBalances[accountBalance.AccountId] = accountBalance.AccountOpeningBalance.String()
+			}
 		}
 
 		statisticAssetTrendsResp = append(statisticAssetTrendsResp, dailyStatisticResp)
 	}
 
+	maxUnixTime := time.Now().Unix()
+
+	if maxTransactionTime > 0 {
+		maxUnixTime = utils.GetUnixTimeFromTransactionTime(maxTransactionTime)
+	}
+
+	accountDailyMarketValues, err := a.investments.GetAllAccountsDailyInvestmentMarketValues(c, uid, maxUnixTime, clientTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsAssetTrendsHandler] failed to get investment market values before \"%d\" for user \"uid:%d\", because %s", maxUnixTime, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	minYearMonthDay := int32(0)
+
+	if minTransactionTime > 0 {
+		minYearMonthDay = utils.FormatUnixTimeToNumericYearMonthDay(utils.GetUnixTimeFromTransactionTime(minTransactionTime), clientTimezone)
+	}
+
+	statisticAssetTrendsResp = a.fillAssetTrendsInvestmentMarketValues(statisticAssetTrendsResp, accountOpeningBalances, accountDailyMarketValues, minYearMonthDay)
 	sort.Sort(statisticAssetTrendsResp)
 
 	return statisticAssetTrendsResp, nil
@@ -877,6 +968,115 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *e
 	return amountsResp, nil
 }
 
+// TransactionForecastHandler returns the projected daily balances of all accounts and upcoming bills of current user
+func (a *TransactionsApi) TransactionForecastHandler(c *core.WebContext) (any, *errs.Error) {
+	var forecastReq models.TransactionForecastRequest
+	err := c.ShouldBindQuery(&forecastReq)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionForecastHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if forecastReq.Months < 1 {
+		forecastReq.Months = defaultTransactionForecastMonths
+	}
+
+	if forecastReq.HistoricalMonths < 1 {
+		forecastReq.HistoricalMonths = defaultTransactionForecastHistoricalMonths
+	}
+
+	clientTimezone, err := c.GetClientTimezone()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionForecastHandler] cannot get client timezone, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionForecastHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	now := time.Now()
+	startDate := now.In(clientTimezone)
+	endDate := startDate.AddDate(0, forecastReq.Months, -1)
+	calculator := models.NewTransactionForecastCalculator(accounts, startDate, endDate)
+
+	if a.CurrentConfig().EnableScheduledTransaction {
+		templates, err := a.transactionTemplates.GetAllTemplatesByUid(c, uid, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionForecastHandler] failed to get all scheduled templates for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		exceptions, err := a.transactionTemplates.GetAllScheduleExceptionsByUid(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionForecastHandler] failed to get all scheduled transaction exceptions for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		templateExceptions := make(map[int64][]*models.TransactionScheduleException, len(templates))
+
+		for i := 0; i < len(exceptions); i++ {
+			templateExceptions[exceptions[i].TemplateId] = append(templateExceptions[exceptions[i].TemplateId], exceptions[i])
+		}
+
+		for i := 0; i < len(templates); i++ {
+			template := templates[i]
+
+			// occurrence dates are in template timezone, so one more day on both sides are required to cover the whole range in client timezone
+			occurrences, err := template.GetScheduledOccurrences(templateExceptions[template.TemplateId], startDate.AddDate(0, 0, -1), endDate.AddDate(0, 0, 1))
+
+			if err != nil {
+				log.Warnf(c, "[transactions.TransactionForecastHandler] failed to compute occurrences of template \"id:%d\" for user \"uid:%d\", because %s", template.TemplateId, uid, err.Error())
+				continue
+			}
+
+			for j := 0; j < len(occurrences); j++ {
+				occurrence := occurrences[j]
+
+				// the occurrences earlier than now have already been created
+				if occurrence.TransactionUnixTime < now.Unix() {
+					continue
+				}
+
+				calculator.AddScheduledOccurrence(template, occurrence, utils.FormatUnixTimeToNumericYearMonthDay(occurrence.TransactionUnixTime, clientTimezone))
+			}
+		}
+	}
+
+	if forecastReq.IncludeHistoricalAverage {
+		historicalEndTime := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, clientTimezone)
+		historicalStartTime := historicalEndTime.AddDate(0, -forecastReq.HistoricalMonths, 0)
+		historicalDays := int(historicalEndTime.Sub(historicalStartTime).Hours()/24 + 0.5)
+
+		totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, historicalStartTime.Unix(), historicalEndTime.Unix()-1, nil, false, "", core.MATCH_MODE_DEFAULT, clientTimezone, false, nil)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionForecastHandler] failed to get historical total amounts for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		for i := 0; i < len(totalAmounts); i++ {
+			totalAmount := totalAmounts[i]
+
+			if totalAmount.Type == models.TRANSACTION_DB_TYPE_INCOME {
+				calculator.AddHistoricalAverage(totalAmount.AccountId, totalAmount.CategoryId, models.TRANSACTION_TYPE_INCOME, totalAmount.Amount, historicalDays)
+			} else if totalAmount.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+				calculator.AddHistoricalAverage(totalAmount.AccountId, totalAmount.CategoryId, models.TRANSACTION_TYPE_EXPENSE, totalAmount.Amount, historicalDays)
+			}
+		}
+	}
+
+	return calculator.ToTransactionForecastResponse(), nil
+}
+
 // TransactionGetHandler returns one specific transaction of current user
 func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.Error) {
 	var transactionGetReq models.TransactionGetRequest
@@ -976,9 +1176,17 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		}
 	}
 
+	transactionSplits, err := a.transactionSplits.GetSplitsByTransactionId(c, uid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	transactionEditable := transaction.IsEditable(user, clientTimezone, accountMap[transaction.AccountId], accountMap[transaction.RelatedAccountId])
 	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 	transactionResp := transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
+	transactionResp.Splits = models.TransactionSplitSlice(transactionSplits).ToTransactionSplitInfoResponses()
 
 	if !transactionGetReq.TrimAccount {
 		if sourceAccount := accountMap[transaction.AccountId]; sourceAccount != nil {
@@ -1046,6 +1254,10 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
+	if len(transactionCreateReq.Splits) > models.MaximumSplitsCountOfTransaction {
+		return nil, errs.ErrTransactionHasTooManySplits
+	}
+
 	if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
 		log.Warnf(c, "[transactions.TransactionCreateHandler] transaction type is invalid")
 		return nil, errs.ErrTransactionTypeInvalid
@@ -1080,7 +1292,8 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrUserNotFound
 	}
 
-	transaction := a.createNewTransactionModel(uid, &transactionCreateReq, c.ClientIP())
+	transaction := a.createNewTransactionModel(uid, c.GetCurrentOperatorUid(), &transactionCreateReq, c.ClientIP())
+	splits := a.createNewTransactionSplitModels(transactionCreateReq.Splits)
 
 	allUsedAccounts, err := a.getTransactionUsedAccounts(c, uid, []*models.Transaction{transaction})
 
@@ -1128,15 +1341,23 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 					return nil, errs.Or(err, errs.ErrOperationFailed)
 				}
 
+				existedSplits, err := a.transactionSplits.GetSplitsByTransactionId(c, uid, transactionId)
+
+				if err != nil {
+					log.Errorf(c, "[transactions.TransactionCreateHandler] failed to get splits of existed transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
 				transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 				transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
+				transactionResp.Splits = models.TransactionSplitSlice(existedSplits).ToTransactionSplitInfoResponses()
 
 				return transactionResp, nil
 			}
 		}
 	}
 
-	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds)
+	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds, splits)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCreateHandler] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -1148,6 +1369,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, transactionCreateReq.ClientSessionId, utils.Int64ToString(transaction.TransactionId))
 	transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 	transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
+	transactionResp.Splits = models.TransactionSplitSlice(splits).ToTransactionSplitInfoResponses()
 
 	return transactionResp, nil
 }
@@ -1191,6 +1413,10 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
+	if len(transactionModifyReq.Splits) > models.MaximumSplitsCountOfTransaction {
+		return nil, errs.ErrTransactionHasTooManySplits
+	}
+
 	uid := c.GetCurrentUid()
 	user, err := a.users.GetUserById(c, uid)
 
@@ -1258,6 +1484,15 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 
 	transactionPictureIds := a.transactionPictures.GetTransactionPictureIds(transactionPictureInfos)
 
+	transactionSplits, err := a.transactionSplits.GetSplitsByTransactionId(c, uid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transaction splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newSplits := a.createNewTransactionSplitModels(transactionModifyReq.Splits)
+
 	newTransaction := &models.Transaction{
 		TransactionId:     transaction.TransactionId,
 		Uid:               uid,
@@ -1294,7 +1529,8 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		newTransaction.GeoLongitude == transaction.GeoLongitude &&
 		newTransaction.GeoLatitude == transaction.GeoLatitude &&
 		utils.Int64SliceEquals(tagIds, transactionTagIds) &&
-		utils.Int64SliceEquals(pictureIds, transactionPictureIds) {
+		utils.Int64SliceEquals(pictureIds, transactionPictureIds) &&
+		(newSplits == nil || models.TransactionSplitSlice(newSplits).IsEquals(transactionSplits)) {
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
@@ -1359,7 +1595,7 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.ModifyTransaction(c, newTransaction, changeToTransfer, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds)
+	err = a.transactions.ModifyTransaction(c, newTransaction, changeToTransfer, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds, newSplits)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
@@ -1368,8 +1604,13 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 
 	log.Infof(c, "[transactions.TransactionModifyHandler] user \"uid:%d\" has updated transaction \"id:%d\" successfully", uid, transactionModifyReq.Id)
 
+	if newSplits == nil && newTransaction.Type == transaction.Type && newTransaction.CategoryId == transaction.CategoryId {
+		newSplits = transactionSplits
+	}
+
 	newTransactionResp := newTransaction.ToTransactionInfoResponse(tagIds, transactionEditable)
 	newTransactionResp.Pictures = a.GetTransactionPictureInfoResponseList(newPictureInfos)
+	newTransactionResp.Splits = models.TransactionSplitSlice(newSplits).ToTransactionSplitInfoResponses()
 
 	return newTransactionResp, nil
 }
@@ -1623,7 +1864,7 @@ func (a *TransactionsApi) TransactionBatchUpdateAccountsHandler(c *core.WebConte
 			continue
 		}
 
-		err = a.transactions.ModifyTransaction(c, transaction, false, 0, nil, nil, nil, nil)
+		err = a.transactions.ModifyTransaction(c, transaction, false, 0, nil, nil, nil, nil, nil)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionBatchUpdateAccountsHandler] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -2527,6 +2768,18 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 
 	tagMap := a.transactionTags.GetVisibleTagNameMapByList(tags)
 
+	rules, err := a.transactionRules.GetAllRulesByUid(c, user.Uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get transaction rules for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if len(rules) > 0 {
+		ruleEngine := models.NewTransactionRuleEngine(rules, a.transactionCategories.GetCategoryMapByList(categories), a.accounts.GetAccountMapByList(accounts), a.transactionTags.GetTagMapByList(tags))
+		additionalOptions = additionalOptions.WithTransactionRuleEngine(ruleEngine)
+	}
+
 	parsedTransactions, _, _, _, _, _, err := dataImporter.ParseImportedData(c, user, fileData, clientTimezone, additionalOptions, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 
 	if err != nil {
@@ -2534,6 +2787,17 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	probableDuplicateTransactionIds, err := a.getProbableDuplicateTransactionIds(c, user.Uid, parsedTransactions.ToTransactionsList())
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to detect duplicate transactions for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	for i := 0; i < len(parsedTransactions); i++ {
+		parsedTransactions[i].ProbableDuplicateTransactionId = probableDuplicateTransactionIds[i]
+	}
+
 	parsedTransactionRespsList := parsedTransactions.ToImportTransactionResponseList()
 
 	if len(parsedTransactionRespsList) < 1 {
@@ -2591,6 +2855,7 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 	}
 
 	newTransactionTagIdsMap := make(map[int][]int64, len(transactionImportReq.Transactions))
+	newTransactionSplitsMap := make(map[int][]*models.TransactionSplit)
 
 	for i := 0; i < len(transactionImportReq.Transactions); i++ {
 		transactionCreateReq := transactionImportReq.Transactions[i]
@@ -2605,6 +2870,10 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 			return nil, errs.ErrTransactionHasTooManyTags
 		}
 
+		if len(transactionCreateReq.Splits) > models.MaximumSplitsCountOfTransaction {
+			return nil, errs.ErrTransactionHasTooManySplits
+		}
+
 		if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
 			log.Warnf(c, "[transactions.TransactionImportHandler] transaction type of transaction \"index:%d\" is invalid", i)
 			return nil, errs.ErrTransactionTypeInvalid
@@ -2629,6 +2898,10 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		}
 
 		newTransactionTagIdsMap[i] = tagIds
+
+		if len(transactionCreateReq.Splits) > 0 {
+			newTransactionSplitsMap[i] = a.createNewTransactionSplitModels(transactionCreateReq.Splits)
+		}
 	}
 
 	user, err := a.users.GetUserById(c, uid)
@@ -2649,10 +2922,48 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 
 	for i := 0; i < len(transactionImportReq.Transactions); i++ {
 		transactionCreateReq := transactionImportReq.Transactions[i]
-		transaction := a.createNewTransactionModel(uid, transactionCreateReq, c.ClientIP())
+		transaction := a.createNewTransactionModel(uid, c.GetCurrentOperatorUid(), transactionCreateReq, c.ClientIP())
 		newTransactions[i] = transaction
 	}
 
+	if transactionImportReq.SkipProbableDuplicates {
+		probableDuplicateTransactionIds, err := a.getProbableDuplicateTransactionIds(c, uid, newTransactions)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionImportHandler] failed to detect duplicate transactions for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		nonDuplicateTransactions := make([]*models.Transaction, 0, len(newTransactions))
+		nonDuplicateTransactionTagIdsMap := make(map[int][]int64, len(newTransactions))
+		nonDuplicateTransactionSplitsMap := make(map[int][]*models.TransactionSplit)
+
+		for i := 0; i < len(newTransactions); i++ {
+			if probableDuplicateTransactionIds[i] > 0 {
+				log.Infof(c, "[transactions.TransactionImportHandler] skip transaction \"index:%d\" which is probable duplicate of transaction \"id:%d\" for user \"uid:%d\"", i, probableDuplicateTransactionIds[i], uid)
+				continue
+			}
+
+			newIndex := len(nonDuplicateTransactions)
+			nonDuplicateTransactions = append(nonDuplicateTransactions, newTransactions[i])
+			nonDuplicateTransactionTagIdsMap[newIndex] = newTransactionTagIdsMap[i]
+
+			if splits, exists := newTransactionSplitsMap[i]; exists {
+				nonDuplicateTransactionSplitsMap[newIndex] = splits
+			}
+		}
+
+		newTransactions = nonDuplicateTransactions
+		newTransactionTagIdsMap = nonDuplicateTransactionTagIdsMap
+		newTransactionSplitsMap = nonDuplicateTransactionSplitsMap
+
+		if len(newTransactions) < 1 {
+			log.Infof(c, "[transactions.TransactionImportHandler] all transactions are probable duplicates for user \"uid:%d\"", uid)
+			a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, "finished:0")
+			return 0, nil
+		}
+	}
+
 	allUsedAccounts, err := a.getTransactionUsedAccounts(c, uid, newTransactions)
 
 	if err != nil {
@@ -2670,7 +2981,7 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, func(currentProcess float64) {
+	err = a.transactions.ImportTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, newTransactionSplitsMap, func(currentProcess float64) {
 		a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, fmt.Sprintf("processing:%.2f", currentProcess))
 	})
 	count := len(newTransactions)
@@ -2738,6 +3049,146 @@ func (a *TransactionsApi) TransactionImportProcessHandler(c *core.WebContext) (a
 	return process, nil
 }
 
+func (a *TransactionsApi) fillAssetTrendsInvestmentMarketValues(statisticAssetTrendsResp models.TransactionStatisticAssetTrendsResponseItemSlice, accountOpeningBalances map[int64]string, accountDailyMarketValues map[int32]map[int64]int64, minYearMonthDay int32) models.TransactionStatisticAssetTrendsResponseItemSlice {
+	if len(accountDailyMarketValues) < 1 {
+		return statisticAssetTrendsResp
+	}
+
+	dailyStatisticRespMap := make(map[int32]*models.TransactionStatisticAssetTrendsResponseItem, len(statisticAssetTrendsResp))
+	allYearMonthDays := make([]int32, 0, len(statisticAssetTrendsResp)+len(accountDailyMarketValues))
+	investmentYearMonthDays := make([]int32, 0, len(accountDailyMarketValues))
+	investmentAccountIds := make(map[int64]bool)
+
+	for i := 0; i < len(statisticAssetTrendsResp); i++ {
+		dailyStatisticResp := statisticAssetTrendsResp[i]
+		yearMonthDay := dailyStatisticResp.Year*10000 + dailyStatisticResp.Month*100 + dailyStatisticResp.Day
+		dailyStatisticRespMap[yearMonthDay] = dailyStatisticResp
+		allYearMonthDays = append(allYearMonthDays, yearMonthDay)
+	}
+
+	for yearMonthDay, accountMarketValues := range accountDailyMarketValues {
+		investmentYearMonthDays = append(investmentYearMonthDays, yearMonthDay)
+
+		for accountId := range accountMarketValues {
+			investmentAccountIds[accountId] = true
+		}
+
+		if _, exists := dailyStatisticRespMap[yearMonthDay]; !exists && yearMonthDay >= minYearMonthDay {
+			dailyStatisticResp := &models.TransactionStatisticAssetTrendsResponseItem{
+				Year:  yearMonthDay / 10000,
+				Month: (yearMonthDay % 10000) / 100,
+				Day:   yearMonthDay % 100,
+				Items: make([]*models.TransactionStatisticAssetTrendsResponseDataItem, 0),
+			}
+
+			dailyStatisticRespMap[yearMonthDay] = dailyStatisticResp
+			allYearMonthDays = append(allYearMonthDays, yearMonthDay)
+			statisticAssetTrendsResp = append(statisticAssetTrendsResp, dailyStatisticResp)
+		}
+	}
+
+	sort.Slice(allYearMonthDays, func(i, j int) bool {
+		return allYearMonthDays[i] < allYearMonthDays[j]
+	})
+
+	sort.Slice(investmentYearMonthDays, func(i, j int) bool {
+		return investmentYearMonthDays[i] < investmentYearMonthDays[j]
+	})
+
+	currentMarketValues := make(map[int64]int64)
+	lastClosingBalances := make(map[int64]string, len(accountOpeningBalances))
+	nextInvestmentDayIndex := 0
+
+	// the balance of account before its first item in the time range is the opening balance of that item
+	for accountId, openingBalance := range accountOpeningBalances {
+		lastClosingBalances[accountId] = openingBalance
+	}
+
+	for i := 0; i < len(allYearMonthDays); i++ {
+		yearMonthDay := allYearMonthDays[i]
+		dailyStatisticResp := dailyStatisticRespMap[yearMonthDay]
+		changedAccountIds := make(map[int64]bool)
+
+		for ; nextInvestmentDayIndex < len(investmentYearMonthDays) && investmentYearMonthDays[nextInvestmentDayIndex] <= yearMonthDay; nextInvestmentDayIndex++ {
+			for accountId, marketValue := range accountDailyMarketValues[investmentYearMonthDays[nextInvestmentDayIndex]] {
+				currentMarketValues[accountId] = marketValue
+				changedAccountIds[accountId] = true
+			}
+		}
+
+		if i == 0 {
+			for accountId, marketValue := range currentMarketValues {
+				if marketValue != 0 {
+					changedAccountIds[accountId] = true
+				}
+			}
+		}
+
+		for j := 0; j < len(dailyStatisticResp.Items); j++ {
+			item := dailyStatisticResp.Items[j]
+			lastClosingBalances[item.AccountId] = item.AccountClosingBalance
+
+			if investmentAccountIds[item.AccountId] {
+				item.InvestmentMarketValue = utils.Int64ToString(currentMarketValues[item.AccountId])
+			}
+
+			delete(changedAccountIds, item.AccountId)
+		}
+
+		for accountId := range changedAccountIds {
+			lastClosingBalance, exists := lastClosingBalances[accountId]
+
+			if !exists {
+				lastClosingBalance = "0"
+			}
+
+			dailyStatisticResp.Items = append(dailyStatisticResp.Items, &models.TransactionStatisticAssetTrendsResponseDataItem{
+				AccountId:             accountId,
+				AccountOpeningBalance: lastClosingBalance,
+				AccountClosingBalance: lastClosingBalance,
+				InvestmentMarketValue: utils.Int64ToString(currentMarketValues[accountId]),
+			})
+		}
+	}
+
+	return statisticAssetTrendsResp
+}
+
+func (a *TransactionsApi) getHistoricalExchangeRateConverter(c *core.WebContext, uid int64, startRateDate int32, endRateDate int32) (*models.HistoricalExchangeRateConverter, error) {
+	dataSource := a.CurrentConfig().ExchangeRatesDataSource
+
+	if dataSource == settings.UserCustomExchangeRatesDataSource {
+		return nil, errs.ErrExchangeRatesDataSourceNotSupportHistoryData
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getHistoricalExchangeRateConverter] failed to get user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getHistoricalExchangeRateConverter] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	exchangeRateHistories, err := a.exchangeRateHistories.GetExchangeRatesInDateRange(c, dataSource, startRateDate, endRateDate)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getHistoricalExchangeRateConverter] failed to get exchange rate histories from \"%d\" to \"%d\", because %s", startRateDate, endRateDate, err.Error())
+		return nil, err
+	}
+
+	if len(exchangeRateHistories) < 1 {
+		return nil, errs.ErrExchangeRateHistoryNotFound
+	}
+
+	return models.NewHistoricalExchangeRateConverter(exchangeRateHistories, a.accounts.GetAccountMapByList(accounts), user.DefaultCurrency), nil
+}
+
 func (a *TransactionsApi) filterTransactions(c *core.WebContext, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account) []*models.Transaction {
 	finalTransactions := make([]*models.Transaction, 0, len(transactions))
 
@@ -2936,6 +3387,33 @@ func (a *TransactionsApi) getTransactionUsedAccounts(c *core.WebContext, uid int
 
 func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, user *models.User, transactions []*models.Transaction, allAccounts map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTransactionTagIds map[int64][]int64, pictureInfoMap map[int64][]*models.TransactionPictureInfo, clientTimezone *time.Location, withPictures bool, trimAccount bool, trimCategory bool, trimTag bool) (models.TransactionInfoResponseSlice, error) {
 	result := make(models.TransactionInfoResponseSlice, len(transactions))
+	incomeOrExpenseTransactionIds := make([]int64, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i].Type == models.TRANSACTION_DB_TYPE_INCOME || transactions[i].Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			incomeOrExpenseTransactionIds = append(incomeOrExpenseTransactionIds, transactions[i].TransactionId)
+		}
+	}
+
+	var splitsMap map[int64][]*models.TransactionSplit
+
+	if len(incomeOrExpenseTransactionIds) > maxTransactionIdsCountForLoadSplitsByIds {
+		var err error
+		splitsMap, err = a.transactionSplits.GetAllSplitsOfAllTransactions(c, user.Uid)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.getTransactionResponseListResult] failed to get all transactions splits for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, err
+		}
+	} else if len(incomeOrExpenseTransactionIds) > 0 {
+		var err error
+		splitsMap, err = a.transactionSplits.GetSplitsByTransactionIds(c, user.Uid, incomeOrExpenseTransactionIds)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.getTransactionResponseListResult] failed to get transactions splits for user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, err
+		}
+	}
 
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
@@ -2947,6 +3425,7 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 		transactionEditable := transaction.IsEditable(user, clientTimezone, allAccounts[transaction.AccountId], allAccounts[transaction.RelatedAccountId])
 		transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 		result[i] = transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
+		result[i].Splits = models.TransactionSplitSlice(splitsMap[transaction.TransactionId]).ToTransactionSplitInfoResponses()
 
 		if !trimAccount {
 			if sourceAccount := allAccounts[transaction.AccountId]; sourceAccount != nil {
@@ -2982,7 +3461,79 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 	return result, nil
 }
 
-func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
+func (a *TransactionsApi) createNewTransactionSplitModels(transactionSplitReqs []*models.TransactionSplitRequest) []*models.TransactionSplit {
+	if transactionSplitReqs == nil {
+		return nil
+	}
+
+	splits := make([]*models.TransactionSplit, len(transactionSplitReqs))
+
+	for i := 0; i < len(transactionSplitReqs); i++ {
+		splits[i] = &models.TransactionSplit{
+			CategoryId: transactionSplitReqs[i].CategoryId,
+			Amount:     transactionSplitReqs[i].Amount,
+			Comment:    transactionSplitReqs[i].Comment,
+		}
+	}
+
+	return splits
+}
+
+func (a *TransactionsApi) getProbableDuplicateTransactionIds(c *core.WebContext, uid int64, transactions []*models.Transaction) ([]int64, error) {
+	probableDuplicateTransactionIds := make([]int64, len(transactions))
+	accountIds := make([]int64, 0)
+	accountIdsMap := make(map[int64]bool)
+	minTransactionTime := int64(0)
+	maxTransactionTime := int64(0)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.AccountId <= 0 {
+			continue
+		}
+
+		if !accountIdsMap[transaction.AccountId] {
+			accountIdsMap[transaction.AccountId] = true
+			accountIds = append(accountIds, transaction.AccountId)
+		}
+
+		if minTransactionTime == 0 || transaction.TransactionTime < minTransactionTime {
+			minTransactionTime = transaction.TransactionTime
+		}
+
+		if maxTransactionTime == 0 || transaction.TransactionTime > maxTransactionTime {
+			maxTransactionTime = transaction.TransactionTime
+		}
+	}
+
+	if len(accountIds) < 1 {
+		return probableDuplicateTransactionIds, nil
+	}
+
+	minUnixTime := utils.GetUnixTimeFromTransactionTime(minTransactionTime) - models.TransactionDuplicateMatchTimeWindow
+	maxUnixTime := utils.GetUnixTimeFromTransactionTime(maxTransactionTime) + models.TransactionDuplicateMatchTimeWindow
+
+	if minUnixTime < 1 {
+		minUnixTime = 1
+	}
+
+	existedTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, utils.GetMaxTransactionTimeFromUnixTime(maxUnixTime), utils.GetMinTransactionTimeFromUnixTime(minUnixTime), 0, nil, accountIds, nil, false, "", "", core.MATCH_MODE_DEFAULT, false, pageCountForDetectingDuplicateTransactions, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	matcher := models.NewTransactionDuplicateMatcher(existedTransactions, models.TransactionDuplicateMatchTimeWindow)
+
+	for i := 0; i < len(transactions); i++ {
+		probableDuplicateTransactionIds[i] = matcher.FindProbableDuplicate(transactions[i])
+	}
+
+	return probableDuplicateTransactionIds, nil
+}
+
+func (a *TransactionsApi) createNewTransactionModel(uid int64, creatorUid int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
 	var transactionDbType models.TransactionDbType
 
 	if transactionCreateReq.Type == models.TRANSACTION_TYPE_MODIFY_BALANCE {
@@ -3005,7 +3556,9 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 		Amount:            transactionCreateReq.SourceAmount,
 		HideAmount:        transactionCreateReq.HideAmount,
 		Comment:           transactionCreateReq.Comment,
+		ExternalReference: transactionCreateReq.ExternalReference,
 		CreatedIp:         clientIp,
+		CreatorUid:        creatorUid,
 	}
 
 	if transactionCreateReq.Type == models.TRANSACTION_TYPE_TRANSFER {
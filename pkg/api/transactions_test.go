@@ -0,0 +1,93 @@
+package api
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestFillAssetTrendsInvestmentMarketValues_AccountWithPriorHistoryAndNoTransactionsInTimeRange(t *testing.T) {
+	statisticAssetTrendsResp := models.TransactionStatisticAssetTrendsResponseItemSlice{
+		{
+			Year:  2024,
+			Month: 1,
+			Day:   1,
+			Items: []*models.TransactionStatisticAssetTrendsResponseDataItem{
+				{AccountId: 1, AccountOpeningBalance: "100000", AccountClosingBalance: "100000"},
+			},
+		},
+	}
+	accountOpeningBalances := map[int64]string{1: "100000"}
+	accountDailyMarketValues := map[int32]map[int64]int64{
+		20231215: {1: 110000},
+		20240110: {1: 120000},
+	}
+
+	actualResp := Transactions.fillAssetTrendsInvestmentMarketValues(statisticAssetTrendsResp, accountOpeningBalances, accountDailyMarketValues, 20240101)
+	sort.Sort(actualResp)
+
+	assert.Equal(t, 2, len(actualResp))
+
+	assert.Equal(t, int32(1), actualResp[0].Day)
+	assert.Equal(t, 1, len(actualResp[0].Items))
+	assert.Equal(t, "100000", actualResp[0].Items[0].AccountOpeningBalance)
+	assert.Equal(t, "100000", actualResp[0].Items[0].AccountClosingBalance)
+	assert.Equal(t, "110000", actualResp[0].Items[0].InvestmentMarketValue)
+
+	assert.Equal(t, int32(10), actualResp[1].Day)
+	assert.Equal(t, 1, len(actualResp[1].Items))
+	assert.Equal(t, int64(1), actualResp[1].Items[0].AccountId)
+	assert.Equal(t, "100000", actualResp[1].Items[0].AccountOpeningBalance)
+	assert.Equal(t, "100000", actualResp[1].Items[0].AccountClosingBalance)
+	assert.Equal(t, "120000", actualResp[1].Items[0].InvestmentMarketValue)
+}
+
+func TestFillAssetTrendsInvestmentMarketValues_MarketValueBeforeFirstItemOfAccount(t *testing.T) {
+	statisticAssetTrendsResp := models.TransactionStatisticAssetTrendsResponseItemSlice{
+		{
+			Year:  2024,
+			Month: 1,
+			Day:   1,
+			Items: []*models.TransactionStatisticAssetTrendsResponseDataItem{
+				{AccountId: 2, AccountOpeningBalance: "0", AccountClosingBalance: "5000"},
+			},
+		},
+		{
+			Year:  2024,
+			Month: 1,
+			Day:   5,
+			Items: []*models.TransactionStatisticAssetTrendsResponseDataItem{
+				{AccountId: 1, AccountOpeningBalance: "100000", AccountClosingBalance: "120000"},
+			},
+		},
+	}
+	accountOpeningBalances := map[int64]string{1: "100000", 2: "0"}
+	accountDailyMarketValues := map[int32]map[int64]int64{
+		20240103: {1: 110000},
+	}
+
+	actualResp := Transactions.fillAssetTrendsInvestmentMarketValues(statisticAssetTrendsResp, accountOpeningBalances, accountDailyMarketValues, 20240101)
+	sort.Sort(actualResp)
+
+	assert.Equal(t, 3, len(actualResp))
+
+	assert.Equal(t, int32(1), actualResp[0].Day)
+	assert.Equal(t, 1, len(actualResp[0].Items))
+	assert.Equal(t, "", actualResp[0].Items[0].InvestmentMarketValue)
+
+	assert.Equal(t, int32(3), actualResp[1].Day)
+	assert.Equal(t, 1, len(actualResp[1].Items))
+	assert.Equal(t, int64(1), actualResp[1].Items[0].AccountId)
+	assert.Equal(t, "100000", actualResp[1].Items[0].AccountOpeningBalance)
+	assert.Equal(t, "100000", actualResp[1].Items[0].AccountClosingBalance)
+	assert.Equal(t, "110000", actualResp[1].Items[0].InvestmentMarketValue)
+
+	assert.Equal(t, int32(5), actualResp[2].Day)
+	assert.Equal(t, 1, len(actualResp[2].Items))
+	assert.Equal(t, "100000", actualResp[2].Items[0].AccountOpeningBalance)
+	assert.Equal(t, "120000", actualResp[2].Items[0].AccountClosingBalance)
+	assert.Equal(t, "110000", actualResp[2].Items[0].InvestmentMarketValue)
+}
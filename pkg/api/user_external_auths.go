@@ -9,10 +9,12 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // UserExternalAuthsApi represents user external auth api
 type UserExternalAuthsApi struct {
+	ApiUsingConfig
 	users             *services.UserService
 	userExternalAuths *services.UserExternalAuthService
 }
@@ -20,6 +22,9 @@ type UserExternalAuthsApi struct {
 // Initialize a user external auth api singleton instance
 var (
 	UserExternalAuths = &UserExternalAuthsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
 		users:             services.Users,
 		userExternalAuths: services.UserExternalAuths,
 	}
@@ -35,24 +40,27 @@ func (a *UserExternalAuthsApi) ExternalAuthListHandler(c *core.WebContext) (any,
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	userExternalAuthResps := make(models.UserExternalAuthInfoResponsesSlice, 0, len(userExternalAuths)+1)
-	currentExternalAuthType := oauth2.GetExternalUserAuthType()
-	hasCurrentExternalAuth := false
+	userExternalAuthResps := make(models.UserExternalAuthInfoResponsesSlice, 0, len(userExternalAuths)+2)
+	linkableExternalAuthTypes := make(map[core.UserExternalAuthType]bool, 2)
 
-	for i := 0; i < len(userExternalAuths); i++ {
-		userExternalAuth := userExternalAuths[i]
+	if a.CurrentConfig().EnableOAuth2Login {
+		linkableExternalAuthTypes[oauth2.GetExternalUserAuthType()] = true
+	}
 
-		if userExternalAuth.ExternalAuthType == currentExternalAuthType {
-			hasCurrentExternalAuth = true
-		}
+	if a.CurrentConfig().EnableSAMLLogin {
+		linkableExternalAuthTypes[core.USER_EXTERNAL_AUTH_TYPE_SAML] = true
+	}
 
+	for i := 0; i < len(userExternalAuths); i++ {
+		userExternalAuth := userExternalAuths[i]
+		delete(linkableExternalAuthTypes, userExternalAuth.ExternalAuthType)
 		userExternalAuthResps = append(userExternalAuthResps, userExternalAuth.ToUserExternalAuthInfoResponse())
 	}
 
-	if !hasCurrentExternalAuth {
+	for externalAuthType := range linkableExternalAuthTypes {
 		userExternalAuthResps = append(userExternalAuthResps, &models.UserExternalAuthInfoResponse{
-			ExternalAuthCategory: currentExternalAuthType.GetCategory(),
-			ExternalAuthType:     currentExternalAuthType,
+			ExternalAuthCategory: externalAuthType.GetCategory(),
+			ExternalAuthType:     externalAuthType,
 			Linked:               false,
 		})
 	}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/go-webauthn/webauthn/protocol"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// UserPasskeysApi represents user passkey api
+type UserPasskeysApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	users        *services.UserService
+	userPasskeys *services.UserPasskeyService
+}
+
+// Initialize a user passkey api singleton instance
+var (
+	UserPasskeys = &UserPasskeysApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		users:        services.Users,
+		userPasskeys: services.UserPasskeys,
+	}
+)
+
+// PasskeyListHandler returns passkey list of current user
+func (a *UserPasskeysApi) PasskeyListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	passkeys, err := a.userPasskeys.GetAllPasskeysByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[user_passkeys.PasskeyListHandler] failed to get all passkeys for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	passkeyResps := make(models.UserPasskeyInfoResponseSlice, len(passkeys))
+
+	for i := 0; i < len(passkeys); i++ {
+		passkeyResps[i] = passkeys[i].ToUserPasskeyInfoResponse()
+	}
+
+	sort.Sort(passkeyResps)
+
+	return passkeyResps, nil
+}
+
+// PasskeyRegisterBeginHandler returns the credential creation options for registering a new passkey of current user
+func (a *UserPasskeysApi) PasskeyRegisterBeginHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	passkeyUser, err := a.getPasskeyUser(c, uid)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	creation, session, err := a.userPasskeys.BeginRegistration(c, passkeyUser)
+
+	if err != nil {
+		log.Errorf(c, "[user_passkeys.PasskeyRegisterBeginHandler] failed to begin passkey registration for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.SetPasskeyCeremonySession(c, uid, session)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return creation, nil
+}
+
+// PasskeyRegisterFinishHandler verifies the credential creation response and saves the new passkey of current user
+func (a *UserPasskeysApi) PasskeyRegisterFinishHandler(c *core.WebContext) (any, *errs.Error) {
+	var passkeyRegisterReq models.UserPasskeyRegisterRequest
+	err := c.ShouldBindJSON(&passkeyRegisterReq)
+
+	if err != nil {
+		log.Warnf(c, "[user_passkeys.PasskeyRegisterFinishHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	passkeyUser, err := a.getPasskeyUser(c, uid)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	credentialCreation, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(passkeyRegisterReq.Credential))
+
+	if err != nil {
+		log.Warnf(c, "[user_passkeys.PasskeyRegisterFinishHandler] failed to parse passkey credential for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrPasskeyCredentialInvalid
+	}
+
+	session, err := a.GetAndRemovePasskeyCeremonySession(c, uid, credentialCreation.Response.CollectedClientData.Challenge)
+
+	if err != nil {
+		log.Warnf(c, "[user_passkeys.PasskeyRegisterFinishHandler] failed to get passkey registration session for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrPasskeyCeremonyExpired)
+	}
+
+	credential, err := a.userPasskeys.FinishRegistration(c, passkeyUser, session, credentialCreation)
+
+	if err != nil {
+		log.Warnf(c, "[user_passkeys.PasskeyRegisterFinishHandler] failed to verify passkey credential for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrPasskeyVerificationFailed)
+	}
+
+	passkey, err := a.userPasskeys.CreatePasskey(c, uid, passkeyRegisterReq.Name, credential)
+
+	if err != nil {
+		log.Errorf(c, "[user_passkeys.PasskeyRegisterFinishHandler] failed to save passkey for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[user_passkeys.PasskeyRegisterFinishHandler] user \"uid:%d\" has registered a new passkey \"id:%d\"", uid, passkey.PasskeyId)
+
+	return passkey.ToUserPasskeyInfoResponse(), nil
+}
+
+// PasskeyDeleteHandler deletes the specified passkey of current user
+func (a *UserPasskeysApi) PasskeyDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var passkeyDeleteReq models.UserPasskeyDeleteRequest
+	err := c.ShouldBindJSON(&passkeyDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[user_passkeys.PasskeyDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.userPasskeys.DeletePasskey(c, uid, passkeyDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[user_passkeys.PasskeyDeleteHandler] failed to delete passkey \"id:%d\" for user \"uid:%d\", because %s", passkeyDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[user_passkeys.PasskeyDeleteHandler] user \"uid:%d\" has deleted passkey \"id:%d\"", uid, passkeyDeleteReq.Id)
+
+	return true, nil
+}
+
+func (a *UserPasskeysApi) getPasskeyUser(c *core.WebContext, uid int64) (*models.PasskeyUser, error) {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[user_passkeys.getPasskeyUser] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_PASSKEY_LOGIN) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	passkeys, err := a.userPasskeys.GetAllPasskeysByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[user_passkeys.getPasskeyUser] failed to get all passkeys for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	return &models.PasskeyUser{
+		User:     user,
+		Passkeys: passkeys,
+	}, nil
+}
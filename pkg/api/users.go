@@ -257,6 +257,7 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.WebContext) (any, *errs.Erro
 
 	modifyProfileBasicInfo := false
 	modifyUseLastReconciledTime := false
+	modifyBudgetAlertThresholds := false
 	anythingUpdate := false
 	userNew := &models.User{
 		Uid:  user.Uid,
@@ -507,6 +508,35 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.WebContext) (any, *errs.Erro
 		userNew.IncomeAmountColor = models.AMOUNT_COLOR_TYPE_INVALID
 	}
 
+	if userUpdateReq.BudgetAlertThresholds != nil && *userUpdateReq.BudgetAlertThresholds != user.BudgetAlertThresholds {
+		budgetAlertThresholds, err := models.ParseBudgetAlertThresholds(*userUpdateReq.BudgetAlertThresholds)
+
+		if err != nil {
+			log.Warnf(c, "[users.UserUpdateProfileHandler] budget alert thresholds \"%s\" is invalid", *userUpdateReq.BudgetAlertThresholds)
+			return nil, errs.Or(err, errs.ErrBudgetAlertThresholdsInvalid)
+		}
+
+		textualBudgetAlertThresholds := make([]string, len(budgetAlertThresholds))
+
+		for i := 0; i < len(budgetAlertThresholds); i++ {
+			textualBudgetAlertThresholds[i] = utils.IntToString(int(budgetAlertThresholds[i]))
+		}
+
+		clientTimezone, err := c.GetClientTimezone()
+
+		if err != nil {
+			log.Warnf(c, "[users.UserUpdateProfileHandler] cannot get client timezone, because %s", err.Error())
+			return nil, errs.ErrClientTimezoneOffsetInvalid
+		}
+
+		user.BudgetAlertThresholds = strings.Join(textualBudgetAlertThresholds, ",")
+		user.SetBudgetAlertTimezone(clientTimezone, time.Now().Unix())
+		userNew.BudgetAlertThresholds = user.BudgetAlertThresholds
+		userNew.BudgetAlertTimezone = user.BudgetAlertTimezone
+		modifyBudgetAlertThresholds = true
+		anythingUpdate = true
+	}
+
 	if modifyProfileBasicInfo && user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_UPDATE_PROFILE_BASIC_INFO) {
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
@@ -542,7 +572,7 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.WebContext) (any, *errs.Erro
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
-	keyProfileUpdated, emailSetToUnverified, err := a.users.UpdateUser(c, userNew, modifyUserLanguage, modifyUseLastReconciledTime)
+	keyProfileUpdated, emailSetToUnverified, err := a.users.UpdateUser(c, userNew, modifyUserLanguage, modifyUseLastReconciledTime, modifyBudgetAlertThresholds)
 
 	if err != nil {
 		log.Errorf(c, "[users.UserUpdateProfileHandler] failed to update user \"uid:%d\", because %s", user.Uid, err.Error())
@@ -0,0 +1,299 @@
+package api
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maximumWebhookCountPerUser = 10
+
+// WebhooksApi represents webhook api
+type WebhooksApi struct {
+	ApiUsingConfig
+	webhooks *services.WebhookService
+}
+
+// Initialize a webhook api singleton instance
+var (
+	Webhooks = &WebhooksApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		webhooks: services.Webhooks,
+	}
+)
+
+// WebhookListHandler returns webhook list of current user
+func (a *WebhooksApi) WebhookListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	webhooks, err := a.webhooks.GetAllWebhooksByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookListHandler] failed to get webhooks for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	webhookResps := make(models.WebhookInfoResponseSlice, len(webhooks))
+
+	for i := 0; i < len(webhooks); i++ {
+		webhookResps[i] = webhooks[i].ToWebhookInfoResponse(false)
+	}
+
+	sort.Sort(webhookResps)
+
+	return webhookResps, nil
+}
+
+// WebhookGetHandler returns one specific webhook with its secret of current user
+func (a *WebhooksApi) WebhookGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var webhookGetReq models.WebhookGetRequest
+	err := c.ShouldBindQuery(&webhookGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[webhooks.WebhookGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	webhook, err := a.webhooks.GetWebhookByWebhookId(c, uid, webhookGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookGetHandler] failed to get webhook \"id:%d\" for user \"uid:%d\", because %s", webhookGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return webhook.ToWebhookInfoResponse(true), nil
+}
+
+// WebhookCreateHandler saves a new webhook by request parameters for current user
+func (a *WebhooksApi) WebhookCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var webhookCreateReq models.WebhookCreateRequest
+	err := c.ShouldBindJSON(&webhookCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[webhooks.WebhookCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	err = a.checkWebhookUrlAndEventTypes(webhookCreateReq.Url, webhookCreateReq.EventTypes)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	uid := c.GetCurrentUid()
+	totalCount, err := a.webhooks.GetTotalWebhookCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookCreateHandler] failed to get total webhook count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if totalCount >= maximumWebhookCountPerUser {
+		return nil, errs.ErrWebhookCountExceedsLimit
+	}
+
+	webhook := &models.Webhook{
+		Uid:        uid,
+		Name:       webhookCreateReq.Name,
+		Url:        webhookCreateReq.Url,
+		EventTypes: services.GetWebhookEventTypesString(webhookCreateReq.EventTypes),
+	}
+
+	err = a.webhooks.CreateWebhook(c, webhook)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookCreateHandler] failed to create webhook \"id:%d\" for user \"uid:%d\", because %s", webhook.WebhookId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[webhooks.WebhookCreateHandler] user \"uid:%d\" has created a new webhook \"id:%d\" successfully", uid, webhook.WebhookId)
+
+	return webhook.ToWebhookInfoResponse(true), nil
+}
+
+// WebhookModifyHandler saves an existed webhook by request parameters for current user
+func (a *WebhooksApi) WebhookModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var webhookModifyReq models.WebhookModifyRequest
+	err := c.ShouldBindJSON(&webhookModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[webhooks.WebhookModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	err = a.checkWebhookUrlAndEventTypes(webhookModifyReq.Url, webhookModifyReq.EventTypes)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	uid := c.GetCurrentUid()
+	webhook, err := a.webhooks.GetWebhookByWebhookId(c, uid, webhookModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookModifyHandler] failed to get webhook \"id:%d\" for user \"uid:%d\", because %s", webhookModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newWebhook := &models.Webhook{
+		WebhookId:  webhook.WebhookId,
+		Uid:        uid,
+		Name:       webhookModifyReq.Name,
+		Url:        webhookModifyReq.Url,
+		EventTypes: services.GetWebhookEventTypesString(webhookModifyReq.EventTypes),
+		Disabled:   webhookModifyReq.Disabled,
+	}
+
+	if newWebhook.Name == webhook.Name &&
+		newWebhook.Url == webhook.Url &&
+		newWebhook.EventTypes == webhook.EventTypes &&
+		newWebhook.Disabled == webhook.Disabled {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.webhooks.ModifyWebhook(c, newWebhook)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookModifyHandler] failed to update webhook \"id:%d\" for user \"uid:%d\", because %s", webhookModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[webhooks.WebhookModifyHandler] user \"uid:%d\" has updated webhook \"id:%d\" successfully", uid, webhookModifyReq.Id)
+
+	newWebhook.Secret = webhook.Secret
+	newWebhook.CreatedUnixTime = webhook.CreatedUnixTime
+
+	return newWebhook.ToWebhookInfoResponse(true), nil
+}
+
+// WebhookDeleteHandler deletes an existed webhook by request parameters for current user
+func (a *WebhooksApi) WebhookDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var webhookDeleteReq models.WebhookDeleteRequest
+	err := c.ShouldBindJSON(&webhookDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[webhooks.WebhookDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.webhooks.DeleteWebhook(c, uid, webhookDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookDeleteHandler] failed to delete webhook \"id:%d\" for user \"uid:%d\", because %s", webhookDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[webhooks.WebhookDeleteHandler] user \"uid:%d\" has deleted webhook \"id:%d\"", uid, webhookDeleteReq.Id)
+	return true, nil
+}
+
+// WebhookDeliveryListHandler returns the delivery log of the specified webhook of current user
+func (a *WebhooksApi) WebhookDeliveryListHandler(c *core.WebContext) (any, *errs.Error) {
+	var deliveryListReq models.WebhookDeliveryListRequest
+	err := c.ShouldBindQuery(&deliveryListReq)
+
+	if err != nil {
+		log.Warnf(c, "[webhooks.WebhookDeliveryListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	_, err = a.webhooks.GetWebhookByWebhookId(c, uid, deliveryListReq.WebhookId)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookDeliveryListHandler] failed to get webhook \"id:%d\" for user \"uid:%d\", because %s", deliveryListReq.WebhookId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	deliveries, err := a.webhooks.GetDeliveriesByWebhookId(c, uid, deliveryListReq.WebhookId, deliveryListReq.Page, deliveryListReq.Count)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookDeliveryListHandler] failed to get deliveries of webhook \"id:%d\" for user \"uid:%d\", because %s", deliveryListReq.WebhookId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	deliveryResps := make([]*models.WebhookDeliveryInfoResponse, len(deliveries))
+
+	for i := 0; i < len(deliveries); i++ {
+		deliveryResps[i] = deliveries[i].ToWebhookDeliveryInfoResponse()
+	}
+
+	return deliveryResps, nil
+}
+
+// WebhookDeliveryReplayHandler sends the event of an existed delivery to the webhook again for current user
+func (a *WebhooksApi) WebhookDeliveryReplayHandler(c *core.WebContext) (any, *errs.Error) {
+	var deliveryReplayReq models.WebhookDeliveryReplayRequest
+	err := c.ShouldBindJSON(&deliveryReplayReq)
+
+	if err != nil {
+		log.Warnf(c, "[webhooks.WebhookDeliveryReplayHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	delivery, err := a.webhooks.GetDeliveryByDeliveryId(c, uid, deliveryReplayReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookDeliveryReplayHandler] failed to get webhook delivery \"id:%d\" for user \"uid:%d\", because %s", deliveryReplayReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	_, err = a.webhooks.GetWebhookByWebhookId(c, uid, delivery.WebhookId)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookDeliveryReplayHandler] failed to get webhook \"id:%d\" for user \"uid:%d\", because %s", delivery.WebhookId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newDelivery, err := a.webhooks.ReplayDelivery(c, delivery)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.WebhookDeliveryReplayHandler] failed to replay webhook delivery \"id:%d\" for user \"uid:%d\", because %s", deliveryReplayReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[webhooks.WebhookDeliveryReplayHandler] user \"uid:%d\" has replayed webhook delivery \"id:%d\" as new delivery \"id:%d\"", uid, deliveryReplayReq.Id, newDelivery.DeliveryId)
+
+	return newDelivery.ToWebhookDeliveryInfoResponse(), nil
+}
+
+func (a *WebhooksApi) checkWebhookUrlAndEventTypes(webhookUrl string, eventTypes []models.WebhookEventType) error {
+	parsedUrl, err := url.Parse(webhookUrl)
+
+	if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" || parsedUrl.Hostname() == "" {
+		return errs.ErrWebhookUrlInvalid
+	}
+
+	if !a.CurrentConfig().WebhookAllowPrivateNetwork {
+		hostname := strings.ToLower(strings.TrimSuffix(parsedUrl.Hostname(), "."))
+
+		if hostname == "localhost" || strings.HasSuffix(hostname, ".localhost") {
+			return errs.ErrWebhookUrlNotAllowed
+		}
+
+		if ip := net.ParseIP(hostname); ip != nil && !utils.IsPublicIPAddress(ip) {
+			return errs.ErrWebhookUrlNotAllowed
+		}
+	}
+
+	for i := 0; i < len(eventTypes); i++ {
+		if !eventTypes[i].IsValid() {
+			return errs.ErrWebhookEventTypeInvalid
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const ldapUserFilterLoginNamePlaceholder = "%s"
+
+// LDAPContainer contains the current LDAP authentication config
+type LDAPContainer struct {
+	serverUrl         string
+	startTLS          bool
+	tlsConfig         *tls.Config
+	bindDN            string
+	bindPassword      string
+	baseDN            string
+	userFilter        string
+	groupFilter       string
+	userNameAttribute string
+	emailAttribute    string
+	nickNameAttribute string
+	requestTimeout    time.Duration
+}
+
+// Initialize a LDAP container singleton instance
+var (
+	Container = &LDAPContainer{}
+)
+
+// InitializeLDAPAuthentication initializes the LDAP authentication according to the config
+func InitializeLDAPAuthentication(config *settings.Config) error {
+	if !config.EnableLDAPLogin {
+		return nil
+	}
+
+	if config.LDAPServerUrl == "" || config.LDAPBaseDN == "" || config.LDAPUserNameAttribute == "" {
+		return errs.ErrInvalidLDAPConfig
+	}
+
+	if !strings.Contains(config.LDAPUserFilter, ldapUserFilterLoginNamePlaceholder) {
+		return errs.ErrInvalidLDAPConfig
+	}
+
+	serverUrl, err := url.Parse(config.LDAPServerUrl)
+
+	if err != nil || (serverUrl.Scheme != "ldap" && serverUrl.Scheme != "ldaps") || serverUrl.Hostname() == "" {
+		return errs.ErrInvalidLDAPConfig
+	}
+
+	Container.serverUrl = config.LDAPServerUrl
+	Container.startTLS = config.LDAPStartTLS && serverUrl.Scheme == "ldap"
+	Container.tlsConfig = &tls.Config{
+		ServerName:         serverUrl.Hostname(),
+		InsecureSkipVerify: config.LDAPSkipTLSVerify,
+	}
+	Container.bindDN = config.LDAPBindDN
+	Container.bindPassword = config.LDAPBindPassword
+	Container.baseDN = config.LDAPBaseDN
+	Container.userFilter = config.LDAPUserFilter
+	Container.groupFilter = config.LDAPGroupFilter
+	Container.userNameAttribute = config.LDAPUserNameAttribute
+	Container.emailAttribute = config.LDAPEmailAttribute
+	Container.nickNameAttribute = config.LDAPNickNameAttribute
+	Container.requestTimeout = time.Duration(config.LDAPRequestTimeout) * time.Millisecond
+
+	return nil
+}
+
+// AuthenticateUser searches the user by login name and verifies the password by binding as the user, returns the user info if succeeded
+func AuthenticateUser(c core.Context, loginName string, password string) (*LDAPUserInfo, error) {
+	if Container.serverUrl == "" {
+		return nil, errs.ErrLDAPNotEnabled
+	}
+
+	// empty password would lead to an unauthenticated bind, which always succeeds in most LDAP servers
+	if loginName == "" || password == "" {
+		return nil, errs.ErrLoginNameOrPasswordWrong
+	}
+
+	conn, err := Container.connect()
+
+	if err != nil {
+		log.Errorf(c, "[ldap_authentication.AuthenticateUser] failed to connect to ldap server, because %s", err.Error())
+		return nil, errs.ErrLDAPServerUnavailable
+	}
+
+	defer conn.Close()
+
+	if Container.bindDN != "" {
+		err = conn.Bind(Container.bindDN, Container.bindPassword)
+
+		if err != nil {
+			log.Errorf(c, "[ldap_authentication.AuthenticateUser] failed to bind search user, because %s", err.Error())
+			return nil, errs.ErrLDAPServerUnavailable
+		}
+	}
+
+	searchRequest := ldap.NewSearchRequest(
+		Container.baseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		int(Container.requestTimeout/time.Second),
+		false,
+		Container.getUserSearchFilter(loginName),
+		Container.getUserAttributes(),
+		nil,
+	)
+
+	searchResult, err := conn.Search(searchRequest)
+
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		log.Errorf(c, "[ldap_authentication.AuthenticateUser] failed to search user \"%s\", because %s", loginName, err.Error())
+		return nil, errs.ErrLDAPServerUnavailable
+	}
+
+	if searchResult == nil || len(searchResult.Entries) < 1 {
+		log.Warnf(c, "[ldap_authentication.AuthenticateUser] user \"%s\" is not found or not allowed", loginName)
+		return nil, errs.ErrLoginNameOrPasswordWrong
+	} else if len(searchResult.Entries) > 1 {
+		log.Warnf(c, "[ldap_authentication.AuthenticateUser] there are more than one users matched login name \"%s\"", loginName)
+		return nil, errs.ErrLoginNameOrPasswordWrong
+	}
+
+	entry := searchResult.Entries[0]
+	err = conn.Bind(entry.DN, password)
+
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, errs.ErrLoginNameOrPasswordWrong
+		}
+
+		log.Errorf(c, "[ldap_authentication.AuthenticateUser] failed to bind user \"%s\", because %s", entry.DN, err.Error())
+		return nil, errs.ErrLDAPServerUnavailable
+	}
+
+	userInfo := &LDAPUserInfo{
+		DN:       entry.DN,
+		UserName: strings.TrimSpace(entry.GetAttributeValue(Container.userNameAttribute)),
+	}
+
+	if Container.emailAttribute != "" {
+		userInfo.Email = strings.TrimSpace(entry.GetAttributeValue(Container.emailAttribute))
+	}
+
+	if Container.nickNameAttribute != "" {
+		userInfo.NickName = strings.TrimSpace(entry.GetAttributeValue(Container.nickNameAttribute))
+	}
+
+	return userInfo, nil
+}
+
+func (l *LDAPContainer) connect() (*ldap.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout: l.requestTimeout,
+	}
+
+	conn, err := ldap.DialURL(l.serverUrl, ldap.DialWithDialer(dialer), ldap.DialWithTLSConfig(l.tlsConfig))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if l.requestTimeout > 0 {
+		conn.SetTimeout(l.requestTimeout)
+	}
+
+	if l.startTLS {
+		err = conn.StartTLS(l.tlsConfig)
+
+		if err != nil {
+			conn.Close()
+			return nil, errors.Join(errors.New("failed to start tls"), err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (l *LDAPContainer) getUserSearchFilter(loginName string) string {
+	userFilter := strings.ReplaceAll(l.userFilter, ldapUserFilterLoginNamePlaceholder, ldap.EscapeFilter(loginName))
+
+	if l.groupFilter == "" {
+		return userFilter
+	}
+
+	return "(&" + userFilter + l.groupFilter + ")"
+}
+
+func (l *LDAPContainer) getUserAttributes() []string {
+	attributes := []string{"dn", l.userNameAttribute}
+
+	if l.emailAttribute != "" {
+		attributes = append(attributes, l.emailAttribute)
+	}
+
+	if l.nickNameAttribute != "" {
+		attributes = append(attributes, l.nickNameAttribute)
+	}
+
+	return attributes
+}
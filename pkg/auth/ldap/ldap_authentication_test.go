@@ -0,0 +1,62 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestInitializeLDAPAuthentication_InvalidConfig(t *testing.T) {
+	config := &settings.Config{
+		EnableLDAPLogin:       true,
+		LDAPServerUrl:         "ldap://localhost:389",
+		LDAPBaseDN:            "dc=example,dc=com",
+		LDAPUserFilter:        "(uid=%s)",
+		LDAPUserNameAttribute: "uid",
+	}
+
+	config.LDAPServerUrl = "http://localhost:389"
+	assert.Equal(t, errs.ErrInvalidLDAPConfig, InitializeLDAPAuthentication(config))
+
+	config.LDAPServerUrl = "ldap://localhost:389"
+	config.LDAPUserFilter = "(uid=admin)"
+	assert.Equal(t, errs.ErrInvalidLDAPConfig, InitializeLDAPAuthentication(config))
+
+	config.LDAPUserFilter = "(uid=%s)"
+	config.LDAPBaseDN = ""
+	assert.Equal(t, errs.ErrInvalidLDAPConfig, InitializeLDAPAuthentication(config))
+
+	config.EnableLDAPLogin = false
+	assert.Nil(t, InitializeLDAPAuthentication(config))
+}
+
+func TestLDAPContainer_GetUserSearchFilter(t *testing.T) {
+	container := &LDAPContainer{
+		userFilter: "(uid=%s)",
+	}
+
+	assert.Equal(t, "(uid=admin)", container.getUserSearchFilter("admin"))
+	assert.Equal(t, "(uid=\\2a\\29\\28objectClass=\\2a)", container.getUserSearchFilter("*)(objectClass=*"))
+
+	container = &LDAPContainer{
+		userFilter:  "(|(uid=%s)(mail=%s))",
+		groupFilter: "(memberOf=cn=ezbookkeeping,ou=groups,dc=example,dc=com)",
+	}
+
+	assert.Equal(t, "(&(|(uid=admin)(mail=admin))(memberOf=cn=ezbookkeeping,ou=groups,dc=example,dc=com))", container.getUserSearchFilter("admin"))
+}
+
+func TestLDAPContainer_GetUserAttributes(t *testing.T) {
+	container := &LDAPContainer{
+		userNameAttribute: "uid",
+		emailAttribute:    "mail",
+	}
+
+	assert.Equal(t, []string{"dn", "uid", "mail"}, container.getUserAttributes())
+
+	container.nickNameAttribute = "cn"
+	assert.Equal(t, []string{"dn", "uid", "mail", "cn"}, container.getUserAttributes())
+}
@@ -0,0 +1,9 @@
+package ldap
+
+// LDAPUserInfo represents the user info retrieved from LDAP server
+type LDAPUserInfo struct {
+	DN       string
+	UserName string
+	Email    string
+	NickName string
+}
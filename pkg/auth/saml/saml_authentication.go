@@ -0,0 +1,364 @@
+package saml
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crewjam/saml"
+	dsig "github.com/russellhaering/goxmldsig"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/httpclient"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const defaultSAMLMaxIssueDelay = 90 * time.Second
+
+// SAMLContainer contains the current SAML 2.0 service provider
+type SAMLContainer struct {
+	serviceProvider   *saml.ServiceProvider
+	idpMetadataUrl    string
+	httpClient        *http.Client
+	userNameAttribute string
+	emailAttribute    string
+	nickNameAttribute string
+	idpMetadataLock   sync.Mutex
+}
+
+// Initialize a SAML 2.0 container singleton instance
+var (
+	Container = &SAMLContainer{}
+)
+
+// InitializeSAMLServiceProvider initializes the SAML 2.0 service provider according to the config
+func InitializeSAMLServiceProvider(config *settings.Config) error {
+	if !config.EnableSAMLLogin {
+		return nil
+	}
+
+	if config.SAMLIdPMetadataUrl == "" && config.SAMLIdPMetadataFile == "" {
+		return errs.ErrInvalidSAMLConfig
+	}
+
+	if config.SAMLSignAuthnRequest && (config.SAMLSPCertificateFile == "" || config.SAMLSPPrivateKeyFile == "") {
+		return errs.ErrInvalidSAMLConfig
+	}
+
+	metadataUrl, err := url.Parse(config.RootUrl + "saml/metadata")
+
+	if err != nil {
+		return err
+	}
+
+	acsUrl, err := url.Parse(config.RootUrl + "saml/acs")
+
+	if err != nil {
+		return err
+	}
+
+	serviceProvider := &saml.ServiceProvider{
+		EntityID:          config.SAMLSPEntityID,
+		MetadataURL:       *metadataUrl,
+		AcsURL:            *acsUrl,
+		AuthnNameIDFormat: saml.UnspecifiedNameIDFormat,
+	}
+
+	if config.SAMLSPCertificateFile != "" && config.SAMLSPPrivateKeyFile != "" {
+		keyPair, err := tls.LoadX509KeyPair(config.SAMLSPCertificateFile, config.SAMLSPPrivateKeyFile)
+
+		if err != nil {
+			return err
+		}
+
+		certificate, err := x509.ParseCertificate(keyPair.Certificate[0])
+
+		if err != nil {
+			return err
+		}
+
+		serviceProvider.Certificate = certificate
+
+		switch key := keyPair.PrivateKey.(type) {
+		case *rsa.PrivateKey:
+			serviceProvider.Key = key
+
+			if config.SAMLSignAuthnRequest {
+				serviceProvider.SignatureMethod = dsig.RSASHA256SignatureMethod
+			}
+		case *ecdsa.PrivateKey:
+			serviceProvider.Key = key
+
+			if config.SAMLSignAuthnRequest {
+				serviceProvider.SignatureMethod = dsig.ECDSASHA256SignatureMethod
+			}
+		default:
+			return errs.ErrInvalidSAMLConfig
+		}
+	}
+
+	if config.SAMLIdPMetadataFile != "" {
+		metadataContent, err := os.ReadFile(config.SAMLIdPMetadataFile)
+
+		if err != nil {
+			return err
+		}
+
+		serviceProvider.IDPMetadata, err = parseIdentityProviderMetadata(metadataContent)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// the clock skew allowance is also applied to the issue instant, which is compared with current time directly
+	saml.MaxClockSkew = config.SAMLAllowedClockSkewDuration
+	saml.MaxIssueDelay = defaultSAMLMaxIssueDelay + config.SAMLAllowedClockSkewDuration
+
+	Container.serviceProvider = serviceProvider
+	Container.idpMetadataUrl = config.SAMLIdPMetadataUrl
+	Container.httpClient = httpclient.NewHttpClient(config.SAMLRequestTimeout, config.SAMLProxy, config.SAMLSkipTLSVerify, core.GetOutgoingUserAgent(), config.EnableDebugLog)
+	Container.userNameAttribute = config.SAMLUserNameAttribute
+	Container.emailAttribute = config.SAMLEmailAttribute
+	Container.nickNameAttribute = config.SAMLNickNameAttribute
+
+	return nil
+}
+
+// GetSAMLMetadata returns the metadata xml of current SAML 2.0 service provider
+func GetSAMLMetadata(c core.Context) ([]byte, error) {
+	if Container.serviceProvider == nil {
+		return nil, errs.ErrSAMLNotEnabled
+	}
+
+	metadata, err := xml.MarshalIndent(Container.serviceProvider.Metadata(), "", "  ")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), metadata...), nil
+}
+
+// GetSAMLAuthnRequestUrl returns the identity provider url with the signed authentication request and the request id
+func GetSAMLAuthnRequestUrl(c core.Context, relayState string) (string, string, error) {
+	serviceProvider, err := Container.getServiceProvider(c)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	idpUrl := serviceProvider.GetSSOBindingLocation(saml.HTTPRedirectBinding)
+
+	if idpUrl == "" {
+		log.Errorf(c, "[saml_authentication.GetSAMLAuthnRequestUrl] identity provider does not support http redirect binding")
+		return "", "", errs.ErrInvalidSAMLConfig
+	}
+
+	authnRequest, err := serviceProvider.MakeAuthenticationRequest(idpUrl, saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	// relay state is not escaped when building the redirect url
+	redirectUrl, err := authnRequest.Redirect(url.QueryEscape(relayState), serviceProvider)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return redirectUrl.String(), authnRequest.ID, nil
+}
+
+// ParseSAMLResponse validates the base64 encoded SAML 2.0 response and returns the asserted user info
+func ParseSAMLResponse(c core.Context, samlResponse string, possibleRequestIds []string) (*SAMLUserInfo, error) {
+	serviceProvider, err := Container.getServiceProvider(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	responseContent, err := base64.StdEncoding.DecodeString(samlResponse)
+
+	if err != nil {
+		log.Warnf(c, "[saml_authentication.ParseSAMLResponse] failed to decode saml response, because %s", err.Error())
+		return nil, errs.ErrInvalidSAMLResponse
+	}
+
+	assertion, err := serviceProvider.ParseXMLResponse(responseContent, possibleRequestIds, serviceProvider.AcsURL)
+
+	if err != nil {
+		var invalidResponseErr *saml.InvalidResponseError
+
+		if errors.As(err, &invalidResponseErr) && invalidResponseErr.PrivateErr != nil {
+			err = invalidResponseErr.PrivateErr
+		}
+
+		log.Warnf(c, "[saml_authentication.ParseSAMLResponse] failed to validate saml response, because %s", err.Error())
+		return nil, errs.ErrInvalidSAMLResponse
+	}
+
+	return Container.getUserInfo(assertion), nil
+}
+
+func (s *SAMLContainer) getServiceProvider(c core.Context) (*saml.ServiceProvider, error) {
+	if s.serviceProvider == nil {
+		return nil, errs.ErrSAMLNotEnabled
+	}
+
+	s.idpMetadataLock.Lock()
+	defer s.idpMetadataLock.Unlock()
+
+	if s.serviceProvider.IDPMetadata != nil {
+		return s.serviceProvider, nil
+	}
+
+	idpMetadata, err := s.fetchIdentityProviderMetadata(c)
+
+	if err != nil {
+		log.Errorf(c, "[saml_authentication.getServiceProvider] failed to fetch identity provider metadata from \"%s\", because %s", s.idpMetadataUrl, err.Error())
+		return nil, errs.ErrInvalidSAMLConfig
+	}
+
+	s.serviceProvider.IDPMetadata = idpMetadata
+
+	return s.serviceProvider, nil
+}
+
+func (s *SAMLContainer) fetchIdentityProviderMetadata(c core.Context) (*saml.EntityDescriptor, error) {
+	req, err := http.NewRequestWithContext(c, http.MethodGet, s.idpMetadataUrl, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected response status " + resp.Status)
+	}
+
+	metadataContent, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseIdentityProviderMetadata(metadataContent)
+}
+
+func (s *SAMLContainer) getUserInfo(assertion *saml.Assertion) *SAMLUserInfo {
+	userInfo := &SAMLUserInfo{}
+
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		userInfo.NameID = strings.TrimSpace(assertion.Subject.NameID.Value)
+	}
+
+	if s.userNameAttribute != "" {
+		userInfo.UserName = getAssertionAttributeValue(assertion, s.userNameAttribute)
+	}
+
+	if userInfo.UserName == "" {
+		userInfo.UserName = userInfo.NameID
+	}
+
+	if s.emailAttribute != "" {
+		userInfo.Email = getAssertionAttributeValue(assertion, s.emailAttribute)
+	}
+
+	if s.nickNameAttribute != "" {
+		userInfo.NickName = getAssertionAttributeValue(assertion, s.nickNameAttribute)
+	}
+
+	return userInfo
+}
+
+func getAssertionAttributeValue(assertion *saml.Assertion, attributeName string) string {
+	for i := 0; i < len(assertion.AttributeStatements); i++ {
+		attributes := assertion.AttributeStatements[i].Attributes
+
+		for j := 0; j < len(attributes); j++ {
+			attribute := attributes[j]
+
+			if attribute.Name != attributeName && attribute.FriendlyName != attributeName {
+				continue
+			}
+
+			for k := 0; k < len(attribute.Values); k++ {
+				value := strings.TrimSpace(attribute.Values[k].Value)
+
+				if value != "" {
+					return value
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func parseIdentityProviderMetadata(content []byte) (*saml.EntityDescriptor, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	for {
+		token, err := decoder.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		startElement, ok := token.(xml.StartElement)
+
+		if !ok {
+			continue
+		}
+
+		// the metadata may contain a single entity or an entities group
+		if startElement.Name.Local == "EntityDescriptor" {
+			entity := &saml.EntityDescriptor{}
+
+			if err := decoder.DecodeElement(entity, &startElement); err != nil {
+				return nil, err
+			}
+
+			return entity, nil
+		} else if startElement.Name.Local == "EntitiesDescriptor" {
+			entities := &saml.EntitiesDescriptor{}
+
+			if err := decoder.DecodeElement(entities, &startElement); err != nil {
+				return nil, err
+			}
+
+			for i := 0; i < len(entities.EntityDescriptors); i++ {
+				if len(entities.EntityDescriptors[i].IDPSSODescriptors) > 0 {
+					return &entities.EntityDescriptors[i], nil
+				}
+			}
+
+			return nil, errors.New("no identity provider entity found in metadata")
+		}
+
+		return nil, errors.New("unexpected root element \"" + startElement.Name.Local + "\" in metadata")
+	}
+}
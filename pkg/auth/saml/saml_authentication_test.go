@@ -0,0 +1,173 @@
+package saml
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const testIdentityProviderMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com/metadata">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`
+
+const testIdentityProviderEntitiesMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<md:EntitiesDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata">
+  <md:EntityDescriptor entityID="https://sp.example.com/metadata">
+    <md:SPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol"/>
+  </md:EntityDescriptor>
+  <md:EntityDescriptor entityID="https://idp.example.com/metadata">
+    <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+      <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>
+    </md:IDPSSODescriptor>
+  </md:EntityDescriptor>
+</md:EntitiesDescriptor>`
+
+func TestParseIdentityProviderMetadata_EntityDescriptor(t *testing.T) {
+	entity, err := parseIdentityProviderMetadata([]byte(testIdentityProviderMetadata))
+	assert.Nil(t, err)
+	assert.Equal(t, "https://idp.example.com/metadata", entity.EntityID)
+	assert.Equal(t, 1, len(entity.IDPSSODescriptors))
+}
+
+func TestParseIdentityProviderMetadata_EntitiesDescriptor(t *testing.T) {
+	entity, err := parseIdentityProviderMetadata([]byte(testIdentityProviderEntitiesMetadata))
+	assert.Nil(t, err)
+	assert.Equal(t, "https://idp.example.com/metadata", entity.EntityID)
+}
+
+func TestParseIdentityProviderMetadata_InvalidMetadata(t *testing.T) {
+	_, err := parseIdentityProviderMetadata([]byte(`<html></html>`))
+	assert.NotNil(t, err)
+
+	_, err = parseIdentityProviderMetadata([]byte(``))
+	assert.NotNil(t, err)
+}
+
+func TestSAMLContainer_GetUserInfo(t *testing.T) {
+	container := &SAMLContainer{
+		userNameAttribute: "uid",
+		emailAttribute:    "urn:oid:0.9.2342.19200300.100.1.3",
+		nickNameAttribute: "displayName",
+	}
+
+	assertion := &saml.Assertion{
+		Subject: &saml.Subject{
+			NameID: &saml.NameID{Value: "name-id"},
+		},
+		AttributeStatements: []saml.AttributeStatement{
+			{
+				Attributes: []saml.Attribute{
+					{Name: "uid", Values: []saml.AttributeValue{{Value: ""}, {Value: " user "}}},
+					{Name: "urn:oid:0.9.2342.19200300.100.1.3", FriendlyName: "mail", Values: []saml.AttributeValue{{Value: "user@example.com"}}},
+					{Name: "urn:oid:2.16.840.1.113730.3.1.241", FriendlyName: "displayName", Values: []saml.AttributeValue{{Value: "User"}}},
+				},
+			},
+		},
+	}
+
+	userInfo := container.getUserInfo(assertion)
+	assert.Equal(t, "name-id", userInfo.NameID)
+	assert.Equal(t, "user", userInfo.UserName)
+	assert.Equal(t, "user@example.com", userInfo.Email)
+	assert.Equal(t, "User", userInfo.NickName)
+
+	container.userNameAttribute = "username"
+	userInfo = container.getUserInfo(assertion)
+	assert.Equal(t, "name-id", userInfo.UserName)
+}
+
+func TestInitializeSAMLServiceProvider_InvalidConfig(t *testing.T) {
+	config := &settings.Config{
+		EnableSAMLLogin:      true,
+		RootUrl:              "https://ezbookkeeping.example.com/",
+		SAMLSignAuthnRequest: true,
+	}
+
+	assert.Equal(t, errs.ErrInvalidSAMLConfig, InitializeSAMLServiceProvider(config))
+
+	config.SAMLIdPMetadataUrl = "https://idp.example.com/metadata"
+	assert.Equal(t, errs.ErrInvalidSAMLConfig, InitializeSAMLServiceProvider(config))
+
+	config.EnableSAMLLogin = false
+	assert.Nil(t, InitializeSAMLServiceProvider(config))
+}
+
+func TestGetSAMLAuthnRequestUrl(t *testing.T) {
+	tempDir := t.TempDir()
+	certFile, keyFile := writeTestServiceProviderKeyPair(t, tempDir)
+	metadataFile := filepath.Join(tempDir, "idp_metadata.xml")
+	assert.Nil(t, os.WriteFile(metadataFile, []byte(testIdentityProviderMetadata), 0600))
+
+	config := &settings.Config{
+		EnableSAMLLogin:       true,
+		RootUrl:               "https://ezbookkeeping.example.com/",
+		SAMLIdPMetadataFile:   metadataFile,
+		SAMLSPEntityID:        "https://ezbookkeeping.example.com/saml/metadata",
+		SAMLSPCertificateFile: certFile,
+		SAMLSPPrivateKeyFile:  keyFile,
+		SAMLSignAuthnRequest:  true,
+	}
+
+	err := InitializeSAMLServiceProvider(config)
+	assert.Nil(t, err)
+
+	redirectUrl, requestId, err := GetSAMLAuthnRequestUrl(core.NewNullContext(), "desktop|session|verifier")
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", requestId)
+
+	actualUrl, err := url.Parse(redirectUrl)
+	assert.Nil(t, err)
+	assert.Equal(t, "idp.example.com", actualUrl.Host)
+	assert.Equal(t, "/sso", actualUrl.Path)
+	assert.NotEqual(t, "", actualUrl.Query().Get("SAMLRequest"))
+	assert.Equal(t, "desktop|session|verifier", actualUrl.Query().Get("RelayState"))
+	assert.Equal(t, "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256", actualUrl.Query().Get("SigAlg"))
+	assert.NotEqual(t, "", actualUrl.Query().Get("Signature"))
+
+	metadata, err := GetSAMLMetadata(core.NewNullContext())
+	assert.Nil(t, err)
+	assert.Contains(t, string(metadata), "entityID=\"https://ezbookkeeping.example.com/saml/metadata\"")
+	assert.Contains(t, string(metadata), "Location=\"https://ezbookkeeping.example.com/saml/acs\"")
+	assert.Contains(t, string(metadata), "AuthnRequestsSigned=\"true\"")
+}
+
+func writeTestServiceProviderKeyPair(t *testing.T, dir string) (string, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ezbookkeeping.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	assert.Nil(t, err)
+
+	certFile := filepath.Join(dir, "sp.crt")
+	keyFile := filepath.Join(dir, "sp.key")
+
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}), 0600))
+
+	return certFile, keyFile
+}
@@ -0,0 +1,9 @@
+package saml
+
+// SAMLUserInfo represents the user info asserted by the SAML 2.0 identity provider
+type SAMLUserInfo struct {
+	NameID   string
+	UserName string
+	Email    string
+	NickName string
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 
@@ -28,8 +29,10 @@ type UserDataCli struct {
 	tags                    *services.TransactionTagService
 	users                   *services.UserService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
+	userPasskeys            *services.UserPasskeyService
 	tokens                  *services.TokenService
 	forgetPasswords         *services.ForgetPasswordService
+	userDataBackups         *services.UserDataBackupService
 }
 
 // Initialize a user data cli singleton instance
@@ -44,8 +47,10 @@ var (
 		tags:                    services.TransactionTags,
 		users:                   services.Users,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
+		userPasskeys:            services.UserPasskeys,
 		tokens:                  services.Tokens,
 		forgetPasswords:         services.ForgetPasswords,
+		userDataBackups:         services.UserDataBackups,
 	}
 )
 
@@ -364,6 +369,28 @@ func (l *UserDataCli) SetUserEmailUnverified(c *core.CliContext, username string
 	return nil
 }
 
+// SetUserRole sets user role according to the specified user name
+func (l *UserDataCli) SetUserRole(c *core.CliContext, username string, role models.UserRole) error {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.SetUserRole] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
+
+	if !role.IsValid() {
+		log.CliErrorf(c, "[user_data.SetUserRole] user role \"%d\" is invalid", role)
+		return errs.ErrUserRoleInvalid
+	}
+
+	err := l.users.UpdateUserRole(c, username, role)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.SetUserRole] failed to set user role by user name \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	return nil
+}
+
 // DeleteUser deletes user according to the specified user name
 func (l *UserDataCli) DeleteUser(c *core.CliContext, username string) error {
 	if username == "" {
@@ -406,7 +433,7 @@ func (l *UserDataCli) ListUserTokens(c *core.CliContext, username string) ([]*mo
 }
 
 // CreateNewUserToken returns a new token for the specified user
-func (l *UserDataCli) CreateNewUserToken(c *core.CliContext, username string, tokenType string, expiresInSeconds int64) (*models.TokenRecord, string, error) {
+func (l *UserDataCli) CreateNewUserToken(c *core.CliContext, username string, tokenType string, expiresInSeconds int64, apiTokenScopes core.APITokenScopes, mcpTokenScopes core.MCPTokenScopes) (*models.TokenRecord, string, error) {
 	if username == "" {
 		log.CliErrorf(c, "[user_data.CreateNewUserToken] user name is empty")
 		return nil, "", errs.ErrUsernameIsEmpty
@@ -431,7 +458,23 @@ func (l *UserDataCli) CreateNewUserToken(c *core.CliContext, username string, to
 			return nil, "", errs.ErrNotPermittedToPerformThisAction
 		}
 
-		token, tokenRecord, err = l.tokens.CreateAPITokenViaCli(c, user, expiresInSeconds)
+		tokenContext := ""
+
+		if len(apiTokenScopes) > 0 {
+			var tokenContextBytes []byte
+			tokenContextBytes, err = json.Marshal(&models.APITokenContext{
+				Scopes: apiTokenScopes,
+			})
+
+			if err != nil {
+				log.CliErrorf(c, "[user_data.CreateNewUserToken] failed to marshal api token context, because %s", err.Error())
+				return nil, "", errs.ErrOperationFailed
+			}
+
+			tokenContext = string(tokenContextBytes)
+		}
+
+		token, tokenRecord, err = l.tokens.CreateAPITokenViaCli(c, user, expiresInSeconds, tokenContext)
 	} else if tokenType == "mcp" {
 		if !l.CurrentConfig().EnableMCPServer {
 			return nil, "", errs.ErrMCPServerNotEnabled
@@ -441,7 +484,21 @@ func (l *UserDataCli) CreateNewUserToken(c *core.CliContext, username string, to
 			return nil, "", errs.ErrNotPermittedToPerformThisAction
 		}
 
-		token, tokenRecord, err = l.tokens.CreateMCPTokenViaCli(c, user, expiresInSeconds)
+		if len(mcpTokenScopes) < 1 {
+			mcpTokenScopes = core.AllMCPTokenScopes
+		}
+
+		var tokenContext []byte
+		tokenContext, err = json.Marshal(&models.MCPTokenContext{
+			Scopes: mcpTokenScopes,
+		})
+
+		if err != nil {
+			log.CliErrorf(c, "[user_data.CreateNewUserToken] failed to marshal mcp token context, because %s", err.Error())
+			return nil, "", errs.ErrOperationFailed
+		}
+
+		token, tokenRecord, err = l.tokens.CreateMCPTokenViaCli(c, user, expiresInSeconds, string(tokenContext))
 	} else {
 		return nil, "", errs.ErrParameterInvalid
 	}
@@ -554,6 +611,54 @@ func (l *UserDataCli) DisableUserTwoFactorAuthorization(c *core.CliContext, user
 	return nil
 }
 
+// ListUserPasskeys returns all passkeys of the specified user
+func (l *UserDataCli) ListUserPasskeys(c *core.CliContext, username string) ([]*models.UserPasskey, error) {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.ListUserPasskeys] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ListUserPasskeys] error occurs when getting user id by user name")
+		return nil, err
+	}
+
+	passkeys, err := l.userPasskeys.GetAllPasskeysByUid(c, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ListUserPasskeys] failed to get passkeys of user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	return passkeys, nil
+}
+
+// RevokeUserPasskey revokes the specified passkey of the user
+func (l *UserDataCli) RevokeUserPasskey(c *core.CliContext, username string, passkeyId int64) error {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.RevokeUserPasskey] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RevokeUserPasskey] error occurs when getting user id by user name")
+		return err
+	}
+
+	err = l.userPasskeys.DeletePasskey(c, uid, passkeyId)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RevokeUserPasskey] failed to revoke passkey \"id:%d\" of user \"%s\", because %s", passkeyId, username, err.Error())
+		return err
+	}
+
+	return nil
+}
+
 // CheckTransactionAndAccount checks whether all user transactions and all user accounts are correct
 func (l *UserDataCli) CheckTransactionAndAccount(c *core.CliContext, username string) (bool, error) {
 	if username == "" {
@@ -749,7 +854,7 @@ func (l *UserDataCli) FixTransactionTagIndexWithTransactionTime(c *core.CliConte
 	return true, nil
 }
 
-// ExportTransaction returns csv file content according user all transactions
+// ExportTransaction returns exported file content according user all transactions
 func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fileType string) ([]byte, error) {
 	if username == "" {
 		log.CliErrorf(c, "[user_data.ExportTransaction] user name is empty")
@@ -786,7 +891,7 @@ func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fil
 	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap)
 
 	if err != nil {
-		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get csv format exported data for \"%s\", because %s", username, err.Error())
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get %s format exported data for \"%s\", because %s", fileType, username, err.Error())
 		return nil, err
 	}
 
@@ -869,7 +974,9 @@ func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fil
 		return errs.ErrOperationFailed
 	}
 
-	err = l.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, nil)
+	newTransactionSplitsMap := parsedTransactions.ToTransactionSplitsMap()
+
+	err = l.transactions.ImportTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, newTransactionSplitsMap, nil)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ImportTransaction] failed to create transaction, because %s", err.Error())
@@ -879,6 +986,56 @@ func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fil
 	return nil
 }
 
+// BackupUserData returns the backup archive which contains user all data
+func (l *UserDataCli) BackupUserData(c *core.CliContext, username string) ([]byte, error) {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.BackupUserData] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.BackupUserData] error occurs when getting user id by user name")
+		return nil, err
+	}
+
+	result, err := l.userDataBackups.ExportBackupArchive(c, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.BackupUserData] failed to backup data for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RestoreUserData restores user all data from the backup archive, the specified user must not have any data
+func (l *UserDataCli) RestoreUserData(c *core.CliContext, username string, data []byte) error {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.RestoreUserData] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RestoreUserData] error occurs when getting user id by user name")
+		return err
+	}
+
+	backup, err := l.userDataBackups.RestoreBackupArchive(c, uid, data)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RestoreUserData] failed to restore data for user \"%s\", because %s", username, err.Error())
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.RestoreUserData] %d accounts, %d transaction categories, %d transaction tags, %d transactions and %d transaction templates have been restored to user \"%s\"", len(backup.Accounts), len(backup.TransactionCategories), len(backup.TransactionTags), len(backup.Transactions), len(backup.TransactionTemplates), username)
+
+	return nil
+}
+
 func (l *UserDataCli) getUserIdByUsername(c *core.CliContext, username string) (int64, error) {
 	user, err := l.GetUserByUsername(c, username)
 
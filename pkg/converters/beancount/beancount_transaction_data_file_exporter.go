@@ -0,0 +1,483 @@
+package beancount
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const beancountExportedDateFormat = "2006-01-02"
+const beancountExportedPostingIndent = "  "
+const beancountExportedUncategorizedAccountName = "Uncategorized"
+const beancountExportedBalanceModificationNarration = "Balance Modification"
+
+// beancountExportedBalanceAssertion defines the structure of balance assertion which will be exported to Beancount file
+type beancountExportedBalanceAssertion struct {
+	Date      string
+	Account   string
+	Amount    int64
+	Commodity string
+}
+
+// beancountTransactionDataExporter defines the structure of Beancount exporter for transaction data
+type beancountTransactionDataExporter struct {
+}
+
+// Initialize a beancount transaction data exporter singleton instance
+var (
+	BeancountTransactionDataExporter = &beancountTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported Beancount ledger, which contains open directives for all accounts and categories, transactions and balance assertions
+func (c *beancountTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	usedAccountNames := make(map[string]bool)
+	accountNames := c.getAccountNames(accountMap, usedAccountNames)
+	categoryNames := c.getCategoryNames(categoryMap, usedAccountNames)
+	openingBalanceAccountName := c.getAccountTypeName(beancountEquityAccountType) + beancountAccountNameItemsSeparator + beancountEquityAccountNameOpeningBalance
+	openingBalanceAccountUsed := false
+	uncategorizedAccountNames := make(map[beancountAccountType]string, 2)
+
+	sortedTransactions := make([]*models.Transaction, len(transactions))
+	copy(sortedTransactions, transactions)
+
+	sort.SliceStable(sortedTransactions, func(i, j int) bool {
+		return sortedTransactions[i].TransactionTime < sortedTransactions[j].TransactionTime
+	})
+
+	existsTransferOutTransactions := make(map[int64]bool)
+
+	for i := 0; i < len(sortedTransactions); i++ {
+		if sortedTransactions[i].Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			existsTransferOutTransactions[sortedTransactions[i].TransactionId] = true
+		}
+	}
+
+	entries := make([]*beancountTransactionEntry, 0, len(sortedTransactions))
+	balanceAssertions := make(map[int]*beancountExportedBalanceAssertion)
+	firstDate := ""
+
+	for i := 0; i < len(sortedTransactions); i++ {
+		transaction := sortedTransactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN && existsTransferOutTransactions[transaction.RelatedId] {
+			continue
+		}
+
+		account, exists := accountMap[transaction.AccountId]
+
+		if !exists {
+			log.Warnf(ctx, "[beancount_transaction_data_file_exporter.ToExportedContent] cannot find account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.AccountId, transaction.TransactionId, uid)
+			continue
+		}
+
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		transactionTime := time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(transactionTimeZone)
+
+		entry := &beancountTransactionEntry{
+			Date:      transactionTime.Format(beancountExportedDateFormat),
+			Directive: beancountDirectiveCompletedTransaction,
+			Narration: transaction.Comment,
+			Tags:      c.getTransactionTags(transaction.TransactionId, allTagIndexes, tagMap),
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			if entry.Narration == "" {
+				entry.Narration = beancountExportedBalanceModificationNarration
+			}
+
+			entry.Postings = []*beancountPosting{
+				c.createPosting(accountNames[account.AccountId], transaction.RelatedAccountAmount, account.Currency),
+				c.createPosting(openingBalanceAccountName, -transaction.RelatedAccountAmount, account.Currency),
+			}
+
+			openingBalanceAccountUsed = true
+			balanceAssertions[len(entries)] = &beancountExportedBalanceAssertion{
+				Date:      transactionTime.AddDate(0, 0, 1).Format(beancountExportedDateFormat), // balance assertion applies at the beginning of the date
+				Account:   accountNames[account.AccountId],
+				Amount:    transaction.Amount,
+				Commodity: account.Currency,
+			}
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME || transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			categoryAccountType := beancountIncomeAccountType
+			amount := transaction.Amount
+
+			if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+				categoryAccountType = beancountExpensesAccountType
+				amount = -amount
+			}
+
+			categoryAccountName, exists := categoryNames[transaction.CategoryId]
+
+			if !exists {
+				categoryAccountName = c.getAccountTypeName(categoryAccountType) + beancountAccountNameItemsSeparator + beancountExportedUncategorizedAccountName
+				uncategorizedAccountNames[categoryAccountType] = categoryAccountName
+			}
+
+			entry.Postings = []*beancountPosting{
+				c.createPosting(accountNames[account.AccountId], amount, account.Currency),
+				c.createPosting(categoryAccountName, -amount, account.Currency),
+			}
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			relatedAccount, exists := accountMap[transaction.RelatedAccountId]
+
+			if !exists {
+				log.Warnf(ctx, "[beancount_transaction_data_file_exporter.ToExportedContent] cannot find related account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.RelatedAccountId, transaction.TransactionId, uid)
+				continue
+			}
+
+			if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+				entry.Postings = c.createTransferPostings(accountNames[account.AccountId], transaction.Amount, account.Currency, accountNames[relatedAccount.AccountId], transaction.RelatedAccountAmount, relatedAccount.Currency)
+			} else { // only exists transfer in transaction, the source account is the related account
+				entry.Postings = c.createTransferPostings(accountNames[relatedAccount.AccountId], transaction.RelatedAccountAmount, relatedAccount.Currency, accountNames[account.AccountId], transaction.Amount, account.Currency)
+			}
+		} else {
+			continue
+		}
+
+		if firstDate == "" || entry.Date < firstDate {
+			firstDate = entry.Date
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if firstDate == "" {
+		firstDate = time.Now().Format(beancountExportedDateFormat)
+	}
+
+	var ret strings.Builder
+	accounts := c.getSortedAccounts(accountMap)
+	categories := c.getSortedCategories(categoryMap)
+
+	for i := 0; i < len(accounts); i++ {
+		if accountName, exists := accountNames[accounts[i].AccountId]; exists {
+			currency := accounts[i].Currency
+
+			if accounts[i].Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+				currency = ""
+			}
+
+			c.writeOpenDirective(&ret, firstDate, accountName, currency)
+		}
+	}
+
+	for i := 0; i < len(categories); i++ {
+		if categoryName, exists := categoryNames[categories[i].CategoryId]; exists {
+			c.writeOpenDirective(&ret, firstDate, categoryName, "")
+		}
+	}
+
+	for _, accountType := range []beancountAccountType{beancountIncomeAccountType, beancountExpensesAccountType} {
+		if accountName, exists := uncategorizedAccountNames[accountType]; exists && !usedAccountNames[accountName] {
+			c.writeOpenDirective(&ret, firstDate, accountName, "")
+		}
+	}
+
+	if openingBalanceAccountUsed {
+		c.writeOpenDirective(&ret, firstDate, openingBalanceAccountName, "")
+	}
+
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+
+		ret.WriteString("\n")
+		ret.WriteString(entry.Date + " " + string(entry.Directive) + " \"" + c.getEscapedText(entry.Narration) + "\"")
+
+		for j := 0; j < len(entry.Tags); j++ {
+			ret.WriteString(" " + string(beancountTagPrefix) + entry.Tags[j])
+		}
+
+		ret.WriteString("\n")
+
+		for j := 0; j < len(entry.Postings); j++ {
+			posting := entry.Postings[j]
+			ret.WriteString(beancountExportedPostingIndent + posting.Account + " " + posting.Amount + " " + posting.Commodity)
+
+			if posting.TotalCost != "" {
+				ret.WriteString(" " + string(beancountPricePrefix) + string(beancountPricePrefix) + " " + posting.TotalCost + " " + posting.TotalCostCommodity)
+			}
+
+			ret.WriteString("\n")
+		}
+
+		if balanceAssertion, exists := balanceAssertions[i]; exists {
+			amount := balanceAssertion.Amount + c.getSameDayPostingAmount(entries, i, balanceAssertion)
+			ret.WriteString(balanceAssertion.Date + " " + string(beancountDirectiveBalance) + " " + balanceAssertion.Account + " " + utils.FormatAmount(amount) + " " + balanceAssertion.Commodity + "\n")
+		}
+	}
+
+	return []byte(ret.String()), nil
+}
+
+func (c *beancountTransactionDataExporter) writeOpenDirective(builder *strings.Builder, date string, accountName string, currency string) {
+	builder.WriteString(date + " " + string(beancountDirectiveOpen) + " " + accountName)
+
+	if currency != "" {
+		builder.WriteString(" " + currency)
+	}
+
+	builder.WriteString("\n")
+}
+
+func (c *beancountTransactionDataExporter) createPosting(accountName string, amount int64, commodity string) *beancountPosting {
+	return &beancountPosting{
+		Account:   accountName,
+		Amount:    utils.FormatAmount(amount),
+		Commodity: commodity,
+	}
+}
+
+func (c *beancountTransactionDataExporter) createTransferPostings(fromAccountName string, fromAmount int64, fromCommodity string, toAccountName string, toAmount int64, toCommodity string) []*beancountPosting {
+	fromPosting := c.createPosting(fromAccountName, -fromAmount, fromCommodity)
+	toPosting := c.createPosting(toAccountName, toAmount, toCommodity)
+
+	if fromCommodity != toCommodity {
+		toPosting.TotalCost = utils.FormatAmount(fromAmount)
+		toPosting.TotalCostCommodity = fromCommodity
+	}
+
+	return []*beancountPosting{fromPosting, toPosting}
+}
+
+// getSameDayPostingAmount returns the total amount of postings to the asserted account in the entries after the balance modification on the same day
+func (c *beancountTransactionDataExporter) getSameDayPostingAmount(entries []*beancountTransactionEntry, entryIndex int, balanceAssertion *beancountExportedBalanceAssertion) int64 {
+	totalAmount := int64(0)
+	date := entries[entryIndex].Date
+
+	for i := entryIndex + 1; i < len(entries); i++ {
+		if entries[i].Date != date {
+			continue
+		}
+
+		for j := 0; j < len(entries[i].Postings); j++ {
+			posting := entries[i].Postings[j]
+
+			if posting.Account != balanceAssertion.Account || posting.Commodity != balanceAssertion.Commodity {
+				continue
+			}
+
+			amount, err := utils.ParseAmount(posting.Amount)
+
+			if err == nil {
+				totalAmount += amount
+			}
+		}
+	}
+
+	return totalAmount
+}
+
+func (c *beancountTransactionDataExporter) getAccountNames(accountMap map[int64]*models.Account, usedAccountNames map[string]bool) map[int64]string {
+	accounts := c.getSortedAccounts(accountMap)
+	accountNames := make(map[int64]string, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		parentName := ""
+
+		if account.ParentAccountId == models.LevelOneAccountParentId {
+			accountType := beancountAssetsAccountType
+
+			if account.Category.IsLiability() {
+				accountType = beancountLiabilitiesAccountType
+			}
+
+			parentName = c.getAccountTypeName(accountType)
+		} else {
+			parentName = accountNames[account.ParentAccountId]
+		}
+
+		if parentName == "" {
+			continue
+		}
+
+		accountNames[account.AccountId] = c.getUniqueAccountName(parentName, account.Name, usedAccountNames)
+	}
+
+	return accountNames
+}
+
+func (c *beancountTransactionDataExporter) getCategoryNames(categoryMap map[int64]*models.TransactionCategory, usedAccountNames map[string]bool) map[int64]string {
+	categories := c.getSortedCategories(categoryMap)
+	categoryNames := make(map[int64]string, len(categories))
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+		parentName := ""
+
+		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			if category.Type == models.CATEGORY_TYPE_INCOME {
+				parentName = c.getAccountTypeName(beancountIncomeAccountType)
+			} else if category.Type == models.CATEGORY_TYPE_EXPENSE {
+				parentName = c.getAccountTypeName(beancountExpensesAccountType)
+			}
+		} else {
+			parentName = categoryNames[category.ParentCategoryId]
+		}
+
+		if parentName == "" { // transfer categories cannot be represented in Beancount
+			continue
+		}
+
+		categoryNames[category.CategoryId] = c.getUniqueAccountName(parentName, category.Name, usedAccountNames)
+	}
+
+	return categoryNames
+}
+
+// getSortedAccounts returns the accounts ordered by level, category, display order and id, so that parent accounts are always before their sub-accounts
+func (c *beancountTransactionDataExporter) getSortedAccounts(accountMap map[int64]*models.Account) []*models.Account {
+	accounts := make([]*models.Account, 0, len(accountMap))
+
+	for _, account := range accountMap {
+		accounts = append(accounts, account)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if (accounts[i].ParentAccountId == models.LevelOneAccountParentId) != (accounts[j].ParentAccountId == models.LevelOneAccountParentId) {
+			return accounts[i].ParentAccountId == models.LevelOneAccountParentId
+		}
+
+		if accounts[i].Category != accounts[j].Category {
+			return accounts[i].Category < accounts[j].Category
+		}
+
+		if accounts[i].DisplayOrder != accounts[j].DisplayOrder {
+			return accounts[i].DisplayOrder < accounts[j].DisplayOrder
+		}
+
+		return accounts[i].AccountId < accounts[j].AccountId
+	})
+
+	return accounts
+}
+
+// getSortedCategories returns the categories ordered by level, type, display order and id, so that parent categories are always before their sub-categories
+func (c *beancountTransactionDataExporter) getSortedCategories(categoryMap map[int64]*models.TransactionCategory) []*models.TransactionCategory {
+	categories := make([]*models.TransactionCategory, 0, len(categoryMap))
+
+	for _, category := range categoryMap {
+		categories = append(categories, category)
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if (categories[i].ParentCategoryId == models.LevelOneTransactionCategoryParentId) != (categories[j].ParentCategoryId == models.LevelOneTransactionCategoryParentId) {
+			return categories[i].ParentCategoryId == models.LevelOneTransactionCategoryParentId
+		}
+
+		if categories[i].Type != categories[j].Type {
+			return categories[i].Type < categories[j].Type
+		}
+
+		if categories[i].DisplayOrder != categories[j].DisplayOrder {
+			return categories[i].DisplayOrder < categories[j].DisplayOrder
+		}
+
+		return categories[i].CategoryId < categories[j].CategoryId
+	})
+
+	return categories
+}
+
+func (c *beancountTransactionDataExporter) getTransactionTags(transactionId int64, allTagIndexes map[int64][]int64, tagMap map[int64]*models.TransactionTag) []string {
+	tagIndexes, exists := allTagIndexes[transactionId]
+
+	if !exists {
+		return nil
+	}
+
+	tags := make([]string, 0, len(tagIndexes))
+
+	for i := 0; i < len(tagIndexes); i++ {
+		tag, exists := tagMap[tagIndexes[i]]
+
+		if !exists {
+			continue
+		}
+
+		tagName := c.getNormalizedName(tag.Name, "_/.")
+
+		if tagName != "" {
+			tags = append(tags, tagName)
+		}
+	}
+
+	return tags
+}
+
+func (c *beancountTransactionDataExporter) getUniqueAccountName(parentName string, name string, usedAccountNames map[string]bool) string {
+	normalizedName := c.getNormalizedName(name, "")
+
+	if normalizedName == "" {
+		normalizedName = beancountExportedUncategorizedAccountName
+	}
+
+	// account name components must start with a capital letter or a number
+	firstChar := []rune(normalizedName)[0]
+
+	if unicode.IsLower(firstChar) {
+		normalizedName = string(unicode.ToUpper(firstChar)) + string([]rune(normalizedName)[1:])
+	} else if firstChar < unicode.MaxASCII && !unicode.IsUpper(firstChar) && !unicode.IsDigit(firstChar) {
+		normalizedName = "X" + normalizedName
+	}
+
+	finalName := parentName + beancountAccountNameItemsSeparator + normalizedName
+
+	for i := 2; usedAccountNames[finalName]; i++ {
+		finalName = parentName + beancountAccountNameItemsSeparator + normalizedName + "-" + utils.IntToString(i)
+	}
+
+	usedAccountNames[finalName] = true
+
+	return finalName
+}
+
+// getNormalizedName returns the name which only contains letters, digits and the specified allowed characters, other continuous characters are replaced with one dash
+func (c *beancountTransactionDataExporter) getNormalizedName(name string, allowedChars string) string {
+	var ret strings.Builder
+	lastDash := false
+
+	for _, ch := range strings.TrimSpace(name) {
+		if ch != '-' && (unicode.IsLetter(ch) || unicode.IsDigit(ch) || strings.ContainsRune(allowedChars, ch)) {
+			ret.WriteRune(ch)
+			lastDash = false
+		} else if !lastDash {
+			ret.WriteRune('-')
+			lastDash = true
+		}
+	}
+
+	return strings.Trim(ret.String(), "-")
+}
+
+func (c *beancountTransactionDataExporter) getEscapedText(text string) string {
+	text = strings.ReplaceAll(text, "\r", " ")
+	text = strings.ReplaceAll(text, "\n", " ")
+	text = strings.ReplaceAll(text, "\\", "\\\\")
+	text = strings.ReplaceAll(text, "\"", "'")
+
+	return text
+}
+
+func (c *beancountTransactionDataExporter) getAccountTypeName(accountType beancountAccountType) string {
+	switch accountType {
+	case beancountAssetsAccountType:
+		return beancountDefaultAssetsAccountTypeName
+	case beancountLiabilitiesAccountType:
+		return beancountDefaultLiabilitiesAccountTypeName
+	case beancountEquityAccountType:
+		return beancountDefaultEquityAccountTypeName
+	case beancountIncomeAccountType:
+		return beancountDefaultIncomeAccountTypeName
+	case beancountExpensesAccountType:
+		return beancountDefaultExpenseAccountTypeName
+	default:
+		return ""
+	}
+}
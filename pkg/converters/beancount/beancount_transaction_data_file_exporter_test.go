@@ -0,0 +1,130 @@
+package beancount
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestBeancountTransactionDataFileToExportedContent(t *testing.T) {
+	exporter := BeancountTransactionDataExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Bank", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS, Currency: "CNY"},
+		2: {AccountId: 2, ParentAccountId: 1, Name: "my checking", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "CNY"},
+		3: {AccountId: 3, Name: "Credit Card", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "CNY", DisplayOrder: 1},
+		4: {AccountId: 4, Name: "USD Cash", Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Salary", Type: models.CATEGORY_TYPE_INCOME},
+		20: {CategoryId: 20, Name: "Food & Drink", Type: models.CATEGORY_TYPE_EXPENSE},
+		21: {CategoryId: 21, ParentCategoryId: 20, Name: "Lunch", Type: models.CATEGORY_TYPE_EXPENSE},
+		30: {CategoryId: 30, Name: "Transfer", Type: models.CATEGORY_TYPE_TRANSFER},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		100: {TagId: 100, Name: "work trip"},
+		101: {TagId: 101, Name: "2024"},
+	}
+	allTagIndexes := map[int64][]int64{
+		1003: {100, 101},
+	}
+
+	transactionTime := time.Date(2024, time.September, 1, 1, 23, 45, 0, time.UTC).Unix()
+	transactions := []*models.Transaction{
+		{TransactionId: 1006, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, CategoryId: 30, AccountId: 4, Amount: 100, RelatedId: 1005, RelatedAccountId: 2, RelatedAccountAmount: 700, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 86400), TimezoneUtcOffset: 480},
+		{TransactionId: 1005, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, CategoryId: 30, AccountId: 2, Amount: 700, RelatedId: 1006, RelatedAccountId: 4, RelatedAccountAmount: 100, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 86400), TimezoneUtcOffset: 480},
+		{TransactionId: 1004, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 10, AccountId: 2, Amount: 100000, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 3), TimezoneUtcOffset: 480},
+		{TransactionId: 1003, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 21, AccountId: 3, Amount: 1234, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 2), TimezoneUtcOffset: 480, Comment: "Lunch \"with\" team"},
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 2, Amount: 50000, RelatedAccountId: 2, RelatedAccountAmount: 50000, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 1), TimezoneUtcOffset: 480},
+	}
+
+	content, err := exporter.ToExportedContent(context, 1234567890, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	assert.Nil(t, err)
+
+	expectedContent := "2024-09-01 open Assets:USD-Cash USD\n" +
+		"2024-09-01 open Assets:Bank\n" +
+		"2024-09-01 open Liabilities:Credit-Card CNY\n" +
+		"2024-09-01 open Assets:Bank:My-checking CNY\n" +
+		"2024-09-01 open Income:Salary\n" +
+		"2024-09-01 open Expenses:Food-Drink\n" +
+		"2024-09-01 open Expenses:Food-Drink:Lunch\n" +
+		"2024-09-01 open Equity:Opening-Balances\n" +
+		"\n" +
+		"2024-09-01 * \"Balance Modification\"\n" +
+		"  Assets:Bank:My-checking 500.00 CNY\n" +
+		"  Equity:Opening-Balances -500.00 CNY\n" +
+		"2024-09-02 balance Assets:Bank:My-checking 1500.00 CNY\n" +
+		"\n" +
+		"2024-09-01 * \"Lunch 'with' team\" #work-trip #2024\n" +
+		"  Liabilities:Credit-Card -12.34 CNY\n" +
+		"  Expenses:Food-Drink:Lunch 12.34 CNY\n" +
+		"\n" +
+		"2024-09-01 * \"\"\n" +
+		"  Assets:Bank:My-checking 1000.00 CNY\n" +
+		"  Income:Salary -1000.00 CNY\n" +
+		"\n" +
+		"2024-09-02 * \"\"\n" +
+		"  Assets:Bank:My-checking -7.00 CNY\n" +
+		"  Assets:USD-Cash 1.00 USD @@ 7.00 CNY\n"
+
+	assert.Equal(t, expectedContent, string(content))
+}
+
+func TestBeancountTransactionDataFileToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := BeancountTransactionDataExporter
+	importer := BeancountTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Checking", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "CNY"},
+		2: {AccountId: 2, Name: "Cash", Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		100: {TagId: 100, Name: "tag1"},
+	}
+	allTagIndexes := map[int64][]int64{
+		1001: {100},
+	}
+
+	transactionTime := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC).Unix()
+	transactions := []*models.Transaction{
+		{TransactionId: 1001, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 10, AccountId: 1, Amount: 1234, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime), Comment: "Lunch"},
+		{TransactionId: 1002, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, Amount: 700, RelatedId: 1003, RelatedAccountId: 2, RelatedAccountAmount: 100, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 1)},
+		{TransactionId: 1003, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 2, Amount: 100, RelatedId: 1002, RelatedAccountId: 1, RelatedAccountAmount: 700, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 1)},
+	}
+
+	content, err := exporter.ToExportedContent(context, user.Uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	assert.Nil(t, err)
+
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1234), allNewTransactions[0].Amount)
+	assert.Equal(t, "Assets:Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Expenses:Food", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, "Lunch", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[1].Type)
+	assert.Equal(t, int64(700), allNewTransactions[1].Amount)
+	assert.Equal(t, "Assets:Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, int64(100), allNewTransactions[1].RelatedAccountAmount)
+	assert.Equal(t, "Assets:Cash", allNewTransactions[1].OriginalDestinationAccountName)
+	assert.Equal(t, "USD", allNewTransactions[1].OriginalDestinationAccountCurrency)
+}
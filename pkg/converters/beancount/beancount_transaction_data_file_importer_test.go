@@ -343,6 +343,49 @@ func TestBeancountTransactionDataFileParseImportedData_InvalidTransaction(t *tes
 	assert.EqualError(t, err, errs.ErrThereAreNotSupportedTransactionType.Message)
 }
 
+func TestBeancountTransactionDataFileParseImportedData_ParseSplitTransaction(t *testing.T) {
+	importer := BeancountTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, allNewSubIncomeCategories, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"2024-09-01 * \"Supermarket\"\n"+
+			"  Assets:TestAccount -0.23 CNY\n"+
+			"  Expenses:TestCategory 0.11 CNY\n"+
+			"  Expenses:TestCategory2 0.12 CNY\n"+
+			"2024-09-02 * \"Salary\"\n"+
+			"  Income:TestCategory3 -1.00 CNY\n"+
+			"  Income:TestCategory4 -0.50 CNY\n"+
+			"  Assets:TestAccount 1.50 CNY\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 2, len(allNewSubIncomeCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(23), allNewTransactions[0].Amount)
+	assert.Equal(t, "Assets:TestAccount", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Expenses:TestCategory", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, 2, len(allNewTransactions[0].Splits))
+	assert.Equal(t, "Expenses:TestCategory", allNewTransactions[0].Splits[0].OriginalCategoryName)
+	assert.Equal(t, int64(11), allNewTransactions[0].Splits[0].Amount)
+	assert.Equal(t, "Expenses:TestCategory2", allNewTransactions[0].Splits[1].OriginalCategoryName)
+	assert.Equal(t, int64(12), allNewTransactions[0].Splits[1].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(150), allNewTransactions[1].Amount)
+	assert.Equal(t, 2, len(allNewTransactions[1].Splits))
+	assert.Equal(t, "Income:TestCategory3", allNewTransactions[1].Splits[0].OriginalCategoryName)
+	assert.Equal(t, int64(100), allNewTransactions[1].Splits[0].Amount)
+	assert.Equal(t, "Income:TestCategory4", allNewTransactions[1].Splits[1].OriginalCategoryName)
+	assert.Equal(t, int64(50), allNewTransactions[1].Splits[1].Amount)
+}
+
 func TestBeancountTransactionDataFileParseImportedData_NotSupportedToParseSplitTransaction(t *testing.T) {
 	importer := BeancountTransactionDataImporter
 	context := core.NewNullContext()
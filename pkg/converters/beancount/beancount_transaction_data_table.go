@@ -37,6 +37,7 @@ type beancountTransactionDataRow struct {
 	dataTable  *beancountTransactionDataTable
 	data       *beancountTransactionEntry
 	finalItems map[datatable.TransactionDataTableColumn]string
+	splits     []*datatable.TransactionDataRowSplit
 }
 
 // beancountTransactionDataRowIterator defines the structure of Beancount transaction data row iterator
@@ -80,6 +81,11 @@ func (r *beancountTransactionDataRow) GetData(column datatable.TransactionDataTa
 	return ""
 }
 
+// GetSplits returns the split lines of this row, or nil if this row is not a split transaction
+func (r *beancountTransactionDataRow) GetSplits() []*datatable.TransactionDataRowSplit {
+	return r.splits
+}
+
 // HasNext returns whether the iterator does not reach the end
 func (t *beancountTransactionDataRowIterator) HasNext() bool {
 	return t.currentIndex+1 < len(t.dataTable.allData)
@@ -94,7 +100,7 @@ func (t *beancountTransactionDataRowIterator) Next(ctx core.Context, user *model
 	t.currentIndex++
 
 	data := t.dataTable.allData[t.currentIndex]
-	rowItems, err := t.parseTransaction(ctx, user, data)
+	rowItems, splits, err := t.parseTransaction(ctx, user, data)
 
 	if err != nil {
 		return nil, err
@@ -104,14 +110,16 @@ func (t *beancountTransactionDataRowIterator) Next(ctx core.Context, user *model
 		dataTable:  t.dataTable,
 		data:       data,
 		finalItems: rowItems,
+		splits:     splits,
 	}, nil
 }
 
-func (t *beancountTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, beancountEntry *beancountTransactionEntry) (map[datatable.TransactionDataTableColumn]string, error) {
+func (t *beancountTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, beancountEntry *beancountTransactionEntry) (map[datatable.TransactionDataTableColumn]string, []*datatable.TransactionDataRowSplit, error) {
 	data := make(map[datatable.TransactionDataTableColumn]string, len(beancountTransactionSupportedColumns))
+	var splits []*datatable.TransactionDataRowSplit
 
 	if beancountEntry.Date == "" {
-		return nil, errs.ErrMissingTransactionTime
+		return nil, nil, errs.ErrMissingTransactionTime
 	}
 
 	// Beancount supports the international ISO 8601 standard format for dates, with dashes or the same ordering with slashes
@@ -125,21 +133,21 @@ func (t *beancountTransactionDataRowIterator) parseTransaction(ctx core.Context,
 		account2 := t.dataTable.accountMap[splitData2.Account]
 
 		if account1 == nil || account2 == nil {
-			return nil, errs.ErrMissingAccountData
+			return nil, nil, errs.ErrMissingAccountData
 		}
 
 		amount1, err := utils.ParseAmount(splitData1.Amount)
 
 		if err != nil {
 			log.Errorf(ctx, "[beancount_transaction_data_table.parseTransaction] cannot parse amount \"%s\", because %s", splitData1.Amount, err.Error())
-			return nil, errs.ErrAmountInvalid
+			return nil, nil, errs.ErrAmountInvalid
 		}
 
 		amount2, err := utils.ParseAmount(splitData2.Amount)
 
 		if err != nil {
 			log.Errorf(ctx, "[beancount_transaction_data_table.parseTransaction] cannot parse amount \"%s\", because %s", splitData2.Amount, err.Error())
-			return nil, errs.ErrAmountInvalid
+			return nil, nil, errs.ErrAmountInvalid
 		}
 
 		if ((account1.AccountType == beancountEquityAccountType || account1.AccountType == beancountIncomeAccountType) && (account2.AccountType == beancountAssetsAccountType || account2.AccountType == beancountLiabilitiesAccountType)) ||
@@ -207,7 +215,7 @@ func (t *beancountTransactionDataRowIterator) parseTransaction(ctx core.Context,
 				toAmount = amount1
 			} else {
 				log.Errorf(ctx, "[beancount_transaction_data_table.parseTransaction] cannot parse transfer transaction, because unexcepted account amounts \"%d\" and \"%d\"", amount1, amount2)
-				return nil, errs.ErrInvalidBeancountFile
+				return nil, nil, errs.ErrInvalidBeancountFile
 			}
 
 			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER))
@@ -220,20 +228,110 @@ func (t *beancountTransactionDataRowIterator) parseTransaction(ctx core.Context,
 			data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(toAmount)
 		} else {
 			log.Errorf(ctx, "[beancount_transaction_data_table.parseTransaction] cannot parse transaction, because unexcepted account types \"%d\" and \"%d\"", account1.AccountType, account2.AccountType)
-			return nil, errs.ErrThereAreNotSupportedTransactionType
+			return nil, nil, errs.ErrThereAreNotSupportedTransactionType
 		}
 	} else if len(beancountEntry.Postings) <= 1 {
 		log.Errorf(ctx, "[beancount_transaction_data_table.parseTransaction] cannot parse transaction, because postings count is %d", len(beancountEntry.Postings))
-		return nil, errs.ErrInvalidBeancountFile
+		return nil, nil, errs.ErrInvalidBeancountFile
 	} else {
-		log.Errorf(ctx, "[beancount_transaction_data_table.parseTransaction] cannot parse split transaction, because postings count is %d", len(beancountEntry.Postings))
-		return nil, errs.ErrNotSupportedSplitTransactions
+		var err error
+		splits, err = t.parseSplitTransaction(ctx, beancountEntry, data)
+
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(beancountEntry.Tags, BEANCOUNT_TRANSACTION_TAG_SEPARATOR)
 	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = beancountEntry.Narration
 
-	return data, nil
+	return data, splits, nil
+}
+
+func (t *beancountTransactionDataRowIterator) parseSplitTransaction(ctx core.Context, beancountEntry *beancountTransactionEntry, data map[datatable.TransactionDataTableColumn]string) ([]*datatable.TransactionDataRowSplit, error) {
+	var assetOrLiabilityPosting *beancountPosting
+	var assetOrLiabilityAccount *beancountAccount
+	var assetOrLiabilityAmount int64
+	categoryAccounts := make([]*beancountAccount, 0, len(beancountEntry.Postings))
+	categoryAmounts := make([]int64, 0, len(beancountEntry.Postings))
+	expensePostingCount := 0
+	incomePostingCount := 0
+
+	for i := 0; i < len(beancountEntry.Postings); i++ {
+		posting := beancountEntry.Postings[i]
+		account := t.dataTable.accountMap[posting.Account]
+
+		if account == nil {
+			return nil, errs.ErrMissingAccountData
+		}
+
+		amount, err := utils.ParseAmount(posting.Amount)
+
+		if err != nil {
+			log.Errorf(ctx, "[beancount_transaction_data_table.parseSplitTransaction] cannot parse amount \"%s\", because %s", posting.Amount, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		if account.AccountType == beancountAssetsAccountType || account.AccountType == beancountLiabilitiesAccountType {
+			if assetOrLiabilityAccount != nil { // only one assets or liabilities account is supported in split transaction
+				log.Errorf(ctx, "[beancount_transaction_data_table.parseSplitTransaction] cannot parse split transaction, because there are more than one assets or liabilities account")
+				return nil, errs.ErrNotSupportedSplitTransactions
+			}
+
+			assetOrLiabilityPosting = posting
+			assetOrLiabilityAccount = account
+			assetOrLiabilityAmount = amount
+			continue
+		}
+
+		if account.AccountType == beancountExpensesAccountType {
+			expensePostingCount++
+		} else if account.AccountType == beancountIncomeAccountType {
+			incomePostingCount++
+		} else {
+			log.Errorf(ctx, "[beancount_transaction_data_table.parseSplitTransaction] cannot parse split transaction, because unexcepted account type \"%d\"", account.AccountType)
+			return nil, errs.ErrNotSupportedSplitTransactions
+		}
+
+		categoryAccounts = append(categoryAccounts, account)
+		categoryAmounts = append(categoryAmounts, amount)
+	}
+
+	if assetOrLiabilityAccount == nil || (expensePostingCount > 0 && incomePostingCount > 0) {
+		log.Errorf(ctx, "[beancount_transaction_data_table.parseSplitTransaction] cannot parse split transaction, because postings count is %d", len(beancountEntry.Postings))
+		return nil, errs.ErrNotSupportedSplitTransactions
+	}
+
+	isExpense := expensePostingCount > 0
+	splits := make([]*datatable.TransactionDataRowSplit, len(categoryAccounts))
+
+	for i := 0; i < len(categoryAccounts); i++ {
+		amount := categoryAmounts[i]
+
+		if !isExpense {
+			amount = -amount
+		}
+
+		splits[i] = &datatable.TransactionDataRowSplit{
+			SubCategory: categoryAccounts[i].Name,
+			Amount:      utils.FormatAmount(amount),
+		}
+	}
+
+	if isExpense {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_EXPENSE))
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-assetOrLiabilityAmount)
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_INCOME))
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(assetOrLiabilityAmount)
+	}
+
+	// the category of split transaction is the category of its first split line
+	data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = splits[0].SubCategory
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = assetOrLiabilityAccount.Name
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = assetOrLiabilityPosting.Commodity
+
+	return splits, nil
 }
 
 func createNewBeancountTransactionDataTable(beancountData *beancountData) (*beancountTransactionDataTable, error) {
@@ -39,9 +39,11 @@ type camtAccount struct {
 }
 
 type camtEntry struct {
+	EntryReference             string                   `xml:"NtryRef"`
 	Amount                     *camtAmount              `xml:"Amt"`
 	CreditDebitIndicator       camtCreditDebitIndicator `xml:"CdtDbtInd"`
 	BookingDate                *camtDate                `xml:"BookgDt"`
+	AccountServicerReference   string                   `xml:"AcctSvcrRef"`
 	EntryDetails               *camtEntryDetails        `xml:"NtryDtls"`
 	AdditionalEntryInformation string                   `xml:"AddtlNtryInf"`
 }
@@ -61,11 +63,16 @@ type camtEntryDetails struct {
 }
 
 type camtTransactionDetails struct {
+	References                       *camtTransactionReferences `xml:"Refs"`
 	AmountDetails                    *camtAmountDetails         `xml:"AmtDtls"`
 	RemittanceInformation            *camtRemittanceInformation `xml:"RmtInf"`
 	AdditionalTransactionInformation string                     `xml:"AddtlTxInf"`
 }
 
+type camtTransactionReferences struct {
+	AccountServicerReference string `xml:"AcctSvcrRef"`
+}
+
 type camtAmountDetails struct {
 	InstructedAmount  *camtAmount `xml:"InstdAmt>Amt"`
 	TransactionAmount *camtAmount `xml:"TxAmt>Amt"`
@@ -22,6 +22,7 @@ var camtTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]b
 	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_EXTERNAL_REFERENCE:   true,
 }
 
 // camtStatementTransactionDataTable defines the structure of camt statement transaction data table
@@ -300,6 +301,16 @@ func (t *camtStatementTransactionDataRowIterator) parseTransaction(ctx core.Cont
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	if transactionDetails != nil && transactionDetails.References != nil && transactionDetails.References.AccountServicerReference != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_EXTERNAL_REFERENCE] = transactionDetails.References.AccountServicerReference
+	} else if entry.EntryDetails == nil || len(entry.EntryDetails.TransactionDetails) <= 1 { // the entry reference can only identify the transaction when there is no more than one transaction details in one entry
+		if entry.AccountServicerReference != "" {
+			data[datatable.TRANSACTION_DATA_TABLE_EXTERNAL_REFERENCE] = entry.AccountServicerReference
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_EXTERNAL_REFERENCE] = entry.EntryReference
+		}
+	}
+
 	return data, nil
 }
 
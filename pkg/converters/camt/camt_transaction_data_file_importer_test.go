@@ -746,6 +746,82 @@ func TestCamt053TransactionDataFileParseImportedData_ParseDescription(t *testing
 	assert.Equal(t, "Test Entry", allNewTransactions[0].Comment)
 }
 
+func TestCamt053TransactionDataFileParseImportedData_ParseExternalReference(t *testing.T) {
+	importer := Camt053TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		`<?xml version="1.0" encoding="UTF-8"?>
+		<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+			<BkToCstmrStmt>
+				<Stmt>
+					<Acct>
+						<Id>
+							<IBAN>123</IBAN>
+						</Id>
+						<Ccy>CNY</Ccy>
+					</Acct>
+					<Ntry>
+						<NtryRef>ENTRY001</NtryRef>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>CRDT</CdtDbtInd>
+						<Amt Ccy="CNY">123.45</Amt>
+						<AcctSvcrRef>SVCR001</AcctSvcrRef>
+					</Ntry>
+					<Ntry>
+						<NtryRef>ENTRY002</NtryRef>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>CRDT</CdtDbtInd>
+						<Amt Ccy="CNY">123.45</Amt>
+					</Ntry>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>DBIT</CdtDbtInd>
+						<Amt Ccy="CNY">3.00</Amt>
+						<AcctSvcrRef>SVCR003</AcctSvcrRef>
+						<NtryDtls>
+							<TxDtls>
+								<Refs>
+									<AcctSvcrRef>SVCR003-1</AcctSvcrRef>
+								</Refs>
+								<AmtDtls>
+									<TxAmt>
+										<Amt Ccy="CNY">1.00</Amt>
+									</TxAmt>
+								</AmtDtls>
+							</TxDtls>
+							<TxDtls>
+								<AmtDtls>
+									<TxAmt>
+										<Amt Ccy="CNY">2.00</Amt>
+									</TxAmt>
+								</AmtDtls>
+							</TxDtls>
+						</NtryDtls>
+					</Ntry>
+				</Stmt>
+			</BkToCstmrStmt>
+		</Document>`), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, "SVCR001", allNewTransactions[0].ExternalReference)
+	assert.Equal(t, "ENTRY002", allNewTransactions[1].ExternalReference)
+	assert.Equal(t, "SVCR003-1", allNewTransactions[2].ExternalReference)
+	assert.Equal(t, "", allNewTransactions[3].ExternalReference)
+}
+
 func TestCamt053TransactionDataFileParseImportedData_MissingAccountNode(t *testing.T) {
 	importer := Camt053TransactionDataImporter
 	context := core.NewNullContext()
@@ -126,6 +126,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 		categoryId := int64(0)
 		categoryName := ""
 		subCategoryName := ""
+		var splits []*models.ImportTransactionSplit
 
 		if transactionDbType != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			transactionCategoryType, err := c.getTransactionCategoryType(transactionDbType)
@@ -184,6 +185,65 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 
 				categoryId = subCategory.CategoryId
 			}
+
+			if splitDataRow, ok := dataRow.(datatable.TransactionDataRowWithSplits); ok && (transactionDbType == models.TRANSACTION_DB_TYPE_EXPENSE || transactionDbType == models.TRANSACTION_DB_TYPE_INCOME) {
+				dataRowSplits := splitDataRow.GetSplits()
+
+				for i := 0; i < len(dataRowSplits); i++ {
+					dataRowSplit := dataRowSplits[i]
+					var splitSubCategory *models.TransactionCategory
+
+					if transactionDbType == models.TRANSACTION_DB_TYPE_EXPENSE {
+						subCategory, exists := c.getTransactionCategory(expenseCategoryMap, dataRowSplit.Category, dataRowSplit.SubCategory)
+
+						if !exists {
+							subCategory = c.createNewTransactionCategoryModel(user.Uid, dataRowSplit.SubCategory, transactionCategoryType)
+							allNewSubExpenseCategories = append(allNewSubExpenseCategories, subCategory)
+
+							if _, exists = expenseCategoryMap[dataRowSplit.SubCategory]; !exists {
+								expenseCategoryMap[dataRowSplit.SubCategory] = make(map[string]*models.TransactionCategory)
+							}
+
+							expenseCategoryMap[dataRowSplit.SubCategory][dataRowSplit.Category] = subCategory
+						}
+
+						splitSubCategory = subCategory
+					} else if transactionDbType == models.TRANSACTION_DB_TYPE_INCOME {
+						subCategory, exists := c.getTransactionCategory(incomeCategoryMap, dataRowSplit.Category, dataRowSplit.SubCategory)
+
+						if !exists {
+							subCategory = c.createNewTransactionCategoryModel(user.Uid, dataRowSplit.SubCategory, transactionCategoryType)
+							allNewSubIncomeCategories = append(allNewSubIncomeCategories, subCategory)
+
+							if _, exists = incomeCategoryMap[dataRowSplit.SubCategory]; !exists {
+								incomeCategoryMap[dataRowSplit.SubCategory] = make(map[string]*models.TransactionCategory)
+							}
+
+							incomeCategoryMap[dataRowSplit.SubCategory][dataRowSplit.Category] = subCategory
+						}
+
+						splitSubCategory = subCategory
+					}
+
+					splitAmount, err := utils.ParseAmount(dataRowSplit.Amount)
+
+					if err != nil {
+						log.Errorf(ctx, "[data_table_transaction_data_importer.ParseImportedData] cannot parse split amount \"%s\" in data row \"index:%d\" for user \"uid:%d\", because %s", dataRowSplit.Amount, dataRowIndex, user.Uid, err.Error())
+						return nil, nil, nil, nil, nil, nil, errs.ErrAmountInvalid
+					}
+
+					splits = append(splits, &models.ImportTransactionSplit{
+						TransactionSplit: &models.TransactionSplit{
+							Uid:          user.Uid,
+							CategoryId:   splitSubCategory.CategoryId,
+							Amount:       splitAmount,
+							Comment:      dataRowSplit.Description,
+							DisplayOrder: int32(i + 1),
+						},
+						OriginalCategoryName: dataRowSplit.SubCategory,
+					})
+				}
+			}
 		}
 
 		accountName := dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME)
@@ -368,6 +428,12 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			description = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_PAYEE)
 		}
 
+		externalReference := ""
+
+		if dataTable.HasColumn(datatable.TRANSACTION_DATA_TABLE_EXTERNAL_REFERENCE) {
+			externalReference = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_EXTERNAL_REFERENCE)
+		}
+
 		transaction := &models.ImportTransaction{
 			Transaction: &models.Transaction{
 				Uid:                  user.Uid,
@@ -383,6 +449,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 				Comment:              description,
 				GeoLongitude:         geoLongitude,
 				GeoLatitude:          geoLatitude,
+				ExternalReference:    externalReference,
 				CreatedIp:            ctx.ClientIP(),
 			},
 			TagIds:                             tagIds,
@@ -392,6 +459,21 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			OriginalDestinationAccountName:     account2Name,
 			OriginalDestinationAccountCurrency: account2Currency,
 			OriginalTagNames:                   tagNames,
+			Splits:                             splits,
+		}
+
+		if additionalOptions.GetTransactionRuleEngine() != nil {
+			counterparty := ""
+
+			if dataTable.HasColumn(datatable.TRANSACTION_DATA_TABLE_PAYEE) {
+				counterparty = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_PAYEE)
+			}
+
+			if counterparty == "" && dataTable.HasColumn(datatable.TRANSACTION_DATA_TABLE_MERCHANT) {
+				counterparty = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_MERCHANT)
+			}
+
+			additionalOptions.GetTransactionRuleEngine().ApplyToImportTransaction(transaction, counterparty)
 		}
 
 		allNewTransactions = append(allNewTransactions, transaction)
@@ -3,6 +3,7 @@ package converter
 import (
 	"strings"
 
+	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
@@ -16,6 +17,7 @@ type TransactionDataImporterOptions struct {
 	merchantAsTag      bool
 	aiAdditionalPrompt string
 	aiImageContentType string
+	ruleEngine         *models.TransactionRuleEngine
 }
 
 // DefaultImporterOptions provides the default options for transaction data importer
@@ -28,6 +30,7 @@ var DefaultImporterOptions = TransactionDataImporterOptions{
 	merchantAsTag:      false,
 	aiAdditionalPrompt: "",
 	aiImageContentType: "",
+	ruleEngine:         nil,
 }
 
 // GetCurrentConfig returns the current config
@@ -70,6 +73,11 @@ func (o TransactionDataImporterOptions) GetAIImageContentType() string {
 	return o.aiImageContentType
 }
 
+// GetTransactionRuleEngine returns the transaction rule engine which is applied to the imported transactions
+func (o TransactionDataImporterOptions) GetTransactionRuleEngine() *models.TransactionRuleEngine {
+	return o.ruleEngine
+}
+
 // WithPayeeAsTag sets the option to import payee as tag
 func (o TransactionDataImporterOptions) WithPayeeAsTag() TransactionDataImporterOptions {
 	cloned := o.Clone()
@@ -119,6 +127,13 @@ func (o TransactionDataImporterOptions) WithAIImageContentType(contentType strin
 	return cloned
 }
 
+// WithTransactionRuleEngine sets the transaction rule engine which is applied to the imported transactions
+func (o TransactionDataImporterOptions) WithTransactionRuleEngine(ruleEngine *models.TransactionRuleEngine) TransactionDataImporterOptions {
+	cloned := o.Clone()
+	cloned.ruleEngine = ruleEngine
+	return cloned
+}
+
 // Clone creates a copy of the options instance
 func (o TransactionDataImporterOptions) Clone() TransactionDataImporterOptions {
 	return TransactionDataImporterOptions{
@@ -130,6 +145,7 @@ func (o TransactionDataImporterOptions) Clone() TransactionDataImporterOptions {
 		merchantAsTag:      o.merchantAsTag,
 		aiAdditionalPrompt: o.aiAdditionalPrompt,
 		aiImageContentType: o.aiImageContentType,
+		ruleEngine:         o.ruleEngine,
 	}
 }
 
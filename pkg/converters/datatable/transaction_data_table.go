@@ -26,6 +26,20 @@ type TransactionDataRow interface {
 	GetData(column TransactionDataTableColumn) string
 }
 
+// TransactionDataRowWithSplits defines the structure of transaction data row which contains split lines
+type TransactionDataRowWithSplits interface {
+	// GetSplits returns the split lines of this row, or nil if this row is not a split transaction
+	GetSplits() []*TransactionDataRowSplit
+}
+
+// TransactionDataRowSplit represents a split line of transaction data row
+type TransactionDataRowSplit struct {
+	Category    string
+	SubCategory string
+	Amount      string
+	Description string
+}
+
 // TransactionDataRowIterator defines the structure of transaction data row iterator
 type TransactionDataRowIterator interface {
 	// HasNext returns whether the iterator does not reach the end
@@ -76,6 +90,7 @@ const (
 	TRANSACTION_DATA_TABLE_MEMBER                   TransactionDataTableColumn = 102
 	TRANSACTION_DATA_TABLE_PROJECT                  TransactionDataTableColumn = 103
 	TRANSACTION_DATA_TABLE_MERCHANT                 TransactionDataTableColumn = 104
+	TRANSACTION_DATA_TABLE_EXTERNAL_REFERENCE       TransactionDataTableColumn = 105
 )
 
 // TRANSACTION_DATA_TABLE_TIMEZONE_NOT_AVAILABLE represents the constant for timezone not available
@@ -0,0 +1,645 @@
+package gnucash
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const gnucashExportedVersion = "2.0.0"
+const gnucashExportedDateTimeFormat = "2006-01-02 15:04:05 -0700"
+const gnucashExportedGuidType = "guid"
+const gnucashExportedSlotValueType = "string"
+const gnucashExportedCommoditySmallestUnit = "100"
+const gnucashExportedAmountDenominator = "/100"
+const gnucashExportedNotReconciledState = "n"
+
+const gnucashAssetAccountType = "ASSET"
+const gnucashBankAccountType = "BANK"
+const gnucashCashAccountType = "CASH"
+const gnucashCreditAccountType = "CREDIT"
+const gnucashLiabilityAccountType = "LIABILITY"
+
+const gnucashSlotPlaceholder = "placeholder"
+const gnucashSlotPlaceholderValue = "true"
+
+const gnucashExportedRootAccountName = "Root Account"
+const gnucashExportedAssetAccountName = "Assets"
+const gnucashExportedLiabilityAccountName = "Liabilities"
+const gnucashExportedIncomeAccountName = "Income"
+const gnucashExportedExpenseAccountName = "Expenses"
+const gnucashExportedEquityAccountName = "Equity"
+const gnucashExportedOpeningBalanceAccountName = "Opening Balances"
+const gnucashExportedUncategorizedAccountName = "Uncategorized"
+
+var gnucashExportedNamespaces = []xml.Attr{
+	{Name: xml.Name{Local: "xmlns:gnc"}, Value: "http://www.gnucash.org/XML/gnc"},
+	{Name: xml.Name{Local: "xmlns:act"}, Value: "http://www.gnucash.org/XML/act"},
+	{Name: xml.Name{Local: "xmlns:book"}, Value: "http://www.gnucash.org/XML/book"},
+	{Name: xml.Name{Local: "xmlns:cd"}, Value: "http://www.gnucash.org/XML/cd"},
+	{Name: xml.Name{Local: "xmlns:cmdty"}, Value: "http://www.gnucash.org/XML/cmdty"},
+	{Name: xml.Name{Local: "xmlns:slot"}, Value: "http://www.gnucash.org/XML/slot"},
+	{Name: xml.Name{Local: "xmlns:split"}, Value: "http://www.gnucash.org/XML/split"},
+	{Name: xml.Name{Local: "xmlns:trn"}, Value: "http://www.gnucash.org/XML/trn"},
+	{Name: xml.Name{Local: "xmlns:ts"}, Value: "http://www.gnucash.org/XML/ts"},
+}
+
+// gnucashExportedDatabase represents the struct of exported gnucash database file
+type gnucashExportedDatabase struct {
+	XMLName    xml.Name                    `xml:"gnc-v2"`
+	Namespaces []xml.Attr                  `xml:",any,attr"`
+	Counts     []*gnucashExportedCountData `xml:"gnc:count-data"`
+	Book       *gnucashExportedBookData    `xml:"gnc:book"`
+}
+
+// gnucashExportedCountData represents the struct of exported gnucash count data
+type gnucashExportedCountData struct {
+	Key   string `xml:"cd:type,attr"`
+	Value int    `xml:",chardata"`
+}
+
+// gnucashExportedGuid represents the struct of exported gnucash guid
+type gnucashExportedGuid struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// gnucashExportedBookData represents the struct of exported gnucash book data
+type gnucashExportedBookData struct {
+	Version      string                            `xml:"version,attr"`
+	Id           *gnucashExportedGuid              `xml:"book:id"`
+	Counts       []*gnucashExportedCountData       `xml:"gnc:count-data"`
+	Commodities  []*gnucashExportedCommodityData   `xml:"gnc:commodity"`
+	Accounts     []*gnucashExportedAccountData     `xml:"gnc:account"`
+	Transactions []*gnucashExportedTransactionData `xml:"gnc:transaction"`
+}
+
+// gnucashExportedCommodityData represents the struct of exported gnucash commodity data
+type gnucashExportedCommodityData struct {
+	Version string `xml:"version,attr,omitempty"`
+	Space   string `xml:"cmdty:space"`
+	Id      string `xml:"cmdty:id"`
+}
+
+// gnucashExportedSlotData represents the struct of exported gnucash slot data
+type gnucashExportedSlotData struct {
+	Key   string                        `xml:"slot:key"`
+	Value *gnucashExportedSlotValueData `xml:"slot:value"`
+}
+
+// gnucashExportedSlotValueData represents the struct of exported gnucash slot value data
+type gnucashExportedSlotValueData struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// gnucashExportedAccountData represents the struct of exported gnucash account data
+type gnucashExportedAccountData struct {
+	Version      string                        `xml:"version,attr"`
+	Name         string                        `xml:"act:name"`
+	Id           *gnucashExportedGuid          `xml:"act:id"`
+	AccountType  string                        `xml:"act:type"`
+	Commodity    *gnucashExportedCommodityData `xml:"act:commodity,omitempty"`
+	CommodityScu string                        `xml:"act:commodity-scu,omitempty"`
+	Description  string                        `xml:"act:description,omitempty"`
+	Slots        []*gnucashExportedSlotData    `xml:"act:slots>slot,omitempty"`
+	ParentId     *gnucashExportedGuid          `xml:"act:parent,omitempty"`
+}
+
+// gnucashExportedTransactionData represents the struct of exported gnucash transaction data
+type gnucashExportedTransactionData struct {
+	Version     string                                 `xml:"version,attr"`
+	Id          *gnucashExportedGuid                   `xml:"trn:id"`
+	Currency    *gnucashExportedCommodityData          `xml:"trn:currency"`
+	PostedDate  string                                 `xml:"trn:date-posted>ts:date"`
+	EnteredDate string                                 `xml:"trn:date-entered>ts:date"`
+	Description string                                 `xml:"trn:description"`
+	Splits      []*gnucashExportedTransactionSplitData `xml:"trn:splits>trn:split"`
+}
+
+// gnucashExportedTransactionSplitData represents the struct of exported gnucash transaction split data
+type gnucashExportedTransactionSplitData struct {
+	Id              *gnucashExportedGuid `xml:"split:id"`
+	ReconciledState string               `xml:"split:reconciled-state"`
+	Value           string               `xml:"split:value"`
+	Quantity        string               `xml:"split:quantity"`
+	Account         *gnucashExportedGuid `xml:"split:account"`
+}
+
+// gnucashExportedBookBuilder defines the structure of gnucash book builder, which creates accounts on demand
+type gnucashExportedBookBuilder struct {
+	uid                  int64
+	defaultCurrency      string
+	accounts             []*gnucashExportedAccountData
+	accountGuids         map[string]string
+	commodities          map[string]bool
+	rootAccountGuid      string
+	assetAccountGuid     string
+	liabilityAccountGuid string
+	incomeAccountGuid    string
+	expenseAccountGuid   string
+	equityAccountGuid    string
+}
+
+// gnucashTransactionDataExporter defines the structure of gnucash exporter for transaction data
+type gnucashTransactionDataExporter struct {
+}
+
+// Initialize a gnucash transaction data exporter singleton instance
+var (
+	GnuCashTransactionDataExporter = &gnucashTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported gzip compressed gnucash xml book, which contains the commodities, the account tree (accounts and categories) and the transactions with splits
+func (c *gnucashTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	builder := c.createBookBuilder(uid, accountMap)
+	c.addAccounts(builder, accountMap)
+	c.addCategories(builder, categoryMap)
+
+	sortedTransactions := make([]*models.Transaction, len(transactions))
+	copy(sortedTransactions, transactions)
+
+	sort.SliceStable(sortedTransactions, func(i, j int) bool {
+		return sortedTransactions[i].TransactionTime < sortedTransactions[j].TransactionTime
+	})
+
+	existsTransferOutTransactions := make(map[int64]bool)
+
+	for i := 0; i < len(sortedTransactions); i++ {
+		if sortedTransactions[i].Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			existsTransferOutTransactions[sortedTransactions[i].TransactionId] = true
+		}
+	}
+
+	exportedTransactions := make([]*gnucashExportedTransactionData, 0, len(sortedTransactions))
+
+	for i := 0; i < len(sortedTransactions); i++ {
+		transaction := sortedTransactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN && existsTransferOutTransactions[transaction.RelatedId] {
+			continue
+		}
+
+		exportedTransaction := c.createTransaction(ctx, builder, transaction, accountMap, categoryMap)
+
+		if exportedTransaction != nil {
+			exportedTransactions = append(exportedTransactions, exportedTransaction)
+		}
+	}
+
+	commodities := make([]*gnucashExportedCommodityData, 0, len(builder.commodities))
+
+	for currency := range builder.commodities {
+		commodities = append(commodities, &gnucashExportedCommodityData{
+			Version: gnucashExportedVersion,
+			Space:   gnucashCommodityCurrencySpace,
+			Id:      currency,
+		})
+	}
+
+	sort.Slice(commodities, func(i, j int) bool {
+		return commodities[i].Id < commodities[j].Id
+	})
+
+	database := &gnucashExportedDatabase{
+		Namespaces: gnucashExportedNamespaces,
+		Counts: []*gnucashExportedCountData{
+			{Key: "book", Value: 1},
+		},
+		Book: &gnucashExportedBookData{
+			Version: gnucashExportedVersion,
+			Id:      c.createGuid(c.getGuid(uid, "book", "")),
+			Counts: []*gnucashExportedCountData{
+				{Key: "commodity", Value: len(commodities)},
+				{Key: "account", Value: len(builder.accounts)},
+				{Key: "transaction", Value: len(exportedTransactions)},
+			},
+			Commodities:  commodities,
+			Accounts:     builder.accounts,
+			Transactions: exportedTransactions,
+		},
+	}
+
+	content, err := xml.MarshalIndent(database, "", "  ")
+
+	if err != nil {
+		log.Errorf(ctx, "[gnucash_transaction_data_file_exporter.ToExportedContent] failed to marshal gnucash book for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+
+	_, err = gzipWriter.Write([]byte(xml.Header))
+
+	if err == nil {
+		_, err = gzipWriter.Write(content)
+	}
+
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+
+	if err != nil {
+		log.Errorf(ctx, "[gnucash_transaction_data_file_exporter.ToExportedContent] failed to compress gnucash book for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (c *gnucashTransactionDataExporter) createTransaction(ctx core.Context, builder *gnucashExportedBookBuilder, transaction *models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory) *gnucashExportedTransactionData {
+	account, exists := accountMap[transaction.AccountId]
+
+	if !exists {
+		log.Warnf(ctx, "[gnucash_transaction_data_file_exporter.createTransaction] cannot find account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.AccountId, transaction.TransactionId, builder.uid)
+		return nil
+	}
+
+	accountGuid := builder.accountGuids[c.getAccountKey(account.AccountId)]
+	var splits []*gnucashExportedTransactionSplitData
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		equityAccountGuid := c.getOpeningBalanceAccountGuid(builder, account.Currency)
+		splits = []*gnucashExportedTransactionSplitData{
+			c.createSplit(builder.uid, transaction.TransactionId, 0, accountGuid, transaction.RelatedAccountAmount, transaction.RelatedAccountAmount),
+			c.createSplit(builder.uid, transaction.TransactionId, 1, equityAccountGuid, -transaction.RelatedAccountAmount, -transaction.RelatedAccountAmount),
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		categoryAccountGuid := c.getCategoryAccountGuid(builder, categoryMap[transaction.CategoryId], models.CATEGORY_TYPE_INCOME, account.Currency)
+		splits = []*gnucashExportedTransactionSplitData{
+			c.createSplit(builder.uid, transaction.TransactionId, 0, accountGuid, transaction.Amount, transaction.Amount),
+			c.createSplit(builder.uid, transaction.TransactionId, 1, categoryAccountGuid, -transaction.Amount, -transaction.Amount),
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		categoryAccountGuid := c.getCategoryAccountGuid(builder, categoryMap[transaction.CategoryId], models.CATEGORY_TYPE_EXPENSE, account.Currency)
+		splits = []*gnucashExportedTransactionSplitData{
+			c.createSplit(builder.uid, transaction.TransactionId, 0, accountGuid, -transaction.Amount, -transaction.Amount),
+			c.createSplit(builder.uid, transaction.TransactionId, 1, categoryAccountGuid, transaction.Amount, transaction.Amount),
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		relatedAccount, exists := accountMap[transaction.RelatedAccountId]
+
+		if !exists {
+			log.Warnf(ctx, "[gnucash_transaction_data_file_exporter.createTransaction] cannot find related account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.RelatedAccountId, transaction.TransactionId, builder.uid)
+			return nil
+		}
+
+		relatedAccountGuid := builder.accountGuids[c.getAccountKey(relatedAccount.AccountId)]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			splits = []*gnucashExportedTransactionSplitData{
+				c.createSplit(builder.uid, transaction.TransactionId, 0, accountGuid, -transaction.Amount, -transaction.Amount),
+				c.createSplit(builder.uid, transaction.TransactionId, 1, relatedAccountGuid, transaction.Amount, transaction.RelatedAccountAmount),
+			}
+		} else {
+			splits = []*gnucashExportedTransactionSplitData{
+				c.createSplit(builder.uid, transaction.TransactionId, 0, relatedAccountGuid, -transaction.Amount, -transaction.RelatedAccountAmount),
+				c.createSplit(builder.uid, transaction.TransactionId, 1, accountGuid, transaction.Amount, transaction.Amount),
+			}
+		}
+	} else {
+		log.Warnf(ctx, "[gnucash_transaction_data_file_exporter.createTransaction] cannot export transaction \"id:%d\" for user \"uid:%d\", because transaction type \"%d\" is invalid", transaction.TransactionId, builder.uid, transaction.Type)
+		return nil
+	}
+
+	builder.commodities[account.Currency] = true
+
+	postedDate := c.formatDateTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), transaction.TimezoneUtcOffset)
+	enteredDate := postedDate
+
+	if transaction.CreatedUnixTime > 0 {
+		enteredDate = c.formatDateTime(transaction.CreatedUnixTime, transaction.TimezoneUtcOffset)
+	}
+
+	return &gnucashExportedTransactionData{
+		Version: gnucashExportedVersion,
+		Id:      c.createGuid(c.getGuid(builder.uid, "transaction", utils.Int64ToString(transaction.TransactionId))),
+		Currency: &gnucashExportedCommodityData{
+			Space: gnucashCommodityCurrencySpace,
+			Id:    account.Currency,
+		},
+		PostedDate:  postedDate,
+		EnteredDate: enteredDate,
+		Description: transaction.Comment,
+		Splits:      splits,
+	}
+}
+
+func (c *gnucashTransactionDataExporter) createSplit(uid int64, transactionId int64, index int, accountGuid string, value int64, quantity int64) *gnucashExportedTransactionSplitData {
+	return &gnucashExportedTransactionSplitData{
+		Id:              c.createGuid(c.getGuid(uid, "split", fmt.Sprintf("%d:%d", transactionId, index))),
+		ReconciledState: gnucashExportedNotReconciledState,
+		Value:           utils.Int64ToString(value) + gnucashExportedAmountDenominator,
+		Quantity:        utils.Int64ToString(quantity) + gnucashExportedAmountDenominator,
+		Account:         c.createGuid(accountGuid),
+	}
+}
+
+func (c *gnucashTransactionDataExporter) createBookBuilder(uid int64, accountMap map[int64]*models.Account) *gnucashExportedBookBuilder {
+	builder := &gnucashExportedBookBuilder{
+		uid:             uid,
+		defaultCurrency: c.getDefaultCurrency(accountMap),
+		accounts:        make([]*gnucashExportedAccountData, 0),
+		accountGuids:    make(map[string]string),
+		commodities:     make(map[string]bool),
+	}
+
+	builder.rootAccountGuid = c.addAccount(builder, "root", gnucashExportedRootAccountName, gnucashRootAccountType, "", "", "", nil)
+	builder.assetAccountGuid = c.addAccount(builder, "assets", gnucashExportedAssetAccountName, gnucashAssetAccountType, builder.defaultCurrency, "", builder.rootAccountGuid, c.createPlaceholderSlots())
+	builder.liabilityAccountGuid = c.addAccount(builder, "liabilities", gnucashExportedLiabilityAccountName, gnucashLiabilityAccountType, builder.defaultCurrency, "", builder.rootAccountGuid, c.createPlaceholderSlots())
+	builder.incomeAccountGuid = c.addAccount(builder, "income", gnucashExportedIncomeAccountName, gnucashIncomeAccountType, builder.defaultCurrency, "", builder.rootAccountGuid, c.createPlaceholderSlots())
+	builder.expenseAccountGuid = c.addAccount(builder, "expenses", gnucashExportedExpenseAccountName, gnucashExpenseAccountType, builder.defaultCurrency, "", builder.rootAccountGuid, c.createPlaceholderSlots())
+	builder.equityAccountGuid = c.addAccount(builder, "equity", gnucashExportedEquityAccountName, gnucashEquityAccountType, builder.defaultCurrency, "", builder.rootAccountGuid, c.createPlaceholderSlots())
+
+	return builder
+}
+
+func (c *gnucashTransactionDataExporter) addAccounts(builder *gnucashExportedBookBuilder, accountMap map[int64]*models.Account) {
+	accounts := make([]*models.Account, 0, len(accountMap))
+
+	for _, account := range accountMap {
+		accounts = append(accounts, account)
+	}
+
+	// parent accounts must be added before their sub-accounts
+	sort.Slice(accounts, func(i, j int) bool {
+		if (accounts[i].ParentAccountId == models.LevelOneAccountParentId) != (accounts[j].ParentAccountId == models.LevelOneAccountParentId) {
+			return accounts[i].ParentAccountId == models.LevelOneAccountParentId
+		}
+
+		if accounts[i].Category != accounts[j].Category {
+			return accounts[i].Category < accounts[j].Category
+		}
+
+		if accounts[i].DisplayOrder != accounts[j].DisplayOrder {
+			return accounts[i].DisplayOrder < accounts[j].DisplayOrder
+		}
+
+		return accounts[i].AccountId < accounts[j].AccountId
+	})
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		parentGuid := builder.assetAccountGuid
+
+		if account.Category.IsLiability() {
+			parentGuid = builder.liabilityAccountGuid
+		}
+
+		if account.ParentAccountId != models.LevelOneAccountParentId {
+			if guid, exists := builder.accountGuids[c.getAccountKey(account.ParentAccountId)]; exists {
+				parentGuid = guid
+			}
+		}
+
+		var slots []*gnucashExportedSlotData
+
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			slots = c.createPlaceholderSlots()
+		}
+
+		currency := account.Currency
+
+		if currency == "" || currency == validators.ParentAccountCurrencyPlaceholder {
+			currency = builder.defaultCurrency
+		}
+
+		c.addAccount(builder, c.getAccountKey(account.AccountId), account.Name, c.getAccountType(account), currency, account.Comment, parentGuid, slots)
+	}
+}
+
+func (c *gnucashTransactionDataExporter) addCategories(builder *gnucashExportedBookBuilder, categoryMap map[int64]*models.TransactionCategory) {
+	categories := make([]*models.TransactionCategory, 0, len(categoryMap))
+
+	for _, category := range categoryMap {
+		if category.Type == models.CATEGORY_TYPE_INCOME || category.Type == models.CATEGORY_TYPE_EXPENSE {
+			categories = append(categories, category)
+		}
+	}
+
+	// parent categories must be added before their sub-categories
+	sort.Slice(categories, func(i, j int) bool {
+		if (categories[i].ParentCategoryId == models.LevelOneTransactionCategoryParentId) != (categories[j].ParentCategoryId == models.LevelOneTransactionCategoryParentId) {
+			return categories[i].ParentCategoryId == models.LevelOneTransactionCategoryParentId
+		}
+
+		if categories[i].Type != categories[j].Type {
+			return categories[i].Type < categories[j].Type
+		}
+
+		if categories[i].DisplayOrder != categories[j].DisplayOrder {
+			return categories[i].DisplayOrder < categories[j].DisplayOrder
+		}
+
+		return categories[i].CategoryId < categories[j].CategoryId
+	})
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+		parentGuid := builder.expenseAccountGuid
+		accountType := gnucashExpenseAccountType
+
+		if category.Type == models.CATEGORY_TYPE_INCOME {
+			parentGuid = builder.incomeAccountGuid
+			accountType = gnucashIncomeAccountType
+		}
+
+		if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			if guid, exists := builder.accountGuids[c.getCategoryKey(category.ParentCategoryId, builder.defaultCurrency)]; exists {
+				parentGuid = guid
+			}
+		}
+
+		c.addAccount(builder, c.getCategoryKey(category.CategoryId, builder.defaultCurrency), category.Name, accountType, builder.defaultCurrency, category.Comment, parentGuid, nil)
+	}
+}
+
+// getCategoryAccountGuid returns the guid of the category account in specified currency, the account in other currency than default currency would be created on demand beside the original one
+func (c *gnucashTransactionDataExporter) getCategoryAccountGuid(builder *gnucashExportedBookBuilder, category *models.TransactionCategory, categoryType models.TransactionCategoryType, currency string) string {
+	if category == nil {
+		parentGuid := builder.expenseAccountGuid
+		accountType := gnucashExpenseAccountType
+
+		if categoryType == models.CATEGORY_TYPE_INCOME {
+			parentGuid = builder.incomeAccountGuid
+			accountType = gnucashIncomeAccountType
+		}
+
+		key := fmt.Sprintf("uncategorized:%d:%s", categoryType, currency)
+
+		if guid, exists := builder.accountGuids[key]; exists {
+			return guid
+		}
+
+		return c.addAccount(builder, key, c.getNameWithCurrency(gnucashExportedUncategorizedAccountName, currency, builder.defaultCurrency), accountType, currency, "", parentGuid, nil)
+	}
+
+	key := c.getCategoryKey(category.CategoryId, currency)
+
+	if guid, exists := builder.accountGuids[key]; exists {
+		return guid
+	}
+
+	parentGuid := builder.expenseAccountGuid
+	accountType := gnucashExpenseAccountType
+
+	if category.Type == models.CATEGORY_TYPE_INCOME {
+		parentGuid = builder.incomeAccountGuid
+		accountType = gnucashIncomeAccountType
+	}
+
+	if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+		if guid, exists := builder.accountGuids[c.getCategoryKey(category.ParentCategoryId, builder.defaultCurrency)]; exists {
+			parentGuid = guid
+		}
+	}
+
+	return c.addAccount(builder, key, c.getNameWithCurrency(category.Name, currency, builder.defaultCurrency), accountType, currency, category.Comment, parentGuid, nil)
+}
+
+// getOpeningBalanceAccountGuid returns the guid of the opening balance equity account in specified currency, which would be created on demand
+func (c *gnucashTransactionDataExporter) getOpeningBalanceAccountGuid(builder *gnucashExportedBookBuilder, currency string) string {
+	key := "opening-balance:" + currency
+
+	if guid, exists := builder.accountGuids[key]; exists {
+		return guid
+	}
+
+	slots := []*gnucashExportedSlotData{
+		{
+			Key: gnucashSlotEquityType,
+			Value: &gnucashExportedSlotValueData{
+				Type:  gnucashExportedSlotValueType,
+				Value: gnucashSlotEquityTypeOpeningBalance,
+			},
+		},
+	}
+
+	return c.addAccount(builder, key, c.getNameWithCurrency(gnucashExportedOpeningBalanceAccountName, currency, builder.defaultCurrency), gnucashEquityAccountType, currency, "", builder.equityAccountGuid, slots)
+}
+
+func (c *gnucashTransactionDataExporter) addAccount(builder *gnucashExportedBookBuilder, key string, name string, accountType string, currency string, description string, parentGuid string, slots []*gnucashExportedSlotData) string {
+	guid := c.getGuid(builder.uid, "account", key)
+	account := &gnucashExportedAccountData{
+		Version:     gnucashExportedVersion,
+		Name:        name,
+		Id:          c.createGuid(guid),
+		AccountType: accountType,
+		Description: description,
+		Slots:       slots,
+	}
+
+	if currency != "" {
+		account.Commodity = &gnucashExportedCommodityData{
+			Space: gnucashCommodityCurrencySpace,
+			Id:    currency,
+		}
+		account.CommodityScu = gnucashExportedCommoditySmallestUnit
+		builder.commodities[currency] = true
+	}
+
+	if parentGuid != "" {
+		account.ParentId = c.createGuid(parentGuid)
+	}
+
+	builder.accounts = append(builder.accounts, account)
+	builder.accountGuids[key] = guid
+
+	return guid
+}
+
+func (c *gnucashTransactionDataExporter) createPlaceholderSlots() []*gnucashExportedSlotData {
+	return []*gnucashExportedSlotData{
+		{
+			Key: gnucashSlotPlaceholder,
+			Value: &gnucashExportedSlotValueData{
+				Type:  gnucashExportedSlotValueType,
+				Value: gnucashSlotPlaceholderValue,
+			},
+		},
+	}
+}
+
+func (c *gnucashTransactionDataExporter) createGuid(guid string) *gnucashExportedGuid {
+	return &gnucashExportedGuid{
+		Type:  gnucashExportedGuidType,
+		Value: guid,
+	}
+}
+
+// getGuid returns a stable 32 hex characters guid for specified object of the user
+func (c *gnucashTransactionDataExporter) getGuid(uid int64, objectType string, key string) string {
+	return utils.MD5EncodeToString([]byte(fmt.Sprintf("%d:%s:%s", uid, objectType, key)))
+}
+
+func (c *gnucashTransactionDataExporter) getAccountKey(accountId int64) string {
+	return "account:" + utils.Int64ToString(accountId)
+}
+
+func (c *gnucashTransactionDataExporter) getCategoryKey(categoryId int64, currency string) string {
+	return "category:" + utils.Int64ToString(categoryId) + ":" + currency
+}
+
+func (c *gnucashTransactionDataExporter) getNameWithCurrency(name string, currency string, defaultCurrency string) string {
+	if currency == defaultCurrency {
+		return name
+	}
+
+	return name + " (" + currency + ")"
+}
+
+func (c *gnucashTransactionDataExporter) getAccountType(account *models.Account) string {
+	if account.Category == models.ACCOUNT_CATEGORY_CASH {
+		return gnucashCashAccountType
+	} else if account.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
+		return gnucashCreditAccountType
+	} else if account.Category == models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT ||
+		account.Category == models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT ||
+		account.Category == models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT {
+		return gnucashBankAccountType
+	} else if account.Category.IsLiability() {
+		return gnucashLiabilityAccountType
+	}
+
+	return gnucashAssetAccountType
+}
+
+// getDefaultCurrency returns the most used currency of all accounts, which is used as the currency of top level accounts and categories
+func (c *gnucashTransactionDataExporter) getDefaultCurrency(accountMap map[int64]*models.Account) string {
+	currencyCounts := make(map[string]int)
+
+	for _, account := range accountMap {
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || account.Currency == "" || account.Currency == validators.ParentAccountCurrencyPlaceholder {
+			continue
+		}
+
+		currencyCounts[account.Currency]++
+	}
+
+	defaultCurrency := ""
+
+	for currency, count := range currencyCounts {
+		if defaultCurrency == "" || count > currencyCounts[defaultCurrency] || (count == currencyCounts[defaultCurrency] && currency < defaultCurrency) {
+			defaultCurrency = currency
+		}
+	}
+
+	return defaultCurrency
+}
+
+// formatDateTime returns the textual date time in the format of "YYYY-MM-DD HH:MM:SS +HHMM"
+func (c *gnucashTransactionDataExporter) formatDateTime(unixTime int64, utcOffsetMinutes int16) string {
+	timezone := time.FixedZone("Transaction Timezone", int(utcOffsetMinutes)*60)
+	return time.Unix(unixTime, 0).In(timezone).Format(gnucashExportedDateTimeFormat)
+}
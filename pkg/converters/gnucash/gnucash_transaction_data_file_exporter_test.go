@@ -0,0 +1,171 @@
+package gnucash
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestGnuCashTransactionDataFileToExportedContent(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Bank", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS, Currency: "---"},
+		2: {AccountId: 2, ParentAccountId: 1, Name: "Checking", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "CNY"},
+		3: {AccountId: 3, Name: "Credit Card", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "CNY"},
+		4: {AccountId: 4, Name: "Cash", Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		20: {CategoryId: 20, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE},
+		21: {CategoryId: 21, ParentCategoryId: 20, Name: "Lunch", Type: models.CATEGORY_TYPE_EXPENSE},
+	}
+
+	transactionTime := time.Date(2024, time.September, 1, 1, 23, 45, 0, time.UTC).Unix()
+	transactions := []*models.Transaction{
+		{TransactionId: 104, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 21, AccountId: 4, Amount: 300, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 3), TimezoneUtcOffset: 480},
+		{TransactionId: 103, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 4, Amount: 100, RelatedId: 102, RelatedAccountId: 2, RelatedAccountAmount: 700, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 2), TimezoneUtcOffset: 480},
+		{TransactionId: 102, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 2, Amount: 700, RelatedId: 103, RelatedAccountId: 4, RelatedAccountAmount: 100, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 2), TimezoneUtcOffset: 480},
+		{TransactionId: 101, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 21, AccountId: 3, Amount: 1234, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 1), TimezoneUtcOffset: 480, Comment: "Lunch"},
+		{TransactionId: 100, Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 2, Amount: 12345, RelatedAccountId: 2, RelatedAccountAmount: 12345, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime), TimezoneUtcOffset: 480},
+	}
+
+	content, err := exporter.ToExportedContent(context, 1234567890, transactions, accountMap, categoryMap, nil, nil)
+	assert.Nil(t, err)
+	assert.True(t, len(content) > 2 && content[0] == 0x1F && content[1] == 0x8B)
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+	assert.Nil(t, err)
+	xmlContent, err := io.ReadAll(gzipReader)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(xmlContent), "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<gnc-v2 xmlns:gnc=\"http://www.gnucash.org/XML/gnc\""))
+	assert.Contains(t, string(xmlContent), "<gnc:count-data cd:type=\"transaction\">4</gnc:count-data>")
+	assert.Contains(t, string(xmlContent), "<ts:date>2024-09-01 09:23:45 +0800</ts:date>")
+
+	reader, err := createNewGnuCashDatabaseReader(content)
+	assert.Nil(t, err)
+
+	database, err := reader.read(context)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(database.Books))
+
+	book := database.Books[0]
+	accountsByName := make(map[string]*gnucashAccountData)
+	accountsById := make(map[string]*gnucashAccountData)
+
+	for i := 0; i < len(book.Accounts); i++ {
+		accountsByName[book.Accounts[i].Name] = book.Accounts[i]
+		accountsById[book.Accounts[i].Id] = book.Accounts[i]
+	}
+
+	assert.Equal(t, 14, len(book.Accounts))
+	assert.Equal(t, gnucashRootAccountType, accountsByName["Root Account"].AccountType)
+	assert.Equal(t, "BANK", accountsByName["Bank"].AccountType)
+	assert.Equal(t, "Assets", accountsById[accountsByName["Bank"].ParentId].Name)
+	assert.Equal(t, "CNY", accountsByName["Bank"].Commodity.Id)
+	assert.Equal(t, "Bank", accountsById[accountsByName["Checking"].ParentId].Name)
+	assert.Equal(t, "CREDIT", accountsByName["Credit Card"].AccountType)
+	assert.Equal(t, "Liabilities", accountsById[accountsByName["Credit Card"].ParentId].Name)
+	assert.Equal(t, "USD", accountsByName["Cash"].Commodity.Id)
+	assert.Equal(t, gnucashExpenseAccountType, accountsByName["Lunch"].AccountType)
+	assert.Equal(t, "Food", accountsById[accountsByName["Lunch"].ParentId].Name)
+	assert.Equal(t, "USD", accountsByName["Lunch (USD)"].Commodity.Id)
+	assert.Equal(t, "Food", accountsById[accountsByName["Lunch (USD)"].ParentId].Name)
+	assert.Equal(t, gnucashEquityAccountType, accountsByName["Opening Balances"].AccountType)
+	assert.Equal(t, gnucashSlotEquityType, accountsByName["Opening Balances"].Slots[0].Key)
+
+	assert.Equal(t, 4, len(book.Transactions))
+
+	assert.Equal(t, "2024-09-01 09:23:45 +0800", book.Transactions[0].PostedDate)
+	assert.Equal(t, "CNY", book.Transactions[0].Currency.Id)
+	assert.Equal(t, "12345/100", book.Transactions[0].Splits[0].Quantity)
+	assert.Equal(t, "Checking", accountsById[book.Transactions[0].Splits[0].Account].Name)
+	assert.Equal(t, "-12345/100", book.Transactions[0].Splits[1].Quantity)
+	assert.Equal(t, "Opening Balances", accountsById[book.Transactions[0].Splits[1].Account].Name)
+
+	assert.Equal(t, "Lunch", book.Transactions[1].Description)
+	assert.Equal(t, "-1234/100", book.Transactions[1].Splits[0].Value)
+	assert.Equal(t, "1234/100", book.Transactions[1].Splits[1].Value)
+	assert.Equal(t, "Lunch", accountsById[book.Transactions[1].Splits[1].Account].Name)
+
+	assert.Equal(t, "-700/100", book.Transactions[2].Splits[0].Value)
+	assert.Equal(t, "-700/100", book.Transactions[2].Splits[0].Quantity)
+	assert.Equal(t, "700/100", book.Transactions[2].Splits[1].Value)
+	assert.Equal(t, "100/100", book.Transactions[2].Splits[1].Quantity)
+	assert.Equal(t, "Cash", accountsById[book.Transactions[2].Splits[1].Account].Name)
+
+	assert.Equal(t, "USD", book.Transactions[3].Currency.Id)
+	assert.Equal(t, "Lunch (USD)", accountsById[book.Transactions[3].Splits[1].Account].Name)
+}
+
+func TestGnuCashTransactionDataFileToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := GnuCashTransactionDataExporter
+	importer := GnuCashTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Checking", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "CNY"},
+		2: {AccountId: 2, Name: "Cash", Category: models.ACCOUNT_CATEGORY_CASH, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Salary", Type: models.CATEGORY_TYPE_INCOME},
+		20: {CategoryId: 20, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE},
+		21: {CategoryId: 21, ParentCategoryId: 20, Name: "Lunch", Type: models.CATEGORY_TYPE_EXPENSE},
+	}
+
+	transactionTime := time.Date(2024, time.September, 1, 1, 23, 45, 0, time.UTC).Unix()
+	transactions := []*models.Transaction{
+		{TransactionId: 101, Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 1, Amount: 12345, RelatedAccountId: 1, RelatedAccountAmount: 12345, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime), TimezoneUtcOffset: 480},
+		{TransactionId: 102, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 10, AccountId: 1, Amount: 100000, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 1), TimezoneUtcOffset: 480},
+		{TransactionId: 103, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 21, AccountId: 1, Amount: 1234, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 2), TimezoneUtcOffset: 480, Comment: "Lunch"},
+		{TransactionId: 104, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, Amount: 700, RelatedId: 105, RelatedAccountId: 2, RelatedAccountAmount: 100, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 3), TimezoneUtcOffset: 480},
+		{TransactionId: 105, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 2, Amount: 100, RelatedId: 104, RelatedAccountId: 1, RelatedAccountAmount: 700, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 3), TimezoneUtcOffset: 480},
+	}
+
+	content, err := exporter.ToExportedContent(context, user.Uid, transactions, accountMap, categoryMap, nil, nil)
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, _, _, err := importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, transactionTime, utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int16(480), allNewTransactions[0].TimezoneUtcOffset)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(100000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Salary", allNewTransactions[1].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1234), allNewTransactions[2].Amount)
+	assert.Equal(t, "Lunch", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Lunch", allNewTransactions[2].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(700), allNewTransactions[3].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, int64(100), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Cash", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "USD", allNewTransactions[3].OriginalDestinationAccountCurrency)
+}
@@ -761,6 +761,72 @@ func TestGnuCashTransactionDatabaseFileParseImportedData_SkipZeroAmountTransacti
 	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
 }
 
+func TestGnuCashTransactionDatabaseFileParseImportedData_ParseSplitTransaction(t *testing.T) {
+	importer := GnuCashTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		gnucashCommonValidDataCaseHeader+
+			"<gnc:account version=\"2.0.0\">\n"+
+			"  <act:name>Test Category2</act:name>\n"+
+			"  <act:id type=\"guid\">00000000000000000000000000000200</act:id>\n"+
+			"  <act:type>EXPENSE</act:type>\n"+
+			"  <act:parent type=\"guid\">00000000000000000000000000000001</act:parent>\n"+
+			"</gnc:account>\n"+
+			"<gnc:account version=\"2.0.0\">\n"+
+			"  <act:name>Test Category3</act:name>\n"+
+			"  <act:id type=\"guid\">00000000000000000000000000000300</act:id>\n"+
+			"  <act:type>EXPENSE</act:type>\n"+
+			"  <act:parent type=\"guid\">00000000000000000000000000000001</act:parent>\n"+
+			"</gnc:account>\n"+
+			"<gnc:transaction version=\"2.0.0\">\n"+
+			"  <trn:date-posted>\n"+
+			"    <ts:date>2024-09-01 12:34:56 +0000</ts:date>\n"+
+			"  </trn:date-posted>\n"+
+			"  <trn:description>Supermarket</trn:description>\n"+
+			"  <trn:splits>\n"+
+			"    <trn:split>\n"+
+			"      <split:memo>Milk</split:memo>\n"+
+			"      <split:quantity>100/100</split:quantity>\n"+
+			"      <split:account type=\"guid\">00000000000000000000000000000200</split:account>\n"+
+			"    </trn:split>\n"+
+			"    <trn:split>\n"+
+			"      <split:memo>Soap</split:memo>\n"+
+			"      <split:quantity>200/100</split:quantity>\n"+
+			"      <split:account type=\"guid\">00000000000000000000000000000300</split:account>\n"+
+			"    </trn:split>\n"+
+			"    <trn:split>\n"+
+			"      <split:quantity>-300/100</split:quantity>\n"+
+			"      <split:account type=\"guid\">00000000000000000000000000001000</split:account>\n"+
+			"    </trn:split>\n"+
+			"  </trn:splits>\n"+
+			"</gnc:transaction>\n"+
+			gnucashCommonValidDataCaseFooter), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(300), allNewTransactions[0].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Test Category2", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, "Supermarket", allNewTransactions[0].Comment)
+
+	assert.Equal(t, 2, len(allNewTransactions[0].Splits))
+	assert.Equal(t, "Test Category2", allNewTransactions[0].Splits[0].OriginalCategoryName)
+	assert.Equal(t, int64(100), allNewTransactions[0].Splits[0].Amount)
+	assert.Equal(t, "Milk", allNewTransactions[0].Splits[0].Comment)
+	assert.Equal(t, "Test Category3", allNewTransactions[0].Splits[1].OriginalCategoryName)
+	assert.Equal(t, int64(200), allNewTransactions[0].Splits[1].Amount)
+	assert.Equal(t, "Soap", allNewTransactions[0].Splits[1].Comment)
+}
+
 func TestGnuCashTransactionDatabaseFileParseImportedData_NotSupportedToParseSplitTransaction(t *testing.T) {
 	importer := GnuCashTransactionDataImporter
 	context := core.NewNullContext()
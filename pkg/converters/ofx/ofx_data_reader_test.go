@@ -56,18 +56,18 @@ func TestCreateNewOFXFileReader_OFX1(t *testing.T) {
 	assert.Equal(t, "NONE", ofxFile.FileHeader.NewFileUid)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
 
-	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.DefaultCurrency)
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
 
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom)
-	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
 
-	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions))
-	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
-	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
-	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Amount)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
+	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Amount)
 }
 
 func TestCreateNewOFXFileReader_OFX1WithoutBreakLine(t *testing.T) {
@@ -117,18 +117,18 @@ func TestCreateNewOFXFileReader_OFX1WithoutBreakLine(t *testing.T) {
 	assert.Equal(t, "NONE", ofxFile.FileHeader.NewFileUid)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
 
-	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.DefaultCurrency)
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
 
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom)
-	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
 
-	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions))
-	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
-	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
-	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Amount)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
+	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Amount)
 }
 
 func TestCreateNewOFXFileReader_OFX1ParseBankAccountFrom(t *testing.T) {
@@ -167,11 +167,11 @@ func TestCreateNewOFXFileReader_OFX1ParseBankAccountFrom(t *testing.T) {
 	assert.NotNil(t, ofxFile)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom)
 
-	account := ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom
+	account := ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom
 	assert.Equal(t, "1234567890", account.BankId)
 	assert.Equal(t, "2345678901", account.BranchId)
 	assert.Equal(t, "3456789012", account.AccountId)
@@ -212,11 +212,11 @@ func TestCreateNewOFXFileReader_OFX1ParseCreditCardAccountFrom(t *testing.T) {
 	assert.NotNil(t, ofxFile)
 
 	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1)
-	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse)
-	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom)
+	assert.Equal(t, 1, len(ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
+	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom)
 
-	account := ofxFile.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom
+	account := ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom
 	assert.Equal(t, "3456789012", account.AccountId)
 	assert.Equal(t, "4567890123", account.AccountKey)
 }
@@ -254,11 +254,11 @@ func TestCreateNewOFXFileReader_OFX1ParseBankTransactionList(t *testing.T) {
 	assert.NotNil(t, ofxFile)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList)
 
-	transactionList := ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList
+	transactionList := ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList
 	assert.Equal(t, "20240901012345.000[+8:CST]", transactionList.StartDate)
 	assert.Equal(t, "20240901235959.000[+8:CST]", transactionList.EndDate)
 }
@@ -296,11 +296,11 @@ func TestCreateNewOFXFileReader_OFX1ParseCreditTransactionList(t *testing.T) {
 	assert.NotNil(t, ofxFile)
 
 	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1)
-	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse)
-	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList)
+	assert.Equal(t, 1, len(ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
+	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList)
 
-	transactionList := ofxFile.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList
+	transactionList := ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList
 	assert.Equal(t, "20240901012345.000[+8:CST]", transactionList.StartDate)
 	assert.Equal(t, "20240901235959.000[+8:CST]", transactionList.EndDate)
 }
@@ -349,13 +349,13 @@ func TestCreateNewOFXFileReader_OFX1ParseTransaction(t *testing.T) {
 	assert.NotNil(t, ofxFile)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList)
-	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions))
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0])
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0])
 
-	transaction := ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0]
+	transaction := ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0]
 	assert.Equal(t, "1234567890", transaction.TransactionId)
 	assert.Equal(t, ofxCashWithdrawalTransaction, transaction.TransactionType)
 	assert.Equal(t, "20240901012345.000[+8:CST]", transaction.PostedDate)
@@ -416,14 +416,14 @@ func TestCreateNewOFXFileReader_OFX1ParseTransactionPayee(t *testing.T) {
 	assert.NotNil(t, ofxFile)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList)
-	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions))
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0])
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Payee)
-
-	payee := ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Payee
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0])
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Payee)
+
+	payee := ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Payee
 	assert.Equal(t, "Test Name", payee.Name)
 	assert.Equal(t, "Address 1", payee.Address1)
 	assert.Equal(t, "Address 2", payee.Address2)
@@ -435,6 +435,103 @@ func TestCreateNewOFXFileReader_OFX1ParseTransactionPayee(t *testing.T) {
 	assert.Equal(t, "11111111111", payee.Phone)
 }
 
+func TestCreateNewOFXFileReader_OFX1ParseMultipleStatements(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewOFXFileReader(context, []byte(
+		"OFXHEADER:100\n"+
+			"DATA:OFXSGML\n"+
+			"VERSION:103\n"+
+			"SECURITY:NONE\n"+
+			"ENCODING:USASCII\n"+
+			"CHARSET:1252\n"+
+			"COMPRESSION:NONE\n"+
+			"OLDFILEUID:NONE\n"+
+			"NEWFILEUID:NONE\n"+
+			"\n"+
+			"<OFX>\n"+
+			"<BANKMSGSRSV1>\n"+
+			"<STMTTRNRS>\n"+
+			"<STMTRS>\n"+
+			"<CURDEF>CNY\n"+
+			"<BANKACCTFROM>\n"+
+			"<ACCTID>123\n"+
+			"</BANKACCTFROM>\n"+
+			"<BANKTRANLIST>\n"+
+			"<STMTTRN>\n"+
+			"<TRNTYPE>DEP\n"+
+			"<DTPOSTED>20240901012345.000[+8:CST]\n"+
+			"<TRNAMT>123.45\n"+
+			"</STMTTRN>\n"+
+			"</BANKTRANLIST>\n"+
+			"</STMTRS>\n"+
+			"</STMTTRNRS>\n"+
+			"<STMTTRNRS>\n"+
+			"<STMTRS>\n"+
+			"<CURDEF>USD\n"+
+			"<BANKACCTFROM>\n"+
+			"<ACCTID>456\n"+
+			"</BANKACCTFROM>\n"+
+			"<BANKTRANLIST>\n"+
+			"<STMTTRN>\n"+
+			"<TRNTYPE>DEBIT\n"+
+			"<DTPOSTED>20240902012345.000[+8:CST]\n"+
+			"<TRNAMT>-67.89\n"+
+			"</STMTTRN>\n"+
+			"</BANKTRANLIST>\n"+
+			"</STMTRS>\n"+
+			"</STMTTRNRS>\n"+
+			"</BANKMSGSRSV1>\n"+
+			"<CREDITCARDMSGSRSV1>\n"+
+			"<CCSTMTTRNRS>\n"+
+			"<CCSTMTRS>\n"+
+			"<CURDEF>CNY\n"+
+			"<CCACCTFROM>\n"+
+			"<ACCTID>789\n"+
+			"</CCACCTFROM>\n"+
+			"</CCSTMTRS>\n"+
+			"</CCSTMTTRNRS>\n"+
+			"<CCSTMTTRNRS>\n"+
+			"<CCSTMTRS>\n"+
+			"<CURDEF>EUR\n"+
+			"<CCACCTFROM>\n"+
+			"<ACCTID>012\n"+
+			"</CCACCTFROM>\n"+
+			"</CCSTMTRS>\n"+
+			"</CCSTMTTRNRS>\n"+
+			"</CREDITCARDMSGSRSV1>\n"+
+			"</OFX>"))
+
+	assert.Nil(t, err)
+
+	ofxFile, err := reader.read(context)
+	assert.Nil(t, err)
+	assert.NotNil(t, ofxFile)
+
+	assert.NotNil(t, ofxFile.BankMessageResponseV1)
+	assert.Equal(t, 2, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Amount)
+
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse)
+	assert.Equal(t, "USD", ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.DefaultCurrency)
+	assert.Equal(t, "456", ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxGenericDebitTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "-67.89", ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.TransactionList.StatementTransactions[0].Amount)
+
+	assert.NotNil(t, ofxFile.CreditCardMessageResponseV1)
+	assert.Equal(t, 2, len(ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses))
+	assert.Equal(t, "CNY", ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
+	assert.Equal(t, "789", ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, "EUR", ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[1].StatementResponse.DefaultCurrency)
+	assert.Equal(t, "012", ofxFile.CreditCardMessageResponseV1.StatementTransactionResponses[1].StatementResponse.AccountFrom.AccountId)
+}
+
 func TestCreateNewOFXFileReader_OFX1WithEndElement(t *testing.T) {
 	context := core.NewNullContext()
 	reader, err := createNewOFXFileReader(context, []byte(
@@ -482,18 +579,18 @@ func TestCreateNewOFXFileReader_OFX1WithEndElement(t *testing.T) {
 	assert.Equal(t, "NONE", ofxFile.FileHeader.NewFileUid)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
 
-	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.DefaultCurrency)
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
 
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom)
-	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
 
-	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions))
-	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
-	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
-	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Amount)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
+	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Amount)
 }
 
 func TestCreateNewOFXFileReader_OFX1WithBlanklinesInHeader(t *testing.T) {
@@ -545,18 +642,18 @@ func TestCreateNewOFXFileReader_OFX1WithBlanklinesInHeader(t *testing.T) {
 	assert.Equal(t, "NONE", ofxFile.FileHeader.NewFileUid)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
 
-	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.DefaultCurrency)
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
 
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom)
-	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
 
-	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions))
-	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
-	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
-	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Amount)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
+	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Amount)
 }
 
 func TestCreateNewOFXFileReader_OFX1WithoutCharset(t *testing.T) {
@@ -696,18 +793,78 @@ func TestCreateNewOFXFileReader_OFX2(t *testing.T) {
 	assert.Equal(t, "NONE", ofxFile.FileHeader.NewFileUid)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
+
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
+
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
+
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
+	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Amount)
+}
+
+func TestCreateNewOFXFileReader_OFX2ParseMultipleStatements(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewOFXFileReader(context, []byte(
+		"<?xml version=\"1.0\" encoding=\"US-ASCII\"?>\n"+
+			"<?OFX OFXHEADER=\"200\" VERSION=\"211\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n"+
+			"<OFX>\n"+
+			"  <BANKMSGSRSV1>\n"+
+			"    <STMTTRNRS>\n"+
+			"      <STMTRS>\n"+
+			"        <CURDEF>CNY</CURDEF>\n"+
+			"        <BANKACCTFROM>\n"+
+			"          <ACCTID>123</ACCTID>\n"+
+			"        </BANKACCTFROM>\n"+
+			"        <BANKTRANLIST>\n"+
+			"          <STMTTRN>\n"+
+			"            <TRNTYPE>DEP</TRNTYPE>\n"+
+			"            <DTPOSTED>20240901012345.000[+8:CST]</DTPOSTED>\n"+
+			"            <TRNAMT>123.45</TRNAMT>\n"+
+			"          </STMTTRN>\n"+
+			"        </BANKTRANLIST>\n"+
+			"      </STMTRS>\n"+
+			"    </STMTTRNRS>\n"+
+			"    <STMTTRNRS>\n"+
+			"      <STMTRS>\n"+
+			"        <CURDEF>USD</CURDEF>\n"+
+			"        <BANKACCTFROM>\n"+
+			"          <ACCTID>456</ACCTID>\n"+
+			"        </BANKACCTFROM>\n"+
+			"        <BANKTRANLIST>\n"+
+			"          <STMTTRN>\n"+
+			"            <TRNTYPE>DEBIT</TRNTYPE>\n"+
+			"            <DTPOSTED>20240902012345.000[+8:CST]</DTPOSTED>\n"+
+			"            <TRNAMT>-67.89</TRNAMT>\n"+
+			"          </STMTTRN>\n"+
+			"        </BANKTRANLIST>\n"+
+			"      </STMTRS>\n"+
+			"    </STMTTRNRS>\n"+
+			"  </BANKMSGSRSV1>\n"+
+			"</OFX>"))
 
-	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.DefaultCurrency)
+	assert.Nil(t, err)
+
+	ofxFile, err := reader.read(context)
+	assert.Nil(t, err)
+	assert.NotNil(t, ofxFile)
+
+	assert.NotNil(t, ofxFile.BankMessageResponseV1)
+	assert.Equal(t, 2, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
 
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom)
-	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Amount)
 
-	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions))
-	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
-	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
-	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Amount)
+	assert.Equal(t, "USD", ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.DefaultCurrency)
+	assert.Equal(t, "456", ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.AccountFrom.AccountId)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, "-67.89", ofxFile.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse.TransactionList.StatementTransactions[0].Amount)
 }
 
 func TestCreateNewOFXFileReader_OFX2WithoutBreakLine(t *testing.T) {
@@ -749,18 +906,18 @@ func TestCreateNewOFXFileReader_OFX2WithoutBreakLine(t *testing.T) {
 	assert.Equal(t, "NONE", ofxFile.FileHeader.NewFileUid)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
 
-	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.DefaultCurrency)
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
 
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom)
-	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
 
-	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions))
-	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
-	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
-	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Amount)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
+	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Amount)
 }
 
 func TestCreateNewOFXFileReader_OFX2WithoutOFXHeader(t *testing.T) {
@@ -889,16 +1046,16 @@ func TestCreateNewOFXFileReader_OFX2WithoutAnyHeader(t *testing.T) {
 	assert.Nil(t, ofxFile.FileHeader)
 
 	assert.NotNil(t, ofxFile.BankMessageResponseV1)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse)
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses))
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse)
 
-	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.DefaultCurrency)
+	assert.Equal(t, "CNY", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.DefaultCurrency)
 
-	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom)
-	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.AccountFrom.AccountId)
+	assert.NotNil(t, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom)
+	assert.Equal(t, "123", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.AccountFrom.AccountId)
 
-	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions))
-	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
-	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
-	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList.StatementTransactions[0].Amount)
+	assert.Equal(t, 1, len(ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxDepositTransaction, ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "20240901012345.000[+8:CST]", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].PostedDate)
+	assert.Equal(t, "123.45", ofxFile.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse.TransactionList.StatementTransactions[0].Amount)
 }
@@ -0,0 +1,270 @@
+package ofx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ofxExportedFileHeader = "<?OFX OFXHEADER=\"200\" VERSION=\"220\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n"
+const ofxExportedDateTimeFormat = "20060102150405.000"
+const ofxExportedLanguage = "ENG"
+const ofxExportedNameMaxLength = 32
+const ofxSuccessStatusCode = "0"
+const ofxInfoStatusSeverity = "INFO"
+
+var ofxAccountCategoryAccountTypeMapping = map[models.AccountCategory]ofxAccountType{
+	models.ACCOUNT_CATEGORY_DEBT:                   ofxLineOfCreditAccount,
+	models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT:        ofxSavingsAccount,
+	models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT: ofxCertificateOfDepositAccount,
+}
+
+// ofxTransactionDataExporter defines the structure of open financial exchange (ofx) file exporter for transaction data
+type ofxTransactionDataExporter struct {
+}
+
+// Initialize a open financial exchange (ofx) transaction data exporter singleton instance
+var (
+	OFXTransactionDataExporter = &ofxTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported open financial exchange (ofx) 2.x file content, which contains one statement for each account
+func (c *ofxTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	accounts := make([]*models.Account, 0)
+	accountTransactions := make(map[int64][]*models.Transaction)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		account, exists := accountMap[transaction.AccountId]
+
+		if !exists {
+			log.Warnf(ctx, "[ofx_transaction_data_file_exporter.ToExportedContent] cannot find account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.AccountId, transaction.TransactionId, uid)
+			continue
+		}
+
+		if _, exists := accountTransactions[account.AccountId]; !exists {
+			accounts = append(accounts, account)
+		}
+
+		accountTransactions[account.AccountId] = append(accountTransactions[account.AccountId], transaction)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Category != accounts[j].Category {
+			return accounts[i].Category < accounts[j].Category
+		}
+
+		if accounts[i].DisplayOrder != accounts[j].DisplayOrder {
+			return accounts[i].DisplayOrder < accounts[j].DisplayOrder
+		}
+
+		return accounts[i].AccountId < accounts[j].AccountId
+	})
+
+	currentDateTime := c.formatDateTime(time.Now().Unix(), 0)
+
+	file := &ofxFile{
+		SignOnMessageResponseV1: &ofxSignOnMessageResponseV1{
+			SignOnResponse: &ofxSignOnResponse{
+				Status:     c.createSuccessStatus(),
+				ServerDate: currentDateTime,
+				Language:   ofxExportedLanguage,
+			},
+		},
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		transactionsInAccount := accountTransactions[account.AccountId]
+
+		sort.SliceStable(transactionsInAccount, func(i, j int) bool {
+			return transactionsInAccount[i].TransactionTime < transactionsInAccount[j].TransactionTime
+		})
+
+		ledgerBalance := &ofxBalance{
+			Amount:   utils.FormatAmount(account.Balance),
+			AsOfDate: currentDateTime,
+		}
+
+		if account.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
+			if file.CreditCardMessageResponseV1 == nil {
+				file.CreditCardMessageResponseV1 = &ofxCreditCardMessageResponseV1{}
+			}
+
+			file.CreditCardMessageResponseV1.StatementTransactionResponses = append(file.CreditCardMessageResponseV1.StatementTransactionResponses, &ofxCreditCardStatementTransactionResponse{
+				TransactionUid: utils.Int64ToString(account.AccountId),
+				Status:         c.createSuccessStatus(),
+				StatementResponse: &ofxCreditCardStatementResponse{
+					DefaultCurrency: account.Currency,
+					AccountFrom:     c.createCreditCardAccount(account),
+					TransactionList: c.createCreditCardTransactionList(transactionsInAccount, accountMap, categoryMap),
+					LedgerBalance:   ledgerBalance,
+				},
+			})
+		} else {
+			if file.BankMessageResponseV1 == nil {
+				file.BankMessageResponseV1 = &ofxBankMessageResponseV1{}
+			}
+
+			file.BankMessageResponseV1.StatementTransactionResponses = append(file.BankMessageResponseV1.StatementTransactionResponses, &ofxBankStatementTransactionResponse{
+				TransactionUid: utils.Int64ToString(account.AccountId),
+				Status:         c.createSuccessStatus(),
+				StatementResponse: &ofxBankStatementResponse{
+					DefaultCurrency: account.Currency,
+					AccountFrom:     c.createBankAccount(account),
+					TransactionList: c.createBankTransactionList(transactionsInAccount, accountMap, categoryMap),
+					LedgerBalance:   ledgerBalance,
+				},
+			})
+		}
+	}
+
+	content, err := xml.MarshalIndent(file, "", "  ")
+
+	if err != nil {
+		log.Errorf(ctx, "[ofx_transaction_data_file_exporter.ToExportedContent] failed to marshal ofx file for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	buffer.WriteString(ofxExportedFileHeader)
+	buffer.Write(content)
+	buffer.WriteString("\n")
+
+	return buffer.Bytes(), nil
+}
+
+func (c *ofxTransactionDataExporter) createBankTransactionList(transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory) *ofxBankTransactionList {
+	statementTransactions := make([]*ofxBankStatementTransaction, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		statementTransaction := &ofxBankStatementTransaction{
+			ofxBaseStatementTransaction: c.createBaseStatementTransaction(transaction, categoryMap),
+		}
+
+		if relatedAccount, exists := accountMap[transaction.RelatedAccountId]; exists && transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && relatedAccount.Category != models.ACCOUNT_CATEGORY_CREDIT_CARD {
+			statementTransaction.AccountTo = c.createBankAccount(relatedAccount)
+		}
+
+		statementTransactions[i] = statementTransaction
+	}
+
+	transactionList := &ofxBankTransactionList{
+		StatementTransactions: statementTransactions,
+	}
+
+	if len(statementTransactions) > 0 {
+		transactionList.StartDate = statementTransactions[0].PostedDate
+		transactionList.EndDate = statementTransactions[len(statementTransactions)-1].PostedDate
+	}
+
+	return transactionList
+}
+
+func (c *ofxTransactionDataExporter) createCreditCardTransactionList(transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory) *ofxCreditCardTransactionList {
+	statementTransactions := make([]*ofxCreditCardStatementTransaction, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		statementTransaction := &ofxCreditCardStatementTransaction{
+			ofxBaseStatementTransaction: c.createBaseStatementTransaction(transaction, categoryMap),
+		}
+
+		if relatedAccount, exists := accountMap[transaction.RelatedAccountId]; exists && transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && relatedAccount.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
+			statementTransaction.AccountTo = c.createCreditCardAccount(relatedAccount)
+		}
+
+		statementTransactions[i] = statementTransaction
+	}
+
+	transactionList := &ofxCreditCardTransactionList{
+		StatementTransactions: statementTransactions,
+	}
+
+	if len(statementTransactions) > 0 {
+		transactionList.StartDate = statementTransactions[0].PostedDate
+		transactionList.EndDate = statementTransactions[len(statementTransactions)-1].PostedDate
+	}
+
+	return transactionList
+}
+
+func (c *ofxTransactionDataExporter) createBaseStatementTransaction(transaction *models.Transaction, categoryMap map[int64]*models.TransactionCategory) ofxBaseStatementTransaction {
+	transactionType := ofxOtherTransaction
+	amount := transaction.Amount
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		transactionType = ofxDepositTransaction
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		transactionType = ofxGenericDebitTransaction
+		amount = -amount
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		transactionType = ofxTransferTransaction
+		amount = -amount
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		transactionType = ofxTransferTransaction
+	}
+
+	name := ""
+
+	if category, exists := categoryMap[transaction.CategoryId]; exists {
+		name = utils.SubString(category.Name, 0, ofxExportedNameMaxLength)
+	}
+
+	return ofxBaseStatementTransaction{
+		TransactionId:   utils.Int64ToString(transaction.TransactionId),
+		TransactionType: transactionType,
+		PostedDate:      c.formatDateTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), transaction.TimezoneUtcOffset),
+		Amount:          utils.FormatAmount(amount),
+		Name:            name,
+		Memo:            transaction.Comment,
+	}
+}
+
+func (c *ofxTransactionDataExporter) createBankAccount(account *models.Account) *ofxBankAccount {
+	accountType, exists := ofxAccountCategoryAccountTypeMapping[account.Category]
+
+	if !exists {
+		accountType = ofxCheckingAccount
+	}
+
+	return &ofxBankAccount{
+		BankId:      utils.Int64ToString(account.AccountId),
+		AccountId:   account.Name,
+		AccountType: accountType,
+	}
+}
+
+func (c *ofxTransactionDataExporter) createCreditCardAccount(account *models.Account) *ofxCreditCardAccount {
+	return &ofxCreditCardAccount{
+		AccountId: account.Name,
+	}
+}
+
+func (c *ofxTransactionDataExporter) createSuccessStatus() *ofxStatus {
+	return &ofxStatus{
+		Code:     ofxSuccessStatusCode,
+		Severity: ofxInfoStatusSeverity,
+	}
+}
+
+// formatDateTime returns the textual date time in the format of "YYYYMMDDHHMMSS.XXX[gmt offset]"
+func (c *ofxTransactionDataExporter) formatDateTime(unixTime int64, utcOffsetMinutes int16) string {
+	timezone := time.FixedZone("Transaction Timezone", int(utcOffsetMinutes)*60)
+	hoursOffset := utils.Float64ToString(float64(utcOffsetMinutes) / 60)
+
+	if utcOffsetMinutes >= 0 {
+		hoursOffset = "+" + hoursOffset
+	}
+
+	return time.Unix(unixTime, 0).In(timezone).Format(ofxExportedDateTimeFormat) + "[" + hoursOffset + "]"
+}
@@ -0,0 +1,135 @@
+package ofx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestOFXTransactionDataFileToExportedContent(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Checking", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Currency: "CNY", Balance: 12345},
+		2: {AccountId: 2, Name: "Credit Card", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Currency: "CNY", Balance: -100},
+		3: {AccountId: 3, Name: "Savings", Category: models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT, Currency: "USD", Balance: 0},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Salary"},
+		20: {CategoryId: 20, Name: "Food"},
+		30: {CategoryId: 30, Name: "Transfer"},
+	}
+
+	transactionTime := time.Date(2024, time.September, 1, 1, 23, 45, 0, time.UTC).Unix()
+	transactions := []*models.Transaction{
+		{TransactionId: 104, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, CategoryId: 30, AccountId: 3, Amount: 100, RelatedId: 103, RelatedAccountId: 1, RelatedAccountAmount: 700, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 3), TimezoneUtcOffset: 480},
+		{TransactionId: 103, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, CategoryId: 30, AccountId: 1, Amount: 700, RelatedId: 104, RelatedAccountId: 3, RelatedAccountAmount: 100, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 3), TimezoneUtcOffset: 480},
+		{TransactionId: 102, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 20, AccountId: 2, Amount: 1234, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 2), TimezoneUtcOffset: -330, Comment: "Lunch"},
+		{TransactionId: 101, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 10, AccountId: 1, Amount: 12345, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime), TimezoneUtcOffset: 480},
+	}
+
+	content, err := exporter.ToExportedContent(context, 1234567890, transactions, accountMap, categoryMap, nil, nil)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<?OFX OFXHEADER=\"200\" VERSION=\"220\""))
+
+	reader, err := createNewOFXFileReader(context, content)
+	assert.Nil(t, err)
+
+	file, err := reader.read(context)
+	assert.Nil(t, err)
+	assert.Equal(t, ofxVersion2, file.FileHeader.OFXDeclarationVersion)
+	assert.NotNil(t, file.SignOnMessageResponseV1)
+	assert.Equal(t, ofxSuccessStatusCode, file.SignOnMessageResponseV1.SignOnResponse.Status.Code)
+
+	assert.NotNil(t, file.BankMessageResponseV1)
+	assert.Equal(t, 2, len(file.BankMessageResponseV1.StatementTransactionResponses))
+
+	checkingStatement := file.BankMessageResponseV1.StatementTransactionResponses[0].StatementResponse
+	assert.Equal(t, "CNY", checkingStatement.DefaultCurrency)
+	assert.Equal(t, "Checking", checkingStatement.AccountFrom.AccountId)
+	assert.Equal(t, ofxCheckingAccount, checkingStatement.AccountFrom.AccountType)
+	assert.Equal(t, "123.45", checkingStatement.LedgerBalance.Amount)
+	assert.Equal(t, "20240901092345.000[+8]", checkingStatement.TransactionList.StartDate)
+	assert.Equal(t, "20240901092348.000[+8]", checkingStatement.TransactionList.EndDate)
+	assert.Equal(t, 2, len(checkingStatement.TransactionList.StatementTransactions))
+
+	assert.Equal(t, "101", checkingStatement.TransactionList.StatementTransactions[0].TransactionId)
+	assert.Equal(t, ofxDepositTransaction, checkingStatement.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "123.45", checkingStatement.TransactionList.StatementTransactions[0].Amount)
+	assert.Equal(t, "Salary", checkingStatement.TransactionList.StatementTransactions[0].Name)
+
+	assert.Equal(t, "103", checkingStatement.TransactionList.StatementTransactions[1].TransactionId)
+	assert.Equal(t, ofxTransferTransaction, checkingStatement.TransactionList.StatementTransactions[1].TransactionType)
+	assert.Equal(t, "-7.00", checkingStatement.TransactionList.StatementTransactions[1].Amount)
+	assert.NotNil(t, checkingStatement.TransactionList.StatementTransactions[1].AccountTo)
+	assert.Equal(t, "Savings", checkingStatement.TransactionList.StatementTransactions[1].AccountTo.AccountId)
+	assert.Equal(t, ofxSavingsAccount, checkingStatement.TransactionList.StatementTransactions[1].AccountTo.AccountType)
+
+	savingsStatement := file.BankMessageResponseV1.StatementTransactionResponses[1].StatementResponse
+	assert.Equal(t, "USD", savingsStatement.DefaultCurrency)
+	assert.Equal(t, "Savings", savingsStatement.AccountFrom.AccountId)
+	assert.Equal(t, 1, len(savingsStatement.TransactionList.StatementTransactions))
+	assert.Equal(t, "104", savingsStatement.TransactionList.StatementTransactions[0].TransactionId)
+	assert.Equal(t, "1.00", savingsStatement.TransactionList.StatementTransactions[0].Amount)
+
+	assert.NotNil(t, file.CreditCardMessageResponseV1)
+	assert.Equal(t, 1, len(file.CreditCardMessageResponseV1.StatementTransactionResponses))
+
+	creditCardStatement := file.CreditCardMessageResponseV1.StatementTransactionResponses[0].StatementResponse
+	assert.Equal(t, "Credit Card", creditCardStatement.AccountFrom.AccountId)
+	assert.Equal(t, "-1.00", creditCardStatement.LedgerBalance.Amount)
+	assert.Equal(t, 1, len(creditCardStatement.TransactionList.StatementTransactions))
+	assert.Equal(t, ofxGenericDebitTransaction, creditCardStatement.TransactionList.StatementTransactions[0].TransactionType)
+	assert.Equal(t, "20240831195347.000[-5.5]", creditCardStatement.TransactionList.StatementTransactions[0].PostedDate)
+	assert.Equal(t, "-12.34", creditCardStatement.TransactionList.StatementTransactions[0].Amount)
+	assert.Equal(t, "Food", creditCardStatement.TransactionList.StatementTransactions[0].Name)
+	assert.Equal(t, "Lunch", creditCardStatement.TransactionList.StatementTransactions[0].Memo)
+}
+
+func TestOFXTransactionDataFileToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	importer := OFXTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Checking", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Currency: "CNY"},
+		2: {AccountId: 2, Name: "Credit Card", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Currency: "CNY"},
+	}
+
+	transactionTime := time.Date(2024, time.September, 1, 1, 23, 45, 0, time.UTC).Unix()
+	transactions := []*models.Transaction{
+		{TransactionId: 101, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, Amount: 12345, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime), TimezoneUtcOffset: 480},
+		{TransactionId: 102, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2, Amount: 1234, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 1), TimezoneUtcOffset: 480, Comment: "Lunch"},
+	}
+
+	content, err := exporter.ToExportedContent(context, user.Uid, transactions, accountMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, int64(transactionTime), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1234), allNewTransactions[1].Amount)
+	assert.Equal(t, "Credit Card", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Lunch", allNewTransactions[1].Comment)
+}
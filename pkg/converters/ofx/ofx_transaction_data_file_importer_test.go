@@ -604,6 +604,48 @@ func TestOFXTransactionDataFileParseImportedData_ParseDescription(t *testing.T)
 	assert.Equal(t, "Test", allNewTransactions[0].Comment)
 }
 
+func TestOFXTransactionDataFileParseImportedData_ParseExternalReference(t *testing.T) {
+	importer := OFXTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"<OFX>\n"+
+			"  <BANKMSGSRSV1>\n"+
+			"    <STMTTRNRS>\n"+
+			"      <STMTRS>\n"+
+			"        <CURDEF>CNY</CURDEF>\n"+
+			"        <BANKACCTFROM>\n"+
+			"          <ACCTID>123</ACCTID>\n"+
+			"        </BANKACCTFROM>\n"+
+			"        <BANKTRANLIST>\n"+
+			"          <STMTTRN>\n"+
+			"            <TRNTYPE>DEP</TRNTYPE>\n"+
+			"            <DTPOSTED>20240901012345.000[+8:CST]</DTPOSTED>\n"+
+			"            <TRNAMT>123.45</TRNAMT>\n"+
+			"            <FITID>20240901001</FITID>\n"+
+			"          </STMTTRN>\n"+
+			"          <STMTTRN>\n"+
+			"            <TRNTYPE>DEP</TRNTYPE>\n"+
+			"            <DTPOSTED>20240902012345.000[+8:CST]</DTPOSTED>\n"+
+			"            <TRNAMT>123.45</TRNAMT>\n"+
+			"          </STMTTRN>\n"+
+			"        </BANKTRANLIST>\n"+
+			"      </STMTRS>\n"+
+			"    </STMTTRNRS>\n"+
+			"  </BANKMSGSRSV1>\n"+
+			"</OFX>"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, "20240901001", allNewTransactions[0].ExternalReference)
+	assert.Equal(t, "", allNewTransactions[1].ExternalReference)
+}
+
 func TestOFXTransactionDataFileParseImportedData_MissingAccountFromNode(t *testing.T) {
 	importer := OFXTransactionDataImporter
 	context := core.NewNullContext()
@@ -24,6 +24,7 @@ var ofxTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bo
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+	datatable.TRANSACTION_DATA_TABLE_EXTERNAL_REFERENCE:       true,
 }
 
 // ofxTransactionData defines the structure of open financial exchange (ofx) transaction data
@@ -220,6 +221,8 @@ func (t *ofxTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	data[datatable.TRANSACTION_DATA_TABLE_EXTERNAL_REFERENCE] = ofxTransaction.TransactionId
+
 	return data, nil
 }
 
@@ -280,66 +283,80 @@ func createNewOFXTransactionDataTable(file *ofxFile) (*ofxTransactionDataTable,
 
 	allData := make([]*ofxTransactionData, 0)
 
-	if file.BankMessageResponseV1 != nil &&
-		file.BankMessageResponseV1.StatementTransactionResponse != nil &&
-		file.BankMessageResponseV1.StatementTransactionResponse.StatementResponse != nil &&
-		file.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList != nil {
-		statement := file.BankMessageResponseV1.StatementTransactionResponse.StatementResponse
-		bankTransactions := statement.TransactionList.StatementTransactions
-		fromAccountId := ""
-		fromCreditAccount := false
-
-		if statement.AccountFrom != nil {
-			fromAccountId = statement.AccountFrom.AccountId
+	if file.BankMessageResponseV1 != nil {
+		for i := 0; i < len(file.BankMessageResponseV1.StatementTransactionResponses); i++ {
+			statementTransactionResponse := file.BankMessageResponseV1.StatementTransactionResponses[i]
 
-			if statement.AccountFrom.AccountType == ofxLineOfCreditAccount {
-				fromCreditAccount = true
+			if statementTransactionResponse == nil ||
+				statementTransactionResponse.StatementResponse == nil ||
+				statementTransactionResponse.StatementResponse.TransactionList == nil {
+				continue
 			}
-		}
 
-		for i := 0; i < len(bankTransactions); i++ {
-			toAccountId := ""
+			statement := statementTransactionResponse.StatementResponse
+			bankTransactions := statement.TransactionList.StatementTransactions
+			fromAccountId := ""
+			fromCreditAccount := false
 
-			if bankTransactions[i].AccountTo != nil {
-				toAccountId = bankTransactions[i].AccountTo.AccountId
+			if statement.AccountFrom != nil {
+				fromAccountId = statement.AccountFrom.AccountId
+
+				if statement.AccountFrom.AccountType == ofxLineOfCreditAccount {
+					fromCreditAccount = true
+				}
 			}
 
-			allData = append(allData, &ofxTransactionData{
-				ofxBaseStatementTransaction: bankTransactions[i].ofxBaseStatementTransaction,
-				DefaultCurrency:             statement.DefaultCurrency,
-				FromAccountId:               fromAccountId,
-				FromCreditAccount:           fromCreditAccount,
-				ToAccountId:                 toAccountId,
-			})
+			for j := 0; j < len(bankTransactions); j++ {
+				toAccountId := ""
+
+				if bankTransactions[j].AccountTo != nil {
+					toAccountId = bankTransactions[j].AccountTo.AccountId
+				}
+
+				allData = append(allData, &ofxTransactionData{
+					ofxBaseStatementTransaction: bankTransactions[j].ofxBaseStatementTransaction,
+					DefaultCurrency:             statement.DefaultCurrency,
+					FromAccountId:               fromAccountId,
+					FromCreditAccount:           fromCreditAccount,
+					ToAccountId:                 toAccountId,
+				})
+			}
 		}
 	}
 
-	if file.CreditCardMessageResponseV1 != nil &&
-		file.CreditCardMessageResponseV1.StatementTransactionResponse != nil &&
-		file.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse != nil &&
-		file.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList != nil {
-		statement := file.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse
-		bankTransactions := statement.TransactionList.StatementTransactions
-		fromAccountId := ""
+	if file.CreditCardMessageResponseV1 != nil {
+		for i := 0; i < len(file.CreditCardMessageResponseV1.StatementTransactionResponses); i++ {
+			statementTransactionResponse := file.CreditCardMessageResponseV1.StatementTransactionResponses[i]
 
-		if statement.AccountFrom != nil {
-			fromAccountId = statement.AccountFrom.AccountId
-		}
+			if statementTransactionResponse == nil ||
+				statementTransactionResponse.StatementResponse == nil ||
+				statementTransactionResponse.StatementResponse.TransactionList == nil {
+				continue
+			}
 
-		for i := 0; i < len(bankTransactions); i++ {
-			toAccountId := ""
+			statement := statementTransactionResponse.StatementResponse
+			bankTransactions := statement.TransactionList.StatementTransactions
+			fromAccountId := ""
 
-			if bankTransactions[i].AccountTo != nil {
-				toAccountId = bankTransactions[i].AccountTo.AccountId
+			if statement.AccountFrom != nil {
+				fromAccountId = statement.AccountFrom.AccountId
 			}
 
-			allData = append(allData, &ofxTransactionData{
-				ofxBaseStatementTransaction: bankTransactions[i].ofxBaseStatementTransaction,
-				DefaultCurrency:             statement.DefaultCurrency,
-				FromAccountId:               fromAccountId,
-				FromCreditAccount:           true,
-				ToAccountId:                 toAccountId,
-			})
+			for j := 0; j < len(bankTransactions); j++ {
+				toAccountId := ""
+
+				if bankTransactions[j].AccountTo != nil {
+					toAccountId = bankTransactions[j].AccountTo.AccountId
+				}
+
+				allData = append(allData, &ofxTransactionData{
+					ofxBaseStatementTransaction: bankTransactions[j].ofxBaseStatementTransaction,
+					DefaultCurrency:             statement.DefaultCurrency,
+					FromAccountId:               fromAccountId,
+					FromCreditAccount:           true,
+					ToAccountId:                 toAccountId,
+				})
+			}
 		}
 	}
 
@@ -0,0 +1,259 @@
+package qif
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const qifBankAccountType = "Bank"
+const qifCashAccountType = "Cash"
+const qifCreditCardAccountType = "CCard"
+const qifAssetAccountType = "Oth A"
+const qifLiabilityAccountType = "Oth L"
+
+const qifCategoryNameSeparator = ":"
+
+var qifAccountTypeTransactionHeaderMapping = map[string]string{
+	qifBankAccountType:       qifBankTransactionHeader,
+	qifCashAccountType:       qifCashTransactionHeader,
+	qifCreditCardAccountType: qifCreditCardTransactionHeader,
+	qifAssetAccountType:      qifAssetAccountTransactionHeader,
+	qifLiabilityAccountType:  qifLiabilityAccountTransactionHeader,
+}
+
+// qifTransactionDataExporter defines the structure of quicken interchange format (qif) exporter for transaction data
+type qifTransactionDataExporter struct {
+	dateFormatType qifDateFormatType
+}
+
+// Initialize a quicken interchange format (qif) transaction data exporter singleton instance
+var (
+	QifYearMonthDayTransactionDataExporter = &qifTransactionDataExporter{
+		dateFormatType: qifYearMonthDayDateFormat,
+	}
+
+	QifMonthDayYearTransactionDataExporter = &qifTransactionDataExporter{
+		dateFormatType: qifMonthDayYearDateFormat,
+	}
+
+	QifDayMonthYearTransactionDataExporter = &qifTransactionDataExporter{
+		dateFormatType: qifDayMonthYearDateFormat,
+	}
+)
+
+// ToExportedContent returns the exported quicken interchange format (qif) data, which contains the category list and one transaction list for each account
+// Transfer transactions are only written into the source account, because qif does not support different amounts in both sides
+func (c *qifTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	existsTransferOutTransactions := make(map[int64]bool)
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i].Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			existsTransferOutTransactions[transactions[i].TransactionId] = true
+		}
+	}
+
+	accounts := make([]*models.Account, 0)
+	accountTransactions := make(map[int64][]*models.Transaction)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN && existsTransferOutTransactions[transaction.RelatedId] {
+			continue
+		}
+
+		account, exists := accountMap[transaction.AccountId]
+
+		if !exists {
+			log.Warnf(ctx, "[qif_transaction_data_file_exporter.ToExportedContent] cannot find account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.AccountId, transaction.TransactionId, uid)
+			continue
+		}
+
+		if _, exists := accountTransactions[account.AccountId]; !exists {
+			accounts = append(accounts, account)
+		}
+
+		accountTransactions[account.AccountId] = append(accountTransactions[account.AccountId], transaction)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Category != accounts[j].Category {
+			return accounts[i].Category < accounts[j].Category
+		}
+
+		if accounts[i].DisplayOrder != accounts[j].DisplayOrder {
+			return accounts[i].DisplayOrder < accounts[j].DisplayOrder
+		}
+
+		return accounts[i].AccountId < accounts[j].AccountId
+	})
+
+	var builder strings.Builder
+	c.writeCategories(&builder, categoryMap)
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		accountType := c.getAccountType(account)
+		transactionsInAccount := accountTransactions[account.AccountId]
+
+		sort.SliceStable(transactionsInAccount, func(i, j int) bool {
+			return transactionsInAccount[i].TransactionTime < transactionsInAccount[j].TransactionTime
+		})
+
+		builder.WriteString(qifAccountHeader + "\n")
+		c.writeLine(&builder, 'N', account.Name)
+		c.writeLine(&builder, 'T', accountType)
+		c.writeLine(&builder, 'D', account.Comment)
+		builder.WriteRune(qifEntryEnd)
+		builder.WriteString("\n")
+
+		builder.WriteString(qifAccountTypeTransactionHeaderMapping[accountType] + "\n")
+
+		for j := 0; j < len(transactionsInAccount); j++ {
+			c.writeTransaction(&builder, account, transactionsInAccount[j], accountMap, categoryMap)
+		}
+	}
+
+	return []byte(builder.String()), nil
+}
+
+func (c *qifTransactionDataExporter) writeCategories(builder *strings.Builder, categoryMap map[int64]*models.TransactionCategory) {
+	categories := make([]*models.TransactionCategory, 0, len(categoryMap))
+
+	for _, category := range categoryMap {
+		if category.Type == models.CATEGORY_TYPE_INCOME || category.Type == models.CATEGORY_TYPE_EXPENSE {
+			categories = append(categories, category)
+		}
+	}
+
+	if len(categories) < 1 {
+		return
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Type != categories[j].Type {
+			return categories[i].Type < categories[j].Type
+		}
+
+		nameI := c.getCategoryName(categories[i], categoryMap)
+		nameJ := c.getCategoryName(categories[j], categoryMap)
+
+		if nameI != nameJ {
+			return nameI < nameJ
+		}
+
+		return categories[i].CategoryId < categories[j].CategoryId
+	})
+
+	builder.WriteString(qifCategoryHeader + "\n")
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+		c.writeLine(builder, 'N', c.getCategoryName(category, categoryMap))
+		c.writeLine(builder, 'D', category.Comment)
+
+		if category.Type == models.CATEGORY_TYPE_INCOME {
+			builder.WriteString(string(qifIncomeTransaction) + "\n")
+		} else {
+			builder.WriteString(string(qifExpenseTransaction) + "\n")
+		}
+
+		builder.WriteRune(qifEntryEnd)
+		builder.WriteString("\n")
+	}
+}
+
+func (c *qifTransactionDataExporter) writeTransaction(builder *strings.Builder, account *models.Account, transaction *models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory) {
+	amount := transaction.Amount
+	payee := ""
+	category := ""
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		amount = transaction.RelatedAccountAmount
+		payee = qifOpeningBalancePayeeText
+		category = "[" + account.Name + "]"
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		category = c.getCategoryName(categoryMap[transaction.CategoryId], categoryMap)
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		amount = -amount
+		category = c.getCategoryName(categoryMap[transaction.CategoryId], categoryMap)
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			amount = -amount
+		}
+
+		if relatedAccount, exists := accountMap[transaction.RelatedAccountId]; exists {
+			category = "[" + relatedAccount.Name + "]"
+		}
+	}
+
+	c.writeLine(builder, 'D', c.formatDate(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), transaction.TimezoneUtcOffset))
+	c.writeLine(builder, 'T', utils.FormatAmount(amount))
+	c.writeLine(builder, 'P', payee)
+	c.writeLine(builder, 'M', transaction.Comment)
+	c.writeLine(builder, 'L', category)
+	builder.WriteRune(qifEntryEnd)
+	builder.WriteString("\n")
+}
+
+func (c *qifTransactionDataExporter) writeLine(builder *strings.Builder, fieldType rune, value string) {
+	if value == "" {
+		return
+	}
+
+	value = strings.ReplaceAll(value, "\r\n", " ")
+	value = strings.ReplaceAll(value, "\n", " ")
+
+	builder.WriteRune(fieldType)
+	builder.WriteString(value)
+	builder.WriteString("\n")
+}
+
+func (c *qifTransactionDataExporter) getAccountType(account *models.Account) string {
+	if account.Category == models.ACCOUNT_CATEGORY_CASH {
+		return qifCashAccountType
+	} else if account.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
+		return qifCreditCardAccountType
+	} else if account.Category == models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT ||
+		account.Category == models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT ||
+		account.Category == models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT {
+		return qifBankAccountType
+	} else if account.Category.IsLiability() {
+		return qifLiabilityAccountType
+	}
+
+	return qifAssetAccountType
+}
+
+// getCategoryName returns the category name in the format of "category:subcategory" for secondary category
+func (c *qifTransactionDataExporter) getCategoryName(category *models.TransactionCategory, categoryMap map[int64]*models.TransactionCategory) string {
+	if category == nil {
+		return ""
+	}
+
+	if parentCategory, exists := categoryMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+		return parentCategory.Name + qifCategoryNameSeparator + category.Name
+	}
+
+	return category.Name
+}
+
+// formatDate returns the textual date in the date format of the exporter
+func (c *qifTransactionDataExporter) formatDate(unixTime int64, utcOffsetMinutes int16) string {
+	timezone := time.FixedZone("Transaction Timezone", int(utcOffsetMinutes)*60)
+	dateTime := time.Unix(unixTime, 0).In(timezone)
+
+	if c.dateFormatType == qifMonthDayYearDateFormat {
+		return dateTime.Format("01/02/2006")
+	} else if c.dateFormatType == qifDayMonthYearDateFormat {
+		return dateTime.Format("02/01/2006")
+	}
+
+	return dateTime.Format("2006-01-02")
+}
@@ -0,0 +1,160 @@
+package qif
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestQifTransactionDataFileToExportedContent(t *testing.T) {
+	exporter := QifMonthDayYearTransactionDataExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Checking", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Currency: "CNY"},
+		2: {AccountId: 2, Name: "Credit Card", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Currency: "CNY"},
+		3: {AccountId: 3, Name: "Wallet", Category: models.ACCOUNT_CATEGORY_CASH, Currency: "CNY"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Salary", Type: models.CATEGORY_TYPE_INCOME},
+		20: {CategoryId: 20, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE},
+		21: {CategoryId: 21, ParentCategoryId: 20, Name: "Lunch", Type: models.CATEGORY_TYPE_EXPENSE},
+		30: {CategoryId: 30, Name: "Transfer", Type: models.CATEGORY_TYPE_TRANSFER},
+	}
+
+	transactionTime := time.Date(2024, time.September, 1, 1, 23, 45, 0, time.UTC).Unix()
+	transactions := []*models.Transaction{
+		{TransactionId: 105, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, CategoryId: 30, AccountId: 3, Amount: 500, RelatedId: 104, RelatedAccountId: 1, RelatedAccountAmount: 500, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 4), TimezoneUtcOffset: 480},
+		{TransactionId: 104, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, CategoryId: 30, AccountId: 1, Amount: 500, RelatedId: 105, RelatedAccountId: 3, RelatedAccountAmount: 500, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 4), TimezoneUtcOffset: 480},
+		{TransactionId: 103, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 21, AccountId: 2, Amount: 1234, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 3), TimezoneUtcOffset: -300, Comment: "Lunch\nwith team"},
+		{TransactionId: 102, Type: models.TRANSACTION_DB_TYPE_INCOME, CategoryId: 10, AccountId: 1, Amount: 100000, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 2), TimezoneUtcOffset: 480},
+		{TransactionId: 101, Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 1, Amount: 12345, RelatedAccountId: 1, RelatedAccountAmount: 12345, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 1), TimezoneUtcOffset: 480},
+	}
+
+	content, err := exporter.ToExportedContent(context, 1234567890, transactions, accountMap, categoryMap, nil, nil)
+	assert.Nil(t, err)
+
+	expectedContent := "!Type:Cat\n" +
+		"NSalary\n" +
+		"I\n" +
+		"^\n" +
+		"NFood\n" +
+		"E\n" +
+		"^\n" +
+		"NFood:Lunch\n" +
+		"E\n" +
+		"^\n" +
+		"!Account\n" +
+		"NChecking\n" +
+		"TBank\n" +
+		"^\n" +
+		"!Type:Bank\n" +
+		"D09/01/2024\n" +
+		"T123.45\n" +
+		"POpening Balance\n" +
+		"L[Checking]\n" +
+		"^\n" +
+		"D09/01/2024\n" +
+		"T1000.00\n" +
+		"LSalary\n" +
+		"^\n" +
+		"D09/01/2024\n" +
+		"T-5.00\n" +
+		"L[Wallet]\n" +
+		"^\n" +
+		"!Account\n" +
+		"NCredit Card\n" +
+		"TCCard\n" +
+		"^\n" +
+		"!Type:CCard\n" +
+		"D08/31/2024\n" +
+		"T-12.34\n" +
+		"MLunch with team\n" +
+		"LFood:Lunch\n" +
+		"^\n"
+
+	assert.Equal(t, expectedContent, string(content))
+}
+
+func TestQifTransactionDataFileToExportedContent_DateFormats(t *testing.T) {
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Checking", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Currency: "CNY"},
+	}
+
+	transactionTime := time.Date(2024, time.September, 2, 1, 23, 45, 0, time.UTC).Unix()
+	transactions := []*models.Transaction{
+		{TransactionId: 101, Type: models.TRANSACTION_DB_TYPE_INCOME, AccountId: 1, Amount: 100, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime), TimezoneUtcOffset: 0},
+	}
+
+	content, err := QifYearMonthDayTransactionDataExporter.ToExportedContent(context, 1234567890, transactions, accountMap, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "D2024-09-02\n")
+
+	content, err = QifMonthDayYearTransactionDataExporter.ToExportedContent(context, 1234567890, transactions, accountMap, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "D09/02/2024\n")
+
+	content, err = QifDayMonthYearTransactionDataExporter.ToExportedContent(context, 1234567890, transactions, accountMap, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "D02/09/2024\n")
+}
+
+func TestQifTransactionDataFileToExportedContent_ImportExportedContent(t *testing.T) {
+	exporter := QifDayMonthYearTransactionDataExporter
+	importer := QifDayMonthYearTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Checking", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Currency: "CNY"},
+		2: {AccountId: 2, Name: "Wallet", Category: models.ACCOUNT_CATEGORY_CASH, Currency: "CNY"},
+	}
+	categoryMap := map[int64]*models.TransactionCategory{
+		20: {CategoryId: 20, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE},
+		21: {CategoryId: 21, ParentCategoryId: 20, Name: "Lunch", Type: models.CATEGORY_TYPE_EXPENSE},
+	}
+
+	transactionTime := time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC).Unix()
+	transactions := []*models.Transaction{
+		{TransactionId: 101, Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, AccountId: 1, Amount: 12345, RelatedAccountId: 1, RelatedAccountAmount: 12345, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime)},
+		{TransactionId: 102, Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 21, AccountId: 1, Amount: 1234, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 1), Comment: "Lunch"},
+		{TransactionId: 103, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, Amount: 500, RelatedId: 104, RelatedAccountId: 2, RelatedAccountAmount: 500, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 2)},
+		{TransactionId: 104, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 2, Amount: 500, RelatedId: 103, RelatedAccountId: 1, RelatedAccountAmount: 500, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(transactionTime + 2)},
+	}
+
+	content, err := exporter.ToExportedContent(context, user.Uid, transactions, accountMap, categoryMap, nil, nil)
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, _, _, _, err := importer.ParseImportedData(context, user, content, time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, transactionTime, utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1234), allNewTransactions[1].Amount)
+	assert.Equal(t, "Lunch", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Lunch", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(500), allNewTransactions[2].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Wallet", allNewTransactions[2].OriginalDestinationAccountName)
+}
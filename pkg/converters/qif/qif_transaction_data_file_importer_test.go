@@ -459,6 +459,76 @@ func TestQIFTransactionDataFileParseImportedData_ParseSubCategory(t *testing.T)
 	assert.Equal(t, "Sub Category", allNewSubExpenseCategories[0].Name)
 }
 
+func TestQIFTransactionDataFileParseImportedData_ParseSplitTransaction(t *testing.T) {
+	importer := QifYearMonthDayTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"!Type:Bank\n"+
+			"D2024-09-01\n"+
+			"T-123.45\n"+
+			"LFood:Groceries\n"+
+			"SFood:Groceries\n"+
+			"EMilk\n"+
+			"$-100.00\n"+
+			"SHousehold\n"+
+			"ESoap\n"+
+			"$-23.45\n"+
+			"^\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Groceries", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, 2, len(allNewTransactions[0].Splits))
+	assert.Equal(t, "Groceries", allNewTransactions[0].Splits[0].OriginalCategoryName)
+	assert.Equal(t, int64(10000), allNewTransactions[0].Splits[0].Amount)
+	assert.Equal(t, "Milk", allNewTransactions[0].Splits[0].Comment)
+	assert.Equal(t, int32(1), allNewTransactions[0].Splits[0].DisplayOrder)
+	assert.Equal(t, "Household", allNewTransactions[0].Splits[1].OriginalCategoryName)
+	assert.Equal(t, int64(2345), allNewTransactions[0].Splits[1].Amount)
+	assert.Equal(t, "Soap", allNewTransactions[0].Splits[1].Comment)
+	assert.Equal(t, int32(2), allNewTransactions[0].Splits[1].DisplayOrder)
+
+	assert.Equal(t, "Groceries", allNewSubExpenseCategories[0].Name)
+	assert.Equal(t, "Household", allNewSubExpenseCategories[1].Name)
+}
+
+func TestQIFTransactionDataFileParseImportedData_ParseSplitTransactionWithTransferLine(t *testing.T) {
+	importer := QifYearMonthDayTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"!Type:Bank\n"+
+			"D2024-09-01\n"+
+			"T-123.45\n"+
+			"LFood\n"+
+			"SFood\n"+
+			"$-100.00\n"+
+			"S[Savings]\n"+
+			"$-23.45\n"+
+			"^\n"), time.UTC, converter.DefaultImporterOptions, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "Food", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, 0, len(allNewTransactions[0].Splits))
+}
+
 func TestQIFTransactionDataFileParseImportedData_ParseDescription(t *testing.T) {
 	importer := QifYearMonthDayTransactionDataImporter
 	context := core.NewNullContext()
@@ -536,6 +606,51 @@ func TestQIFTransactionDataFileParseImportedData_WithAdditionalOptions(t *testin
 	assert.Equal(t, "Test2", allNewTransactions[0].OriginalTagNames[0])
 }
 
+func TestQIFTransactionDataFileParseImportedData_WithTransactionRuleEngine(t *testing.T) {
+	importer := QifYearMonthDayTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	categoryMap := map[int64]*models.TransactionCategory{
+		1001: {CategoryId: 1001, Name: "Food", Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 1000},
+	}
+	tagMap := map[int64]*models.TransactionTag{
+		2001: {TagId: 2001, Name: "Coffee"},
+	}
+	rules := []*models.TransactionRule{
+		{RuleId: 1, CounterpartyPattern: "(?i)starbucks", CategoryId: 1001, TagIds: "2001", NewDescription: "Coffee"},
+	}
+	ruleEngine := models.NewTransactionRuleEngine(rules, categoryMap, nil, tagMap)
+
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"!Type:Bank\n"+
+			"D2024-09-01\n"+
+			"T-12.34\n"+
+			"PSTARBUCKS #123\n"+
+			"^\n"+
+			"D2024-09-02\n"+
+			"T-56.78\n"+
+			"PTest\n"+
+			"^\n"), time.UTC, converter.DefaultImporterOptions.WithTransactionRuleEngine(ruleEngine), nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+
+	assert.Equal(t, int64(1001), allNewTransactions[0].CategoryId)
+	assert.Equal(t, "Food", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, []string{"2001"}, allNewTransactions[0].TagIds)
+	assert.Equal(t, []string{"Coffee"}, allNewTransactions[0].OriginalTagNames)
+	assert.Equal(t, "Coffee", allNewTransactions[0].Comment)
+
+	assert.Equal(t, int64(0), allNewTransactions[1].CategoryId)
+	assert.Equal(t, 0, len(allNewTransactions[1].TagIds))
+	assert.Equal(t, "", allNewTransactions[1].Comment)
+}
+
 func TestQIFTransactionDataFileParseImportedData_MissingRequiredFields(t *testing.T) {
 	importer := QifYearMonthDayTransactionDataImporter
 	context := core.NewNullContext()
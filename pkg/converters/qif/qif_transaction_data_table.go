@@ -45,6 +45,7 @@ type qifTransactionDataRow struct {
 	dataTable  *qifTransactionDataTable
 	data       *qifTransactionData
 	finalItems map[datatable.TransactionDataTableColumn]string
+	splits     []*datatable.TransactionDataRowSplit
 }
 
 // qifTransactionDataRowIterator defines the structure of quicken interchange format (qif) transaction data row iterator
@@ -88,6 +89,11 @@ func (r *qifTransactionDataRow) GetData(column datatable.TransactionDataTableCol
 	return ""
 }
 
+// GetSplits returns the split lines of this row, or nil if this row is not a split transaction
+func (r *qifTransactionDataRow) GetSplits() []*datatable.TransactionDataRowSplit {
+	return r.splits
+}
+
 // HasNext returns whether the iterator does not reach the end
 func (t *qifTransactionDataRowIterator) HasNext() bool {
 	return t.currentIndex+1 < len(t.dataTable.allData)
@@ -102,7 +108,7 @@ func (t *qifTransactionDataRowIterator) Next(ctx core.Context, user *models.User
 	t.currentIndex++
 
 	data := t.dataTable.allData[t.currentIndex]
-	rowItems, err := t.parseTransaction(ctx, user, data)
+	rowItems, splits, err := t.parseTransaction(ctx, user, data)
 
 	if err != nil {
 		log.Errorf(ctx, "[qif_transaction_data_table.Next] cannot parsing transaction in row#%d, because %s", t.currentIndex, err.Error())
@@ -113,32 +119,34 @@ func (t *qifTransactionDataRowIterator) Next(ctx core.Context, user *models.User
 		dataTable:  t.dataTable,
 		data:       data,
 		finalItems: rowItems,
+		splits:     splits,
 	}, nil
 }
 
-func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, qifTransaction *qifTransactionData) (map[datatable.TransactionDataTableColumn]string, error) {
+func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, qifTransaction *qifTransactionData) (map[datatable.TransactionDataTableColumn]string, []*datatable.TransactionDataRowSplit, error) {
 	data := make(map[datatable.TransactionDataTableColumn]string, len(qifTransactionSupportedColumns))
+	var splits []*datatable.TransactionDataRowSplit
 
 	if qifTransaction.Date == "" {
-		return nil, errs.ErrMissingTransactionTime
+		return nil, nil, errs.ErrMissingTransactionTime
 	}
 
 	transactionTime, err := t.parseTransactionTime(ctx, qifTransaction.Date)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
 
 	if qifTransaction.Amount == "" {
-		return nil, errs.ErrAmountInvalid
+		return nil, nil, errs.ErrAmountInvalid
 	}
 
 	amount, err := utils.ParseAmount(strings.ReplaceAll(qifTransaction.Amount, ",", "")) // trim thousands separator
 
 	if err != nil {
-		return nil, errs.ErrAmountInvalid
+		return nil, nil, errs.ErrAmountInvalid
 	}
 
 	if qifTransaction.Account != nil {
@@ -173,13 +181,16 @@ func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
 		}
 
-		if strings.Index(qifTransaction.Category, ":") > 0 { // category:subcategory
-			categories := strings.Split(qifTransaction.Category, ":")
-			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = categories[0]
-			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = categories[len(categories)-1]
-		} else {
-			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = ""
-			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = qifTransaction.Category
+		data[datatable.TRANSACTION_DATA_TABLE_CATEGORY], data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = t.parseCategory(qifTransaction.Category)
+		splits, err = t.parseSplits(qifTransaction, amount < 0)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(splits) > 0 { // the category of split transaction is the category of its first split line
+			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = splits[0].Category
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = splits[0].SubCategory
 		}
 	}
 
@@ -191,7 +202,56 @@ func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = qifTransaction.Payee
 	}
 
-	return data, nil
+	return data, splits, nil
+}
+
+func (t *qifTransactionDataRowIterator) parseSplits(qifTransaction *qifTransactionData, isExpense bool) ([]*datatable.TransactionDataRowSplit, error) {
+	if len(qifTransaction.SubTransactionCategory) < models.MinimumSplitsCountOfTransaction || len(qifTransaction.SubTransactionCategory) != len(qifTransaction.SubTransactionAmount) {
+		return nil, nil
+	}
+
+	splits := make([]*datatable.TransactionDataRowSplit, len(qifTransaction.SubTransactionCategory))
+
+	for i := 0; i < len(qifTransaction.SubTransactionCategory); i++ {
+		subCategory := qifTransaction.SubTransactionCategory[i]
+
+		if len(subCategory) > 0 && subCategory[0] == '[' && subCategory[len(subCategory)-1] == ']' { // split line which transfers to another account is not supported
+			return nil, nil
+		}
+
+		amount, err := utils.ParseAmount(strings.ReplaceAll(qifTransaction.SubTransactionAmount[i], ",", "")) // trim thousands separator
+
+		if err != nil {
+			return nil, errs.ErrAmountInvalid
+		}
+
+		if isExpense {
+			amount = -amount
+		}
+
+		split := &datatable.TransactionDataRowSplit{
+			Amount: utils.FormatAmount(amount),
+		}
+
+		split.Category, split.SubCategory = t.parseCategory(subCategory)
+
+		if len(qifTransaction.SubTransactionMemo) == len(qifTransaction.SubTransactionCategory) {
+			split.Description = qifTransaction.SubTransactionMemo[i]
+		}
+
+		splits[i] = split
+	}
+
+	return splits, nil
+}
+
+func (t *qifTransactionDataRowIterator) parseCategory(category string) (string, string) {
+	if strings.Index(category, ":") > 0 { // category:subcategory
+		categories := strings.Split(category, ":")
+		return categories[0], categories[len(categories)-1]
+	}
+
+	return "", category
 }
 
 func (t *qifTransactionDataRowIterator) parseTransactionTime(ctx core.Context, date string) (string, error) {
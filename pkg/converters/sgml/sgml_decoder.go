@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"io"
 	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -246,7 +247,11 @@ func (d *Decoder) getStructTypeInfo(reflectType reflect.Type) (*sgmlTypeInfo, er
 			sgmlFieldName = field.Tag.Get(xmlTagName)
 		}
 
-		if sgmlFieldName == "" || field.Name == sgmlNameFieldName || field.Name == xmlNameFieldName {
+		if optionsIndex := strings.Index(sgmlFieldName, ","); optionsIndex >= 0 { // ignore tag options (e.g. omitempty)
+			sgmlFieldName = sgmlFieldName[0:optionsIndex]
+		}
+
+		if sgmlFieldName == "" || sgmlFieldName == "-" || field.Name == sgmlNameFieldName || field.Name == xmlNameFieldName {
 			continue
 		}
 
@@ -28,6 +28,18 @@ func GetTransactionDataExporter(fileType string) converter.TransactionDataExport
 		return _default.DefaultTransactionDataCSVFileConverter
 	} else if fileType == "tsv" {
 		return _default.DefaultTransactionDataTSVFileConverter
+	} else if fileType == "ofx" {
+		return ofx.OFXTransactionDataExporter
+	} else if fileType == "qif_ymd" {
+		return qif.QifYearMonthDayTransactionDataExporter
+	} else if fileType == "qif_mdy" {
+		return qif.QifMonthDayYearTransactionDataExporter
+	} else if fileType == "qif_dmy" {
+		return qif.QifDayMonthYearTransactionDataExporter
+	} else if fileType == "gnucash" {
+		return gnucash.GnuCashTransactionDataExporter
+	} else if fileType == "beancount" {
+		return beancount.BeancountTransactionDataExporter
 	} else {
 		return nil
 	}
@@ -0,0 +1,65 @@
+package core
+
+// APITokenScope represents the permission scope of api token
+type APITokenScope string
+
+// API Token Scopes
+const (
+	API_TOKEN_SCOPE_READ_USER_PROFILE   APITokenScope = "user_profile:read"
+	API_TOKEN_SCOPE_READ_ACCOUNTS       APITokenScope = "accounts:read"
+	API_TOKEN_SCOPE_WRITE_ACCOUNTS      APITokenScope = "accounts:write"
+	API_TOKEN_SCOPE_READ_TRANSACTIONS   APITokenScope = "transactions:read"
+	API_TOKEN_SCOPE_WRITE_TRANSACTIONS  APITokenScope = "transactions:write"
+	API_TOKEN_SCOPE_READ_BASIC_DATA     APITokenScope = "basic_data:read"
+	API_TOKEN_SCOPE_WRITE_BASIC_DATA    APITokenScope = "basic_data:write"
+	API_TOKEN_SCOPE_READ_BUDGETS        APITokenScope = "budgets:read"
+	API_TOKEN_SCOPE_WRITE_BUDGETS       APITokenScope = "budgets:write"
+	API_TOKEN_SCOPE_READ_INVESTMENTS    APITokenScope = "investments:read"
+	API_TOKEN_SCOPE_WRITE_INVESTMENTS   APITokenScope = "investments:write"
+	API_TOKEN_SCOPE_READ_STATISTICS     APITokenScope = "statistics:read"
+	API_TOKEN_SCOPE_READ_EXCHANGE_RATES APITokenScope = "exchange_rates:read"
+	API_TOKEN_SCOPE_EXPORT_DATA         APITokenScope = "data:export"
+	API_TOKEN_SCOPE_IMPORT_DATA         APITokenScope = "data:import"
+)
+
+// AllAPITokenScopes represents all the available scopes of api token
+var AllAPITokenScopes = APITokenScopes{
+	API_TOKEN_SCOPE_READ_USER_PROFILE,
+	API_TOKEN_SCOPE_READ_ACCOUNTS,
+	API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	API_TOKEN_SCOPE_READ_TRANSACTIONS,
+	API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	API_TOKEN_SCOPE_READ_BASIC_DATA,
+	API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	API_TOKEN_SCOPE_READ_BUDGETS,
+	API_TOKEN_SCOPE_WRITE_BUDGETS,
+	API_TOKEN_SCOPE_READ_INVESTMENTS,
+	API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+	API_TOKEN_SCOPE_READ_STATISTICS,
+	API_TOKEN_SCOPE_READ_EXCHANGE_RATES,
+	API_TOKEN_SCOPE_EXPORT_DATA,
+	API_TOKEN_SCOPE_IMPORT_DATA,
+}
+
+// IsValid returns whether the api token scope is valid
+func (s APITokenScope) IsValid() bool {
+	return containsTokenScope(AllAPITokenScopes, s)
+}
+
+// APITokenScopes represents the permission scopes of api token, empty scopes means full access
+type APITokenScopes []APITokenScope
+
+// Contains returns whether contains the specified scope
+func (s APITokenScopes) Contains(scope APITokenScope) bool {
+	return containsTokenScope(s, scope)
+}
+
+// String returns a textual representation of the api token scopes separated by commas
+func (s APITokenScopes) String() string {
+	return formatTokenScopes(s)
+}
+
+// ParseAPITokenScopes returns the api token scopes according to the textual scopes separated by commas
+func ParseAPITokenScopes(scopes string) (APITokenScopes, error) {
+	return parseTokenScopes[APITokenScope](scopes, "api")
+}
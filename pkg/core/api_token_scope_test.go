@@ -0,0 +1,33 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITokenScopesContains(t *testing.T) {
+	scopes := APITokenScopes{API_TOKEN_SCOPE_READ_ACCOUNTS, API_TOKEN_SCOPE_READ_TRANSACTIONS}
+	assert.True(t, scopes.Contains(API_TOKEN_SCOPE_READ_ACCOUNTS))
+	assert.True(t, scopes.Contains(API_TOKEN_SCOPE_READ_TRANSACTIONS))
+	assert.False(t, scopes.Contains(API_TOKEN_SCOPE_WRITE_TRANSACTIONS))
+	assert.False(t, APITokenScopes(nil).Contains(API_TOKEN_SCOPE_READ_ACCOUNTS))
+}
+
+func TestAPITokenScopesString(t *testing.T) {
+	assert.Equal(t, "", APITokenScopes{}.String())
+	assert.Equal(t, "accounts:read,data:export", APITokenScopes{API_TOKEN_SCOPE_READ_ACCOUNTS, API_TOKEN_SCOPE_EXPORT_DATA}.String())
+}
+
+func TestParseAPITokenScopes(t *testing.T) {
+	scopes, err := ParseAPITokenScopes("transactions:read, accounts:read,transactions:read")
+	assert.Nil(t, err)
+	assert.Equal(t, APITokenScopes{API_TOKEN_SCOPE_READ_TRANSACTIONS, API_TOKEN_SCOPE_READ_ACCOUNTS}, scopes)
+
+	scopes, err = ParseAPITokenScopes("")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(scopes))
+
+	_, err = ParseAPITokenScopes("transactions:read,tokens:write")
+	assert.NotNil(t, err)
+}
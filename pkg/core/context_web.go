@@ -16,6 +16,8 @@ const webContextTextualTokenFieldKey = "TOKEN_STRING"
 const webContextTokenClaimsFieldKey = "TOKEN_CLAIMS"
 const webContextTokenContextFieldKey = "TOKEN_CONTEXT"
 const webContextResponseErrorFieldKey = "RESPONSE_ERROR"
+const webContextJSONRPCNotificationSenderFieldKey = "JSONRPC_NOTIFICATION_SENDER"
+const webContextLedgerBookUidFieldKey = "LEDGER_BOOK_UID"
 
 // AcceptLanguageHeaderName represents the header name of accept language
 const AcceptLanguageHeaderName = "Accept-Language"
@@ -29,10 +31,14 @@ const ClientTimezoneOffsetHeaderName = "X-Timezone-Offset"
 // ClientTimezoneNameHeaderName represents the header name of client timezone name
 const ClientTimezoneNameHeaderName = "X-Timezone-Name"
 
+// LedgerIdHeaderName represents the header name of the shared ledger which current request operates on
+const LedgerIdHeaderName = "X-Ledger-Id"
+
 const tokenHeaderName = "Authorization"
 const tokenHeaderValuePrefix = "bearer "
 const tokenQueryStringParam = "token"
 const tokenCookieParam = "ebk_auth_token"
+const ledgerIdQueryStringParam = "ledgerId"
 
 // WebContext represents the request and response context
 type WebContext struct {
@@ -137,8 +143,46 @@ func (c *WebContext) GetTokenContext() string {
 	return context.(string)
 }
 
-// GetCurrentUid returns the current user uid by the current user token
+// GetLedgerIdValue returns the textual shared ledger id which current request operates on from the request header or the request query string
+func (c *WebContext) GetLedgerIdValue() string {
+	ledgerIdValue := c.GetHeader(LedgerIdHeaderName)
+
+	if ledgerIdValue != "" {
+		return ledgerIdValue
+	}
+
+	return c.Query(ledgerIdQueryStringParam)
+}
+
+// SetLedgerBookUid sets the uid of the book which holds the data of the shared ledger which current request operates on
+func (c *WebContext) SetLedgerBookUid(uid int64) {
+	c.Set(webContextLedgerBookUidFieldKey, uid)
+}
+
+// GetLedgerBookUid returns the uid of the book which holds the data of the shared ledger which current request operates on
+func (c *WebContext) GetLedgerBookUid() int64 {
+	uid, exists := c.Get(webContextLedgerBookUidFieldKey)
+
+	if !exists {
+		return 0
+	}
+
+	return uid.(int64)
+}
+
+// GetCurrentUid returns the uid of the book which current request operates on, it is the ledger book uid if current request operates on a shared ledger, otherwise it is the current user uid
 func (c *WebContext) GetCurrentUid() int64 {
+	ledgerBookUid := c.GetLedgerBookUid()
+
+	if ledgerBookUid > 0 {
+		return ledgerBookUid
+	}
+
+	return c.GetCurrentOperatorUid()
+}
+
+// GetCurrentOperatorUid returns the current user uid by the current user token
+func (c *WebContext) GetCurrentOperatorUid() int64 {
 	claims := c.GetTokenClaims()
 
 	if claims == nil {
@@ -226,6 +270,24 @@ func (c *WebContext) GetResponseError() *errs.Error {
 	return err.(*errs.Error)
 }
 
+// SetJSONRPCNotificationSender sets the function which sends JSON-RPC notification to the client during current request
+func (c *WebContext) SetJSONRPCNotificationSender(sender func(*JSONRPCNotification)) {
+	c.Set(webContextJSONRPCNotificationSenderFieldKey, sender)
+}
+
+// SendJSONRPCNotification sends the JSON-RPC notification to the client during current request and returns whether the notification is sent
+func (c *WebContext) SendJSONRPCNotification(notification *JSONRPCNotification) bool {
+	sender, exists := c.Get(webContextJSONRPCNotificationSenderFieldKey)
+
+	if !exists {
+		return false
+	}
+
+	sender.(func(*JSONRPCNotification))(notification)
+
+	return true
+}
+
 // getClientTimezoneOffset returns the client timezone offset
 func (c *WebContext) getClientTimezoneOffset() (int16, error) {
 	value := c.GetHeader(ClientTimezoneOffsetHeaderName)
@@ -13,6 +13,13 @@ type JSONRPCRequest struct {
 	ID      any             `json:"id,omitempty"`
 }
 
+// JSONRPCNotification represents the JSON-RPC 2.0 notification sent by server
+type JSONRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
 // JSONRPCResponse represents the JSON-RPC 2.0 response
 type JSONRPCResponse struct {
 	JSONRPC string        `json:"jsonrpc"`
@@ -35,6 +42,13 @@ var JSONRPCParseError = &JSONRPCError{
 	Data:    nil,
 }
 
+// JSONRPCInvalidRequestError represents the "Invalid Request" error in JSON-RPC 2.0
+var JSONRPCInvalidRequestError = &JSONRPCError{
+	Code:    -32600,
+	Message: "Invalid Request",
+	Data:    nil,
+}
+
 // JSONRPCMethodNotFoundError represents the "Method not found" error in JSON-RPC 2.0
 var JSONRPCMethodNotFoundError = &JSONRPCError{
 	Code:    -32601,
@@ -56,6 +70,25 @@ var JSONRPCInternalError = &JSONRPCError{
 	Data:    nil,
 }
 
+// IsNotification returns whether the request is a notification which does not need response
+func (r *JSONRPCRequest) IsNotification() bool {
+	return r.ID == nil
+}
+
+// IsResponse returns whether the message is actually a response sent by the other side
+func (r *JSONRPCRequest) IsResponse() bool {
+	return r.Method == ""
+}
+
+// NewJSONRPCNotification creates a new JSON-RPC notification with the method and params
+func NewJSONRPCNotification(method string, params any) *JSONRPCNotification {
+	return &JSONRPCNotification{
+		JSONRPC: JSONRPCVersion,
+		Method:  method,
+		Params:  params,
+	}
+}
+
 // NewJSONRPCResponse creates a new JSON-RPC response with the result
 func NewJSONRPCResponse(id any, result any) *JSONRPCResponse {
 	return &JSONRPCResponse{
@@ -0,0 +1,43 @@
+package core
+
+// MCPTokenScope represents the permission scope of mcp token
+type MCPTokenScope string
+
+// MCP Token Scopes
+const (
+	MCP_TOKEN_SCOPE_READ_BASIC_DATA    MCPTokenScope = "basic_data:read"
+	MCP_TOKEN_SCOPE_READ_TRANSACTIONS  MCPTokenScope = "transactions:read"
+	MCP_TOKEN_SCOPE_READ_STATISTICS    MCPTokenScope = "statistics:read"
+	MCP_TOKEN_SCOPE_WRITE_TRANSACTIONS MCPTokenScope = "transactions:write"
+)
+
+// AllMCPTokenScopes represents all the available scopes of mcp token
+var AllMCPTokenScopes = MCPTokenScopes{
+	MCP_TOKEN_SCOPE_READ_BASIC_DATA,
+	MCP_TOKEN_SCOPE_READ_TRANSACTIONS,
+	MCP_TOKEN_SCOPE_READ_STATISTICS,
+	MCP_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+}
+
+// IsValid returns whether the mcp token scope is valid
+func (s MCPTokenScope) IsValid() bool {
+	return containsTokenScope(AllMCPTokenScopes, s)
+}
+
+// MCPTokenScopes represents the permission scopes of mcp token
+type MCPTokenScopes []MCPTokenScope
+
+// Contains returns whether contains the specified scope
+func (s MCPTokenScopes) Contains(scope MCPTokenScope) bool {
+	return containsTokenScope(s, scope)
+}
+
+// String returns a textual representation of the mcp token scopes separated by commas
+func (s MCPTokenScopes) String() string {
+	return formatTokenScopes(s)
+}
+
+// ParseMCPTokenScopes returns the mcp token scopes according to the textual scopes separated by commas
+func ParseMCPTokenScopes(scopes string) (MCPTokenScopes, error) {
+	return parseTokenScopes[MCPTokenScope](scopes, "mcp")
+}
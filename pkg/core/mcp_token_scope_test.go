@@ -0,0 +1,33 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMCPTokenScopesContains(t *testing.T) {
+	scopes := MCPTokenScopes{MCP_TOKEN_SCOPE_READ_BASIC_DATA, MCP_TOKEN_SCOPE_READ_TRANSACTIONS}
+	assert.True(t, scopes.Contains(MCP_TOKEN_SCOPE_READ_BASIC_DATA))
+	assert.True(t, scopes.Contains(MCP_TOKEN_SCOPE_READ_TRANSACTIONS))
+	assert.False(t, scopes.Contains(MCP_TOKEN_SCOPE_WRITE_TRANSACTIONS))
+	assert.False(t, MCPTokenScopes(nil).Contains(MCP_TOKEN_SCOPE_READ_BASIC_DATA))
+}
+
+func TestMCPTokenScopesString(t *testing.T) {
+	assert.Equal(t, "", MCPTokenScopes{}.String())
+	assert.Equal(t, "basic_data:read,transactions:write", MCPTokenScopes{MCP_TOKEN_SCOPE_READ_BASIC_DATA, MCP_TOKEN_SCOPE_WRITE_TRANSACTIONS}.String())
+}
+
+func TestParseMCPTokenScopes(t *testing.T) {
+	scopes, err := ParseMCPTokenScopes("transactions:read, statistics:read,transactions:read")
+	assert.Nil(t, err)
+	assert.Equal(t, MCPTokenScopes{MCP_TOKEN_SCOPE_READ_TRANSACTIONS, MCP_TOKEN_SCOPE_READ_STATISTICS}, scopes)
+
+	scopes, err = ParseMCPTokenScopes("")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(scopes))
+
+	_, err = ParseMCPTokenScopes("transactions:read,accounts:write")
+	assert.NotNil(t, err)
+}
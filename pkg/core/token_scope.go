@@ -0,0 +1,58 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenScope represents the permission scope of token
+type tokenScope interface {
+	~string
+	IsValid() bool
+}
+
+// containsTokenScope returns whether the scopes contain the specified scope
+func containsTokenScope[T ~string](scopes []T, scope T) bool {
+	for i := 0; i < len(scopes); i++ {
+		if scopes[i] == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatTokenScopes returns a textual representation of the token scopes separated by commas
+func formatTokenScopes[T ~string](scopes []T) string {
+	textualScopes := make([]string, len(scopes))
+
+	for i := 0; i < len(scopes); i++ {
+		textualScopes[i] = string(scopes[i])
+	}
+
+	return strings.Join(textualScopes, ",")
+}
+
+// parseTokenScopes returns the token scopes according to the textual scopes separated by commas, the duplicate scopes are removed
+func parseTokenScopes[T tokenScope](scopes string, tokenType string) ([]T, error) {
+	items := strings.Split(scopes, ",")
+	result := make([]T, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		scope := T(strings.TrimSpace(items[i]))
+
+		if scope == "" {
+			continue
+		}
+
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("invalid %s token scope \"%s\"", tokenType, scope)
+		}
+
+		if !containsTokenScope(result, scope) {
+			result = append(result, scope)
+		}
+	}
+
+	return result, nil
+}
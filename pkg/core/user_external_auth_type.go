@@ -1,6 +1,8 @@
 package core
 
 const USER_EXTERNAL_AUTH_TYPE_CATEOGRY_OAUTH2 = "oauth2"
+const USER_EXTERNAL_AUTH_TYPE_CATEOGRY_LDAP = "ldap"
+const USER_EXTERNAL_AUTH_TYPE_CATEOGRY_SAML = "saml"
 
 // UserExternalAuthType represents the type of user external authentication
 type UserExternalAuthType string
@@ -11,6 +13,8 @@ const (
 	USER_EXTERNAL_AUTH_TYPE_OAUTH2_NEXTCLOUD UserExternalAuthType = "nextcloud"
 	USER_EXTERNAL_AUTH_TYPE_OAUTH2_GITEA     UserExternalAuthType = "gitea"
 	USER_EXTERNAL_AUTH_TYPE_OAUTH2_GITHUB    UserExternalAuthType = "github"
+	USER_EXTERNAL_AUTH_TYPE_LDAP             UserExternalAuthType = "ldap"
+	USER_EXTERNAL_AUTH_TYPE_SAML             UserExternalAuthType = "saml"
 )
 
 // GetCategory returns the category of the UserExternalAuthType
@@ -21,6 +25,10 @@ func (t UserExternalAuthType) GetCategory() string {
 		USER_EXTERNAL_AUTH_TYPE_OAUTH2_GITEA,
 		USER_EXTERNAL_AUTH_TYPE_OAUTH2_GITHUB:
 		return USER_EXTERNAL_AUTH_TYPE_CATEOGRY_OAUTH2
+	case USER_EXTERNAL_AUTH_TYPE_LDAP:
+		return USER_EXTERNAL_AUTH_TYPE_CATEOGRY_LDAP
+	case USER_EXTERNAL_AUTH_TYPE_SAML:
+		return USER_EXTERNAL_AUTH_TYPE_CATEOGRY_SAML
 	}
 	return ""
 }
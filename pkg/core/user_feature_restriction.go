@@ -46,6 +46,32 @@ func (r UserFeatureRestrictions) String() string {
 	return builder.String()
 }
 
+// ToTypes returns all the restriction types of user features contained in the restrictions
+func (r UserFeatureRestrictions) ToTypes() []UserFeatureRestrictionType {
+	restrictionTypes := make([]UserFeatureRestrictionType, 0)
+
+	for restrictionType := userFeatureRestrictionTypeMinValue; restrictionType <= userFeatureRestrictionTypeMaxValue; restrictionType++ {
+		if r.Contains(restrictionType) {
+			restrictionTypes = append(restrictionTypes, restrictionType)
+		}
+	}
+
+	return restrictionTypes
+}
+
+// NewUserFeatureRestrictions returns restrictions of user features which contain all the specified valid restriction types
+func NewUserFeatureRestrictions(restrictionTypes []UserFeatureRestrictionType) UserFeatureRestrictions {
+	restrictions := UserFeatureRestrictions(0)
+
+	for i := 0; i < len(restrictionTypes); i++ {
+		if restrictionTypes[i].IsValid() {
+			restrictions = restrictions.Add(restrictionTypes[i])
+		}
+	}
+
+	return restrictions
+}
+
 // ParseUserFeatureRestrictions returns restrictions of user features according to the textual restrictions of user features  separated by commas
 func ParseUserFeatureRestrictions(featureRestrictions string) UserFeatureRestrictions {
 	if len(featureRestrictions) < 1 {
@@ -95,10 +121,17 @@ const (
 	USER_FEATURE_RESTRICTION_TYPE_GENERATE_API_TOKEN                           UserFeatureRestrictionType = 17
 	USER_FEATURE_RESTRICTION_TYPE_CREATE_TRANSACTION_FROM_AI_TEXT_RECOGNITION  UserFeatureRestrictionType = 18
 	USER_FEATURE_RESTRICTION_TYPE_UPLOAD_CUSTOM_ICON                           UserFeatureRestrictionType = 19
+	USER_FEATURE_RESTRICTION_TYPE_PASSKEY_LOGIN                                UserFeatureRestrictionType = 20
+	USER_FEATURE_RESTRICTION_TYPE_SAML_LOGIN                                   UserFeatureRestrictionType = 21
 )
 
 const userFeatureRestrictionTypeMinValue UserFeatureRestrictionType = USER_FEATURE_RESTRICTION_TYPE_UPDATE_PASSWORD
-const userFeatureRestrictionTypeMaxValue UserFeatureRestrictionType = USER_FEATURE_RESTRICTION_TYPE_UPLOAD_CUSTOM_ICON
+const userFeatureRestrictionTypeMaxValue UserFeatureRestrictionType = USER_FEATURE_RESTRICTION_TYPE_SAML_LOGIN
+
+// IsValid returns whether the restriction type of user features is valid
+func (t UserFeatureRestrictionType) IsValid() bool {
+	return userFeatureRestrictionTypeMinValue <= t && t <= userFeatureRestrictionTypeMaxValue
+}
 
 // String returns a textual representation of the restriction type of user features
 func (t UserFeatureRestrictionType) String() string {
@@ -141,6 +174,10 @@ func (t UserFeatureRestrictionType) String() string {
 		return "Create Transaction from AI Text Recognition"
 	case USER_FEATURE_RESTRICTION_TYPE_UPLOAD_CUSTOM_ICON:
 		return "Upload Custom Icon"
+	case USER_FEATURE_RESTRICTION_TYPE_PASSKEY_LOGIN:
+		return "Passkey Login"
+	case USER_FEATURE_RESTRICTION_TYPE_SAML_LOGIN:
+		return "SAML Login"
 	default:
 		return fmt.Sprintf("Invalid(%d)", int(t))
 	}
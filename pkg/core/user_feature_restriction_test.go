@@ -107,14 +107,38 @@ func TestParseUserFeatureRestrictions(t *testing.T) {
 	assert.Equal(t, expectedValue, actualValue)
 
 	expectedValue = UserFeatureRestrictions(1)
-	actualValue = ParseUserFeatureRestrictions("1,20")
+	actualValue = ParseUserFeatureRestrictions("1,22")
 	assert.Equal(t, expectedValue, actualValue)
 
 	expectedValue = UserFeatureRestrictions(255)
-	actualValue = ParseUserFeatureRestrictions("1,2,3,4,5,6,7,8,20,21,22")
+	actualValue = ParseUserFeatureRestrictions("1,2,3,4,5,6,7,8,22,23,24")
 	assert.Equal(t, expectedValue, actualValue)
 
 	expectedValue = UserFeatureRestrictions(255)
-	actualValue = ParseUserFeatureRestrictions("1,2,3,4,5,6,7,8,a,b,20")
+	actualValue = ParseUserFeatureRestrictions("1,2,3,4,5,6,7,8,a,b,22")
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestUserFeatureRestrictionsToTypes(t *testing.T) {
+	expectedValue := []UserFeatureRestrictionType{}
+	actualValue := UserFeatureRestrictions(0).ToTypes()
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = []UserFeatureRestrictionType{USER_FEATURE_RESTRICTION_TYPE_UPDATE_PASSWORD, USER_FEATURE_RESTRICTION_TYPE_UPDATE_AVATAR, USER_FEATURE_RESTRICTION_TYPE_SAML_LOGIN}
+	actualValue = UserFeatureRestrictions(0).Add(USER_FEATURE_RESTRICTION_TYPE_SAML_LOGIN).Add(USER_FEATURE_RESTRICTION_TYPE_UPDATE_PASSWORD).Add(USER_FEATURE_RESTRICTION_TYPE_UPDATE_AVATAR).ToTypes()
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestNewUserFeatureRestrictions(t *testing.T) {
+	expectedValue := UserFeatureRestrictions(0)
+	actualValue := NewUserFeatureRestrictions(nil)
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = UserFeatureRestrictions(9)
+	actualValue = NewUserFeatureRestrictions([]UserFeatureRestrictionType{USER_FEATURE_RESTRICTION_TYPE_UPDATE_PASSWORD, USER_FEATURE_RESTRICTION_TYPE_UPDATE_AVATAR, USER_FEATURE_RESTRICTION_TYPE_UPDATE_PASSWORD})
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = UserFeatureRestrictions(1)
+	actualValue = NewUserFeatureRestrictions([]UserFeatureRestrictionType{0, USER_FEATURE_RESTRICTION_TYPE_UPDATE_PASSWORD, 22, 64})
 	assert.Equal(t, expectedValue, actualValue)
 }
@@ -84,6 +84,18 @@ func (c *CronJobSchedulerContainer) registerAllJobs(ctx core.Context, config *se
 	if config.EnableCreateScheduledTransaction {
 		Container.registerIntervalJob(ctx, CreateScheduledTransactionJob)
 	}
+
+	if config.EnableSendBudgetAlerts && config.EnableSMTP {
+		Container.registerIntervalJob(ctx, SendBudgetAlertsJob)
+	}
+
+	if config.EnableSnapshotExchangeRates && config.ExchangeRatesDataSource != settings.UserCustomExchangeRatesDataSource {
+		Container.registerIntervalJob(ctx, SnapshotExchangeRatesJob)
+	}
+
+	if config.EnableWebhook {
+		Container.registerIntervalJob(ctx, DeliverWebhooksJob)
+	}
 }
 
 func (c *CronJobSchedulerContainer) registerIntervalJob(ctx core.Context, job *CronJob) {
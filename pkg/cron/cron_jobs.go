@@ -4,7 +4,11 @@ import (
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // RemoveExpiredTokensJob represents the cron job which periodically remove expired user tokens from the database
@@ -30,3 +34,53 @@ var CreateScheduledTransactionJob = &CronJob{
 		return services.Transactions.CreateScheduledTransactions(c, time.Now().Unix(), c.GetInterval())
 	},
 }
+
+// SendBudgetAlertsJob represents the cron job which periodically check budget spending and send alert emails to users
+var SendBudgetAlertsJob = &CronJob{
+	Name:        "SendBudgetAlerts",
+	Description: "Periodically check budget spending and send alert emails to users.",
+	Period: CronJobIntervalPeriod{
+		Interval: time.Hour,
+	},
+	Run: func(c *core.CronContext) error {
+		return services.BudgetAlerts.SendBudgetAlerts(c, time.Now().Unix())
+	},
+}
+
+// SnapshotExchangeRatesJob represents the cron job which periodically save the latest exchange rates of current data source to the exchange rate history
+var SnapshotExchangeRatesJob = &CronJob{
+	Name:        "SnapshotExchangeRates",
+	Description: "Periodically save the latest exchange rates of current data source to the exchange rate history.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 23,
+	},
+	Run: func(c *core.CronContext) error {
+		config := settings.Container.GetCurrentConfig()
+		exchangeRateResp, err := exchangerates.Container.GetLatestExchangeRates(c, 0, config)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = services.ExchangeRateHistories.SaveExchangeRates(c, config.ExchangeRatesDataSource, []*models.LatestExchangeRateResponse{exchangeRateResp})
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof(c, "[cron_jobs.SnapshotExchangeRatesJob] exchange rates of data source \"%s\" updated at %d have been saved", config.ExchangeRatesDataSource, exchangeRateResp.UpdateTime)
+		return nil
+	},
+}
+
+// DeliverWebhooksJob represents the cron job which periodically deliver the pending webhook events to the subscribed urls
+var DeliverWebhooksJob = &CronJob{
+	Name:        "DeliverWebhooks",
+	Description: "Periodically deliver the pending webhook events to the subscribed urls and retry the failed deliveries.",
+	Period: CronJobIntervalPeriod{
+		Interval: time.Minute,
+	},
+	Run: func(c *core.CronContext) error {
+		return services.Webhooks.DeliverPendingWebhooks(c, time.Now().Unix())
+	},
+}
@@ -0,0 +1,267 @@
+package duplicatechecker
+
+import (
+	"fmt"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const databaseDuplicateCheckerFailureCountExpiration = 1 * time.Minute
+
+// DatabaseDuplicateChecker represents database duplicate checker, which can be shared between multiple instances
+type DatabaseDuplicateChecker struct {
+	store                  *datastore.DataStore
+	defaultExpiration      time.Duration
+	cleanupIntervalTicker  *time.Ticker
+	cleanupIntervalStopper chan bool
+}
+
+// NewDatabaseDuplicateChecker returns a new database duplicate checker
+func NewDatabaseDuplicateChecker(config *settings.Config, store *datastore.DataStore) (*DatabaseDuplicateChecker, error) {
+	checker := &DatabaseDuplicateChecker{
+		store:             store,
+		defaultExpiration: config.DuplicateSubmissionsIntervalDuration,
+	}
+
+	if config.DatabaseDuplicateCheckerCleanupIntervalDuration > 0 {
+		checker.cleanupIntervalTicker = time.NewTicker(config.DatabaseDuplicateCheckerCleanupIntervalDuration)
+		checker.cleanupIntervalStopper = make(chan bool)
+
+		go func() {
+			for {
+				select {
+				case <-checker.cleanupIntervalTicker.C:
+					checker.RemoveExpiredRecords()
+				case <-checker.cleanupIntervalStopper:
+					return
+				}
+			}
+		}()
+	}
+
+	return checker, nil
+}
+
+// GetSubmissionRemark returns whether the same submission has been processed and related remark
+func (c *DatabaseDuplicateChecker) GetSubmissionRemark(checkerType DuplicateCheckerType, uid int64, identification string) (bool, string) {
+	record, err := c.getRecord(c.getCheckKey(checkerType, uid, identification))
+
+	if err != nil {
+		log.Errorf(core.NewNullContext(), "[database_duplicate_checker.GetSubmissionRemark] failed to get submission remark, because %s", err.Error())
+		return false, ""
+	}
+
+	if record == nil {
+		return false, ""
+	}
+
+	return true, record.Remark
+}
+
+// SetSubmissionRemark saves the identification and remark to database
+func (c *DatabaseDuplicateChecker) SetSubmissionRemark(checkerType DuplicateCheckerType, uid int64, identification string, remark string) {
+	c.SetSubmissionRemarkWithCustomExpiration(checkerType, uid, identification, remark, c.defaultExpiration)
+}
+
+// SetSubmissionRemarkWithCustomExpiration saves the identification and remark to database with custom expiration time
+func (c *DatabaseDuplicateChecker) SetSubmissionRemarkWithCustomExpiration(checkerType DuplicateCheckerType, uid int64, identification string, remark string, expiration time.Duration) {
+	err := c.setRecord(c.getCheckKey(checkerType, uid, identification), remark, expiration)
+
+	if err != nil {
+		log.Errorf(core.NewNullContext(), "[database_duplicate_checker.SetSubmissionRemarkWithCustomExpiration] failed to save submission remark, because %s", err.Error())
+	}
+}
+
+// RemoveSubmissionRemark removes the identification and remark in database
+func (c *DatabaseDuplicateChecker) RemoveSubmissionRemark(checkerType DuplicateCheckerType, uid int64, identification string) {
+	err := c.removeRecord(c.getCheckKey(checkerType, uid, identification))
+
+	if err != nil {
+		log.Errorf(core.NewNullContext(), "[database_duplicate_checker.RemoveSubmissionRemark] failed to remove submission remark, because %s", err.Error())
+	}
+}
+
+// GetOrSetCronJobRunningInfo returns the running info when the cron job is running or saves the running info by the current duplicate checker
+func (c *DatabaseDuplicateChecker) GetOrSetCronJobRunningInfo(jobName string, runningInfo string, runningInterval time.Duration) (bool, string) {
+	checkKey := c.getCheckKey(DUPLICATE_CHECKER_TYPE_BACKGROUND_CRON_JOB, 0, jobName)
+	now := time.Now()
+	expiration := runningInterval
+
+	if expiration > 1*time.Second {
+		expiration = expiration - 1*time.Second
+	}
+
+	// the expired running info would block inserting the new one
+	_, err := c.store.Query(core.NewNullContext(), 0).Where("check_key=? AND expired_unix_time<=?", checkKey, now.Unix()).Delete(&models.DuplicateCheckRecord{})
+
+	if err != nil {
+		log.Errorf(core.NewNullContext(), "[database_duplicate_checker.GetOrSetCronJobRunningInfo] failed to remove expired running info of cron job \"%s\", because %s", jobName, err.Error())
+		return true, ""
+	}
+
+	// the primary key guarantees only one instance could insert the running info successfully
+	_, err = c.store.Query(core.NewNullContext(), 0).Insert(&models.DuplicateCheckRecord{
+		CheckKey:        checkKey,
+		Remark:          runningInfo,
+		CreatedUnixTime: now.Unix(),
+		ExpiredUnixTime: now.Add(expiration).Unix(),
+	})
+
+	if err == nil {
+		return false, ""
+	}
+
+	record, getErr := c.getRecord(checkKey)
+
+	if getErr != nil || record == nil {
+		log.Errorf(core.NewNullContext(), "[database_duplicate_checker.GetOrSetCronJobRunningInfo] failed to save running info of cron job \"%s\", because %s", jobName, err.Error())
+		return true, ""
+	}
+
+	return true, record.Remark
+}
+
+// RemoveCronJobRunningInfo removes the running info of the cron job by the current duplicate checker
+func (c *DatabaseDuplicateChecker) RemoveCronJobRunningInfo(jobName string) {
+	err := c.removeRecord(c.getCheckKey(DUPLICATE_CHECKER_TYPE_BACKGROUND_CRON_JOB, 0, jobName))
+
+	if err != nil {
+		log.Errorf(core.NewNullContext(), "[database_duplicate_checker.RemoveCronJobRunningInfo] failed to remove running info of cron job \"%s\", because %s", jobName, err.Error())
+	}
+}
+
+// GetFailureCount returns the failure count of the specified failure key
+func (c *DatabaseDuplicateChecker) GetFailureCount(failureKey string) uint32 {
+	record, err := c.getRecord(c.getCheckKey(DUPLICATE_CHECKER_TYPE_FAILURE_CHECK, 0, failureKey))
+
+	if err != nil {
+		log.Errorf(core.NewNullContext(), "[database_duplicate_checker.GetFailureCount] failed to get failure count, because %s", err.Error())
+		return 0
+	}
+
+	if record == nil {
+		return 0
+	}
+
+	return record.Count
+}
+
+// IncreaseFailureCount increases the failure count of the specified failure key
+func (c *DatabaseDuplicateChecker) IncreaseFailureCount(failureKey string) uint32 {
+	checkKey := c.getCheckKey(DUPLICATE_CHECKER_TYPE_FAILURE_CHECK, 0, failureKey)
+
+	for i := 0; i < 2; i++ {
+		now := time.Now()
+		updatedRows, err := c.store.Query(core.NewNullContext(), 0).Where("check_key=? AND expired_unix_time>?", checkKey, now.Unix()).Incr("count").Update(&models.DuplicateCheckRecord{})
+
+		if err != nil {
+			log.Errorf(core.NewNullContext(), "[database_duplicate_checker.IncreaseFailureCount] failed to increase failure count, because %s", err.Error())
+			return 0
+		}
+
+		if updatedRows > 0 {
+			return c.GetFailureCount(failureKey)
+		}
+
+		_, err = c.store.Query(core.NewNullContext(), 0).Where("check_key=? AND expired_unix_time<=?", checkKey, now.Unix()).Delete(&models.DuplicateCheckRecord{})
+
+		if err != nil {
+			log.Errorf(core.NewNullContext(), "[database_duplicate_checker.IncreaseFailureCount] failed to remove expired failure count, because %s", err.Error())
+			return 0
+		}
+
+		_, err = c.store.Query(core.NewNullContext(), 0).Insert(&models.DuplicateCheckRecord{
+			CheckKey:        checkKey,
+			Count:           1,
+			CreatedUnixTime: now.Unix(),
+			ExpiredUnixTime: now.Add(databaseDuplicateCheckerFailureCountExpiration).Unix(),
+		})
+
+		// another instance may have inserted the same failure key, so try increasing it again
+		if err == nil {
+			return 1
+		}
+	}
+
+	return c.GetFailureCount(failureKey)
+}
+
+// RemoveExpiredRecords removes all expired records in database
+func (c *DatabaseDuplicateChecker) RemoveExpiredRecords() {
+	deletedRows, err := c.store.Query(core.NewNullContext(), 0).Where("expired_unix_time<=?", time.Now().Unix()).Delete(&models.DuplicateCheckRecord{})
+
+	if err != nil {
+		log.Errorf(core.NewNullContext(), "[database_duplicate_checker.RemoveExpiredRecords] failed to remove expired records, because %s", err.Error())
+		return
+	}
+
+	if deletedRows > 0 {
+		log.Debugf(core.NewNullContext(), "[database_duplicate_checker.RemoveExpiredRecords] %d expired records have been removed", deletedRows)
+	}
+}
+
+// Close stops cleaning up expired records periodically
+func (c *DatabaseDuplicateChecker) Close() {
+	if c.cleanupIntervalTicker != nil {
+		c.cleanupIntervalTicker.Stop()
+		c.cleanupIntervalStopper <- true
+	}
+}
+
+func (c *DatabaseDuplicateChecker) getRecord(checkKey string) (*models.DuplicateCheckRecord, error) {
+	record := &models.DuplicateCheckRecord{}
+	has, err := c.store.Query(core.NewNullContext(), 0).Where("check_key=? AND expired_unix_time>?", checkKey, time.Now().Unix()).Get(record)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+
+	return record, nil
+}
+
+func (c *DatabaseDuplicateChecker) setRecord(checkKey string, remark string, expiration time.Duration) error {
+	now := time.Now()
+	record := &models.DuplicateCheckRecord{
+		CheckKey:        checkKey,
+		Remark:          remark,
+		CreatedUnixTime: now.Unix(),
+		ExpiredUnixTime: now.Add(expiration).Unix(),
+	}
+
+	return c.store.DoTransaction(0, core.NewNullContext(), func(sess *xorm.Session) error {
+		_, err := sess.Where("check_key=?", checkKey).Delete(&models.DuplicateCheckRecord{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(record)
+
+		return err
+	})
+}
+
+func (c *DatabaseDuplicateChecker) removeRecord(checkKey string) error {
+	_, err := c.store.Query(core.NewNullContext(), 0).Where("check_key=?", checkKey).Delete(&models.DuplicateCheckRecord{})
+	return err
+}
+
+func (c *DatabaseDuplicateChecker) getCheckKey(checkerType DuplicateCheckerType, uid int64, identification string) string {
+	checkKey := fmt.Sprintf("%d|%d|%s", checkerType, uid, identification)
+
+	if len(checkKey) > models.DuplicateCheckRecordMaxKeyLength {
+		checkKey = fmt.Sprintf("%d|%d|%s", checkerType, uid, utils.MD5EncodeToString([]byte(identification)))
+	}
+
+	return checkKey
+}
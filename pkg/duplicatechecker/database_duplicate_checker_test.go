@@ -0,0 +1,109 @@
+package duplicatechecker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func newTestDatabaseDuplicateChecker(t *testing.T) *DatabaseDuplicateChecker {
+	config := &settings.Config{
+		DatabaseConfig: &settings.DatabaseConfig{
+			DatabaseType:      settings.Sqlite3DbType,
+			DatabasePath:      filepath.Join(t.TempDir(), "ezbookkeeping.db"),
+			MaxOpenConnection: 1,
+		},
+		DuplicateSubmissionsIntervalDuration: 100 * time.Second,
+	}
+
+	err := datastore.InitializeDataStore(config)
+	assert.Nil(t, err)
+
+	err = datastore.Container.TokenStore.SyncStructs(new(models.DuplicateCheckRecord))
+	assert.Nil(t, err)
+
+	checker, err := NewDatabaseDuplicateChecker(config, datastore.Container.TokenStore)
+	assert.Nil(t, err)
+
+	return checker
+}
+
+func TestDatabaseDuplicateChecker_SetAndGetSubmissionRemark(t *testing.T) {
+	checker := newTestDatabaseDuplicateChecker(t)
+
+	uid := int64(1234567890)
+	id := "2345678901"
+	expectedRemark := "0123456789"
+
+	checker.SetSubmissionRemark(DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, id, expectedRemark)
+	found, actualRemark := checker.GetSubmissionRemark(DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, id)
+	assert.Equal(t, true, found)
+	assert.Equal(t, expectedRemark, actualRemark)
+
+	found, actualRemark = checker.GetSubmissionRemark(DUPLICATE_CHECKER_TYPE_NEW_ACCOUNT, uid, id)
+	assert.Equal(t, false, found)
+	assert.Equal(t, "", actualRemark)
+
+	checker.SetSubmissionRemark(DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, id, "3456789012")
+	found, actualRemark = checker.GetSubmissionRemark(DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, id)
+	assert.Equal(t, true, found)
+	assert.Equal(t, "3456789012", actualRemark)
+
+	checker.RemoveSubmissionRemark(DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, id)
+	found, actualRemark = checker.GetSubmissionRemark(DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, id)
+	assert.Equal(t, false, found)
+	assert.Equal(t, "", actualRemark)
+}
+
+func TestDatabaseDuplicateChecker_SetAndGetExpiredSubmissionRemark(t *testing.T) {
+	checker := newTestDatabaseDuplicateChecker(t)
+
+	uid := int64(1234567890)
+	id := "2345678901"
+
+	checker.SetSubmissionRemarkWithCustomExpiration(DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, id, "0123456789", -1*time.Second)
+	found, actualRemark := checker.GetSubmissionRemark(DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, id)
+	assert.Equal(t, false, found)
+	assert.Equal(t, "", actualRemark)
+
+	checker.RemoveExpiredRecords()
+	count, err := datastore.Container.TokenStore.Query(core.NewNullContext(), 0).Count(&models.DuplicateCheckRecord{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestDatabaseDuplicateChecker_GetOrSetCronJobRunningInfo(t *testing.T) {
+	checker := newTestDatabaseDuplicateChecker(t)
+
+	found, runningInfo := checker.GetOrSetCronJobRunningInfo("test-job", "instance-1", 1*time.Minute)
+	assert.Equal(t, false, found)
+	assert.Equal(t, "", runningInfo)
+
+	found, runningInfo = checker.GetOrSetCronJobRunningInfo("test-job", "instance-2", 1*time.Minute)
+	assert.Equal(t, true, found)
+	assert.Equal(t, "instance-1", runningInfo)
+
+	checker.RemoveCronJobRunningInfo("test-job")
+
+	found, runningInfo = checker.GetOrSetCronJobRunningInfo("test-job", "instance-2", 1*time.Minute)
+	assert.Equal(t, false, found)
+	assert.Equal(t, "", runningInfo)
+}
+
+func TestDatabaseDuplicateChecker_IncreaseFailureCount(t *testing.T) {
+	checker := newTestDatabaseDuplicateChecker(t)
+
+	assert.Equal(t, uint32(0), checker.GetFailureCount("127.0.0.1"))
+	assert.Equal(t, uint32(1), checker.IncreaseFailureCount("127.0.0.1"))
+	assert.Equal(t, uint32(2), checker.IncreaseFailureCount("127.0.0.1"))
+	assert.Equal(t, uint32(3), checker.IncreaseFailureCount("127.0.0.1"))
+	assert.Equal(t, uint32(3), checker.GetFailureCount("127.0.0.1"))
+	assert.Equal(t, uint32(0), checker.GetFailureCount("127.0.0.2"))
+}
@@ -3,6 +3,7 @@ package duplicatechecker
 import (
 	"time"
 
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
@@ -23,6 +24,11 @@ func InitializeDuplicateChecker(config *settings.Config) error {
 		checker, err := NewInMemoryDuplicateChecker(config)
 		Container.current = checker
 
+		return err
+	} else if config.DuplicateCheckerType == settings.DatabaseDuplicateCheckerType {
+		checker, err := NewDatabaseDuplicateChecker(config, datastore.Container.TokenStore)
+		Container.current = checker
+
 		return err
 	}
 
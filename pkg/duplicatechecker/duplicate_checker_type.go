@@ -5,16 +5,23 @@ type DuplicateCheckerType uint8
 
 // Types of uuid
 const (
-	DUPLICATE_CHECKER_TYPE_BACKGROUND_CRON_JOB DuplicateCheckerType = 0
-	DUPLICATE_CHECKER_TYPE_NEW_ACCOUNT         DuplicateCheckerType = 1
-	DUPLICATE_CHECKER_TYPE_NEW_SUBACCOUNT      DuplicateCheckerType = 2
-	DUPLICATE_CHECKER_TYPE_NEW_CATEGORY        DuplicateCheckerType = 3
-	DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION     DuplicateCheckerType = 4
-	DUPLICATE_CHECKER_TYPE_NEW_TEMPLATE        DuplicateCheckerType = 5
-	DUPLICATE_CHECKER_TYPE_NEW_PICTURE         DuplicateCheckerType = 6
-	DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS DuplicateCheckerType = 7
-	DUPLICATE_CHECKER_TYPE_OAUTH2_REDIRECT     DuplicateCheckerType = 8
-	DUPLICATE_CHECKER_TYPE_NEW_CUSTOM_ICON     DuplicateCheckerType = 9
-	DUPLICATE_CHECKER_TYPE_2FA_PASSCODE        DuplicateCheckerType = 10
-	DUPLICATE_CHECKER_TYPE_FAILURE_CHECK       DuplicateCheckerType = 255
+	DUPLICATE_CHECKER_TYPE_BACKGROUND_CRON_JOB        DuplicateCheckerType = 0
+	DUPLICATE_CHECKER_TYPE_NEW_ACCOUNT                DuplicateCheckerType = 1
+	DUPLICATE_CHECKER_TYPE_NEW_SUBACCOUNT             DuplicateCheckerType = 2
+	DUPLICATE_CHECKER_TYPE_NEW_CATEGORY               DuplicateCheckerType = 3
+	DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION            DuplicateCheckerType = 4
+	DUPLICATE_CHECKER_TYPE_NEW_TEMPLATE               DuplicateCheckerType = 5
+	DUPLICATE_CHECKER_TYPE_NEW_PICTURE                DuplicateCheckerType = 6
+	DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS        DuplicateCheckerType = 7
+	DUPLICATE_CHECKER_TYPE_OAUTH2_REDIRECT            DuplicateCheckerType = 8
+	DUPLICATE_CHECKER_TYPE_NEW_CUSTOM_ICON            DuplicateCheckerType = 9
+	DUPLICATE_CHECKER_TYPE_2FA_PASSCODE               DuplicateCheckerType = 10
+	DUPLICATE_CHECKER_TYPE_NEW_BUDGET                 DuplicateCheckerType = 11
+	DUPLICATE_CHECKER_TYPE_NEW_SECURITY               DuplicateCheckerType = 12
+	DUPLICATE_CHECKER_TYPE_NEW_INVESTMENT_TRANSACTION DuplicateCheckerType = 13
+	DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION_RULE       DuplicateCheckerType = 14
+	DUPLICATE_CHECKER_TYPE_PASSKEY_CEREMONY           DuplicateCheckerType = 15
+	DUPLICATE_CHECKER_TYPE_SAML_REDIRECT              DuplicateCheckerType = 16
+	DUPLICATE_CHECKER_TYPE_MCP_SESSION                DuplicateCheckerType = 17
+	DUPLICATE_CHECKER_TYPE_FAILURE_CHECK              DuplicateCheckerType = 255
 )
@@ -0,0 +1,13 @@
+package errs
+
+import (
+	"net/http"
+)
+
+// Error codes related to administrator
+var (
+	ErrNotAdministrator                = NewNormalError(NormalSubcategoryAdministrator, 0, http.StatusForbidden, "current user is not administrator")
+	ErrCannotOperateOnCurrentUser      = NewNormalError(NormalSubcategoryAdministrator, 1, http.StatusBadRequest, "cannot perform this operation on current user")
+	ErrUserRoleInvalid                 = NewNormalError(NormalSubcategoryAdministrator, 2, http.StatusBadRequest, "user role is invalid")
+	ErrUserFeatureRestrictionTypeEmpty = NewNormalError(NormalSubcategoryAdministrator, 3, http.StatusBadRequest, "user feature restriction types are empty")
+)
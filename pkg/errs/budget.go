@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to budgets
+var (
+	ErrBudgetIdInvalid              = NewNormalError(NormalSubcategoryBudget, 0, http.StatusBadRequest, "budget id is invalid")
+	ErrBudgetNotFound               = NewNormalError(NormalSubcategoryBudget, 1, http.StatusBadRequest, "budget not found")
+	ErrBudgetPeriodTypeInvalid      = NewNormalError(NormalSubcategoryBudget, 2, http.StatusBadRequest, "budget period type is invalid")
+	ErrBudgetTargetTypeInvalid      = NewNormalError(NormalSubcategoryBudget, 3, http.StatusBadRequest, "budget target type is invalid")
+	ErrBudgetCategoryNotExpense     = NewNormalError(NormalSubcategoryBudget, 4, http.StatusBadRequest, "budget category must be expense category")
+	ErrBudgetLimitAmountInvalid     = NewNormalError(NormalSubcategoryBudget, 5, http.StatusBadRequest, "budget limit amount is invalid")
+	ErrBudgetCurrencyMismatch       = NewNormalError(NormalSubcategoryBudget, 6, http.StatusBadRequest, "budget currency must be the same as account currency")
+	ErrBudgetAlertThresholdsInvalid = NewNormalError(NormalSubcategoryBudget, 7, http.StatusBadRequest, "budget alert thresholds are invalid")
+)
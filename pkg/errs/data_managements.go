@@ -6,7 +6,11 @@ import (
 
 // Error codes related to data management
 var (
-	ErrDataExportNotAllowed     = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
-	ErrDataImportNotAllowed     = NewNormalError(NormalSubcategoryDataManagement, 2, http.StatusBadRequest, "data import not allowed")
-	ErrImportTooManyTransaction = NewNormalError(NormalSubcategoryDataManagement, 3, http.StatusBadRequest, "import too many transactions")
+	ErrDataExportNotAllowed      = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
+	ErrDataImportNotAllowed      = NewNormalError(NormalSubcategoryDataManagement, 2, http.StatusBadRequest, "data import not allowed")
+	ErrImportTooManyTransaction  = NewNormalError(NormalSubcategoryDataManagement, 3, http.StatusBadRequest, "import too many transactions")
+	ErrBackupFileInvalid         = NewNormalError(NormalSubcategoryDataManagement, 4, http.StatusBadRequest, "backup file is invalid")
+	ErrBackupVersionNotSupported = NewNormalError(NormalSubcategoryDataManagement, 5, http.StatusBadRequest, "backup file version is not supported")
+	ErrRestoreUserDataNotEmpty   = NewNormalError(NormalSubcategoryDataManagement, 6, http.StatusBadRequest, "user data must be empty before restoring")
+	ErrBackupFileEmpty           = NewNormalError(NormalSubcategoryDataManagement, 7, http.StatusBadRequest, "backup file is empty")
 )
@@ -46,6 +46,17 @@ const (
 	NormalSubcategoryInsightsExplorer       = 18
 	NormalSubcategoryTagGroup               = 19
 	NormalSubcategoryUserCustomIcon         = 20
+	NormalSubcategoryBudget                 = 21
+	NormalSubcategoryInvestment             = 22
+	NormalSubcategoryLoan                   = 23
+	NormalSubcategoryTransactionRule        = 24
+	NormalSubcategoryExchangeRateHistory    = 25
+	NormalSubcategoryWebhook                = 26
+	NormalSubcategoryLedger                 = 27
+	NormalSubcategoryPasskey                = 28
+	NormalSubcategoryLDAP                   = 29
+	NormalSubcategorySAML                   = 30
+	NormalSubcategoryAdministrator          = 31
 )
 
 // Error represents the specific error returned to user
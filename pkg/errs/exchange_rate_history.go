@@ -0,0 +1,11 @@
+package errs
+
+import "net/http"
+
+// Error codes related to exchange rate histories
+var (
+	ErrExchangeRateHistoryDateInvalid               = NewNormalError(NormalSubcategoryExchangeRateHistory, 0, http.StatusBadRequest, "exchange rate history date is invalid")
+	ErrExchangeRateHistoryDateRangeInvalid          = NewNormalError(NormalSubcategoryExchangeRateHistory, 1, http.StatusBadRequest, "exchange rate history date range is invalid")
+	ErrExchangeRateHistoryNotFound                  = NewNormalError(NormalSubcategoryExchangeRateHistory, 2, http.StatusBadRequest, "exchange rate history data not found")
+	ErrExchangeRatesDataSourceNotSupportHistoryData = NewNormalError(NormalSubcategoryExchangeRateHistory, 3, http.StatusBadRequest, "current exchange rates data source does not support historical data")
+)
@@ -0,0 +1,20 @@
+package errs
+
+import "net/http"
+
+// Error codes related to investments
+var (
+	ErrSecurityIdInvalid                         = NewNormalError(NormalSubcategoryInvestment, 0, http.StatusBadRequest, "security id is invalid")
+	ErrSecurityNotFound                          = NewNormalError(NormalSubcategoryInvestment, 1, http.StatusBadRequest, "security not found")
+	ErrSecurityAccountNotInvestmentAccount       = NewNormalError(NormalSubcategoryInvestment, 2, http.StatusBadRequest, "security account must be investment account")
+	ErrSecuritySymbolAlreadyExists               = NewNormalError(NormalSubcategoryInvestment, 3, http.StatusBadRequest, "security symbol already exists")
+	ErrInvestmentTransactionIdInvalid            = NewNormalError(NormalSubcategoryInvestment, 4, http.StatusBadRequest, "investment transaction id is invalid")
+	ErrInvestmentTransactionNotFound             = NewNormalError(NormalSubcategoryInvestment, 5, http.StatusBadRequest, "investment transaction not found")
+	ErrInvestmentTransactionTypeInvalid          = NewNormalError(NormalSubcategoryInvestment, 6, http.StatusBadRequest, "investment transaction type is invalid")
+	ErrInvestmentTransactionQuantityInvalid      = NewNormalError(NormalSubcategoryInvestment, 7, http.StatusBadRequest, "investment transaction quantity is invalid")
+	ErrInvestmentSellQuantityExceedsHolding      = NewNormalError(NormalSubcategoryInvestment, 8, http.StatusBadRequest, "sell quantity exceeds holding quantity")
+	ErrSecurityPriceIdInvalid                    = NewNormalError(NormalSubcategoryInvestment, 9, http.StatusBadRequest, "security price id is invalid")
+	ErrSecurityPriceNotFound                     = NewNormalError(NormalSubcategoryInvestment, 10, http.StatusBadRequest, "security price not found")
+	ErrSecurityPricesDataSourceNotEnabled        = NewNormalError(NormalSubcategoryInvestment, 11, http.StatusBadRequest, "security prices data source is not enabled")
+	ErrSecurityPricesDataSourceNoAvailablePrices = NewNormalError(NormalSubcategoryInvestment, 12, http.StatusBadRequest, "no available security prices from data source")
+)
@@ -0,0 +1,15 @@
+package errs
+
+import (
+	"net/http"
+)
+
+// Error codes related to ldap
+var (
+	ErrLDAPNotEnabled                   = NewNormalError(NormalSubcategoryLDAP, 0, http.StatusBadRequest, "ldap not enabled")
+	ErrLDAPAutoRegistrationNotEnabled   = NewNormalError(NormalSubcategoryLDAP, 1, http.StatusBadRequest, "ldap auto registration not enabled")
+	ErrLDAPServerUnavailable            = NewNormalError(NormalSubcategoryLDAP, 2, http.StatusBadRequest, "cannot connect to ldap server")
+	ErrLDAPUserNameEmpty                = NewNormalError(NormalSubcategoryLDAP, 3, http.StatusBadRequest, "user name from ldap server is empty")
+	ErrLDAPEmailEmpty                   = NewNormalError(NormalSubcategoryLDAP, 4, http.StatusBadRequest, "email from ldap server is empty")
+	ErrLDAPUserAlreadyExistsButNotBound = NewNormalError(NormalSubcategoryLDAP, 5, http.StatusBadRequest, "user with the same user name already exists and is not bound to ldap user")
+)
@@ -0,0 +1,20 @@
+package errs
+
+import "net/http"
+
+// Error codes related to shared ledgers
+var (
+	ErrLedgerIdInvalid                         = NewNormalError(NormalSubcategoryLedger, 0, http.StatusBadRequest, "ledger id is invalid")
+	ErrLedgerNotFound                          = NewNormalError(NormalSubcategoryLedger, 1, http.StatusBadRequest, "ledger not found")
+	ErrLedgerAlreadyExists                     = NewNormalError(NormalSubcategoryLedger, 2, http.StatusBadRequest, "ledger already exists")
+	ErrLedgerMemberIdInvalid                   = NewNormalError(NormalSubcategoryLedger, 3, http.StatusBadRequest, "ledger member id is invalid")
+	ErrLedgerMemberNotFound                    = NewNormalError(NormalSubcategoryLedger, 4, http.StatusBadRequest, "ledger member not found")
+	ErrLedgerMemberRoleInvalid                 = NewNormalError(NormalSubcategoryLedger, 5, http.StatusBadRequest, "ledger member role is invalid")
+	ErrLedgerMemberAlreadyExists               = NewNormalError(NormalSubcategoryLedger, 6, http.StatusBadRequest, "ledger member already exists")
+	ErrCannotInviteSelfToLedger                = NewNormalError(NormalSubcategoryLedger, 7, http.StatusBadRequest, "cannot invite yourself to ledger")
+	ErrCannotModifyLedgerCreator               = NewNormalError(NormalSubcategoryLedger, 8, http.StatusBadRequest, "cannot modify or remove the creator of ledger")
+	ErrLedgerOperationNotPermitted             = NewNormalError(NormalSubcategoryLedger, 9, http.StatusForbidden, "operation is not permitted in ledger")
+	ErrLedgerInvitationTokenIsInvalidOrExpired = NewNormalError(NormalSubcategoryLedger, 10, http.StatusBadRequest, "ledger invitation token is invalid or expired")
+	ErrLedgerInvitationNotFound                = NewNormalError(NormalSubcategoryLedger, 11, http.StatusBadRequest, "ledger invitation not found")
+	ErrLedgerMemberCountExceedsLimit           = NewNormalError(NormalSubcategoryLedger, 12, http.StatusBadRequest, "ledger member count exceeds limit")
+)
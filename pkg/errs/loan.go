@@ -0,0 +1,14 @@
+package errs
+
+import "net/http"
+
+// Error codes related to loans
+var (
+	ErrLoanNotFound                       = NewNormalError(NormalSubcategoryLoan, 0, http.StatusBadRequest, "loan not found")
+	ErrLoanAccountNotDebtAccount          = NewNormalError(NormalSubcategoryLoan, 1, http.StatusBadRequest, "loan account must be debt account")
+	ErrLoanStartDateInvalid               = NewNormalError(NormalSubcategoryLoan, 2, http.StatusBadRequest, "loan start date is invalid")
+	ErrLoanPaymentAccountInvalid          = NewNormalError(NormalSubcategoryLoan, 3, http.StatusBadRequest, "loan payment account is invalid")
+	ErrLoanPaymentAccountCurrencyNotMatch = NewNormalError(NormalSubcategoryLoan, 4, http.StatusBadRequest, "loan payment account currency must be the same as loan account")
+	ErrLoanPrincipalCategoryInvalid       = NewNormalError(NormalSubcategoryLoan, 5, http.StatusBadRequest, "loan principal category must be transfer category")
+	ErrLoanInterestCategoryInvalid        = NewNormalError(NormalSubcategoryLoan, 6, http.StatusBadRequest, "loan interest category must be expense category")
+)
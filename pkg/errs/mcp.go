@@ -4,5 +4,11 @@ import "net/http"
 
 // Error codes related to model context protocol server
 var (
-	ErrMCPServerNotEnabled = NewNormalError(NormalSubcategoryModelContextProtocol, 0, http.StatusBadRequest, "mcp server is not enabled")
+	ErrMCPServerNotEnabled       = NewNormalError(NormalSubcategoryModelContextProtocol, 0, http.StatusBadRequest, "mcp server is not enabled")
+	ErrMCPTokenScopeInvalid      = NewNormalError(NormalSubcategoryModelContextProtocol, 1, http.StatusBadRequest, "mcp token scope is invalid")
+	ErrMCPTokenScopeNotPermitted = NewNormalError(NormalSubcategoryModelContextProtocol, 2, http.StatusForbidden, "current token does not have the scope to perform this action")
+	ErrMCPResourceNotFound       = NewNormalError(NormalSubcategoryModelContextProtocol, 3, http.StatusNotFound, "mcp resource not found")
+	ErrMCPPromptNotFound         = NewNormalError(NormalSubcategoryModelContextProtocol, 4, http.StatusNotFound, "mcp prompt not found")
+	ErrMCPSessionNotFound        = NewNormalError(NormalSubcategoryModelContextProtocol, 5, http.StatusNotFound, "mcp session not found")
+	ErrMCPSessionIdIsEmpty       = NewNormalError(NormalSubcategoryModelContextProtocol, 6, http.StatusBadRequest, "mcp session id is empty")
 )
@@ -0,0 +1,16 @@
+package errs
+
+import "net/http"
+
+// Error codes related to passkeys
+var (
+	ErrPasskeyNotEnabled         = NewNormalError(NormalSubcategoryPasskey, 0, http.StatusBadRequest, "passkey is not enabled")
+	ErrPasskeyIdInvalid          = NewNormalError(NormalSubcategoryPasskey, 1, http.StatusBadRequest, "passkey id is invalid")
+	ErrPasskeyNotFound           = NewNormalError(NormalSubcategoryPasskey, 2, http.StatusBadRequest, "passkey not found")
+	ErrPasskeyNameInvalid        = NewNormalError(NormalSubcategoryPasskey, 3, http.StatusBadRequest, "passkey name is invalid")
+	ErrPasskeyAlreadyRegistered  = NewNormalError(NormalSubcategoryPasskey, 4, http.StatusBadRequest, "passkey has already been registered")
+	ErrPasskeyCredentialInvalid  = NewNormalError(NormalSubcategoryPasskey, 5, http.StatusBadRequest, "passkey credential is invalid")
+	ErrPasskeyCeremonyExpired    = NewNormalError(NormalSubcategoryPasskey, 6, http.StatusBadRequest, "passkey request has expired")
+	ErrPasskeyVerificationFailed = NewNormalError(NormalSubcategoryPasskey, 7, http.StatusUnauthorized, "passkey verification failed")
+	ErrPasskeyRelyingPartyNotSet = NewNormalError(NormalSubcategoryPasskey, 8, http.StatusInternalServerError, "passkey relying party is not set")
+)
@@ -0,0 +1,20 @@
+package errs
+
+import (
+	"net/http"
+)
+
+// Error codes related to saml 2.0
+var (
+	ErrSAMLNotEnabled                    = NewNormalError(NormalSubcategorySAML, 0, http.StatusBadRequest, "saml not enabled")
+	ErrSAMLAutoRegistrationNotEnabled    = NewNormalError(NormalSubcategorySAML, 1, http.StatusBadRequest, "saml auto registration not enabled")
+	ErrInvalidSAMLLoginRequest           = NewNormalError(NormalSubcategorySAML, 2, http.StatusBadRequest, "invalid saml login request")
+	ErrMissingSAMLResponse               = NewNormalError(NormalSubcategorySAML, 3, http.StatusBadRequest, "missing saml response")
+	ErrInvalidSAMLRelayState             = NewNormalError(NormalSubcategorySAML, 4, http.StatusBadRequest, "invalid relay state in saml response")
+	ErrInvalidSAMLResponse               = NewNormalError(NormalSubcategorySAML, 5, http.StatusBadRequest, "invalid saml response")
+	ErrSAMLUserAlreadyBoundToAnotherUser = NewNormalError(NormalSubcategorySAML, 6, http.StatusBadRequest, "saml user already bound to another user")
+	ErrSAMLUserNameEmpty                 = NewNormalError(NormalSubcategorySAML, 7, http.StatusBadRequest, "user name from saml identity provider is empty")
+	ErrSAMLEmailEmpty                    = NewNormalError(NormalSubcategorySAML, 8, http.StatusBadRequest, "email from saml identity provider is empty")
+	ErrSAMLUserNameEmptyCannotRegister   = NewNormalError(NormalSubcategorySAML, 9, http.StatusBadRequest, "user name from saml identity provider is empty, cannot register new user")
+	ErrSAMLEmailEmptyCannotRegister      = NewNormalError(NormalSubcategorySAML, 10, http.StatusBadRequest, "email from saml identity provider is empty, cannot register new user")
+)
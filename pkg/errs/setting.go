@@ -31,4 +31,12 @@ var (
 	ErrInvalidOAuth2Provider                          = NewSystemError(SystemSubcategorySetting, 24, http.StatusInternalServerError, "invalid oauth 2.0 provider")
 	ErrInvalidOAuth2StateExpiredTime                  = NewSystemError(SystemSubcategorySetting, 25, http.StatusInternalServerError, "invalid oauth 2.0 state expired time")
 	ErrInvalidLLMThinkingLevel                        = NewSystemError(SystemSubcategorySetting, 26, http.StatusInternalServerError, "invalid llm thinking level")
+	ErrInvalidSecurityPricesDataSource                = NewSystemError(SystemSubcategorySetting, 27, http.StatusInternalServerError, "invalid security prices data source")
+	ErrInvalidMCPSessionExpiredTime                   = NewSystemError(SystemSubcategorySetting, 28, http.StatusInternalServerError, "invalid mcp session expired time")
+	ErrInvalidLedgerInvitationTokenExpiredTime        = NewSystemError(SystemSubcategorySetting, 29, http.StatusInternalServerError, "invalid ledger invitation token expired time")
+	ErrInvalidDatabaseDuplicateCheckerCleanupInterval = NewSystemError(SystemSubcategorySetting, 30, http.StatusInternalServerError, "invalid database duplicate checker cleanup interval")
+	ErrInvalidLDAPConfig                              = NewSystemError(SystemSubcategorySetting, 31, http.StatusInternalServerError, "invalid ldap config")
+	ErrInvalidSAMLConfig                              = NewSystemError(SystemSubcategorySetting, 32, http.StatusInternalServerError, "invalid saml config")
+	ErrInvalidSAMLUserIdentifier                      = NewSystemError(SystemSubcategorySetting, 33, http.StatusInternalServerError, "invalid saml user identifier")
+	ErrInvalidSAMLStateExpiredTime                    = NewSystemError(SystemSubcategorySetting, 34, http.StatusInternalServerError, "invalid saml state expired time")
 )
@@ -51,4 +51,9 @@ var (
 	ErrTransactionTimeZoneInvalid                                  = NewNormalError(NormalSubcategoryTransaction, 44, http.StatusBadRequest, "transaction time zone is invalid")
 	ErrAmountInvalid                                               = NewNormalError(NormalSubcategoryTransaction, 45, http.StatusBadRequest, "transaction amount is invalid")
 	ErrGeographicLocationInvalid                                   = NewNormalError(NormalSubcategoryTransaction, 46, http.StatusBadRequest, "geographic location is invalid")
+	ErrTransactionSplitNotAllowed                                  = NewNormalError(NormalSubcategoryTransaction, 47, http.StatusBadRequest, "transaction split is only allowed for income or expense transaction")
+	ErrTransactionSplitsTooFew                                     = NewNormalError(NormalSubcategoryTransaction, 48, http.StatusBadRequest, "transaction must have at least two splits")
+	ErrTransactionHasTooManySplits                                 = NewNormalError(NormalSubcategoryTransaction, 49, http.StatusBadRequest, "transaction has too many splits")
+	ErrTransactionSplitAmountsNotEqual                             = NewNormalError(NormalSubcategoryTransaction, 50, http.StatusBadRequest, "transaction split amounts not equal to transaction amount")
+	ErrTransactionSplitsRequiredWhenAmountChanged                  = NewNormalError(NormalSubcategoryTransaction, 51, http.StatusBadRequest, "transaction splits are required when amount of split transaction changed")
 )
@@ -0,0 +1,19 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction rules
+var (
+	ErrTransactionRuleIdInvalid                  = NewNormalError(NormalSubcategoryTransactionRule, 0, http.StatusBadRequest, "transaction rule id is invalid")
+	ErrTransactionRuleNotFound                   = NewNormalError(NormalSubcategoryTransactionRule, 1, http.StatusBadRequest, "transaction rule not found")
+	ErrTransactionRuleTransactionTypeInvalid     = NewNormalError(NormalSubcategoryTransactionRule, 2, http.StatusBadRequest, "transaction rule transaction type is invalid")
+	ErrTransactionRuleDescriptionPatternInvalid  = NewNormalError(NormalSubcategoryTransactionRule, 3, http.StatusBadRequest, "transaction rule description pattern is invalid")
+	ErrTransactionRuleCounterpartyPatternInvalid = NewNormalError(NormalSubcategoryTransactionRule, 4, http.StatusBadRequest, "transaction rule counterparty pattern is invalid")
+	ErrTransactionRuleAmountRangeInvalid         = NewNormalError(NormalSubcategoryTransactionRule, 5, http.StatusBadRequest, "transaction rule amount range is invalid")
+	ErrTransactionRuleHasNoCondition             = NewNormalError(NormalSubcategoryTransactionRule, 6, http.StatusBadRequest, "transaction rule must have at least one condition")
+	ErrTransactionRuleHasNoAction                = NewNormalError(NormalSubcategoryTransactionRule, 7, http.StatusBadRequest, "transaction rule must have at least one action")
+	ErrTransactionRuleCategoryInvalid            = NewNormalError(NormalSubcategoryTransactionRule, 8, http.StatusBadRequest, "transaction rule category is invalid")
+	ErrTransactionRuleDestinationAccountInvalid  = NewNormalError(NormalSubcategoryTransactionRule, 9, http.StatusBadRequest, "transaction rule destination account is invalid")
+	ErrTransactionRuleHasTooManyTags             = NewNormalError(NormalSubcategoryTransactionRule, 10, http.StatusBadRequest, "transaction rule has too many tags")
+	ErrTransactionRuleTagInvalid                 = NewNormalError(NormalSubcategoryTransactionRule, 11, http.StatusBadRequest, "transaction rule tag is invalid")
+)
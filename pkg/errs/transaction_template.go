@@ -12,4 +12,12 @@ var (
 	ErrTransactionTemplateHasTooManyTags                     = NewNormalError(NormalSubcategoryTemplate, 5, http.StatusBadRequest, "transaction template has too many tags")
 	ErrScheduledTransactionTemplateStartDataLaterThanEndDate = NewNormalError(NormalSubcategoryTemplate, 6, http.StatusBadRequest, "scheduled transaction start date is later than end time")
 	ErrScheduledTransactionStartDateRequired                 = NewNormalError(NormalSubcategoryTemplate, 7, http.StatusBadRequest, "scheduled transaction start date is required")
+	ErrScheduledTransactionAdjustmentPolicyInvalid           = NewNormalError(NormalSubcategoryTemplate, 8, http.StatusBadRequest, "scheduled transaction adjustment policy is invalid")
+	ErrScheduledTransactionHolidayInvalid                    = NewNormalError(NormalSubcategoryTemplate, 9, http.StatusBadRequest, "scheduled transaction holiday is invalid")
+	ErrScheduledTransactionExceptionIdInvalid                = NewNormalError(NormalSubcategoryTemplate, 10, http.StatusBadRequest, "scheduled transaction exception id is invalid")
+	ErrScheduledTransactionExceptionNotFound                 = NewNormalError(NormalSubcategoryTemplate, 11, http.StatusBadRequest, "scheduled transaction exception not found")
+	ErrScheduledTransactionExceptionTypeInvalid              = NewNormalError(NormalSubcategoryTemplate, 12, http.StatusBadRequest, "scheduled transaction exception type is invalid")
+	ErrScheduledTransactionOccurrenceNotFound                = NewNormalError(NormalSubcategoryTemplate, 13, http.StatusBadRequest, "scheduled transaction occurrence not found")
+	ErrScheduledTransactionOccurrenceAlreadyHasException     = NewNormalError(NormalSubcategoryTemplate, 14, http.StatusBadRequest, "scheduled transaction occurrence already has exception")
+	ErrScheduledTransactionPostponedDateInvalid              = NewNormalError(NormalSubcategoryTemplate, 15, http.StatusBadRequest, "scheduled transaction postponed date is invalid")
 )
@@ -0,0 +1,16 @@
+package errs
+
+import "net/http"
+
+// Error codes related to webhooks
+var (
+	ErrWebhookNotEnabled        = NewNormalError(NormalSubcategoryWebhook, 0, http.StatusBadRequest, "webhook is not enabled")
+	ErrWebhookIdInvalid         = NewNormalError(NormalSubcategoryWebhook, 1, http.StatusBadRequest, "webhook id is invalid")
+	ErrWebhookNotFound          = NewNormalError(NormalSubcategoryWebhook, 2, http.StatusBadRequest, "webhook not found")
+	ErrWebhookUrlInvalid        = NewNormalError(NormalSubcategoryWebhook, 3, http.StatusBadRequest, "webhook url is invalid")
+	ErrWebhookEventTypeInvalid  = NewNormalError(NormalSubcategoryWebhook, 4, http.StatusBadRequest, "webhook event type is invalid")
+	ErrWebhookDeliveryIdInvalid = NewNormalError(NormalSubcategoryWebhook, 5, http.StatusBadRequest, "webhook delivery id is invalid")
+	ErrWebhookDeliveryNotFound  = NewNormalError(NormalSubcategoryWebhook, 6, http.StatusBadRequest, "webhook delivery not found")
+	ErrWebhookCountExceedsLimit = NewNormalError(NormalSubcategoryWebhook, 7, http.StatusBadRequest, "webhook count exceeds limit")
+	ErrWebhookUrlNotAllowed     = NewNormalError(NormalSubcategoryWebhook, 8, http.StatusBadRequest, "webhook url is not allowed")
+)
@@ -16,12 +16,14 @@ import (
 )
 
 const bankOfCanadaExchangeRateUrl = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?recent=1"
+const bankOfCanadaHistoricalExchangeRateUrl = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json"
 const bankOfCanadaExchangeRateReferenceUrl = "https://www.bankofcanada.ca/rates/exchange/daily-exchange-rates/"
 const bankOfCanadaDataSource = "Bank of Canada"
 const bankOfCanadaBaseCurrency = "CAD"
 
 const bankOfCanadaDataUpdateDateFormat = "2006-01-02 15:04"
 const bankOfCanadaDataUpdateDateTimezone = "America/Toronto"
+const bankOfCanadaHistoricalRequestDateFormat = "2006-01-02"
 
 // BankOfCanadaDataSource defines the structure of exchange rates data source of bank of Canada
 type BankOfCanadaDataSource struct {
@@ -130,6 +132,32 @@ func (e *BankOfCanadaExchangeRateData) ToLatestExchangeRateResponse(c core.Conte
 	return latestExchangeRateResp
 }
 
+// ToHistoricalExchangeRateResponses returns the view-objects of every published day according to original data from bank of Canada
+func (e *BankOfCanadaExchangeRateData) ToHistoricalExchangeRateResponses(c core.Context) []*models.LatestExchangeRateResponse {
+	if len(e.Observations) < 1 {
+		log.Errorf(c, "[bank_of_canada_datasource.ToHistoricalExchangeRateResponses] observations is empty")
+		return nil
+	}
+
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(e.Observations))
+
+	for i := 0; i < len(e.Observations); i++ {
+		dailyData := &BankOfCanadaExchangeRateData{
+			Observations: []BankOfCanadaObservationData{e.Observations[i]},
+		}
+
+		exchangeRateResp := dailyData.ToLatestExchangeRateResponse(c)
+
+		if exchangeRateResp == nil {
+			return nil
+		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	return exchangeRateResps
+}
+
 // BuildRequests returns the bank of Canada exchange rates http requests
 func (e *BankOfCanadaDataSource) BuildRequests() ([]*http.Request, error) {
 	req, err := http.NewRequest("GET", bankOfCanadaExchangeRateUrl, nil)
@@ -160,3 +188,39 @@ func (e *BankOfCanadaDataSource) Parse(c core.Context, content []byte) (*models.
 
 	return latestExchangeRateResponse, nil
 }
+
+// BuildHistoricalRequests returns the bank of Canada historical exchange rates http requests
+func (e *BankOfCanadaDataSource) BuildHistoricalRequests(startTime time.Time, endTime time.Time) ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", bankOfCanadaHistoricalExchangeRateUrl, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := req.URL.Query()
+	query.Set("start_date", startTime.Format(bankOfCanadaHistoricalRequestDateFormat))
+	query.Set("end_date", endTime.Format(bankOfCanadaHistoricalRequestDateFormat))
+	req.URL.RawQuery = query.Encode()
+
+	return []*http.Request{req}, nil
+}
+
+// ParseHistorical returns the common response entities of every published day according to the bank of Canada data source raw response
+func (e *BankOfCanadaDataSource) ParseHistorical(c core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	bankOfCanadaData := &BankOfCanadaExchangeRateData{}
+	err := json.Unmarshal(content, bankOfCanadaData)
+
+	if err != nil {
+		log.Errorf(c, "[bank_of_canada_datasource.ParseHistorical] failed to parse json data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	historicalExchangeRateResponses := bankOfCanadaData.ToHistoricalExchangeRateResponses(c)
+
+	if historicalExchangeRateResponses == nil {
+		log.Errorf(c, "[bank_of_canada_datasource.ParseHistorical] failed to parse historical exchange rate data")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
@@ -195,3 +195,16 @@ func TestBankOfCanadaDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestBankOfCanadaDataSource_ParseHistorical(t *testing.T) {
+	dataSource := &BankOfCanadaDataSource{}
+	context := core.NewNullContext()
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte(bankOfCanadaMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualHistoricalExchangeRateResponses, 2)
+	assert.Equal(t, int64(1577827800), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, "VND", actualHistoricalExchangeRateResponses[0].ExchangeRates[0].Currency)
+	assert.Equal(t, int64(1617309000), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Len(t, actualHistoricalExchangeRateResponses[1].ExchangeRates, 2)
+}
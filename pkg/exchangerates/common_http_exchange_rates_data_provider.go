@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -11,6 +12,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // HttpExchangeRatesDataSource defines the structure of http exchange rates data source
@@ -22,6 +24,17 @@ type HttpExchangeRatesDataSource interface {
 	Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error)
 }
 
+// HistoricalHttpExchangeRatesDataSource defines the structure of http exchange rates data source which publishes historical exchange rates
+type HistoricalHttpExchangeRatesDataSource interface {
+	HttpExchangeRatesDataSource
+
+	// BuildHistoricalRequests returns the http requests of historical exchange rates between the start date and the end date
+	BuildHistoricalRequests(startTime time.Time, endTime time.Time) ([]*http.Request, error)
+
+	// ParseHistorical returns the common response entities of every published day according to the data source raw response
+	ParseHistorical(c core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error)
+}
+
 // CommonHttpExchangeRatesDataProvider defines the structure of common http exchange rates data provider
 type CommonHttpExchangeRatesDataProvider struct {
 	ExchangeRatesDataProvider
@@ -105,6 +118,116 @@ func (e *CommonHttpExchangeRatesDataProvider) GetLatestExchangeRates(c core.Cont
 	return finalExchangeRateResponse, nil
 }
 
+// SupportsHistoricalExchangeRates returns whether the current http data source publishes historical exchange rates
+func (e *CommonHttpExchangeRatesDataProvider) SupportsHistoricalExchangeRates() bool {
+	_, ok := e.dataSource.(HistoricalHttpExchangeRatesDataSource)
+	return ok
+}
+
+// GetHistoricalExchangeRates returns the historical exchange rates of every published day between the start date and the end date
+func (e *CommonHttpExchangeRatesDataProvider) GetHistoricalExchangeRates(c core.Context, startTime time.Time, endTime time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	historicalDataSource, ok := e.dataSource.(HistoricalHttpExchangeRatesDataSource)
+
+	if !ok {
+		return nil, errs.ErrExchangeRatesDataSourceNotSupportHistoryData
+	}
+
+	requests, err := historicalDataSource.BuildHistoricalRequests(startTime, endTime)
+
+	if err != nil {
+		log.Errorf(c, "[common_http_exchange_rates_data_provider.GetHistoricalExchangeRates] failed to build requests, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	startDate := utils.FormatUnixTimeToNumericYearMonthDay(startTime.Unix(), time.UTC)
+	endDate := utils.FormatUnixTimeToNumericYearMonthDay(endTime.Unix(), time.UTC)
+	dailyExchangeRateResps := make(map[int32]*models.LatestExchangeRateResponse)
+	dailyExchangeRatesMaps := make(map[int32]map[string]string)
+
+	for i := 0; i < len(requests); i++ {
+		req := requests[i]
+		req = req.WithContext(httpclient.CustomHttpResponseLog(c, func(data []byte) {
+			log.Debugf(c, "[common_http_exchange_rates_data_provider.GetHistoricalExchangeRates] response#%d is %s", i, data)
+		}))
+
+		resp, err := e.httpClient.Do(req)
+
+		if err != nil {
+			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetHistoricalExchangeRates] failed to request historical exchange rate data, because %s", err.Error())
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+
+		if resp.StatusCode != 200 {
+			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetHistoricalExchangeRates] failed to get historical exchange rate data response, because response code is %d", resp.StatusCode)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		exchangeRateResps, err := historicalDataSource.ParseHistorical(c, body)
+
+		if err != nil {
+			log.Errorf(c, "[common_http_exchange_rates_data_provider.GetHistoricalExchangeRates] failed to parse response, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
+		}
+
+		for j := 0; j < len(exchangeRateResps); j++ {
+			exchangeRateResp := exchangeRateResps[j]
+			rateDate := utils.FormatUnixTimeToNumericYearMonthDay(exchangeRateResp.UpdateTime, time.UTC)
+
+			if rateDate < startDate || rateDate > endDate {
+				continue
+			}
+
+			exchangeRatesMap, exists := dailyExchangeRatesMaps[rateDate]
+
+			if !exists {
+				exchangeRatesMap = make(map[string]string)
+				dailyExchangeRatesMaps[rateDate] = exchangeRatesMap
+			}
+
+			for k := 0; k < len(exchangeRateResp.ExchangeRates); k++ {
+				exchangeRate := exchangeRateResp.ExchangeRates[k]
+				exchangeRatesMap[exchangeRate.Currency] = exchangeRate.Rate
+			}
+
+			exchangeRatesMap[exchangeRateResp.BaseCurrency] = "1"
+			dailyExchangeRateResps[rateDate] = exchangeRateResp
+		}
+	}
+
+	finalExchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(dailyExchangeRateResps))
+
+	for rateDate, exchangeRateResp := range dailyExchangeRateResps {
+		exchangeRatesMap := dailyExchangeRatesMaps[rateDate]
+		allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(exchangeRatesMap))
+
+		for currency, rate := range exchangeRatesMap {
+			allExchangeRates = append(allExchangeRates, &models.LatestExchangeRate{
+				Currency: currency,
+				Rate:     rate,
+			})
+		}
+
+		sort.Sort(allExchangeRates)
+
+		finalExchangeRateResps = append(finalExchangeRateResps, &models.LatestExchangeRateResponse{
+			DataSource:    exchangeRateResp.DataSource,
+			ReferenceUrl:  exchangeRateResp.ReferenceUrl,
+			UpdateTime:    exchangeRateResp.UpdateTime,
+			BaseCurrency:  exchangeRateResp.BaseCurrency,
+			ExchangeRates: allExchangeRates,
+		})
+	}
+
+	sort.Slice(finalExchangeRateResps, func(i, j int) bool {
+		return finalExchangeRateResps[i].UpdateTime < finalExchangeRateResps[j].UpdateTime
+	})
+
+	return finalExchangeRateResps, nil
+}
+
 func newCommonHttpExchangeRatesDataProvider(config *settings.Config, dataSource HttpExchangeRatesDataSource) *CommonHttpExchangeRatesDataProvider {
 	return &CommonHttpExchangeRatesDataProvider{
 		dataSource: dataSource,
@@ -138,3 +138,26 @@ func TestEuroCentralBankDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestEuroCentralBankDataSource_ParseHistorical(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := core.NewNullContext()
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>"+
+		"<gesmes:Envelope xmlns:gesmes=\"http://www.gesmes.org/xml/2002-08-01\" xmlns=\"http://www.ecb.int/vocabulary/2002-08-01/eurofxref\">"+
+		"<Cube>"+
+		"<Cube time=\"2021-04-01\">"+
+		"<Cube currency=\"USD\" rate=\"1.1746\" />"+
+		"</Cube>"+
+		"<Cube time=\"2021-03-31\">"+
+		"<Cube currency=\"USD\" rate=\"1.1725\" />"+
+		"</Cube>"+
+		"</Cube>"+
+		"</gesmes:Envelope>"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualHistoricalExchangeRateResponses, 2)
+	assert.Equal(t, int64(1617285600), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, "1.1746", actualHistoricalExchangeRateResponses[0].ExchangeRates[0].Rate)
+	assert.Equal(t, int64(1617199200), actualHistoricalExchangeRateResponses[1].UpdateTime)
+	assert.Equal(t, "1.1725", actualHistoricalExchangeRateResponses[1].ExchangeRates[0].Rate)
+}
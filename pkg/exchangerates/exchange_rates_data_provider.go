@@ -1,6 +1,8 @@
 package exchangerates
 
 import (
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
@@ -11,3 +13,12 @@ type ExchangeRatesDataProvider interface {
 	// GetLatestExchangeRates returns the common response entities
 	GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error)
 }
+
+// HistoricalExchangeRatesDataProvider defines the structure of exchange rates data provider which supports historical exchange rates
+type HistoricalExchangeRatesDataProvider interface {
+	// SupportsHistoricalExchangeRates returns whether the data source of provider publishes historical exchange rates
+	SupportsHistoricalExchangeRates() bool
+
+	// GetHistoricalExchangeRates returns the common response entities of every published day between the start date and the end date
+	GetHistoricalExchangeRates(c core.Context, startTime time.Time, endTime time.Time) ([]*models.LatestExchangeRateResponse, error)
+}
@@ -1,6 +1,8 @@
 package exchangerates
 
 import (
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
@@ -86,3 +88,27 @@ func (e *ExchangeRatesDataProviderContainer) GetLatestExchangeRates(c core.Conte
 
 	return e.current.GetLatestExchangeRates(c, uid, currentConfig)
 }
+
+// SupportsHistoricalExchangeRates returns whether the current exchange rates data source publishes historical exchange rates
+func (e *ExchangeRatesDataProviderContainer) SupportsHistoricalExchangeRates() bool {
+	if historicalDataProvider, ok := e.current.(HistoricalExchangeRatesDataProvider); ok {
+		return historicalDataProvider.SupportsHistoricalExchangeRates()
+	}
+
+	return false
+}
+
+// GetHistoricalExchangeRates returns the historical exchange rates data of every published day between the start date and the end date from the current exchange rates data source
+func (e *ExchangeRatesDataProviderContainer) GetHistoricalExchangeRates(c core.Context, startTime time.Time, endTime time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	if Container.current == nil {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	historicalDataProvider, ok := e.current.(HistoricalExchangeRatesDataProvider)
+
+	if !ok || !historicalDataProvider.SupportsHistoricalExchangeRates() {
+		return nil, errs.ErrExchangeRatesDataSourceNotSupportHistoryData
+	}
+
+	return historicalDataProvider.GetHistoricalExchangeRates(c, startTime, endTime)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"math"
 	"net/http"
+	"sort"
 	"time"
 
 	"golang.org/x/net/html/charset"
@@ -18,12 +19,14 @@ import (
 )
 
 const norgesBankExchangeRateUrl = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1&lastNObservations=1"
+const norgesBankHistoricalExchangeRateUrl = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1"
 const norgesBankExchangeRateReferenceUrl = "https://www.norges-bank.no/en/topics/Statistics/exchange_rates/"
 const norgesBankDataSource = "Norges Bank"
 const norgesBankBaseCurrency = "NOK"
 
 const norgesBankUpdateDateFormat = "2006-01-02 15"
 const norgesBankUpdateDateTimezone = "Europe/Oslo"
+const norgesBankHistoricalRequestDateFormat = "2006-01-02"
 
 // NorgesBankDataSource defines the structure of exchange rates data source of Norges Bank
 type NorgesBankDataSource struct {
@@ -119,6 +122,80 @@ func (e *NorgesBankExchangeRateData) ToLatestExchangeRateResponse(c core.Context
 	return latestExchangeRateResp
 }
 
+// ToHistoricalExchangeRateResponses returns the view-objects of every published day according to original data from Norges Bank
+func (e *NorgesBankExchangeRateData) ToHistoricalExchangeRateResponses(c core.Context) []*models.LatestExchangeRateResponse {
+	if e.DataSet == nil || len(e.DataSet.ExchangeRates) < 1 {
+		log.Errorf(c, "[norges_bank_datasource.ToHistoricalExchangeRateResponses] all exchange rates is empty")
+		return nil
+	}
+
+	timezone, err := time.LoadLocation(norgesBankUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[norges_bank_datasource.ToHistoricalExchangeRateResponses] failed to get timezone, timezone name is %s", norgesBankUpdateDateTimezone)
+		return nil
+	}
+
+	dailyExchangeRateResps := make(map[string]*models.LatestExchangeRateResponse)
+
+	for i := 0; i < len(e.DataSet.ExchangeRates); i++ {
+		exchangeRate := e.DataSet.ExchangeRates[i]
+
+		if _, exists := validators.AllCurrencyNames[exchangeRate.BaseCurrency]; !exists {
+			continue
+		}
+
+		if exchangeRate.TargetCurrency != norgesBankBaseCurrency {
+			continue
+		}
+
+		for j := 0; j < len(exchangeRate.Observations); j++ {
+			observation := exchangeRate.Observations[j]
+			exchangeRateResp, exists := dailyExchangeRateResps[observation.Date]
+
+			if !exists {
+				updateDateTime := observation.Date + " 16" // Publication time of daily exchange rates is approximately 16:00 CET.
+				updateTime, err := time.ParseInLocation(norgesBankUpdateDateFormat, updateDateTime, timezone)
+
+				if err != nil {
+					log.Errorf(c, "[norges_bank_datasource.ToHistoricalExchangeRateResponses] failed to parse update date, datetime is %s", observation.Date)
+					return nil
+				}
+
+				exchangeRateResp = &models.LatestExchangeRateResponse{
+					DataSource:    norgesBankDataSource,
+					ReferenceUrl:  norgesBankExchangeRateReferenceUrl,
+					UpdateTime:    updateTime.Unix(),
+					BaseCurrency:  norgesBankBaseCurrency,
+					ExchangeRates: make(models.LatestExchangeRateSlice, 0, len(e.DataSet.ExchangeRates)),
+				}
+
+				dailyExchangeRateResps[observation.Date] = exchangeRateResp
+			}
+
+			finalExchangeRate := exchangeRate.ToLatestExchangeRate(c, observation.Rate)
+
+			if finalExchangeRate == nil {
+				continue
+			}
+
+			exchangeRateResp.ExchangeRates = append(exchangeRateResp.ExchangeRates, finalExchangeRate)
+		}
+	}
+
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(dailyExchangeRateResps))
+
+	for _, exchangeRateResp := range dailyExchangeRateResps {
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	sort.Slice(exchangeRateResps, func(i, j int) bool {
+		return exchangeRateResps[i].UpdateTime < exchangeRateResps[j].UpdateTime
+	})
+
+	return exchangeRateResps
+}
+
 // ToLatestExchangeRate returns a data pair according to original data from Norges Bank
 func (e *NorgesBankExchangeRate) ToLatestExchangeRate(c core.Context, exchangeRate string) *models.LatestExchangeRate {
 	rate, err := utils.StringToFloat64(exchangeRate)
@@ -192,3 +269,42 @@ func (e *NorgesBankDataSource) Parse(c core.Context, content []byte) (*models.La
 
 	return latestExchangeRateResponse, nil
 }
+
+// BuildHistoricalRequests returns the Norges Bank historical exchange rates http requests
+func (e *NorgesBankDataSource) BuildHistoricalRequests(startTime time.Time, endTime time.Time) ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", norgesBankHistoricalExchangeRateUrl, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := req.URL.Query()
+	query.Set("startPeriod", startTime.Format(norgesBankHistoricalRequestDateFormat))
+	query.Set("endPeriod", endTime.Format(norgesBankHistoricalRequestDateFormat))
+	req.URL.RawQuery = query.Encode()
+
+	return []*http.Request{req}, nil
+}
+
+// ParseHistorical returns the common response entities of every published day according to the Norges Bank data source raw response
+func (e *NorgesBankDataSource) ParseHistorical(c core.Context, content []byte) ([]*models.LatestExchangeRateResponse, error) {
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
+	xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+	norgesBankData := &NorgesBankExchangeRateData{}
+	err := xmlDecoder.Decode(norgesBankData)
+
+	if err != nil {
+		log.Errorf(c, "[norges_bank_datasource.ParseHistorical] failed to parse xml data, because %s", err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	historicalExchangeRateResponses := norgesBankData.ToHistoricalExchangeRateResponses(c)
+
+	if historicalExchangeRateResponses == nil {
+		log.Errorf(c, "[norges_bank_datasource.ParseHistorical] failed to parse historical exchange rate data")
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return historicalExchangeRateResponses, nil
+}
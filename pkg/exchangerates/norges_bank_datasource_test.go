@@ -220,3 +220,26 @@ func TestNorgesBankDataSource_InvalidUnit(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestNorgesBankDataSource_ParseHistorical(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+	context := core.NewNullContext()
+
+	actualHistoricalExchangeRateResponses, err := dataSource.ParseHistorical(context, []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+		"<message:StructureSpecificData xmlns:message=\"http://www.sdmx.org/resources/sdmxml/schemas/v2_1/message\">\n"+
+		"  <message:DataSet>\n"+
+		"    <Series BASE_CUR=\"USD\" QUOTE_CUR=\"NOK\" UNIT_MULT=\"0\">\n"+
+		"      <Obs TIME_PERIOD=\"2024-11-14\" OBS_VALUE=\"11.0000\" />\n"+
+		"      <Obs TIME_PERIOD=\"2024-11-15\" OBS_VALUE=\"11.0545\" />\n"+
+		"    </Series>\n"+
+		"  </message:DataSet>\n"+
+		"</message:StructureSpecificData>"))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualHistoricalExchangeRateResponses, 2)
+	assert.Equal(t, int64(1731596400), actualHistoricalExchangeRateResponses[0].UpdateTime)
+	assert.Contains(t, actualHistoricalExchangeRateResponses[0].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "0.09090909090909091",
+	})
+	assert.Equal(t, int64(1731682800), actualHistoricalExchangeRateResponses[1].UpdateTime)
+}
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"crypto/tls"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"syscall"
 	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 type defaultTransport struct {
@@ -47,6 +52,24 @@ func (t *defaultTransport) RoundTrip(req *http.Request) (*http.Response, error)
 // NewHttpClient creates and returns a new http client with specified settings
 func NewHttpClient(requestTimeout uint32, proxy string, skipTLSVerify bool, defaultUserAgent string, enableHttpResponseLog bool) *http.Client {
 	baseTransport := http.DefaultTransport.(*http.Transport).Clone()
+	return newHttpClient(baseTransport, requestTimeout, proxy, skipTLSVerify, defaultUserAgent, enableHttpResponseLog)
+}
+
+// NewPublicNetworkHttpClient creates and returns a new http client with specified settings, which refuses to connect to non-public network address (including the proxy server)
+// The address is checked after domain name resolution when connecting, so that the domain name which resolves to non-public network address cannot bypass the check
+func NewPublicNetworkHttpClient(requestTimeout uint32, proxy string, skipTLSVerify bool, defaultUserAgent string, enableHttpResponseLog bool) *http.Client {
+	baseTransport := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   publicNetworkOnlyDialControl,
+	}
+	baseTransport.DialContext = dialer.DialContext
+
+	return newHttpClient(baseTransport, requestTimeout, proxy, skipTLSVerify, defaultUserAgent, enableHttpResponseLog)
+}
+
+func newHttpClient(baseTransport *http.Transport, requestTimeout uint32, proxy string, skipTLSVerify bool, defaultUserAgent string, enableHttpResponseLog bool) *http.Client {
 	SetProxyUrl(baseTransport, proxy)
 
 	if skipTLSVerify {
@@ -76,3 +99,17 @@ func SetProxyUrl(transport *http.Transport, proxy string) {
 		transport.Proxy = http.ProxyFromEnvironment
 	}
 }
+
+func publicNetworkOnlyDialControl(network string, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+
+	if err != nil {
+		return err
+	}
+
+	if !utils.IsPublicIPAddress(net.ParseIP(host)) {
+		return errs.ErrPrivateNetworkAddressNotAllowed
+	}
+
+	return nil
+}
@@ -6,11 +6,13 @@ import (
 
 // LocaleTextItems represents all text items need to be translated
 type LocaleTextItems struct {
-	GlobalTextItems             *GlobalTextItems
-	DefaultTypes                *DefaultTypes
-	DataConverterTextItems      *DataConverterTextItems
-	VerifyEmailTextItems        *VerifyEmailTextItems
-	ForgetPasswordMailTextItems *ForgetPasswordMailTextItems
+	GlobalTextItems               *GlobalTextItems
+	DefaultTypes                  *DefaultTypes
+	DataConverterTextItems        *DataConverterTextItems
+	VerifyEmailTextItems          *VerifyEmailTextItems
+	ForgetPasswordMailTextItems   *ForgetPasswordMailTextItems
+	BudgetAlertMailTextItems      *BudgetAlertMailTextItems
+	LedgerInvitationMailTextItems *LedgerInvitationMailTextItems
 }
 
 // GlobalTextItems represents global text items need to be translated
@@ -47,3 +49,27 @@ type ForgetPasswordMailTextItems struct {
 	ResetPassword             string
 	DescriptionBelowBtnFormat string
 }
+
+// BudgetAlertMailTextItems represents text items need to be translated in budget alert mail
+type BudgetAlertMailTextItems struct {
+	Title                 string
+	SalutationFormat      string
+	DescriptionAboveTable string
+	BudgetName            string
+	SpentAmount           string
+	AvailableAmount       string
+	UsedPercentage        string
+	DescriptionBelowTable string
+}
+
+// LedgerInvitationMailTextItems represents text items need to be translated in shared ledger invitation mail
+type LedgerInvitationMailTextItems struct {
+	Title                     string
+	SalutationFormat          string
+	DescriptionAboveBtnFormat string
+	AcceptInvitation          string
+	RoleOwner                 string
+	RoleEditor                string
+	RoleViewer                string
+	DescriptionBelowBtnFormat string
+}
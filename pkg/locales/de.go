@@ -30,4 +30,24 @@ var de = &LocaleTextItems{
 		ResetPassword:             "Passwort zurücksetzen",
 		DescriptionBelowBtnFormat: "Wenn Sie nicht angefordert haben, Ihr Passwort zurückzusetzen, ignorieren Sie bitte diese E-Mail. Wenn Sie den obigen Link nicht anklicken können, kopieren Sie bitte die obige URL und fügen Sie sie in Ihren Browser ein. Der Link zum Zurücksetzen des Passworts wird nach %v Minuten ablaufen.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Budget-Warnung",
+		SalutationFormat:      "Hallo %s,",
+		DescriptionAboveTable: "Die Ausgaben der folgenden Budgets haben die von Ihnen festgelegten Warnschwellen erreicht.",
+		BudgetName:            "Budget",
+		SpentAmount:           "Ausgegeben",
+		AvailableAmount:       "Verfügbar",
+		UsedPercentage:        "Verbraucht",
+		DescriptionBelowTable: "Sie können die Budget-Warnschwellen in den Benutzereinstellungen ändern oder deaktivieren.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Einladung zum gemeinsamen Haushaltsbuch",
+		SalutationFormat:          "Hallo %s,",
+		DescriptionAboveBtnFormat: "%s hat Sie eingeladen, dem gemeinsamen Haushaltsbuch \"%s\" als %s beizutreten. Sie können auf den folgenden Link klicken, um die Einladung anzunehmen.",
+		AcceptInvitation:          "Einladung annehmen",
+		RoleOwner:                 "Eigentümer",
+		RoleEditor:                "Bearbeiter",
+		RoleViewer:                "Betrachter",
+		DescriptionBelowBtnFormat: "Wenn Sie diesem Haushaltsbuch nicht beitreten möchten, ignorieren Sie diese E-Mail einfach. Wenn Sie den obigen Link nicht anklicken können, kopieren Sie bitte die obige URL und fügen Sie sie in Ihren Browser ein. Der Einladungslink läuft nach %v Stunden ab.",
+	},
 }
@@ -30,4 +30,24 @@ var el = &LocaleTextItems{
 		ResetPassword:             "Επαναφορά κωδικού πρόσβασης",
 		DescriptionBelowBtnFormat: "Αν δεν ζητήσατε εσείς επαναφορά του κωδικού πρόσβασής σας, απλώς αγνοήστε αυτό το email. Αν δεν μπορείτε να κάνετε κλικ στον παραπάνω σύνδεσμο, αντιγράψτε τη διεύθυνση και επικολλήστε την στο πρόγραμμα περιήγησής σας. Ο σύνδεσμος επαναφοράς κωδικού πρόσβασης θα λήξει μετά από %v λεπτά.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Budget Alert",
+		SalutationFormat:      "Hi %s,",
+		DescriptionAboveTable: "The spending of the following budgets has reached the alert thresholds you set.",
+		BudgetName:            "Budget",
+		SpentAmount:           "Spent",
+		AvailableAmount:       "Available",
+		UsedPercentage:        "Used",
+		DescriptionBelowTable: "You can change or turn off the budget alert thresholds in the user settings.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Shared Ledger Invitation",
+		SalutationFormat:          "Hi %s,",
+		DescriptionAboveBtnFormat: "%s invited you to join the shared ledger \"%s\" as %s. You can click the link below to accept the invitation.",
+		AcceptInvitation:          "Accept Invitation",
+		RoleOwner:                 "Owner",
+		RoleEditor:                "Editor",
+		RoleViewer:                "Viewer",
+		DescriptionBelowBtnFormat: "If you do not want to join this ledger, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The invitation link will be expired after %v hours.",
+	},
 }
@@ -30,4 +30,24 @@ var es = &LocaleTextItems{
 		ResetPassword:             "Restablecer Contraseña",
 		DescriptionBelowBtnFormat: "Si no solicitó un restablecimiento de contraseña, simplemente descarte este correo. Si no puede hacer click en el link anterior, copie la url arriba mostrada y péguela en su navegadror. El enlace de restablecimiento de contraseña expira pasados %v minutos.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Alerta de presupuesto",
+		SalutationFormat:      "Hola %s,",
+		DescriptionAboveTable: "El gasto de los siguientes presupuestos ha alcanzado los umbrales de alerta que configuró.",
+		BudgetName:            "Presupuesto",
+		SpentAmount:           "Gastado",
+		AvailableAmount:       "Disponible",
+		UsedPercentage:        "Usado",
+		DescriptionBelowTable: "Puede cambiar o desactivar los umbrales de alerta de presupuesto en la configuración de usuario.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Invitación a libro compartido",
+		SalutationFormat:          "Hola %s,",
+		DescriptionAboveBtnFormat: "%s te ha invitado a unirte al libro compartido \"%s\" como %s. Puedes hacer clic en el siguiente enlace para aceptar la invitación.",
+		AcceptInvitation:          "Aceptar invitación",
+		RoleOwner:                 "Propietario",
+		RoleEditor:                "Editor",
+		RoleViewer:                "Lector",
+		DescriptionBelowBtnFormat: "Si no deseas unirte a este libro, simplemente ignora este correo. Si no puedes hacer clic en el enlace anterior, copia la URL anterior y pégala en tu navegador. El enlace de invitación caducará en %v horas.",
+	},
 }
@@ -30,4 +30,24 @@ var fr = &LocaleTextItems{
 		ResetPassword:             "Réinitialiser le mot de passe",
 		DescriptionBelowBtnFormat: "Si vous n'avez pas demandé la réinitialisation de votre mot de passe, vous pouvez ignorer cet e-mail. Si vous ne pouvez pas cliquer sur le lien ci-dessus, copiez l'URL ci-dessus et collez-la dans votre navigateur. Le lien de réinitialisation du mot de passe expire après %v minutes.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Alerte de budget",
+		SalutationFormat:      "Bonjour %s,",
+		DescriptionAboveTable: "Les dépenses des budgets suivants ont atteint les seuils d'alerte que vous avez définis.",
+		BudgetName:            "Budget",
+		SpentAmount:           "Dépensé",
+		AvailableAmount:       "Disponible",
+		UsedPercentage:        "Utilisé",
+		DescriptionBelowTable: "Vous pouvez modifier ou désactiver les seuils d'alerte de budget dans les paramètres utilisateur.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Invitation à un registre partagé",
+		SalutationFormat:          "Bonjour %s,",
+		DescriptionAboveBtnFormat: "%s vous a invité à rejoindre le registre partagé « %s » en tant que %s. Vous pouvez cliquer sur le lien ci-dessous pour accepter l'invitation.",
+		AcceptInvitation:          "Accepter l'invitation",
+		RoleOwner:                 "Propriétaire",
+		RoleEditor:                "Éditeur",
+		RoleViewer:                "Lecteur",
+		DescriptionBelowBtnFormat: "Si vous ne souhaitez pas rejoindre ce registre, ignorez simplement cet e-mail. Si vous ne pouvez pas cliquer sur le lien ci-dessus, copiez l'URL ci-dessus et collez-la dans votre navigateur. Le lien d'invitation expirera dans %v heures.",
+	},
 }
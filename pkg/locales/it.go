@@ -30,4 +30,24 @@ var it = &LocaleTextItems{
 		ResetPassword:             "Reimposta password",
 		DescriptionBelowBtnFormat: "Se non hai chiesto alcun cambio della password, puoi ignorare questa mail. Se non riesci a cliccare il link, copia l'indirizzo URL qui sopra e incollalo nel tuo browser preferito. Il link di verifica scadrà tra %v minuti.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Avviso di budget",
+		SalutationFormat:      "Ciao %s,",
+		DescriptionAboveTable: "La spesa dei seguenti budget ha raggiunto le soglie di avviso che hai impostato.",
+		BudgetName:            "Budget",
+		SpentAmount:           "Speso",
+		AvailableAmount:       "Disponibile",
+		UsedPercentage:        "Utilizzato",
+		DescriptionBelowTable: "Puoi modificare o disattivare le soglie di avviso del budget nelle impostazioni utente.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Invito a un registro condiviso",
+		SalutationFormat:          "Ciao %s,",
+		DescriptionAboveBtnFormat: "%s ti ha invitato a unirti al registro condiviso \"%s\" come %s. Puoi fare clic sul link sottostante per accettare l'invito.",
+		AcceptInvitation:          "Accetta invito",
+		RoleOwner:                 "Proprietario",
+		RoleEditor:                "Editor",
+		RoleViewer:                "Visualizzatore",
+		DescriptionBelowBtnFormat: "Se non desideri unirti a questo registro, ignora semplicemente questa email. Se non riesci a fare clic sul link sopra, copia l'URL sopra e incollalo nel tuo browser. Il link di invito scadrà tra %v ore.",
+	},
 }
@@ -30,4 +30,24 @@ var ja = &LocaleTextItems{
 		ResetPassword:             "パスワードをリセット",
 		DescriptionBelowBtnFormat: "パスワードのリセットをリクエストしていない場合はこのメールを無視してください。上記のリンクをクリックできない場合は、上記のURLをコピーしてブラウザに貼り付けてください。パスワードリセットのリンクは%v分後に期限切れになります。",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "予算アラート",
+		SalutationFormat:      "%s さん、",
+		DescriptionAboveTable: "以下の予算の支出が設定したアラートのしきい値に達しました。",
+		BudgetName:            "予算",
+		SpentAmount:           "支出済み",
+		AvailableAmount:       "利用可能",
+		UsedPercentage:        "使用率",
+		DescriptionBelowTable: "予算アラートのしきい値はユーザー設定で変更または無効にできます。",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "共有帳簿への招待",
+		SalutationFormat:          "%s 様",
+		DescriptionAboveBtnFormat: "%s さんがあなたを共有帳簿「%s」に招待しました（権限：%s）。下のリンクをクリックして招待を承諾してください。",
+		AcceptInvitation:          "招待を承諾",
+		RoleOwner:                 "オーナー",
+		RoleEditor:                "編集者",
+		RoleViewer:                "閲覧者",
+		DescriptionBelowBtnFormat: "この帳簿に参加しない場合は、このメールを無視してください。上のリンクをクリックできない場合は、上記のURLをコピーしてブラウザに貼り付けてください。招待リンクは %v 時間後に期限切れになります。",
+	},
 }
@@ -30,4 +30,24 @@ var ko = &LocaleTextItems{
 		ResetPassword:             "비밀번호 재설정",
 		DescriptionBelowBtnFormat: "비밀번호 재설정을 요청하지 않으셨다면 이 이메일을 무시해주세요. 위 링크를 클릭할 수 없는 경우, 위 URL을 복사하여 브라우저에 붙여넣어 주세요. 비밀번호 재설정 링크는 %v분 후에 만료됩니다.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "예산 알림",
+		SalutationFormat:      "%s님, 안녕하세요.",
+		DescriptionAboveTable: "다음 예산의 지출이 설정한 알림 임계값에 도달했습니다.",
+		BudgetName:            "예산",
+		SpentAmount:           "지출",
+		AvailableAmount:       "사용 가능",
+		UsedPercentage:        "사용률",
+		DescriptionBelowTable: "사용자 설정에서 예산 알림 임계값을 변경하거나 끌 수 있습니다.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "공유 장부 초대",
+		SalutationFormat:          "%s님 안녕하세요,",
+		DescriptionAboveBtnFormat: "%s님이 공유 장부 \"%s\"에 %s(으)로 초대했습니다. 아래 링크를 클릭하여 초대를 수락할 수 있습니다.",
+		AcceptInvitation:          "초대 수락",
+		RoleOwner:                 "소유자",
+		RoleEditor:                "편집자",
+		RoleViewer:                "뷰어",
+		DescriptionBelowBtnFormat: "이 장부에 참여하지 않으려면 이 이메일을 무시하세요. 위 링크를 클릭할 수 없는 경우 위 URL을 복사하여 브라우저에 붙여넣으세요. 초대 링크는 %v시간 후에 만료됩니다.",
+	},
 }
@@ -30,4 +30,24 @@ var nl = &LocaleTextItems{
 		ResetPassword:             "Wachtwoord opnieuw instellen",
 		DescriptionBelowBtnFormat: "Als je geen verzoek hebt gedaan om je wachtwoord te resetten, kun je deze e-mail negeren. Als je niet op de bovenstaande link kunt klikken, kopieer dan de URL hierboven en plak deze in je browser. De link voor het opnieuw instellen van het wachtwoord verloopt na  %v minuten.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Budgetwaarschuwing",
+		SalutationFormat:      "Hallo %s,",
+		DescriptionAboveTable: "De uitgaven van de volgende budgetten hebben de door u ingestelde waarschuwingsdrempels bereikt.",
+		BudgetName:            "Budget",
+		SpentAmount:           "Uitgegeven",
+		AvailableAmount:       "Beschikbaar",
+		UsedPercentage:        "Gebruikt",
+		DescriptionBelowTable: "U kunt de drempels voor budgetwaarschuwingen wijzigen of uitschakelen in de gebruikersinstellingen.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Uitnodiging voor gedeeld kasboek",
+		SalutationFormat:          "Hallo %s,",
+		DescriptionAboveBtnFormat: "%s heeft je uitgenodigd om lid te worden van het gedeelde kasboek \"%s\" als %s. Je kunt op de onderstaande link klikken om de uitnodiging te accepteren.",
+		AcceptInvitation:          "Uitnodiging accepteren",
+		RoleOwner:                 "Eigenaar",
+		RoleEditor:                "Bewerker",
+		RoleViewer:                "Lezer",
+		DescriptionBelowBtnFormat: "Als je niet wilt deelnemen aan dit kasboek, negeer deze e-mail dan gewoon. Als je niet op de bovenstaande link kunt klikken, kopieer dan de bovenstaande URL en plak deze in je browser. De uitnodigingslink verloopt na %v uur.",
+	},
 }
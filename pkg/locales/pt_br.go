@@ -30,4 +30,24 @@ var ptBR = &LocaleTextItems{
 		ResetPassword:             "Redefinir senha",
 		DescriptionBelowBtnFormat: "Se você não solicitou a redefinição da senha, ignore este e-mail. Se não conseguir clicar no link acima, copie a URL e cole no navegador. O link de redefinição de senha expira em %v minutos.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Alerta de orçamento",
+		SalutationFormat:      "Olá %s,",
+		DescriptionAboveTable: "Os gastos dos seguintes orçamentos atingiram os limites de alerta que você definiu.",
+		BudgetName:            "Orçamento",
+		SpentAmount:           "Gasto",
+		AvailableAmount:       "Disponível",
+		UsedPercentage:        "Utilizado",
+		DescriptionBelowTable: "Você pode alterar ou desativar os limites de alerta de orçamento nas configurações do usuário.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Convite para livro compartilhado",
+		SalutationFormat:          "Olá %s,",
+		DescriptionAboveBtnFormat: "%s convidou você para participar do livro compartilhado \"%s\" como %s. Você pode clicar no link abaixo para aceitar o convite.",
+		AcceptInvitation:          "Aceitar convite",
+		RoleOwner:                 "Proprietário",
+		RoleEditor:                "Editor",
+		RoleViewer:                "Leitor",
+		DescriptionBelowBtnFormat: "Se você não deseja participar deste livro, simplesmente ignore este e-mail. Se você não conseguir clicar no link acima, copie a URL acima e cole-a no seu navegador. O link de convite expirará após %v horas.",
+	},
 }
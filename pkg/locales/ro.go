@@ -30,4 +30,24 @@ var ro = &LocaleTextItems{
 		ResetPassword:             "Resetare Parolă",
 		DescriptionBelowBtnFormat: "Dacă nu ați solicitat resetarea parolei, ignorați acest email. Dacă nu puteți accesa linkul de mai sus, copiați adresa URL și inserați-o în browser. Linkul de resetare va expira după %v minute.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Budget Alert",
+		SalutationFormat:      "Hi %s,",
+		DescriptionAboveTable: "The spending of the following budgets has reached the alert thresholds you set.",
+		BudgetName:            "Budget",
+		SpentAmount:           "Spent",
+		AvailableAmount:       "Available",
+		UsedPercentage:        "Used",
+		DescriptionBelowTable: "You can change or turn off the budget alert thresholds in the user settings.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Shared Ledger Invitation",
+		SalutationFormat:          "Hi %s,",
+		DescriptionAboveBtnFormat: "%s invited you to join the shared ledger \"%s\" as %s. You can click the link below to accept the invitation.",
+		AcceptInvitation:          "Accept Invitation",
+		RoleOwner:                 "Owner",
+		RoleEditor:                "Editor",
+		RoleViewer:                "Viewer",
+		DescriptionBelowBtnFormat: "If you do not want to join this ledger, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The invitation link will be expired after %v hours.",
+	},
 }
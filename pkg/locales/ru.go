@@ -30,4 +30,24 @@ var ru = &LocaleTextItems{
 		ResetPassword:             "Сбросить пароль",
 		DescriptionBelowBtnFormat: "Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо. Если вы не можете нажать на ссылку выше, скопируйте указанный выше URL и вставьте его в браузер. Ссылка для сброса пароля истечет через %v минут.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Уведомление о бюджете",
+		SalutationFormat:      "Здравствуйте, %s!",
+		DescriptionAboveTable: "Расходы по следующим бюджетам достигли заданных вами порогов уведомления.",
+		BudgetName:            "Бюджет",
+		SpentAmount:           "Потрачено",
+		AvailableAmount:       "Доступно",
+		UsedPercentage:        "Использовано",
+		DescriptionBelowTable: "Вы можете изменить или отключить пороги уведомлений о бюджете в настройках пользователя.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Приглашение в общую книгу",
+		SalutationFormat:          "Здравствуйте, %s,",
+		DescriptionAboveBtnFormat: "%s пригласил(а) вас присоединиться к общей книге «%s» в роли «%s». Нажмите на ссылку ниже, чтобы принять приглашение.",
+		AcceptInvitation:          "Принять приглашение",
+		RoleOwner:                 "Владелец",
+		RoleEditor:                "Редактор",
+		RoleViewer:                "Наблюдатель",
+		DescriptionBelowBtnFormat: "Если вы не хотите присоединяться к этой книге, просто проигнорируйте это письмо. Если вы не можете нажать на ссылку выше, скопируйте указанный адрес и вставьте его в браузер. Срок действия ссылки-приглашения истечёт через %v ч.",
+	},
 }
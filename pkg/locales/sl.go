@@ -30,4 +30,24 @@ var sl = &LocaleTextItems{
 		ResetPassword:             "Ponastavi geslo",
 		DescriptionBelowBtnFormat: "Če niste zahtevali ponastavitve gesla, prosimo, da to e-poštno sporočilo preprosto prezrete. Če ne morete klikniti zgornje povezave, kopirajte zgornji URL in ga prilepite v brskalnik. Povezava za ponastavitev gesla bo potekla po %v minutah.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Budget Alert",
+		SalutationFormat:      "Hi %s,",
+		DescriptionAboveTable: "The spending of the following budgets has reached the alert thresholds you set.",
+		BudgetName:            "Budget",
+		SpentAmount:           "Spent",
+		AvailableAmount:       "Available",
+		UsedPercentage:        "Used",
+		DescriptionBelowTable: "You can change or turn off the budget alert thresholds in the user settings.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Shared Ledger Invitation",
+		SalutationFormat:          "Hi %s,",
+		DescriptionAboveBtnFormat: "%s invited you to join the shared ledger \"%s\" as %s. You can click the link below to accept the invitation.",
+		AcceptInvitation:          "Accept Invitation",
+		RoleOwner:                 "Owner",
+		RoleEditor:                "Editor",
+		RoleViewer:                "Viewer",
+		DescriptionBelowBtnFormat: "If you do not want to join this ledger, please simply disregard this email. If you cannot click the link above, please copy the above url and paste it into your browser. The invitation link will be expired after %v hours.",
+	},
 }
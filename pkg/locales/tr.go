@@ -30,4 +30,24 @@ var tr = &LocaleTextItems{
 		ResetPassword:             "Şifreyi Sıfırla",
 		DescriptionBelowBtnFormat: "Eğer şifre sıfırlama talebinde bulunmadıysanız, lütfen bu e-postayı dikkate almayın. Eğer yukarıdaki bağlantıya tıklayamıyorsanız, lütfen adresi kopyalayıp tarayıcınıza yapıştırın. Şifre sıfırlama bağlantısının süresi %v dakika sonra dolacaktır.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Bütçe Uyarısı",
+		SalutationFormat:      "Merhaba %s,",
+		DescriptionAboveTable: "Aşağıdaki bütçelerin harcamaları belirlediğiniz uyarı eşiklerine ulaştı.",
+		BudgetName:            "Bütçe",
+		SpentAmount:           "Harcanan",
+		AvailableAmount:       "Kullanılabilir",
+		UsedPercentage:        "Kullanılan",
+		DescriptionBelowTable: "Bütçe uyarı eşiklerini kullanıcı ayarlarından değiştirebilir veya kapatabilirsiniz.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Paylaşılan Defter Daveti",
+		SalutationFormat:          "Merhaba %s,",
+		DescriptionAboveBtnFormat: "%s sizi paylaşılan \"%s\" defterine %s olarak katılmaya davet etti. Daveti kabul etmek için aşağıdaki bağlantıya tıklayabilirsiniz.",
+		AcceptInvitation:          "Daveti Kabul Et",
+		RoleOwner:                 "Sahip",
+		RoleEditor:                "Düzenleyici",
+		RoleViewer:                "Görüntüleyici",
+		DescriptionBelowBtnFormat: "Bu deftere katılmak istemiyorsanız, lütfen bu e-postayı dikkate almayın. Yukarıdaki bağlantıya tıklayamıyorsanız, lütfen yukarıdaki URL'yi kopyalayıp tarayıcınıza yapıştırın. Davet bağlantısının süresi %v saat sonra dolacaktır.",
+	},
 }
@@ -30,4 +30,24 @@ var uk = &LocaleTextItems{
 		ResetPassword:             "Скинути пароль",
 		DescriptionBelowBtnFormat: "Якщо ви не надсилали запит на скидання пароля, просто проігноруйте цей лист. Якщо ви не можете натиснути на посилання вище, скопіюйте вказану URL-адресу та вставте її у свій браузер. Посилання для скидання пароля буде дійсне протягом %v хвилин.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Сповіщення про бюджет",
+		SalutationFormat:      "Вітаємо, %s!",
+		DescriptionAboveTable: "Витрати за наступними бюджетами досягли встановлених вами порогів сповіщення.",
+		BudgetName:            "Бюджет",
+		SpentAmount:           "Витрачено",
+		AvailableAmount:       "Доступно",
+		UsedPercentage:        "Використано",
+		DescriptionBelowTable: "Ви можете змінити або вимкнути пороги сповіщень про бюджет у налаштуваннях користувача.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Запрошення до спільної книги",
+		SalutationFormat:          "Вітаємо, %s,",
+		DescriptionAboveBtnFormat: "%s запросив(ла) вас приєднатися до спільної книги «%s» у ролі «%s». Натисніть посилання нижче, щоб прийняти запрошення.",
+		AcceptInvitation:          "Прийняти запрошення",
+		RoleOwner:                 "Власник",
+		RoleEditor:                "Редактор",
+		RoleViewer:                "Глядач",
+		DescriptionBelowBtnFormat: "Якщо ви не бажаєте приєднуватися до цієї книги, просто проігноруйте цей лист. Якщо ви не можете натиснути на посилання вище, скопіюйте вказану адресу та вставте її у браузер. Термін дії посилання-запрошення закінчиться через %v год.",
+	},
 }
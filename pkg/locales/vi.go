@@ -30,4 +30,24 @@ var vi = &LocaleTextItems{
 		ResetPassword:             "Đặt lại Mật khẩu",
 		DescriptionBelowBtnFormat: "Nếu bạn không yêu cầu đặt lại mật khẩu, vui lòng bỏ qua email này. Nếu bạn không thể nhấp vào liên kết trên, hãy sao chép và dán liên kết vào trình duyệt của bạn. Liên kết đặt lại mật khẩu sẽ hết hạn sau %v phút.",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "Cảnh báo ngân sách",
+		SalutationFormat:      "Xin chào %s,",
+		DescriptionAboveTable: "Chi tiêu của các ngân sách sau đã đạt ngưỡng cảnh báo mà bạn đã đặt.",
+		BudgetName:            "Ngân sách",
+		SpentAmount:           "Đã chi",
+		AvailableAmount:       "Khả dụng",
+		UsedPercentage:        "Đã dùng",
+		DescriptionBelowTable: "Bạn có thể thay đổi hoặc tắt ngưỡng cảnh báo ngân sách trong cài đặt người dùng.",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "Lời mời tham gia sổ chung",
+		SalutationFormat:          "Xin chào %s,",
+		DescriptionAboveBtnFormat: "%s đã mời bạn tham gia sổ chung \"%s\" với vai trò %s. Bạn có thể nhấp vào liên kết bên dưới để chấp nhận lời mời.",
+		AcceptInvitation:          "Chấp nhận lời mời",
+		RoleOwner:                 "Chủ sở hữu",
+		RoleEditor:                "Người chỉnh sửa",
+		RoleViewer:                "Người xem",
+		DescriptionBelowBtnFormat: "Nếu bạn không muốn tham gia sổ này, vui lòng bỏ qua email này. Nếu bạn không thể nhấp vào liên kết trên, vui lòng sao chép URL ở trên và dán vào trình duyệt của bạn. Liên kết mời sẽ hết hạn sau %v giờ.",
+	},
 }
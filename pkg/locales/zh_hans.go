@@ -30,4 +30,24 @@ var zhHans = &LocaleTextItems{
 		ResetPassword:             "重置密码",
 		DescriptionBelowBtnFormat: "如果您没有请求重置密码，请直接忽略本邮件。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。重置密码链接将在 %v 分钟后过期。",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "预算提醒",
+		SalutationFormat:      "%s 您好，",
+		DescriptionAboveTable: "以下预算的支出已达到您设置的提醒阈值。",
+		BudgetName:            "预算",
+		SpentAmount:           "已支出",
+		AvailableAmount:       "可用",
+		UsedPercentage:        "已使用",
+		DescriptionBelowTable: "您可以在用户设置中修改或关闭预算提醒阈值。",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "共享账本邀请",
+		SalutationFormat:          "%s 您好，",
+		DescriptionAboveBtnFormat: "%s 邀请您加入共享账本“%s”，您的身份为%s。您可以点击下方链接接受邀请。",
+		AcceptInvitation:          "接受邀请",
+		RoleOwner:                 "所有者",
+		RoleEditor:                "编辑者",
+		RoleViewer:                "查看者",
+		DescriptionBelowBtnFormat: "如果您不想加入该账本，请直接忽略本邮件。如果您无法点击上述链接，请复制下方的地址然后在您的浏览器中粘贴。邀请链接将在 %v 小时后过期。",
+	},
 }
@@ -30,4 +30,24 @@ var zhHant = &LocaleTextItems{
 		ResetPassword:             "重設密碼",
 		DescriptionBelowBtnFormat: "如果您沒有請求重設密碼，請直接忽略本郵件。如果您無法點擊上述連結，請複製下方的地址然後在您的瀏覽器中貼上。重設密碼連結將在 %v 分鐘後過期。",
 	},
+	BudgetAlertMailTextItems: &BudgetAlertMailTextItems{
+		Title:                 "預算提醒",
+		SalutationFormat:      "%s 您好，",
+		DescriptionAboveTable: "以下預算的支出已達到您設定的提醒閾值。",
+		BudgetName:            "預算",
+		SpentAmount:           "已支出",
+		AvailableAmount:       "可用",
+		UsedPercentage:        "已使用",
+		DescriptionBelowTable: "您可以在使用者設定中修改或關閉預算提醒閾值。",
+	},
+	LedgerInvitationMailTextItems: &LedgerInvitationMailTextItems{
+		Title:                     "共享帳本邀請",
+		SalutationFormat:          "%s 您好，",
+		DescriptionAboveBtnFormat: "%s 邀請您加入共享帳本「%s」，您的身分為%s。您可以點擊下方連結接受邀請。",
+		AcceptInvitation:          "接受邀請",
+		RoleOwner:                 "擁有者",
+		RoleEditor:                "編輯者",
+		RoleViewer:                "檢視者",
+		DescriptionBelowBtnFormat: "如果您不想加入該帳本，請直接忽略本郵件。如果您無法點擊上述連結，請複製下方的網址然後在您的瀏覽器中貼上。邀請連結將在 %v 小時後過期。",
+	},
 }
@@ -0,0 +1,165 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maxTransactionCountInAccountLedgerResource = 100
+
+// MCPAccountLedgerResource represents the contents of the account ledger resource
+type MCPAccountLedgerResource struct {
+	AccountId             string                                   `json:"account_id"`
+	AccountName           string                                   `json:"account_name"`
+	Currency              string                                   `json:"currency"`
+	CurrentBalance        string                                   `json:"current_balance"`
+	TotalTransactionCount int                                      `json:"total_transaction_count"`
+	Transactions          []*MCPReconciliationStatementTransaction `json:"transactions"`
+}
+
+type mcpAccountLedgerResourceHandler struct{}
+
+var MCPAccountLedgerResourceHandler = &mcpAccountLedgerResourceHandler{}
+
+// Name returns the name of the MCP resource
+func (h *mcpAccountLedgerResourceHandler) Name() string {
+	return "account_ledger"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpAccountLedgerResourceHandler) Description() string {
+	return fmt.Sprintf("Ledger of an account in ezBookkeeping, including the current balance and the latest %d transactions with the account balance after each transaction.", maxTransactionCountInAccountLedgerResource)
+}
+
+// URITemplate returns the uri template of the MCP resource
+func (h *mcpAccountLedgerResourceHandler) URITemplate() string {
+	return mcpResourceURIPrefix + "accounts/{account_id}/ledger"
+}
+
+// MimeType returns the mime type of the MCP resource contents
+func (h *mcpAccountLedgerResourceHandler) MimeType() string {
+	return "application/json"
+}
+
+// RequiredScope returns the mcp token scope required to read the MCP resource
+func (h *mcpAccountLedgerResourceHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_TRANSACTIONS
+}
+
+// List returns all the resources of the current user which can be read by the MCP resource handler
+func (h *mcpAccountLedgerResourceHandler) List(c *core.WebContext, user *models.User, services MCPAvailableServices) ([]*MCPResource, error) {
+	uid := user.Uid
+	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[account_ledger_resource_handler.List] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	hiddenParentAccountIds := make(map[int64]bool)
+	resources := make([]*MCPResource, 0, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Hidden {
+			if account.ParentAccountId == models.LevelOneAccountParentId {
+				hiddenParentAccountIds[account.AccountId] = true
+			}
+
+			continue
+		}
+
+		if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT || hiddenParentAccountIds[account.ParentAccountId] {
+			continue
+		}
+
+		resources = append(resources, &MCPResource{
+			URI:         getMCPAccountLedgerResourceURI(account.AccountId),
+			Name:        h.Name(),
+			Title:       fmt.Sprintf("Ledger of %s", account.Name),
+			Description: fmt.Sprintf("Ledger of the account \"%s\" (%s)", account.Name, account.Currency),
+			MimeType:    h.MimeType(),
+		})
+	}
+
+	return resources, nil
+}
+
+// Read returns the text contents of the MCP resource according to the variables in the uri
+func (h *mcpAccountLedgerResourceHandler) Read(c *core.WebContext, uriVariables map[string]string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (string, error) {
+	accountId, err := utils.StringToInt64(uriVariables["account_id"])
+
+	if err != nil || accountId <= 0 {
+		return "", errs.ErrAccountIdInvalid
+	}
+
+	uid := user.Uid
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[account_ledger_resource_handler.Read] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return "", err
+	}
+
+	accountsMap := services.GetAccountService().GetAccountMapByList(allAccounts)
+	account, exists := accountsMap[accountId]
+
+	if !exists || account == nil {
+		log.Warnf(c, "[account_ledger_resource_handler.Read] account \"id:%d\" not found for user \"uid:%d\"", accountId, uid)
+		return "", errs.ErrAccountNotFound
+	}
+
+	if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		log.Warnf(c, "[account_ledger_resource_handler.Read] account \"id:%d\" for user \"uid:%d\" is not a single account", accountId, uid)
+		return "", errs.ErrAccountTypeInvalid
+	}
+
+	transactionsWithAccountBalance, _, _, _, closingBalance, err := services.GetTransactionService().GetAllTransactionsInOneAccountWithAccountBalanceByMaxTime(c, uid, pageCountForLoadTransactions, 0, 0, account.AccountId, account.Category)
+
+	if err != nil {
+		log.Errorf(c, "[account_ledger_resource_handler.Read] failed to get transactions of account \"id:%d\" for user \"uid:%d\", because %s", accountId, uid, err.Error())
+		return "", err
+	}
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Errorf(c, "[account_ledger_resource_handler.Read] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return "", err
+	}
+
+	totalTransactionCount := len(transactionsWithAccountBalance)
+
+	if len(transactionsWithAccountBalance) > maxTransactionCountInAccountLedgerResource {
+		transactionsWithAccountBalance = transactionsWithAccountBalance[:maxTransactionCountInAccountLedgerResource]
+	}
+
+	resource := MCPAccountLedgerResource{
+		AccountId:             utils.Int64ToString(account.AccountId),
+		AccountName:           account.Name,
+		Currency:              account.Currency,
+		CurrentBalance:        utils.FormatBigIntAmount(closingBalance),
+		TotalTransactionCount: totalTransactionCount,
+		Transactions:          createNewMCPReconciliationStatementTransactions(c, transactionsWithAccountBalance, accountsMap, services.GetTransactionCategoryService().GetCategoryMapByList(allCategories)),
+	}
+
+	content, err := json.Marshal(resource)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+func getMCPAccountLedgerResourceURI(accountId int64) string {
+	return fmt.Sprintf("%saccounts/%d/ledger", mcpResourceURIPrefix, accountId)
+}
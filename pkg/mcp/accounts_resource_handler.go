@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mcpAccountsResourceURI = mcpResourceURIPrefix + "accounts"
+
+// MCPAccountsResource represents the contents of the accounts resource
+type MCPAccountsResource struct {
+	Accounts []*MCPAccountResourceInfo `json:"accounts"`
+}
+
+// MCPAccountResourceInfo defines the structure of account in the accounts resource
+type MCPAccountResourceInfo struct {
+	Id          string                    `json:"id"`
+	Name        string                    `json:"name"`
+	Category    string                    `json:"category"`
+	Currency    string                    `json:"currency,omitempty"`
+	Balance     string                    `json:"balance,omitempty"`
+	Comment     string                    `json:"comment,omitempty"`
+	LedgerURI   string                    `json:"ledger_uri,omitempty"`
+	SubAccounts []*MCPAccountResourceInfo `json:"sub_accounts,omitempty"`
+}
+
+var mcpAccountCategoryNames = map[models.AccountCategory]string{
+	models.ACCOUNT_CATEGORY_CASH:                   "cash",
+	models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT:       "checking_account",
+	models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT:        "savings_account",
+	models.ACCOUNT_CATEGORY_CREDIT_CARD:            "credit_card",
+	models.ACCOUNT_CATEGORY_VIRTUAL:                "virtual",
+	models.ACCOUNT_CATEGORY_DEBT:                   "debt",
+	models.ACCOUNT_CATEGORY_RECEIVABLES:            "receivable",
+	models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT: "certificate_of_deposit",
+	models.ACCOUNT_CATEGORY_INVESTMENT:             "investment",
+}
+
+type mcpAccountsResourceHandler struct{}
+
+var MCPAccountsResourceHandler = &mcpAccountsResourceHandler{}
+
+// Name returns the name of the MCP resource
+func (h *mcpAccountsResourceHandler) Name() string {
+	return "accounts"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpAccountsResourceHandler) Description() string {
+	return "All visible accounts and sub-accounts of the current user in ezBookkeeping, including the current balance and the ledger uri of each account."
+}
+
+// URITemplate returns the uri template of the MCP resource
+func (h *mcpAccountsResourceHandler) URITemplate() string {
+	return mcpAccountsResourceURI
+}
+
+// MimeType returns the mime type of the MCP resource contents
+func (h *mcpAccountsResourceHandler) MimeType() string {
+	return "application/json"
+}
+
+// RequiredScope returns the mcp token scope required to read the MCP resource
+func (h *mcpAccountsResourceHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_BASIC_DATA
+}
+
+// List returns all the resources of the current user which can be read by the MCP resource handler
+func (h *mcpAccountsResourceHandler) List(c *core.WebContext, user *models.User, services MCPAvailableServices) ([]*MCPResource, error) {
+	return []*MCPResource{
+		{
+			URI:         mcpAccountsResourceURI,
+			Name:        h.Name(),
+			Title:       "Accounts",
+			Description: h.Description(),
+			MimeType:    h.MimeType(),
+		},
+	}, nil
+}
+
+// Read returns the text contents of the MCP resource according to the variables in the uri
+func (h *mcpAccountsResourceHandler) Read(c *core.WebContext, uriVariables map[string]string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (string, error) {
+	uid := user.Uid
+	accounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[accounts_resource_handler.Read] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return "", err
+	}
+
+	resource := MCPAccountsResource{
+		Accounts: make([]*MCPAccountResourceInfo, 0, len(accounts)),
+	}
+
+	parentAccountInfos := make(map[int64]*MCPAccountResourceInfo)
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Hidden {
+			continue
+		}
+
+		accountInfo := &MCPAccountResourceInfo{
+			Id:       utils.Int64ToString(account.AccountId),
+			Name:     account.Name,
+			Category: mcpAccountCategoryNames[account.Category],
+			Comment:  account.Comment,
+		}
+
+		if account.Type == models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			accountInfo.Currency = account.Currency
+			accountInfo.Balance = utils.FormatAmount(account.Balance)
+			accountInfo.LedgerURI = getMCPAccountLedgerResourceURI(account.AccountId)
+		}
+
+		if account.ParentAccountId == models.LevelOneAccountParentId {
+			parentAccountInfos[account.AccountId] = accountInfo
+			resource.Accounts = append(resource.Accounts, accountInfo)
+		} else if parentAccountInfo, exists := parentAccountInfos[account.ParentAccountId]; exists {
+			parentAccountInfo.SubAccounts = append(parentAccountInfo.SubAccounts, accountInfo)
+		}
+	}
+
+	content, err := json.Marshal(resource)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
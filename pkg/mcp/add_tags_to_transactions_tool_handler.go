@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPBatchUpdateTransactionTagsRequest represents all parameters of the batch adding or removing transaction tags request
+type MCPBatchUpdateTransactionTagsRequest struct {
+	TransactionIds []string `json:"transaction_ids" jsonschema_description:"List of unique identifiers of the transactions (they can be obtained from query_transactions tool)"`
+	Tags           []string `json:"tags" jsonschema_description:"List of tag names"`
+}
+
+// MCPBatchUpdateTransactionTagsResponse represents the response structure for batch adding or removing transaction tags
+type MCPBatchUpdateTransactionTagsResponse struct {
+	Success          bool `json:"success" jsonschema_description:"Indicates whether this operation is successful"`
+	TransactionCount int  `json:"transaction_count" jsonschema_description:"Number of transactions which have been updated"`
+}
+
+type mcpAddTagsToTransactionsToolHandler struct{}
+
+var MCPAddTagsToTransactionsToolHandler = &mcpAddTagsToTransactionsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpAddTagsToTransactionsToolHandler) Name() string {
+	return "add_tags_to_transactions"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpAddTagsToTransactionsToolHandler) Description() string {
+	return "Add tags to multiple existing transactions in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpAddTagsToTransactionsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPBatchUpdateTransactionTagsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpAddTagsToTransactionsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPBatchUpdateTransactionTagsResponse{})
+}
+
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpAddTagsToTransactionsToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_WRITE_TRANSACTIONS
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpAddTagsToTransactionsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var batchUpdateRequest MCPBatchUpdateTransactionTagsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &batchUpdateRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	transactions, tagIds, err := getEditableTransactionsAndTagIdsForBatchUpdate(c, user, &batchUpdateRequest, services)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactionIds := make([]int64, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transactionIds[i] = transactions[i].TransactionId
+	}
+
+	transactionTagIndexes, err := services.GetTransactionTagService().GetAllTagIdsOfTransactions(c, uid, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[add_tags_to_transactions_tool_handler.Handle] failed to get transactions tag indexes for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	allNewTransactionTagIndexes := make(map[int64][]int64, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		existedTagIds := transactionTagIndexes[transaction.TransactionId]
+		newTagIds := utils.Int64SliceMinus(tagIds, existedTagIds)
+
+		if len(existedTagIds)+len(newTagIds) > models.MaximumTagsCountOfTransaction {
+			log.Warnf(c, "[add_tags_to_transactions_tool_handler.Handle] transaction \"id:%d\" would have too many tags for user \"uid:%d\"", transaction.TransactionId, uid)
+			return nil, nil, errs.ErrTransactionHasTooManyTags
+		}
+
+		allNewTransactionTagIndexes[transaction.TransactionId] = newTagIds
+	}
+
+	err = services.GetTransactionService().BatchAddTagsToTransactions(c, uid, transactions, allNewTransactionTagIndexes)
+
+	if err != nil {
+		log.Errorf(c, "[add_tags_to_transactions_tool_handler.Handle] failed to batch add transactions tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	log.Infof(c, "[add_tags_to_transactions_tool_handler.Handle] user \"uid:%d\" has batch added tags to %d transactions successfully", uid, len(transactions))
+
+	response := MCPBatchUpdateTransactionTagsResponse{
+		Success:          true,
+		TransactionCount: len(transactions),
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func getEditableTransactionsAndTagIdsForBatchUpdate(c *core.WebContext, user *models.User, batchUpdateRequest *MCPBatchUpdateTransactionTagsRequest, services MCPAvailableServices) ([]*models.Transaction, []int64, error) {
+	if len(batchUpdateRequest.TransactionIds) < 1 || len(batchUpdateRequest.Tags) < 1 {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	transactionIds, err := utils.StringArrayToInt64Array(batchUpdateRequest.TransactionIds)
+
+	if err != nil {
+		log.Warnf(c, "[add_tags_to_transactions_tool_handler.getEditableTransactionsAndTagIdsForBatchUpdate] parse transaction ids failed, because %s", err.Error())
+		return nil, nil, errs.ErrTransactionIdInvalid
+	}
+
+	transactionIds = utils.ToUniqueInt64Slice(transactionIds)
+
+	uid := user.Uid
+	tagIds, err := getVisibleTagIdsByNames(c, uid, batchUpdateRequest.Tags, services)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactions, err := services.GetTransactionService().GetTransactionsByTransactionIds(c, uid, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[add_tags_to_transactions_tool_handler.getEditableTransactionsAndTagIdsForBatchUpdate] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	if len(transactions) != len(transactionIds) {
+		log.Warnf(c, "[add_tags_to_transactions_tool_handler.getEditableTransactionsAndTagIdsForBatchUpdate] some transactions do not exist for user \"uid:%d\"", uid)
+		return nil, nil, errs.ErrTransactionNotFound
+	}
+
+	allAccountIds := make([]int64, 0, len(transactions)*2)
+
+	for i := 0; i < len(transactions); i++ {
+		allAccountIds = append(allAccountIds, transactions[i].AccountId)
+
+		if transactions[i].RelatedAccountId > 0 {
+			allAccountIds = append(allAccountIds, transactions[i].RelatedAccountId)
+		}
+	}
+
+	allUsedAccounts, err := services.GetAccountService().GetAccountsByAccountIds(c, uid, utils.ToUniqueInt64Slice(allAccountIds))
+
+	if err != nil {
+		log.Errorf(c, "[add_tags_to_transactions_tool_handler.getEditableTransactionsAndTagIdsForBatchUpdate] failed to get transaction used accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			log.Warnf(c, "[add_tags_to_transactions_tool_handler.getEditableTransactionsAndTagIdsForBatchUpdate] cannot modify transaction \"id:%d\" for user \"uid:%d\", because transaction type is transfer in", transaction.TransactionId, uid)
+			return nil, nil, errs.ErrTransactionTypeInvalid
+		}
+
+		if !isTransactionEditable(user, transaction, allUsedAccounts) {
+			log.Warnf(c, "[add_tags_to_transactions_tool_handler.getEditableTransactionsAndTagIdsForBatchUpdate] transaction \"id:%d\" is not editable for user \"uid:%d\"", transaction.TransactionId, uid)
+			return nil, nil, errs.ErrCannotModifyTransactionWithThisTransactionTime
+		}
+	}
+
+	return transactions, tagIds, nil
+}
@@ -65,6 +65,11 @@ func (h *mcpAddTransactionToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPAddTransactionResponse{})
 }
 
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpAddTransactionToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_WRITE_TRANSACTIONS
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	var addTransactionRequest MCPAddTransactionRequest
@@ -191,7 +196,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	}
 
 	if !addTransactionRequest.DryRun {
-		err = services.GetTransactionService().CreateTransaction(c, transaction, tagIds, nil)
+		err = services.GetTransactionService().CreateTransaction(c, transaction, tagIds, nil, nil)
 
 		if err != nil {
 			log.Errorf(c, "[add_transaction_tool_handler.Handle] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -315,6 +320,7 @@ func (h *mcpAddTransactionToolHandler) createNewTransactionModel(c *core.WebCont
 		HideAmount:        false,
 		Comment:           addTransactionRequest.Comment,
 		CreatedIp:         clientIp,
+		CreatorUid:        uid,
 	}
 
 	if addTransactionRequest.Type == transactionTypeTransfer {
@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maxTransactionCountInCategorizeUncategorizedTransactionsPrompt = 100
+
+type mcpCategorizeUncategorizedTransactionsPromptHandler struct{}
+
+var MCPCategorizeUncategorizedTransactionsPromptHandler = &mcpCategorizeUncategorizedTransactionsPromptHandler{}
+
+// Name returns the name of the MCP prompt
+func (h *mcpCategorizeUncategorizedTransactionsPromptHandler) Name() string {
+	return "categorize_uncategorized_transactions"
+}
+
+// Description returns the description of the MCP prompt
+func (h *mcpCategorizeUncategorizedTransactionsPromptHandler) Description() string {
+	return "Suggest proper secondary categories for the transactions which are not categorized or are in a catch-all category."
+}
+
+// Arguments returns the arguments of the MCP prompt
+func (h *mcpCategorizeUncategorizedTransactionsPromptHandler) Arguments() []*MCPPromptArgument {
+	return []*MCPPromptArgument{
+		{
+			Name:        "category_name",
+			Description: "Name of the catch-all secondary category whose transactions also need to be categorized (e.g. Other Expense) (optional)",
+		},
+		{
+			Name:        "start_time",
+			Description: "Start time of the transactions in RFC 3339 format (e.g. 2023-01-01T00:00:00Z) (optional, leave empty to start from the first transaction)",
+		},
+		{
+			Name:        "end_time",
+			Description: "End time of the transactions in RFC 3339 format (e.g. 2023-01-31T23:59:59Z) (optional, leave empty to end at the current time)",
+		},
+	}
+}
+
+// RequiredScope returns the mcp token scope required to get the MCP prompt
+func (h *mcpCategorizeUncategorizedTransactionsPromptHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_TRANSACTIONS
+}
+
+// Handle processes the MCP get prompt request and returns the prompt messages
+func (h *mcpCategorizeUncategorizedTransactionsPromptHandler) Handle(c *core.WebContext, getPromptReq *MCPGetPromptRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) ([]*MCPPromptMessage, error) {
+	maxTransactionTime, minTransactionTime, err := parseOptionalTransactionTimeRange(getPromptReq.Arguments["start_time"], getPromptReq.Arguments["end_time"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	uid := user.Uid
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[categorize_uncategorized_transactions_prompt_handler.Handle] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Errorf(c, "[categorize_uncategorized_transactions_prompt_handler.Handle] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	transactions, err := services.GetTransactionService().GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, 0, nil, nil, nil, false, "", "", core.MATCH_MODE_DEFAULT, false, pageCountForLoadTransactions, true)
+
+	if err != nil {
+		log.Errorf(c, "[categorize_uncategorized_transactions_prompt_handler.Handle] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	accountsMap := services.GetAccountService().GetAccountMapByList(allAccounts)
+	categoriesMap := services.GetTransactionCategoryService().GetCategoryMapByList(allCategories)
+	catchAllCategoryName := getPromptReq.Arguments["category_name"]
+	uncategorizedTransactions := make([]*MCPTransactionInfo, 0)
+	totalUncategorizedTransactionCount := 0
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		category := categoriesMap[transaction.CategoryId]
+
+		if !h.isUncategorized(category, categoriesMap, catchAllCategoryName) {
+			continue
+		}
+
+		totalUncategorizedTransactionCount++
+
+		if len(uncategorizedTransactions) >= maxTransactionCountInCategorizeUncategorizedTransactionsPrompt {
+			continue
+		}
+
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		transactionInfo := &MCPTransactionInfo{
+			Id:      utils.Int64ToString(transaction.TransactionId),
+			Time:    utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(transactionUnixTime, transactionTimeZone),
+			Amount:  utils.FormatAmount(transaction.Amount),
+			Comment: transaction.Comment,
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			transactionInfo.Type = transactionTypeIncome
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			transactionInfo.Type = transactionTypeExpense
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			transactionInfo.Type = transactionTypeTransfer
+		}
+
+		if category != nil {
+			transactionInfo.SecondaryCategoryName = category.Name
+		}
+
+		if account, exists := accountsMap[transaction.AccountId]; exists && account != nil {
+			transactionInfo.AccountName = account.Name
+			transactionInfo.Currency = account.Currency
+		}
+
+		uncategorizedTransactions = append(uncategorizedTransactions, transactionInfo)
+	}
+
+	if totalUncategorizedTransactionCount < 1 {
+		return []*MCPPromptMessage{
+			NewMCPUserPromptMessage("All my transactions in the specified time range in ezBookkeeping have already been categorized. Please tell me that there is nothing to categorize."),
+		}, nil
+	}
+
+	categoriesContent, err := json.Marshal(createNewMCPTransactionCategoriesResource(allCategories))
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactionsContent, err := json.Marshal(uncategorizedTransactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*MCPPromptMessage{
+		NewMCPUserPromptMessage(fmt.Sprintf("I have %d transactions in ezBookkeeping which are not properly categorized, the following are %d of them:\n\n%s\n\n"+
+			"These are all my available transaction categories:\n\n%s\n\n"+
+			"Please suggest the most suitable secondary category of the same transaction type (income, expense or transfer) for each transaction according to its description, amount and account, and list your suggestions in a table. "+
+			"After I confirm the suggestions, use the modify_transaction tool to update the category of each transaction.",
+			totalUncategorizedTransactionCount, len(uncategorizedTransactions), string(transactionsContent), string(categoriesContent))),
+	}, nil
+}
+
+func (h *mcpCategorizeUncategorizedTransactionsPromptHandler) isUncategorized(category *models.TransactionCategory, categoriesMap map[int64]*models.TransactionCategory, catchAllCategoryName string) bool {
+	if category == nil || category.Hidden || category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		return true
+	}
+
+	if parentCategory, exists := categoriesMap[category.ParentCategoryId]; !exists || parentCategory == nil || parentCategory.Hidden {
+		return true
+	}
+
+	return catchAllCategoryName != "" && category.Name == catchAllCategoryName
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type mcpExplainAccountBalanceChangePromptHandler struct{}
+
+var MCPExplainAccountBalanceChangePromptHandler = &mcpExplainAccountBalanceChangePromptHandler{}
+
+// Name returns the name of the MCP prompt
+func (h *mcpExplainAccountBalanceChangePromptHandler) Name() string {
+	return "explain_account_balance_change"
+}
+
+// Description returns the description of the MCP prompt
+func (h *mcpExplainAccountBalanceChangePromptHandler) Description() string {
+	return "Explain how and why the balance of an account changed during a period of time."
+}
+
+// Arguments returns the arguments of the MCP prompt
+func (h *mcpExplainAccountBalanceChangePromptHandler) Arguments() []*MCPPromptArgument {
+	return []*MCPPromptArgument{
+		{
+			Name:        "account_name",
+			Description: "Account name (must not be an account containing sub-accounts)",
+			Required:    true,
+		},
+		{
+			Name:        "start_time",
+			Description: "Start time of the period in RFC 3339 format (e.g. 2023-01-01T00:00:00Z) (optional, leave empty to start from the first transaction)",
+		},
+		{
+			Name:        "end_time",
+			Description: "End time of the period in RFC 3339 format (e.g. 2023-01-31T23:59:59Z) (optional, leave empty to end at the current time)",
+		},
+	}
+}
+
+// RequiredScope returns the mcp token scope required to get the MCP prompt
+func (h *mcpExplainAccountBalanceChangePromptHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_TRANSACTIONS
+}
+
+// Handle processes the MCP get prompt request and returns the prompt messages
+func (h *mcpExplainAccountBalanceChangePromptHandler) Handle(c *core.WebContext, getPromptReq *MCPGetPromptRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) ([]*MCPPromptMessage, error) {
+	maxTransactionTime, minTransactionTime, err := parseOptionalTransactionTimeRange(getPromptReq.Arguments["start_time"], getPromptReq.Arguments["end_time"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := getMCPReconciliationStatement(c, user.Uid, getPromptReq.Arguments["account_name"], maxTransactionTime, minTransactionTime, services)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(statement)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*MCPPromptMessage{
+		NewMCPUserPromptMessage(fmt.Sprintf("The balance of my account \"%s\" in ezBookkeeping changed from %s to %s %s. "+
+			"The following data is the reconciliation statement of this account, transactions are in descending order of time and each transaction contains the account balance after it:\n\n%s\n\n"+
+			"Please explain how the balance changed, summarize the main inflows and outflows by category and related account, and point out any unusual transactions or balance modifications that I should check.",
+			statement.AccountName, statement.OpeningBalance, statement.ClosingBalance, statement.Currency, string(content))),
+	}, nil
+}
@@ -32,6 +32,51 @@ type MCPToolHandler[T MCPTextContent | MCPImageContent | MCPAudioContent | MCPRe
 	// OutputType returns the output type for the MCP tool response
 	OutputType() reflect.Type
 
+	// RequiredScope returns the mcp token scope required to use the MCP tool
+	RequiredScope() core.MCPTokenScope
+
 	// Handle processes the MCP call tool request and returns the response
 	Handle(*core.WebContext, *MCPCallToolRequest, *models.User, *settings.Config, MCPAvailableServices) (any, []*T, error)
 }
+
+// MCPResourceHandler defines the MCP resource handler
+type MCPResourceHandler interface {
+	// Name returns the name of the MCP resource
+	Name() string
+
+	// Description returns the description of the MCP resource
+	Description() string
+
+	// URITemplate returns the uri template of the MCP resource, the variables in the template are enclosed in braces
+	URITemplate() string
+
+	// MimeType returns the mime type of the MCP resource contents
+	MimeType() string
+
+	// RequiredScope returns the mcp token scope required to read the MCP resource
+	RequiredScope() core.MCPTokenScope
+
+	// List returns all the resources of the current user which can be read by the MCP resource handler
+	List(*core.WebContext, *models.User, MCPAvailableServices) ([]*MCPResource, error)
+
+	// Read returns the text contents of the MCP resource according to the variables in the uri
+	Read(*core.WebContext, map[string]string, *models.User, *settings.Config, MCPAvailableServices) (string, error)
+}
+
+// MCPPromptHandler defines the MCP prompt handler
+type MCPPromptHandler interface {
+	// Name returns the name of the MCP prompt
+	Name() string
+
+	// Description returns the description of the MCP prompt
+	Description() string
+
+	// Arguments returns the arguments of the MCP prompt
+	Arguments() []*MCPPromptArgument
+
+	// RequiredScope returns the mcp token scope required to get the MCP prompt
+	RequiredScope() core.MCPTokenScope
+
+	// Handle processes the MCP get prompt request and returns the prompt messages
+	Handle(*core.WebContext, *MCPGetPromptRequest, *models.User, *settings.Config, MCPAvailableServices) ([]*MCPPromptMessage, error)
+}
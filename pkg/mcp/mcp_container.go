@@ -1,15 +1,20 @@
 package mcp
 
 import (
+	"strings"
+
 	"github.com/invopop/jsonschema"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
+const mcpProgressTokenFieldKey = "MCP_PROGRESS_TOKEN"
+
 // MCPContainer contains the all mcp handlers
 type MCPContainer struct {
 	mcpTextContentTools      *orderedmap.OrderedMap[string, MCPToolHandler[MCPTextContent]]
@@ -18,6 +23,11 @@ type MCPContainer struct {
 	mcpResourceLinkTools     *orderedmap.OrderedMap[string, MCPToolHandler[MCPResourceLink]]
 	mcpEmbeddedResourceTools *orderedmap.OrderedMap[string, MCPToolHandler[MCPEmbeddedResource]]
 	mcpTools                 []*MCPTool
+	mcpToolRequiredScopes    map[string]core.MCPTokenScope
+	mcpResourceHandlers      *orderedmap.OrderedMap[string, MCPResourceHandler]
+	mcpResourceTemplates     []*MCPResourceTemplate
+	mcpPromptHandlers        *orderedmap.OrderedMap[string, MCPPromptHandler]
+	mcpPrompts               []*MCPPrompt
 }
 
 // Initialize a mcp handler container singleton instance
@@ -34,6 +44,12 @@ func (c *MCPContainer) GetMCPTools() []*MCPTool {
 	return c.mcpTools
 }
 
+// GetMCPToolRequiredScope returns the mcp token scope required to use the specified MCP tool and whether the tool exists
+func (c *MCPContainer) GetMCPToolRequiredScope(name string) (core.MCPTokenScope, bool) {
+	scope, exists := c.mcpToolRequiredScopes[name]
+	return scope, exists
+}
+
 // HandleTool returns the result of the MCP tool handler based on the tool name
 func (c *MCPContainer) HandleTool(ctx *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, error) {
 	if handler, exists := c.mcpTextContentTools.Get(callToolReq.Name); exists {
@@ -59,6 +75,147 @@ func (c *MCPContainer) HandleTool(ctx *core.WebContext, callToolReq *MCPCallTool
 	return nil, errs.ErrApiNotFound
 }
 
+// GetMCPResourceTemplates returns the registered MCP resource templates
+func (c *MCPContainer) GetMCPResourceTemplates() []*MCPResourceTemplate {
+	if len(c.mcpResourceTemplates) == 0 {
+		return nil
+	}
+
+	return c.mcpResourceTemplates
+}
+
+// GetMCPResourceRequiredScope returns the mcp token scope required to read the specified MCP resource and whether the resource exists
+func (c *MCPContainer) GetMCPResourceRequiredScope(name string) (core.MCPTokenScope, bool) {
+	handler, exists := c.mcpResourceHandlers.Get(name)
+
+	if !exists {
+		return "", false
+	}
+
+	return handler.RequiredScope(), true
+}
+
+// GetMCPResourceRequiredScopeByURI returns the mcp token scope required to read the MCP resource of the specified uri and whether the resource exists
+func (c *MCPContainer) GetMCPResourceRequiredScopeByURI(uri string) (core.MCPTokenScope, bool) {
+	handler, _ := c.getMCPResourceHandlerByURI(uri)
+
+	if handler == nil {
+		return "", false
+	}
+
+	return handler.RequiredScope(), true
+}
+
+// ListResources returns all the resources of the current user which can be read with the specified mcp token scopes
+func (c *MCPContainer) ListResources(ctx *core.WebContext, user *models.User, tokenScopes core.MCPTokenScopes, services MCPAvailableServices) ([]*MCPResource, error) {
+	resources := make([]*MCPResource, 0)
+
+	for pair := c.mcpResourceHandlers.Oldest(); pair != nil; pair = pair.Next() {
+		handler := pair.Value
+
+		if !tokenScopes.Contains(handler.RequiredScope()) {
+			continue
+		}
+
+		handlerResources, err := handler.List(ctx, user, services)
+
+		if err != nil {
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		resources = append(resources, handlerResources...)
+	}
+
+	return resources, nil
+}
+
+// ReadResource returns the contents of the MCP resource based on the resource uri
+func (c *MCPContainer) ReadResource(ctx *core.WebContext, readResourceReq *MCPReadResourceRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, error) {
+	handler, uriVariables := c.getMCPResourceHandlerByURI(readResourceReq.URI)
+
+	if handler == nil {
+		return nil, errs.ErrMCPResourceNotFound
+	}
+
+	text, err := handler.Read(ctx, uriVariables, user, currentConfig, services)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	readResourceResp := MCPReadResourceResponse[MCPTextResourceContents]{
+		Contents: []*MCPTextResourceContents{
+			{
+				URI:      readResourceReq.URI,
+				Text:     text,
+				MimeType: handler.MimeType(),
+			},
+		},
+	}
+
+	return readResourceResp, nil
+}
+
+// GetMCPPrompts returns the registered MCP prompts
+func (c *MCPContainer) GetMCPPrompts() []*MCPPrompt {
+	if len(c.mcpPrompts) == 0 {
+		return nil
+	}
+
+	return c.mcpPrompts
+}
+
+// GetMCPPromptRequiredScope returns the mcp token scope required to get the specified MCP prompt and whether the prompt exists
+func (c *MCPContainer) GetMCPPromptRequiredScope(name string) (core.MCPTokenScope, bool) {
+	handler, exists := c.mcpPromptHandlers.Get(name)
+
+	if !exists {
+		return "", false
+	}
+
+	return handler.RequiredScope(), true
+}
+
+// GetPrompt returns the messages of the MCP prompt based on the prompt name
+func (c *MCPContainer) GetPrompt(ctx *core.WebContext, getPromptReq *MCPGetPromptRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, error) {
+	handler, exists := c.mcpPromptHandlers.Get(getPromptReq.Name)
+
+	if !exists {
+		return nil, errs.ErrMCPPromptNotFound
+	}
+
+	arguments := handler.Arguments()
+
+	for i := 0; i < len(arguments); i++ {
+		if arguments[i].Required && getPromptReq.Arguments[arguments[i].Name] == "" {
+			return nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+	}
+
+	messages, err := handler.Handle(ctx, getPromptReq, user, currentConfig, services)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	getPromptResp := MCPGetPromptResponse{
+		Description: handler.Description(),
+		Messages:    messages,
+	}
+
+	return getPromptResp, nil
+}
+
+func (c *MCPContainer) getMCPResourceHandlerByURI(uri string) (MCPResourceHandler, map[string]string) {
+	for pair := c.mcpResourceHandlers.Oldest(); pair != nil; pair = pair.Next() {
+		if uriVariables, matched := matchMCPResourceURITemplate(pair.Value.URITemplate(), uri); matched {
+			return pair.Value, uriVariables
+		}
+	}
+
+	return nil, nil
+}
+
 // InitializeMCPHandlers initializes the all mcp handlers according to the config
 func InitializeMCPHandlers(config *settings.Config) error {
 	container := &MCPContainer{
@@ -68,17 +225,40 @@ func InitializeMCPHandlers(config *settings.Config) error {
 		mcpResourceLinkTools:     orderedmap.New[string, MCPToolHandler[MCPResourceLink]](),
 		mcpEmbeddedResourceTools: orderedmap.New[string, MCPToolHandler[MCPEmbeddedResource]](),
 		mcpTools:                 make([]*MCPTool, 0),
+		mcpToolRequiredScopes:    make(map[string]core.MCPTokenScope),
+		mcpResourceHandlers:      orderedmap.New[string, MCPResourceHandler](),
+		mcpResourceTemplates:     make([]*MCPResourceTemplate, 0),
+		mcpPromptHandlers:        orderedmap.New[string, MCPPromptHandler](),
+		mcpPrompts:               make([]*MCPPrompt, 0),
 	}
 
 	registerMCPTextContentToolHandler(container, MCPAddTransactionToolHandler)
+	registerMCPTextContentToolHandler(container, MCPModifyTransactionToolHandler)
+	registerMCPTextContentToolHandler(container, MCPDeleteTransactionToolHandler)
+	registerMCPTextContentToolHandler(container, MCPAddTagsToTransactionsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPRemoveTagsFromTransactionsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryTransactionsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryReconciliationStatementToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryTransactionStatisticsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryMonthlyTrendsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryAssetTrendsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllAccountsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllAccountsBalanceToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllTransactionCategoriesToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllTransactionTagsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryLatestExchangeRatesToolHandler)
 
+	registerMCPResourceHandler(container, MCPAccountsResourceHandler)
+	registerMCPResourceHandler(container, MCPAccountLedgerResourceHandler)
+	registerMCPResourceHandler(container, MCPTransactionCategoriesResourceHandler)
+
+	registerMCPPromptHandler(container, MCPMonthlySpendingReviewPromptHandler)
+	registerMCPPromptHandler(container, MCPCategorizeUncategorizedTransactionsPromptHandler)
+	registerMCPPromptHandler(container, MCPExplainAccountBalanceChangePromptHandler)
+
 	Container = container
+	Sessions = newMCPSessionContainer(duplicatechecker.Container, config.MCPSessionExpiredTimeDuration)
+
 	return nil
 }
 
@@ -108,10 +288,46 @@ func registerMCPToolHandler[T MCPTextContent | MCPImageContent | MCPAudioContent
 	}
 
 	mcpToolHandlerMap.Set(handler.Name(), handler)
+	c.mcpToolRequiredScopes[handler.Name()] = handler.RequiredScope()
 	c.mcpTools = append(c.mcpTools, createNewMCPToolInfo(handler.Name(), handler))
 }
 
+func registerMCPResourceHandler(c *MCPContainer, handler MCPResourceHandler) {
+	if _, exists := c.mcpResourceHandlers.Get(handler.Name()); exists {
+		return
+	}
+
+	c.mcpResourceHandlers.Set(handler.Name(), handler)
+
+	if strings.Contains(handler.URITemplate(), "{") {
+		c.mcpResourceTemplates = append(c.mcpResourceTemplates, &MCPResourceTemplate{
+			URITemplate: handler.URITemplate(),
+			Name:        handler.Name(),
+			MimeType:    handler.MimeType(),
+			Description: handler.Description(),
+		})
+	}
+}
+
+func registerMCPPromptHandler(c *MCPContainer, handler MCPPromptHandler) {
+	if _, exists := c.mcpPromptHandlers.Get(handler.Name()); exists {
+		return
+	}
+
+	c.mcpPromptHandlers.Set(handler.Name(), handler)
+	c.mcpPrompts = append(c.mcpPrompts, &MCPPrompt{
+		Name:        handler.Name(),
+		Description: handler.Description(),
+		Arguments:   handler.Arguments(),
+	})
+}
+
 func handleTool[T MCPTextContent | MCPImageContent | MCPAudioContent | MCPResourceLink | MCPEmbeddedResource](ctx *core.WebContext, handler MCPToolHandler[T], currentConfig *settings.Config, services MCPAvailableServices, callToolReq *MCPCallToolRequest, user *models.User) (any, error) {
+	if callToolReq.Meta != nil && callToolReq.Meta.ProgressToken != nil {
+		ctx.Set(mcpProgressTokenFieldKey, callToolReq.Meta.ProgressToken)
+		defer ctx.Set(mcpProgressTokenFieldKey, nil)
+	}
+
 	structuredResponse, result, err := handler.Handle(ctx, callToolReq, user, currentConfig, services)
 
 	if err != nil {
@@ -130,6 +346,21 @@ func handleTool[T MCPTextContent | MCPImageContent | MCPAudioContent | MCPResour
 	return callToolResp, nil
 }
 
+func reportToolProgress(ctx *core.WebContext, progress int, total int, message string) {
+	progressToken, exists := ctx.Get(mcpProgressTokenFieldKey)
+
+	if !exists || progressToken == nil {
+		return
+	}
+
+	ctx.SendJSONRPCNotification(core.NewJSONRPCNotification(MCPNotificationMethodProgress, &MCPProgressNotificationParams{
+		ProgressToken: progressToken,
+		Progress:      float64(progress),
+		Total:         float64(total),
+		Message:       message,
+	}))
+}
+
 func createNewMCPToolInfo[T MCPTextContent | MCPImageContent | MCPAudioContent | MCPResourceLink | MCPEmbeddedResource](name string, handler MCPToolHandler[T]) *MCPTool {
 	mcpTool := &MCPTool{
 		Name:        name,
@@ -164,3 +395,31 @@ func createNewMCPToolInfo[T MCPTextContent | MCPImageContent | MCPAudioContent |
 
 	return mcpTool
 }
+
+func matchMCPResourceURITemplate(uriTemplate string, uri string) (map[string]string, bool) {
+	templateItems := strings.Split(uriTemplate, "/")
+	uriItems := strings.Split(uri, "/")
+
+	if len(templateItems) != len(uriItems) {
+		return nil, false
+	}
+
+	variables := make(map[string]string)
+
+	for i := 0; i < len(templateItems); i++ {
+		templateItem := templateItems[i]
+		uriItem := uriItems[i]
+
+		if len(templateItem) > 2 && templateItem[0] == '{' && templateItem[len(templateItem)-1] == '}' {
+			if uriItem == "" {
+				return nil, false
+			}
+
+			variables[templateItem[1:len(templateItem)-1]] = uriItem
+		} else if templateItem != uriItem {
+			return nil, false
+		}
+	}
+
+	return variables, true
+}
@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchMCPResourceURITemplate_StaticURI(t *testing.T) {
+	variables, matched := matchMCPResourceURITemplate("ezbookkeeping://accounts", "ezbookkeeping://accounts")
+	assert.True(t, matched)
+	assert.Equal(t, 0, len(variables))
+
+	_, matched = matchMCPResourceURITemplate("ezbookkeeping://accounts", "ezbookkeeping://transaction-categories")
+	assert.False(t, matched)
+}
+
+func TestMatchMCPResourceURITemplate_URIWithVariables(t *testing.T) {
+	variables, matched := matchMCPResourceURITemplate("ezbookkeeping://accounts/{account_id}/ledger", "ezbookkeeping://accounts/1234567890/ledger")
+	assert.True(t, matched)
+	assert.Equal(t, "1234567890", variables["account_id"])
+}
+
+func TestMatchMCPResourceURITemplate_NotMatched(t *testing.T) {
+	_, matched := matchMCPResourceURITemplate("ezbookkeeping://accounts/{account_id}/ledger", "ezbookkeeping://accounts")
+	assert.False(t, matched)
+
+	_, matched = matchMCPResourceURITemplate("ezbookkeeping://accounts/{account_id}/ledger", "ezbookkeeping://accounts//ledger")
+	assert.False(t, matched)
+
+	_, matched = matchMCPResourceURITemplate("ezbookkeeping://accounts/{account_id}/ledger", "ezbookkeeping://accounts/1234567890/statement")
+	assert.False(t, matched)
+
+	_, matched = matchMCPResourceURITemplate("ezbookkeeping://accounts/{account_id}/ledger", "ezbookkeeping://accounts/1234567890/ledger/2023-01")
+	assert.False(t, matched)
+}
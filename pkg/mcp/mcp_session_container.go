@@ -0,0 +1,217 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mcpSessionIdLength = 32
+const mcpSessionNotificationBufferSize = 16
+const defaultMCPSessionExpiredTime = time.Hour
+
+// MCPSession represents a session of the MCP streamable http transport
+type MCPSession struct {
+	SessionId          string
+	Uid                int64
+	ProtocolVersion    string
+	CreatedUnixTime    int64
+	lastActiveUnixTime int64
+}
+
+// mcpSessionStreams represents the event streams of a mcp session which are opened in current instance
+type mcpSessionStreams struct {
+	uid     int64
+	streams map[int64]chan *core.JSONRPCNotification
+}
+
+// MCPSessionContainer contains all the active mcp sessions, the sessions are saved in the duplicate checker so that they can be shared between multiple instances when using database duplicate checker,
+// but the event streams can only be opened and notified in the instance which the client connects to
+type MCPSessionContainer struct {
+	mutex          sync.Mutex
+	checker        duplicatechecker.DuplicateChecker
+	sessionStreams map[string]*mcpSessionStreams
+	expiredTime    time.Duration
+	nextStreamId   int64
+}
+
+// Initialize a mcp session container singleton instance
+var (
+	Sessions = newMCPSessionContainer(duplicatechecker.Container, defaultMCPSessionExpiredTime)
+)
+
+// CreateSession creates a new mcp session for the specified user and returns the session
+func (c *MCPSessionContainer) CreateSession(uid int64, protocolVersion string) (*MCPSession, error) {
+	sessionId, err := utils.GetRandomNumberOrLetter(mcpSessionIdLength)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	session := &MCPSession{
+		SessionId:          sessionId,
+		Uid:                uid,
+		ProtocolVersion:    protocolVersion,
+		CreatedUnixTime:    now,
+		lastActiveUnixTime: now,
+	}
+
+	c.saveSession(session)
+
+	return session, nil
+}
+
+// GetSession returns the active mcp session of the specified user by the session id and refreshes its last active time, returns nil if the session does not exist or has expired
+func (c *MCPSessionContainer) GetSession(uid int64, sessionId string) *MCPSession {
+	found, remark := c.checker.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_MCP_SESSION, uid, sessionId)
+
+	if !found {
+		c.closeSessionStreams(sessionId)
+		return nil
+	}
+
+	session, err := parseMCPSession(uid, sessionId, remark)
+
+	if err != nil {
+		c.RemoveSession(uid, sessionId)
+		return nil
+	}
+
+	now := time.Now().Unix()
+
+	if now-session.lastActiveUnixTime >= int64(c.expiredTime/time.Second)/4 {
+		session.lastActiveUnixTime = now
+		c.saveSession(session)
+	}
+
+	return session
+}
+
+// RemoveSession removes the mcp session of the specified user by the session id and closes all its event streams in current instance
+func (c *MCPSessionContainer) RemoveSession(uid int64, sessionId string) {
+	c.checker.RemoveSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_MCP_SESSION, uid, sessionId)
+	c.closeSessionStreams(sessionId)
+}
+
+// Subscribe creates a new event stream for the mcp session of the specified user and returns the notification channel and the function to unsubscribe, returns nil if the session does not exist
+func (c *MCPSessionContainer) Subscribe(uid int64, sessionId string) (<-chan *core.JSONRPCNotification, func()) {
+	if c.GetSession(uid, sessionId) == nil {
+		return nil, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	sessionStreams, exists := c.sessionStreams[sessionId]
+
+	if !exists {
+		sessionStreams = &mcpSessionStreams{
+			uid:     uid,
+			streams: make(map[int64]chan *core.JSONRPCNotification),
+		}
+		c.sessionStreams[sessionId] = sessionStreams
+	}
+
+	c.nextStreamId++
+	streamId := c.nextStreamId
+	stream := make(chan *core.JSONRPCNotification, mcpSessionNotificationBufferSize)
+	sessionStreams.streams[streamId] = stream
+
+	return stream, func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		if stream, exists := sessionStreams.streams[streamId]; exists {
+			delete(sessionStreams.streams, streamId)
+			close(stream)
+		}
+
+		if len(sessionStreams.streams) < 1 && c.sessionStreams[sessionId] == sessionStreams {
+			delete(c.sessionStreams, sessionId)
+		}
+	}
+}
+
+// NotifyUser sends the notification to all the event streams of the active mcp sessions of the specified user which are opened in current instance
+func (c *MCPSessionContainer) NotifyUser(uid int64, notification *core.JSONRPCNotification) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, sessionStreams := range c.sessionStreams {
+		if sessionStreams.uid != uid {
+			continue
+		}
+
+		for _, stream := range sessionStreams.streams {
+			select {
+			case stream <- notification:
+			default:
+			}
+		}
+	}
+}
+
+func (c *MCPSessionContainer) saveSession(session *MCPSession) {
+	remark := fmt.Sprintf("%s|%d|%d", session.ProtocolVersion, session.CreatedUnixTime, session.lastActiveUnixTime)
+	c.checker.SetSubmissionRemarkWithCustomExpiration(duplicatechecker.DUPLICATE_CHECKER_TYPE_MCP_SESSION, session.Uid, session.SessionId, remark, c.expiredTime)
+}
+
+func (c *MCPSessionContainer) closeSessionStreams(sessionId string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	sessionStreams, exists := c.sessionStreams[sessionId]
+
+	if !exists {
+		return
+	}
+
+	for streamId, stream := range sessionStreams.streams {
+		delete(sessionStreams.streams, streamId)
+		close(stream)
+	}
+
+	delete(c.sessionStreams, sessionId)
+}
+
+func parseMCPSession(uid int64, sessionId string, remark string) (*MCPSession, error) {
+	items := strings.Split(remark, "|")
+
+	if len(items) != 3 {
+		return nil, fmt.Errorf("invalid mcp session remark \"%s\"", remark)
+	}
+
+	createdUnixTime, err := utils.StringToInt64(items[1])
+
+	if err != nil {
+		return nil, err
+	}
+
+	lastActiveUnixTime, err := utils.StringToInt64(items[2])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &MCPSession{
+		SessionId:          sessionId,
+		Uid:                uid,
+		ProtocolVersion:    items[0],
+		CreatedUnixTime:    createdUnixTime,
+		lastActiveUnixTime: lastActiveUnixTime,
+	}, nil
+}
+
+func newMCPSessionContainer(checker duplicatechecker.DuplicateChecker, expiredTime time.Duration) *MCPSessionContainer {
+	return &MCPSessionContainer{
+		checker:        checker,
+		sessionStreams: make(map[string]*mcpSessionStreams),
+		expiredTime:    expiredTime,
+	}
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestMCPSessionContainer_CreateAndGetSession(t *testing.T) {
+	container := newTestMCPSessionContainer()
+
+	session, err := container.CreateSession(1234567890, "2025-06-18")
+	assert.Nil(t, err)
+	assert.Equal(t, mcpSessionIdLength, len(session.SessionId))
+
+	actualSession := container.GetSession(1234567890, session.SessionId)
+	assert.NotNil(t, actualSession)
+	assert.Equal(t, int64(1234567890), actualSession.Uid)
+	assert.Equal(t, "2025-06-18", actualSession.ProtocolVersion)
+
+	assert.Nil(t, container.GetSession(1234567890, "not-exists"))
+}
+
+func TestMCPSessionContainer_RemoveSession(t *testing.T) {
+	container := newTestMCPSessionContainer()
+
+	session, err := container.CreateSession(1234567890, "2025-06-18")
+	assert.Nil(t, err)
+
+	notifications, _ := container.Subscribe(1234567890, session.SessionId)
+	assert.NotNil(t, notifications)
+
+	container.RemoveSession(1234567890, session.SessionId)
+	assert.Nil(t, container.GetSession(1234567890, session.SessionId))
+
+	_, ok := <-notifications
+	assert.False(t, ok)
+}
+
+func TestMCPSessionContainer_ExpiredSession(t *testing.T) {
+	container := newTestMCPSessionContainer()
+
+	session, err := container.CreateSession(1234567890, "2025-06-18")
+	assert.Nil(t, err)
+
+	notifications, _ := container.Subscribe(1234567890, session.SessionId)
+	assert.NotNil(t, notifications)
+
+	container.checker.RemoveSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_MCP_SESSION, 1234567890, session.SessionId)
+	assert.Nil(t, container.GetSession(1234567890, session.SessionId))
+
+	_, ok := <-notifications
+	assert.False(t, ok)
+}
+
+func TestMCPSessionContainer_GetSessionOfOtherUser(t *testing.T) {
+	container := newTestMCPSessionContainer()
+
+	session, err := container.CreateSession(1234567890, "2025-06-18")
+	assert.Nil(t, err)
+
+	assert.Nil(t, container.GetSession(1234567891, session.SessionId))
+
+	notifications, _ := container.Subscribe(1234567891, session.SessionId)
+	assert.Nil(t, notifications)
+}
+
+func TestMCPSessionContainer_SharedBetweenInstances(t *testing.T) {
+	checker := newTestDuplicateChecker()
+	container := newMCPSessionContainer(checker, time.Hour)
+	otherContainer := newMCPSessionContainer(checker, time.Hour)
+
+	session, err := container.CreateSession(1234567890, "2025-06-18")
+	assert.Nil(t, err)
+
+	actualSession := otherContainer.GetSession(1234567890, session.SessionId)
+	assert.NotNil(t, actualSession)
+	assert.Equal(t, "2025-06-18", actualSession.ProtocolVersion)
+	assert.Equal(t, session.CreatedUnixTime, actualSession.CreatedUnixTime)
+
+	otherContainer.RemoveSession(1234567890, session.SessionId)
+	assert.Nil(t, container.GetSession(1234567890, session.SessionId))
+}
+
+func TestMCPSessionContainer_NotifyUser(t *testing.T) {
+	container := newTestMCPSessionContainer()
+
+	session, err := container.CreateSession(1234567890, "2025-06-18")
+	assert.Nil(t, err)
+
+	otherSession, err := container.CreateSession(1234567891, "2025-06-18")
+	assert.Nil(t, err)
+
+	notifications, unsubscribe := container.Subscribe(1234567890, session.SessionId)
+	otherNotifications, otherUnsubscribe := container.Subscribe(1234567891, otherSession.SessionId)
+
+	container.NotifyUser(1234567890, core.NewJSONRPCNotification(MCPNotificationMethodResourcesListChanged, nil))
+
+	notification := <-notifications
+	assert.Equal(t, MCPNotificationMethodResourcesListChanged, notification.Method)
+	assert.Equal(t, 0, len(otherNotifications))
+
+	unsubscribe()
+	otherUnsubscribe()
+
+	_, ok := <-notifications
+	assert.False(t, ok)
+}
+
+func newTestDuplicateChecker() duplicatechecker.DuplicateChecker {
+	checker, _ := duplicatechecker.NewInMemoryDuplicateChecker(&settings.Config{
+		DuplicateSubmissionsIntervalDuration:            time.Minute,
+		InMemoryDuplicateCheckerCleanupIntervalDuration: time.Minute,
+	})
+
+	return checker
+}
+
+func newTestMCPSessionContainer() *MCPSessionContainer {
+	return newMCPSessionContainer(newTestDuplicateChecker(), time.Hour)
+}
@@ -26,6 +26,20 @@ const ToolResultStructuredContentMinVersion = MCPProtocolVersion20250618
 // MCPProtocolVersionHeaderName defines the HTTP header name for the MCP protocol version
 const MCPProtocolVersionHeaderName = "MCP-Protocol-Version"
 
+// MCPSessionIdHeaderName defines the HTTP header name for the MCP session id
+const MCPSessionIdHeaderName = "Mcp-Session-Id"
+
+// MCP notification methods sent by server
+const (
+	MCPNotificationMethodProgress             = "notifications/progress"
+	MCPNotificationMethodToolsListChanged     = "notifications/tools/list_changed"
+	MCPNotificationMethodResourcesListChanged = "notifications/resources/list_changed"
+	MCPNotificationMethodPromptsListChanged   = "notifications/prompts/list_changed"
+)
+
+// mcpResourceURIPrefix defines the prefix of the uri of all resources provided by the MCP server
+const mcpResourceURIPrefix = "ezbookkeeping://"
+
 // SupportedMCPVersion defines a map of supported MCP versions
 var SupportedMCPVersion = map[MCPProtocolVersion]bool{
 	MCPProtocolVersion20250618: true,
@@ -92,6 +106,21 @@ type MCPResource struct {
 	Description string `json:"description,omitempty"`
 }
 
+// MCPListResourceTemplatesResponse defines the response structure for listing resource templates in the MCP
+type MCPListResourceTemplatesResponse struct {
+	ResourceTemplates []*MCPResourceTemplate `json:"resourceTemplates"`
+	NextCursor        string                 `json:"nextCursor,omitempty"`
+}
+
+// MCPResourceTemplate defines the structure of a resource template in the MCP
+type MCPResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	MimeType    string `json:"mimeType,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
 // MCPReadResourceRequest defines the request structure for reading a resource in the MCP
 type MCPReadResourceRequest struct {
 	URI string `json:"uri"`
@@ -116,6 +145,46 @@ type MCPBlobResourceContents struct {
 	MimeType string `json:"mimeType,omitempty"`
 }
 
+// MCPListPromptsResponse defines the response structure for listing prompts in the MCP
+type MCPListPromptsResponse struct {
+	Prompts    []*MCPPrompt `json:"prompts"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+// MCPPrompt defines the structure of a prompt in the MCP
+type MCPPrompt struct {
+	Name        string               `json:"name"`
+	Title       string               `json:"title,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Arguments   []*MCPPromptArgument `json:"arguments,omitempty"`
+}
+
+// MCPPromptArgument defines the structure of a prompt argument in the MCP
+type MCPPromptArgument struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MCPGetPromptRequest defines the request structure for getting a prompt in the MCP
+type MCPGetPromptRequest struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// MCPGetPromptResponse defines the response structure for getting a prompt in the MCP
+type MCPGetPromptResponse struct {
+	Description string              `json:"description,omitempty"`
+	Messages    []*MCPPromptMessage `json:"messages"`
+}
+
+// MCPPromptMessage defines the structure of a message in the prompt in the MCP
+type MCPPromptMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
 // MCPListToolsResponse defines the response structure for listing tools in the MCP
 type MCPListToolsResponse struct {
 	Tools      []*MCPTool `json:"tools"`
@@ -135,6 +204,20 @@ type MCPTool struct {
 type MCPCallToolRequest struct {
 	Name      string          `json:"name"`
 	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Meta      *MCPRequestMeta `json:"_meta,omitempty"`
+}
+
+// MCPRequestMeta defines the metadata structure of the request in the MCP
+type MCPRequestMeta struct {
+	ProgressToken any `json:"progressToken,omitempty"`
+}
+
+// MCPProgressNotificationParams defines the params structure of the progress notification in the MCP
+type MCPProgressNotificationParams struct {
+	ProgressToken any     `json:"progressToken"`
+	Progress      float64 `json:"progress"`
+	Total         float64 `json:"total,omitempty"`
+	Message       string  `json:"message,omitempty"`
 }
 
 // MCPCallToolResponse defines the response structure for calling a tool in the MCP
@@ -189,6 +272,14 @@ func NewMCPTextContent(text string) *MCPTextContent {
 	}
 }
 
+// NewMCPUserPromptMessage creates a new instance of MCPPromptMessage with the given text sent by user
+func NewMCPUserPromptMessage(text string) *MCPPromptMessage {
+	return &MCPPromptMessage{
+		Role:    "user",
+		Content: NewMCPTextContent(text),
+	}
+}
+
 // NewMCPImageContent creates a new instance of MCPImageContent with the given data and MIME type
 func NewMCPImageContent(data []byte, mimeType string) *MCPImageContent {
 	return &MCPImageContent{
@@ -0,0 +1,361 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPModifyTransactionRequest represents all parameters of the modify transaction request
+type MCPModifyTransactionRequest struct {
+	Id                     string    `json:"id" jsonschema_description:"Unique identifier of the transaction to modify (it can be obtained from query_transactions tool)"`
+	Time                   string    `json:"time,omitempty" jsonschema:"format=date-time" jsonschema_description:"New transaction time in RFC 3339 format (e.g. 2023-01-01T12:00:00Z) (optional, leave empty to keep unchanged)"`
+	SecondaryCategoryName  string    `json:"category_name,omitempty" jsonschema_description:"New secondary category name for the transaction (optional, leave empty to keep unchanged)"`
+	AccountName            string    `json:"account_name,omitempty" jsonschema_description:"New account name for the transaction (optional, leave empty to keep unchanged)"`
+	Amount                 string    `json:"amount,omitempty" jsonschema_description:"New transaction amount (optional, leave empty to keep unchanged)"`
+	DestinationAccountName string    `json:"destination_account_name,omitempty" jsonschema_description:"New destination account name for transfer transactions (optional, leave empty to keep unchanged)"`
+	DestinationAmount      string    `json:"destination_amount,omitempty" jsonschema_description:"New destination amount for transfer transactions (optional, leave empty to keep unchanged)"`
+	Tags                   *[]string `json:"tags,omitempty" jsonschema_description:"New list of tags associated with the transaction, which replaces all the existing tags (optional, leave it out to keep unchanged, set to an empty list to remove all tags, maximum 10 tags allowed)"`
+	Comment                *string   `json:"comment,omitempty" jsonschema_description:"New transaction description (optional, leave it out to keep unchanged)"`
+	DryRun                 bool      `json:"dry_run,omitempty" jsonschema_description:"If true, the transaction will not be saved, only validated (optional)"`
+}
+
+// MCPModifyTransactionResponse represents the response structure for modify transaction
+type MCPModifyTransactionResponse struct {
+	Success bool `json:"success" jsonschema_description:"Indicates whether this operation is successful"`
+	DryRun  bool `json:"dry_run,omitempty" jsonschema_description:"Indicates whether this operation is a dry run (transaction not saved actually)"`
+}
+
+type mcpModifyTransactionToolHandler struct{}
+
+var MCPModifyTransactionToolHandler = &mcpModifyTransactionToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpModifyTransactionToolHandler) Name() string {
+	return "modify_transaction"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpModifyTransactionToolHandler) Description() string {
+	return "Modify an existing income, expense or transfer transaction in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpModifyTransactionToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPModifyTransactionRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpModifyTransactionToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPModifyTransactionResponse{})
+}
+
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpModifyTransactionToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_WRITE_TRANSACTIONS
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpModifyTransactionToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var modifyTransactionRequest MCPModifyTransactionRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &modifyTransactionRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	transactionId, err := utils.StringToInt64(modifyTransactionRequest.Id)
+
+	if err != nil || transactionId <= 0 {
+		return nil, nil, errs.ErrTransactionIdInvalid
+	}
+
+	if modifyTransactionRequest.Tags != nil && len(*modifyTransactionRequest.Tags) > models.MaximumTagsCountOfTransaction {
+		return nil, nil, errs.ErrTransactionHasTooManyTags
+	}
+
+	uid := user.Uid
+	transaction, err := services.GetTransactionService().GetTransactionByTransactionId(c, uid, transactionId)
+
+	if err != nil {
+		log.Warnf(c, "[modify_transaction_tool_handler.Handle] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+		return nil, nil, err
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		log.Warnf(c, "[modify_transaction_tool_handler.Handle] cannot modify transaction \"id:%d\" with type \"%d\" for user \"uid:%d\"", transactionId, transaction.Type, uid)
+		return nil, nil, errs.ErrTransactionTypeInvalid
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT && (modifyTransactionRequest.DestinationAccountName != "" || modifyTransactionRequest.DestinationAmount != "") {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	newTransaction := &models.Transaction{
+		TransactionId:     transaction.TransactionId,
+		Uid:               uid,
+		Type:              transaction.Type,
+		CategoryId:        transaction.CategoryId,
+		TransactionTime:   transaction.TransactionTime,
+		TimezoneUtcOffset: transaction.TimezoneUtcOffset,
+		AccountId:         transaction.AccountId,
+		Amount:            transaction.Amount,
+		HideAmount:        transaction.HideAmount,
+		Comment:           transaction.Comment,
+		GeoLongitude:      transaction.GeoLongitude,
+		GeoLatitude:       transaction.GeoLatitude,
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		newTransaction.RelatedAccountId = transaction.RelatedAccountId
+		newTransaction.RelatedAccountAmount = transaction.RelatedAccountAmount
+	}
+
+	if modifyTransactionRequest.AccountName != "" || modifyTransactionRequest.DestinationAccountName != "" {
+		allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+		if err != nil {
+			log.Warnf(c, "[modify_transaction_tool_handler.Handle] get account error, because %s", err.Error())
+			return nil, nil, err
+		}
+
+		accountsMap := services.GetAccountService().GetVisibleAccountNameMapByList(allAccounts)
+
+		if modifyTransactionRequest.AccountName != "" {
+			sourceAccount, exists := accountsMap[modifyTransactionRequest.AccountName]
+
+			if !exists {
+				log.Warnf(c, "[modify_transaction_tool_handler.Handle] source account \"%s\" not found for user \"uid:%d\"", modifyTransactionRequest.AccountName, uid)
+				return nil, nil, errs.ErrSourceAccountNotFound
+			}
+
+			newTransaction.AccountId = sourceAccount.AccountId
+		}
+
+		if modifyTransactionRequest.DestinationAccountName != "" {
+			destinationAccount, exists := accountsMap[modifyTransactionRequest.DestinationAccountName]
+
+			if !exists {
+				log.Warnf(c, "[modify_transaction_tool_handler.Handle] destination account \"%s\" not found for user \"uid:%d\"", modifyTransactionRequest.DestinationAccountName, uid)
+				return nil, nil, errs.ErrDestinationAccountNotFound
+			}
+
+			newTransaction.RelatedAccountId = destinationAccount.AccountId
+		}
+	}
+
+	if modifyTransactionRequest.SecondaryCategoryName != "" {
+		allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+
+		if err != nil {
+			log.Warnf(c, "[modify_transaction_tool_handler.Handle] get transaction category error, because %s", err.Error())
+			return nil, nil, err
+		}
+
+		transactionCategory := getVisibleSecondaryCategoryByName(allCategories, modifyTransactionRequest.SecondaryCategoryName, transaction.Type)
+
+		if transactionCategory == nil {
+			log.Warnf(c, "[modify_transaction_tool_handler.Handle] secondary category \"%s\" not found for user \"uid:%d\"", modifyTransactionRequest.SecondaryCategoryName, uid)
+			return nil, nil, errs.ErrTransactionCategoryNotFound
+		}
+
+		newTransaction.CategoryId = transactionCategory.CategoryId
+	}
+
+	if modifyTransactionRequest.Time != "" {
+		transactionTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(modifyTransactionRequest.Time)
+
+		if err != nil {
+			log.Warnf(c, "[modify_transaction_tool_handler.Handle] parse transaction time \"%s\" error, because %s", modifyTransactionRequest.Time, err.Error())
+			return nil, nil, errs.ErrTransactionTimeInvalid
+		}
+
+		newTransaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix())
+		newTransaction.TimezoneUtcOffset = utils.GetTimezoneOffsetMinutes(transactionTime.Unix(), transactionTime.Location())
+	}
+
+	if modifyTransactionRequest.Amount != "" {
+		amount, err := parseTransactionAmount(c, modifyTransactionRequest.Amount)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		newTransaction.Amount = amount
+	}
+
+	if modifyTransactionRequest.DestinationAmount != "" {
+		destinationAmount, err := parseTransactionAmount(c, modifyTransactionRequest.DestinationAmount)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		newTransaction.RelatedAccountAmount = destinationAmount
+	}
+
+	if modifyTransactionRequest.Comment != nil {
+		newTransaction.Comment = *modifyTransactionRequest.Comment
+	}
+
+	allTransactionTagIds, err := services.GetTransactionTagService().GetAllTagIdsOfTransactions(c, uid, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.Errorf(c, "[modify_transaction_tool_handler.Handle] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
+
+	if transactionTagIds == nil {
+		transactionTagIds = make([]int64, 0)
+	}
+
+	tagIds := transactionTagIds
+
+	if modifyTransactionRequest.Tags != nil {
+		tagIds, err = getVisibleTagIdsByNames(c, uid, *modifyTransactionRequest.Tags, services)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if newTransaction.CategoryId == transaction.CategoryId &&
+		utils.GetUnixTimeFromTransactionTime(newTransaction.TransactionTime) == utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) &&
+		newTransaction.TimezoneUtcOffset == transaction.TimezoneUtcOffset &&
+		newTransaction.AccountId == transaction.AccountId &&
+		newTransaction.Amount == transaction.Amount &&
+		newTransaction.RelatedAccountId == transaction.RelatedAccountId &&
+		newTransaction.RelatedAccountAmount == transaction.RelatedAccountAmount &&
+		newTransaction.Comment == transaction.Comment &&
+		utils.Int64SliceEquals(tagIds, transactionTagIds) {
+		return nil, nil, errs.ErrNothingWillBeUpdated
+	}
+
+	allUsedAccounts, err := services.GetAccountService().GetAccountsByAccountIds(c, uid, utils.ToUniqueInt64Slice([]int64{transaction.AccountId, transaction.RelatedAccountId, newTransaction.AccountId, newTransaction.RelatedAccountId}))
+
+	if err != nil {
+		log.Errorf(c, "[modify_transaction_tool_handler.Handle] failed to get transaction used accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	if !isTransactionEditable(user, transaction, allUsedAccounts) || !isTransactionEditable(user, newTransaction, allUsedAccounts) {
+		return nil, nil, errs.ErrCannotModifyTransactionWithThisTransactionTime
+	}
+
+	if !modifyTransactionRequest.DryRun {
+		var addTransactionTagIds []int64
+		var removeTransactionTagIds []int64
+
+		if !utils.Int64SliceEquals(tagIds, transactionTagIds) {
+			removeTransactionTagIds = transactionTagIds
+			addTransactionTagIds = tagIds
+		}
+
+		err = services.GetTransactionService().ModifyTransaction(c, newTransaction, false, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, nil, nil, nil)
+
+		if err != nil {
+			log.Errorf(c, "[modify_transaction_tool_handler.Handle] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+			return nil, nil, err
+		}
+
+		log.Infof(c, "[modify_transaction_tool_handler.Handle] user \"uid:%d\" has updated transaction \"id:%d\" successfully", uid, transactionId)
+	}
+
+	response := MCPModifyTransactionResponse{
+		Success: true,
+		DryRun:  modifyTransactionRequest.DryRun,
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func getVisibleSecondaryCategoryByName(allCategories []*models.TransactionCategory, categoryName string, transactionType models.TransactionDbType) *models.TransactionCategory {
+	for i := 0; i < len(allCategories); i++ {
+		category := allCategories[i]
+
+		if category.Hidden || category.ParentCategoryId == models.LevelOneTransactionCategoryParentId || category.Name != categoryName {
+			continue
+		}
+
+		if category.Type == models.CATEGORY_TYPE_INCOME && transactionType == models.TRANSACTION_DB_TYPE_INCOME {
+			return category
+		} else if category.Type == models.CATEGORY_TYPE_EXPENSE && transactionType == models.TRANSACTION_DB_TYPE_EXPENSE {
+			return category
+		} else if category.Type == models.CATEGORY_TYPE_TRANSFER && transactionType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			return category
+		}
+	}
+
+	return nil
+}
+
+func getVisibleTagIdsByNames(c *core.WebContext, uid int64, tagNames []string, services MCPAvailableServices) ([]int64, error) {
+	tagIds := make([]int64, 0, len(tagNames))
+
+	if len(tagNames) < 1 {
+		return tagIds, nil
+	}
+
+	allTags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[modify_transaction_tool_handler.getVisibleTagIdsByNames] get transaction tags error, because %s", err.Error())
+		return nil, err
+	}
+
+	tagMaps := services.GetTransactionTagService().GetVisibleTagNameMapByList(allTags)
+
+	for i := 0; i < len(tagNames); i++ {
+		tag, exists := tagMaps[tagNames[i]]
+
+		if !exists {
+			log.Warnf(c, "[modify_transaction_tool_handler.getVisibleTagIdsByNames] transaction tag \"%s\" not found for user \"uid:%d\"", tagNames[i], uid)
+			return nil, errs.ErrTransactionTagNotFound
+		}
+
+		tagIds = append(tagIds, tag.TagId)
+	}
+
+	return utils.ToUniqueInt64Slice(tagIds), nil
+}
+
+func parseTransactionAmount(c *core.WebContext, textualAmount string) (int64, error) {
+	amount, err := utils.ParseAmount(textualAmount)
+
+	if err != nil {
+		log.Warnf(c, "[modify_transaction_tool_handler.parseTransactionAmount] parse transaction amount \"%s\" error, because %s", textualAmount, err.Error())
+		return 0, errs.ErrAmountInvalid
+	}
+
+	if amount < models.MinimumTransactionAmount || amount > models.MaximumTransactionAmount {
+		log.Warnf(c, "[modify_transaction_tool_handler.parseTransactionAmount] transaction amount \"%s\" is out of range", textualAmount)
+		return 0, errs.ErrAmountInvalid
+	}
+
+	return amount, nil
+}
+
+func isTransactionEditable(user *models.User, transaction *models.Transaction, accountsMap map[int64]*models.Account) bool {
+	transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+	return user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transactionTimeZone, accountsMap[transaction.AccountId], accountsMap[transaction.RelatedAccountId])
+}
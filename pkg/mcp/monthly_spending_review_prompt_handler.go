@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+type mcpMonthlySpendingReviewPromptHandler struct{}
+
+var MCPMonthlySpendingReviewPromptHandler = &mcpMonthlySpendingReviewPromptHandler{}
+
+// Name returns the name of the MCP prompt
+func (h *mcpMonthlySpendingReviewPromptHandler) Name() string {
+	return "monthly_spending_review"
+}
+
+// Description returns the description of the MCP prompt
+func (h *mcpMonthlySpendingReviewPromptHandler) Description() string {
+	return "Review the income and expense of a month and compare them with the previous month."
+}
+
+// Arguments returns the arguments of the MCP prompt
+func (h *mcpMonthlySpendingReviewPromptHandler) Arguments() []*MCPPromptArgument {
+	return []*MCPPromptArgument{
+		{
+			Name:        "month",
+			Description: "Month to review in YYYY-MM format (e.g. 2023-01) (optional, leave empty to review the current month)",
+		},
+		{
+			Name:        "timezone",
+			Description: "IANA timezone name used to determine which month a transaction belongs to (e.g. America/New_York) (optional, leave empty to use the timezone of every transaction itself)",
+		},
+	}
+}
+
+// RequiredScope returns the mcp token scope required to get the MCP prompt
+func (h *mcpMonthlySpendingReviewPromptHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_STATISTICS
+}
+
+// Handle processes the MCP get prompt request and returns the prompt messages
+func (h *mcpMonthlySpendingReviewPromptHandler) Handle(c *core.WebContext, getPromptReq *MCPGetPromptRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) ([]*MCPPromptMessage, error) {
+	timezone, useTransactionTimezone, err := parseOptionalTimezone(c, getPromptReq.Arguments["timezone"])
+
+	if err != nil {
+		return nil, err
+	}
+
+	var year, month int32
+
+	if getPromptReq.Arguments["month"] != "" {
+		year, month, err = utils.ParseNumericYearMonth(getPromptReq.Arguments["month"])
+
+		if err != nil || year <= 0 || month < 1 || month > 12 {
+			return nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+	} else {
+		now := time.Now().In(timezone)
+		year = int32(now.Year())
+		month = int32(now.Month())
+	}
+
+	previousYear, previousMonth := year, month-1
+
+	if previousMonth < 1 {
+		previousYear, previousMonth = year-1, 12
+	}
+
+	monthlyTrends, err := getMCPMonthlyTrends(c, user.Uid, previousYear, previousMonth, year, month, "", core.MATCH_MODE_DEFAULT, timezone, useTransactionTimezone, services)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(monthlyTrends.Months); i++ {
+		items := make([]*MCPTransactionStatisticItem, 0, len(monthlyTrends.Months[i].Items))
+
+		for j := 0; j < len(monthlyTrends.Months[i].Items); j++ {
+			item := monthlyTrends.Months[i].Items[j]
+
+			if item.Type == transactionTypeIncome || item.Type == transactionTypeExpense {
+				items = append(items, item)
+			}
+		}
+
+		monthlyTrends.Months[i].Items = items
+	}
+
+	content, err := json.Marshal(monthlyTrends)
+
+	if err != nil {
+		return nil, err
+	}
+
+	currentMonthText := fmt.Sprintf("%04d-%02d", year, month)
+	previousMonthText := fmt.Sprintf("%04d-%02d", previousYear, previousMonth)
+
+	return []*MCPPromptMessage{
+		NewMCPUserPromptMessage(fmt.Sprintf("Please review my spending in %s recorded in ezBookkeeping. "+
+			"The following data contains the total income and expense of %s and %s, grouped by category and account, all amounts are in the currency of the account:\n\n%s\n\n"+
+			"Please summarize my income and expense by category in %s, compare them with %s, point out the categories which changed significantly, and give some suggestions to reduce unnecessary expenses.",
+			currentMonthText, previousMonthText, currentMonthText, string(content), currentMonthText, previousMonthText)),
+	}, nil
+}
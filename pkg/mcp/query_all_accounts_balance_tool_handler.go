@@ -58,6 +58,11 @@ func (h *mcpQueryAllAccountsBalanceToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPQueryAllAccountsBalanceResponse{})
 }
 
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpQueryAllAccountsBalanceToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_BASIC_DATA
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllAccountsBalanceToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	uid := user.Uid
@@ -41,6 +41,11 @@ func (h *mcpQueryAllTransactionCategoriesToolHandler) OutputType() reflect.Type
 	return reflect.TypeOf(&MCPQueryAllTransactionCategoriesResponse{})
 }
 
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpQueryAllTransactionCategoriesToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_BASIC_DATA
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllTransactionCategoriesToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	uid := user.Uid
@@ -39,6 +39,11 @@ func (h *mcpQueryAllTransactionTagsToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPAllQueryTransactionTagsResponse{})
 }
 
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpQueryAllTransactionTagsToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_BASIC_DATA
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllTransactionTagsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	uid := user.Uid
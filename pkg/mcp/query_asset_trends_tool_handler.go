@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPQueryAssetTrendsRequest represents all parameters of the query asset trends request
+type MCPQueryAssetTrendsRequest struct {
+	StartTime string `json:"start_time" jsonschema:"format=date-time" jsonschema_description:"Start time for the trends in RFC 3339 format (e.g. 2023-01-01T00:00:00Z), the timezone of start time is used to determine which day a transaction belongs to"`
+	EndTime   string `json:"end_time" jsonschema:"format=date-time" jsonschema_description:"End time for the trends in RFC 3339 format (e.g. 2023-12-31T23:59:59Z)"`
+}
+
+// MCPQueryAssetTrendsResponse represents the response structure for querying asset trends
+type MCPQueryAssetTrendsResponse struct {
+	Days []*MCPAssetTrendsDailyItem `json:"days" jsonschema_description:"List of days on which account balances changed, in ascending order of date"`
+}
+
+// MCPAssetTrendsDailyItem defines the structure of account balances in one day
+type MCPAssetTrendsDailyItem struct {
+	Date     string                       `json:"date" jsonschema_description:"Date in YYYY-MM-DD format"`
+	Accounts []*MCPAssetTrendsAccountItem `json:"accounts" jsonschema_description:"List of accounts whose balance changed on this day"`
+}
+
+// MCPAssetTrendsAccountItem defines the structure of account opening and closing balance in one day
+type MCPAssetTrendsAccountItem struct {
+	AccountName    string `json:"account_name" jsonschema_description:"Account name"`
+	OpeningBalance string `json:"opening_balance" jsonschema_description:"Account balance at the beginning of the day (negative value indicates amount owed)"`
+	ClosingBalance string `json:"closing_balance" jsonschema_description:"Account balance at the end of the day (negative value indicates amount owed)"`
+	Currency       string `json:"currency" jsonschema_description:"Currency code of the account (e.g. USD, EUR)"`
+}
+
+type mcpQueryAssetTrendsToolHandler struct{}
+
+var MCPQueryAssetTrendsToolHandler = &mcpQueryAssetTrendsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryAssetTrendsToolHandler) Name() string {
+	return "query_asset_trends"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryAssetTrendsToolHandler) Description() string {
+	return "Query daily opening and closing balance of every account within a time range in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryAssetTrendsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryAssetTrendsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryAssetTrendsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryAssetTrendsResponse{})
+}
+
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpQueryAssetTrendsToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_STATISTICS
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryAssetTrendsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryAssetTrendsRequest MCPQueryAssetTrendsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryAssetTrendsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	startTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(queryAssetTrendsRequest.StartTime)
+
+	if err != nil {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	endTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(queryAssetTrendsRequest.EndTime)
+
+	if err != nil {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(endTime.Unix())
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(startTime.Unix())
+
+	accountDailyBalances, err := services.GetTransactionService().GetAllAccountsDailyOpeningAndClosingBalance(c, uid, maxTransactionTime, minTransactionTime, startTime.Location())
+
+	if err != nil {
+		log.Errorf(c, "[query_asset_trends_tool_handler.Handle] failed to get accounts daily balance for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[query_asset_trends_tool_handler.Handle] get account error, because %s", err.Error())
+		return nil, nil, err
+	}
+
+	accountsMap := services.GetAccountService().GetAccountMapByList(allAccounts)
+	yearMonthDays := make([]int32, 0, len(accountDailyBalances))
+
+	for yearMonthDay := range accountDailyBalances {
+		yearMonthDays = append(yearMonthDays, yearMonthDay)
+	}
+
+	sort.Slice(yearMonthDays, func(i, j int) bool {
+		return yearMonthDays[i] < yearMonthDays[j]
+	})
+
+	response := MCPQueryAssetTrendsResponse{
+		Days: make([]*MCPAssetTrendsDailyItem, len(yearMonthDays)),
+	}
+
+	for i := 0; i < len(yearMonthDays); i++ {
+		yearMonthDay := yearMonthDays[i]
+		dailyAccountBalances := accountDailyBalances[yearMonthDay]
+		dailyItem := &MCPAssetTrendsDailyItem{
+			Date:     utils.FormatNumericYearMonthDayToLongDate(yearMonthDay),
+			Accounts: make([]*MCPAssetTrendsAccountItem, 0, len(dailyAccountBalances)),
+		}
+
+		for j := 0; j < len(dailyAccountBalances); j++ {
+			accountBalance := dailyAccountBalances[j]
+			account, exists := accountsMap[accountBalance.AccountId]
+
+			if !exists || account == nil {
+				log.Warnf(c, "[query_asset_trends_tool_handler.Handle] account \"id:%d\" of user \"uid:%d\" does not exist", accountBalance.AccountId, uid)
+				continue
+			}
+
+			dailyItem.Accounts = append(dailyItem.Accounts, &MCPAssetTrendsAccountItem{
+				AccountName:    account.Name,
+				OpeningBalance: utils.FormatBigIntAmount(accountBalance.AccountOpeningBalance),
+				ClosingBalance: utils.FormatBigIntAmount(accountBalance.AccountClosingBalance),
+				Currency:       account.Currency,
+			})
+		}
+
+		response.Days[i] = dailyItem
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
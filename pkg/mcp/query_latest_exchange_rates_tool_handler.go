@@ -56,6 +56,11 @@ func (h *mcpQueryLatestExchangeRatesToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPQueryExchangeRatesResponse{})
 }
 
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpQueryLatestExchangeRatesToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_BASIC_DATA
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryLatestExchangeRatesToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	var exchangeRatesRequest MCPQueryExchangeRatesRequest
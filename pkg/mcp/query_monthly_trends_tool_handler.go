@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// MCPQueryMonthlyTrendsRequest represents all parameters of the query monthly trends request
+type MCPQueryMonthlyTrendsRequest struct {
+	StartMonth string `json:"start_month" jsonschema:"pattern=^[0-9]{4}-[0-9]{1,2}$" jsonschema_description:"Start month of the trends in YYYY-MM format (e.g. 2023-01)"`
+	EndMonth   string `json:"end_month" jsonschema:"pattern=^[0-9]{4}-[0-9]{1,2}$" jsonschema_description:"End month of the trends in YYYY-MM format (e.g. 2023-12)"`
+	Timezone   string `json:"timezone,omitempty" jsonschema_description:"IANA timezone name used to determine which month a transaction belongs to (e.g. America/New_York) (optional, leave empty to use the timezone of every transaction itself)"`
+	Keyword    string `json:"keyword,omitempty" jsonschema_description:"Keyword to filter transactions by description (optional)"`
+	MatchMode  string `json:"match_mode,omitempty" jsonschema:"enum=default,enum=ignore_case" jsonschema_description:"Match mode for keyword search (optional, leave empty for database default setting, ignore_case for case-insensitive search)"`
+}
+
+// MCPQueryMonthlyTrendsResponse represents the response structure for querying monthly trends
+type MCPQueryMonthlyTrendsResponse struct {
+	Months []*MCPMonthlyTrendsItem `json:"months" jsonschema_description:"List of monthly statistics in ascending order of month"`
+}
+
+// MCPMonthlyTrendsItem defines the structure of statistics in one month
+type MCPMonthlyTrendsItem struct {
+	Month string                         `json:"month" jsonschema_description:"Month of the statistics in YYYY-MM format"`
+	Items []*MCPTransactionStatisticItem `json:"items" jsonschema_description:"Total inflows and outflows in this month grouped by transaction type, category and account"`
+}
+
+type mcpQueryMonthlyTrendsToolHandler struct{}
+
+var MCPQueryMonthlyTrendsToolHandler = &mcpQueryMonthlyTrendsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryMonthlyTrendsToolHandler) Name() string {
+	return "query_monthly_trends"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryMonthlyTrendsToolHandler) Description() string {
+	return "Query monthly total income, expense and transfer amounts grouped by category and account in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryMonthlyTrendsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryMonthlyTrendsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryMonthlyTrendsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryMonthlyTrendsResponse{})
+}
+
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpQueryMonthlyTrendsToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_STATISTICS
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryMonthlyTrendsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryMonthlyTrendsRequest MCPQueryMonthlyTrendsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryMonthlyTrendsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	yearMonthRange := &models.YearMonthRangeRequest{
+		StartYearMonth: queryMonthlyTrendsRequest.StartMonth,
+		EndYearMonth:   queryMonthlyTrendsRequest.EndMonth,
+	}
+
+	startYear, startMonth, endYear, endMonth, err := yearMonthRange.GetNumericYearMonthRange()
+
+	if err != nil || startYear <= 0 || startMonth <= 0 || endYear <= 0 || endMonth <= 0 {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	timezone, useTransactionTimezone, err := parseOptionalTimezone(c, queryMonthlyTrendsRequest.Timezone)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matchModeType := core.MATCH_MODE_DEFAULT
+
+	if queryMonthlyTrendsRequest.MatchMode == "ignore_case" {
+		matchModeType = core.MATCH_MODE_IGNORE_CASE
+	}
+
+	response, err := getMCPMonthlyTrends(c, user.Uid, startYear, startMonth, endYear, endMonth, queryMonthlyTrendsRequest.Keyword, matchModeType, timezone, useTransactionTimezone, services)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func parseOptionalTimezone(c *core.WebContext, timezoneName string) (*time.Location, bool, error) {
+	if timezoneName == "" {
+		return time.UTC, true, nil
+	}
+
+	timezone, err := time.LoadLocation(timezoneName)
+
+	if err != nil {
+		log.Warnf(c, "[query_monthly_trends_tool_handler.parseOptionalTimezone] cannot load timezone \"%s\", because %s", timezoneName, err.Error())
+		return nil, false, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	return timezone, false, nil
+}
+
+func getMCPMonthlyTrends(c *core.WebContext, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, keyword string, matchModeType core.MatchMode, timezone *time.Location, useTransactionTimezone bool, services MCPAvailableServices) (*MCPQueryMonthlyTrendsResponse, error) {
+	allMonthlyTotalAmounts, err := services.GetTransactionService().GetAccountsAndCategoriesMonthlyInflowAndOutflow(c, uid, startYear, startMonth, endYear, endMonth, nil, false, keyword, matchModeType, timezone, useTransactionTimezone, nil)
+
+	if err != nil {
+		log.Errorf(c, "[query_monthly_trends_tool_handler.getMCPMonthlyTrends] failed to get accounts and categories monthly income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[query_monthly_trends_tool_handler.getMCPMonthlyTrends] get account error, because %s", err.Error())
+		return nil, err
+	}
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Warnf(c, "[query_monthly_trends_tool_handler.getMCPMonthlyTrends] get transaction category error, because %s", err.Error())
+		return nil, err
+	}
+
+	accountsMap := services.GetAccountService().GetAccountMapByList(allAccounts)
+	categoriesMap := services.GetTransactionCategoryService().GetCategoryMapByList(allCategories)
+	yearMonths := make([]int32, 0, len(allMonthlyTotalAmounts))
+
+	for yearMonth := range allMonthlyTotalAmounts {
+		yearMonths = append(yearMonths, yearMonth)
+	}
+
+	sort.Slice(yearMonths, func(i, j int) bool {
+		return yearMonths[i] < yearMonths[j]
+	})
+
+	response := &MCPQueryMonthlyTrendsResponse{
+		Months: make([]*MCPMonthlyTrendsItem, len(yearMonths)),
+	}
+
+	for i := 0; i < len(yearMonths); i++ {
+		yearMonth := yearMonths[i]
+		response.Months[i] = &MCPMonthlyTrendsItem{
+			Month: fmt.Sprintf("%04d-%02d", yearMonth/100, yearMonth%100),
+			Items: createNewMCPTransactionStatisticItems(c, allMonthlyTotalAmounts[yearMonth], accountsMap, categoriesMap),
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,238 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPQueryReconciliationStatementRequest represents all parameters of the query reconciliation statement request
+type MCPQueryReconciliationStatementRequest struct {
+	AccountName string `json:"account_name" jsonschema_description:"Account name for the reconciliation statement (must not be an account containing sub-accounts)"`
+	StartTime   string `json:"start_time,omitempty" jsonschema:"format=date-time" jsonschema_description:"Start time for the statement in RFC 3339 format (e.g. 2023-01-01T00:00:00Z) (optional, leave empty to start from the first transaction)"`
+	EndTime     string `json:"end_time,omitempty" jsonschema:"format=date-time" jsonschema_description:"End time for the statement in RFC 3339 format (e.g. 2023-01-31T23:59:59Z) (optional, leave empty to end at the current time)"`
+}
+
+// MCPQueryReconciliationStatementResponse represents the response structure for querying reconciliation statement
+type MCPQueryReconciliationStatementResponse struct {
+	AccountName    string                                   `json:"account_name" jsonschema_description:"Account name of the statement"`
+	Currency       string                                   `json:"currency" jsonschema_description:"Currency code of the account (e.g. USD, EUR)"`
+	OpeningBalance string                                   `json:"opening_balance" jsonschema_description:"Account balance before the first transaction in the statement"`
+	ClosingBalance string                                   `json:"closing_balance" jsonschema_description:"Account balance after the last transaction in the statement"`
+	TotalInflows   string                                   `json:"total_inflows" jsonschema_description:"Total inflows of the account in the statement"`
+	TotalOutflows  string                                   `json:"total_outflows" jsonschema_description:"Total outflows of the account in the statement"`
+	Transactions   []*MCPReconciliationStatementTransaction `json:"transactions" jsonschema_description:"List of transactions in the statement in descending order of time"`
+}
+
+// MCPReconciliationStatementTransaction defines the structure of transaction in reconciliation statement
+type MCPReconciliationStatementTransaction struct {
+	Id                 string `json:"id" jsonschema_description:"Unique identifier of the transaction (for transfer in transactions, this is the identifier of the transfer transaction)"`
+	Time               string `json:"time" jsonschema_description:"Time of the transaction in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
+	Type               string `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer_out,enum=transfer_in,enum=balance_modification" jsonschema_description:"Transaction type (income, expense, transfer_out, transfer_in, balance_modification)"`
+	Amount             string `json:"amount" jsonschema_description:"Amount of the transaction in the account currency"`
+	CategoryName       string `json:"category_name,omitempty" jsonschema_description:"Secondary category name of the transaction"`
+	RelatedAccountName string `json:"related_account_name,omitempty" jsonschema_description:"Destination account name for transfer out transactions, or source account name for transfer in transactions (optional)"`
+	Comment            string `json:"comment,omitempty" jsonschema_description:"Description of the transaction"`
+	ClosingBalance     string `json:"closing_balance" jsonschema_description:"Account balance after this transaction"`
+}
+
+type mcpQueryReconciliationStatementToolHandler struct{}
+
+var MCPQueryReconciliationStatementToolHandler = &mcpQueryReconciliationStatementToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryReconciliationStatementToolHandler) Name() string {
+	return "query_reconciliation_statement"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryReconciliationStatementToolHandler) Description() string {
+	return "Query reconciliation statement of an account, including opening balance, closing balance and the balance after every transaction in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryReconciliationStatementToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryReconciliationStatementRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryReconciliationStatementToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryReconciliationStatementResponse{})
+}
+
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpQueryReconciliationStatementToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_TRANSACTIONS
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryReconciliationStatementToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryStatementRequest MCPQueryReconciliationStatementRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryStatementRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	maxTransactionTime, minTransactionTime, err := parseOptionalTransactionTimeRange(queryStatementRequest.StartTime, queryStatementRequest.EndTime)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := getMCPReconciliationStatement(c, user.Uid, queryStatementRequest.AccountName, maxTransactionTime, minTransactionTime, services)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func parseOptionalTransactionTimeRange(startTimeText string, endTimeText string) (int64, int64, error) {
+	maxTransactionTime := int64(0)
+
+	if endTimeText != "" {
+		endTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(endTimeText)
+
+		if err != nil {
+			return 0, 0, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(endTime.Unix())
+	}
+
+	minTransactionTime := int64(0)
+
+	if startTimeText != "" {
+		startTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(startTimeText)
+
+		if err != nil {
+			return 0, 0, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(startTime.Unix())
+	}
+
+	return maxTransactionTime, minTransactionTime, nil
+}
+
+func getMCPReconciliationStatement(c *core.WebContext, uid int64, accountName string, maxTransactionTime int64, minTransactionTime int64, services MCPAvailableServices) (*MCPQueryReconciliationStatementResponse, error) {
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[query_reconciliation_statement_tool_handler.getMCPReconciliationStatement] get account error, because %s", err.Error())
+		return nil, err
+	}
+
+	account, exists := services.GetAccountService().GetVisibleAccountNameMapByList(allAccounts)[accountName]
+
+	if !exists {
+		log.Warnf(c, "[query_reconciliation_statement_tool_handler.getMCPReconciliationStatement] account \"%s\" not found for user \"uid:%d\"", accountName, uid)
+		return nil, errs.ErrAccountNotFound
+	}
+
+	if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		log.Warnf(c, "[query_reconciliation_statement_tool_handler.getMCPReconciliationStatement] account \"id:%d\" for user \"uid:%d\" is not a single account", account.AccountId, uid)
+		return nil, errs.ErrAccountTypeInvalid
+	}
+
+	reportToolProgress(c, 1, 3, "Accounts loaded")
+
+	transactionsWithAccountBalance, totalInflows, totalOutflows, openingBalance, closingBalance, err := services.GetTransactionService().GetAllTransactionsInOneAccountWithAccountBalanceByMaxTime(c, uid, pageCountForLoadTransactions, maxTransactionTime, minTransactionTime, account.AccountId, account.Category)
+
+	if err != nil {
+		log.Errorf(c, "[query_reconciliation_statement_tool_handler.getMCPReconciliationStatement] failed to get transactions of account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+		return nil, err
+	}
+
+	reportToolProgress(c, 2, 3, fmt.Sprintf("%d transactions loaded", len(transactionsWithAccountBalance)))
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Warnf(c, "[query_reconciliation_statement_tool_handler.getMCPReconciliationStatement] get transaction category error, because %s", err.Error())
+		return nil, err
+	}
+
+	reportToolProgress(c, 3, 3, "Categories loaded")
+
+	response := &MCPQueryReconciliationStatementResponse{
+		AccountName:    account.Name,
+		Currency:       account.Currency,
+		OpeningBalance: utils.FormatBigIntAmount(openingBalance),
+		ClosingBalance: utils.FormatBigIntAmount(closingBalance),
+		TotalInflows:   utils.FormatBigIntAmount(totalInflows),
+		TotalOutflows:  utils.FormatBigIntAmount(totalOutflows),
+		Transactions:   createNewMCPReconciliationStatementTransactions(c, transactionsWithAccountBalance, services.GetAccountService().GetAccountMapByList(allAccounts), services.GetTransactionCategoryService().GetCategoryMapByList(allCategories)),
+	}
+
+	return response, nil
+}
+
+func createNewMCPReconciliationStatementTransactions(c *core.WebContext, transactionsWithAccountBalance []*models.TransactionWithAccountBalance, accountsMap map[int64]*models.Account, categoriesMap map[int64]*models.TransactionCategory) []*MCPReconciliationStatementTransaction {
+	transactionInfos := make([]*MCPReconciliationStatementTransaction, 0, len(transactionsWithAccountBalance))
+
+	for i := 0; i < len(transactionsWithAccountBalance); i++ {
+		transaction := transactionsWithAccountBalance[i]
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+
+		transactionInfo := &MCPReconciliationStatementTransaction{
+			Id:             utils.Int64ToString(transaction.TransactionId),
+			Time:           utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(transactionUnixTime, transactionTimeZone),
+			Amount:         utils.FormatAmount(transaction.Amount),
+			Comment:        transaction.Comment,
+			ClosingBalance: utils.FormatBigIntAmount(transaction.AccountClosingBalance),
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			transactionInfo.Type = transactionTypeIncome
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			transactionInfo.Type = transactionTypeExpense
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			transactionInfo.Type = transactionStatisticTypeTransferOut
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			transactionInfo.Id = utils.Int64ToString(transaction.RelatedId)
+			transactionInfo.Type = transactionStatisticTypeTransferIn
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			transactionInfo.Type = transactionTypeModifyBalance
+		} else {
+			log.Warnf(c, "[query_reconciliation_statement_tool_handler.createNewMCPReconciliationStatementTransactions] encountered transaction with unexpected type \"%d\" for transaction \"id:%d\"", transaction.Type, transaction.TransactionId)
+			continue
+		}
+
+		if category, exists := categoriesMap[transaction.CategoryId]; exists && category != nil {
+			transactionInfo.CategoryName = category.Name
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			if relatedAccount, exists := accountsMap[transaction.RelatedAccountId]; exists && relatedAccount != nil {
+				transactionInfo.RelatedAccountName = relatedAccount.Name
+			}
+		}
+
+		transactionInfos = append(transactionInfos, transactionInfo)
+	}
+
+	return transactionInfos
+}
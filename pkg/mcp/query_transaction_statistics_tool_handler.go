@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const transactionStatisticTypeTransferOut = "transfer_out"
+const transactionStatisticTypeTransferIn = "transfer_in"
+
+// MCPQueryTransactionStatisticsRequest represents all parameters of the query transaction statistics request
+type MCPQueryTransactionStatisticsRequest struct {
+	StartTime              string `json:"start_time" jsonschema:"format=date-time" jsonschema_description:"Start time for the statistics in RFC 3339 format (e.g. 2023-01-01T00:00:00Z), the timezone of start time is used to calculate the local time of transactions"`
+	EndTime                string `json:"end_time" jsonschema:"format=date-time" jsonschema_description:"End time for the statistics in RFC 3339 format (e.g. 2023-01-31T23:59:59Z)"`
+	Keyword                string `json:"keyword,omitempty" jsonschema_description:"Keyword to filter transactions by description (optional)"`
+	MatchMode              string `json:"match_mode,omitempty" jsonschema:"enum=default,enum=ignore_case" jsonschema_description:"Match mode for keyword search (optional, leave empty for database default setting, ignore_case for case-insensitive search)"`
+	UseTransactionTimezone bool   `json:"use_transaction_timezone,omitempty" jsonschema_description:"If true, the local time of every transaction is calculated by the timezone of the transaction itself (optional)"`
+}
+
+// MCPQueryTransactionStatisticsResponse represents the response structure for querying transaction statistics
+type MCPQueryTransactionStatisticsResponse struct {
+	StartTime string                         `json:"start_time" jsonschema_description:"Start time of the statistics in RFC 3339 format"`
+	EndTime   string                         `json:"end_time" jsonschema_description:"End time of the statistics in RFC 3339 format"`
+	Items     []*MCPTransactionStatisticItem `json:"items" jsonschema_description:"Total inflows and outflows grouped by transaction type, category and account"`
+}
+
+// MCPTransactionStatisticItem defines the structure of transaction total amount grouped by transaction type, category and account
+type MCPTransactionStatisticItem struct {
+	Type               string `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer_out,enum=transfer_in" jsonschema_description:"Transaction type (income, expense, transfer_out, transfer_in)"`
+	CategoryName       string `json:"category_name" jsonschema_description:"Secondary category name of the transactions"`
+	AccountName        string `json:"account_name" jsonschema_description:"Account name of the transactions"`
+	RelatedAccountName string `json:"related_account_name,omitempty" jsonschema_description:"Destination account name for transfer out transactions, or source account name for transfer in transactions (optional)"`
+	TotalAmount        string `json:"total_amount" jsonschema_description:"Total amount of the transactions in the account currency"`
+	Currency           string `json:"currency" jsonschema_description:"Currency code of the account (e.g. USD, EUR)"`
+}
+
+type mcpQueryTransactionStatisticsToolHandler struct{}
+
+var MCPQueryTransactionStatisticsToolHandler = &mcpQueryTransactionStatisticsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryTransactionStatisticsToolHandler) Name() string {
+	return "query_transaction_statistics"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryTransactionStatisticsToolHandler) Description() string {
+	return "Query total income, expense and transfer amounts grouped by category and account within a time range in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryTransactionStatisticsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTransactionStatisticsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryTransactionStatisticsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTransactionStatisticsResponse{})
+}
+
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpQueryTransactionStatisticsToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_STATISTICS
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryTransactionStatisticsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryStatisticsRequest MCPQueryTransactionStatisticsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryStatisticsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	startTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(queryStatisticsRequest.StartTime)
+
+	if err != nil {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	endTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(queryStatisticsRequest.EndTime)
+
+	if err != nil {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	matchModeType := core.MATCH_MODE_DEFAULT
+
+	if queryStatisticsRequest.MatchMode == "ignore_case" {
+		matchModeType = core.MATCH_MODE_IGNORE_CASE
+	}
+
+	uid := user.Uid
+	totalAmounts, err := services.GetTransactionService().GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, startTime.Unix(), endTime.Unix(), nil, false, queryStatisticsRequest.Keyword, matchModeType, startTime.Location(), queryStatisticsRequest.UseTransactionTimezone, nil)
+
+	if err != nil {
+		log.Errorf(c, "[query_transaction_statistics_tool_handler.Handle] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[query_transaction_statistics_tool_handler.Handle] get account error, because %s", err.Error())
+		return nil, nil, err
+	}
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Warnf(c, "[query_transaction_statistics_tool_handler.Handle] get transaction category error, because %s", err.Error())
+		return nil, nil, err
+	}
+
+	response := MCPQueryTransactionStatisticsResponse{
+		StartTime: utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(startTime.Unix(), startTime.Location()),
+		EndTime:   utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(endTime.Unix(), endTime.Location()),
+		Items:     createNewMCPTransactionStatisticItems(c, totalAmounts, services.GetAccountService().GetAccountMapByList(allAccounts), services.GetTransactionCategoryService().GetCategoryMapByList(allCategories)),
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func createNewMCPTransactionStatisticItems(c *core.WebContext, totalAmounts []*models.TransactionTotalAmount, accountsMap map[int64]*models.Account, categoriesMap map[int64]*models.TransactionCategory) []*MCPTransactionStatisticItem {
+	items := make([]*MCPTransactionStatisticItem, 0, len(totalAmounts))
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmount := totalAmounts[i]
+		item := &MCPTransactionStatisticItem{
+			TotalAmount: utils.FormatBigIntAmount(totalAmount.Amount),
+		}
+
+		if totalAmount.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			item.Type = transactionTypeIncome
+		} else if totalAmount.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			item.Type = transactionTypeExpense
+		} else if totalAmount.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			item.Type = transactionStatisticTypeTransferOut
+		} else if totalAmount.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			item.Type = transactionStatisticTypeTransferIn
+		} else {
+			log.Warnf(c, "[query_transaction_statistics_tool_handler.createNewMCPTransactionStatisticItems] encountered total amount with unexpected type \"%d\"", totalAmount.Type)
+			continue
+		}
+
+		if category, exists := categoriesMap[totalAmount.CategoryId]; exists && category != nil {
+			item.CategoryName = category.Name
+		}
+
+		if account, exists := accountsMap[totalAmount.AccountId]; exists && account != nil {
+			item.AccountName = account.Name
+			item.Currency = account.Currency
+		}
+
+		if totalAmount.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || totalAmount.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			if relatedAccount, exists := accountsMap[totalAmount.RelatedAccountId]; exists && relatedAccount != nil {
+				item.RelatedAccountName = relatedAccount.Name
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
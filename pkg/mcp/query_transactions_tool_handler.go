@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"strings"
 	"time"
@@ -40,6 +41,7 @@ type MCPQueryTransactionsResponse struct {
 
 // MCPTransactionInfo defines the structure of transaction information
 type MCPTransactionInfo struct {
+	Id                     string `json:"id" jsonschema_description:"Unique identifier of the transaction"`
 	Time                   string `json:"time,omitempty" jsonschema_description:"Time of the transaction in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
 	Type                   string `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer,enum=balance_modification" jsonschema_description:"Transaction type (income, expense, transfer, balance_modification)"`
 	Amount                 string `json:"amount" jsonschema_description:"Amount of the transaction in the specified currency"`
@@ -76,6 +78,11 @@ func (h *mcpQueryTransactionsToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPQueryTransactionsResponse{})
 }
 
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpQueryTransactionsToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_TRANSACTIONS
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	var queryTransactionsRequest MCPQueryTransactionsRequest
@@ -166,6 +173,8 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 		matchModeType = core.MATCH_MODE_IGNORE_CASE
 	}
 
+	reportToolProgress(c, 1, 3, "Accounts and categories loaded")
+
 	totalCount, err := services.GetTransactionService().GetTransactionCount(c, uid, maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, "", queryTransactionsRequest.Keyword, matchModeType, false)
 
 	if err != nil {
@@ -173,7 +182,17 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 		return nil, nil, err
 	}
 
+	reportToolProgress(c, 2, 3, fmt.Sprintf("%d transactions matched", totalCount))
+
 	transactions, err := services.GetTransactionService().GetTransactionsByMaxTimeUpToCount(c, uid, maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, "", queryTransactionsRequest.Keyword, matchModeType, false, queryTransactionsRequest.Page, queryTransactionsRequest.Count, pageCountForLoadTransactions, false, true)
+
+	if err != nil {
+		log.Errorf(c, "[query_transactions_tool_handler.Handle] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	reportToolProgress(c, 3, 3, fmt.Sprintf("%d transactions loaded", len(transactions)))
+
 	structuredResponse, response, err := h.createNewMCPQueryTransactionsResponse(c, &queryTransactionsRequest, transactions, totalCount, services.GetAccountService().GetAccountMapByList(allAccounts), services.GetTransactionCategoryService().GetCategoryMapByList(allCategories))
 
 	if err != nil {
@@ -202,6 +221,7 @@ func (h *mcpQueryTransactionsToolHandler) createNewMCPQueryTransactionsResponse(
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
 		transactionInfo := MCPTransactionInfo{
+			Id:     utils.Int64ToString(transaction.TransactionId),
 			Amount: utils.FormatAmount(transaction.Amount),
 		}
 
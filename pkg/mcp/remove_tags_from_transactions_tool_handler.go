@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type mcpRemoveTagsFromTransactionsToolHandler struct{}
+
+var MCPRemoveTagsFromTransactionsToolHandler = &mcpRemoveTagsFromTransactionsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpRemoveTagsFromTransactionsToolHandler) Name() string {
+	return "remove_tags_from_transactions"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpRemoveTagsFromTransactionsToolHandler) Description() string {
+	return "Remove tags from multiple existing transactions in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpRemoveTagsFromTransactionsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPBatchUpdateTransactionTagsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpRemoveTagsFromTransactionsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPBatchUpdateTransactionTagsResponse{})
+}
+
+// RequiredScope returns the mcp token scope required to use the MCP tool
+func (h *mcpRemoveTagsFromTransactionsToolHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_WRITE_TRANSACTIONS
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpRemoveTagsFromTransactionsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var batchUpdateRequest MCPBatchUpdateTransactionTagsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &batchUpdateRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	transactions, tagIds, err := getEditableTransactionsAndTagIdsForBatchUpdate(c, user, &batchUpdateRequest, services)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactionIds := make([]int64, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transactionIds[i] = transactions[i].TransactionId
+	}
+
+	err = services.GetTransactionService().BatchRemoveTagsFromTransactions(c, uid, transactionIds, tagIds)
+
+	if err != nil {
+		log.Errorf(c, "[remove_tags_from_transactions_tool_handler.Handle] failed to batch remove transactions tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	log.Infof(c, "[remove_tags_from_transactions_tool_handler.Handle] user \"uid:%d\" has batch removed tags from %d transactions successfully", uid, len(transactions))
+
+	response := MCPBatchUpdateTransactionTagsResponse{
+		Success:          true,
+		TransactionCount: len(transactions),
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mcpTransactionCategoriesResourceURI = mcpResourceURIPrefix + "transaction-categories"
+
+// MCPTransactionCategoriesResource represents the contents of the transaction categories resource
+type MCPTransactionCategoriesResource struct {
+	IncomeCategories   []*MCPTransactionCategoryResourceInfo `json:"income_categories"`
+	ExpenseCategories  []*MCPTransactionCategoryResourceInfo `json:"expense_categories"`
+	TransferCategories []*MCPTransactionCategoryResourceInfo `json:"transfer_categories"`
+}
+
+// MCPTransactionCategoryResourceInfo defines the structure of transaction category in the transaction categories resource
+type MCPTransactionCategoryResourceInfo struct {
+	Id            string                                `json:"id"`
+	Name          string                                `json:"name"`
+	Comment       string                                `json:"comment,omitempty"`
+	SubCategories []*MCPTransactionCategoryResourceInfo `json:"sub_categories,omitempty"`
+}
+
+type mcpTransactionCategoriesResourceHandler struct{}
+
+var MCPTransactionCategoriesResourceHandler = &mcpTransactionCategoriesResourceHandler{}
+
+// Name returns the name of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) Name() string {
+	return "transaction_categories"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) Description() string {
+	return "Tree of all visible primary and secondary transaction categories of the current user in ezBookkeeping."
+}
+
+// URITemplate returns the uri template of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) URITemplate() string {
+	return mcpTransactionCategoriesResourceURI
+}
+
+// MimeType returns the mime type of the MCP resource contents
+func (h *mcpTransactionCategoriesResourceHandler) MimeType() string {
+	return "application/json"
+}
+
+// RequiredScope returns the mcp token scope required to read the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) RequiredScope() core.MCPTokenScope {
+	return core.MCP_TOKEN_SCOPE_READ_BASIC_DATA
+}
+
+// List returns all the resources of the current user which can be read by the MCP resource handler
+func (h *mcpTransactionCategoriesResourceHandler) List(c *core.WebContext, user *models.User, services MCPAvailableServices) ([]*MCPResource, error) {
+	return []*MCPResource{
+		{
+			URI:         mcpTransactionCategoriesResourceURI,
+			Name:        h.Name(),
+			Title:       "Transaction Categories",
+			Description: h.Description(),
+			MimeType:    h.MimeType(),
+		},
+	}, nil
+}
+
+// Read returns the text contents of the MCP resource according to the variables in the uri
+func (h *mcpTransactionCategoriesResourceHandler) Read(c *core.WebContext, uriVariables map[string]string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (string, error) {
+	uid := user.Uid
+	categories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_categories_resource_handler.Read] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return "", err
+	}
+
+	content, err := json.Marshal(createNewMCPTransactionCategoriesResource(categories))
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+func createNewMCPTransactionCategoriesResource(categories []*models.TransactionCategory) *MCPTransactionCategoriesResource {
+	resource := &MCPTransactionCategoriesResource{
+		IncomeCategories:   make([]*MCPTransactionCategoryResourceInfo, 0),
+		ExpenseCategories:  make([]*MCPTransactionCategoryResourceInfo, 0),
+		TransferCategories: make([]*MCPTransactionCategoryResourceInfo, 0),
+	}
+
+	primaryCategoryInfos := make(map[int64]*MCPTransactionCategoryResourceInfo)
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+
+		if category.Hidden || category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			continue
+		}
+
+		categoryInfo := &MCPTransactionCategoryResourceInfo{
+			Id:      utils.Int64ToString(category.CategoryId),
+			Name:    category.Name,
+			Comment: category.Comment,
+		}
+
+		primaryCategoryInfos[category.CategoryId] = categoryInfo
+
+		if category.Type == models.CATEGORY_TYPE_INCOME {
+			resource.IncomeCategories = append(resource.IncomeCategories, categoryInfo)
+		} else if category.Type == models.CATEGORY_TYPE_EXPENSE {
+			resource.ExpenseCategories = append(resource.ExpenseCategories, categoryInfo)
+		} else if category.Type == models.CATEGORY_TYPE_TRANSFER {
+			resource.TransferCategories = append(resource.TransferCategories, categoryInfo)
+		}
+	}
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+
+		if category.Hidden || category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+			continue
+		}
+
+		primaryCategoryInfo, exists := primaryCategoryInfos[category.ParentCategoryId]
+
+		if !exists {
+			continue
+		}
+
+		primaryCategoryInfo.SubCategories = append(primaryCategoryInfo.SubCategories, &MCPTransactionCategoryResourceInfo{
+			Id:      utils.Int64ToString(category.CategoryId),
+			Name:    category.Name,
+			Comment: category.Comment,
+		})
+	}
+
+	return resource
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// AdministratorAuthorization verifies whether current user is an enabled administrator
+func AdministratorAuthorization(config *settings.Config) core.MiddlewareHandlerFunc {
+	return func(c *core.WebContext) {
+		uid := c.GetCurrentOperatorUid()
+		user, err := services.Users.GetUserById(c, uid)
+
+		if err != nil {
+			if !errs.IsCustomError(err) {
+				log.Errorf(c, "[administrator.AdministratorAuthorization] failed to get user \"uid:%d\", because %s", uid, err.Error())
+			}
+
+			utils.PrintJsonErrorResult(c, errs.Or(err, errs.ErrUserNotFound))
+			return
+		}
+
+		if user.Disabled {
+			log.Warnf(c, "[administrator.AdministratorAuthorization] user \"uid:%d\" is disabled", uid)
+			utils.PrintJsonErrorResult(c, errs.ErrUserIsDisabled)
+			return
+		}
+
+		if !user.IsAdministrator() {
+			log.Warnf(c, "[administrator.AdministratorAuthorization] user \"uid:%d\" is not administrator but tried to access \"%s\"", uid, c.FullPath())
+			utils.PrintJsonErrorResult(c, errs.ErrNotAdministrator)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,205 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+const apiV1RoutePathPrefix = "/api/v1"
+
+// apiTokenScopeNotRequiredRoutes represents the routes which can be accessed by api token with any scopes
+var apiTokenScopeNotRequiredRoutes = map[string]bool{
+	"/systems/version.json": true,
+}
+
+// apiTokenRequiredScopes represents the scope required by api token with scopes for each route, the routes which are not listed here can only be accessed by api token without scopes
+var apiTokenRequiredScopes = map[string]core.APITokenScope{
+	// Users
+	"/users/profile/get.json":        core.API_TOKEN_SCOPE_READ_USER_PROFILE,
+	"/users/settings/cloud/get.json": core.API_TOKEN_SCOPE_READ_USER_PROFILE,
+
+	// Data
+	"/data/statistics.json":                core.API_TOKEN_SCOPE_READ_STATISTICS,
+	"/data/export.csv":                     core.API_TOKEN_SCOPE_EXPORT_DATA,
+	"/data/export.tsv":                     core.API_TOKEN_SCOPE_EXPORT_DATA,
+	"/data/export.ofx":                     core.API_TOKEN_SCOPE_EXPORT_DATA,
+	"/data/export_ymd.qif":                 core.API_TOKEN_SCOPE_EXPORT_DATA,
+	"/data/export_mdy.qif":                 core.API_TOKEN_SCOPE_EXPORT_DATA,
+	"/data/export_dmy.qif":                 core.API_TOKEN_SCOPE_EXPORT_DATA,
+	"/data/export.gnucash":                 core.API_TOKEN_SCOPE_EXPORT_DATA,
+	"/data/export.beancount":               core.API_TOKEN_SCOPE_EXPORT_DATA,
+	"/data/backup.zip":                     core.API_TOKEN_SCOPE_EXPORT_DATA,
+	"/data/restore.json":                   core.API_TOKEN_SCOPE_IMPORT_DATA,
+	"/transactions/parse_custom_file.json": core.API_TOKEN_SCOPE_IMPORT_DATA,
+	"/transactions/parse_import.json":      core.API_TOKEN_SCOPE_IMPORT_DATA,
+	"/transactions/import.json":            core.API_TOKEN_SCOPE_IMPORT_DATA,
+	"/transactions/import/process.json":    core.API_TOKEN_SCOPE_IMPORT_DATA,
+
+	// Accounts
+	"/accounts/list.json":                        core.API_TOKEN_SCOPE_READ_ACCOUNTS,
+	"/accounts/get.json":                         core.API_TOKEN_SCOPE_READ_ACCOUNTS,
+	"/accounts/add.json":                         core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	"/accounts/modify.json":                      core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	"/accounts/update/last_reconciled_time.json": core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	"/accounts/hide.json":                        core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	"/accounts/move.json":                        core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	"/accounts/delete.json":                      core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	"/accounts/sub_account/delete.json":          core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	"/accounts/loan/get.json":                    core.API_TOKEN_SCOPE_READ_ACCOUNTS,
+	"/accounts/loan/set.json":                    core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	"/accounts/loan/delete.json":                 core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+
+	// Transactions
+	"/transactions/count.json":                       core.API_TOKEN_SCOPE_READ_TRANSACTIONS,
+	"/transactions/list.json":                        core.API_TOKEN_SCOPE_READ_TRANSACTIONS,
+	"/transactions/list/by_month.json":               core.API_TOKEN_SCOPE_READ_TRANSACTIONS,
+	"/transactions/list/all.json":                    core.API_TOKEN_SCOPE_READ_TRANSACTIONS,
+	"/transactions/reconciliation_statements.json":   core.API_TOKEN_SCOPE_READ_TRANSACTIONS,
+	"/transactions/statistics.json":                  core.API_TOKEN_SCOPE_READ_STATISTICS,
+	"/transactions/statistics/trends.json":           core.API_TOKEN_SCOPE_READ_STATISTICS,
+	"/transactions/statistics/asset_trends.json":     core.API_TOKEN_SCOPE_READ_STATISTICS,
+	"/transactions/amounts.json":                     core.API_TOKEN_SCOPE_READ_STATISTICS,
+	"/transactions/forecast.json":                    core.API_TOKEN_SCOPE_READ_STATISTICS,
+	"/transactions/get.json":                         core.API_TOKEN_SCOPE_READ_TRANSACTIONS,
+	"/transactions/add.json":                         core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transactions/modify.json":                      core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transactions/batch_update/category.json":       core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transactions/batch_update/account.json":        core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transactions/batch_update/tag/add.json":        core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transactions/batch_update/tag/remove.json":     core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transactions/batch_update/tag/clear.json":      core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transactions/move/all.json":                    core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transactions/delete.json":                      core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transactions/batch_delete.json":                core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transaction/pictures/upload.json":              core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/transaction/pictures/remove_unused.json":       core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/llm/transactions/recognize_text.json":          core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	"/llm/transactions/recognize_receipt_image.json": core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+
+	// Transaction Categories
+	"/transaction/categories/list.json":      core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/categories/get.json":       core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/categories/add.json":       core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/categories/add_batch.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/categories/modify.json":    core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/categories/hide.json":      core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/categories/move.json":      core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/categories/delete.json":    core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+
+	// Transaction Tag Groups
+	"/transaction/tags/groups/list.json":   core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/tags/groups/get.json":    core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/tags/groups/add.json":    core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/tags/groups/modify.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/tags/groups/move.json":   core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/tags/groups/delete.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+
+	// Transaction Tags
+	"/transaction/tags/list.json":      core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/tags/get.json":       core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/tags/add.json":       core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/tags/add_batch.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/tags/modify.json":    core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/tags/hide.json":      core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/tags/move.json":      core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/tags/delete.json":    core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+
+	// Transaction Templates
+	"/transaction/templates/list.json":                       core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/templates/get.json":                        core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/templates/add.json":                        core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/templates/modify.json":                     core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/templates/hide.json":                       core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/templates/move.json":                       core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/templates/delete.json":                     core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/templates/schedule/preview.json":           core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/templates/schedule/exceptions/list.json":   core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/templates/schedule/exceptions/add.json":    core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/templates/schedule/exceptions/delete.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+
+	// Transaction Rules
+	"/transaction/rules/list.json":   core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/rules/get.json":    core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/transaction/rules/add.json":    core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/rules/modify.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/rules/hide.json":   core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/rules/move.json":   core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/rules/delete.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/transaction/rules/apply.json":  core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+
+	// Insights Explorers
+	"/insights/explorers/list.json":   core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/insights/explorers/get.json":    core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/insights/explorers/add.json":    core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/insights/explorers/modify.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/insights/explorers/hide.json":   core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/insights/explorers/move.json":   core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/insights/explorers/delete.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+
+	// Budgets
+	"/budgets/list.json":     core.API_TOKEN_SCOPE_READ_BUDGETS,
+	"/budgets/get.json":      core.API_TOKEN_SCOPE_READ_BUDGETS,
+	"/budgets/progress.json": core.API_TOKEN_SCOPE_READ_BUDGETS,
+	"/budgets/add.json":      core.API_TOKEN_SCOPE_WRITE_BUDGETS,
+	"/budgets/modify.json":   core.API_TOKEN_SCOPE_WRITE_BUDGETS,
+	"/budgets/hide.json":     core.API_TOKEN_SCOPE_WRITE_BUDGETS,
+	"/budgets/move.json":     core.API_TOKEN_SCOPE_WRITE_BUDGETS,
+	"/budgets/delete.json":   core.API_TOKEN_SCOPE_WRITE_BUDGETS,
+
+	// Investments
+	"/investments/securities/list.json":     core.API_TOKEN_SCOPE_READ_INVESTMENTS,
+	"/investments/securities/add.json":      core.API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+	"/investments/securities/modify.json":   core.API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+	"/investments/securities/delete.json":   core.API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+	"/investments/transactions/list.json":   core.API_TOKEN_SCOPE_READ_INVESTMENTS,
+	"/investments/transactions/add.json":    core.API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+	"/investments/transactions/delete.json": core.API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+	"/investments/prices/list.json":         core.API_TOKEN_SCOPE_READ_INVESTMENTS,
+	"/investments/prices/add.json":          core.API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+	"/investments/prices/delete.json":       core.API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+	"/investments/prices/update.json":       core.API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+	"/investments/holdings.json":            core.API_TOKEN_SCOPE_READ_INVESTMENTS,
+
+	// User Custom Icons
+	"/custom_icons/list.json":   core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	"/custom_icons/upload.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/custom_icons/move.json":   core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/custom_icons/delete.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+
+	// Exchange Rates
+	"/exchange_rates/latest.json":             core.API_TOKEN_SCOPE_READ_EXCHANGE_RATES,
+	"/exchange_rates/historical.json":         core.API_TOKEN_SCOPE_READ_EXCHANGE_RATES,
+	"/exchange_rates/user_custom/update.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	"/exchange_rates/user_custom/delete.json": core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+}
+
+// isAPITokenScopesPermitted returns whether the api token with the specified scopes can access the specified route
+func isAPITokenScopesPermitted(scopes core.APITokenScopes, routePath string) bool {
+	if len(scopes) < 1 {
+		return true
+	}
+
+	return isAPIRouteScopesPermitted(scopes, routePath)
+}
+
+// isAPIRouteScopesPermitted returns whether the specified scopes contain the scope required by the specified api route, the api routes which do not require any scope are always permitted
+func isAPIRouteScopesPermitted(scopes core.APITokenScopes, routePath string) bool {
+	if !strings.HasPrefix(routePath, apiV1RoutePathPrefix) {
+		return false
+	}
+
+	routePath = routePath[len(apiV1RoutePathPrefix):]
+
+	if apiTokenScopeNotRequiredRoutes[routePath] {
+		return true
+	}
+
+	requiredScope, exists := apiTokenRequiredScopes[routePath]
+
+	if !exists {
+		return false
+	}
+
+	return scopes.Contains(requiredScope)
+}
@@ -1,11 +1,14 @@
 package middlewares
 
 import (
+	"encoding/json"
+
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
@@ -102,6 +105,28 @@ func JWTResetPasswordAuthorization(config *settings.Config) core.MiddlewareHandl
 	}
 }
 
+// JWTLedgerInvitationAuthorization verifies whether current request is shared ledger invitation accepting
+func JWTLedgerInvitationAuthorization(config *settings.Config) core.MiddlewareHandlerFunc {
+	return func(c *core.WebContext) {
+		claims, tokenContext, err := getTokenClaims(c, TOKEN_SOURCE_TYPE_ARGUMENT)
+
+		if err != nil {
+			utils.PrintJsonErrorResult(c, errs.ErrLedgerInvitationTokenIsInvalidOrExpired)
+			return
+		}
+
+		if claims.Type != core.USER_TOKEN_TYPE_LEDGER_INVITATION {
+			log.Warnf(c, "[authorization.JWTLedgerInvitationAuthorization] user \"uid:%d\" token is not for ledger invitation", claims.Uid)
+			utils.PrintJsonErrorResult(c, errs.ErrCurrentInvalidToken)
+			return
+		}
+
+		c.SetTokenClaims(claims)
+		c.SetTokenContext(tokenContext)
+		c.Next()
+	}
+}
+
 // JWTMCPAuthorization verifies whether current request is valid by jwt mcp token in header
 func JWTMCPAuthorization(config *settings.Config) core.MiddlewareHandlerFunc {
 	return func(c *core.WebContext) {
@@ -179,6 +204,22 @@ func jwtAuthorization(config *settings.Config, source TokenSourceType, allowedAP
 			return
 		}
 
+		if claims.Type == core.USER_TOKEN_TYPE_API && tokenContext != "" {
+			var apiTokenContext models.APITokenContext
+
+			if err := json.Unmarshal([]byte(tokenContext), &apiTokenContext); err != nil {
+				log.Warnf(c, "[authorization.jwtAuthorization] failed to parse api token context of user \"uid:%d\", because %s", claims.Uid, err.Error())
+				utils.PrintJsonErrorResult(c, errs.ErrCurrentInvalidToken)
+				return
+			}
+
+			if !isAPITokenScopesPermitted(apiTokenContext.Scopes, c.FullPath()) {
+				log.Warnf(c, "[authorization.jwtAuthorization] user \"uid:%d\" api token with scopes \"%s\" is not permitted to access \"%s\"", claims.Uid, apiTokenContext.Scopes.String(), c.FullPath())
+				utils.PrintJsonErrorResult(c, errs.ErrAPITokenScopeNotPermitted)
+				return
+			}
+		}
+
 		c.SetTokenClaims(claims)
 		c.SetTokenContext(tokenContext)
 		c.Next()
@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ledgerManagementRoutePathPrefix = apiV1RoutePathPrefix + "/ledgers/"
+
+// ledgerMemberResourceRequiredScopes represents the scope required by ledger member for each non-api resource route, the resource routes which are not listed here cannot be accessed in shared ledger
+var ledgerMemberResourceRequiredScopes = map[string]core.APITokenScope{
+	"/pictures/:fileName": core.API_TOKEN_SCOPE_READ_TRANSACTIONS,
+	"/icons/:fileName":    core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+}
+
+// LedgerMemberAuthorization verifies whether current user is the member of the shared ledger specified in request, and switches the book which current request operates on to the book of shared ledger
+func LedgerMemberAuthorization(config *settings.Config) core.MiddlewareHandlerFunc {
+	return func(c *core.WebContext) {
+		ledgerIdValue := c.GetLedgerIdValue()
+
+		if ledgerIdValue == "" || strings.HasPrefix(c.FullPath(), ledgerManagementRoutePathPrefix) {
+			c.Next()
+			return
+		}
+
+		uid := c.GetCurrentOperatorUid()
+
+		if uid <= 0 {
+			c.Next()
+			return
+		}
+
+		ledgerId, err := utils.StringToInt64(ledgerIdValue)
+
+		if err != nil || ledgerId <= 0 {
+			utils.PrintJsonErrorResult(c, errs.ErrLedgerIdInvalid)
+			return
+		}
+
+		ledger, err := services.Ledgers.GetLedgerByLedgerId(c, ledgerId)
+
+		if err != nil {
+			log.Warnf(c, "[ledger.LedgerMemberAuthorization] failed to get ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerId, uid, err.Error())
+			utils.PrintJsonErrorResult(c, errs.Or(err, errs.ErrOperationFailed))
+			return
+		}
+
+		if ledger.BookUid <= 0 {
+			log.Errorf(c, "[ledger.LedgerMemberAuthorization] ledger \"id:%d\" does not have a book", ledgerId)
+			utils.PrintJsonErrorResult(c, errs.ErrLedgerNotFound)
+			return
+		}
+
+		member, err := services.Ledgers.GetAcceptedMember(c, ledgerId, uid)
+
+		if err != nil {
+			if err == errs.ErrLedgerMemberNotFound {
+				log.Warnf(c, "[ledger.LedgerMemberAuthorization] user \"uid:%d\" is not the member of ledger \"id:%d\"", uid, ledgerId)
+				utils.PrintJsonErrorResult(c, errs.ErrLedgerOperationNotPermitted)
+			} else {
+				log.Errorf(c, "[ledger.LedgerMemberAuthorization] failed to get member of ledger \"id:%d\" for user \"uid:%d\", because %s", ledgerId, uid, err.Error())
+				utils.PrintJsonErrorResult(c, errs.Or(err, errs.ErrOperationFailed))
+			}
+
+			return
+		}
+
+		if !isLedgerMemberScopesPermitted(member.Role.GetPermittedScopes(), c.FullPath()) {
+			log.Warnf(c, "[ledger.LedgerMemberAuthorization] user \"uid:%d\" with role \"%d\" cannot access \"%s\" in ledger \"id:%d\"", uid, member.Role, c.FullPath(), ledgerId)
+			utils.PrintJsonErrorResult(c, errs.ErrLedgerOperationNotPermitted)
+			return
+		}
+
+		c.SetLedgerBookUid(ledger.BookUid)
+		c.Next()
+	}
+}
+
+// isLedgerMemberScopesPermitted returns whether the ledger member with the specified scopes can access the specified route, the member without any scope cannot access any route
+func isLedgerMemberScopesPermitted(scopes core.APITokenScopes, routePath string) bool {
+	if len(scopes) < 1 {
+		return false
+	}
+
+	if !strings.HasPrefix(routePath, apiV1RoutePathPrefix) {
+		requiredScope, exists := ledgerMemberResourceRequiredScopes[routePath]
+
+		if !exists {
+			return false
+		}
+
+		return scopes.Contains(requiredScope)
+	}
+
+	return isAPIRouteScopesPermitted(scopes, routePath)
+}
@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mcp"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPSessionValidation verifies whether the mcp session in the request header is active and belongs to current user
+func MCPSessionValidation(c *core.WebContext) {
+	sessionId := c.GetHeader(mcp.MCPSessionIdHeaderName)
+
+	if sessionId == "" {
+		c.Next()
+		return
+	}
+
+	uid := c.GetCurrentUid()
+	session := mcp.Sessions.GetSession(uid, sessionId)
+
+	if session == nil {
+		log.Warnf(c, "[mcp_session.MCPSessionValidation] mcp session \"%s\" of user \"uid:%d\" does not exist or has expired", sessionId, uid)
+		utils.PrintJsonErrorResult(c, errs.ErrMCPSessionNotFound)
+		return
+	}
+
+	c.Next()
+}
@@ -96,8 +96,9 @@ type Account struct {
 
 // AccountExtend represents account extend data stored in database
 type AccountExtend struct {
-	LastReconciledTime      *int64 `json:"lastReconciledTime"`
-	CreditCardStatementDate *int   `json:"creditCardStatementDate"`
+	LastReconciledTime      *int64           `json:"lastReconciledTime"`
+	CreditCardStatementDate *int             `json:"creditCardStatementDate"`
+	Loan                    *AccountLoanInfo `json:"loan,omitempty"`
 }
 
 // AccountCreateRequest represents all parameters of account creation request
@@ -0,0 +1,84 @@
+package models
+
+import "github.com/mayswind/ezbookkeeping/pkg/core"
+
+// AdminUserListRequest represents all parameters of user listing request by administrator
+type AdminUserListRequest struct {
+	Keyword string `form:"keyword" binding:"max=100"`
+	Page    int32  `form:"page" binding:"min=0"`
+	Count   int32  `form:"count" binding:"required,min=1,max=100"`
+}
+
+// AdminUserGetRequest represents all parameters of user getting request by administrator
+type AdminUserGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// AdminUserIdRequest represents all parameters of request which operates on a specified user by administrator
+type AdminUserIdRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// AdminUserRoleUpdateRequest represents all parameters of user role updating request by administrator
+type AdminUserRoleUpdateRequest struct {
+	Id   int64    `json:"id,string" binding:"required,min=1"`
+	Role UserRole `json:"role" binding:"min=0,max=1"`
+}
+
+// AdminUserFeatureRestrictionUpdateRequest represents all parameters of user feature restrictions updating request by administrator
+type AdminUserFeatureRestrictionUpdateRequest struct {
+	Id                  int64                             `json:"id,string" binding:"required,min=1"`
+	FeatureRestrictions []core.UserFeatureRestrictionType `json:"featureRestrictions"`
+}
+
+// AdminUserTokenRevokeRequest represents all parameters of user token revoking request by administrator
+type AdminUserTokenRevokeRequest struct {
+	Id      int64  `json:"id,string" binding:"required,min=1"`
+	TokenId string `json:"tokenId" binding:"required,notBlank"`
+}
+
+// AdminUserInfoResponse represents a view-object of user info for administrator
+type AdminUserInfoResponse struct {
+	Id                  int64                             `json:"id,string"`
+	Username            string                            `json:"username"`
+	Email               string                            `json:"email"`
+	Nickname            string                            `json:"nickname"`
+	Role                UserRole                          `json:"role"`
+	Disabled            bool                              `json:"disabled"`
+	EmailVerified       bool                              `json:"emailVerified"`
+	NoPassword          bool                              `json:"noPassword,omitempty"`
+	FeatureRestrictions []core.UserFeatureRestrictionType `json:"featureRestrictions"`
+	CreatedAt           int64                             `json:"createdAt"`
+	LastLoginAt         int64                             `json:"lastLoginAt"`
+}
+
+// AdminUserDataStatisticsResponse represents a view-object of user data statistics and storage usage for administrator
+type AdminUserDataStatisticsResponse struct {
+	*DataStatisticsResponse
+	TotalTransactionPictureSize int64 `json:"totalTransactionPictureSize,string"`
+	TotalCustomIconSize         int64 `json:"totalCustomIconSize,string"`
+	AvatarSize                  int64 `json:"avatarSize,string"`
+}
+
+// AdminUserInfoPageWrapperResponse represents a response of user info for administrator which contains items and count
+type AdminUserInfoPageWrapperResponse struct {
+	Items      []*AdminUserInfoResponse `json:"items"`
+	TotalCount int64                    `json:"totalCount"`
+}
+
+// ToAdminUserInfoResponse returns a view-object of user info for administrator according to database model
+func (u *User) ToAdminUserInfoResponse() *AdminUserInfoResponse {
+	return &AdminUserInfoResponse{
+		Id:                  u.Uid,
+		Username:            u.Username,
+		Email:               u.Email,
+		Nickname:            u.Nickname,
+		Role:                u.Role,
+		Disabled:            u.Disabled,
+		EmailVerified:       u.EmailVerified,
+		NoPassword:          u.Password == "",
+		FeatureRestrictions: u.FeatureRestriction.ToTypes(),
+		CreatedAt:           u.CreatedUnixTime,
+		LastLoginAt:         u.LastLoginUnixTime,
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+func TestUserRoleIsValid(t *testing.T) {
+	assert.True(t, USER_ROLE_NORMAL.IsValid())
+	assert.True(t, USER_ROLE_ADMINISTRATOR.IsValid())
+	assert.False(t, UserRole(2).IsValid())
+}
+
+func TestUserToAdminUserInfoResponse(t *testing.T) {
+	user := &User{
+		Uid:                1234567890,
+		Username:           "admin",
+		Email:              "admin@example.com",
+		Nickname:           "Admin",
+		Password:           "",
+		Role:               USER_ROLE_ADMINISTRATOR,
+		EmailVerified:      true,
+		FeatureRestriction: core.UserFeatureRestrictions(0).Add(core.USER_FEATURE_RESTRICTION_TYPE_UPDATE_EMAIL).Add(core.USER_FEATURE_RESTRICTION_TYPE_CLEAR_ALL_DATA),
+		CreatedUnixTime:    1700000000,
+		LastLoginUnixTime:  1700000100,
+	}
+
+	actualValue := user.ToAdminUserInfoResponse()
+	assert.Equal(t, int64(1234567890), actualValue.Id)
+	assert.Equal(t, "admin", actualValue.Username)
+	assert.Equal(t, USER_ROLE_ADMINISTRATOR, actualValue.Role)
+	assert.False(t, actualValue.Disabled)
+	assert.True(t, actualValue.EmailVerified)
+	assert.True(t, actualValue.NoPassword)
+	assert.Equal(t, []core.UserFeatureRestrictionType{core.USER_FEATURE_RESTRICTION_TYPE_UPDATE_EMAIL, core.USER_FEATURE_RESTRICTION_TYPE_CLEAR_ALL_DATA}, actualValue.FeatureRestrictions)
+	assert.Equal(t, int64(1700000000), actualValue.CreatedAt)
+	assert.Equal(t, int64(1700000100), actualValue.LastLoginAt)
+	assert.True(t, user.IsAdministrator())
+}
@@ -0,0 +1,393 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumBudgetRolloverPeriods represents the maximum count of previous periods which are used for calculating rollover amount
+const MaximumBudgetRolloverPeriods = 60
+
+// MaximumBudgetAlertThresholdsCount represents the maximum count of budget alert thresholds of a user
+const MaximumBudgetAlertThresholdsCount = 5
+
+// MaximumBudgetAlertThresholdPercent represents the maximum percent of a budget alert threshold
+const MaximumBudgetAlertThresholdPercent = 1000
+
+// BudgetPeriodType represents budget period type
+type BudgetPeriodType byte
+
+// Budget period types
+const (
+	BUDGET_PERIOD_TYPE_MONTHLY     BudgetPeriodType = 1
+	BUDGET_PERIOD_TYPE_WEEKLY      BudgetPeriodType = 2
+	BUDGET_PERIOD_TYPE_YEARLY      BudgetPeriodType = 3
+	BUDGET_PERIOD_TYPE_FISCAL_YEAR BudgetPeriodType = 4
+)
+
+// String returns a textual representation of the budget period type enum
+func (t BudgetPeriodType) String() string {
+	switch t {
+	case BUDGET_PERIOD_TYPE_MONTHLY:
+		return "Monthly"
+	case BUDGET_PERIOD_TYPE_WEEKLY:
+		return "Weekly"
+	case BUDGET_PERIOD_TYPE_YEARLY:
+		return "Yearly"
+	case BUDGET_PERIOD_TYPE_FISCAL_YEAR:
+		return "Fiscal Year"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(t))
+	}
+}
+
+// BudgetTargetType represents budget target type
+type BudgetTargetType byte
+
+// Budget target types
+const (
+	BUDGET_TARGET_TYPE_CATEGORY BudgetTargetType = 1
+	BUDGET_TARGET_TYPE_ACCOUNT  BudgetTargetType = 2
+)
+
+// String returns a textual representation of the budget target type enum
+func (t BudgetTargetType) String() string {
+	switch t {
+	case BUDGET_TARGET_TYPE_CATEGORY:
+		return "Category"
+	case BUDGET_TARGET_TYPE_ACCOUNT:
+		return "Account"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(t))
+	}
+}
+
+// Budget represents budget data stored in database
+type Budget struct {
+	BudgetId        int64            `xorm:"PK"`
+	Uid             int64            `xorm:"INDEX(IDX_budget_uid_deleted_order) NOT NULL"`
+	Deleted         bool             `xorm:"INDEX(IDX_budget_uid_deleted_order) NOT NULL"`
+	Name            string           `xorm:"VARCHAR(64) NOT NULL"`
+	PeriodType      BudgetPeriodType `xorm:"NOT NULL"`
+	TargetType      BudgetTargetType `xorm:"NOT NULL"`
+	TargetId        int64            `xorm:"NOT NULL"`
+	Currency        string           `xorm:"VARCHAR(3) NOT NULL"`
+	LimitAmount     int64            `xorm:"NOT NULL"`
+	Rollover        bool             `xorm:"NOT NULL"`
+	StartTime       int64            `xorm:"NOT NULL"`
+	Comment         string           `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder    int32            `xorm:"INDEX(IDX_budget_uid_deleted_order) NOT NULL"`
+	Hidden          bool             `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+
+	LastAlertPeriodStartTime int64
+	LastAlertThreshold       uint32
+}
+
+// BudgetListRequest represents all parameters of budget listing request
+type BudgetListRequest struct {
+	VisibleOnly bool `form:"visible_only"`
+}
+
+// BudgetGetRequest represents all parameters of budget getting request
+type BudgetGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// BudgetCreateRequest represents all parameters of budget creation request
+type BudgetCreateRequest struct {
+	Name            string           `json:"name" binding:"required,notBlank,max=64"`
+	PeriodType      BudgetPeriodType `json:"periodType" binding:"required"`
+	TargetType      BudgetTargetType `json:"targetType" binding:"required"`
+	TargetId        int64            `json:"targetId,string" binding:"required,min=1"`
+	Currency        string           `json:"currency" binding:"required,len=3,validCurrency"`
+	LimitAmount     int64            `json:"limitAmount" binding:"validTransactionAmount"`
+	Rollover        bool             `json:"rollover"`
+	StartTime       int64            `json:"startTime" binding:"min=0"`
+	Comment         string           `json:"comment" binding:"max=255"`
+	ClientSessionId string           `json:"clientSessionId"`
+}
+
+// BudgetModifyRequest represents all parameters of budget modification request
+type BudgetModifyRequest struct {
+	Id          int64            `json:"id,string" binding:"required,min=1"`
+	Name        string           `json:"name" binding:"required,notBlank,max=64"`
+	PeriodType  BudgetPeriodType `json:"periodType" binding:"required"`
+	TargetType  BudgetTargetType `json:"targetType" binding:"required"`
+	TargetId    int64            `json:"targetId,string" binding:"required,min=1"`
+	Currency    string           `json:"currency" binding:"required,len=3,validCurrency"`
+	LimitAmount int64            `json:"limitAmount" binding:"validTransactionAmount"`
+	Rollover    bool             `json:"rollover"`
+	StartTime   int64            `json:"startTime" binding:"min=0"`
+	Comment     string           `json:"comment" binding:"max=255"`
+	Hidden      bool             `json:"hidden"`
+}
+
+// BudgetHideRequest represents all parameters of budget hiding request
+type BudgetHideRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Hidden bool  `json:"hidden"`
+}
+
+// BudgetMoveRequest represents all parameters of budget moving request
+type BudgetMoveRequest struct {
+	NewDisplayOrders []*BudgetNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// BudgetNewDisplayOrderRequest represents a data pair of id and display order
+type BudgetNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// BudgetDeleteRequest represents all parameters of budget deleting request
+type BudgetDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// BudgetProgressRequest represents all parameters of budget progress request
+type BudgetProgressRequest struct {
+	Id   int64 `form:"id,string" binding:"min=0"`
+	Time int64 `form:"time" binding:"min=0"`
+}
+
+// BudgetInfoResponse represents a view-object of budget
+type BudgetInfoResponse struct {
+	Id           int64            `json:"id,string"`
+	Name         string           `json:"name"`
+	PeriodType   BudgetPeriodType `json:"periodType"`
+	TargetType   BudgetTargetType `json:"targetType"`
+	TargetId     int64            `json:"targetId,string"`
+	Currency     string           `json:"currency"`
+	LimitAmount  int64            `json:"limitAmount"`
+	Rollover     bool             `json:"rollover"`
+	StartTime    int64            `json:"startTime"`
+	Comment      string           `json:"comment"`
+	DisplayOrder int32            `json:"displayOrder"`
+	Hidden       bool             `json:"hidden"`
+}
+
+// BudgetProgressResponse represents a view-object of budget versus actual spending in one period
+type BudgetProgressResponse struct {
+	*BudgetInfoResponse
+	PeriodStartTime           int64                               `json:"periodStartTime"`
+	PeriodEndTime             int64                               `json:"periodEndTime"`
+	RolloverAmount            string                              `json:"rolloverAmount"`
+	AvailableAmount           string                              `json:"availableAmount"`
+	SpentAmount               string                              `json:"spentAmount"`
+	RemainingAmount           string                              `json:"remainingAmount"`
+	OtherCurrencySpentAmounts []*BudgetProgressCurrencyAmountItem `json:"otherCurrencySpentAmounts,omitempty"`
+}
+
+// BudgetProgress represents the calculated progress of a budget in a period
+type BudgetProgress struct {
+	PeriodStartTime           int64
+	PeriodEndTime             int64
+	RolloverAmount            int64
+	SpentAmount               int64
+	OtherCurrencySpentAmounts map[string]int64
+}
+
+// BudgetProgressCurrencyAmountItem represents the spent amount in a currency different from the budget currency
+type BudgetProgressCurrencyAmountItem struct {
+	Currency string `json:"currency"`
+	Amount   string `json:"amount"`
+}
+
+// IsValid returns whether the budget period type is valid
+func (t BudgetPeriodType) IsValid() bool {
+	return t >= BUDGET_PERIOD_TYPE_MONTHLY && t <= BUDGET_PERIOD_TYPE_FISCAL_YEAR
+}
+
+// IsValid returns whether the budget target type is valid
+func (t BudgetTargetType) IsValid() bool {
+	return t == BUDGET_TARGET_TYPE_CATEGORY || t == BUDGET_TARGET_TYPE_ACCOUNT
+}
+
+// ParseBudgetAlertThresholds returns the sorted alert threshold percents according to the textual thresholds (e.g. "80,100")
+func ParseBudgetAlertThresholds(thresholds string) ([]uint32, error) {
+	if thresholds == "" {
+		return nil, nil
+	}
+
+	items := strings.Split(thresholds, ",")
+
+	if len(items) > MaximumBudgetAlertThresholdsCount {
+		return nil, errs.ErrBudgetAlertThresholdsInvalid
+	}
+
+	result := make([]uint32, 0, len(items))
+	existedThresholds := make(map[uint32]bool, len(items))
+
+	for i := 0; i < len(items); i++ {
+		threshold, err := utils.StringToInt(strings.TrimSpace(items[i]))
+
+		if err != nil || threshold < 1 || threshold > MaximumBudgetAlertThresholdPercent {
+			return nil, errs.ErrBudgetAlertThresholdsInvalid
+		}
+
+		if existedThresholds[uint32(threshold)] {
+			continue
+		}
+
+		existedThresholds[uint32(threshold)] = true
+		result = append(result, uint32(threshold))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+
+	return result, nil
+}
+
+// GetPeriodTimeRange returns the first and the last unix time of the budget period which contains the specified unix time
+func (b *Budget) GetPeriodTimeRange(unixTime int64, timezone *time.Location, firstDayOfWeek core.WeekDay, fiscalYearStart core.FiscalYearStart) (int64, int64) {
+	t := time.Unix(unixTime, 0).In(timezone)
+	var periodStart time.Time
+	var nextPeriodStart time.Time
+
+	switch b.PeriodType {
+	case BUDGET_PERIOD_TYPE_WEEKLY:
+		dayOffset := (int(t.Weekday()) - int(firstDayOfWeek) + 7) % 7
+		periodStart = time.Date(t.Year(), t.Month(), t.Day()-dayOffset, 0, 0, 0, 0, timezone)
+		nextPeriodStart = periodStart.AddDate(0, 0, 7)
+	case BUDGET_PERIOD_TYPE_YEARLY:
+		periodStart = time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, timezone)
+		nextPeriodStart = periodStart.AddDate(1, 0, 0)
+	case BUDGET_PERIOD_TYPE_FISCAL_YEAR:
+		month, day, err := fiscalYearStart.GetMonthDay()
+
+		if err != nil {
+			month, day, _ = core.FISCAL_YEAR_START_DEFAULT.GetMonthDay()
+		}
+
+		periodStart = time.Date(t.Year(), time.Month(month), int(day), 0, 0, 0, 0, timezone)
+
+		if t.Before(periodStart) {
+			periodStart = time.Date(t.Year()-1, time.Month(month), int(day), 0, 0, 0, 0, timezone)
+		}
+
+		nextPeriodStart = periodStart.AddDate(1, 0, 0)
+	default:
+		periodStart = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, timezone)
+		nextPeriodStart = periodStart.AddDate(0, 1, 0)
+	}
+
+	return periodStart.Unix(), nextPeriodStart.Unix() - 1
+}
+
+// GetRolloverPeriodTimeRanges returns the time ranges of previous periods (in chronological order) which are used for calculating rollover amount of the period starting at the specified unix time
+func (b *Budget) GetRolloverPeriodTimeRanges(periodStartTime int64, timezone *time.Location, firstDayOfWeek core.WeekDay, fiscalYearStart core.FiscalYearStart) [][2]int64 {
+	if !b.Rollover {
+		return nil
+	}
+
+	firstPeriodStartTime, _ := b.GetPeriodTimeRange(b.StartTime, timezone, firstDayOfWeek, fiscalYearStart)
+	previousPeriods := make([][2]int64, 0)
+
+	for previousEndTime := periodStartTime - 1; previousEndTime >= firstPeriodStartTime && len(previousPeriods) < MaximumBudgetRolloverPeriods; {
+		previousStartTime, _ := b.GetPeriodTimeRange(previousEndTime, timezone, firstDayOfWeek, fiscalYearStart)
+		previousPeriods = append(previousPeriods, [2]int64{previousStartTime, previousEndTime})
+		previousEndTime = previousStartTime - 1
+	}
+
+	for i, j := 0, len(previousPeriods)-1; i < j; i, j = i+1, j-1 {
+		previousPeriods[i], previousPeriods[j] = previousPeriods[j], previousPeriods[i]
+	}
+
+	return previousPeriods
+}
+
+// ToBudgetInfoResponse returns a view-object according to database model
+func (b *Budget) ToBudgetInfoResponse() *BudgetInfoResponse {
+	return &BudgetInfoResponse{
+		Id:           b.BudgetId,
+		Name:         b.Name,
+		PeriodType:   b.PeriodType,
+		TargetType:   b.TargetType,
+		TargetId:     b.TargetId,
+		Currency:     b.Currency,
+		LimitAmount:  b.LimitAmount,
+		Rollover:     b.Rollover,
+		StartTime:    b.StartTime,
+		Comment:      b.Comment,
+		DisplayOrder: b.DisplayOrder,
+		Hidden:       b.Hidden,
+	}
+}
+
+// ToBudgetProgressResponse returns a view-object of budget progress according to database model and calculated amounts
+func (b *Budget) ToBudgetProgressResponse(periodStartTime int64, periodEndTime int64, rolloverAmount int64, spentAmount int64, otherCurrencySpentAmounts map[string]int64) *BudgetProgressResponse {
+	availableAmount := b.LimitAmount + rolloverAmount
+	otherCurrencies := make([]string, 0, len(otherCurrencySpentAmounts))
+
+	for currency := range otherCurrencySpentAmounts {
+		otherCurrencies = append(otherCurrencies, currency)
+	}
+
+	sort.Strings(otherCurrencies)
+	otherCurrencyAmounts := make([]*BudgetProgressCurrencyAmountItem, len(otherCurrencies))
+
+	for i := 0; i < len(otherCurrencies); i++ {
+		otherCurrencyAmounts[i] = &BudgetProgressCurrencyAmountItem{
+			Currency: otherCurrencies[i],
+			Amount:   utils.Int64ToString(otherCurrencySpentAmounts[otherCurrencies[i]]),
+		}
+	}
+
+	return &BudgetProgressResponse{
+		BudgetInfoResponse:        b.ToBudgetInfoResponse(),
+		PeriodStartTime:           periodStartTime,
+		PeriodEndTime:             periodEndTime,
+		RolloverAmount:            utils.Int64ToString(rolloverAmount),
+		AvailableAmount:           utils.Int64ToString(availableAmount),
+		SpentAmount:               utils.Int64ToString(spentAmount),
+		RemainingAmount:           utils.Int64ToString(availableAmount - spentAmount),
+		OtherCurrencySpentAmounts: otherCurrencyAmounts,
+	}
+}
+
+// BudgetInfoResponseSlice represents the slice data structure of BudgetInfoResponse
+type BudgetInfoResponseSlice []*BudgetInfoResponse
+
+// Len returns the count of items
+func (s BudgetInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s BudgetInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s BudgetInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
+
+// BudgetProgressResponseSlice represents the slice data structure of BudgetProgressResponse
+type BudgetProgressResponseSlice []*BudgetProgressResponse
+
+// Len returns the count of items
+func (s BudgetProgressResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s BudgetProgressResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s BudgetProgressResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestBudgetGetPeriodTimeRange_Monthly(t *testing.T) {
+	budget := &Budget{PeriodType: BUDGET_PERIOD_TYPE_MONTHLY}
+	unixTime := time.Date(2024, time.February, 15, 12, 30, 0, 0, time.UTC).Unix()
+
+	startTime, endTime := budget.GetPeriodTimeRange(unixTime, time.UTC, core.WEEKDAY_SUNDAY, core.FISCAL_YEAR_START_DEFAULT)
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC).Unix()-1, endTime)
+}
+
+func TestBudgetGetPeriodTimeRange_MonthlyWithTimezone(t *testing.T) {
+	budget := &Budget{PeriodType: BUDGET_PERIOD_TYPE_MONTHLY}
+	timezone := time.FixedZone("Test Timezone", 8*60*60)
+	unixTime := time.Date(2024, time.January, 31, 20, 0, 0, 0, time.UTC).Unix()
+
+	startTime, endTime := budget.GetPeriodTimeRange(unixTime, timezone, core.WEEKDAY_SUNDAY, core.FISCAL_YEAR_START_DEFAULT)
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, timezone).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, timezone).Unix()-1, endTime)
+}
+
+func TestBudgetGetPeriodTimeRange_Weekly(t *testing.T) {
+	budget := &Budget{PeriodType: BUDGET_PERIOD_TYPE_WEEKLY}
+	unixTime := time.Date(2024, time.May, 15, 8, 0, 0, 0, time.UTC).Unix() // Wednesday
+
+	startTime, endTime := budget.GetPeriodTimeRange(unixTime, time.UTC, core.WEEKDAY_SUNDAY, core.FISCAL_YEAR_START_DEFAULT)
+	assert.Equal(t, time.Date(2024, time.May, 12, 0, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, time.May, 19, 0, 0, 0, 0, time.UTC).Unix()-1, endTime)
+
+	startTime, endTime = budget.GetPeriodTimeRange(unixTime, time.UTC, core.WEEKDAY_MONDAY, core.FISCAL_YEAR_START_DEFAULT)
+	assert.Equal(t, time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC).Unix()-1, endTime)
+}
+
+func TestBudgetGetPeriodTimeRange_Yearly(t *testing.T) {
+	budget := &Budget{PeriodType: BUDGET_PERIOD_TYPE_YEARLY}
+	unixTime := time.Date(2024, time.July, 4, 0, 0, 0, 0, time.UTC).Unix()
+
+	startTime, endTime := budget.GetPeriodTimeRange(unixTime, time.UTC, core.WEEKDAY_SUNDAY, core.FISCAL_YEAR_START_DEFAULT)
+	assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()-1, endTime)
+}
+
+func TestBudgetGetPeriodTimeRange_FiscalYear(t *testing.T) {
+	budget := &Budget{PeriodType: BUDGET_PERIOD_TYPE_FISCAL_YEAR}
+	fiscalYearStart, err := core.NewFiscalYearStart(4, 1)
+	assert.Nil(t, err)
+
+	unixTime := time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC).Unix()
+	startTime, endTime := budget.GetPeriodTimeRange(unixTime, time.UTC, core.WEEKDAY_SUNDAY, fiscalYearStart)
+	assert.Equal(t, time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC).Unix()-1, endTime)
+
+	unixTime = time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC).Unix()
+	startTime, endTime = budget.GetPeriodTimeRange(unixTime, time.UTC, core.WEEKDAY_SUNDAY, fiscalYearStart)
+	assert.Equal(t, time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC).Unix(), startTime)
+	assert.Equal(t, time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC).Unix()-1, endTime)
+}
+
+func TestBudgetToBudgetProgressResponse(t *testing.T) {
+	budget := &Budget{
+		BudgetId:    1,
+		Name:        "Food",
+		Currency:    "USD",
+		LimitAmount: 50000,
+	}
+
+	actualValue := budget.ToBudgetProgressResponse(100, 200, 1000, 30000, map[string]int64{"EUR": 500, "CNY": 2000})
+	assert.Equal(t, "1000", actualValue.RolloverAmount)
+	assert.Equal(t, "51000", actualValue.AvailableAmount)
+	assert.Equal(t, "30000", actualValue.SpentAmount)
+	assert.Equal(t, "21000", actualValue.RemainingAmount)
+	assert.Equal(t, 2, len(actualValue.OtherCurrencySpentAmounts))
+	assert.Equal(t, "CNY", actualValue.OtherCurrencySpentAmounts[0].Currency)
+	assert.Equal(t, "2000", actualValue.OtherCurrencySpentAmounts[0].Amount)
+	assert.Equal(t, "EUR", actualValue.OtherCurrencySpentAmounts[1].Currency)
+	assert.Equal(t, "500", actualValue.OtherCurrencySpentAmounts[1].Amount)
+}
+
+func TestBudgetGetRolloverPeriodTimeRanges(t *testing.T) {
+	budget := &Budget{
+		PeriodType: BUDGET_PERIOD_TYPE_MONTHLY,
+		Rollover:   false,
+		StartTime:  time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	periodStartTime := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	actualValue := budget.GetRolloverPeriodTimeRanges(periodStartTime, time.UTC, core.WEEKDAY_SUNDAY, core.FISCAL_YEAR_START_DEFAULT)
+	assert.Equal(t, 0, len(actualValue))
+
+	budget.Rollover = true
+	actualValue = budget.GetRolloverPeriodTimeRanges(periodStartTime, time.UTC, core.WEEKDAY_SUNDAY, core.FISCAL_YEAR_START_DEFAULT)
+	assert.Equal(t, 3, len(actualValue))
+	assert.Equal(t, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC).Unix(), actualValue[0][0])
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC).Unix()-1, actualValue[0][1])
+	assert.Equal(t, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC).Unix(), actualValue[1][0])
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC).Unix(), actualValue[2][0])
+	assert.Equal(t, periodStartTime-1, actualValue[2][1])
+}
+
+func TestBudgetGetRolloverPeriodTimeRanges_MaximumPeriods(t *testing.T) {
+	budget := &Budget{
+		PeriodType: BUDGET_PERIOD_TYPE_WEEKLY,
+		Rollover:   true,
+		StartTime:  time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	periodStartTime := time.Date(2024, time.April, 7, 0, 0, 0, 0, time.UTC).Unix()
+
+	actualValue := budget.GetRolloverPeriodTimeRanges(periodStartTime, time.UTC, core.WEEKDAY_SUNDAY, core.FISCAL_YEAR_START_DEFAULT)
+	assert.Equal(t, MaximumBudgetRolloverPeriods, len(actualValue))
+	assert.Equal(t, periodStartTime-1, actualValue[MaximumBudgetRolloverPeriods-1][1])
+}
+
+func TestParseBudgetAlertThresholds(t *testing.T) {
+	actualValue, err := ParseBudgetAlertThresholds("")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(actualValue))
+
+	actualValue, err = ParseBudgetAlertThresholds("100,80")
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{80, 100}, actualValue)
+
+	actualValue, err = ParseBudgetAlertThresholds("80, 100,80")
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{80, 100}, actualValue)
+}
+
+func TestParseBudgetAlertThresholds_InvalidThresholds(t *testing.T) {
+	_, err := ParseBudgetAlertThresholds("0")
+	assert.EqualError(t, err, errs.ErrBudgetAlertThresholdsInvalid.Message)
+
+	_, err = ParseBudgetAlertThresholds("1001")
+	assert.EqualError(t, err, errs.ErrBudgetAlertThresholdsInvalid.Message)
+
+	_, err = ParseBudgetAlertThresholds("80,abc")
+	assert.EqualError(t, err, errs.ErrBudgetAlertThresholdsInvalid.Message)
+
+	_, err = ParseBudgetAlertThresholds("10,20,30,40,50,60")
+	assert.EqualError(t, err, errs.ErrBudgetAlertThresholdsInvalid.Message)
+}
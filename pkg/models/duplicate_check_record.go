@@ -0,0 +1,13 @@
+package models
+
+// DuplicateCheckRecordMaxKeyLength represents the maximum size of duplicate check key stored in database
+const DuplicateCheckRecordMaxKeyLength = 255
+
+// DuplicateCheckRecord represents duplicate checker data stored in database
+type DuplicateCheckRecord struct {
+	CheckKey        string `xorm:"VARCHAR(255) PK"`
+	Remark          string `xorm:"TEXT"`
+	Count           uint32 `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	ExpiredUnixTime int64 `xorm:"INDEX(IDX_duplicate_check_record_expired_time)"`
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// ExchangeRateHistory represents the daily exchange rate snapshot of the exchange rates data source stored in database
+type ExchangeRateHistory struct {
+	DataSource      string `xorm:"PK VARCHAR(32) NOT NULL"`
+	RateDate        int32  `xorm:"PK NOT NULL"`
+	Currency        string `xorm:"PK VARCHAR(3) NOT NULL"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	UpdateUnixTime  int64
+	CreatedUnixTime int64
+}
+
+// HistoricalExchangeRateRequest represents all parameters of historical exchange rate data request
+type HistoricalExchangeRateRequest struct {
+	Date string `form:"date" binding:"required,len=10"`
+}
+
+// HistoricalExchangeRateResponse returns a view-object which contains the exchange rates of specified date
+type HistoricalExchangeRateResponse struct {
+	Date          string                  `json:"date"`
+	UpdateTime    int64                   `json:"updateTime"`
+	BaseCurrency  string                  `json:"baseCurrency"`
+	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
+}
+
+// CreateExchangeRateHistories returns the exchange rate history database models of the date when the exchange rates were updated
+func CreateExchangeRateHistories(dataSource string, exchangeRateResp *LatestExchangeRateResponse) []*ExchangeRateHistory {
+	rateDate := utils.FormatUnixTimeToNumericYearMonthDay(exchangeRateResp.UpdateTime, time.UTC)
+	exchangeRateHistories := make([]*ExchangeRateHistory, 0, len(exchangeRateResp.ExchangeRates))
+
+	for i := 0; i < len(exchangeRateResp.ExchangeRates); i++ {
+		exchangeRate := exchangeRateResp.ExchangeRates[i]
+		rate, err := utils.StringToFloat64(exchangeRate.Rate)
+
+		if err != nil || rate <= 0 {
+			continue
+		}
+
+		exchangeRateHistories = append(exchangeRateHistories, &ExchangeRateHistory{
+			DataSource:     dataSource,
+			RateDate:       rateDate,
+			Currency:       exchangeRate.Currency,
+			BaseCurrency:   exchangeRateResp.BaseCurrency,
+			Rate:           exchangeRate.Rate,
+			UpdateUnixTime: exchangeRateResp.UpdateTime,
+		})
+	}
+
+	return exchangeRateHistories
+}
+
+// ToHistoricalExchangeRateResponse returns a view-object according to the exchange rate histories of the same date
+func ToHistoricalExchangeRateResponse(exchangeRateHistories []*ExchangeRateHistory) *HistoricalExchangeRateResponse {
+	if len(exchangeRateHistories) < 1 {
+		return nil
+	}
+
+	firstExchangeRateHistory := exchangeRateHistories[0]
+	resp := &HistoricalExchangeRateResponse{
+		Date:          utils.FormatNumericYearMonthDayToLongDate(firstExchangeRateHistory.RateDate),
+		BaseCurrency:  firstExchangeRateHistory.BaseCurrency,
+		ExchangeRates: make(LatestExchangeRateSlice, 0, len(exchangeRateHistories)),
+	}
+
+	for i := 0; i < len(exchangeRateHistories); i++ {
+		exchangeRateHistory := exchangeRateHistories[i]
+
+		if exchangeRateHistory.UpdateUnixTime > resp.UpdateTime {
+			resp.UpdateTime = exchangeRateHistory.UpdateUnixTime
+		}
+
+		resp.ExchangeRates = append(resp.ExchangeRates, &LatestExchangeRate{
+			Currency: exchangeRateHistory.Currency,
+			Rate:     exchangeRateHistory.Rate,
+		})
+	}
+
+	sort.Sort(resp.ExchangeRates)
+
+	return resp
+}
+
+// HistoricalExchangeRateConverter converts the amount of accounts to the target currency at the exchange rates of the transaction date
+type HistoricalExchangeRateConverter struct {
+	targetCurrency    string
+	accountCurrencies map[int64]string
+	currencyRates     map[string][]*historicalExchangeRate
+}
+
+type historicalExchangeRate struct {
+	rateDate int32
+	rate     float64
+}
+
+// NewHistoricalExchangeRateConverter returns a new historical exchange rate converter according to the exchange rate histories of the same data source
+func NewHistoricalExchangeRateConverter(exchangeRateHistories []*ExchangeRateHistory, accountMap map[int64]*Account, targetCurrency string) *HistoricalExchangeRateConverter {
+	converter := &HistoricalExchangeRateConverter{
+		targetCurrency:    targetCurrency,
+		accountCurrencies: make(map[int64]string, len(accountMap)),
+		currencyRates:     make(map[string][]*historicalExchangeRate),
+	}
+
+	for accountId, account := range accountMap {
+		converter.accountCurrencies[accountId] = account.Currency
+	}
+
+	for i := 0; i < len(exchangeRateHistories); i++ {
+		exchangeRateHistory := exchangeRateHistories[i]
+		rate, err := utils.StringToFloat64(exchangeRateHistory.Rate)
+
+		if err != nil || rate <= 0 {
+			continue
+		}
+
+		converter.currencyRates[exchangeRateHistory.Currency] = append(converter.currencyRates[exchangeRateHistory.Currency], &historicalExchangeRate{
+			rateDate: exchangeRateHistory.RateDate,
+			rate:     rate,
+		})
+	}
+
+	for _, rates := range converter.currencyRates {
+		sort.Slice(rates, func(i, j int) bool {
+			return rates[i].rateDate < rates[j].rateDate
+		})
+	}
+
+	return converter
+}
+
+// ConvertAmount returns the amount in target currency at the exchange rates of specified date, and whether the amount has been converted
+func (c *HistoricalExchangeRateConverter) ConvertAmount(accountId int64, amount int64, yearMonthDay int32) (int64, bool) {
+	accountCurrency, exists := c.accountCurrencies[accountId]
+
+	if !exists {
+		return 0, false
+	}
+
+	if accountCurrency == c.targetCurrency {
+		return amount, true
+	}
+
+	fromRate := c.getRate(accountCurrency, yearMonthDay)
+	toRate := c.getRate(c.targetCurrency, yearMonthDay)
+
+	if fromRate <= 0 || toRate <= 0 {
+		return 0, false
+	}
+
+	return int64(math.Round(float64(amount) / fromRate * toRate)), true
+}
+
+func (c *HistoricalExchangeRateConverter) getRate(currency string, yearMonthDay int32) float64 {
+	rates := c.currencyRates[currency]
+
+	if len(rates) < 1 {
+		return 0
+	}
+
+	index := sort.Search(len(rates), func(i int) bool {
+		return rates[i].rateDate > yearMonthDay
+	})
+
+	// use the earliest exchange rate if there is no exchange rate before specified date
+	if index < 1 {
+		return rates[0].rate
+	}
+
+	return rates[index-1].rate
+}
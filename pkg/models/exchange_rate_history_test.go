@@ -0,0 +1,84 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateExchangeRateHistories(t *testing.T) {
+	exchangeRateResp := &LatestExchangeRateResponse{
+		UpdateTime:   1617285600,
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "1"},
+			{Currency: "USD", Rate: "1.1746"},
+			{Currency: "CNY", Rate: "0"},
+			{Currency: "JPY", Rate: "null"},
+		},
+	}
+
+	exchangeRateHistories := CreateExchangeRateHistories("euro_central_bank", exchangeRateResp)
+	assert.Equal(t, 2, len(exchangeRateHistories))
+	assert.Equal(t, "euro_central_bank", exchangeRateHistories[1].DataSource)
+	assert.Equal(t, int32(20210401), exchangeRateHistories[1].RateDate)
+	assert.Equal(t, "USD", exchangeRateHistories[1].Currency)
+	assert.Equal(t, "EUR", exchangeRateHistories[1].BaseCurrency)
+	assert.Equal(t, "1.1746", exchangeRateHistories[1].Rate)
+	assert.Equal(t, int64(1617285600), exchangeRateHistories[1].UpdateUnixTime)
+}
+
+func TestToHistoricalExchangeRateResponse(t *testing.T) {
+	exchangeRateHistories := []*ExchangeRateHistory{
+		{RateDate: 20210401, Currency: "USD", BaseCurrency: "EUR", Rate: "1.1746", UpdateUnixTime: 1617285600},
+		{RateDate: 20210401, Currency: "CNY", BaseCurrency: "EUR", Rate: "7.7195", UpdateUnixTime: 1617285600},
+	}
+
+	resp := ToHistoricalExchangeRateResponse(exchangeRateHistories)
+	assert.Equal(t, "2021-04-01", resp.Date)
+	assert.Equal(t, int64(1617285600), resp.UpdateTime)
+	assert.Equal(t, "EUR", resp.BaseCurrency)
+	assert.Equal(t, "CNY", resp.ExchangeRates[0].Currency)
+	assert.Equal(t, "USD", resp.ExchangeRates[1].Currency)
+
+	assert.Nil(t, ToHistoricalExchangeRateResponse(nil))
+}
+
+func TestHistoricalExchangeRateConverterConvertAmount(t *testing.T) {
+	exchangeRateHistories := []*ExchangeRateHistory{
+		{RateDate: 20240102, Currency: "EUR", Rate: "1"},
+		{RateDate: 20240102, Currency: "USD", Rate: "1.25"},
+		{RateDate: 20240101, Currency: "EUR", Rate: "1"},
+		{RateDate: 20240101, Currency: "USD", Rate: "1.1"},
+		{RateDate: 20240103, Currency: "EUR", Rate: "1"},
+		{RateDate: 20240103, Currency: "USD", Rate: "1.5"},
+	}
+	accountMap := map[int64]*Account{
+		1: {AccountId: 1, Currency: "USD"},
+		2: {AccountId: 2, Currency: "EUR"},
+		3: {AccountId: 3, Currency: "JPY"},
+	}
+	converter := NewHistoricalExchangeRateConverter(exchangeRateHistories, accountMap, "EUR")
+
+	amount, converted := converter.ConvertAmount(1, 1000, 20240102)
+	assert.True(t, converted)
+	assert.Equal(t, int64(800), amount)
+
+	amount, converted = converter.ConvertAmount(1, 1500, 20240105)
+	assert.True(t, converted)
+	assert.Equal(t, int64(1000), amount)
+
+	amount, converted = converter.ConvertAmount(1, 1100, 20231231)
+	assert.True(t, converted)
+	assert.Equal(t, int64(1000), amount)
+
+	amount, converted = converter.ConvertAmount(2, 1234, 20240102)
+	assert.True(t, converted)
+	assert.Equal(t, int64(1234), amount)
+
+	_, converted = converter.ConvertAmount(3, 1000, 20240102)
+	assert.False(t, converted)
+
+	_, converted = converter.ConvertAmount(4, 1000, 20240102)
+	assert.False(t, converted)
+}
@@ -12,6 +12,14 @@ type ImportTransaction struct {
 	OriginalDestinationAccountName     string
 	OriginalDestinationAccountCurrency string
 	OriginalTagNames                   []string
+	Splits                             []*ImportTransactionSplit
+	ProbableDuplicateTransactionId     int64
+}
+
+// ImportTransactionSplit represents the imported split line data of transaction
+type ImportTransactionSplit struct {
+	*TransactionSplit
+	OriginalCategoryName string
 }
 
 // ImportTransactionRequest represents all parameters of the imported transaction data
@@ -36,23 +44,34 @@ type ImportTransactionRequestItem struct {
 
 // ImportTransactionResponse represents a view-object of the imported transaction data
 type ImportTransactionResponse struct {
-	Type                               TransactionType                 `json:"type"`
-	CategoryId                         int64                           `json:"categoryId,string"`
-	OriginalCategoryName               string                          `json:"originalCategoryName"`
-	Time                               int64                           `json:"time"`
-	UtcOffset                          int16                           `json:"utcOffset"`
-	SourceAccountId                    int64                           `json:"sourceAccountId,string"`
-	OriginalSourceAccountName          string                          `json:"originalSourceAccountName"`
-	OriginalSourceAccountCurrency      string                          `json:"originalSourceAccountCurrency"`
-	DestinationAccountId               int64                           `json:"destinationAccountId,string,omitempty"`
-	OriginalDestinationAccountName     string                          `json:"originalDestinationAccountName,omitempty"`
-	OriginalDestinationAccountCurrency string                          `json:"originalDestinationAccountCurrency,omitempty"`
-	SourceAmount                       int64                           `json:"sourceAmount"`
-	DestinationAmount                  int64                           `json:"destinationAmount,omitempty"`
-	TagIds                             []string                        `json:"tagIds"`
-	OriginalTagNames                   []string                        `json:"originalTagNames"`
-	Comment                            string                          `json:"comment"`
-	GeoLocation                        *TransactionGeoLocationResponse `json:"geoLocation,omitempty"`
+	Type                               TransactionType                   `json:"type"`
+	CategoryId                         int64                             `json:"categoryId,string"`
+	OriginalCategoryName               string                            `json:"originalCategoryName"`
+	Time                               int64                             `json:"time"`
+	UtcOffset                          int16                             `json:"utcOffset"`
+	SourceAccountId                    int64                             `json:"sourceAccountId,string"`
+	OriginalSourceAccountName          string                            `json:"originalSourceAccountName"`
+	OriginalSourceAccountCurrency      string                            `json:"originalSourceAccountCurrency"`
+	DestinationAccountId               int64                             `json:"destinationAccountId,string,omitempty"`
+	OriginalDestinationAccountName     string                            `json:"originalDestinationAccountName,omitempty"`
+	OriginalDestinationAccountCurrency string                            `json:"originalDestinationAccountCurrency,omitempty"`
+	SourceAmount                       int64                             `json:"sourceAmount"`
+	DestinationAmount                  int64                             `json:"destinationAmount,omitempty"`
+	TagIds                             []string                          `json:"tagIds"`
+	OriginalTagNames                   []string                          `json:"originalTagNames"`
+	Comment                            string                            `json:"comment"`
+	GeoLocation                        *TransactionGeoLocationResponse   `json:"geoLocation,omitempty"`
+	Splits                             []*ImportTransactionSplitResponse `json:"splits,omitempty"`
+	ExternalReference                  string                            `json:"externalReference,omitempty"`
+	ProbableDuplicateTransactionId     int64                             `json:"probableDuplicateTransactionId,string,omitempty"`
+}
+
+// ImportTransactionSplitResponse represents a view-object of the imported split line data of transaction
+type ImportTransactionSplitResponse struct {
+	CategoryId           int64  `json:"categoryId,string"`
+	OriginalCategoryName string `json:"originalCategoryName"`
+	Amount               int64  `json:"amount"`
+	Comment              string `json:"comment"`
 }
 
 // ImportTransactionResponsePageWrapper represents a response of imported transaction which contains items and count
@@ -78,6 +97,21 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 		geoLocation = nil
 	}
 
+	var splits []*ImportTransactionSplitResponse
+
+	if len(t.Splits) > 0 {
+		splits = make([]*ImportTransactionSplitResponse, len(t.Splits))
+
+		for i := 0; i < len(t.Splits); i++ {
+			splits[i] = &ImportTransactionSplitResponse{
+				CategoryId:           t.Splits[i].CategoryId,
+				OriginalCategoryName: t.Splits[i].OriginalCategoryName,
+				Amount:               t.Splits[i].Amount,
+				Comment:              t.Splits[i].Comment,
+			}
+		}
+	}
+
 	return &ImportTransactionResponse{
 		Type:                               transactionType,
 		CategoryId:                         t.CategoryId,
@@ -96,6 +130,9 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 		OriginalTagNames:                   t.OriginalTagNames,
 		Comment:                            t.Comment,
 		GeoLocation:                        geoLocation,
+		Splits:                             splits,
+		ExternalReference:                  t.ExternalReference,
+		ProbableDuplicateTransactionId:     t.ProbableDuplicateTransactionId,
 	}
 }
 
@@ -177,6 +214,27 @@ func (s ImportedTransactionSlice) ToTransactionTagIdsMap() (map[int][]int64, err
 	return transactionTagIdsMap, nil
 }
 
+// ToTransactionSplitsMap returns a map of transaction split lines
+func (s ImportedTransactionSlice) ToTransactionSplitsMap() map[int][]*TransactionSplit {
+	transactionSplitsMap := make(map[int][]*TransactionSplit)
+
+	for i := 0; i < s.Len(); i++ {
+		if len(s[i].Splits) < 1 {
+			continue
+		}
+
+		splits := make([]*TransactionSplit, len(s[i].Splits))
+
+		for j := 0; j < len(s[i].Splits); j++ {
+			splits[j] = s[i].Splits[j].TransactionSplit
+		}
+
+		transactionSplitsMap[i] = splits
+	}
+
+	return transactionSplitsMap
+}
+
 // ToImportTransactionResponseList returns the a list of view-objects according to imported transaction data
 func (s ImportedTransactionSlice) ToImportTransactionResponseList() []*ImportTransactionResponse {
 	transactionResps := make([]*ImportTransactionResponse, 0, s.Len())
@@ -0,0 +1,429 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// InvestmentQuantityScale represents the scale of stored security quantity (e.g. 15000 means 1.5 units)
+const InvestmentQuantityScale = 10000
+
+// InvestmentTransactionType represents investment transaction type
+type InvestmentTransactionType byte
+
+// Investment transaction types
+const (
+	INVESTMENT_TRANSACTION_TYPE_BUY      InvestmentTransactionType = 1
+	INVESTMENT_TRANSACTION_TYPE_SELL     InvestmentTransactionType = 2
+	INVESTMENT_TRANSACTION_TYPE_DIVIDEND InvestmentTransactionType = 3
+)
+
+// String returns a textual representation of the investment transaction type enum
+func (t InvestmentTransactionType) String() string {
+	switch t {
+	case INVESTMENT_TRANSACTION_TYPE_BUY:
+		return "Buy"
+	case INVESTMENT_TRANSACTION_TYPE_SELL:
+		return "Sell"
+	case INVESTMENT_TRANSACTION_TYPE_DIVIDEND:
+		return "Dividend"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(t))
+	}
+}
+
+// IsValid returns whether the investment transaction type is valid
+func (t InvestmentTransactionType) IsValid() bool {
+	return t >= INVESTMENT_TRANSACTION_TYPE_BUY && t <= INVESTMENT_TRANSACTION_TYPE_DIVIDEND
+}
+
+// InvestmentSecurity represents security data stored in database
+type InvestmentSecurity struct {
+	SecurityId      int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_investment_security_uid_deleted_account_id) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_investment_security_uid_deleted_account_id) NOT NULL"`
+	AccountId       int64  `xorm:"INDEX(IDX_investment_security_uid_deleted_account_id) NOT NULL"`
+	Symbol          string `xorm:"VARCHAR(32) NOT NULL"`
+	Name            string `xorm:"VARCHAR(64) NOT NULL"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// InvestmentTransaction represents investment transaction data stored in database
+type InvestmentTransaction struct {
+	InvestmentTransactionId int64                     `xorm:"PK"`
+	Uid                     int64                     `xorm:"INDEX(IDX_investment_transaction_uid_deleted_security_id_time) NOT NULL"`
+	Deleted                 bool                      `xorm:"INDEX(IDX_investment_transaction_uid_deleted_security_id_time) NOT NULL"`
+	SecurityId              int64                     `xorm:"INDEX(IDX_investment_transaction_uid_deleted_security_id_time) NOT NULL"`
+	AccountId               int64                     `xorm:"NOT NULL"`
+	Type                    InvestmentTransactionType `xorm:"NOT NULL"`
+	TransactionTime         int64                     `xorm:"INDEX(IDX_investment_transaction_uid_deleted_security_id_time) NOT NULL"`
+	Quantity                int64                     `xorm:"NOT NULL"`
+	Amount                  int64                     `xorm:"NOT NULL"`
+	Fee                     int64                     `xorm:"NOT NULL"`
+	Comment                 string                    `xorm:"VARCHAR(255) NOT NULL"`
+	CreatedUnixTime         int64
+	UpdatedUnixTime         int64
+	DeletedUnixTime         int64
+}
+
+// InvestmentSecurityGetRequest represents all parameters of security getting request
+type InvestmentSecurityGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentSecurityListRequest represents all parameters of security listing request
+type InvestmentSecurityListRequest struct {
+	AccountId int64 `form:"account_id,string" binding:"min=0"`
+}
+
+// InvestmentSecurityCreateRequest represents all parameters of security creation request
+type InvestmentSecurityCreateRequest struct {
+	AccountId       int64  `json:"accountId,string" binding:"required,min=1"`
+	Symbol          string `json:"symbol" binding:"required,notBlank,max=32"`
+	Name            string `json:"name" binding:"required,notBlank,max=64"`
+	Comment         string `json:"comment" binding:"max=255"`
+	ClientSessionId string `json:"clientSessionId"`
+}
+
+// InvestmentSecurityModifyRequest represents all parameters of security modification request
+type InvestmentSecurityModifyRequest struct {
+	Id      int64  `json:"id,string" binding:"required,min=1"`
+	Symbol  string `json:"symbol" binding:"required,notBlank,max=32"`
+	Name    string `json:"name" binding:"required,notBlank,max=64"`
+	Comment string `json:"comment" binding:"max=255"`
+}
+
+// InvestmentSecurityDeleteRequest represents all parameters of security deleting request
+type InvestmentSecurityDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentTransactionListRequest represents all parameters of investment transaction listing request
+type InvestmentTransactionListRequest struct {
+	SecurityId int64 `form:"security_id,string" binding:"required,min=1"`
+}
+
+// InvestmentTransactionCreateRequest represents all parameters of investment transaction creation request
+type InvestmentTransactionCreateRequest struct {
+	SecurityId      int64                     `json:"securityId,string" binding:"required,min=1"`
+	Type            InvestmentTransactionType `json:"type" binding:"required"`
+	TransactionTime int64                     `json:"time" binding:"required,min=1"`
+	Quantity        int64                     `json:"quantity" binding:"min=0"`
+	Amount          int64                     `json:"amount" binding:"min=0,validTransactionAmount"`
+	Fee             int64                     `json:"fee" binding:"min=0,validTransactionAmount"`
+	Comment         string                    `json:"comment" binding:"max=255"`
+	ClientSessionId string                    `json:"clientSessionId"`
+}
+
+// InvestmentTransactionDeleteRequest represents all parameters of investment transaction deleting request
+type InvestmentTransactionDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// InvestmentHoldingListRequest represents all parameters of investment holding listing request
+type InvestmentHoldingListRequest struct {
+	AccountId int64 `form:"account_id,string" binding:"min=0"`
+}
+
+// InvestmentSecurityInfoResponse represents a view-object of security
+type InvestmentSecurityInfoResponse struct {
+	Id        int64  `json:"id,string"`
+	AccountId int64  `json:"accountId,string"`
+	Symbol    string `json:"symbol"`
+	Name      string `json:"name"`
+	Comment   string `json:"comment"`
+}
+
+// InvestmentTransactionInfoResponse represents a view-object of investment transaction
+type InvestmentTransactionInfoResponse struct {
+	Id              int64                     `json:"id,string"`
+	SecurityId      int64                     `json:"securityId,string"`
+	AccountId       int64                     `json:"accountId,string"`
+	Type            InvestmentTransactionType `json:"type"`
+	TransactionTime int64                     `json:"time"`
+	Quantity        int64                     `json:"quantity"`
+	Amount          int64                     `json:"amount"`
+	Fee             int64                     `json:"fee"`
+	Comment         string                    `json:"comment"`
+}
+
+// InvestmentHoldingLotResponse represents a view-object of an open lot of security holding
+type InvestmentHoldingLotResponse struct {
+	AcquiredTime int64  `json:"acquiredTime"`
+	Quantity     int64  `json:"quantity"`
+	CostBasis    string `json:"costBasis"`
+}
+
+// InvestmentHoldingResponse represents a view-object of security holding
+type InvestmentHoldingResponse struct {
+	SecurityId      int64                           `json:"securityId,string"`
+	AccountId       int64                           `json:"accountId,string"`
+	Symbol          string                          `json:"symbol"`
+	Name            string                          `json:"name"`
+	Quantity        int64                           `json:"quantity"`
+	CostBasis       string                          `json:"costBasis"`
+	LatestPrice     string                          `json:"latestPrice,omitempty"`
+	LatestPriceTime int64                           `json:"latestPriceTime,omitempty"`
+	MarketValue     string                          `json:"marketValue,omitempty"`
+	UnrealizedGain  string                          `json:"unrealizedGain,omitempty"`
+	RealizedGain    string                          `json:"realizedGain"`
+	Dividends       string                          `json:"dividends"`
+	Lots            []*InvestmentHoldingLotResponse `json:"lots"`
+}
+
+// InvestmentHoldingLot represents an open lot of security holding
+type InvestmentHoldingLot struct {
+	AcquiredTime int64
+	Quantity     int64
+	CostBasis    int64
+}
+
+// InvestmentHolding represents the holding of a security which is calculated by first-in-first-out lots
+type InvestmentHolding struct {
+	SecurityId   int64
+	Quantity     int64
+	CostBasis    int64
+	RealizedGain int64
+	Dividends    int64
+	Lots         []*InvestmentHoldingLot
+}
+
+// NewInvestmentHolding returns a new empty holding of the specified security
+func NewInvestmentHolding(securityId int64) *InvestmentHolding {
+	return &InvestmentHolding{
+		SecurityId: securityId,
+		Lots:       make([]*InvestmentHoldingLot, 0),
+	}
+}
+
+// Apply updates the holding according to the investment transaction, the transactions must be applied in chronological order
+func (h *InvestmentHolding) Apply(transaction *InvestmentTransaction) error {
+	if transaction.Type == INVESTMENT_TRANSACTION_TYPE_BUY {
+		if transaction.Quantity <= 0 {
+			return errs.ErrInvestmentTransactionQuantityInvalid
+		}
+
+		costBasis := transaction.Amount + transaction.Fee
+
+		h.Lots = append(h.Lots, &InvestmentHoldingLot{
+			AcquiredTime: transaction.TransactionTime,
+			Quantity:     transaction.Quantity,
+			CostBasis:    costBasis,
+		})
+		h.Quantity += transaction.Quantity
+		h.CostBasis += costBasis
+	} else if transaction.Type == INVESTMENT_TRANSACTION_TYPE_SELL {
+		if transaction.Quantity <= 0 {
+			return errs.ErrInvestmentTransactionQuantityInvalid
+		}
+
+		if transaction.Quantity > h.Quantity {
+			return errs.ErrInvestmentSellQuantityExceedsHolding
+		}
+
+		remainingQuantity := transaction.Quantity
+		soldCostBasis := int64(0)
+
+		for remainingQuantity > 0 && len(h.Lots) > 0 {
+			lot := h.Lots[0]
+
+			if lot.Quantity <= remainingQuantity {
+				remainingQuantity -= lot.Quantity
+				soldCostBasis += lot.CostBasis
+				h.Lots = h.Lots[1:]
+				continue
+			}
+
+			lotCostBasis := lot.CostBasis * remainingQuantity / lot.Quantity
+			lot.Quantity -= remainingQuantity
+			lot.CostBasis -= lotCostBasis
+			soldCostBasis += lotCostBasis
+			remainingQuantity = 0
+		}
+
+		h.Quantity -= transaction.Quantity
+		h.CostBasis -= soldCostBasis
+		h.RealizedGain += transaction.Amount - transaction.Fee - soldCostBasis
+	} else if transaction.Type == INVESTMENT_TRANSACTION_TYPE_DIVIDEND {
+		h.Dividends += transaction.Amount - transaction.Fee
+	} else {
+		return errs.ErrInvestmentTransactionTypeInvalid
+	}
+
+	return nil
+}
+
+// GetMarketValue returns the market value of the holding according to the specified unit price
+func (h *InvestmentHolding) GetMarketValue(price int64) int64 {
+	return GetInvestmentMarketValue(h.Quantity, price)
+}
+
+// ToInvestmentHoldingResponse returns a view-object according to holding and the security
+func (h *InvestmentHolding) ToInvestmentHoldingResponse(security *InvestmentSecurity, latestPrice *SecurityPrice) *InvestmentHoldingResponse {
+	resp := &InvestmentHoldingResponse{
+		SecurityId:   security.SecurityId,
+		AccountId:    security.AccountId,
+		Symbol:       security.Symbol,
+		Name:         security.Name,
+		Quantity:     h.Quantity,
+		CostBasis:    utils.FormatAmount(h.CostBasis),
+		RealizedGain: utils.FormatAmount(h.RealizedGain),
+		Dividends:    utils.FormatAmount(h.Dividends),
+		Lots:         make([]*InvestmentHoldingLotResponse, len(h.Lots)),
+	}
+
+	for i := 0; i < len(h.Lots); i++ {
+		resp.Lots[i] = &InvestmentHoldingLotResponse{
+			AcquiredTime: h.Lots[i].AcquiredTime,
+			Quantity:     h.Lots[i].Quantity,
+			CostBasis:    utils.FormatAmount(h.Lots[i].CostBasis),
+		}
+	}
+
+	if latestPrice != nil {
+		marketValue := h.GetMarketValue(latestPrice.Price)
+		resp.LatestPrice = utils.FormatAmount(latestPrice.Price)
+		resp.LatestPriceTime = latestPrice.PriceTime
+		resp.MarketValue = utils.FormatAmount(marketValue)
+		resp.UnrealizedGain = utils.FormatAmount(marketValue - h.CostBasis)
+	}
+
+	return resp
+}
+
+// GetInvestmentMarketValue returns the market value of the specified quantity according to the unit price
+func GetInvestmentMarketValue(quantity int64, price int64) int64 {
+	return quantity/InvestmentQuantityScale*price + quantity%InvestmentQuantityScale*price/InvestmentQuantityScale
+}
+
+// GetInvestmentUnitPrice returns the unit price of the specified quantity according to the total amount
+func GetInvestmentUnitPrice(quantity int64, amount int64) int64 {
+	if quantity <= 0 {
+		return 0
+	}
+
+	return amount * InvestmentQuantityScale / quantity
+}
+
+// IsQuantityValid returns whether the quantity is valid for the investment transaction type
+func (t *InvestmentTransaction) IsQuantityValid() bool {
+	if t.Type == INVESTMENT_TRANSACTION_TYPE_DIVIDEND {
+		return t.Quantity == 0
+	}
+
+	return t.Quantity > 0
+}
+
+// ToImpliedSecurityPrice returns the security price implied by the buy or sell transaction, or nil if no price can be implied
+func (t *InvestmentTransaction) ToImpliedSecurityPrice() *SecurityPrice {
+	if t.Type != INVESTMENT_TRANSACTION_TYPE_BUY && t.Type != INVESTMENT_TRANSACTION_TYPE_SELL {
+		return nil
+	}
+
+	if t.Quantity <= 0 || t.Amount <= 0 {
+		return nil
+	}
+
+	return &SecurityPrice{
+		Uid:        t.Uid,
+		SecurityId: t.SecurityId,
+		PriceTime:  t.TransactionTime,
+		Price:      GetInvestmentUnitPrice(t.Quantity, t.Amount),
+		DataSource: SecurityPriceTransactionDataSource,
+	}
+}
+
+// ToInvestmentSecurityInfoResponse returns a view-object according to database model
+func (s *InvestmentSecurity) ToInvestmentSecurityInfoResponse() *InvestmentSecurityInfoResponse {
+	return &InvestmentSecurityInfoResponse{
+		Id:        s.SecurityId,
+		AccountId: s.AccountId,
+		Symbol:    s.Symbol,
+		Name:      s.Name,
+		Comment:   s.Comment,
+	}
+}
+
+// ToInvestmentTransactionInfoResponse returns a view-object according to database model
+func (t *InvestmentTransaction) ToInvestmentTransactionInfoResponse() *InvestmentTransactionInfoResponse {
+	return &InvestmentTransactionInfoResponse{
+		Id:              t.InvestmentTransactionId,
+		SecurityId:      t.SecurityId,
+		AccountId:       t.AccountId,
+		Type:            t.Type,
+		TransactionTime: t.TransactionTime,
+		Quantity:        t.Quantity,
+		Amount:          t.Amount,
+		Fee:             t.Fee,
+		Comment:         t.Comment,
+	}
+}
+
+// InvestmentSecurityInfoResponseSlice represents the slice data structure of InvestmentSecurityInfoResponse
+type InvestmentSecurityInfoResponseSlice []*InvestmentSecurityInfoResponse
+
+// Len returns the count of items
+func (s InvestmentSecurityInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s InvestmentSecurityInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s InvestmentSecurityInfoResponseSlice) Less(i, j int) bool {
+	if s[i].AccountId != s[j].AccountId {
+		return s[i].AccountId < s[j].AccountId
+	}
+
+	return strings.Compare(s[i].Symbol, s[j].Symbol) < 0
+}
+
+// InvestmentTransactionSlice represents the slice data structure of InvestmentTransaction
+type InvestmentTransactionSlice []*InvestmentTransaction
+
+// Len returns the count of items
+func (s InvestmentTransactionSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s InvestmentTransactionSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s InvestmentTransactionSlice) Less(i, j int) bool {
+	if s[i].TransactionTime != s[j].TransactionTime {
+		return s[i].TransactionTime < s[j].TransactionTime
+	}
+
+	return s[i].InvestmentTransactionId < s[j].InvestmentTransactionId
+}
+
+// ToInvestmentHolding returns the holding of the specified security by applying all transactions, the transactions must be sorted in chronological order
+func (s InvestmentTransactionSlice) ToInvestmentHolding(securityId int64) (*InvestmentHolding, error) {
+	holding := NewInvestmentHolding(securityId)
+
+	for i := 0; i < len(s); i++ {
+		if s[i].SecurityId != securityId {
+			continue
+		}
+
+		err := holding.Apply(s[i])
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return holding, nil
+}
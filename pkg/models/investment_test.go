@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestInvestmentHoldingApply_BuyAndSellFirstInFirstOut(t *testing.T) {
+	transactions := InvestmentTransactionSlice{
+		{InvestmentTransactionId: 1, SecurityId: 1, Type: INVESTMENT_TRANSACTION_TYPE_BUY, TransactionTime: 100, Quantity: 10 * InvestmentQuantityScale, Amount: 100000, Fee: 500},
+		{InvestmentTransactionId: 2, SecurityId: 1, Type: INVESTMENT_TRANSACTION_TYPE_BUY, TransactionTime: 200, Quantity: 10 * InvestmentQuantityScale, Amount: 120000},
+		{InvestmentTransactionId: 3, SecurityId: 1, Type: INVESTMENT_TRANSACTION_TYPE_SELL, TransactionTime: 300, Quantity: 15 * InvestmentQuantityScale, Amount: 195000, Fee: 1000},
+	}
+
+	holding, err := transactions.ToInvestmentHolding(1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5*InvestmentQuantityScale), holding.Quantity)
+	assert.Equal(t, int64(60000), holding.CostBasis)
+	assert.Equal(t, int64(195000-1000-100500-60000), holding.RealizedGain)
+	assert.Equal(t, 1, len(holding.Lots))
+	assert.Equal(t, int64(200), holding.Lots[0].AcquiredTime)
+	assert.Equal(t, int64(5*InvestmentQuantityScale), holding.Lots[0].Quantity)
+	assert.Equal(t, int64(60000), holding.Lots[0].CostBasis)
+}
+
+func TestInvestmentHoldingApply_SellAllLots(t *testing.T) {
+	holding := NewInvestmentHolding(1)
+
+	err := holding.Apply(&InvestmentTransaction{Type: INVESTMENT_TRANSACTION_TYPE_BUY, Quantity: 3 * InvestmentQuantityScale, Amount: 30000})
+	assert.Nil(t, err)
+
+	err = holding.Apply(&InvestmentTransaction{Type: INVESTMENT_TRANSACTION_TYPE_SELL, Quantity: 3 * InvestmentQuantityScale, Amount: 24000})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), holding.Quantity)
+	assert.Equal(t, int64(0), holding.CostBasis)
+	assert.Equal(t, int64(-6000), holding.RealizedGain)
+	assert.Equal(t, 0, len(holding.Lots))
+}
+
+func TestInvestmentHoldingApply_SellQuantityExceedsHolding(t *testing.T) {
+	holding := NewInvestmentHolding(1)
+
+	err := holding.Apply(&InvestmentTransaction{Type: INVESTMENT_TRANSACTION_TYPE_BUY, Quantity: InvestmentQuantityScale, Amount: 10000})
+	assert.Nil(t, err)
+
+	err = holding.Apply(&InvestmentTransaction{Type: INVESTMENT_TRANSACTION_TYPE_SELL, Quantity: 2 * InvestmentQuantityScale, Amount: 20000})
+	assert.EqualError(t, err, errs.ErrInvestmentSellQuantityExceedsHolding.Message)
+	assert.Equal(t, int64(InvestmentQuantityScale), holding.Quantity)
+}
+
+func TestInvestmentHoldingApply_Dividend(t *testing.T) {
+	holding := NewInvestmentHolding(1)
+
+	err := holding.Apply(&InvestmentTransaction{Type: INVESTMENT_TRANSACTION_TYPE_DIVIDEND, Amount: 1500, Fee: 150})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1350), holding.Dividends)
+	assert.Equal(t, int64(0), holding.Quantity)
+}
+
+func TestInvestmentHoldingApply_InvalidType(t *testing.T) {
+	holding := NewInvestmentHolding(1)
+
+	err := holding.Apply(&InvestmentTransaction{Type: 0, Quantity: InvestmentQuantityScale})
+	assert.EqualError(t, err, errs.ErrInvestmentTransactionTypeInvalid.Message)
+}
+
+func TestGetInvestmentMarketValue(t *testing.T) {
+	assert.Equal(t, int64(12345), GetInvestmentMarketValue(InvestmentQuantityScale, 12345))
+	assert.Equal(t, int64(30000), GetInvestmentMarketValue(15000, 20000))
+	assert.Equal(t, int64(0), GetInvestmentMarketValue(0, 20000))
+}
+
+func TestInvestmentTransactionToImpliedSecurityPrice(t *testing.T) {
+	transaction := &InvestmentTransaction{Uid: 1, SecurityId: 2, Type: INVESTMENT_TRANSACTION_TYPE_BUY, TransactionTime: 100, Quantity: 4 * InvestmentQuantityScale, Amount: 10000}
+	price := transaction.ToImpliedSecurityPrice()
+	assert.NotNil(t, price)
+	assert.Equal(t, int64(2500), price.Price)
+	assert.Equal(t, int64(100), price.PriceTime)
+	assert.Equal(t, SecurityPriceTransactionDataSource, price.DataSource)
+
+	dividend := &InvestmentTransaction{Type: INVESTMENT_TRANSACTION_TYPE_DIVIDEND, Amount: 10000}
+	assert.Nil(t, dividend.ToImpliedSecurityPrice())
+}
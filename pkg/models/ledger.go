@@ -0,0 +1,254 @@
+package models
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+// LedgerMemberRole represents the role of member in shared ledger
+type LedgerMemberRole byte
+
+// Ledger member roles
+const (
+	LEDGER_MEMBER_ROLE_OWNER  LedgerMemberRole = 1
+	LEDGER_MEMBER_ROLE_EDITOR LedgerMemberRole = 2
+	LEDGER_MEMBER_ROLE_VIEWER LedgerMemberRole = 3
+)
+
+// ledgerMemberViewerScopes represents the api scopes which the viewer of shared ledger can access
+var ledgerMemberViewerScopes = core.APITokenScopes{
+	core.API_TOKEN_SCOPE_READ_ACCOUNTS,
+	core.API_TOKEN_SCOPE_READ_TRANSACTIONS,
+	core.API_TOKEN_SCOPE_READ_BASIC_DATA,
+	core.API_TOKEN_SCOPE_READ_BUDGETS,
+	core.API_TOKEN_SCOPE_READ_INVESTMENTS,
+	core.API_TOKEN_SCOPE_READ_STATISTICS,
+	core.API_TOKEN_SCOPE_READ_EXCHANGE_RATES,
+}
+
+// ledgerMemberEditorScopes represents the api scopes which the editor of shared ledger can access
+var ledgerMemberEditorScopes = append(core.APITokenScopes{
+	core.API_TOKEN_SCOPE_WRITE_ACCOUNTS,
+	core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS,
+	core.API_TOKEN_SCOPE_WRITE_BASIC_DATA,
+	core.API_TOKEN_SCOPE_WRITE_BUDGETS,
+	core.API_TOKEN_SCOPE_WRITE_INVESTMENTS,
+}, ledgerMemberViewerScopes...)
+
+// ledgerMemberOwnerScopes represents the api scopes which the owner of shared ledger can access
+var ledgerMemberOwnerScopes = append(core.APITokenScopes{
+	core.API_TOKEN_SCOPE_EXPORT_DATA,
+}, ledgerMemberEditorScopes...)
+
+// IsValid returns whether the ledger member role is valid
+func (r LedgerMemberRole) IsValid() bool {
+	return r == LEDGER_MEMBER_ROLE_OWNER || r == LEDGER_MEMBER_ROLE_EDITOR || r == LEDGER_MEMBER_ROLE_VIEWER
+}
+
+// CanBeGrantedToMember returns whether this role can be granted to an invited member, the owner role only belongs to the creator of the shared ledger
+func (r LedgerMemberRole) CanBeGrantedToMember() bool {
+	return r == LEDGER_MEMBER_ROLE_EDITOR || r == LEDGER_MEMBER_ROLE_VIEWER
+}
+
+// CanManageMembers returns whether the member with this role can modify the ledger and manage its members
+func (r LedgerMemberRole) CanManageMembers() bool {
+	return r == LEDGER_MEMBER_ROLE_OWNER
+}
+
+// GetPermittedScopes returns the api scopes which the member with this role can access when operating on the shared ledger
+func (r LedgerMemberRole) GetPermittedScopes() core.APITokenScopes {
+	switch r {
+	case LEDGER_MEMBER_ROLE_OWNER:
+		return ledgerMemberOwnerScopes
+	case LEDGER_MEMBER_ROLE_EDITOR:
+		return ledgerMemberEditorScopes
+	case LEDGER_MEMBER_ROLE_VIEWER:
+		return ledgerMemberViewerScopes
+	default:
+		return nil
+	}
+}
+
+// LedgerMemberStatus represents the status of member in shared ledger
+type LedgerMemberStatus byte
+
+// Ledger member statuses
+const (
+	LEDGER_MEMBER_STATUS_INVITED  LedgerMemberStatus = 1
+	LEDGER_MEMBER_STATUS_ACCEPTED LedgerMemberStatus = 2
+)
+
+// Ledger represents shared ledger data stored in database, the data of shared ledger is stored in a dedicated book which is independent of the books of all members
+type Ledger struct {
+	LedgerId        int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_ledger_uid_deleted) NOT NULL"`
+	BookUid         int64  `xorm:"NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_ledger_uid_deleted) NOT NULL"`
+	Name            string `xorm:"VARCHAR(64) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// LedgerMember represents the member of shared ledger stored in database
+type LedgerMember struct {
+	MemberId         int64              `xorm:"PK"`
+	LedgerId         int64              `xorm:"INDEX(IDX_ledger_member_ledger_id_deleted) NOT NULL"`
+	Uid              int64              `xorm:"INDEX(IDX_ledger_member_uid_deleted_status) NOT NULL"`
+	Deleted          bool               `xorm:"INDEX(IDX_ledger_member_ledger_id_deleted) INDEX(IDX_ledger_member_uid_deleted_status) NOT NULL"`
+	Role             LedgerMemberRole   `xorm:"TINYINT NOT NULL"`
+	Status           LedgerMemberStatus `xorm:"INDEX(IDX_ledger_member_uid_deleted_status) TINYINT NOT NULL"`
+	InviterUid       int64              `xorm:"NOT NULL"`
+	CreatedUnixTime  int64
+	UpdatedUnixTime  int64
+	AcceptedUnixTime int64
+	DeletedUnixTime  int64
+}
+
+// LedgerGetRequest represents all parameters of shared ledger getting request
+type LedgerGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// LedgerCreateRequest represents all parameters of shared ledger creation request
+type LedgerCreateRequest struct {
+	Name string `json:"name" binding:"required,notBlank,max=64"`
+}
+
+// LedgerModifyRequest represents all parameters of shared ledger modification request
+type LedgerModifyRequest struct {
+	Id   int64  `json:"id,string" binding:"required,min=1"`
+	Name string `json:"name" binding:"required,notBlank,max=64"`
+}
+
+// LedgerDeleteRequest represents all parameters of shared ledger deleting request
+type LedgerDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// LedgerMemberInviteRequest represents all parameters of shared ledger member inviting request
+type LedgerMemberInviteRequest struct {
+	LedgerId int64            `json:"ledgerId,string" binding:"required,min=1"`
+	Email    string           `json:"email" binding:"required,notBlank,max=100,validEmail"`
+	Role     LedgerMemberRole `json:"role" binding:"required"`
+}
+
+// LedgerMemberModifyRequest represents all parameters of shared ledger member role modification request
+type LedgerMemberModifyRequest struct {
+	Id   int64            `json:"id,string" binding:"required,min=1"`
+	Role LedgerMemberRole `json:"role" binding:"required"`
+}
+
+// LedgerMemberRemoveRequest represents all parameters of shared ledger member removing request
+type LedgerMemberRemoveRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// LedgerMemberStatisticsRequest represents all parameters of shared ledger member statistics request
+type LedgerMemberStatisticsRequest struct {
+	Id        int64 `form:"id,string" binding:"required,min=1"`
+	StartTime int64 `form:"startTime" binding:"min=0"`
+	EndTime   int64 `form:"endTime" binding:"min=0"`
+}
+
+// LedgerInvitationAcceptRequest represents all parameters of shared ledger invitation accepting request
+type LedgerInvitationAcceptRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// LedgerInvitationDeclineRequest represents all parameters of shared ledger invitation declining request
+type LedgerInvitationDeclineRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// LedgerInfoResponse represents a view-object of shared ledger
+type LedgerInfoResponse struct {
+	Id        int64                       `json:"id,string"`
+	Name      string                      `json:"name"`
+	OwnerUid  int64                       `json:"ownerUid,string"`
+	Role      LedgerMemberRole            `json:"role"`
+	Members   []*LedgerMemberInfoResponse `json:"members,omitempty"`
+	CreatedAt int64                       `json:"createdAt"`
+}
+
+// LedgerMemberInfoResponse represents a view-object of shared ledger member
+type LedgerMemberInfoResponse struct {
+	Id        int64              `json:"id,string"`
+	Uid       int64              `json:"uid,string"`
+	Username  string             `json:"username"`
+	Nickname  string             `json:"nickname"`
+	Role      LedgerMemberRole   `json:"role"`
+	Status    LedgerMemberStatus `json:"status"`
+	CreatedAt int64              `json:"createdAt"`
+}
+
+// LedgerInvitationInfoResponse represents a view-object of shared ledger invitation
+type LedgerInvitationInfoResponse struct {
+	Id              int64            `json:"id,string"`
+	LedgerId        int64            `json:"ledgerId,string"`
+	LedgerName      string           `json:"ledgerName"`
+	InviterNickname string           `json:"inviterNickname"`
+	Role            LedgerMemberRole `json:"role"`
+	CreatedAt       int64            `json:"createdAt"`
+}
+
+// LedgerMemberStatisticResponse represents a view-object of the income and expense statistics of shared ledger member
+type LedgerMemberStatisticResponse struct {
+	Uid      int64                                   `json:"uid,string"`
+	Nickname string                                  `json:"nickname"`
+	Accounts []*LedgerMemberAccountStatisticResponse `json:"accounts"`
+}
+
+// LedgerMemberAccountStatisticResponse represents a view-object of the income and expense statistics of shared ledger member in an account
+type LedgerMemberAccountStatisticResponse struct {
+	AccountId        int64 `json:"accountId,string"`
+	IncomeAmount     int64 `json:"incomeAmount"`
+	ExpenseAmount    int64 `json:"expenseAmount"`
+	TransactionCount int64 `json:"transactionCount"`
+}
+
+// ToLedgerInfoResponse returns a view-object according to database model
+func (l *Ledger) ToLedgerInfoResponse(role LedgerMemberRole) *LedgerInfoResponse {
+	return &LedgerInfoResponse{
+		Id:        l.LedgerId,
+		Name:      l.Name,
+		OwnerUid:  l.Uid,
+		Role:      role,
+		CreatedAt: l.CreatedUnixTime,
+	}
+}
+
+// ToLedgerMemberInfoResponse returns a view-object according to database model
+func (m *LedgerMember) ToLedgerMemberInfoResponse(user *User) *LedgerMemberInfoResponse {
+	resp := &LedgerMemberInfoResponse{
+		Id:        m.MemberId,
+		Uid:       m.Uid,
+		Role:      m.Role,
+		Status:    m.Status,
+		CreatedAt: m.CreatedUnixTime,
+	}
+
+	if user != nil {
+		resp.Username = user.Username
+		resp.Nickname = user.Nickname
+	}
+
+	return resp
+}
+
+// LedgerInfoResponseSlice represents the slice data structure of LedgerInfoResponse
+type LedgerInfoResponseSlice []*LedgerInfoResponse
+
+// Len returns the count of items
+func (s LedgerInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s LedgerInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s LedgerInfoResponseSlice) Less(i, j int) bool {
+	return s[i].CreatedAt < s[j].CreatedAt
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+func TestLedgerMemberRoleIsValid(t *testing.T) {
+	assert.True(t, LEDGER_MEMBER_ROLE_OWNER.IsValid())
+	assert.True(t, LEDGER_MEMBER_ROLE_EDITOR.IsValid())
+	assert.True(t, LEDGER_MEMBER_ROLE_VIEWER.IsValid())
+	assert.False(t, LedgerMemberRole(0).IsValid())
+	assert.False(t, LedgerMemberRole(4).IsValid())
+}
+
+func TestLedgerMemberRoleCanBeGrantedToMember(t *testing.T) {
+	assert.False(t, LEDGER_MEMBER_ROLE_OWNER.CanBeGrantedToMember())
+	assert.True(t, LEDGER_MEMBER_ROLE_EDITOR.CanBeGrantedToMember())
+	assert.True(t, LEDGER_MEMBER_ROLE_VIEWER.CanBeGrantedToMember())
+	assert.False(t, LedgerMemberRole(0).CanBeGrantedToMember())
+	assert.False(t, LedgerMemberRole(4).CanBeGrantedToMember())
+}
+
+func TestLedgerMemberRoleCanManageMembers(t *testing.T) {
+	assert.True(t, LEDGER_MEMBER_ROLE_OWNER.CanManageMembers())
+	assert.False(t, LEDGER_MEMBER_ROLE_EDITOR.CanManageMembers())
+	assert.False(t, LEDGER_MEMBER_ROLE_VIEWER.CanManageMembers())
+}
+
+func TestLedgerMemberRoleGetPermittedScopes(t *testing.T) {
+	viewerScopes := LEDGER_MEMBER_ROLE_VIEWER.GetPermittedScopes()
+	assert.True(t, viewerScopes.Contains(core.API_TOKEN_SCOPE_READ_TRANSACTIONS))
+	assert.True(t, viewerScopes.Contains(core.API_TOKEN_SCOPE_READ_STATISTICS))
+	assert.False(t, viewerScopes.Contains(core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS))
+	assert.False(t, viewerScopes.Contains(core.API_TOKEN_SCOPE_READ_USER_PROFILE))
+
+	editorScopes := LEDGER_MEMBER_ROLE_EDITOR.GetPermittedScopes()
+	assert.True(t, editorScopes.Contains(core.API_TOKEN_SCOPE_READ_TRANSACTIONS))
+	assert.True(t, editorScopes.Contains(core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS))
+	assert.False(t, editorScopes.Contains(core.API_TOKEN_SCOPE_EXPORT_DATA))
+
+	ownerScopes := LEDGER_MEMBER_ROLE_OWNER.GetPermittedScopes()
+	assert.True(t, ownerScopes.Contains(core.API_TOKEN_SCOPE_WRITE_TRANSACTIONS))
+	assert.True(t, ownerScopes.Contains(core.API_TOKEN_SCOPE_EXPORT_DATA))
+	assert.False(t, ownerScopes.Contains(core.API_TOKEN_SCOPE_IMPORT_DATA))
+
+	assert.Nil(t, LedgerMemberRole(0).GetPermittedScopes())
+}
@@ -0,0 +1,240 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// LoanInterestRateScale represents the scale of loan annual interest rate, e.g. 68750 means 6.875%
+const LoanInterestRateScale = 10000
+
+// AccountLoanInfo represents the loan definition of debt account stored in account extend data
+type AccountLoanInfo struct {
+	Principal           int64 `json:"principal"`
+	AnnualInterestRate  int64 `json:"annualInterestRate"`
+	TermMonths          int32 `json:"termMonths"`
+	PaymentDay          int32 `json:"paymentDay"`
+	StartTime           int64 `json:"startTime"`
+	TimezoneUtcOffset   int16 `json:"utcOffset"`
+	PaymentAccountId    int64 `json:"paymentAccountId"`
+	PrincipalCategoryId int64 `json:"principalCategoryId"`
+	InterestCategoryId  int64 `json:"interestCategoryId"`
+	PaymentTemplateId   int64 `json:"paymentTemplateId"`
+}
+
+// LoanAmortizationItem represents a payment period of loan amortization schedule
+type LoanAmortizationItem struct {
+	Period           int32
+	PaymentTime      int64
+	Payment          int64
+	Principal        int64
+	Interest         int64
+	RemainingBalance int64
+}
+
+// AccountLoanGetRequest represents all parameters of account loan getting request
+type AccountLoanGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// AccountLoanSetRequest represents all parameters of account loan setting request
+type AccountLoanSetRequest struct {
+	Id                  int64  `json:"id,string" binding:"required,min=1"`
+	Principal           int64  `json:"principal" binding:"required,min=1,validTransactionAmount"`
+	AnnualInterestRate  int64  `json:"annualInterestRate" binding:"min=0,max=10000000"`
+	TermMonths          int32  `json:"termMonths" binding:"required,min=1,max=1200"`
+	PaymentDay          int32  `json:"paymentDay" binding:"required,min=1,max=28"`
+	StartDate           string `json:"startDate" binding:"required"`
+	UtcOffset           int16  `json:"utcOffset" binding:"min=-720,max=840"`
+	PaymentAccountId    int64  `json:"paymentAccountId,string" binding:"required,min=1"`
+	PrincipalCategoryId int64  `json:"principalCategoryId,string" binding:"required,min=1"`
+	InterestCategoryId  int64  `json:"interestCategoryId,string" binding:"required,min=1"`
+}
+
+// AccountLoanDeleteRequest represents all parameters of account loan deleting request
+type AccountLoanDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// LoanAmortizationItemResponse represents a view-object of loan amortization schedule item
+type LoanAmortizationItemResponse struct {
+	Period           int32 `json:"period"`
+	PaymentTime      int64 `json:"paymentTime"`
+	Payment          int64 `json:"payment"`
+	Principal        int64 `json:"principal"`
+	Interest         int64 `json:"interest"`
+	RemainingBalance int64 `json:"remainingBalance"`
+}
+
+// AccountLoanInfoResponse represents a view-object of account loan
+type AccountLoanInfoResponse struct {
+	AccountId           int64                           `json:"accountId,string"`
+	Principal           int64                           `json:"principal"`
+	AnnualInterestRate  int64                           `json:"annualInterestRate"`
+	TermMonths          int32                           `json:"termMonths"`
+	PaymentDay          int32                           `json:"paymentDay"`
+	StartTime           int64                           `json:"startTime"`
+	UtcOffset           int16                           `json:"utcOffset"`
+	PaymentAccountId    int64                           `json:"paymentAccountId,string"`
+	PrincipalCategoryId int64                           `json:"principalCategoryId,string"`
+	InterestCategoryId  int64                           `json:"interestCategoryId,string"`
+	PaymentTemplateId   int64                           `json:"paymentTemplateId,string"`
+	MonthlyPayment      int64                           `json:"monthlyPayment"`
+	TotalPayment        int64                           `json:"totalPayment"`
+	TotalInterest       int64                           `json:"totalInterest"`
+	PaidPeriods         int32                           `json:"paidPeriods"`
+	RemainingBalance    int64                           `json:"remainingBalance"`
+	RemainingInterest   int64                           `json:"remainingInterest"`
+	AccountBalance      int64                           `json:"accountBalance"`
+	Schedule            []*LoanAmortizationItemResponse `json:"schedule"`
+}
+
+// GetAmortizationSchedule returns the amortization schedule of the loan with fixed monthly payment
+func (l *AccountLoanInfo) GetAmortizationSchedule() []*LoanAmortizationItem {
+	if l.Principal <= 0 || l.TermMonths <= 0 {
+		return nil
+	}
+
+	monthlyRate := float64(l.AnnualInterestRate) / LoanInterestRateScale / 100 / 12
+	regularPayment := l.GetMonthlyPayment()
+	balance := l.Principal
+	schedule := make([]*LoanAmortizationItem, l.TermMonths)
+
+	for i := int32(0); i < l.TermMonths; i++ {
+		interest := int64(math.Round(float64(balance) * monthlyRate))
+		principal := regularPayment - interest
+
+		if i == l.TermMonths-1 || principal > balance {
+			principal = balance
+		} else if principal < 0 {
+			principal = 0
+		}
+
+		balance -= principal
+
+		schedule[i] = &LoanAmortizationItem{
+			Period:           i + 1,
+			PaymentTime:      l.GetPaymentTime(i + 1),
+			Payment:          principal + interest,
+			Principal:        principal,
+			Interest:         interest,
+			RemainingBalance: balance,
+		}
+	}
+
+	return schedule
+}
+
+// GetMonthlyPayment returns the regular monthly payment of the loan
+func (l *AccountLoanInfo) GetMonthlyPayment() int64 {
+	if l.Principal <= 0 || l.TermMonths <= 0 {
+		return 0
+	}
+
+	if l.AnnualInterestRate <= 0 {
+		return int64(math.Ceil(float64(l.Principal) / float64(l.TermMonths)))
+	}
+
+	monthlyRate := float64(l.AnnualInterestRate) / LoanInterestRateScale / 100 / 12
+	payment := float64(l.Principal) * monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(l.TermMonths)))
+
+	return int64(math.Round(payment))
+}
+
+// GetPaymentTime returns the unix time of the first second of the payment date of the specified period (starts from 1)
+func (l *AccountLoanInfo) GetPaymentTime(period int32) int64 {
+	timezone := time.FixedZone("Loan Timezone", int(l.TimezoneUtcOffset)*60)
+	startTime := time.Unix(l.StartTime, 0).In(timezone)
+	paymentTime := time.Date(startTime.Year(), startTime.Month()+time.Month(period), int(l.PaymentDay), 0, 0, 0, 0, timezone)
+
+	return paymentTime.Unix()
+}
+
+// GetPeriodByPaymentMonth returns the period (starts from 1) whose payment date is in the specified month, or 0 if no payment in this month
+func (l *AccountLoanInfo) GetPeriodByPaymentMonth(year int, month time.Month) int32 {
+	timezone := time.FixedZone("Loan Timezone", int(l.TimezoneUtcOffset)*60)
+	startTime := time.Unix(l.StartTime, 0).In(timezone)
+	period := int32((year*12 + int(month)) - (startTime.Year()*12 + int(startTime.Month())))
+
+	if period < 1 || period > l.TermMonths {
+		return 0
+	}
+
+	return period
+}
+
+// ToPaymentTransactionTemplate returns a monthly scheduled transfer template for paying the loan
+func (l *AccountLoanInfo) ToPaymentTransactionTemplate(uid int64, loanAccountId int64, name string, displayOrder int32) *TransactionTemplate {
+	timezone := time.FixedZone("Loan Timezone", int(l.TimezoneUtcOffset)*60)
+	scheduledAtTime := time.Date(2020, 1, 1, 0, 0, 0, 0, timezone).In(time.UTC)
+	firstPaymentTime := l.GetPaymentTime(1)
+	lastPaymentTime := l.GetPaymentTime(l.TermMonths) + 24*60*60 - 1
+
+	return &TransactionTemplate{
+		Uid:                        uid,
+		TemplateType:               TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
+		Name:                       name,
+		Type:                       TRANSACTION_TYPE_TRANSFER,
+		CategoryId:                 l.PrincipalCategoryId,
+		AccountId:                  l.PaymentAccountId,
+		ScheduledFrequencyType:     TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:         utils.IntToString(int(l.PaymentDay)),
+		ScheduledStartTime:         &firstPaymentTime,
+		ScheduledEndTime:           &lastPaymentTime,
+		ScheduledAt:                int16(scheduledAtTime.Hour()*60 + scheduledAtTime.Minute()),
+		ScheduledTimezoneUtcOffset: l.TimezoneUtcOffset,
+		Amount:                     l.GetMonthlyPayment(),
+		RelatedAccountId:           loanAccountId,
+		RelatedAccountAmount:       l.GetMonthlyPayment(),
+		DisplayOrder:               displayOrder,
+	}
+}
+
+// ToAccountLoanInfoResponse returns a view-object according to the loan definition and the debt account
+func (l *AccountLoanInfo) ToAccountLoanInfoResponse(account *Account, currentUnixTime int64) *AccountLoanInfoResponse {
+	schedule := l.GetAmortizationSchedule()
+	resp := &AccountLoanInfoResponse{
+		AccountId:           account.AccountId,
+		Principal:           l.Principal,
+		AnnualInterestRate:  l.AnnualInterestRate,
+		TermMonths:          l.TermMonths,
+		PaymentDay:          l.PaymentDay,
+		StartTime:           l.StartTime,
+		UtcOffset:           l.TimezoneUtcOffset,
+		PaymentAccountId:    l.PaymentAccountId,
+		PrincipalCategoryId: l.PrincipalCategoryId,
+		InterestCategoryId:  l.InterestCategoryId,
+		PaymentTemplateId:   l.PaymentTemplateId,
+		MonthlyPayment:      l.GetMonthlyPayment(),
+		RemainingBalance:    l.Principal,
+		AccountBalance:      account.Balance,
+		Schedule:            make([]*LoanAmortizationItemResponse, len(schedule)),
+	}
+
+	for i := 0; i < len(schedule); i++ {
+		item := schedule[i]
+
+		resp.TotalPayment += item.Payment
+		resp.TotalInterest += item.Interest
+
+		if item.PaymentTime <= currentUnixTime {
+			resp.PaidPeriods = item.Period
+			resp.RemainingBalance = item.RemainingBalance
+		} else {
+			resp.RemainingInterest += item.Interest
+		}
+
+		resp.Schedule[i] = &LoanAmortizationItemResponse{
+			Period:           item.Period,
+			PaymentTime:      item.PaymentTime,
+			Payment:          item.Payment,
+			Principal:        item.Principal,
+			Interest:         item.Interest,
+			RemainingBalance: item.RemainingBalance,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountLoanInfoGetMonthlyPayment(t *testing.T) {
+	loan := &AccountLoanInfo{Principal: 100000, AnnualInterestRate: 60000, TermMonths: 12}
+	assert.Equal(t, int64(8607), loan.GetMonthlyPayment())
+
+	loan = &AccountLoanInfo{Principal: 100000, AnnualInterestRate: 0, TermMonths: 3}
+	assert.Equal(t, int64(33334), loan.GetMonthlyPayment())
+}
+
+func TestAccountLoanInfoGetAmortizationSchedule(t *testing.T) {
+	loan := &AccountLoanInfo{
+		Principal:          100000,
+		AnnualInterestRate: 60000,
+		TermMonths:         12,
+		PaymentDay:         5,
+		StartTime:          time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	schedule := loan.GetAmortizationSchedule()
+	assert.Equal(t, 12, len(schedule))
+
+	assert.Equal(t, int32(1), schedule[0].Period)
+	assert.Equal(t, time.Date(2024, time.February, 5, 0, 0, 0, 0, time.UTC).Unix(), schedule[0].PaymentTime)
+	assert.Equal(t, int64(500), schedule[0].Interest)
+	assert.Equal(t, int64(8107), schedule[0].Principal)
+	assert.Equal(t, int64(8607), schedule[0].Payment)
+	assert.Equal(t, int64(91893), schedule[0].RemainingBalance)
+
+	totalPrincipal := int64(0)
+
+	for i := 0; i < len(schedule); i++ {
+		totalPrincipal += schedule[i].Principal
+	}
+
+	assert.Equal(t, int64(100000), totalPrincipal)
+	assert.Equal(t, int64(0), schedule[11].RemainingBalance)
+	assert.Equal(t, time.Date(2025, time.January, 5, 0, 0, 0, 0, time.UTC).Unix(), schedule[11].PaymentTime)
+}
+
+func TestAccountLoanInfoGetAmortizationSchedule_ZeroInterestRate(t *testing.T) {
+	loan := &AccountLoanInfo{Principal: 100000, TermMonths: 3, PaymentDay: 1}
+
+	schedule := loan.GetAmortizationSchedule()
+	assert.Equal(t, 3, len(schedule))
+	assert.Equal(t, int64(33334), schedule[0].Principal)
+	assert.Equal(t, int64(33334), schedule[1].Principal)
+	assert.Equal(t, int64(33332), schedule[2].Principal)
+	assert.Equal(t, int64(0), schedule[0].Interest+schedule[1].Interest+schedule[2].Interest)
+}
+
+func TestAccountLoanInfoGetPeriodByPaymentMonth(t *testing.T) {
+	loan := &AccountLoanInfo{
+		TermMonths:        12,
+		PaymentDay:        5,
+		StartTime:         time.Date(2024, time.January, 15, 0, 0, 0, 0, time.FixedZone("Test Timezone", 8*60*60)).Unix(),
+		TimezoneUtcOffset: 8 * 60,
+	}
+
+	assert.Equal(t, int32(0), loan.GetPeriodByPaymentMonth(2024, time.January))
+	assert.Equal(t, int32(1), loan.GetPeriodByPaymentMonth(2024, time.February))
+	assert.Equal(t, int32(12), loan.GetPeriodByPaymentMonth(2025, time.January))
+	assert.Equal(t, int32(0), loan.GetPeriodByPaymentMonth(2025, time.February))
+}
+
+func TestAccountLoanInfoToAccountLoanInfoResponse(t *testing.T) {
+	loan := &AccountLoanInfo{
+		Principal:          100000,
+		AnnualInterestRate: 60000,
+		TermMonths:         12,
+		PaymentDay:         5,
+		StartTime:          time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	account := &Account{AccountId: 1, Balance: -91893}
+
+	resp := loan.ToAccountLoanInfoResponse(account, time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC).Unix())
+	assert.Equal(t, int32(1), resp.PaidPeriods)
+	assert.Equal(t, int64(91893), resp.RemainingBalance)
+	assert.Equal(t, resp.TotalPayment-int64(100000), resp.TotalInterest)
+	assert.Equal(t, resp.TotalInterest-int64(500), resp.RemainingInterest)
+	assert.Equal(t, int64(-91893), resp.AccountBalance)
+	assert.Equal(t, 12, len(resp.Schedule))
+}
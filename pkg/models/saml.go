@@ -0,0 +1,14 @@
+package models
+
+// SAMLLoginRequest represents all parameters of SAML 2.0 login request
+type SAMLLoginRequest struct {
+	Platform        string `form:"platform" binding:"required"`
+	ClientSessionId string `form:"client_session_id" binding:"required"`
+	Token           string `form:"token"`
+}
+
+// SAMLAssertionConsumerRequest represents all parameters of SAML 2.0 assertion consumer service request
+type SAMLAssertionConsumerRequest struct {
+	SAMLResponse string `form:"SAMLResponse"`
+	RelayState   string `form:"RelayState"`
+}
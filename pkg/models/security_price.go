@@ -0,0 +1,140 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// SecurityPriceManualDataSource represents the data source name of the security prices which are set by user manually
+const SecurityPriceManualDataSource = "manual"
+
+// SecurityPriceTransactionDataSource represents the data source name of the security prices which are implied by buy or sell transactions
+const SecurityPriceTransactionDataSource = "transaction"
+
+// SecurityPrice represents security price history data stored in database
+type SecurityPrice struct {
+	PriceId         int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_security_price_uid_deleted_security_id_time) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_security_price_uid_deleted_security_id_time) NOT NULL"`
+	SecurityId      int64  `xorm:"INDEX(IDX_security_price_uid_deleted_security_id_time) NOT NULL"`
+	PriceTime       int64  `xorm:"INDEX(IDX_security_price_uid_deleted_security_id_time) NOT NULL"`
+	Price           int64  `xorm:"NOT NULL"`
+	DataSource      string `xorm:"VARCHAR(32) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// SecurityPriceListRequest represents all parameters of security price listing request
+type SecurityPriceListRequest struct {
+	SecurityId int64 `form:"security_id,string" binding:"required,min=1"`
+}
+
+// SecurityPriceCreateRequest represents all parameters of security price creation request
+type SecurityPriceCreateRequest struct {
+	SecurityId int64 `json:"securityId,string" binding:"required,min=1"`
+	PriceTime  int64 `json:"time" binding:"required,min=1"`
+	Price      int64 `json:"price" binding:"min=0,validTransactionAmount"`
+}
+
+// SecurityPriceDeleteRequest represents all parameters of security price deleting request
+type SecurityPriceDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// SecurityPriceUpdateRequest represents all parameters of updating security prices from data source request
+type SecurityPriceUpdateRequest struct {
+	AccountId int64 `json:"accountId,string" binding:"min=0"`
+}
+
+// SecurityPriceInfoResponse represents a view-object of security price
+type SecurityPriceInfoResponse struct {
+	Id         int64  `json:"id,string"`
+	SecurityId int64  `json:"securityId,string"`
+	PriceTime  int64  `json:"time"`
+	Price      int64  `json:"price"`
+	DataSource string `json:"dataSource"`
+}
+
+// LatestSecurityPriceResponse returns a view-object which contains latest security prices from data source
+type LatestSecurityPriceResponse struct {
+	DataSource   string                   `json:"dataSource"`
+	ReferenceUrl string                   `json:"referenceUrl"`
+	Prices       LatestSecurityPriceSlice `json:"prices"`
+}
+
+// LatestSecurityPrice represents the latest price of a security symbol
+type LatestSecurityPrice struct {
+	Symbol     string `json:"symbol"`
+	Price      string `json:"price"`
+	UpdateTime int64  `json:"updateTime"`
+}
+
+// ToSecurityPriceInfoResponse returns a view-object according to database model
+func (p *SecurityPrice) ToSecurityPriceInfoResponse() *SecurityPriceInfoResponse {
+	return &SecurityPriceInfoResponse{
+		Id:         p.PriceId,
+		SecurityId: p.SecurityId,
+		PriceTime:  p.PriceTime,
+		Price:      p.Price,
+		DataSource: p.DataSource,
+	}
+}
+
+// ToSecurityPrice returns a security price database model according to the latest price of the security
+func (p *LatestSecurityPrice) ToSecurityPrice(uid int64, securityId int64, dataSource string) (*SecurityPrice, error) {
+	price, err := utils.ParseAmount(p.Price)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecurityPrice{
+		Uid:        uid,
+		SecurityId: securityId,
+		PriceTime:  p.UpdateTime,
+		Price:      price,
+		DataSource: dataSource,
+	}, nil
+}
+
+// SecurityPriceInfoResponseSlice represents the slice data structure of SecurityPriceInfoResponse
+type SecurityPriceInfoResponseSlice []*SecurityPriceInfoResponse
+
+// Len returns the count of items
+func (s SecurityPriceInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s SecurityPriceInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s SecurityPriceInfoResponseSlice) Less(i, j int) bool {
+	if s[i].PriceTime != s[j].PriceTime {
+		return s[i].PriceTime > s[j].PriceTime
+	}
+
+	return s[i].Id > s[j].Id
+}
+
+// LatestSecurityPriceSlice represents the slice data structure of LatestSecurityPrice
+type LatestSecurityPriceSlice []*LatestSecurityPrice
+
+// Len returns the count of items
+func (s LatestSecurityPriceSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s LatestSecurityPriceSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s LatestSecurityPriceSlice) Less(i, j int) bool {
+	return strings.Compare(s[i].Symbol, s[j].Symbol) < 0
+}
@@ -25,16 +25,28 @@ type OAuth2CallbackTokenContext struct {
 	ExternalEmail    string                    `json:"externalEmail"`
 }
 
+// APITokenContext represents the context data of api token
+type APITokenContext struct {
+	Scopes core.APITokenScopes `json:"scopes"`
+}
+
+// MCPTokenContext represents the context data of mcp token
+type MCPTokenContext struct {
+	Scopes core.MCPTokenScopes `json:"scopes"`
+}
+
 // TokenGenerateAPIRequest represents all parameters of api token generation request
 type TokenGenerateAPIRequest struct {
-	ExpiredInSeconds int64  `json:"expiresInSeconds" binding:"omitempty,min=0,max=4294967295"`
-	Password         string `json:"password" binding:"omitempty,min=6,max=128"`
+	ExpiredInSeconds int64                `json:"expiresInSeconds" binding:"omitempty,min=0,max=4294967295"`
+	Password         string               `json:"password" binding:"omitempty,min=6,max=128"`
+	Scopes           []core.APITokenScope `json:"scopes"`
 }
 
 // TokenGenerateMCPRequest represents all parameters of mcp token generation request
 type TokenGenerateMCPRequest struct {
-	ExpiredInSeconds int64  `json:"expiresInSeconds" binding:"omitempty,min=0,max=4294967295"`
-	Password         string `json:"password" binding:"omitempty,min=6,max=128"`
+	ExpiredInSeconds int64                `json:"expiresInSeconds" binding:"omitempty,min=0,max=4294967295"`
+	Password         string               `json:"password" binding:"omitempty,min=6,max=128"`
+	Scopes           []core.MCPTokenScope `json:"scopes"`
 }
 
 // TokenRevokeRequest represents all parameters of token revoking request
@@ -141,7 +141,9 @@ type Transaction struct {
 	Comment              string            `xorm:"VARCHAR(255) NOT NULL"`
 	GeoLongitude         float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
 	GeoLatitude          float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
+	ExternalReference    string            `xorm:"VARCHAR(255)"`
 	CreatedIp            string            `xorm:"VARCHAR(39)"`
+	CreatorUid           int64
 	ScheduledCreated     bool
 	CreatedUnixTime      int64
 	UpdatedUnixTime      int64
@@ -174,8 +176,10 @@ type TransactionCreateRequest struct {
 	HideAmount           bool                           `json:"hideAmount"`
 	TagIds               []string                       `json:"tagIds"`
 	PictureIds           []string                       `json:"pictureIds"`
+	Splits               []*TransactionSplitRequest     `json:"splits" binding:"omitempty,dive"`
 	Comment              string                         `json:"comment" binding:"max=255"`
 	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	ExternalReference    string                         `json:"externalReference,omitempty" binding:"max=255"`
 	ClientSessionId      string                         `json:"clientSessionId"`
 }
 
@@ -193,14 +197,16 @@ type TransactionModifyRequest struct {
 	HideAmount           bool                           `json:"hideAmount"`
 	TagIds               []string                       `json:"tagIds"`
 	PictureIds           []string                       `json:"pictureIds"`
+	Splits               []*TransactionSplitRequest     `json:"splits" binding:"omitempty,dive"`
 	Comment              string                         `json:"comment" binding:"max=255"`
 	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
 }
 
 // TransactionImportRequest represents all parameters of transaction import request
 type TransactionImportRequest struct {
-	Transactions    []*TransactionCreateRequest `json:"transactions"`
-	ClientSessionId string                      `json:"clientSessionId"`
+	Transactions           []*TransactionCreateRequest `json:"transactions"`
+	SkipProbableDuplicates bool                        `json:"skipProbableDuplicates"`
+	ClientSessionId        string                      `json:"clientSessionId"`
 }
 
 // TransactionImportProcessRequest represents all parameters of transaction import process request
@@ -293,21 +299,23 @@ type TransactionReconciliationStatementRequest struct {
 
 // TransactionStatisticRequest represents all parameters of transaction statistic request
 type TransactionStatisticRequest struct {
-	StartTime              int64          `form:"start_time" binding:"min=0"`
-	EndTime                int64          `form:"end_time" binding:"min=0"`
-	TagFilter              string         `form:"tag_filter" binding:"validTagFilter"`
-	Keyword                string         `form:"keyword"`
-	MatchMode              core.MatchMode `form:"match_mode" binding:"min=0,max=1"`
-	UseTransactionTimezone bool           `form:"use_transaction_timezone"`
+	StartTime                  int64          `form:"start_time" binding:"min=0"`
+	EndTime                    int64          `form:"end_time" binding:"min=0"`
+	TagFilter                  string         `form:"tag_filter" binding:"validTagFilter"`
+	Keyword                    string         `form:"keyword"`
+	MatchMode                  core.MatchMode `form:"match_mode" binding:"min=0,max=1"`
+	UseTransactionTimezone     bool           `form:"use_transaction_timezone"`
+	UseHistoricalExchangeRates bool           `form:"use_historical_exchange_rates"`
 }
 
 // TransactionStatisticTrendsRequest represents all parameters of transaction statistic trends request
 type TransactionStatisticTrendsRequest struct {
 	YearMonthRangeRequest
-	TagFilter              string         `form:"tag_filter" binding:"validTagFilter"`
-	Keyword                string         `form:"keyword"`
-	MatchMode              core.MatchMode `form:"match_mode" binding:"min=0,max=1"`
-	UseTransactionTimezone bool           `form:"use_transaction_timezone"`
+	TagFilter                  string         `form:"tag_filter" binding:"validTagFilter"`
+	Keyword                    string         `form:"keyword"`
+	MatchMode                  core.MatchMode `form:"match_mode" binding:"min=0,max=1"`
+	UseTransactionTimezone     bool           `form:"use_transaction_timezone"`
+	UseHistoricalExchangeRates bool           `form:"use_historical_exchange_rates"`
 }
 
 // TransactionStatisticAssetTrendsRequest represents all parameters of transaction statistic asset trends request
@@ -418,8 +426,10 @@ type TransactionInfoResponse struct {
 	TagIds               []string                                 `json:"tagIds"`
 	Tags                 []*TransactionTagInfoResponse            `json:"tags,omitempty"`
 	Pictures             TransactionPictureInfoBasicResponseSlice `json:"pictures,omitempty"`
+	Splits               []*TransactionSplitInfoResponse          `json:"splits,omitempty"`
 	Comment              string                                   `json:"comment"`
 	GeoLocation          *TransactionGeoLocationResponse          `json:"geoLocation,omitempty"`
+	CreatorUid           int64                                    `json:"creatorUid,string,omitempty"`
 	Editable             bool                                     `json:"editable"`
 }
 
@@ -466,11 +476,12 @@ type TransactionStatisticResponse struct {
 
 // TransactionStatisticResponseItem represents total amount item for a response
 type TransactionStatisticResponseItem struct {
-	CategoryId         int64                         `json:"categoryId,string"`
-	AccountId          int64                         `json:"accountId,string"`
-	RelatedAccountId   int64                         `json:"relatedAccountId,string,omitempty"`
-	RelatedAccountType TransactionRelatedAccountType `json:"relatedAccountType,omitempty"`
-	TotalAmount        string                        `json:"amount"`
+	CategoryId                   int64                         `json:"categoryId,string"`
+	AccountId                    int64                         `json:"accountId,string"`
+	RelatedAccountId             int64                         `json:"relatedAccountId,string,omitempty"`
+	RelatedAccountType           TransactionRelatedAccountType `json:"relatedAccountType,omitempty"`
+	TotalAmount                  string                        `json:"amount"`
+	TotalAmountInDefaultCurrency string                        `json:"amountInDefaultCurrency,omitempty"`
 }
 
 // TransactionStatisticTrendsResponseItem represents the data within each statistic interval
@@ -493,6 +504,7 @@ type TransactionStatisticAssetTrendsResponseDataItem struct {
 	AccountId             int64  `json:"accountId,string"`
 	AccountOpeningBalance string `json:"accountOpeningBalance"`
 	AccountClosingBalance string `json:"accountClosingBalance"`
+	InvestmentMarketValue string `json:"investmentMarketValue,omitempty"`
 }
 
 // TransactionAmountsResponseItem represents an item of transaction amounts
@@ -523,6 +535,18 @@ type TransactionTotalAmount struct {
 	AccountId        int64
 	RelatedAccountId int64
 	Amount           *big.Int
+
+	AmountInDefaultCurrency *big.Int
+	ExchangeRateMissing     bool
+}
+
+// TransactionCreatorTotalAmount represents total income and expense amount of transactions created by a member of shared ledger in an account
+type TransactionCreatorTotalAmount struct {
+	CreatorUid         int64
+	AccountId          int64
+	TotalIncomeAmount  int64
+	TotalExpenseAmount int64
+	TransactionCount   int64
 }
 
 // TransactionAmountsAndCurrency represents income and expense amounts with currency
@@ -646,6 +670,7 @@ func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *
 		TagIds:               utils.Int64ArrayToStringArray(tagIds),
 		Comment:              t.Comment,
 		GeoLocation:          geoLocation,
+		CreatorUid:           t.CreatorUid,
 		Editable:             editable,
 	}
 }
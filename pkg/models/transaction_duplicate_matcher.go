@@ -0,0 +1,169 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionDuplicateMatchTimeWindow represents the maximum time difference (in seconds) between imported transaction and existed transaction which can be considered as duplicate
+const TransactionDuplicateMatchTimeWindow = 3 * 24 * 60 * 60
+
+const transactionDuplicateMatchMinCommentSimilarity = 0.5
+
+// TransactionDuplicateMatcher finds the existed transactions which are probable duplicates of the imported transactions
+type TransactionDuplicateMatcher struct {
+	timeWindow            int64
+	accountTransactions   map[int64][]*Transaction
+	matchedTransactionIds map[int64]bool
+}
+
+// NewTransactionDuplicateMatcher returns a new transaction duplicate matcher according to the existed transactions
+func NewTransactionDuplicateMatcher(existedTransactions []*Transaction, timeWindow int64) *TransactionDuplicateMatcher {
+	matcher := &TransactionDuplicateMatcher{
+		timeWindow:            timeWindow,
+		accountTransactions:   make(map[int64][]*Transaction),
+		matchedTransactionIds: make(map[int64]bool),
+	}
+
+	for i := 0; i < len(existedTransactions); i++ {
+		transaction := existedTransactions[i]
+		matcher.accountTransactions[transaction.AccountId] = append(matcher.accountTransactions[transaction.AccountId], transaction)
+	}
+
+	return matcher
+}
+
+// FindProbableDuplicate returns the id of existed transaction which is the probable duplicate of specified transaction, returns 0 if not found.
+// Every existed transaction can only be matched once, so the same rows in the imported file would not be considered as the duplicate of one transaction.
+func (m *TransactionDuplicateMatcher) FindProbableDuplicate(transaction *Transaction) int64 {
+	if transaction == nil || transaction.AccountId <= 0 {
+		return 0
+	}
+
+	existedTransactions := m.accountTransactions[transaction.AccountId]
+
+	if len(existedTransactions) < 1 {
+		return 0
+	}
+
+	if transaction.ExternalReference != "" {
+		for i := 0; i < len(existedTransactions); i++ {
+			existedTransaction := existedTransactions[i]
+
+			if existedTransaction.ExternalReference == transaction.ExternalReference && !m.matchedTransactionIds[existedTransaction.TransactionId] {
+				m.matchedTransactionIds[existedTransaction.TransactionId] = true
+				return existedTransaction.TransactionId
+			}
+		}
+	}
+
+	transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+	var matchedTransaction *Transaction
+	matchedTimeDifference := int64(-1)
+
+	for i := 0; i < len(existedTransactions); i++ {
+		existedTransaction := existedTransactions[i]
+
+		if m.matchedTransactionIds[existedTransaction.TransactionId] {
+			continue
+		}
+
+		if transaction.ExternalReference != "" && existedTransaction.ExternalReference != "" {
+			continue // both transactions have external reference but they are different
+		}
+
+		if !isTransactionTypeDuplicateCompatible(transaction.Type, existedTransaction.Type) || existedTransaction.Amount != transaction.Amount {
+			continue
+		}
+
+		timeDifference := utils.GetUnixTimeFromTransactionTime(existedTransaction.TransactionTime) - transactionUnixTime
+
+		if timeDifference < 0 {
+			timeDifference = -timeDifference
+		}
+
+		if timeDifference > m.timeWindow {
+			continue
+		}
+
+		if !isTransactionCommentSimilar(transaction.Comment, existedTransaction.Comment) {
+			continue
+		}
+
+		if matchedTransaction == nil || timeDifference < matchedTimeDifference {
+			matchedTransaction = existedTransaction
+			matchedTimeDifference = timeDifference
+		}
+	}
+
+	if matchedTransaction == nil {
+		return 0
+	}
+
+	m.matchedTransactionIds[matchedTransaction.TransactionId] = true
+
+	return matchedTransaction.TransactionId
+}
+
+func isTransactionTypeDuplicateCompatible(importedType TransactionDbType, existedType TransactionDbType) bool {
+	if importedType == existedType {
+		return true
+	}
+
+	// the transfer between accounts is often recorded as income or expense in the bank statement
+	if importedType == TRANSACTION_DB_TYPE_INCOME && existedType == TRANSACTION_DB_TYPE_TRANSFER_IN {
+		return true
+	} else if importedType == TRANSACTION_DB_TYPE_EXPENSE && existedType == TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		return true
+	}
+
+	return false
+}
+
+func isTransactionCommentSimilar(comment1 string, comment2 string) bool {
+	comment1 = strings.ToLower(strings.TrimSpace(comment1))
+	comment2 = strings.ToLower(strings.TrimSpace(comment2))
+
+	if comment1 == "" || comment2 == "" {
+		return true
+	}
+
+	if strings.Contains(comment1, comment2) || strings.Contains(comment2, comment1) {
+		return true
+	}
+
+	words1 := getCommentWordSet(comment1)
+	words2 := getCommentWordSet(comment2)
+
+	if len(words1) < 1 || len(words2) < 1 {
+		return false
+	}
+
+	sameWordCount := 0
+
+	for word := range words1 {
+		if words2[word] {
+			sameWordCount++
+		}
+	}
+
+	allWordCount := len(words1) + len(words2) - sameWordCount
+
+	return float64(sameWordCount)/float64(allWordCount) >= transactionDuplicateMatchMinCommentSimilarity
+}
+
+func getCommentWordSet(comment string) map[string]bool {
+	words := strings.FieldsFunc(comment, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	wordSet := make(map[string]bool, len(words))
+
+	for i := 0; i < len(words); i++ {
+		wordSet[words[i]] = true
+	}
+
+	return wordSet
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestTransactionDuplicateMatcherFindProbableDuplicate_ExternalReference(t *testing.T) {
+	existedTransactions := []*Transaction{
+		{TransactionId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 1000, ExternalReference: "ABC123"},
+		{TransactionId: 2, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 1000, ExternalReference: "DEF456"},
+	}
+	matcher := NewTransactionDuplicateMatcher(existedTransactions, TransactionDuplicateMatchTimeWindow)
+
+	transaction := &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1706745600), Amount: 2000, ExternalReference: "ABC123"}
+	assert.Equal(t, int64(1), matcher.FindProbableDuplicate(transaction))
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 1000, ExternalReference: "DEF456"}
+	assert.Equal(t, int64(0), matcher.FindProbableDuplicate(transaction))
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 1000, ExternalReference: "XYZ789"}
+	assert.Equal(t, int64(0), matcher.FindProbableDuplicate(transaction))
+}
+
+func TestTransactionDuplicateMatcherFindProbableDuplicate_FuzzyMatch(t *testing.T) {
+	existedTransactions := []*Transaction{
+		{TransactionId: 1, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 1000, Comment: "Coffee Shop"},
+		{TransactionId: 2, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704153600), Amount: 1000, Comment: "Coffee Shop"},
+		{TransactionId: 3, Type: TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 5000, Comment: ""},
+		{TransactionId: 4, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 3000, Comment: "Supermarket Purchase"},
+	}
+	matcher := NewTransactionDuplicateMatcher(existedTransactions, TransactionDuplicateMatchTimeWindow)
+
+	transaction := &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704160800), Amount: 1000, Comment: "COFFEE SHOP 1234"}
+	assert.Equal(t, int64(2), matcher.FindProbableDuplicate(transaction))
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704160800), Amount: 1000, Comment: "Coffee Shop"}
+	assert.Equal(t, int64(1), matcher.FindProbableDuplicate(transaction))
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704160800), Amount: 1000, Comment: "Coffee Shop"}
+	assert.Equal(t, int64(0), matcher.FindProbableDuplicate(transaction))
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 5000, Comment: "Salary"}
+	assert.Equal(t, int64(3), matcher.FindProbableDuplicate(transaction))
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 3000, Comment: "Gas Station"}
+	assert.Equal(t, int64(0), matcher.FindProbableDuplicate(transaction))
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200 + TransactionDuplicateMatchTimeWindow + 1), Amount: 3000, Comment: "Supermarket Purchase"}
+	assert.Equal(t, int64(0), matcher.FindProbableDuplicate(transaction))
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 0, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1704067200), Amount: 3000, Comment: "Supermarket Purchase"}
+	assert.Equal(t, int64(0), matcher.FindProbableDuplicate(transaction))
+}
+
+func TestIsTransactionCommentSimilar(t *testing.T) {
+	assert.True(t, isTransactionCommentSimilar("", "Coffee"))
+	assert.True(t, isTransactionCommentSimilar("Card payment: Coffee Shop", "coffee shop"))
+	assert.True(t, isTransactionCommentSimilar("Coffee Shop Berlin", "Shop Coffee"))
+	assert.False(t, isTransactionCommentSimilar("Coffee Shop Berlin Central", "Book Shop"))
+	assert.False(t, isTransactionCommentSimilar("!!!", "???"))
+}
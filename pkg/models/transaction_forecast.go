@@ -0,0 +1,326 @@
+package models
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionForecastEventType represents the type of transaction forecast event
+type TransactionForecastEventType byte
+
+// Transaction forecast event types
+const (
+	TRANSACTION_FORECAST_EVENT_TYPE_SCHEDULED_TRANSACTION TransactionForecastEventType = 1
+	TRANSACTION_FORECAST_EVENT_TYPE_CREDIT_CARD_STATEMENT TransactionForecastEventType = 2
+)
+
+// TransactionForecastRequest represents all parameters of transaction forecast request
+type TransactionForecastRequest struct {
+	Months                   int  `form:"months" binding:"omitempty,min=1,max=12"`
+	IncludeHistoricalAverage bool `form:"include_historical_average"`
+	HistoricalMonths         int  `form:"historical_months" binding:"omitempty,min=1,max=12"`
+}
+
+// TransactionForecastResponse represents a view-object of transaction forecast
+type TransactionForecastResponse struct {
+	StartDate string                                      `json:"startDate"`
+	EndDate   string                                      `json:"endDate"`
+	Accounts  []*TransactionForecastAccountResponse       `json:"accounts"`
+	Events    TransactionForecastEventResponseSlice       `json:"events"`
+	Averages  []*TransactionForecastAverageAmountResponse `json:"averages,omitempty"`
+}
+
+// TransactionForecastAccountResponse represents a view-object of projected balances of one account
+type TransactionForecastAccountResponse struct {
+	AccountId          int64                                      `json:"accountId,string"`
+	Currency           string                                     `json:"currency"`
+	OpeningBalance     int64                                      `json:"openingBalance"`
+	ClosingBalance     int64                                      `json:"closingBalance"`
+	MinimumBalance     int64                                      `json:"minimumBalance"`
+	MinimumBalanceDate string                                     `json:"minimumBalanceDate"`
+	NegativeDates      []string                                   `json:"negativeDates"`
+	Days               []*TransactionForecastAccountDailyResponse `json:"days"`
+}
+
+// TransactionForecastAccountDailyResponse represents a view-object of projected balance of one account in one day
+type TransactionForecastAccountDailyResponse struct {
+	Date     string `json:"date"`
+	Inflow   int64  `json:"inflow"`
+	Outflow  int64  `json:"outflow"`
+	Balance  int64  `json:"balance"`
+	Negative bool   `json:"negative"`
+}
+
+// TransactionForecastEventResponse represents a view-object of upcoming bill or scheduled transaction
+type TransactionForecastEventResponse struct {
+	Date                 string                       `json:"date"`
+	Type                 TransactionForecastEventType `json:"type"`
+	TemplateId           int64                        `json:"templateId,string,omitempty"`
+	Name                 string                       `json:"name,omitempty"`
+	TransactionType      TransactionType              `json:"transactionType,omitempty"`
+	CategoryId           int64                        `json:"categoryId,string,omitempty"`
+	AccountId            int64                        `json:"accountId,string"`
+	RelatedAccountId     int64                        `json:"relatedAccountId,string,omitempty"`
+	Amount               int64                        `json:"amount"`
+	RelatedAccountAmount int64                        `json:"relatedAccountAmount,omitempty"`
+}
+
+// TransactionForecastAverageAmountResponse represents a view-object of average daily amount of one category in one account
+type TransactionForecastAverageAmountResponse struct {
+	AccountId   int64           `json:"accountId,string"`
+	CategoryId  int64           `json:"categoryId,string"`
+	Type        TransactionType `json:"type"`
+	TotalAmount int64           `json:"totalAmount"`
+	DailyAmount int64           `json:"dailyAmount"`
+}
+
+// TransactionForecastEventResponseSlice represents the slice data structure of TransactionForecastEventResponse
+type TransactionForecastEventResponseSlice []*TransactionForecastEventResponse
+
+// Len returns the count of items
+func (s TransactionForecastEventResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionForecastEventResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionForecastEventResponseSlice) Less(i, j int) bool {
+	if s[i].Date != s[j].Date {
+		return s[i].Date < s[j].Date
+	}
+
+	return s[i].Type < s[j].Type
+}
+
+type transactionForecastAccount struct {
+	account  *Account
+	inflows  []int64
+	outflows []int64
+}
+
+// TransactionForecastCalculator projects the balances of accounts day by day
+type TransactionForecastCalculator struct {
+	startDate  time.Time
+	days       int
+	dateIndex  map[int32]int
+	accounts   []*transactionForecastAccount
+	accountMap map[int64]*transactionForecastAccount
+	events     TransactionForecastEventResponseSlice
+	averages   []*TransactionForecastAverageAmountResponse
+}
+
+// NewTransactionForecastCalculator returns a new transaction forecast calculator for the specified accounts from start date to end date (both inclusive)
+func NewTransactionForecastCalculator(accounts []*Account, startDate time.Time, endDate time.Time) *TransactionForecastCalculator {
+	startDate = getScheduleDate(startDate)
+	endDate = getScheduleDate(endDate)
+	days := 0
+
+	if !endDate.Before(startDate) {
+		days = int(endDate.Sub(startDate).Hours()/24) + 1
+	}
+
+	calculator := &TransactionForecastCalculator{
+		startDate:  startDate,
+		days:       days,
+		dateIndex:  make(map[int32]int, days),
+		accounts:   make([]*transactionForecastAccount, 0, len(accounts)),
+		accountMap: make(map[int64]*transactionForecastAccount, len(accounts)),
+		events:     make(TransactionForecastEventResponseSlice, 0),
+	}
+
+	for i := 0; i < days; i++ {
+		calculator.dateIndex[getNumericScheduleDate(startDate.AddDate(0, 0, i))] = i
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		// parent accounts have no balance themselves, their sub-accounts are projected instead
+		if account.Hidden || account.Type == ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			continue
+		}
+
+		forecastAccount := &transactionForecastAccount{
+			account:  account,
+			inflows:  make([]int64, days),
+			outflows: make([]int64, days),
+		}
+
+		calculator.accounts = append(calculator.accounts, forecastAccount)
+		calculator.accountMap[account.AccountId] = forecastAccount
+	}
+
+	return calculator
+}
+
+// AddScheduledOccurrence applies the amounts of the specified scheduled transaction occurrence on the specified date (in numeric year, month and day) to the related accounts
+func (f *TransactionForecastCalculator) AddScheduledOccurrence(template *TransactionTemplate, occurrence *TransactionScheduleOccurrence, date int32) bool {
+	index, exists := f.dateIndex[date]
+
+	if !exists {
+		return false
+	}
+
+	sourceAccount := f.accountMap[template.AccountId]
+	destinationAccount := f.accountMap[template.RelatedAccountId]
+
+	if template.Type == TRANSACTION_TYPE_INCOME && sourceAccount != nil {
+		sourceAccount.inflows[index] += occurrence.Amount
+	} else if template.Type == TRANSACTION_TYPE_EXPENSE && sourceAccount != nil {
+		sourceAccount.outflows[index] += occurrence.Amount
+	} else if template.Type == TRANSACTION_TYPE_TRANSFER && (sourceAccount != nil || destinationAccount != nil) {
+		if sourceAccount != nil {
+			sourceAccount.outflows[index] += occurrence.Amount
+		}
+
+		if destinationAccount != nil {
+			destinationAccount.inflows[index] += occurrence.RelatedAccountAmount
+		}
+	} else {
+		return false
+	}
+
+	event := &TransactionForecastEventResponse{
+		Date:            utils.FormatNumericYearMonthDayToLongDate(date),
+		Type:            TRANSACTION_FORECAST_EVENT_TYPE_SCHEDULED_TRANSACTION,
+		TemplateId:      template.TemplateId,
+		Name:            template.Name,
+		TransactionType: template.Type,
+		CategoryId:      template.CategoryId,
+		AccountId:       template.AccountId,
+		Amount:          occurrence.Amount,
+	}
+
+	if template.Type == TRANSACTION_TYPE_TRANSFER {
+		event.RelatedAccountId = template.RelatedAccountId
+		event.RelatedAccountAmount = occurrence.RelatedAccountAmount
+	}
+
+	f.events = append(f.events, event)
+
+	return true
+}
+
+// AddHistoricalAverage distributes the total amount of one category in the historical days evenly to every forecast day after the first one
+func (f *TransactionForecastCalculator) AddHistoricalAverage(accountId int64, categoryId int64, transactionType TransactionType, totalAmount *big.Int, historicalDays int) bool {
+	forecastAccount, exists := f.accountMap[accountId]
+
+	if !exists || historicalDays < 1 || totalAmount == nil || totalAmount.Sign() == 0 {
+		return false
+	}
+
+	if transactionType != TRANSACTION_TYPE_INCOME && transactionType != TRANSACTION_TYPE_EXPENSE {
+		return false
+	}
+
+	divisor := big.NewInt(int64(historicalDays))
+	lastCumulativeAmount := int64(0)
+
+	// the first day is today, and the transactions of today have already been included in the current balance
+	for i := 1; i < f.days; i++ {
+		cumulativeAmount := new(big.Int).Mul(totalAmount, big.NewInt(int64(i)))
+		cumulativeAmount.Quo(cumulativeAmount, divisor)
+		dailyAmount := cumulativeAmount.Int64() - lastCumulativeAmount
+		lastCumulativeAmount = cumulativeAmount.Int64()
+
+		if transactionType == TRANSACTION_TYPE_INCOME {
+			forecastAccount.inflows[i] += dailyAmount
+		} else {
+			forecastAccount.outflows[i] += dailyAmount
+		}
+	}
+
+	f.averages = append(f.averages, &TransactionForecastAverageAmountResponse{
+		AccountId:   accountId,
+		CategoryId:  categoryId,
+		Type:        transactionType,
+		TotalAmount: totalAmount.Int64(),
+		DailyAmount: new(big.Int).Quo(totalAmount, divisor).Int64(),
+	})
+
+	return true
+}
+
+// ToTransactionForecastResponse returns the projected balances of all accounts and all upcoming events
+func (f *TransactionForecastCalculator) ToTransactionForecastResponse() *TransactionForecastResponse {
+	response := &TransactionForecastResponse{
+		StartDate: utils.FormatNumericYearMonthDayToLongDate(getNumericScheduleDate(f.startDate)),
+		EndDate:   utils.FormatNumericYearMonthDayToLongDate(getNumericScheduleDate(f.startDate.AddDate(0, 0, f.days-1))),
+		Accounts:  make([]*TransactionForecastAccountResponse, len(f.accounts)),
+		Events:    make(TransactionForecastEventResponseSlice, 0, len(f.events)),
+		Averages:  f.averages,
+	}
+
+	response.Events = append(response.Events, f.events...)
+
+	for i := 0; i < len(f.accounts); i++ {
+		forecastAccount := f.accounts[i]
+		account := forecastAccount.account
+		balance := account.Balance
+		statementDate := 0
+
+		if account.Category == ACCOUNT_CATEGORY_CREDIT_CARD && account.Extend != nil && account.Extend.CreditCardStatementDate != nil {
+			statementDate = *account.Extend.CreditCardStatementDate
+		}
+
+		accountResp := &TransactionForecastAccountResponse{
+			AccountId:          account.AccountId,
+			Currency:           account.Currency,
+			OpeningBalance:     balance,
+			MinimumBalance:     balance,
+			MinimumBalanceDate: response.StartDate,
+			NegativeDates:      make([]string, 0),
+			Days:               make([]*TransactionForecastAccountDailyResponse, f.days),
+		}
+
+		for j := 0; j < f.days; j++ {
+			date := f.startDate.AddDate(0, 0, j)
+			dateText := utils.FormatNumericYearMonthDayToLongDate(getNumericScheduleDate(date))
+			balance = balance + forecastAccount.inflows[j] - forecastAccount.outflows[j]
+
+			// the balance of liability account is negative when there is outstanding debt
+			negative := balance < 0 && !account.Category.IsLiability()
+
+			accountResp.Days[j] = &TransactionForecastAccountDailyResponse{
+				Date:     dateText,
+				Inflow:   forecastAccount.inflows[j],
+				Outflow:  forecastAccount.outflows[j],
+				Balance:  balance,
+				Negative: negative,
+			}
+
+			if balance < accountResp.MinimumBalance {
+				accountResp.MinimumBalance = balance
+				accountResp.MinimumBalanceDate = dateText
+			}
+
+			if negative {
+				accountResp.NegativeDates = append(accountResp.NegativeDates, dateText)
+			}
+
+			if statementDate > 0 && date.Day() == statementDate && balance < 0 {
+				response.Events = append(response.Events, &TransactionForecastEventResponse{
+					Date:      dateText,
+					Type:      TRANSACTION_FORECAST_EVENT_TYPE_CREDIT_CARD_STATEMENT,
+					Name:      account.Name,
+					AccountId: account.AccountId,
+					Amount:    -balance,
+				})
+			}
+		}
+
+		accountResp.ClosingBalance = balance
+		response.Accounts[i] = accountResp
+	}
+
+	sort.Stable(response.Events)
+
+	return response
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionForecastCalculator_ScheduledTransactions(t *testing.T) {
+	accounts := []*Account{
+		{AccountId: 1, Category: ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 1000},
+		{AccountId: 2, Category: ACCOUNT_CATEGORY_SAVINGS_ACCOUNT, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 5000},
+		{AccountId: 3, Category: ACCOUNT_CATEGORY_CASH, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 100, Hidden: true},
+	}
+
+	calculator := NewTransactionForecastCalculator(accounts, getTestScheduleDate(2024, time.January, 1), getTestScheduleDate(2024, time.January, 5))
+
+	salary := &TransactionTemplate{TemplateId: 10, Type: TRANSACTION_TYPE_INCOME, AccountId: 1}
+	rent := &TransactionTemplate{TemplateId: 11, Type: TRANSACTION_TYPE_EXPENSE, AccountId: 1}
+	saving := &TransactionTemplate{TemplateId: 12, Type: TRANSACTION_TYPE_TRANSFER, AccountId: 2, RelatedAccountId: 1}
+	hidden := &TransactionTemplate{TemplateId: 13, Type: TRANSACTION_TYPE_EXPENSE, AccountId: 3}
+
+	assert.True(t, calculator.AddScheduledOccurrence(rent, &TransactionScheduleOccurrence{Amount: 1500}, 20240102))
+	assert.True(t, calculator.AddScheduledOccurrence(saving, &TransactionScheduleOccurrence{Amount: 800, RelatedAccountAmount: 800}, 20240103))
+	assert.True(t, calculator.AddScheduledOccurrence(salary, &TransactionScheduleOccurrence{Amount: 3000}, 20240105))
+	assert.False(t, calculator.AddScheduledOccurrence(hidden, &TransactionScheduleOccurrence{Amount: 50}, 20240105))
+	assert.False(t, calculator.AddScheduledOccurrence(salary, &TransactionScheduleOccurrence{Amount: 3000}, 20240106))
+
+	response := calculator.ToTransactionForecastResponse()
+	assert.Equal(t, "2024-01-01", response.StartDate)
+	assert.Equal(t, "2024-01-05", response.EndDate)
+	assert.Equal(t, 2, len(response.Accounts))
+	assert.Equal(t, 3, len(response.Events))
+
+	checkingAccount := response.Accounts[0]
+	assert.Equal(t, int64(1), checkingAccount.AccountId)
+	assert.Equal(t, int64(1000), checkingAccount.OpeningBalance)
+	assert.Equal(t, int64(3300), checkingAccount.ClosingBalance)
+	assert.Equal(t, int64(-500), checkingAccount.MinimumBalance)
+	assert.Equal(t, "2024-01-02", checkingAccount.MinimumBalanceDate)
+	assert.Equal(t, []string{"2024-01-02"}, checkingAccount.NegativeDates)
+	assert.Equal(t, int64(1500), checkingAccount.Days[1].Outflow)
+	assert.True(t, checkingAccount.Days[1].Negative)
+	assert.Equal(t, int64(300), checkingAccount.Days[2].Balance)
+	assert.False(t, checkingAccount.Days[2].Negative)
+
+	savingsAccount := response.Accounts[1]
+	assert.Equal(t, int64(4200), savingsAccount.ClosingBalance)
+	assert.Equal(t, 0, len(savingsAccount.NegativeDates))
+}
+
+func TestTransactionForecastCalculator_CreditCardStatement(t *testing.T) {
+	statementDate := 15
+	accounts := []*Account{
+		{AccountId: 1, Category: ACCOUNT_CATEGORY_CREDIT_CARD, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: -2000, Extend: &AccountExtend{CreditCardStatementDate: &statementDate}},
+		{AccountId: 2, Category: ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS, Currency: "USD"},
+	}
+
+	calculator := NewTransactionForecastCalculator(accounts, getTestScheduleDate(2024, time.January, 10), getTestScheduleDate(2024, time.March, 9))
+
+	subscription := &TransactionTemplate{TemplateId: 10, Type: TRANSACTION_TYPE_EXPENSE, AccountId: 1}
+	assert.True(t, calculator.AddScheduledOccurrence(subscription, &TransactionScheduleOccurrence{Amount: 500}, 20240112))
+
+	response := calculator.ToTransactionForecastResponse()
+	assert.Equal(t, 1, len(response.Accounts))
+
+	creditCardAccount := response.Accounts[0]
+	assert.Equal(t, int64(-2500), creditCardAccount.ClosingBalance)
+	assert.Equal(t, 0, len(creditCardAccount.NegativeDates))
+
+	assert.Equal(t, 3, len(response.Events))
+	assert.Equal(t, TRANSACTION_FORECAST_EVENT_TYPE_SCHEDULED_TRANSACTION, response.Events[0].Type)
+	assert.Equal(t, "2024-01-15", response.Events[1].Date)
+	assert.Equal(t, TRANSACTION_FORECAST_EVENT_TYPE_CREDIT_CARD_STATEMENT, response.Events[1].Type)
+	assert.Equal(t, int64(2500), response.Events[1].Amount)
+	assert.Equal(t, "2024-02-15", response.Events[2].Date)
+}
+
+func TestTransactionForecastCalculator_HistoricalAverage(t *testing.T) {
+	accounts := []*Account{
+		{AccountId: 1, Category: ACCOUNT_CATEGORY_CASH, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD", Balance: 100},
+	}
+
+	calculator := NewTransactionForecastCalculator(accounts, getTestScheduleDate(2024, time.January, 1), getTestScheduleDate(2024, time.January, 4))
+
+	assert.True(t, calculator.AddHistoricalAverage(1, 100, TRANSACTION_TYPE_EXPENSE, big.NewInt(100), 3))
+	assert.False(t, calculator.AddHistoricalAverage(2, 100, TRANSACTION_TYPE_EXPENSE, big.NewInt(100), 3))
+	assert.False(t, calculator.AddHistoricalAverage(1, 100, TRANSACTION_TYPE_TRANSFER, big.NewInt(100), 3))
+
+	response := calculator.ToTransactionForecastResponse()
+	cashAccount := response.Accounts[0]
+
+	// 100 / 3 per day, the remainder is carried to the following days
+	assert.Equal(t, int64(0), cashAccount.Days[0].Outflow)
+	assert.Equal(t, int64(33), cashAccount.Days[1].Outflow)
+	assert.Equal(t, int64(33), cashAccount.Days[2].Outflow)
+	assert.Equal(t, int64(34), cashAccount.Days[3].Outflow)
+	assert.Equal(t, int64(0), cashAccount.ClosingBalance)
+	assert.Equal(t, 1, len(response.Averages))
+	assert.Equal(t, int64(33), response.Averages[0].DailyAmount)
+}
@@ -0,0 +1,450 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionRule represents transaction rule data stored in database
+type TransactionRule struct {
+	RuleId               int64           `xorm:"PK"`
+	Uid                  int64           `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Deleted              bool            `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Name                 string          `xorm:"VARCHAR(64) NOT NULL"`
+	TransactionType      TransactionType `xorm:"NOT NULL"`
+	DescriptionPattern   string          `xorm:"VARCHAR(255) NOT NULL"`
+	CounterpartyPattern  string          `xorm:"VARCHAR(255) NOT NULL"`
+	MinAmount            *int64
+	MaxAmount            *int64
+	AccountId            int64  `xorm:"NOT NULL"`
+	CategoryId           int64  `xorm:"NOT NULL"`
+	TagIds               string `xorm:"VARCHAR(255) NOT NULL"`
+	DestinationAccountId int64  `xorm:"NOT NULL"`
+	NewDescription       string `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder         int32  `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Hidden               bool   `xorm:"NOT NULL"`
+	CreatedUnixTime      int64
+	UpdatedUnixTime      int64
+	DeletedUnixTime      int64
+}
+
+// TransactionRuleGetRequest represents all parameters of transaction rule getting request
+type TransactionRuleGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleCreateRequest represents all parameters of transaction rule creation request
+type TransactionRuleCreateRequest struct {
+	Name                 string          `json:"name" binding:"required,notBlank,max=64"`
+	TransactionType      TransactionType `json:"transactionType" binding:"min=0,max=4"`
+	DescriptionPattern   string          `json:"descriptionPattern" binding:"max=255"`
+	CounterpartyPattern  string          `json:"counterpartyPattern" binding:"max=255"`
+	MinAmount            *int64          `json:"minAmount" binding:"omitempty,validTransactionAmount"`
+	MaxAmount            *int64          `json:"maxAmount" binding:"omitempty,validTransactionAmount"`
+	AccountId            int64           `json:"accountId,string" binding:"min=0"`
+	CategoryId           int64           `json:"categoryId,string" binding:"min=0"`
+	TagIds               []string        `json:"tagIds"`
+	DestinationAccountId int64           `json:"destinationAccountId,string" binding:"min=0"`
+	NewDescription       string          `json:"newDescription" binding:"max=255"`
+	ClientSessionId      string          `json:"clientSessionId"`
+}
+
+// TransactionRuleModifyRequest represents all parameters of transaction rule modification request
+type TransactionRuleModifyRequest struct {
+	Id                   int64           `json:"id,string" binding:"required,min=1"`
+	Name                 string          `json:"name" binding:"required,notBlank,max=64"`
+	TransactionType      TransactionType `json:"transactionType" binding:"min=0,max=4"`
+	DescriptionPattern   string          `json:"descriptionPattern" binding:"max=255"`
+	CounterpartyPattern  string          `json:"counterpartyPattern" binding:"max=255"`
+	MinAmount            *int64          `json:"minAmount" binding:"omitempty,validTransactionAmount"`
+	MaxAmount            *int64          `json:"maxAmount" binding:"omitempty,validTransactionAmount"`
+	AccountId            int64           `json:"accountId,string" binding:"min=0"`
+	CategoryId           int64           `json:"categoryId,string" binding:"min=0"`
+	TagIds               []string        `json:"tagIds"`
+	DestinationAccountId int64           `json:"destinationAccountId,string" binding:"min=0"`
+	NewDescription       string          `json:"newDescription" binding:"max=255"`
+	Hidden               bool            `json:"hidden"`
+}
+
+// TransactionRuleHideRequest represents all parameters of transaction rule hiding request
+type TransactionRuleHideRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Hidden bool  `json:"hidden"`
+}
+
+// TransactionRuleMoveRequest represents all parameters of transaction rule moving request
+type TransactionRuleMoveRequest struct {
+	NewDisplayOrders []*TransactionRuleNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// TransactionRuleNewDisplayOrderRequest represents a data pair of id and display order
+type TransactionRuleNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// TransactionRuleDeleteRequest represents all parameters of transaction rule deleting request
+type TransactionRuleDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleApplyRequest represents all parameters of applying transaction rules to existed transactions request
+type TransactionRuleApplyRequest struct {
+	Id int64 `json:"id,string" binding:"min=0"`
+}
+
+// TransactionRuleInfoResponse represents a view-object of transaction rule
+type TransactionRuleInfoResponse struct {
+	Id                   int64           `json:"id,string"`
+	Name                 string          `json:"name"`
+	TransactionType      TransactionType `json:"transactionType"`
+	DescriptionPattern   string          `json:"descriptionPattern"`
+	CounterpartyPattern  string          `json:"counterpartyPattern"`
+	MinAmount            *int64          `json:"minAmount,omitempty"`
+	MaxAmount            *int64          `json:"maxAmount,omitempty"`
+	AccountId            int64           `json:"accountId,string"`
+	CategoryId           int64           `json:"categoryId,string"`
+	TagIds               []string        `json:"tagIds"`
+	DestinationAccountId int64           `json:"destinationAccountId,string"`
+	NewDescription       string          `json:"newDescription"`
+	DisplayOrder         int32           `json:"displayOrder"`
+	Hidden               bool            `json:"hidden"`
+}
+
+// TransactionRuleApplyResponse represents the result of applying transaction rules to existed transactions
+type TransactionRuleApplyResponse struct {
+	UpdatedCount int64 `json:"updatedCount"`
+	FailedCount  int64 `json:"failedCount"`
+}
+
+// HasCondition returns whether the transaction rule has at least one condition
+func (r *TransactionRule) HasCondition() bool {
+	return r.TransactionType != 0 ||
+		r.DescriptionPattern != "" ||
+		r.CounterpartyPattern != "" ||
+		r.MinAmount != nil ||
+		r.MaxAmount != nil ||
+		r.AccountId != 0
+}
+
+// HasAction returns whether the transaction rule has at least one action
+func (r *TransactionRule) HasAction() bool {
+	return r.CategoryId != 0 ||
+		r.TagIds != "" ||
+		r.DestinationAccountId != 0 ||
+		r.NewDescription != ""
+}
+
+// GetTagIds returns all tag ids which would be added by the transaction rule
+func (r *TransactionRule) GetTagIds() []int64 {
+	tagIds := make([]string, 0)
+
+	if r.TagIds != "" {
+		tagIds = strings.Split(r.TagIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(tagIds)
+
+	return result
+}
+
+// ToTransactionRuleInfoResponse returns a view-object according to database model
+func (r *TransactionRule) ToTransactionRuleInfoResponse() *TransactionRuleInfoResponse {
+	tagIds := make([]string, 0)
+
+	if r.TagIds != "" {
+		tagIds = strings.Split(r.TagIds, ",")
+	}
+
+	return &TransactionRuleInfoResponse{
+		Id:                   r.RuleId,
+		Name:                 r.Name,
+		TransactionType:      r.TransactionType,
+		DescriptionPattern:   r.DescriptionPattern,
+		CounterpartyPattern:  r.CounterpartyPattern,
+		MinAmount:            r.MinAmount,
+		MaxAmount:            r.MaxAmount,
+		AccountId:            r.AccountId,
+		CategoryId:           r.CategoryId,
+		TagIds:               tagIds,
+		DestinationAccountId: r.DestinationAccountId,
+		NewDescription:       r.NewDescription,
+		DisplayOrder:         r.DisplayOrder,
+		Hidden:               r.Hidden,
+	}
+}
+
+// TransactionRuleEngine represents the engine which applies the enabled transaction rules of a user to transactions
+type TransactionRuleEngine struct {
+	rules       []*compiledTransactionRule
+	categoryMap map[int64]*TransactionCategory
+	accountMap  map[int64]*Account
+	tagMap      map[int64]*TransactionTag
+}
+
+type compiledTransactionRule struct {
+	*TransactionRule
+	descriptionRegex  *regexp.Regexp
+	counterpartyRegex *regexp.Regexp
+	tagIds            []int64
+}
+
+// NewTransactionRuleEngine returns a new transaction rule engine, the hidden rules and the rules with invalid pattern are ignored
+func NewTransactionRuleEngine(rules []*TransactionRule, categoryMap map[int64]*TransactionCategory, accountMap map[int64]*Account, tagMap map[int64]*TransactionTag) *TransactionRuleEngine {
+	engine := &TransactionRuleEngine{
+		rules:       make([]*compiledTransactionRule, 0, len(rules)),
+		categoryMap: categoryMap,
+		accountMap:  accountMap,
+		tagMap:      tagMap,
+	}
+
+	for i := 0; i < len(rules); i++ {
+		rule := rules[i]
+
+		if rule.Hidden {
+			continue
+		}
+
+		compiledRule := &compiledTransactionRule{
+			TransactionRule: rule,
+			tagIds:          rule.GetTagIds(),
+		}
+
+		if rule.DescriptionPattern != "" {
+			regex, err := regexp.Compile(rule.DescriptionPattern)
+
+			if err != nil {
+				continue
+			}
+
+			compiledRule.descriptionRegex = regex
+		}
+
+		if rule.CounterpartyPattern != "" {
+			regex, err := regexp.Compile(rule.CounterpartyPattern)
+
+			if err != nil {
+				continue
+			}
+
+			compiledRule.counterpartyRegex = regex
+		}
+
+		engine.rules = append(engine.rules, compiledRule)
+	}
+
+	return engine
+}
+
+// RuleCount returns the count of enabled rules in the engine
+func (e *TransactionRuleEngine) RuleCount() int {
+	return len(e.rules)
+}
+
+// ApplyToImportTransaction applies all matched rules to the imported transaction in display order and returns whether any rule is matched,
+// the counterparty is used for matching the counterparty pattern, or the description is used if the counterparty is empty
+func (e *TransactionRuleEngine) ApplyToImportTransaction(transaction *ImportTransaction, counterparty string) bool {
+	matchedRules := e.getMatchedRules(transaction.Transaction, counterparty)
+
+	if len(matchedRules) < 1 {
+		return false
+	}
+
+	existedTagIds := make(map[string]bool, len(transaction.TagIds))
+
+	for i := 0; i < len(transaction.TagIds); i++ {
+		existedTagIds[transaction.TagIds[i]] = true
+	}
+
+	for i := 0; i < len(matchedRules); i++ {
+		rule := matchedRules[i]
+
+		if category := e.getApplicableCategory(transaction.Transaction, rule, len(transaction.Splits) > 0); category != nil {
+			transaction.CategoryId = category.CategoryId
+			transaction.OriginalCategoryName = category.Name
+		}
+
+		for j := 0; j < len(rule.tagIds); j++ {
+			tag, exists := e.tagMap[rule.tagIds[j]]
+			tagId := utils.Int64ToString(rule.tagIds[j])
+
+			if !exists || tag.Hidden || existedTagIds[tagId] {
+				continue
+			}
+
+			transaction.TagIds = append(transaction.TagIds, tagId)
+			transaction.OriginalTagNames = append(transaction.OriginalTagNames, tag.Name)
+			existedTagIds[tagId] = true
+		}
+
+		if account := e.getApplicableDestinationAccount(transaction.Transaction, rule); account != nil {
+			transaction.RelatedAccountId = account.AccountId
+			transaction.OriginalDestinationAccountName = account.Name
+			transaction.OriginalDestinationAccountCurrency = account.Currency
+		}
+
+		if rule.NewDescription != "" {
+			transaction.Comment = rule.NewDescription
+		}
+	}
+
+	return true
+}
+
+// ApplyToTransaction applies all matched rules to the existed transaction in display order,
+// and returns whether the transaction is changed and the tag ids which need to be added to the transaction
+func (e *TransactionRuleEngine) ApplyToTransaction(transaction *Transaction, currentTagIds []int64, hasSplits bool) (bool, []int64) {
+	matchedRules := e.getMatchedRules(transaction, "")
+
+	if len(matchedRules) < 1 {
+		return false, nil
+	}
+
+	changed := false
+	existedTagIds := make(map[int64]bool, len(currentTagIds))
+	var addTagIds []int64
+
+	for i := 0; i < len(currentTagIds); i++ {
+		existedTagIds[currentTagIds[i]] = true
+	}
+
+	for i := 0; i < len(matchedRules); i++ {
+		rule := matchedRules[i]
+
+		if category := e.getApplicableCategory(transaction, rule, hasSplits); category != nil && transaction.CategoryId != category.CategoryId {
+			transaction.CategoryId = category.CategoryId
+			changed = true
+		}
+
+		for j := 0; j < len(rule.tagIds); j++ {
+			tagId := rule.tagIds[j]
+
+			if tag, exists := e.tagMap[tagId]; !exists || tag.Hidden || existedTagIds[tagId] {
+				continue
+			}
+
+			addTagIds = append(addTagIds, tagId)
+			existedTagIds[tagId] = true
+			changed = true
+		}
+
+		if account := e.getApplicableDestinationAccount(transaction, rule); account != nil && transaction.RelatedAccountId != account.AccountId {
+			// the destination amount is kept, so only the account with the same currency can be the new destination account
+			if currentAccount, exists := e.accountMap[transaction.RelatedAccountId]; exists && currentAccount.Currency == account.Currency {
+				transaction.RelatedAccountId = account.AccountId
+				changed = true
+			}
+		}
+
+		if rule.NewDescription != "" && transaction.Comment != rule.NewDescription {
+			transaction.Comment = rule.NewDescription
+			changed = true
+		}
+	}
+
+	return changed, addTagIds
+}
+
+func (e *TransactionRuleEngine) getMatchedRules(transaction *Transaction, counterparty string) []*compiledTransactionRule {
+	if transaction.Type != TRANSACTION_DB_TYPE_INCOME && transaction.Type != TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		return nil
+	}
+
+	transactionType, err := transaction.Type.ToTransactionType()
+
+	if err != nil {
+		return nil
+	}
+
+	if counterparty == "" {
+		counterparty = transaction.Comment
+	}
+
+	var matchedRules []*compiledTransactionRule
+
+	for i := 0; i < len(e.rules); i++ {
+		rule := e.rules[i]
+
+		if rule.TransactionType != 0 && rule.TransactionType != transactionType {
+			continue
+		}
+
+		if rule.AccountId != 0 && rule.AccountId != transaction.AccountId {
+			continue
+		}
+
+		if rule.MinAmount != nil && transaction.Amount < *rule.MinAmount {
+			continue
+		}
+
+		if rule.MaxAmount != nil && transaction.Amount > *rule.MaxAmount {
+			continue
+		}
+
+		if rule.descriptionRegex != nil && !rule.descriptionRegex.MatchString(transaction.Comment) {
+			continue
+		}
+
+		if rule.counterpartyRegex != nil && !rule.counterpartyRegex.MatchString(counterparty) {
+			continue
+		}
+
+		matchedRules = append(matchedRules, rule)
+	}
+
+	return matchedRules
+}
+
+func (e *TransactionRuleEngine) getApplicableCategory(transaction *Transaction, rule *compiledTransactionRule, hasSplits bool) *TransactionCategory {
+	if rule.CategoryId == 0 || hasSplits {
+		return nil
+	}
+
+	category, exists := e.categoryMap[rule.CategoryId]
+
+	if !exists || category.Hidden || category.ParentCategoryId == LevelOneTransactionCategoryParentId {
+		return nil
+	}
+
+	if (transaction.Type == TRANSACTION_DB_TYPE_INCOME && category.Type == CATEGORY_TYPE_INCOME) ||
+		(transaction.Type == TRANSACTION_DB_TYPE_EXPENSE && category.Type == CATEGORY_TYPE_EXPENSE) ||
+		(transaction.Type == TRANSACTION_DB_TYPE_TRANSFER_OUT && category.Type == CATEGORY_TYPE_TRANSFER) {
+		return category
+	}
+
+	return nil
+}
+
+func (e *TransactionRuleEngine) getApplicableDestinationAccount(transaction *Transaction, rule *compiledTransactionRule) *Account {
+	if rule.DestinationAccountId == 0 || transaction.Type != TRANSACTION_DB_TYPE_TRANSFER_OUT || rule.DestinationAccountId == transaction.AccountId {
+		return nil
+	}
+
+	account, exists := e.accountMap[rule.DestinationAccountId]
+
+	if !exists || account.Hidden || account.Type != ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		return nil
+	}
+
+	return account
+}
+
+// TransactionRuleInfoResponseSlice represents the slice data structure of TransactionRuleInfoResponse
+type TransactionRuleInfoResponseSlice []*TransactionRuleInfoResponse
+
+// Len returns the count of items
+func (s TransactionRuleInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionRuleInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionRuleInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionRuleEngineApplyToTransaction_MatchConditions(t *testing.T) {
+	minAmount := int64(1000)
+	maxAmount := int64(5000)
+	categoryMap := map[int64]*TransactionCategory{
+		11: {CategoryId: 11, Type: CATEGORY_TYPE_EXPENSE, ParentCategoryId: 10},
+	}
+	rules := []*TransactionRule{
+		{RuleId: 1, TransactionType: TRANSACTION_TYPE_EXPENSE, DescriptionPattern: "^Uber", MinAmount: &minAmount, MaxAmount: &maxAmount, AccountId: 1, CategoryId: 11},
+	}
+	engine := NewTransactionRuleEngine(rules, categoryMap, nil, nil)
+
+	transaction := &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, Amount: 2000, Comment: "Uber Trip"}
+	changed, _ := engine.ApplyToTransaction(transaction, nil, false)
+	assert.True(t, changed)
+	assert.Equal(t, int64(11), transaction.CategoryId)
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, Amount: 6000, Comment: "Uber Trip"}
+	changed, _ = engine.ApplyToTransaction(transaction, nil, false)
+	assert.False(t, changed)
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2, Amount: 2000, Comment: "Uber Trip"}
+	changed, _ = engine.ApplyToTransaction(transaction, nil, false)
+	assert.False(t, changed)
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 1, Amount: 2000, Comment: "Uber Trip"}
+	changed, _ = engine.ApplyToTransaction(transaction, nil, false)
+	assert.False(t, changed)
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, Amount: 2000, Comment: "Lyft"}
+	changed, _ = engine.ApplyToTransaction(transaction, nil, false)
+	assert.False(t, changed)
+}
+
+func TestTransactionRuleEngineApplyToTransaction_MultipleRules(t *testing.T) {
+	categoryMap := map[int64]*TransactionCategory{
+		11: {CategoryId: 11, Type: CATEGORY_TYPE_EXPENSE, ParentCategoryId: 10},
+		12: {CategoryId: 12, Type: CATEGORY_TYPE_EXPENSE, ParentCategoryId: 10},
+	}
+	tagMap := map[int64]*TransactionTag{
+		21: {TagId: 21},
+		22: {TagId: 22},
+		23: {TagId: 23, Hidden: true},
+	}
+	rules := []*TransactionRule{
+		{RuleId: 1, DescriptionPattern: "Market", CategoryId: 11, TagIds: "21,22"},
+		{RuleId: 2, DescriptionPattern: "Market", CategoryId: 12, TagIds: "22,23", NewDescription: "Supermarket"},
+		{RuleId: 3, DescriptionPattern: "Market", CategoryId: 11, Hidden: true},
+	}
+	engine := NewTransactionRuleEngine(rules, categoryMap, nil, tagMap)
+	assert.Equal(t, 2, engine.RuleCount())
+
+	transaction := &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 11, Comment: "Market"}
+	changed, addTagIds := engine.ApplyToTransaction(transaction, []int64{21}, false)
+	assert.True(t, changed)
+	assert.Equal(t, int64(12), transaction.CategoryId)
+	assert.Equal(t, []int64{22}, addTagIds)
+	assert.Equal(t, "Supermarket", transaction.Comment)
+
+	changed, addTagIds = engine.ApplyToTransaction(transaction, []int64{21, 22}, false)
+	assert.False(t, changed)
+	assert.Equal(t, 0, len(addTagIds))
+}
+
+func TestTransactionRuleEngineApplyToTransaction_SkipCategoryOfOtherTypeOrSplitTransaction(t *testing.T) {
+	categoryMap := map[int64]*TransactionCategory{
+		11: {CategoryId: 11, Type: CATEGORY_TYPE_INCOME, ParentCategoryId: 10},
+		12: {CategoryId: 12, Type: CATEGORY_TYPE_EXPENSE, ParentCategoryId: 10},
+	}
+	rules := []*TransactionRule{
+		{RuleId: 1, DescriptionPattern: "Refund", CategoryId: 11},
+		{RuleId: 2, DescriptionPattern: "Shop", CategoryId: 12},
+	}
+	engine := NewTransactionRuleEngine(rules, categoryMap, nil, nil)
+
+	transaction := &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 13, Comment: "Refund"}
+	changed, _ := engine.ApplyToTransaction(transaction, nil, false)
+	assert.False(t, changed)
+	assert.Equal(t, int64(13), transaction.CategoryId)
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 13, Comment: "Shop"}
+	changed, _ = engine.ApplyToTransaction(transaction, nil, true)
+	assert.False(t, changed)
+	assert.Equal(t, int64(13), transaction.CategoryId)
+}
+
+func TestTransactionRuleEngineApplyToTransaction_DestinationAccount(t *testing.T) {
+	accountMap := map[int64]*Account{
+		1: {AccountId: 1, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		2: {AccountId: 2, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		3: {AccountId: 3, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "USD"},
+		4: {AccountId: 4, Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "EUR"},
+	}
+	rules := []*TransactionRule{
+		{RuleId: 1, DescriptionPattern: "Savings", DestinationAccountId: 3},
+		{RuleId: 2, DescriptionPattern: "Travel", DestinationAccountId: 4},
+	}
+	engine := NewTransactionRuleEngine(rules, nil, accountMap, nil)
+
+	transaction := &Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 2, Comment: "Savings"}
+	changed, _ := engine.ApplyToTransaction(transaction, nil, false)
+	assert.True(t, changed)
+	assert.Equal(t, int64(3), transaction.RelatedAccountId)
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, RelatedAccountId: 2, Comment: "Travel"}
+	changed, _ = engine.ApplyToTransaction(transaction, nil, false)
+	assert.False(t, changed)
+	assert.Equal(t, int64(2), transaction.RelatedAccountId)
+
+	transaction = &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, Comment: "Savings"}
+	changed, _ = engine.ApplyToTransaction(transaction, nil, false)
+	assert.False(t, changed)
+}
+
+func TestTransactionRuleEngineApplyToImportTransaction(t *testing.T) {
+	accountMap := map[int64]*Account{
+		3: {AccountId: 3, Name: "Savings", Type: ACCOUNT_TYPE_SINGLE_ACCOUNT, Currency: "EUR"},
+	}
+	categoryMap := map[int64]*TransactionCategory{
+		11: {CategoryId: 11, Name: "Internal Transfer", Type: CATEGORY_TYPE_TRANSFER, ParentCategoryId: 10},
+	}
+	tagMap := map[int64]*TransactionTag{
+		21: {TagId: 21, Name: "Auto"},
+	}
+	rules := []*TransactionRule{
+		{RuleId: 1, CounterpartyPattern: "^ACME Bank$", CategoryId: 11, TagIds: "21", DestinationAccountId: 3, NewDescription: "Monthly Saving"},
+	}
+	engine := NewTransactionRuleEngine(rules, categoryMap, accountMap, tagMap)
+
+	transaction := &ImportTransaction{
+		Transaction:                    &Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, Comment: "Standing Order"},
+		TagIds:                         []string{"0"},
+		OriginalTagNames:               []string{"Imported"},
+		OriginalDestinationAccountName: "Unknown",
+	}
+
+	assert.True(t, engine.ApplyToImportTransaction(transaction, "ACME Bank"))
+	assert.Equal(t, int64(11), transaction.CategoryId)
+	assert.Equal(t, "Internal Transfer", transaction.OriginalCategoryName)
+	assert.Equal(t, []string{"0", "21"}, transaction.TagIds)
+	assert.Equal(t, []string{"Imported", "Auto"}, transaction.OriginalTagNames)
+	assert.Equal(t, int64(3), transaction.RelatedAccountId)
+	assert.Equal(t, "Savings", transaction.OriginalDestinationAccountName)
+	assert.Equal(t, "EUR", transaction.OriginalDestinationAccountCurrency)
+	assert.Equal(t, "Monthly Saving", transaction.Comment)
+
+	transaction = &ImportTransaction{
+		Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, Comment: "ACME Bank"},
+	}
+	assert.False(t, engine.ApplyToImportTransaction(transaction, "Other Bank"))
+	assert.True(t, engine.ApplyToImportTransaction(transaction, ""))
+}
+
+func TestNewTransactionRuleEngine_IgnoreInvalidPattern(t *testing.T) {
+	rules := []*TransactionRule{
+		{RuleId: 1, DescriptionPattern: "(", NewDescription: "Test"},
+		{RuleId: 2, CounterpartyPattern: "[", NewDescription: "Test"},
+		{RuleId: 3, DescriptionPattern: "Test", NewDescription: "Test"},
+	}
+	engine := NewTransactionRuleEngine(rules, nil, nil, nil)
+	assert.Equal(t, 1, engine.RuleCount())
+}
+
+func TestTransactionRuleHasConditionAndHasAction(t *testing.T) {
+	rule := &TransactionRule{}
+	assert.False(t, rule.HasCondition())
+	assert.False(t, rule.HasAction())
+
+	amount := int64(0)
+	rule = &TransactionRule{MinAmount: &amount, TagIds: "1"}
+	assert.True(t, rule.HasCondition())
+	assert.True(t, rule.HasAction())
+}
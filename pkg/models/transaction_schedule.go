@@ -0,0 +1,490 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// maxScheduledDateAdjustmentDays represents the maximum days that an occurrence can be moved by the adjustment policy
+const maxScheduledDateAdjustmentDays = 31
+
+// TransactionScheduleAdjustmentPolicy represents how to adjust the occurrence of scheduled transaction which falls on weekend or holiday
+type TransactionScheduleAdjustmentPolicy byte
+
+// Transaction schedule adjustment policies
+const (
+	TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_NONE                       TransactionScheduleAdjustmentPolicy = 0
+	TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_SKIP                       TransactionScheduleAdjustmentPolicy = 1
+	TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_PREVIOUS_BUSINESS_DAY      TransactionScheduleAdjustmentPolicy = 2
+	TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_NEXT_BUSINESS_DAY          TransactionScheduleAdjustmentPolicy = 3
+	TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_MODIFIED_NEXT_BUSINESS_DAY TransactionScheduleAdjustmentPolicy = 4
+)
+
+// IsValid returns whether the transaction schedule adjustment policy is valid
+func (p TransactionScheduleAdjustmentPolicy) IsValid() bool {
+	return p <= TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_MODIFIED_NEXT_BUSINESS_DAY
+}
+
+// TransactionScheduleExceptionType represents the type of exception for a single occurrence of scheduled transaction
+type TransactionScheduleExceptionType byte
+
+// Transaction schedule exception types
+const (
+	TRANSACTION_SCHEDULE_EXCEPTION_TYPE_SKIP          TransactionScheduleExceptionType = 1
+	TRANSACTION_SCHEDULE_EXCEPTION_TYPE_POSTPONE      TransactionScheduleExceptionType = 2
+	TRANSACTION_SCHEDULE_EXCEPTION_TYPE_MODIFY_AMOUNT TransactionScheduleExceptionType = 3
+)
+
+// IsValid returns whether the transaction schedule exception type is valid
+func (t TransactionScheduleExceptionType) IsValid() bool {
+	return t >= TRANSACTION_SCHEDULE_EXCEPTION_TYPE_SKIP && t <= TRANSACTION_SCHEDULE_EXCEPTION_TYPE_MODIFY_AMOUNT
+}
+
+// TransactionScheduleException represents the exception of a single occurrence of scheduled transaction stored in database
+type TransactionScheduleException struct {
+	ExceptionId          int64                            `xorm:"PK"`
+	Uid                  int64                            `xorm:"INDEX(IDX_transaction_schedule_exception_uid_deleted_template_id) NOT NULL"`
+	Deleted              bool                             `xorm:"INDEX(IDX_transaction_schedule_exception_uid_deleted_template_id) NOT NULL"`
+	TemplateId           int64                            `xorm:"INDEX(IDX_transaction_schedule_exception_uid_deleted_template_id) NOT NULL"`
+	OccurrenceDate       int32                            `xorm:"NOT NULL"`
+	Type                 TransactionScheduleExceptionType `xorm:"NOT NULL"`
+	PostponedDate        int32                            `xorm:"NOT NULL"`
+	Amount               int64                            `xorm:"NOT NULL"`
+	RelatedAccountAmount int64                            `xorm:"NOT NULL"`
+	CreatedUnixTime      int64
+	UpdatedUnixTime      int64
+	DeletedUnixTime      int64
+}
+
+// TransactionScheduleOccurrence represents a single occurrence of scheduled transaction
+type TransactionScheduleOccurrence struct {
+	OriginalDate         int32
+	Date                 int32
+	TransactionUnixTime  int64
+	Amount               int64
+	RelatedAccountAmount int64
+	Adjusted             bool
+	ExceptionType        TransactionScheduleExceptionType
+}
+
+// TransactionTemplateSchedulePreviewRequest represents all parameters of scheduled transaction occurrences preview request
+type TransactionTemplateSchedulePreviewRequest struct {
+	Id    int64 `form:"id,string" binding:"required,min=1"`
+	Count int   `form:"count" binding:"omitempty,min=1,max=100"`
+}
+
+// TransactionScheduleExceptionListRequest represents all parameters of scheduled transaction exceptions listing request
+type TransactionScheduleExceptionListRequest struct {
+	TemplateId int64 `form:"templateId,string" binding:"required,min=1"`
+}
+
+// TransactionScheduleExceptionCreateRequest represents all parameters of scheduled transaction exception creation request
+type TransactionScheduleExceptionCreateRequest struct {
+	TemplateId        int64                            `json:"templateId,string" binding:"required,min=1"`
+	OccurrenceDate    string                           `json:"occurrenceDate" binding:"required"`
+	Type              TransactionScheduleExceptionType `json:"type" binding:"required"`
+	PostponedDate     string                           `json:"postponedDate"`
+	SourceAmount      int64                            `json:"sourceAmount" binding:"validTransactionAmount"`
+	DestinationAmount int64                            `json:"destinationAmount" binding:"validTransactionAmount"`
+}
+
+// TransactionScheduleExceptionDeleteRequest represents all parameters of scheduled transaction exception deleting request
+type TransactionScheduleExceptionDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionScheduleExceptionInfoResponse represents a view-object of scheduled transaction exception
+type TransactionScheduleExceptionInfoResponse struct {
+	Id                int64                            `json:"id,string"`
+	TemplateId        int64                            `json:"templateId,string"`
+	OccurrenceDate    string                           `json:"occurrenceDate"`
+	Type              TransactionScheduleExceptionType `json:"type"`
+	PostponedDate     string                           `json:"postponedDate,omitempty"`
+	SourceAmount      *int64                           `json:"sourceAmount,omitempty"`
+	DestinationAmount *int64                           `json:"destinationAmount,omitempty"`
+}
+
+// TransactionScheduleOccurrenceResponse represents a view-object of scheduled transaction occurrence
+type TransactionScheduleOccurrenceResponse struct {
+	Date              string                           `json:"date"`
+	OriginalDate      string                           `json:"originalDate"`
+	Time              int64                            `json:"time"`
+	UtcOffset         int16                            `json:"utcOffset"`
+	SourceAmount      int64                            `json:"sourceAmount"`
+	DestinationAmount *int64                           `json:"destinationAmount,omitempty"`
+	Adjusted          bool                             `json:"adjusted"`
+	ExceptionType     TransactionScheduleExceptionType `json:"exceptionType,omitempty"`
+}
+
+// ToTransactionScheduleExceptionInfoResponse returns a view-object according to database model
+func (e *TransactionScheduleException) ToTransactionScheduleExceptionInfoResponse(templateType TransactionType) *TransactionScheduleExceptionInfoResponse {
+	response := &TransactionScheduleExceptionInfoResponse{
+		Id:             e.ExceptionId,
+		TemplateId:     e.TemplateId,
+		OccurrenceDate: utils.FormatNumericYearMonthDayToLongDate(e.OccurrenceDate),
+		Type:           e.Type,
+	}
+
+	if e.Type == TRANSACTION_SCHEDULE_EXCEPTION_TYPE_POSTPONE {
+		response.PostponedDate = utils.FormatNumericYearMonthDayToLongDate(e.PostponedDate)
+	} else if e.Type == TRANSACTION_SCHEDULE_EXCEPTION_TYPE_MODIFY_AMOUNT {
+		amount := e.Amount
+		response.SourceAmount = &amount
+
+		if templateType == TRANSACTION_TYPE_TRANSFER {
+			relatedAccountAmount := e.RelatedAccountAmount
+			response.DestinationAmount = &relatedAccountAmount
+		}
+	}
+
+	return response
+}
+
+// ToTransactionScheduleOccurrenceResponse returns a view-object according to the occurrence of scheduled transaction
+func (o *TransactionScheduleOccurrence) ToTransactionScheduleOccurrenceResponse(template *TransactionTemplate) *TransactionScheduleOccurrenceResponse {
+	response := &TransactionScheduleOccurrenceResponse{
+		Date:          utils.FormatNumericYearMonthDayToLongDate(o.Date),
+		OriginalDate:  utils.FormatNumericYearMonthDayToLongDate(o.OriginalDate),
+		Time:          o.TransactionUnixTime,
+		UtcOffset:     template.ScheduledTimezoneUtcOffset,
+		SourceAmount:  o.Amount,
+		Adjusted:      o.Adjusted,
+		ExceptionType: o.ExceptionType,
+	}
+
+	if template.Type == TRANSACTION_TYPE_TRANSFER {
+		relatedAccountAmount := o.RelatedAccountAmount
+		response.DestinationAmount = &relatedAccountAmount
+	}
+
+	return response
+}
+
+// GetScheduledTimezone returns the timezone of the scheduled transaction template
+func (t *TransactionTemplate) GetScheduledTimezone() *time.Location {
+	return time.FixedZone("Template Timezone", int(t.ScheduledTimezoneUtcOffset)*60)
+}
+
+// IsScheduledRuleDate returns whether the specified date (in template timezone) matches the frequency of the scheduled transaction template before applying any adjustment or exception
+func (t *TransactionTemplate) IsScheduledRuleDate(date time.Time) (bool, error) {
+	dates, err := t.getScheduledRuleDates(date, date)
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(dates) > 0, nil
+}
+
+// GetScheduledOccurrences returns all occurrences of the scheduled transaction template whose final transaction date is between the from date and the to date (both inclusive, in template timezone),
+// the occurrences are computed from the frequency, then the exceptions and the weekend / holiday adjustment policy are applied
+func (t *TransactionTemplate) GetScheduledOccurrences(exceptions []*TransactionScheduleException, fromDate time.Time, toDate time.Time) ([]*TransactionScheduleOccurrence, error) {
+	from := getScheduleDate(fromDate)
+	to := getScheduleDate(toDate)
+	result := make([]*TransactionScheduleOccurrence, 0)
+
+	if to.Before(from) {
+		return result, nil
+	}
+
+	ruleDates, err := t.getScheduledRuleDates(from.AddDate(0, 0, -maxScheduledDateAdjustmentDays), to.AddDate(0, 0, maxScheduledDateAdjustmentDays))
+
+	if err != nil {
+		return nil, err
+	}
+
+	exceptionsByDate := make(map[int32]*TransactionScheduleException, len(exceptions))
+
+	for i := 0; i < len(exceptions); i++ {
+		exceptionsByDate[exceptions[i].OccurrenceDate] = exceptions[i]
+	}
+
+	holidays := t.getScheduledHolidaySet()
+
+	for _, ruleDate := range ruleDates {
+		exception := exceptionsByDate[getNumericScheduleDate(ruleDate)]
+
+		if exception != nil && (exception.Type == TRANSACTION_SCHEDULE_EXCEPTION_TYPE_SKIP || exception.Type == TRANSACTION_SCHEDULE_EXCEPTION_TYPE_POSTPONE) {
+			continue
+		}
+
+		date, ok := t.adjustScheduledDate(ruleDate, holidays)
+
+		if !ok || !t.isScheduledDateInRange(date, from, to) {
+			continue
+		}
+
+		result = append(result, t.newScheduleOccurrence(ruleDate, date, exception))
+	}
+
+	// the postponed occurrences are not adjusted again, and they may be postponed far from the original dates
+	for _, exception := range exceptions {
+		if exception.Type != TRANSACTION_SCHEDULE_EXCEPTION_TYPE_POSTPONE {
+			continue
+		}
+
+		date := parseNumericScheduleDate(exception.PostponedDate)
+
+		if !t.isScheduledDateInRange(date, from, to) {
+			continue
+		}
+
+		originalDate := parseNumericScheduleDate(exception.OccurrenceDate)
+		isRuleDate, err := t.IsScheduledRuleDate(originalDate)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if isRuleDate {
+			result = append(result, t.newScheduleOccurrence(originalDate, date, exception))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Date != result[j].Date {
+			return result[i].Date < result[j].Date
+		}
+
+		return result[i].OriginalDate < result[j].OriginalDate
+	})
+
+	return result, nil
+}
+
+func (t *TransactionTemplate) newScheduleOccurrence(originalDate time.Time, date time.Time, exception *TransactionScheduleException) *TransactionScheduleOccurrence {
+	localMinutesOfDay := ((int(t.ScheduledAt)+int(t.ScheduledTimezoneUtcOffset))%1440 + 1440) % 1440
+	transactionTime := time.Date(date.Year(), date.Month(), date.Day(), 0, localMinutesOfDay, 0, 0, t.GetScheduledTimezone())
+
+	occurrence := &TransactionScheduleOccurrence{
+		OriginalDate:         getNumericScheduleDate(originalDate),
+		Date:                 getNumericScheduleDate(date),
+		TransactionUnixTime:  transactionTime.Unix(),
+		Amount:               t.Amount,
+		RelatedAccountAmount: t.RelatedAccountAmount,
+		Adjusted:             !date.Equal(originalDate),
+	}
+
+	if exception != nil {
+		occurrence.ExceptionType = exception.Type
+
+		if exception.Type == TRANSACTION_SCHEDULE_EXCEPTION_TYPE_MODIFY_AMOUNT {
+			occurrence.Amount = exception.Amount
+			occurrence.RelatedAccountAmount = exception.RelatedAccountAmount
+		}
+	}
+
+	return occurrence
+}
+
+func (t *TransactionTemplate) isScheduledDateInRange(date time.Time, from time.Time, to time.Time) bool {
+	if date.Before(from) || date.After(to) {
+		return false
+	}
+
+	startDate, endDate := t.getScheduledDateRange()
+
+	if startDate != nil && date.Before(*startDate) {
+		return false
+	}
+
+	if endDate != nil && date.After(*endDate) {
+		return false
+	}
+
+	return true
+}
+
+func (t *TransactionTemplate) adjustScheduledDate(date time.Time, holidays map[string]bool) (time.Time, bool) {
+	if t.ScheduledAdjustmentPolicy == TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_NONE || isScheduleBusinessDay(date, holidays) {
+		return date, true
+	}
+
+	switch t.ScheduledAdjustmentPolicy {
+	case TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_PREVIOUS_BUSINESS_DAY:
+		return findScheduleBusinessDay(date, -1, holidays)
+	case TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_NEXT_BUSINESS_DAY:
+		return findScheduleBusinessDay(date, 1, holidays)
+	case TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_MODIFIED_NEXT_BUSINESS_DAY:
+		nextDate, ok := findScheduleBusinessDay(date, 1, holidays)
+
+		if ok && nextDate.Month() == date.Month() {
+			return nextDate, true
+		}
+
+		return findScheduleBusinessDay(date, -1, holidays)
+	default:
+		return date, false
+	}
+}
+
+func (t *TransactionTemplate) getScheduledRuleDates(fromDate time.Time, toDate time.Time) ([]time.Time, error) {
+	from := getScheduleDate(fromDate)
+	to := getScheduleDate(toDate)
+	startDate, endDate := t.getScheduledDateRange()
+	result := make([]time.Time, 0)
+
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED {
+		return result, nil
+	}
+
+	if startDate != nil && from.Before(*startDate) {
+		from = *startDate
+	}
+
+	if endDate != nil && to.After(*endDate) {
+		to = *endDate
+	}
+
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE {
+		rule, err := utils.ParseRecurrenceRule(t.ScheduledFrequency)
+
+		if err != nil {
+			return nil, errs.ErrScheduledTransactionFrequencyInvalid
+		}
+
+		if startDate == nil {
+			return nil, errs.ErrScheduledTransactionStartDateRequired
+		}
+
+		if to.Before(from) {
+			return result, nil
+		}
+
+		return rule.GetOccurrenceDates(*startDate, from, to), nil
+	}
+
+	if t.ScheduledFrequencyType != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY &&
+		t.ScheduledFrequencyType != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY &&
+		t.ScheduledFrequencyType != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY &&
+		t.ScheduledFrequencyType != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY &&
+		t.ScheduledFrequencyType != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS {
+		return nil, errs.ErrScheduledTransactionFrequencyInvalid
+	}
+
+	if t.ScheduledFrequency == "" {
+		return nil, errs.ErrScheduledTransactionFrequencyInvalid
+	}
+
+	frequencyValues, err := utils.StringArrayToInt64Array(strings.Split(t.ScheduledFrequency, ","))
+
+	if err != nil {
+		return nil, errs.ErrScheduledTransactionFrequencyInvalid
+	}
+
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS && (startDate == nil || len(frequencyValues) != 1 || frequencyValues[0] <= 0) {
+		return nil, errs.ErrScheduledTransactionFrequencyInvalid
+	}
+
+	frequencyValueSet := utils.ToSet(frequencyValues)
+
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		switch t.ScheduledFrequencyType {
+		case TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY:
+			if !frequencyValueSet[int64(date.Weekday())] {
+				continue
+			}
+		case TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY:
+			maxDayInMonth := int64(utils.GetMaxDayOfMonth(date.Year(), date.Month()))
+
+			// the negative value means the days counted from the end of month, e.g. -1 is the last day of month
+			if !frequencyValueSet[int64(date.Day())] && !frequencyValueSet[int64(date.Day())-maxDayInMonth-1] {
+				continue
+			}
+		case TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY:
+			if !frequencyValueSet[int64(date.Month())*100+int64(date.Day())] {
+				continue
+			}
+		case TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS:
+			daysDiff := int64(date.Sub(*startDate).Hours() / 24)
+
+			if daysDiff < 0 || daysDiff%frequencyValues[0] != 0 {
+				continue
+			}
+		}
+
+		result = append(result, date)
+	}
+
+	return result, nil
+}
+
+func (t *TransactionTemplate) getScheduledDateRange() (*time.Time, *time.Time) {
+	var startDate, endDate *time.Time
+	timezone := t.GetScheduledTimezone()
+
+	if t.ScheduledStartTime != nil {
+		date := getScheduleDate(time.Unix(*t.ScheduledStartTime, 0).In(timezone))
+		startDate = &date
+	}
+
+	if t.ScheduledEndTime != nil {
+		date := getScheduleDate(time.Unix(*t.ScheduledEndTime, 0).In(timezone))
+		endDate = &date
+	}
+
+	return startDate, endDate
+}
+
+func (t *TransactionTemplate) getScheduledHolidaySet() map[string]bool {
+	holidays := t.GetScheduledHolidays()
+	result := make(map[string]bool, len(holidays))
+
+	for _, holiday := range holidays {
+		result[holiday] = true
+	}
+
+	return result
+}
+
+// ParseScheduledHoliday returns the normalized holiday which is either "YYYY-MM-DD" for a specific date or "MM-DD" for an annual date
+func ParseScheduledHoliday(holiday string) (string, error) {
+	holiday = strings.TrimSpace(holiday)
+
+	if date, err := time.Parse("2006-01-02", holiday); err == nil {
+		return date.Format("2006-01-02"), nil
+	}
+
+	// use a leap year to accept February 29th as an annual holiday
+	if date, err := time.Parse("2006-01-02", "2000-"+holiday); err == nil {
+		return date.Format("01-02"), nil
+	}
+
+	return "", errs.ErrScheduledTransactionHolidayInvalid
+}
+
+func isScheduleBusinessDay(date time.Time, holidays map[string]bool) bool {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+
+	return !holidays[date.Format("2006-01-02")] && !holidays[date.Format("01-02")]
+}
+
+func findScheduleBusinessDay(date time.Time, step int, holidays map[string]bool) (time.Time, bool) {
+	for i := 1; i <= maxScheduledDateAdjustmentDays; i++ {
+		candidate := date.AddDate(0, 0, i*step)
+
+		if isScheduleBusinessDay(candidate, holidays) {
+			return candidate, true
+		}
+	}
+
+	return date, false
+}
+
+func getScheduleDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func getNumericScheduleDate(date time.Time) int32 {
+	return int32(date.Year())*10000 + int32(date.Month())*100 + int32(date.Day())
+}
+
+func parseNumericScheduleDate(date int32) time.Time {
+	return time.Date(int(date/10000), time.Month((date%10000)/100), int(date%100), 0, 0, 0, 0, time.UTC)
+}
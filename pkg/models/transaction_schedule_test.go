@@ -0,0 +1,186 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func getTestScheduleDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func getTestScheduleOccurrenceDates(occurrences []*TransactionScheduleOccurrence) []int32 {
+	dates := make([]int32, len(occurrences))
+
+	for i := 0; i < len(occurrences); i++ {
+		dates[i] = occurrences[i].Date
+	}
+
+	return dates
+}
+
+func TestTransactionTemplateGetScheduledOccurrences_LegacyMonthlyFrequency(t *testing.T) {
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:     "1,-1",
+		ScheduledAt:            0,
+		Amount:                 100,
+	}
+
+	occurrences, err := template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.February, 1), getTestScheduleDate(2024, time.March, 31))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20240201, 20240229, 20240301, 20240331}, getTestScheduleOccurrenceDates(occurrences))
+	assert.Equal(t, int64(1706745600), occurrences[0].TransactionUnixTime)
+	assert.Equal(t, int64(100), occurrences[0].Amount)
+}
+
+func TestTransactionTemplateGetScheduledOccurrences_LegacyEveryNDaysFrequency(t *testing.T) {
+	startTime := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.FixedZone("Test Timezone", 28800)).Unix()
+	template := &TransactionTemplate{
+		ScheduledFrequencyType:     TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS,
+		ScheduledFrequency:         "10",
+		ScheduledStartTime:         &startTime,
+		ScheduledAt:                -480,
+		ScheduledTimezoneUtcOffset: 480,
+	}
+
+	occurrences, err := template.GetScheduledOccurrences(nil, getTestScheduleDate(2023, time.December, 1), getTestScheduleDate(2024, time.January, 31))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20240101, 20240111, 20240121, 20240131}, getTestScheduleOccurrenceDates(occurrences))
+	assert.Equal(t, startTime, occurrences[0].TransactionUnixTime)
+}
+
+func TestTransactionTemplateGetScheduledOccurrences_RecurrenceRuleWithoutStartDate(t *testing.T) {
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE,
+		ScheduledFrequency:     "FREQ=MONTHLY;BYDAY=2TU",
+	}
+
+	_, err := template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.January, 1), getTestScheduleDate(2024, time.January, 31))
+	assert.EqualError(t, err, errs.ErrScheduledTransactionStartDateRequired.Message)
+}
+
+func TestTransactionTemplateGetScheduledOccurrences_RecurrenceRuleWithEndDate(t *testing.T) {
+	startTime := getTestScheduleDate(2024, time.January, 1).Unix()
+	endTime := getTestScheduleDate(2024, time.March, 31).Unix() + 86399
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE,
+		ScheduledFrequency:     "FREQ=MONTHLY;BYDAY=2TU",
+		ScheduledStartTime:     &startTime,
+		ScheduledEndTime:       &endTime,
+	}
+
+	occurrences, err := template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.January, 1), getTestScheduleDate(2024, time.December, 31))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20240109, 20240213, 20240312}, getTestScheduleOccurrenceDates(occurrences))
+}
+
+func TestTransactionTemplateGetScheduledOccurrences_AdjustmentPolicy(t *testing.T) {
+	startTime := getTestScheduleDate(2024, time.January, 1).Unix()
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE,
+		ScheduledFrequency:     "FREQ=MONTHLY;BYMONTHDAY=-1",
+		ScheduledStartTime:     &startTime,
+		ScheduledHolidays:      "2024-04-30",
+	}
+
+	// 2024-03-31 is sunday, 2024-04-30 is holiday, 2024-06-30 is sunday
+	template.ScheduledAdjustmentPolicy = TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_NONE
+	occurrences, err := template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.March, 1), getTestScheduleDate(2024, time.June, 30))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20240331, 20240430, 20240531, 20240630}, getTestScheduleOccurrenceDates(occurrences))
+
+	template.ScheduledAdjustmentPolicy = TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_SKIP
+	occurrences, err = template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.March, 1), getTestScheduleDate(2024, time.June, 30))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20240531}, getTestScheduleOccurrenceDates(occurrences))
+
+	template.ScheduledAdjustmentPolicy = TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_PREVIOUS_BUSINESS_DAY
+	occurrences, err = template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.March, 1), getTestScheduleDate(2024, time.June, 30))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20240329, 20240429, 20240531, 20240628}, getTestScheduleOccurrenceDates(occurrences))
+	assert.True(t, occurrences[0].Adjusted)
+	assert.Equal(t, int32(20240331), occurrences[0].OriginalDate)
+	assert.False(t, occurrences[2].Adjusted)
+
+	template.ScheduledAdjustmentPolicy = TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_NEXT_BUSINESS_DAY
+	occurrences, err = template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.March, 1), getTestScheduleDate(2024, time.June, 30))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20240401, 20240501, 20240531}, getTestScheduleOccurrenceDates(occurrences))
+
+	template.ScheduledAdjustmentPolicy = TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_MODIFIED_NEXT_BUSINESS_DAY
+	occurrences, err = template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.March, 1), getTestScheduleDate(2024, time.June, 30))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20240329, 20240429, 20240531, 20240628}, getTestScheduleOccurrenceDates(occurrences))
+}
+
+func TestTransactionTemplateGetScheduledOccurrences_AnnualHoliday(t *testing.T) {
+	template := &TransactionTemplate{
+		ScheduledFrequencyType:    TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY,
+		ScheduledFrequency:        "1225",
+		ScheduledAdjustmentPolicy: TRANSACTION_SCHEDULE_ADJUSTMENT_POLICY_NEXT_BUSINESS_DAY,
+		ScheduledHolidays:         "12-25,12-26",
+	}
+
+	occurrences, err := template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.January, 1), getTestScheduleDate(2025, time.December, 31))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20241227, 20251229}, getTestScheduleOccurrenceDates(occurrences))
+}
+
+func TestTransactionTemplateGetScheduledOccurrences_Exceptions(t *testing.T) {
+	startTime := getTestScheduleDate(2024, time.January, 1).Unix()
+	template := &TransactionTemplate{
+		Type:                   TRANSACTION_TYPE_EXPENSE,
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE,
+		ScheduledFrequency:     "FREQ=WEEKLY;BYDAY=MO",
+		ScheduledStartTime:     &startTime,
+		Amount:                 1000,
+	}
+
+	exceptions := []*TransactionScheduleException{
+		{OccurrenceDate: 20240108, Type: TRANSACTION_SCHEDULE_EXCEPTION_TYPE_SKIP},
+		{OccurrenceDate: 20240115, Type: TRANSACTION_SCHEDULE_EXCEPTION_TYPE_POSTPONE, PostponedDate: 20240124},
+		{OccurrenceDate: 20240122, Type: TRANSACTION_SCHEDULE_EXCEPTION_TYPE_MODIFY_AMOUNT, Amount: 1500},
+		{OccurrenceDate: 20240123, Type: TRANSACTION_SCHEDULE_EXCEPTION_TYPE_POSTPONE, PostponedDate: 20240125},
+	}
+
+	occurrences, err := template.GetScheduledOccurrences(exceptions, getTestScheduleDate(2024, time.January, 1), getTestScheduleDate(2024, time.January, 31))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{20240101, 20240122, 20240124, 20240129}, getTestScheduleOccurrenceDates(occurrences))
+	assert.Equal(t, int64(1000), occurrences[0].Amount)
+	assert.Equal(t, int64(1500), occurrences[1].Amount)
+	assert.Equal(t, TRANSACTION_SCHEDULE_EXCEPTION_TYPE_MODIFY_AMOUNT, occurrences[1].ExceptionType)
+	assert.Equal(t, int32(20240115), occurrences[2].OriginalDate)
+	assert.Equal(t, TRANSACTION_SCHEDULE_EXCEPTION_TYPE_POSTPONE, occurrences[2].ExceptionType)
+	assert.Equal(t, int64(1000), occurrences[2].Amount)
+}
+
+func TestTransactionTemplateGetScheduledOccurrences_DisabledFrequency(t *testing.T) {
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED,
+	}
+
+	occurrences, err := template.GetScheduledOccurrences(nil, getTestScheduleDate(2024, time.January, 1), getTestScheduleDate(2024, time.December, 31))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(occurrences))
+}
+
+func TestParseScheduledHoliday(t *testing.T) {
+	holiday, err := ParseScheduledHoliday("2024-10-01")
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-10-01", holiday)
+
+	holiday, err = ParseScheduledHoliday("02-29")
+	assert.Nil(t, err)
+	assert.Equal(t, "02-29", holiday)
+
+	_, err = ParseScheduledHoliday("2023-02-29")
+	assert.EqualError(t, err, errs.ErrScheduledTransactionHolidayInvalid.Message)
+
+	_, err = ParseScheduledHoliday("13-01")
+	assert.EqualError(t, err, errs.ErrScheduledTransactionHolidayInvalid.Message)
+}
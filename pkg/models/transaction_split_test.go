@@ -0,0 +1,72 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionSplitSliceLess(t *testing.T) {
+	var splitSlice TransactionSplitSlice
+	splitSlice = append(splitSlice, &TransactionSplit{
+		SplitId:      1,
+		DisplayOrder: 3,
+	})
+	splitSlice = append(splitSlice, &TransactionSplit{
+		SplitId:      2,
+		DisplayOrder: 1,
+	})
+	splitSlice = append(splitSlice, &TransactionSplit{
+		SplitId:      3,
+		DisplayOrder: 2,
+	})
+
+	sort.Sort(splitSlice)
+
+	assert.Equal(t, int64(2), splitSlice[0].SplitId)
+	assert.Equal(t, int64(3), splitSlice[1].SplitId)
+	assert.Equal(t, int64(1), splitSlice[2].SplitId)
+}
+
+func TestTransactionSplitSliceIsEquals(t *testing.T) {
+	splits := TransactionSplitSlice{
+		{SplitId: 1, CategoryId: 10, Amount: 100, Comment: "foo"},
+		{SplitId: 2, CategoryId: 11, Amount: 200, Comment: "bar"},
+	}
+
+	assert.True(t, splits.IsEquals(TransactionSplitSlice{
+		{CategoryId: 10, Amount: 100, Comment: "foo"},
+		{CategoryId: 11, Amount: 200, Comment: "bar"},
+	}))
+
+	assert.False(t, splits.IsEquals(TransactionSplitSlice{
+		{CategoryId: 11, Amount: 200, Comment: "bar"},
+		{CategoryId: 10, Amount: 100, Comment: "foo"},
+	}))
+
+	assert.False(t, splits.IsEquals(TransactionSplitSlice{
+		{CategoryId: 10, Amount: 100, Comment: "foo"},
+		{CategoryId: 11, Amount: 201, Comment: "bar"},
+	}))
+
+	assert.False(t, splits.IsEquals(TransactionSplitSlice{
+		{CategoryId: 10, Amount: 100, Comment: "foo"},
+	}))
+
+	assert.True(t, TransactionSplitSlice(nil).IsEquals(TransactionSplitSlice{}))
+}
+
+func TestTransactionSplitSliceToTransactionSplitInfoResponses(t *testing.T) {
+	assert.Nil(t, TransactionSplitSlice(nil).ToTransactionSplitInfoResponses())
+
+	splits := TransactionSplitSlice{
+		{SplitId: 1, CategoryId: 10, Amount: 100, Comment: "foo"},
+	}
+
+	responses := splits.ToTransactionSplitInfoResponses()
+	assert.Equal(t, 1, len(responses))
+	assert.Equal(t, int64(10), responses[0].CategoryId)
+	assert.Equal(t, int64(100), responses[0].Amount)
+	assert.Equal(t, "foo", responses[0].Comment)
+}
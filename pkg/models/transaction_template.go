@@ -27,6 +27,7 @@ const (
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY        TransactionScheduleFrequencyType = 3
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY       TransactionScheduleFrequencyType = 4
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS TransactionScheduleFrequencyType = 5
+	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE        TransactionScheduleFrequencyType = 6
 )
 
 // TransactionTemplate represents transaction template stored in database
@@ -45,6 +46,8 @@ type TransactionTemplate struct {
 	ScheduledEndTime           *int64                           `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
 	ScheduledAt                int16                            `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_time)"`
 	ScheduledTimezoneUtcOffset int16
+	ScheduledAdjustmentPolicy  TransactionScheduleAdjustmentPolicy
+	ScheduledHolidays          string `xorm:"VARCHAR(1000)"`
 	TagIds                     string `xorm:"VARCHAR(255) NOT NULL"`
 	Amount                     int64  `xorm:"NOT NULL"`
 	RelatedAccountId           int64  `xorm:"NOT NULL"`
@@ -70,23 +73,25 @@ type TransactionTemplateGetRequest struct {
 
 // TransactionTemplateCreateRequest represents all parameters of transaction template creation request
 type TransactionTemplateCreateRequest struct {
-	TemplateType               TransactionTemplateType           `json:"templateType"`
-	Name                       string                            `json:"name" binding:"required,notBlank,max=64"`
-	Type                       TransactionType                   `json:"type" binding:"required"`
-	CategoryId                 int64                             `json:"categoryId,string" binding:"required,min=1"`
-	SourceAccountId            int64                             `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId       int64                             `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount               int64                             `json:"sourceAmount" binding:"validTransactionAmount"`
-	DestinationAmount          int64                             `json:"destinationAmount" binding:"validTransactionAmount"`
-	HideAmount                 bool                              `json:"hideAmount"`
-	TagIds                     []string                          `json:"tagIds"`
-	Comment                    string                            `json:"comment" binding:"max=255"`
-	ScheduledFrequencyType     *TransactionScheduleFrequencyType `json:"scheduledFrequencyType" binding:"omitempty"`
-	ScheduledFrequency         *string                           `json:"scheduledFrequency" binding:"omitempty"`
-	ScheduledStartDate         *string                           `json:"scheduledStartDate" binding:"omitempty"`
-	ScheduledEndDate           *string                           `json:"scheduledEndDate" binding:"omitempty"`
-	ScheduledTimezoneUtcOffset *int16                            `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
-	ClientSessionId            string                            `json:"clientSessionId"`
+	TemplateType               TransactionTemplateType              `json:"templateType"`
+	Name                       string                               `json:"name" binding:"required,notBlank,max=64"`
+	Type                       TransactionType                      `json:"type" binding:"required"`
+	CategoryId                 int64                                `json:"categoryId,string" binding:"required,min=1"`
+	SourceAccountId            int64                                `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId       int64                                `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount               int64                                `json:"sourceAmount" binding:"validTransactionAmount"`
+	DestinationAmount          int64                                `json:"destinationAmount" binding:"validTransactionAmount"`
+	HideAmount                 bool                                 `json:"hideAmount"`
+	TagIds                     []string                             `json:"tagIds"`
+	Comment                    string                               `json:"comment" binding:"max=255"`
+	ScheduledFrequencyType     *TransactionScheduleFrequencyType    `json:"scheduledFrequencyType" binding:"omitempty"`
+	ScheduledFrequency         *string                              `json:"scheduledFrequency" binding:"omitempty"`
+	ScheduledStartDate         *string                              `json:"scheduledStartDate" binding:"omitempty"`
+	ScheduledEndDate           *string                              `json:"scheduledEndDate" binding:"omitempty"`
+	ScheduledAdjustmentPolicy  *TransactionScheduleAdjustmentPolicy `json:"scheduledAdjustmentPolicy" binding:"omitempty"`
+	ScheduledHolidays          []string                             `json:"scheduledHolidays" binding:"omitempty,max=80"`
+	ScheduledTimezoneUtcOffset *int16                               `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
+	ClientSessionId            string                               `json:"clientSessionId"`
 }
 
 // TransactionTemplateModifyNameRequest represents all parameters of transaction template name modification request
@@ -97,22 +102,24 @@ type TransactionTemplateModifyNameRequest struct {
 
 // TransactionTemplateModifyRequest represents all parameters of transaction template modification request
 type TransactionTemplateModifyRequest struct {
-	Id                         int64                             `json:"id,string" binding:"required,min=1"`
-	Name                       string                            `json:"name" binding:"required,notBlank,max=64"`
-	Type                       TransactionType                   `json:"type" binding:"required"`
-	CategoryId                 int64                             `json:"categoryId,string" binding:"required,min=1"`
-	SourceAccountId            int64                             `json:"sourceAccountId,string" binding:"required,min=1"`
-	DestinationAccountId       int64                             `json:"destinationAccountId,string" binding:"min=0"`
-	SourceAmount               int64                             `json:"sourceAmount" binding:"validTransactionAmount"`
-	DestinationAmount          int64                             `json:"destinationAmount" binding:"validTransactionAmount"`
-	HideAmount                 bool                              `json:"hideAmount"`
-	TagIds                     []string                          `json:"tagIds"`
-	Comment                    string                            `json:"comment" binding:"max=255"`
-	ScheduledFrequencyType     *TransactionScheduleFrequencyType `json:"scheduledFrequencyType" binding:"omitempty"`
-	ScheduledFrequency         *string                           `json:"scheduledFrequency" binding:"omitempty"`
-	ScheduledStartDate         *string                           `json:"scheduledStartDate" binding:"omitempty"`
-	ScheduledEndDate           *string                           `json:"scheduledEndDate" binding:"omitempty"`
-	ScheduledTimezoneUtcOffset *int16                            `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
+	Id                         int64                                `json:"id,string" binding:"required,min=1"`
+	Name                       string                               `json:"name" binding:"required,notBlank,max=64"`
+	Type                       TransactionType                      `json:"type" binding:"required"`
+	CategoryId                 int64                                `json:"categoryId,string" binding:"required,min=1"`
+	SourceAccountId            int64                                `json:"sourceAccountId,string" binding:"required,min=1"`
+	DestinationAccountId       int64                                `json:"destinationAccountId,string" binding:"min=0"`
+	SourceAmount               int64                                `json:"sourceAmount" binding:"validTransactionAmount"`
+	DestinationAmount          int64                                `json:"destinationAmount" binding:"validTransactionAmount"`
+	HideAmount                 bool                                 `json:"hideAmount"`
+	TagIds                     []string                             `json:"tagIds"`
+	Comment                    string                               `json:"comment" binding:"max=255"`
+	ScheduledFrequencyType     *TransactionScheduleFrequencyType    `json:"scheduledFrequencyType" binding:"omitempty"`
+	ScheduledFrequency         *string                              `json:"scheduledFrequency" binding:"omitempty"`
+	ScheduledStartDate         *string                              `json:"scheduledStartDate" binding:"omitempty"`
+	ScheduledEndDate           *string                              `json:"scheduledEndDate" binding:"omitempty"`
+	ScheduledAdjustmentPolicy  *TransactionScheduleAdjustmentPolicy `json:"scheduledAdjustmentPolicy" binding:"omitempty"`
+	ScheduledHolidays          []string                             `json:"scheduledHolidays" binding:"omitempty,max=80"`
+	ScheduledTimezoneUtcOffset *int16                               `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
 }
 
 // TransactionTemplateHideRequest represents all parameters of transaction template hiding request
@@ -139,15 +146,17 @@ type TransactionTemplateDeleteRequest struct {
 
 type TransactionTemplateInfoResponse struct {
 	*TransactionInfoResponse
-	TemplateType           TransactionTemplateType           `json:"templateType"`
-	Name                   string                            `json:"name"`
-	ScheduledFrequencyType *TransactionScheduleFrequencyType `json:"scheduledFrequencyType,omitempty"`
-	ScheduledFrequency     *string                           `json:"scheduledFrequency,omitempty"`
-	ScheduledStartDate     *string                           `json:"scheduledStartDate" binding:"omitempty"`
-	ScheduledEndDate       *string                           `json:"scheduledEndDate" binding:"omitempty"`
-	ScheduledAt            *int16                            `json:"scheduledAt,omitempty"`
-	DisplayOrder           int32                             `json:"displayOrder"`
-	Hidden                 bool                              `json:"hidden"`
+	TemplateType              TransactionTemplateType              `json:"templateType"`
+	Name                      string                               `json:"name"`
+	ScheduledFrequencyType    *TransactionScheduleFrequencyType    `json:"scheduledFrequencyType,omitempty"`
+	ScheduledFrequency        *string                              `json:"scheduledFrequency,omitempty"`
+	ScheduledStartDate        *string                              `json:"scheduledStartDate" binding:"omitempty"`
+	ScheduledEndDate          *string                              `json:"scheduledEndDate" binding:"omitempty"`
+	ScheduledAt               *int16                               `json:"scheduledAt,omitempty"`
+	ScheduledAdjustmentPolicy *TransactionScheduleAdjustmentPolicy `json:"scheduledAdjustmentPolicy,omitempty"`
+	ScheduledHolidays         []string                             `json:"scheduledHolidays,omitempty"`
+	DisplayOrder              int32                                `json:"displayOrder"`
+	Hidden                    bool                                 `json:"hidden"`
 }
 
 // GetTagIds returns all tag ids of the transaction template
@@ -163,6 +172,17 @@ func (t *TransactionTemplate) GetTagIds() []int64 {
 	return result
 }
 
+// GetScheduledHolidays returns all holidays of the scheduled transaction template, each item is either "YYYY-MM-DD" for a specific date or "MM-DD" for an annual date
+func (t *TransactionTemplate) GetScheduledHolidays() []string {
+	holidays := make([]string, 0)
+
+	if t.ScheduledHolidays != "" {
+		holidays = strings.Split(t.ScheduledHolidays, ",")
+	}
+
+	return holidays
+}
+
 // ToTransactionTemplateInfoResponse returns a view-object according to database model
 func (t *TransactionTemplate) ToTransactionTemplateInfoResponse(serverUtcOffset int16) *TransactionTemplateInfoResponse {
 	utcOffset := serverUtcOffset
@@ -183,6 +203,8 @@ func (t *TransactionTemplate) ToTransactionTemplateInfoResponse(serverUtcOffset
 		response.ScheduledFrequencyType = &t.ScheduledFrequencyType
 		response.ScheduledFrequency = &t.ScheduledFrequency
 		response.ScheduledAt = &t.ScheduledAt
+		response.ScheduledAdjustmentPolicy = &t.ScheduledAdjustmentPolicy
+		response.ScheduledHolidays = t.GetScheduledHolidays()
 
 		templateTimeZone := time.FixedZone("Template Timezone", int(t.ScheduledTimezoneUtcOffset)*60)
 
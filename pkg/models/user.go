@@ -83,6 +83,35 @@ func (s AmountColorType) String() string {
 	}
 }
 
+// UserRole represents the role of user
+type UserRole byte
+
+// User Roles
+const (
+	USER_ROLE_NORMAL        UserRole = 0
+	USER_ROLE_ADMINISTRATOR UserRole = 1
+	USER_ROLE_LEDGER_BOOK   UserRole = 2
+)
+
+// IsValid returns whether the user role is valid
+func (r UserRole) IsValid() bool {
+	return r == USER_ROLE_NORMAL || r == USER_ROLE_ADMINISTRATOR
+}
+
+// String returns a textual representation of the user role enum
+func (r UserRole) String() string {
+	switch r {
+	case USER_ROLE_NORMAL:
+		return "Normal"
+	case USER_ROLE_ADMINISTRATOR:
+		return "Administrator"
+	case USER_ROLE_LEDGER_BOOK:
+		return "Ledger Book"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(r))
+	}
+}
+
 // User represents user data stored in database
 type User struct {
 	Uid                   int64  `xorm:"PK"`
@@ -114,7 +143,10 @@ type User struct {
 	CoordinateDisplayType core.CoordinateDisplayType `xorm:"TINYINT"`
 	ExpenseAmountColor    AmountColorType            `xorm:"TINYINT"`
 	IncomeAmountColor     AmountColorType            `xorm:"TINYINT"`
+	BudgetAlertThresholds string                     `xorm:"VARCHAR(32)"`
+	BudgetAlertTimezone   string                     `xorm:"VARCHAR(64)"`
 	FeatureRestriction    core.UserFeatureRestrictions
+	Role                  UserRole `xorm:"TINYINT"`
 	Disabled              bool
 	Deleted               bool `xorm:"NOT NULL"`
 	EmailVerified         bool `xorm:"NOT NULL"`
@@ -153,6 +185,7 @@ type UserBasicInfo struct {
 	CoordinateDisplayType core.CoordinateDisplayType `json:"coordinateDisplayType"`
 	ExpenseAmountColor    AmountColorType            `json:"expenseAmountColor"`
 	IncomeAmountColor     AmountColorType            `json:"incomeAmountColor"`
+	BudgetAlertThresholds string                     `json:"budgetAlertThresholds"`
 	EmailVerified         bool                       `json:"emailVerified"`
 }
 
@@ -162,6 +195,12 @@ type UserLoginRequest struct {
 	Password  string `json:"password" binding:"required,min=6,max=128"`
 }
 
+// LDAPLoginRequest represents all parameters of ldap user login request
+type LDAPLoginRequest struct {
+	LoginName string `json:"loginName" binding:"required,notBlank,max=100"`
+	Password  string `json:"password" binding:"required,max=128"`
+}
+
 // UserRegisterRequest represents all parameters of user registering request
 type UserRegisterRequest struct {
 	Username        string       `json:"username" binding:"required,notBlank,max=32,validUsername"`
@@ -220,6 +259,7 @@ type UserProfileUpdateRequest struct {
 	CoordinateDisplayType *core.CoordinateDisplayType `json:"coordinateDisplayType" binding:"omitempty,min=0,max=6"`
 	ExpenseAmountColor    *AmountColorType            `json:"expenseAmountColor" binding:"omitempty,min=0,max=4"`
 	IncomeAmountColor     *AmountColorType            `json:"incomeAmountColor" binding:"omitempty,min=0,max=4"`
+	BudgetAlertThresholds *string                     `json:"budgetAlertThresholds" binding:"omitempty,max=32"`
 }
 
 // UserProfileUpdateResponse represents the data returns to frontend after updating profile
@@ -231,8 +271,9 @@ type UserProfileUpdateResponse struct {
 // UserProfileResponse represents a view-object of user profile
 type UserProfileResponse struct {
 	*UserBasicInfo
-	NoPassword  bool  `json:"noPassword,omitempty"`
-	LastLoginAt int64 `json:"lastLoginAt"`
+	NoPassword      bool  `json:"noPassword,omitempty"`
+	IsAdministrator bool  `json:"isAdministrator,omitempty"`
+	LastLoginAt     int64 `json:"lastLoginAt"`
 }
 
 // CanEditTransactionByTransactionTime returns whether this user can edit transaction with specified transaction time
@@ -292,6 +333,43 @@ func (u *User) CanEditTransactionByTransactionTime(transactionTime int64, client
 	return false
 }
 
+// SetBudgetAlertTimezone sets the timezone which budget alerts use, the timezone name is saved if it is a valid IANA timezone name, otherwise the timezone offset at the specified time is saved
+func (u *User) SetBudgetAlertTimezone(timezone *time.Location, unixTime int64) {
+	if location, err := time.LoadLocation(timezone.String()); err == nil && location != nil {
+		u.BudgetAlertTimezone = timezone.String()
+		return
+	}
+
+	u.BudgetAlertTimezone = utils.FormatTimezoneOffset(unixTime, timezone)
+}
+
+// GetBudgetAlertTimezone returns the timezone which budget alerts use, returns the local timezone of server if it is not set or invalid
+func (u *User) GetBudgetAlertTimezone() *time.Location {
+	if u.BudgetAlertTimezone == "" {
+		return time.Local
+	}
+
+	if location, err := utils.ParseFromTimezoneOffset(u.BudgetAlertTimezone); err == nil && location != nil {
+		return location
+	}
+
+	if location, err := time.LoadLocation(u.BudgetAlertTimezone); err == nil && location != nil {
+		return location
+	}
+
+	return time.Local
+}
+
+// IsAdministrator returns whether this user can manage all users of current instance
+func (u *User) IsAdministrator() bool {
+	return u.Role == USER_ROLE_ADMINISTRATOR
+}
+
+// IsLedgerBook returns whether the user is the internal user which holds the data of a shared ledger
+func (u *User) IsLedgerBook() bool {
+	return u.Role == USER_ROLE_LEDGER_BOOK
+}
+
 // ToUserBasicInfo returns a user basic view-object according to database model
 func (u *User) ToUserBasicInfo(avatarProvider core.UserAvatarProviderType, avatarUrl string) *UserBasicInfo {
 	fiscalYearStart := u.FiscalYearStart
@@ -328,6 +406,7 @@ func (u *User) ToUserBasicInfo(avatarProvider core.UserAvatarProviderType, avata
 		CoordinateDisplayType: u.CoordinateDisplayType,
 		ExpenseAmountColor:    u.ExpenseAmountColor,
 		IncomeAmountColor:     u.IncomeAmountColor,
+		BudgetAlertThresholds: u.BudgetAlertThresholds,
 		EmailVerified:         u.EmailVerified,
 	}
 }
@@ -335,8 +414,9 @@ func (u *User) ToUserBasicInfo(avatarProvider core.UserAvatarProviderType, avata
 // ToUserProfileResponse returns a user profile view-object according to database model
 func (u *User) ToUserProfileResponse(basicInfo *UserBasicInfo) *UserProfileResponse {
 	return &UserProfileResponse{
-		UserBasicInfo: basicInfo,
-		NoPassword:    u.Password == "",
-		LastLoginAt:   u.LastLoginUnixTime,
+		UserBasicInfo:   basicInfo,
+		NoPassword:      u.Password == "",
+		IsAdministrator: u.IsAdministrator(),
+		LastLoginAt:     u.LastLoginUnixTime,
 	}
 }
@@ -0,0 +1,61 @@
+package models
+
+// UserDataBackupCurrentVersion represents the current version of user data backup archive
+const UserDataBackupCurrentVersion = 1
+
+// UserDataBackupDataFileName represents the file name of user data in backup archive
+const UserDataBackupDataFileName = "backup.json"
+
+// UserDataBackupCustomIconDirectory represents the directory name of user custom icon files in backup archive
+const UserDataBackupCustomIconDirectory = "icons"
+
+// UserDataBackupTransactionPictureDirectory represents the directory name of transaction picture files in backup archive
+const UserDataBackupTransactionPictureDirectory = "pictures"
+
+// UserDataBackup represents all data of a user stored in backup archive
+type UserDataBackup struct {
+	Version                       int                             `json:"version"`
+	ExportedUnixTime              int64                           `json:"exportedUnixTime"`
+	Accounts                      []*Account                      `json:"accounts"`
+	TransactionCategories         []*TransactionCategory          `json:"transactionCategories"`
+	TransactionTagGroups          []*TransactionTagGroup          `json:"transactionTagGroups"`
+	TransactionTags               []*TransactionTag               `json:"transactionTags"`
+	Transactions                  []*Transaction                  `json:"transactions"`
+	TransactionTagIndexes         []*TransactionTagIndex          `json:"transactionTagIndexes"`
+	TransactionSplits             []*TransactionSplit             `json:"transactionSplits"`
+	TransactionTemplates          []*TransactionTemplate          `json:"transactionTemplates"`
+	TransactionScheduleExceptions []*TransactionScheduleException `json:"transactionScheduleExceptions"`
+	TransactionPictureInfos       []*TransactionPictureInfo       `json:"transactionPictureInfos"`
+	InsightsExplorers             []*InsightsExplorer             `json:"insightsExplorers"`
+	UserCustomExchangeRates       []*UserCustomExchangeRate       `json:"userCustomExchangeRates"`
+	UserCustomIcons               []*UserCustomIcon               `json:"userCustomIcons"`
+	Budgets                       []*Budget                       `json:"budgets"`
+	InvestmentSecurities          []*InvestmentSecurity           `json:"investmentSecurities"`
+	InvestmentTransactions        []*InvestmentTransaction        `json:"investmentTransactions"`
+	SecurityPrices                []*SecurityPrice                `json:"securityPrices"`
+	TransactionRules              []*TransactionRule              `json:"transactionRules"`
+}
+
+// UserDataRestoreResponse represents a view-object of user data restoring result
+type UserDataRestoreResponse struct {
+	TotalAccountCount             int64 `json:"totalAccountCount,string"`
+	TotalTransactionCategoryCount int64 `json:"totalTransactionCategoryCount,string"`
+	TotalTransactionTagCount      int64 `json:"totalTransactionTagCount,string"`
+	TotalTransactionCount         int64 `json:"totalTransactionCount,string"`
+	TotalTransactionPictureCount  int64 `json:"totalTransactionPictureCount,string"`
+	TotalTransactionTemplateCount int64 `json:"totalTransactionTemplateCount,string"`
+	TotalCustomIconCount          int64 `json:"totalCustomIconCount,string"`
+}
+
+// ToUserDataRestoreResponse returns a view-object of user data restoring result according to the backup data
+func (b *UserDataBackup) ToUserDataRestoreResponse() *UserDataRestoreResponse {
+	return &UserDataRestoreResponse{
+		TotalAccountCount:             int64(len(b.Accounts)),
+		TotalTransactionCategoryCount: int64(len(b.TransactionCategories)),
+		TotalTransactionTagCount:      int64(len(b.TransactionTags)),
+		TotalTransactionCount:         int64(len(b.Transactions)),
+		TotalTransactionPictureCount:  int64(len(b.TransactionPictureInfos)),
+		TotalTransactionTemplateCount: int64(len(b.TransactionTemplates)),
+		TotalCustomIconCount:          int64(len(b.UserCustomIcons)),
+	}
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// UserPasskey represents user passkey data stored in database
+type UserPasskey struct {
+	PasskeyId        int64                `xorm:"PK"`
+	Uid              int64                `xorm:"INDEX(IDX_user_passkey_uid) NOT NULL"`
+	Name             string               `xorm:"VARCHAR(64) NOT NULL"`
+	CredentialId     string               `xorm:"VARCHAR(255) UNIQUE NOT NULL"`
+	Credential       *webauthn.Credential `xorm:"BLOB NOT NULL"`
+	CreatedUnixTime  int64
+	LastUsedUnixTime int64
+}
+
+// UserPasskeyRegisterRequest represents all parameters of user passkey registration finishing request
+type UserPasskeyRegisterRequest struct {
+	Name       string          `json:"name" binding:"required,notBlank,max=64"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+// UserPasskeyDeleteRequest represents all parameters of user passkey deleting request
+type UserPasskeyDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// UserPasskeyLoginRequest represents all parameters of passkey login request
+type UserPasskeyLoginRequest struct {
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+// UserPasskeyInfoResponse represents a view-object of user passkey
+type UserPasskeyInfoResponse struct {
+	Id         int64  `json:"id,string"`
+	Name       string `json:"name"`
+	CreatedAt  int64  `json:"createdAt"`
+	LastUsedAt int64  `json:"lastUsedAt,omitempty"`
+}
+
+// ToUserPasskeyInfoResponse returns a view-object according to database model
+func (p *UserPasskey) ToUserPasskeyInfoResponse() *UserPasskeyInfoResponse {
+	return &UserPasskeyInfoResponse{
+		Id:         p.PasskeyId,
+		Name:       p.Name,
+		CreatedAt:  p.CreatedUnixTime,
+		LastUsedAt: p.LastUsedUnixTime,
+	}
+}
+
+// UserPasskeyInfoResponseSlice represents the slice data structure of UserPasskeyInfoResponse
+type UserPasskeyInfoResponseSlice []*UserPasskeyInfoResponse
+
+// Len returns the count of items
+func (s UserPasskeyInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s UserPasskeyInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s UserPasskeyInfoResponseSlice) Less(i, j int) bool {
+	return s[i].CreatedAt > s[j].CreatedAt
+}
+
+// PasskeyUser represents a user and all passkeys of the user, which implements the webauthn user interface
+type PasskeyUser struct {
+	User     *User
+	Passkeys []*UserPasskey
+}
+
+// WebAuthnID returns the user handle of the user
+func (u *PasskeyUser) WebAuthnID() []byte {
+	return GetPasskeyUserHandle(u.User.Uid)
+}
+
+// WebAuthnName returns the user name of the user
+func (u *PasskeyUser) WebAuthnName() string {
+	return u.User.Username
+}
+
+// WebAuthnDisplayName returns the display name of the user
+func (u *PasskeyUser) WebAuthnDisplayName() string {
+	if u.User.Nickname != "" {
+		return u.User.Nickname
+	}
+
+	return u.User.Username
+}
+
+// WebAuthnCredentials returns all credentials of the user
+func (u *PasskeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.Passkeys))
+
+	for i := 0; i < len(u.Passkeys); i++ {
+		if u.Passkeys[i].Credential != nil {
+			credentials = append(credentials, *u.Passkeys[i].Credential)
+		}
+	}
+
+	return credentials
+}
+
+// GetPasskeyByCredentialId returns the passkey of the user according to the specified credential id
+func (u *PasskeyUser) GetPasskeyByCredentialId(credentialId []byte) *UserPasskey {
+	encodedCredentialId := EncodePasskeyCredentialId(credentialId)
+
+	for i := 0; i < len(u.Passkeys); i++ {
+		if u.Passkeys[i].CredentialId == encodedCredentialId {
+			return u.Passkeys[i]
+		}
+	}
+
+	return nil
+}
+
+// GetPasskeyUserHandle returns the passkey user handle according to the user uid
+func GetPasskeyUserHandle(uid int64) []byte {
+	userHandle := make([]byte, 8)
+	binary.BigEndian.PutUint64(userHandle, uint64(uid))
+	return userHandle
+}
+
+// GetUidFromPasskeyUserHandle returns the user uid according to the passkey user handle
+func GetUidFromPasskeyUserHandle(userHandle []byte) int64 {
+	if len(userHandle) != 8 {
+		return 0
+	}
+
+	return int64(binary.BigEndian.Uint64(userHandle))
+}
+
+// EncodePasskeyCredentialId returns the textual credential id which is stored in database
+func EncodePasskeyCredentialId(credentialId []byte) string {
+	return base64.RawURLEncoding.EncodeToString(credentialId)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPasskeyUserHandle(t *testing.T) {
+	userHandle := GetPasskeyUserHandle(1234567890123456789)
+	assert.Equal(t, 8, len(userHandle))
+	assert.Equal(t, int64(1234567890123456789), GetUidFromPasskeyUserHandle(userHandle))
+
+	assert.Equal(t, int64(0), GetUidFromPasskeyUserHandle(nil))
+	assert.Equal(t, int64(0), GetUidFromPasskeyUserHandle([]byte{1, 2, 3}))
+}
+
+func TestPasskeyUser_WebAuthnCredentials(t *testing.T) {
+	passkeyUser := &PasskeyUser{
+		User: &User{Uid: 1, Username: "username"},
+		Passkeys: []*UserPasskey{
+			{PasskeyId: 1, CredentialId: EncodePasskeyCredentialId([]byte{1, 2, 3}), Credential: &webauthn.Credential{ID: []byte{1, 2, 3}}},
+			{PasskeyId: 2, CredentialId: EncodePasskeyCredentialId([]byte{4, 5, 6}), Credential: &webauthn.Credential{ID: []byte{4, 5, 6}}},
+		},
+	}
+
+	assert.Equal(t, "username", passkeyUser.WebAuthnName())
+	assert.Equal(t, "username", passkeyUser.WebAuthnDisplayName())
+	assert.Equal(t, 2, len(passkeyUser.WebAuthnCredentials()))
+
+	passkey := passkeyUser.GetPasskeyByCredentialId([]byte{4, 5, 6})
+	assert.NotNil(t, passkey)
+	assert.Equal(t, int64(2), passkey.PasskeyId)
+
+	assert.Nil(t, passkeyUser.GetPasskeyByCredentialId([]byte{7, 8, 9}))
+}
@@ -181,3 +181,31 @@ func TestUserCanEditTransactionByTransactionTime_ScopeIsLastReconciledTimeOrLate
 	assert.Equal(t, false, user.CanEditTransactionByTransactionTime(utils.GetMinTransactionTimeFromUnixTime(destinationAccountLastReconciledTime.Unix()), timezone, sourceAccount, destinationAccount))
 	assert.Equal(t, false, user.CanEditTransactionByTransactionTime(utils.GetMinTransactionTimeFromUnixTime(destinationAccountLastReconciledTime.Add(1*time.Second).Unix()), timezone, sourceAccount, destinationAccount))
 }
+
+func TestUserSetBudgetAlertTimezone_TimezoneName(t *testing.T) {
+	user := &User{}
+	timezone, err := time.LoadLocation("Asia/Shanghai")
+	assert.Nil(t, err)
+
+	user.SetBudgetAlertTimezone(timezone, time.Now().Unix())
+	assert.Equal(t, "Asia/Shanghai", user.BudgetAlertTimezone)
+	assert.Equal(t, "Asia/Shanghai", user.GetBudgetAlertTimezone().String())
+}
+
+func TestUserSetBudgetAlertTimezone_FixedTimezone(t *testing.T) {
+	user := &User{}
+	timezone := time.FixedZone("Client Fixed Timezone", -(5*60+30)*60)
+	unixTime := time.Now().Unix()
+
+	user.SetBudgetAlertTimezone(timezone, unixTime)
+	assert.Equal(t, "-05:30", user.BudgetAlertTimezone)
+	assert.Equal(t, int16(-(5*60 + 30)), utils.GetTimezoneOffsetMinutes(unixTime, user.GetBudgetAlertTimezone()))
+}
+
+func TestUserGetBudgetAlertTimezone_EmptyOrInvalid(t *testing.T) {
+	user := &User{}
+	assert.Equal(t, time.Local, user.GetBudgetAlertTimezone())
+
+	user.BudgetAlertTimezone = "Invalid/Timezone"
+	assert.Equal(t, time.Local, user.GetBudgetAlertTimezone())
+}
@@ -0,0 +1,267 @@
+package models
+
+import (
+	"strings"
+)
+
+// WebhookEventType represents the type of event which triggers webhook
+type WebhookEventType string
+
+// Webhook event types
+const (
+	WEBHOOK_EVENT_TYPE_TRANSACTION_CREATED     WebhookEventType = "transaction.created"
+	WEBHOOK_EVENT_TYPE_TRANSACTION_MODIFIED    WebhookEventType = "transaction.modified"
+	WEBHOOK_EVENT_TYPE_TRANSACTION_DELETED     WebhookEventType = "transaction.deleted"
+	WEBHOOK_EVENT_TYPE_ACCOUNT_BALANCE_CHANGED WebhookEventType = "account.balance_changed"
+	WEBHOOK_EVENT_TYPE_IMPORT_COMPLETED        WebhookEventType = "import.completed"
+)
+
+// AllWebhookEventTypes represents all the available webhook event types
+var AllWebhookEventTypes = []WebhookEventType{
+	WEBHOOK_EVENT_TYPE_TRANSACTION_CREATED,
+	WEBHOOK_EVENT_TYPE_TRANSACTION_MODIFIED,
+	WEBHOOK_EVENT_TYPE_TRANSACTION_DELETED,
+	WEBHOOK_EVENT_TYPE_ACCOUNT_BALANCE_CHANGED,
+	WEBHOOK_EVENT_TYPE_IMPORT_COMPLETED,
+}
+
+// IsValid returns whether the webhook event type is valid
+func (t WebhookEventType) IsValid() bool {
+	for i := 0; i < len(AllWebhookEventTypes); i++ {
+		if AllWebhookEventTypes[i] == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookDeliveryStatus represents the status of webhook delivery
+type WebhookDeliveryStatus byte
+
+// Webhook delivery statuses
+const (
+	WEBHOOK_DELIVERY_STATUS_PENDING   WebhookDeliveryStatus = 1
+	WEBHOOK_DELIVERY_STATUS_SUCCEEDED WebhookDeliveryStatus = 2
+	WEBHOOK_DELIVERY_STATUS_FAILED    WebhookDeliveryStatus = 3
+)
+
+// Webhook represents webhook subscription data stored in database
+type Webhook struct {
+	WebhookId       int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_webhook_uid_deleted) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_webhook_uid_deleted) NOT NULL"`
+	Name            string `xorm:"VARCHAR(64) NOT NULL"`
+	Url             string `xorm:"VARCHAR(1024) NOT NULL"`
+	Secret          string `xorm:"VARCHAR(64) NOT NULL"`
+	EventTypes      string `xorm:"VARCHAR(255) NOT NULL"`
+	Disabled        bool   `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// WebhookDelivery represents the delivery of webhook event stored in database
+type WebhookDelivery struct {
+	DeliveryId             int64                 `xorm:"PK"`
+	Uid                    int64                 `xorm:"INDEX(IDX_webhook_delivery_uid_webhook_id_created_time) NOT NULL"`
+	WebhookId              int64                 `xorm:"INDEX(IDX_webhook_delivery_uid_webhook_id_created_time) NOT NULL"`
+	EventId                int64                 `xorm:"NOT NULL"`
+	EventType              WebhookEventType      `xorm:"VARCHAR(64) NOT NULL"`
+	Payload                string                `xorm:"BLOB NOT NULL"`
+	Status                 WebhookDeliveryStatus `xorm:"INDEX(IDX_webhook_delivery_status_next_attempt_time) TINYINT NOT NULL"`
+	AttemptCount           int32                 `xorm:"NOT NULL"`
+	NextAttemptUnixTime    int64                 `xorm:"INDEX(IDX_webhook_delivery_status_next_attempt_time) NOT NULL"`
+	LastResponseStatusCode int32
+	LastErrorMessage       string `xorm:"VARCHAR(255)"`
+	CreatedUnixTime        int64  `xorm:"INDEX(IDX_webhook_delivery_uid_webhook_id_created_time)"`
+	UpdatedUnixTime        int64
+}
+
+// WebhookEventPayload represents the request body sent to the webhook url
+type WebhookEventPayload struct {
+	EventId   int64            `json:"eventId,string"`
+	EventType WebhookEventType `json:"eventType"`
+	CreatedAt int64            `json:"createdAt"`
+	Data      any              `json:"data"`
+}
+
+// WebhookTransactionEventData represents the event data of transaction created, modified or deleted event
+type WebhookTransactionEventData struct {
+	Id                   int64           `json:"id,string"`
+	Type                 TransactionType `json:"type"`
+	CategoryId           int64           `json:"categoryId,string"`
+	Time                 int64           `json:"time"`
+	UtcOffset            int16           `json:"utcOffset"`
+	SourceAccountId      int64           `json:"sourceAccountId,string"`
+	DestinationAccountId int64           `json:"destinationAccountId,string,omitempty"`
+	SourceAmount         int64           `json:"sourceAmount"`
+	DestinationAmount    int64           `json:"destinationAmount,omitempty"`
+	Comment              string          `json:"comment"`
+}
+
+// WebhookAccountBalanceChangedEventData represents the event data of account balance changed event
+type WebhookAccountBalanceChangedEventData struct {
+	AccountId int64  `json:"accountId,string"`
+	Currency  string `json:"currency"`
+	Balance   int64  `json:"balance"`
+}
+
+// WebhookImportCompletedEventData represents the event data of transactions import completed event
+type WebhookImportCompletedEventData struct {
+	TransactionCount int `json:"transactionCount"`
+}
+
+// WebhookGetRequest represents all parameters of webhook getting request
+type WebhookGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// WebhookCreateRequest represents all parameters of webhook creation request
+type WebhookCreateRequest struct {
+	Name       string             `json:"name" binding:"required,notBlank,max=64"`
+	Url        string             `json:"url" binding:"required,notBlank,max=1024,url"`
+	EventTypes []WebhookEventType `json:"eventTypes" binding:"required,min=1"`
+}
+
+// WebhookModifyRequest represents all parameters of webhook modification request
+type WebhookModifyRequest struct {
+	Id         int64              `json:"id,string" binding:"required,min=1"`
+	Name       string             `json:"name" binding:"required,notBlank,max=64"`
+	Url        string             `json:"url" binding:"required,notBlank,max=1024,url"`
+	EventTypes []WebhookEventType `json:"eventTypes" binding:"required,min=1"`
+	Disabled   bool               `json:"disabled"`
+}
+
+// WebhookDeleteRequest represents all parameters of webhook deleting request
+type WebhookDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// WebhookDeliveryListRequest represents all parameters of webhook delivery listing request
+type WebhookDeliveryListRequest struct {
+	WebhookId int64 `form:"webhookId,string" binding:"required,min=1"`
+	Page      int32 `form:"page" binding:"required,min=1"`
+	Count     int32 `form:"count" binding:"required,min=1,max=50"`
+}
+
+// WebhookDeliveryReplayRequest represents all parameters of webhook delivery replaying request
+type WebhookDeliveryReplayRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// WebhookInfoResponse represents a view-object of webhook
+type WebhookInfoResponse struct {
+	Id         int64              `json:"id,string"`
+	Name       string             `json:"name"`
+	Url        string             `json:"url"`
+	Secret     string             `json:"secret,omitempty"`
+	EventTypes []WebhookEventType `json:"eventTypes"`
+	Disabled   bool               `json:"disabled"`
+	CreatedAt  int64              `json:"createdAt"`
+}
+
+// WebhookDeliveryInfoResponse represents a view-object of webhook delivery
+type WebhookDeliveryInfoResponse struct {
+	Id                     int64                 `json:"id,string"`
+	WebhookId              int64                 `json:"webhookId,string"`
+	EventId                int64                 `json:"eventId,string"`
+	EventType              WebhookEventType      `json:"eventType"`
+	Payload                string                `json:"payload"`
+	Status                 WebhookDeliveryStatus `json:"status"`
+	AttemptCount           int32                 `json:"attemptCount"`
+	NextAttemptTime        int64                 `json:"nextAttemptTime,omitempty"`
+	LastResponseStatusCode int32                 `json:"lastResponseStatusCode,omitempty"`
+	LastErrorMessage       string                `json:"lastErrorMessage,omitempty"`
+	CreatedAt              int64                 `json:"createdAt"`
+	UpdatedAt              int64                 `json:"updatedAt"`
+}
+
+// GetEventTypes returns all event types subscribed by the webhook
+func (w *Webhook) GetEventTypes() []WebhookEventType {
+	eventTypes := make([]WebhookEventType, 0)
+
+	if w.EventTypes == "" {
+		return eventTypes
+	}
+
+	items := strings.Split(w.EventTypes, ",")
+
+	for i := 0; i < len(items); i++ {
+		eventTypes = append(eventTypes, WebhookEventType(items[i]))
+	}
+
+	return eventTypes
+}
+
+// IsSubscribed returns whether the webhook subscribes the specified event type
+func (w *Webhook) IsSubscribed(eventType WebhookEventType) bool {
+	eventTypes := w.GetEventTypes()
+
+	for i := 0; i < len(eventTypes); i++ {
+		if eventTypes[i] == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToWebhookInfoResponse returns a view-object according to database model
+func (w *Webhook) ToWebhookInfoResponse(withSecret bool) *WebhookInfoResponse {
+	resp := &WebhookInfoResponse{
+		Id:         w.WebhookId,
+		Name:       w.Name,
+		Url:        w.Url,
+		EventTypes: w.GetEventTypes(),
+		Disabled:   w.Disabled,
+		CreatedAt:  w.CreatedUnixTime,
+	}
+
+	if withSecret {
+		resp.Secret = w.Secret
+	}
+
+	return resp
+}
+
+// ToWebhookDeliveryInfoResponse returns a view-object according to database model
+func (d *WebhookDelivery) ToWebhookDeliveryInfoResponse() *WebhookDeliveryInfoResponse {
+	resp := &WebhookDeliveryInfoResponse{
+		Id:                     d.DeliveryId,
+		WebhookId:              d.WebhookId,
+		EventId:                d.EventId,
+		EventType:              d.EventType,
+		Payload:                d.Payload,
+		Status:                 d.Status,
+		AttemptCount:           d.AttemptCount,
+		LastResponseStatusCode: d.LastResponseStatusCode,
+		LastErrorMessage:       d.LastErrorMessage,
+		CreatedAt:              d.CreatedUnixTime,
+		UpdatedAt:              d.UpdatedUnixTime,
+	}
+
+	if d.Status == WEBHOOK_DELIVERY_STATUS_PENDING {
+		resp.NextAttemptTime = d.NextAttemptUnixTime
+	}
+
+	return resp
+}
+
+// WebhookInfoResponseSlice represents the slice data structure of WebhookInfoResponse
+type WebhookInfoResponseSlice []*WebhookInfoResponse
+
+// Len returns the count of items
+func (s WebhookInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s WebhookInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s WebhookInfoResponseSlice) Less(i, j int) bool {
+	return s[i].CreatedAt < s[j].CreatedAt
+}
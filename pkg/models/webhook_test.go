@@ -0,0 +1,74 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookGetEventTypes(t *testing.T) {
+	webhook := &Webhook{
+		EventTypes: "transaction.created,account.balance_changed",
+	}
+
+	assert.Equal(t, []WebhookEventType{WEBHOOK_EVENT_TYPE_TRANSACTION_CREATED, WEBHOOK_EVENT_TYPE_ACCOUNT_BALANCE_CHANGED}, webhook.GetEventTypes())
+
+	webhook.EventTypes = ""
+	assert.Equal(t, []WebhookEventType{}, webhook.GetEventTypes())
+}
+
+func TestWebhookIsSubscribed(t *testing.T) {
+	webhook := &Webhook{
+		EventTypes: "transaction.created,import.completed",
+	}
+
+	assert.True(t, webhook.IsSubscribed(WEBHOOK_EVENT_TYPE_TRANSACTION_CREATED))
+	assert.True(t, webhook.IsSubscribed(WEBHOOK_EVENT_TYPE_IMPORT_COMPLETED))
+	assert.False(t, webhook.IsSubscribed(WEBHOOK_EVENT_TYPE_TRANSACTION_DELETED))
+	assert.False(t, webhook.IsSubscribed(WebhookEventType("transaction")))
+}
+
+func TestWebhookEventTypeIsValid(t *testing.T) {
+	for i := 0; i < len(AllWebhookEventTypes); i++ {
+		assert.True(t, AllWebhookEventTypes[i].IsValid())
+	}
+
+	assert.False(t, WebhookEventType("").IsValid())
+	assert.False(t, WebhookEventType("transaction.unknown").IsValid())
+}
+
+func TestWebhookToWebhookInfoResponse(t *testing.T) {
+	webhook := &Webhook{
+		WebhookId:  1,
+		Name:       "Test",
+		Url:        "https://example.com/hook",
+		Secret:     "secret",
+		EventTypes: "transaction.created",
+	}
+
+	assert.Equal(t, "secret", webhook.ToWebhookInfoResponse(true).Secret)
+	assert.Equal(t, "", webhook.ToWebhookInfoResponse(false).Secret)
+}
+
+func TestWebhookInfoResponseSliceLess(t *testing.T) {
+	var webhookRespSlice WebhookInfoResponseSlice
+	webhookRespSlice = append(webhookRespSlice, &WebhookInfoResponse{
+		Id:        1,
+		CreatedAt: 3,
+	})
+	webhookRespSlice = append(webhookRespSlice, &WebhookInfoResponse{
+		Id:        2,
+		CreatedAt: 1,
+	})
+	webhookRespSlice = append(webhookRespSlice, &WebhookInfoResponse{
+		Id:        3,
+		CreatedAt: 2,
+	})
+
+	sort.Sort(webhookRespSlice)
+
+	assert.Equal(t, int64(2), webhookRespSlice[0].Id)
+	assert.Equal(t, int64(3), webhookRespSlice[1].Id)
+	assert.Equal(t, int64(1), webhookRespSlice[2].Id)
+}
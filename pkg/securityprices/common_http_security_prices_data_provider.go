@@ -0,0 +1,100 @@
+package securityprices
+
+import (
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/httpclient"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// HttpSecurityPricesDataSource defines the structure of http security prices data source
+type HttpSecurityPricesDataSource interface {
+	// BuildRequests returns the http requests
+	BuildRequests(symbols []string) ([]*http.Request, error)
+
+	// Parse returns the common response entity according to the data source raw response
+	Parse(c core.Context, content []byte) (*models.LatestSecurityPriceResponse, error)
+}
+
+// CommonHttpSecurityPricesDataProvider defines the structure of common http security prices data provider
+type CommonHttpSecurityPricesDataProvider struct {
+	SecurityPricesDataProvider
+	dataSource HttpSecurityPricesDataSource
+	httpClient *http.Client
+}
+
+func (e *CommonHttpSecurityPricesDataProvider) GetLatestSecurityPrices(c core.Context, uid int64, symbols []string, currentConfig *settings.Config) (*models.LatestSecurityPriceResponse, error) {
+	requests, err := e.dataSource.BuildRequests(symbols)
+
+	if err != nil {
+		log.Errorf(c, "[common_http_security_prices_data_provider.GetLatestSecurityPrices] failed to build requests for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	securityPriceResps := make([]*models.LatestSecurityPriceResponse, 0, len(requests))
+
+	for i := 0; i < len(requests); i++ {
+		req := requests[i]
+		req = req.WithContext(httpclient.CustomHttpResponseLog(c, func(data []byte) {
+			log.Debugf(c, "[common_http_security_prices_data_provider.GetLatestSecurityPrices] response#%d is %s", i, data)
+		}))
+
+		resp, err := e.httpClient.Do(req)
+
+		if err != nil {
+			log.Errorf(c, "[common_http_security_prices_data_provider.GetLatestSecurityPrices] failed to request latest security price data for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+
+		if resp.StatusCode != 200 {
+			log.Errorf(c, "[common_http_security_prices_data_provider.GetLatestSecurityPrices] failed to get latest security price data response for user \"uid:%d\", because response code is %d", uid, resp.StatusCode)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		securityPriceResp, err := e.dataSource.Parse(c, body)
+
+		if err != nil {
+			log.Errorf(c, "[common_http_security_prices_data_provider.GetLatestSecurityPrices] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
+		}
+
+		securityPriceResps = append(securityPriceResps, securityPriceResp)
+	}
+
+	if len(securityPriceResps) < 1 {
+		return nil, errs.ErrSecurityPricesDataSourceNoAvailablePrices
+	}
+
+	lastSecurityPriceResponse := securityPriceResps[len(securityPriceResps)-1]
+	allSecurityPrices := make(models.LatestSecurityPriceSlice, 0)
+
+	for i := 0; i < len(securityPriceResps); i++ {
+		allSecurityPrices = append(allSecurityPrices, securityPriceResps[i].Prices...)
+	}
+
+	sort.Sort(allSecurityPrices)
+
+	finalSecurityPriceResponse := &models.LatestSecurityPriceResponse{
+		DataSource:   lastSecurityPriceResponse.DataSource,
+		ReferenceUrl: lastSecurityPriceResponse.ReferenceUrl,
+		Prices:       allSecurityPrices,
+	}
+
+	return finalSecurityPriceResponse, nil
+}
+
+func newCommonHttpSecurityPricesDataProvider(config *settings.Config, dataSource HttpSecurityPricesDataSource) *CommonHttpSecurityPricesDataProvider {
+	return &CommonHttpSecurityPricesDataProvider{
+		dataSource: dataSource,
+		httpClient: httpclient.NewHttpClient(config.SecurityPricesRequestTimeout, config.SecurityPricesProxy, config.SecurityPricesSkipTLSVerify, core.GetOutgoingUserAgent(), config.EnableDebugLog),
+	}
+}
@@ -0,0 +1,13 @@
+package securityprices
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// SecurityPricesDataProvider defines the structure of security prices data provider
+type SecurityPricesDataProvider interface {
+	// GetLatestSecurityPrices returns the common response entities
+	GetLatestSecurityPrices(c core.Context, uid int64, symbols []string, currentConfig *settings.Config) (*models.LatestSecurityPriceResponse, error)
+}
@@ -0,0 +1,45 @@
+package securityprices
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// SecurityPricesDataProviderContainer contains the current security prices data provider
+type SecurityPricesDataProviderContainer struct {
+	current SecurityPricesDataProvider
+}
+
+// Initialize a security prices data provider container singleton instance
+var (
+	Container = &SecurityPricesDataProviderContainer{}
+)
+
+// InitializeSecurityPricesDataSource initializes the current security prices data source according to the config
+func InitializeSecurityPricesDataSource(config *settings.Config) error {
+	if config.SecurityPricesDataSource == "" {
+		Container.current = nil
+		return nil
+	} else if config.SecurityPricesDataSource == settings.StooqSecurityPricesDataSource {
+		Container.current = newCommonHttpSecurityPricesDataProvider(config, &StooqDataSource{})
+		return nil
+	}
+
+	return errs.ErrInvalidSecurityPricesDataSource
+}
+
+// IsEnabled returns whether the security prices data source is enabled
+func (e *SecurityPricesDataProviderContainer) IsEnabled() bool {
+	return e.current != nil
+}
+
+// GetLatestSecurityPrices returns the latest security prices data from the current security prices data source
+func (e *SecurityPricesDataProviderContainer) GetLatestSecurityPrices(c core.Context, uid int64, symbols []string, currentConfig *settings.Config) (*models.LatestSecurityPriceResponse, error) {
+	if e.current == nil {
+		return nil, errs.ErrSecurityPricesDataSourceNotEnabled
+	}
+
+	return e.current.GetLatestSecurityPrices(c, uid, symbols, currentConfig)
+}
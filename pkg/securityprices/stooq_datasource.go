@@ -0,0 +1,135 @@
+package securityprices
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const stooqSecurityPriceUrl = "https://stooq.com/q/l/?f=sd2t2c&h&e=csv&s="
+const stooqSecurityPriceReferenceUrl = "https://stooq.com/"
+const stooqDataSource = "Stooq"
+
+const stooqMaxSymbolCountPerRequest = 20
+const stooqNoDataValue = "N/D"
+
+const stooqUpdateDateTimeFormat = "2006-01-02 15:04:05"
+const stooqUpdateDateTimezone = "Europe/Warsaw"
+
+// StooqDataSource defines the structure of security prices data source of Stooq
+type StooqDataSource struct {
+	HttpSecurityPricesDataSource
+}
+
+// BuildRequests returns the Stooq security prices http requests
+func (e *StooqDataSource) BuildRequests(symbols []string) ([]*http.Request, error) {
+	requests := make([]*http.Request, 0, len(symbols)/stooqMaxSymbolCountPerRequest+1)
+
+	for i := 0; i < len(symbols); i += stooqMaxSymbolCountPerRequest {
+		end := i + stooqMaxSymbolCountPerRequest
+
+		if end > len(symbols) {
+			end = len(symbols)
+		}
+
+		escapedSymbols := make([]string, 0, end-i)
+
+		for j := i; j < end; j++ {
+			escapedSymbols = append(escapedSymbols, url.QueryEscape(strings.ToLower(symbols[j])))
+		}
+
+		req, err := http.NewRequest("GET", stooqSecurityPriceUrl+strings.Join(escapedSymbols, "+"), nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, req)
+	}
+
+	return requests, nil
+}
+
+// Parse returns the common response entity according to the Stooq data source raw response
+func (e *StooqDataSource) Parse(c core.Context, content []byte) (*models.LatestSecurityPriceResponse, error) {
+	csvReader := csv.NewReader(bytes.NewReader(content))
+	csvReader.FieldsPerRecord = -1
+
+	allLines, err := csvReader.ReadAll()
+
+	if err != nil {
+		log.Errorf(c, "[stooq_datasource.Parse] failed to parse csv data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	if len(allLines) < 1 || len(allLines[0]) < 4 || allLines[0][0] != "Symbol" {
+		log.Errorf(c, "[stooq_datasource.Parse] csv header is invalid, content is %s", string(content))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	timezone, err := time.LoadLocation(stooqUpdateDateTimezone)
+
+	if err != nil {
+		log.Errorf(c, "[stooq_datasource.Parse] failed to get timezone, timezone name is %s", stooqUpdateDateTimezone)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	prices := make(models.LatestSecurityPriceSlice, 0, len(allLines)-1)
+
+	for i := 1; i < len(allLines); i++ {
+		items := allLines[i]
+
+		if len(items) < 4 {
+			continue
+		}
+
+		symbol := items[0]
+		date := items[1]
+		timeOfDay := items[2]
+		closePrice := items[3]
+
+		if date == stooqNoDataValue || timeOfDay == stooqNoDataValue || closePrice == stooqNoDataValue {
+			log.Warnf(c, "[stooq_datasource.Parse] no data for symbol \"%s\"", symbol)
+			continue
+		}
+
+		updateTime, err := time.ParseInLocation(stooqUpdateDateTimeFormat, date+" "+timeOfDay, timezone)
+
+		if err != nil {
+			log.Warnf(c, "[stooq_datasource.Parse] failed to parse update time for symbol \"%s\", datetime is %s %s", symbol, date, timeOfDay)
+			continue
+		}
+
+		price, err := utils.StringToFloat64(closePrice)
+
+		if err != nil || price <= 0 || math.IsInf(price, 0) {
+			log.Warnf(c, "[stooq_datasource.Parse] price is invalid for symbol \"%s\", price is %s", symbol, closePrice)
+			continue
+		}
+
+		prices = append(prices, &models.LatestSecurityPrice{
+			Symbol:     symbol,
+			Price:      fmt.Sprintf("%.2f", price),
+			UpdateTime: updateTime.Unix(),
+		})
+	}
+
+	latestSecurityPriceResponse := &models.LatestSecurityPriceResponse{
+		DataSource:   stooqDataSource,
+		ReferenceUrl: stooqSecurityPriceReferenceUrl,
+		Prices:       prices,
+	}
+
+	return latestSecurityPriceResponse, nil
+}
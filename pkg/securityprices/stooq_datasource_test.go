@@ -0,0 +1,88 @@
+package securityprices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const stooqMinimumRequiredContent = "Symbol,Date,Time,Close\r\n" +
+	"AAPL.US,2024-11-15,22:00:05,225.0\r\n" +
+	"MSFT.US,2024-11-15,22:00:09,415.005\r\n"
+
+func TestStooqDataSource_BuildRequests(t *testing.T) {
+	dataSource := &StooqDataSource{}
+
+	symbols := make([]string, 0, 21)
+
+	for i := 0; i < 21; i++ {
+		symbols = append(symbols, "AAPL.US")
+	}
+
+	requests, err := dataSource.BuildRequests(symbols)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "aapl.us", requests[1].URL.Query().Get("s"))
+}
+
+func TestStooqDataSource_StandardDataExtractPrices(t *testing.T) {
+	dataSource := &StooqDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestSecurityPriceResponse, err := dataSource.Parse(context, []byte(stooqMinimumRequiredContent))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "Stooq", actualLatestSecurityPriceResponse.DataSource)
+	assert.Contains(t, actualLatestSecurityPriceResponse.Prices, &models.LatestSecurityPrice{
+		Symbol:     "AAPL.US",
+		Price:      "225.00",
+		UpdateTime: 1731704405,
+	})
+	assert.Contains(t, actualLatestSecurityPriceResponse.Prices, &models.LatestSecurityPrice{
+		Symbol:     "MSFT.US",
+		Price:      "415.00",
+		UpdateTime: 1731704409,
+	})
+}
+
+func TestStooqDataSource_NoDataSymbol(t *testing.T) {
+	dataSource := &StooqDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestSecurityPriceResponse, err := dataSource.Parse(context, []byte("Symbol,Date,Time,Close\n"+
+		"AAPL.US,2024-11-15,22:00:05,225.0\n"+
+		"UNKNOWN.US,N/D,N/D,N/D\n"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(actualLatestSecurityPriceResponse.Prices))
+	assert.Equal(t, "AAPL.US", actualLatestSecurityPriceResponse.Prices[0].Symbol)
+}
+
+func TestStooqDataSource_InvalidPrice(t *testing.T) {
+	dataSource := &StooqDataSource{}
+	context := core.NewNullContext()
+
+	actualLatestSecurityPriceResponse, err := dataSource.Parse(context, []byte("Symbol,Date,Time,Close\n"+
+		"AAPL.US,2024-11-15,22:00:05,null\n"+
+		"MSFT.US,2024-11-15,22:00:09,-1\n"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(actualLatestSecurityPriceResponse.Prices))
+}
+
+func TestStooqDataSource_BlankContent(t *testing.T) {
+	dataSource := &StooqDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte(""))
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+}
+
+func TestStooqDataSource_InvalidHeader(t *testing.T) {
+	dataSource := &StooqDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.Parse(context, []byte("<html></html>"))
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+}
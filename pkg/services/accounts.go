@@ -299,7 +299,7 @@ func (s *AccountService) CreateAccounts(c core.Context, mainAccount *models.Acco
 
 	userDataDb := s.UserDataDB(mainAccount.Uid)
 
-	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
+	err := userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(allAccounts); i++ {
 			account := allAccounts[i]
 			_, err := sess.Insert(account)
@@ -366,6 +366,22 @@ func (s *AccountService) CreateAccounts(c core.Context, mainAccount *models.Acco
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(allInitTransactions) > 0 {
+		balanceChangedAccountIds := make([]int64, len(allInitTransactions))
+
+		for i := 0; i < len(allInitTransactions); i++ {
+			balanceChangedAccountIds[i] = allInitTransactions[i].AccountId
+		}
+
+		webhookEvents.publishAccountBalanceChangedEvents(c, mainAccount.Uid, balanceChangedAccountIds)
+	}
+
+	return nil
 }
 
 // ModifyAccounts saves an existed account model to database
@@ -441,7 +457,7 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 
 	userDataDb := s.UserDataDB(mainAccount.Uid)
 
-	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
+	err := userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
 		// update accounts
 		for i := 0; i < len(updateAccounts); i++ {
 			account := updateAccounts[i]
@@ -590,6 +606,22 @@ func (s *AccountService) ModifyAccounts(c core.Context, mainAccount *models.Acco
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(addInitTransactions) > 0 {
+		balanceChangedAccountIds := make([]int64, len(addInitTransactions))
+
+		for i := 0; i < len(addInitTransactions); i++ {
+			balanceChangedAccountIds[i] = addInitTransactions[i].AccountId
+		}
+
+		webhookEvents.publishAccountBalanceChangedEvents(c, mainAccount.Uid, balanceChangedAccountIds)
+	}
+
+	return nil
 }
 
 // UpdateAccountExtend updates extend field of given account
@@ -613,6 +645,111 @@ func (s *AccountService) UpdateAccountExtend(c core.Context, uid int64, account
 	})
 }
 
+// SetAccountLoan saves the loan definition of given debt account and replaces its scheduled payment template
+func (s *AccountService) SetAccountLoan(c core.Context, uid int64, account *models.Account, loan *models.AccountLoanInfo, paymentTemplate *models.TransactionTemplate) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	paymentTemplate.TemplateId = s.GenerateUuid(uuid.UUID_TYPE_TEMPLATE)
+
+	if paymentTemplate.TemplateId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	now := time.Now().Unix()
+
+	paymentTemplate.Uid = uid
+	paymentTemplate.Deleted = false
+	paymentTemplate.CreatedUnixTime = now
+	paymentTemplate.UpdatedUnixTime = now
+
+	var oldPaymentTemplateId int64
+
+	if account.Extend == nil {
+		account.Extend = &models.AccountExtend{}
+	} else if account.Extend.Loan != nil {
+		oldPaymentTemplateId = account.Extend.Loan.PaymentTemplateId
+	}
+
+	loan.PaymentTemplateId = paymentTemplate.TemplateId
+	account.Extend.Loan = loan
+	account.UpdatedUnixTime = now
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		if oldPaymentTemplateId > 0 {
+			templateUpdateModel := &models.TransactionTemplate{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			_, err := sess.ID(oldPaymentTemplateId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(templateUpdateModel)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := sess.Insert(paymentTemplate)
+
+		if err != nil {
+			return err
+		}
+
+		updatedRows, err := sess.ID(account.AccountId).Cols("extend", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(account)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrAccountNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteAccountLoan removes the loan definition of given debt account and deletes its scheduled payment template
+func (s *AccountService) DeleteAccountLoan(c core.Context, uid int64, account *models.Account) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if account.Extend == nil || account.Extend.Loan == nil {
+		return errs.ErrLoanNotFound
+	}
+
+	now := time.Now().Unix()
+	paymentTemplateId := account.Extend.Loan.PaymentTemplateId
+
+	account.Extend.Loan = nil
+	account.UpdatedUnixTime = now
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		if paymentTemplateId > 0 {
+			templateUpdateModel := &models.TransactionTemplate{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			_, err := sess.ID(paymentTemplateId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(templateUpdateModel)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		updatedRows, err := sess.ID(account.AccountId).Cols("extend", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(account)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrAccountNotFound
+		}
+
+		return nil
+	})
+}
+
 // HideAccount updates hidden field of given accounts
 func (s *AccountService) HideAccount(c core.Context, uid int64, ids []int64, hidden bool) error {
 	if uid <= 0 {
@@ -749,6 +886,14 @@ func (s *AccountService) DeleteAccount(c core.Context, uid int64, accountId int6
 			return errs.ErrAccountInUseCannotBeDeleted
 		}
 
+		exists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Limit(1).Exist(&models.InvestmentSecurity{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrAccountInUseCannotBeDeleted
+		}
+
 		deletedRows, err := sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Update(updateModel)
 
 		if err != nil {
@@ -838,6 +983,14 @@ func (s *AccountService) DeleteSubAccount(c core.Context, uid int64, accountId i
 			return errs.ErrSubAccountInUseCannotBeDeleted
 		}
 
+		exists, err = sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).Limit(1).Exist(&models.InvestmentSecurity{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrSubAccountInUseCannotBeDeleted
+		}
+
 		deletedRows, err := sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).Update(updateModel)
 
 		if err != nil {
@@ -99,6 +99,11 @@ func (s *ServiceUsingStorage) ExistsAvatar(ctx core.Context, uid int64, fileExte
 	return s.container.ExistsAvatar(ctx, s.getUserAvatarPath(uid, fileExtension))
 }
 
+// GetAvatarSize returns the size of the user avatar in bytes from the current avatar object storage
+func (s *ServiceUsingStorage) GetAvatarSize(ctx core.Context, uid int64, fileExtension string) (int64, error) {
+	return s.container.GetAvatarSize(ctx, s.getUserAvatarPath(uid, fileExtension))
+}
+
 // ReadAvatar returns the user avatar from the current avatar object storage
 func (s *ServiceUsingStorage) ReadAvatar(ctx core.Context, uid int64, fileExtension string) (storage.ObjectInStorage, error) {
 	return s.container.ReadAvatar(ctx, s.getUserAvatarPath(uid, fileExtension))
@@ -119,6 +124,11 @@ func (s *ServiceUsingStorage) ExistsUserCustomIcon(ctx core.Context, uid int64,
 	return s.container.ExistsUserCustomIcon(ctx, s.getUserCustomIconPath(uid, iconId))
 }
 
+// GetUserCustomIconSize returns the size of the user custom icon in bytes from the current user custom icon object storage
+func (s *ServiceUsingStorage) GetUserCustomIconSize(ctx core.Context, uid int64, iconId int64) (int64, error) {
+	return s.container.GetUserCustomIconSize(ctx, s.getUserCustomIconPath(uid, iconId))
+}
+
 // ReadUserCustomIcon returns the user custom icon from the current user custom icon object storage
 func (s *ServiceUsingStorage) ReadUserCustomIcon(ctx core.Context, uid int64, iconId int64) (storage.ObjectInStorage, error) {
 	return s.container.ReadUserCustomIcon(ctx, s.getUserCustomIconPath(uid, iconId))
@@ -139,6 +149,11 @@ func (s *ServiceUsingStorage) ExistsTransactionPicture(ctx core.Context, uid int
 	return s.container.ExistsTransactionPicture(ctx, s.getTransactionPicturePath(uid, pictureId, fileExtension))
 }
 
+// GetTransactionPictureSize returns the size of the transaction picture in bytes from the current transaction picture object storage
+func (s *ServiceUsingStorage) GetTransactionPictureSize(ctx core.Context, uid int64, pictureId int64, fileExtension string) (int64, error) {
+	return s.container.GetTransactionPictureSize(ctx, s.getTransactionPicturePath(uid, pictureId, fileExtension))
+}
+
 // ReadTransactionPicture returns the transaction picture from the current transaction picture object storage
 func (s *ServiceUsingStorage) ReadTransactionPicture(ctx core.Context, uid int64, pictureId int64, fileExtension string) (storage.ObjectInStorage, error) {
 	return s.container.ReadTransactionPicture(ctx, s.getTransactionPicturePath(uid, pictureId, fileExtension))
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/locales"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mail"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/templates"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// BudgetAlertService represents budget alert service
+type BudgetAlertService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingMailer
+	budgets  *BudgetService
+	accounts *AccountService
+}
+
+// Initialize a budget alert service singleton instance
+var (
+	BudgetAlerts = &BudgetAlertService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingMailer: ServiceUsingMailer{
+			container: mail.Container,
+		},
+		budgets:  Budgets,
+		accounts: Accounts,
+	}
+)
+
+// SendBudgetAlerts checks the spending of visible budgets of all users who enable budget alerts, and sends alert emails when the spending crosses new thresholds in current period
+func (s *BudgetAlertService) SendBudgetAlerts(c core.Context, currentUnixTime int64) error {
+	if !s.CurrentConfig().EnableSMTP {
+		return errs.ErrSMTPServerNotEnabled
+	}
+
+	var users []*models.User
+	err := s.UserDB().NewSession(c).Where("deleted=? AND disabled=? AND budget_alert_thresholds<>?", false, false, "").Find(&users)
+
+	if err != nil {
+		return err
+	}
+
+	if len(users) < 1 {
+		return nil
+	}
+
+	log.Infof(c, "[budget_alerts.SendBudgetAlerts] should check budgets of %d users now", len(users))
+
+	sentCount := 0
+	skipCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(users); i++ {
+		user := users[i]
+		sent, err := s.sendUserBudgetAlerts(c, user, currentUnixTime)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[budget_alerts.SendBudgetAlerts] failed to send budget alerts for user \"uid:%d\", because %s", user.Uid, err.Error())
+		} else if sent {
+			sentCount++
+			log.Infof(c, "[budget_alerts.SendBudgetAlerts] budget alerts have been sent to user \"uid:%d\"", user.Uid)
+		} else {
+			skipCount++
+		}
+	}
+
+	log.Infof(c, "[budget_alerts.SendBudgetAlerts] %d users have been sent budget alerts, %d users skipped, %d users failed", sentCount, skipCount, failedCount)
+
+	return nil
+}
+
+func (s *BudgetAlertService) sendUserBudgetAlerts(c core.Context, user *models.User, currentUnixTime int64) (bool, error) {
+	thresholds, err := models.ParseBudgetAlertThresholds(user.BudgetAlertThresholds)
+
+	if err != nil {
+		return false, err
+	}
+
+	if len(thresholds) < 1 {
+		return false, nil
+	}
+
+	uid := user.Uid
+	allBudgets, err := s.budgets.GetAllBudgetsByUid(c, uid)
+
+	if err != nil {
+		return false, err
+	}
+
+	budgets := make([]*models.Budget, 0, len(allBudgets))
+
+	for i := 0; i < len(allBudgets); i++ {
+		if !allBudgets[i].Hidden {
+			budgets = append(budgets, allBudgets[i])
+		}
+	}
+
+	if len(budgets) < 1 {
+		return false, nil
+	}
+
+	accounts, err := s.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		return false, err
+	}
+
+	accountMap := s.accounts.GetAccountMapByList(accounts)
+	timezone := user.GetBudgetAlertTimezone()
+	totalAmountsCache := make(map[[2]int64][]*models.TransactionTotalAmount)
+
+	alertItems := make([]map[string]any, 0, len(budgets))
+	alertedBudgets := make([]*models.Budget, 0, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budget := budgets[i]
+		progress, err := s.budgets.GetBudgetProgress(c, uid, budget, accountMap, currentUnixTime, timezone, user.FirstDayOfWeek, user.FiscalYearStart, totalAmountsCache)
+
+		if err != nil {
+			return false, err
+		}
+
+		periodStartTime := progress.PeriodStartTime
+		spentAmount := progress.SpentAmount
+		availableAmount := budget.LimitAmount + progress.RolloverAmount
+
+		if spentAmount <= 0 || availableAmount <= 0 {
+			continue
+		}
+
+		usedPercentage := spentAmount * 100 / availableAmount
+		crossedThreshold := uint32(0)
+
+		for j := 0; j < len(thresholds); j++ {
+			if usedPercentage >= int64(thresholds[j]) {
+				crossedThreshold = thresholds[j]
+			}
+		}
+
+		if crossedThreshold < 1 {
+			continue
+		}
+
+		if budget.LastAlertPeriodStartTime == periodStartTime && budget.LastAlertThreshold >= crossedThreshold {
+			continue
+		}
+
+		budget.LastAlertPeriodStartTime = periodStartTime
+		budget.LastAlertThreshold = crossedThreshold
+		alertedBudgets = append(alertedBudgets, budget)
+		alertItems = append(alertItems, map[string]any{
+			"Name":            budget.Name,
+			"Currency":        budget.Currency,
+			"SpentAmount":     utils.FormatAmount(spentAmount),
+			"AvailableAmount": utils.FormatAmount(availableAmount),
+			"UsedPercentage":  usedPercentage,
+		})
+	}
+
+	if len(alertItems) < 1 {
+		return false, nil
+	}
+
+	// the last alert info must be saved before sending email, otherwise the same alert would be sent again if saving failed
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(alertedBudgets); i++ {
+			budget := alertedBudgets[i]
+			_, err := sess.ID(budget.BudgetId).Cols("last_alert_period_start_time", "last_alert_threshold").Where("uid=? AND deleted=?", uid, false).Update(budget)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	err = s.sendBudgetAlertEmail(user, alertItems)
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *BudgetAlertService) sendBudgetAlertEmail(user *models.User, alertItems []map[string]any) error {
+	localeTextItems := locales.GetLocaleTextItems(user.Language)
+	budgetAlertTextItems := localeTextItems.BudgetAlertMailTextItems
+
+	tmpl, err := templates.GetTemplate(templates.TEMPLATE_BUDGET_ALERT)
+
+	if err != nil {
+		return err
+	}
+
+	templateParams := map[string]any{
+		"AppName": localeTextItems.GlobalTextItems.AppName,
+		"BudgetAlertMail": map[string]any{
+			"Title":                 budgetAlertTextItems.Title,
+			"Salutation":            fmt.Sprintf(budgetAlertTextItems.SalutationFormat, user.Nickname),
+			"DescriptionAboveTable": budgetAlertTextItems.DescriptionAboveTable,
+			"BudgetName":            budgetAlertTextItems.BudgetName,
+			"SpentAmount":           budgetAlertTextItems.SpentAmount,
+			"AvailableAmount":       budgetAlertTextItems.AvailableAmount,
+			"UsedPercentage":        budgetAlertTextItems.UsedPercentage,
+			"DescriptionBelowTable": budgetAlertTextItems.DescriptionBelowTable,
+			"Items":                 alertItems,
+		},
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      user.Email,
+		Subject: budgetAlertTextItems.Title,
+		Body:    bodyBuffer.String(),
+	}
+
+	return s.SendMail(message)
+}
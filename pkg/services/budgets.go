@@ -0,0 +1,367 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// BudgetService represents budget service
+type BudgetService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	accounts     *AccountService
+	categories   *TransactionCategoryService
+	transactions *TransactionService
+}
+
+// Initialize a budget service singleton instance
+var (
+	Budgets = &BudgetService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		accounts:     Accounts,
+		categories:   TransactionCategories,
+		transactions: Transactions,
+	}
+)
+
+// GetTotalBudgetCountByUid returns total budget count of user
+func (s *BudgetService) GetTotalBudgetCountByUid(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.Budget{})
+
+	return count, err
+}
+
+// GetAllBudgetsByUid returns all budget models of user
+func (s *BudgetService) GetAllBudgetsByUid(c core.Context, uid int64) ([]*models.Budget, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var budgets []*models.Budget
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&budgets)
+
+	return budgets, err
+}
+
+// GetBudgetByBudgetId returns a budget model according to budget id
+func (s *BudgetService) GetBudgetByBudgetId(c core.Context, uid int64, budgetId int64) (*models.Budget, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if budgetId <= 0 {
+		return nil, errs.ErrBudgetIdInvalid
+	}
+
+	budget := &models.Budget{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(budgetId).Where("uid=? AND deleted=?", uid, false).Get(budget)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrBudgetNotFound
+	}
+
+	return budget, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *BudgetService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	budget := &models.Budget{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(budget)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return budget.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateBudget saves a new budget model to database
+func (s *BudgetService) CreateBudget(c core.Context, budget *models.Budget) error {
+	if budget.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	budget.BudgetId = s.GenerateUuid(uuid.UUID_TYPE_BUDGET)
+
+	if budget.BudgetId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	budget.Deleted = false
+	budget.CreatedUnixTime = time.Now().Unix()
+	budget.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(budget.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(budget)
+		return err
+	})
+}
+
+// ModifyBudget saves an existed budget model to database
+func (s *BudgetService) ModifyBudget(c core.Context, budget *models.Budget) error {
+	if budget.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	budget.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(budget.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(budget.BudgetId).Cols("name", "period_type", "target_type", "target_id", "currency", "limit_amount", "rollover", "start_time", "comment", "hidden", "updated_unix_time").Where("uid=? AND deleted=?", budget.Uid, false).Update(budget)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrBudgetNotFound
+		}
+
+		return err
+	})
+}
+
+// HideBudget updates hidden field of given budget ids
+func (s *BudgetService) HideBudget(c core.Context, uid int64, ids []int64, hidden bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Budget{
+		Hidden:          hidden,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("budget_id", ids).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrBudgetNotFound
+		}
+
+		return err
+	})
+}
+
+// ModifyBudgetDisplayOrders updates display order of given budgets
+func (s *BudgetService) ModifyBudgetDisplayOrders(c core.Context, uid int64, budgets []*models.Budget) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	for i := 0; i < len(budgets); i++ {
+		budgets[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(budgets); i++ {
+			budget := budgets[i]
+			updatedRows, err := sess.ID(budget.BudgetId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(budget)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrBudgetNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteBudget deletes an existed budget from database
+func (s *BudgetService) DeleteBudget(c core.Context, uid int64, budgetId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Budget{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(budgetId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrBudgetNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllBudgets deletes all existed budgets from database
+func (s *BudgetService) DeleteAllBudgets(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Budget{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetBudgetTargetIds returns the ids of the target and its sub-targets of budget
+func (s *BudgetService) GetBudgetTargetIds(c core.Context, uid int64, budget *models.Budget) (map[int64]bool, error) {
+	var ids []int64
+	var err error
+
+	if budget.TargetType == models.BUDGET_TARGET_TYPE_CATEGORY {
+		ids, err = s.categories.GetCategoryOrSubCategoryIds(c, utils.Int64ToString(budget.TargetId), uid)
+	} else {
+		ids, err = s.accounts.GetAccountOrSubAccountIds(c, utils.Int64ToString(budget.TargetId), uid)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	targetIds := make(map[int64]bool, len(ids))
+
+	for i := 0; i < len(ids); i++ {
+		targetIds[ids[i]] = true
+	}
+
+	return targetIds, nil
+}
+
+// GetBudgetProgress returns the progress of budget in the period which contains the specified time, the transaction total amounts of each time range are saved in the cache so that they can be reused when calculating other budgets of the same user
+func (s *BudgetService) GetBudgetProgress(c core.Context, uid int64, budget *models.Budget, accountMap map[int64]*models.Account, currentUnixTime int64, timezone *time.Location, firstDayOfWeek core.WeekDay, fiscalYearStart core.FiscalYearStart, totalAmountsCache map[[2]int64][]*models.TransactionTotalAmount) (*models.BudgetProgress, error) {
+	targetIds, err := s.GetBudgetTargetIds(c, uid, budget)
+
+	if err != nil {
+		return nil, err
+	}
+
+	getTotalAmounts := func(startTime int64, endTime int64) ([]*models.TransactionTotalAmount, error) {
+		cacheKey := [2]int64{startTime, endTime}
+
+		if totalAmounts, exists := totalAmountsCache[cacheKey]; exists {
+			return totalAmounts, nil
+		}
+
+		totalAmounts, err := s.transactions.GetAccountsAndCategoriesTotalInflowAndOutflow(c, uid, startTime, endTime, nil, false, "", core.MATCH_MODE_DEFAULT, timezone, false, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		totalAmountsCache[cacheKey] = totalAmounts
+		return totalAmounts, nil
+	}
+
+	periodStartTime, periodEndTime := budget.GetPeriodTimeRange(currentUnixTime, timezone, firstDayOfWeek, fiscalYearStart)
+	rolloverPeriods := budget.GetRolloverPeriodTimeRanges(periodStartTime, timezone, firstDayOfWeek, fiscalYearStart)
+	rolloverAmount := int64(0)
+
+	for i := 0; i < len(rolloverPeriods); i++ {
+		totalAmounts, err := getTotalAmounts(rolloverPeriods[i][0], rolloverPeriods[i][1])
+
+		if err != nil {
+			return nil, err
+		}
+
+		previousSpentAmount, _ := s.GetBudgetSpentAmounts(budget, totalAmounts, targetIds, accountMap)
+		rolloverAmount = rolloverAmount + budget.LimitAmount - previousSpentAmount
+
+		if rolloverAmount < 0 {
+			rolloverAmount = 0
+		}
+	}
+
+	totalAmounts, err := getTotalAmounts(periodStartTime, periodEndTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	spentAmount, otherCurrencySpentAmounts := s.GetBudgetSpentAmounts(budget, totalAmounts, targetIds, accountMap)
+
+	return &models.BudgetProgress{
+		PeriodStartTime:           periodStartTime,
+		PeriodEndTime:             periodEndTime,
+		RolloverAmount:            rolloverAmount,
+		SpentAmount:               spentAmount,
+		OtherCurrencySpentAmounts: otherCurrencySpentAmounts,
+	}, nil
+}
+
+// GetBudgetSpentAmounts returns the spent amount in budget currency and the spent amounts in other currencies according to the transaction total amounts
+func (s *BudgetService) GetBudgetSpentAmounts(budget *models.Budget, totalAmounts []*models.TransactionTotalAmount, targetIds map[int64]bool, accountMap map[int64]*models.Account) (int64, map[string]int64) {
+	spentAmount := int64(0)
+	otherCurrencySpentAmounts := make(map[string]int64)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmount := totalAmounts[i]
+
+		if totalAmount.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			continue
+		}
+
+		if budget.TargetType == models.BUDGET_TARGET_TYPE_CATEGORY && !targetIds[totalAmount.CategoryId] {
+			continue
+		} else if budget.TargetType == models.BUDGET_TARGET_TYPE_ACCOUNT && !targetIds[totalAmount.AccountId] {
+			continue
+		}
+
+		account, exists := accountMap[totalAmount.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		if account.Currency == budget.Currency {
+			spentAmount += totalAmount.Amount.Int64()
+		} else {
+			otherCurrencySpentAmounts[account.Currency] += totalAmount.Amount.Int64()
+		}
+	}
+
+	return spentAmount, otherCurrencySpentAmounts
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// ExchangeRateHistoryService represents exchange rate history service
+type ExchangeRateHistoryService struct {
+	ServiceUsingDB
+}
+
+// Initialize a exchange rate history service singleton instance
+var (
+	ExchangeRateHistories = &ExchangeRateHistoryService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetExchangeRatesByDate returns the exchange rate history models of the latest snapshot on or before specified date
+func (s *ExchangeRateHistoryService) GetExchangeRatesByDate(c core.Context, dataSource string, rateDate int32) ([]*models.ExchangeRateHistory, error) {
+	if dataSource == "" {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	latestExchangeRateHistory := &models.ExchangeRateHistory{}
+	has, err := s.UserDB().NewSession(c).Cols("rate_date").Where("data_source=? AND rate_date<=?", dataSource, rateDate).OrderBy("rate_date desc").Limit(1).Get(latestExchangeRateHistory)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrExchangeRateHistoryNotFound
+	}
+
+	var exchangeRateHistories []*models.ExchangeRateHistory
+	err = s.UserDB().NewSession(c).Where("data_source=? AND rate_date=?", dataSource, latestExchangeRateHistory.RateDate).Find(&exchangeRateHistories)
+
+	return exchangeRateHistories, err
+}
+
+// GetExchangeRatesInDateRange returns the exchange rate history models between specified dates, including the latest snapshot before the start date
+func (s *ExchangeRateHistoryService) GetExchangeRatesInDateRange(c core.Context, dataSource string, startRateDate int32, endRateDate int32) ([]*models.ExchangeRateHistory, error) {
+	if dataSource == "" {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if startRateDate > 0 {
+		latestExchangeRateHistory := &models.ExchangeRateHistory{}
+		has, err := s.UserDB().NewSession(c).Cols("rate_date").Where("data_source=? AND rate_date<=?", dataSource, startRateDate).OrderBy("rate_date desc").Limit(1).Get(latestExchangeRateHistory)
+
+		if err != nil {
+			return nil, err
+		} else if has {
+			startRateDate = latestExchangeRateHistory.RateDate
+		}
+	}
+
+	condition := "data_source=?"
+	conditionParams := make([]any, 0, 3)
+	conditionParams = append(conditionParams, dataSource)
+
+	if startRateDate > 0 {
+		condition = condition + " AND rate_date>=?"
+		conditionParams = append(conditionParams, startRateDate)
+	}
+
+	if endRateDate > 0 {
+		condition = condition + " AND rate_date<=?"
+		conditionParams = append(conditionParams, endRateDate)
+	}
+
+	var exchangeRateHistories []*models.ExchangeRateHistory
+	err := s.UserDB().NewSession(c).Where(condition, conditionParams...).OrderBy("rate_date asc").Find(&exchangeRateHistories)
+
+	return exchangeRateHistories, err
+}
+
+// SaveExchangeRates saves the exchange rates of every published day to database, the existed exchange rates of the same day would be replaced
+func (s *ExchangeRateHistoryService) SaveExchangeRates(c core.Context, dataSource string, exchangeRateResps []*models.LatestExchangeRateResponse) (int, error) {
+	if dataSource == "" {
+		return 0, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	now := time.Now().Unix()
+	savedDays := 0
+
+	for i := 0; i < len(exchangeRateResps); i++ {
+		exchangeRateHistories := models.CreateExchangeRateHistories(dataSource, exchangeRateResps[i])
+
+		if len(exchangeRateHistories) < 1 {
+			continue
+		}
+
+		rateDate := exchangeRateHistories[0].RateDate
+
+		err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+			_, err := sess.Where("data_source=? AND rate_date=?", dataSource, rateDate).Delete(&models.ExchangeRateHistory{})
+
+			if err != nil {
+				return err
+			}
+
+			for j := 0; j < len(exchangeRateHistories); j++ {
+				exchangeRateHistory := exchangeRateHistories[j]
+				exchangeRateHistory.CreatedUnixTime = now
+
+				_, err := sess.Insert(exchangeRateHistory)
+
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			log.Errorf(c, "[exchange_rate_histories.SaveExchangeRates] failed to save exchange rates of \"%d\" for data source \"%s\", because %s", rateDate, dataSource, err.Error())
+			return savedDays, err
+		}
+
+		savedDays++
+	}
+
+	return savedDays, nil
+}
@@ -0,0 +1,505 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// InvestmentService represents investment service
+type InvestmentService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize an investment service singleton instance
+var (
+	Investments = &InvestmentService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllSecuritiesByUid returns all security models of user
+func (s *InvestmentService) GetAllSecuritiesByUid(c core.Context, uid int64) ([]*models.InvestmentSecurity, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var securities []*models.InvestmentSecurity
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("account_id asc, symbol asc").Find(&securities)
+
+	return securities, err
+}
+
+// GetSecuritiesByAccountId returns all security models of specified account
+func (s *InvestmentService) GetSecuritiesByAccountId(c core.Context, uid int64, accountId int64) ([]*models.InvestmentSecurity, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	var securities []*models.InvestmentSecurity
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).OrderBy("symbol asc").Find(&securities)
+
+	return securities, err
+}
+
+// GetSecurityBySecurityId returns a security model according to security id
+func (s *InvestmentService) GetSecurityBySecurityId(c core.Context, uid int64, securityId int64) (*models.InvestmentSecurity, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if securityId <= 0 {
+		return nil, errs.ErrSecurityIdInvalid
+	}
+
+	security := &models.InvestmentSecurity{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(securityId).Where("uid=? AND deleted=?", uid, false).Get(security)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrSecurityNotFound
+	}
+
+	return security, nil
+}
+
+// CreateSecurity saves a new security model to database
+func (s *InvestmentService) CreateSecurity(c core.Context, security *models.InvestmentSecurity) error {
+	if security.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	security.SecurityId = s.GenerateUuid(uuid.UUID_TYPE_INVESTMENT)
+
+	if security.SecurityId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	security.Deleted = false
+	security.CreatedUnixTime = time.Now().Unix()
+	security.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(security.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid", "deleted", "account_id", "symbol").Where("uid=? AND deleted=? AND account_id=? AND symbol=?", security.Uid, false, security.AccountId, security.Symbol).Exist(&models.InvestmentSecurity{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrSecuritySymbolAlreadyExists
+		}
+
+		_, err = sess.Insert(security)
+		return err
+	})
+}
+
+// ModifySecurity saves an existed security model to database
+func (s *InvestmentService) ModifySecurity(c core.Context, security *models.InvestmentSecurity) error {
+	if security.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	security.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(security.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid", "deleted", "security_id", "account_id", "symbol").Where("uid=? AND deleted=? AND security_id<>? AND account_id=? AND symbol=?", security.Uid, false, security.SecurityId, security.AccountId, security.Symbol).Exist(&models.InvestmentSecurity{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrSecuritySymbolAlreadyExists
+		}
+
+		updatedRows, err := sess.ID(security.SecurityId).Cols("symbol", "name", "comment", "updated_unix_time").Where("uid=? AND deleted=?", security.Uid, false).Update(security)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrSecurityNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteSecurity deletes an existed security and all its investment transactions and prices from database
+func (s *InvestmentService) DeleteSecurity(c core.Context, uid int64, securityId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentSecurity{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updateTransactionModel := &models.InvestmentTransaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updatePriceModel := &models.SecurityPrice{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(securityId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrSecurityNotFound
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND security_id=?", uid, false, securityId).Update(updateTransactionModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND security_id=?", uid, false, securityId).Update(updatePriceModel)
+
+		return err
+	})
+}
+
+// DeleteAllInvestments deletes all existed securities, investment transactions and security prices from database
+func (s *InvestmentService) DeleteAllInvestments(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentSecurity{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updateTransactionModel := &models.InvestmentTransaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updatePriceModel := &models.SecurityPrice{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateTransactionModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updatePriceModel)
+
+		return err
+	})
+}
+
+// GetAllInvestmentTransactionsByUid returns all investment transaction models of user in chronological order
+func (s *InvestmentService) GetAllInvestmentTransactionsByUid(c core.Context, uid int64) ([]*models.InvestmentTransaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var transactions []*models.InvestmentTransaction
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_time asc, investment_transaction_id asc").Find(&transactions)
+
+	return transactions, err
+}
+
+// GetInvestmentTransactionsBySecurityId returns all investment transaction models of specified security in chronological order
+func (s *InvestmentService) GetInvestmentTransactionsBySecurityId(c core.Context, uid int64, securityId int64) ([]*models.InvestmentTransaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if securityId <= 0 {
+		return nil, errs.ErrSecurityIdInvalid
+	}
+
+	var transactions []*models.InvestmentTransaction
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND security_id=?", uid, false, securityId).OrderBy("transaction_time asc, investment_transaction_id asc").Find(&transactions)
+
+	return transactions, err
+}
+
+// GetInvestmentTransactionByInvestmentTransactionId returns an investment transaction model according to investment transaction id
+func (s *InvestmentService) GetInvestmentTransactionByInvestmentTransactionId(c core.Context, uid int64, transactionId int64) (*models.InvestmentTransaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrInvestmentTransactionIdInvalid
+	}
+
+	transaction := &models.InvestmentTransaction{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(transaction)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrInvestmentTransactionNotFound
+	}
+
+	return transaction, nil
+}
+
+// CreateInvestmentTransaction saves a new investment transaction model to database
+func (s *InvestmentService) CreateInvestmentTransaction(c core.Context, transaction *models.InvestmentTransaction) error {
+	if transaction.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if !transaction.Type.IsValid() {
+		return errs.ErrInvestmentTransactionTypeInvalid
+	}
+
+	if !transaction.IsQuantityValid() {
+		return errs.ErrInvestmentTransactionQuantityInvalid
+	}
+
+	transaction.InvestmentTransactionId = s.GenerateUuid(uuid.UUID_TYPE_INVESTMENT)
+
+	if transaction.InvestmentTransactionId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	transaction.Deleted = false
+	transaction.CreatedUnixTime = time.Now().Unix()
+	transaction.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var existedTransactions models.InvestmentTransactionSlice
+		err := sess.Where("uid=? AND deleted=? AND security_id=?", transaction.Uid, false, transaction.SecurityId).Find(&existedTransactions)
+
+		if err != nil {
+			return err
+		}
+
+		existedTransactions = append(existedTransactions, transaction)
+		sort.Sort(existedTransactions)
+
+		if _, err = existedTransactions.ToInvestmentHolding(transaction.SecurityId); err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(transaction)
+		return err
+	})
+}
+
+// DeleteInvestmentTransaction deletes an existed investment transaction from database
+func (s *InvestmentService) DeleteInvestmentTransaction(c core.Context, uid int64, transactionId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.InvestmentTransaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		transaction := &models.InvestmentTransaction{}
+		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(transaction)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrInvestmentTransactionNotFound
+		}
+
+		var remainingTransactions models.InvestmentTransactionSlice
+		err = sess.Where("uid=? AND deleted=? AND security_id=? AND investment_transaction_id<>?", uid, false, transaction.SecurityId, transactionId).Find(&remainingTransactions)
+
+		if err != nil {
+			return err
+		}
+
+		sort.Sort(remainingTransactions)
+
+		if _, err = remainingTransactions.ToInvestmentHolding(transaction.SecurityId); err != nil {
+			return err
+		}
+
+		deletedRows, err := sess.ID(transactionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrInvestmentTransactionNotFound
+		}
+
+		return err
+	})
+}
+
+// GetAllAccountsDailyInvestmentMarketValues returns the closing market value of securities of each account on every day when the holding quantity or security price changes
+func (s *InvestmentService) GetAllAccountsDailyInvestmentMarketValues(c core.Context, uid int64, maxUnixTime int64, clientTimezone *time.Location) (map[int32]map[int64]int64, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	dailyMarketValues := make(map[int32]map[int64]int64)
+	securities, err := s.GetAllSecuritiesByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	} else if len(securities) < 1 {
+		return dailyMarketValues, nil
+	}
+
+	var transactions []*models.InvestmentTransaction
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND transaction_time<=?", uid, false, maxUnixTime).OrderBy("transaction_time asc, investment_transaction_id asc").Find(&transactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var prices []*models.SecurityPrice
+	err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND price_time<=?", uid, false, maxUnixTime).OrderBy("price_time asc, price_id asc").Find(&prices)
+
+	if err != nil {
+		return nil, err
+	}
+
+	securityAccountIds := make(map[int64]int64, len(securities))
+	accountSecurityIds := make(map[int64][]int64)
+
+	for i := 0; i < len(securities); i++ {
+		security := securities[i]
+		securityAccountIds[security.SecurityId] = security.AccountId
+		accountSecurityIds[security.AccountId] = append(accountSecurityIds[security.AccountId], security.SecurityId)
+	}
+
+	quantities := make(map[int64]int64)
+	lastPrices := make(map[int64]int64)
+	changedAccountIds := make(map[int64]bool)
+	currentYearMonthDay := int32(0)
+
+	flushDailyMarketValues := func() {
+		if len(changedAccountIds) < 1 {
+			return
+		}
+
+		accountMarketValues := dailyMarketValues[currentYearMonthDay]
+
+		if accountMarketValues == nil {
+			accountMarketValues = make(map[int64]int64)
+			dailyMarketValues[currentYearMonthDay] = accountMarketValues
+		}
+
+		for accountId := range changedAccountIds {
+			marketValue := int64(0)
+			securityIds := accountSecurityIds[accountId]
+
+			for i := 0; i < len(securityIds); i++ {
+				marketValue += models.GetInvestmentMarketValue(quantities[securityIds[i]], lastPrices[securityIds[i]])
+			}
+
+			accountMarketValues[accountId] = marketValue
+		}
+
+		changedAccountIds = make(map[int64]bool)
+	}
+
+	for i, j := 0, 0; i < len(transactions) || j < len(prices); {
+		var eventTime int64
+		var securityId int64
+
+		if j >= len(prices) || (i < len(transactions) && transactions[i].TransactionTime <= prices[j].PriceTime) {
+			transaction := transactions[i]
+			i++
+
+			if transaction.Type == models.INVESTMENT_TRANSACTION_TYPE_DIVIDEND {
+				continue
+			}
+
+			eventTime = transaction.TransactionTime
+			securityId = transaction.SecurityId
+
+			if transaction.Type == models.INVESTMENT_TRANSACTION_TYPE_BUY {
+				quantities[securityId] += transaction.Quantity
+			} else if transaction.Type == models.INVESTMENT_TRANSACTION_TYPE_SELL {
+				quantities[securityId] -= transaction.Quantity
+			}
+
+			if impliedPrice := transaction.ToImpliedSecurityPrice(); impliedPrice != nil {
+				lastPrices[securityId] = impliedPrice.Price
+			}
+		} else {
+			price := prices[j]
+			j++
+
+			eventTime = price.PriceTime
+			securityId = price.SecurityId
+			lastPrices[securityId] = price.Price
+		}
+
+		accountId, exists := securityAccountIds[securityId]
+
+		if !exists {
+			continue
+		}
+
+		yearMonthDay := utils.FormatUnixTimeToNumericYearMonthDay(eventTime, clientTimezone)
+
+		if yearMonthDay != currentYearMonthDay {
+			flushDailyMarketValues()
+			currentYearMonthDay = yearMonthDay
+		}
+
+		changedAccountIds[accountId] = true
+	}
+
+	flushDailyMarketValues()
+
+	return dailyMarketValues, nil
+}
+
+// GetInvestmentTransactionListMapBySecurityId returns an investment transaction list map grouped by security id
+func (s *InvestmentService) GetInvestmentTransactionListMapBySecurityId(transactions []*models.InvestmentTransaction) map[int64]models.InvestmentTransactionSlice {
+	transactionMap := make(map[int64]models.InvestmentTransactionSlice)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transactionMap[transaction.SecurityId] = append(transactionMap[transaction.SecurityId], transaction)
+	}
+
+	return transactionMap
+}
@@ -0,0 +1,577 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/locales"
+	"github.com/mayswind/ezbookkeeping/pkg/mail"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/templates"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const ledgerInvitationUrlFormat = "%sdesktop#/ledger/invitation?token=%s"
+const ledgerBookUsernameFormat = "ledger_%d"
+const ledgerBookEmailFormat = "ledger_%d@ledger.invalid"
+
+// LedgerService represents shared ledger service
+type LedgerService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingMailer
+	ServiceUsingUuid
+}
+
+// Initialize a shared ledger service singleton instance
+var (
+	Ledgers = &LedgerService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingMailer: ServiceUsingMailer{
+			container: mail.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetLedgerByLedgerId returns a shared ledger model according to ledger id
+func (s *LedgerService) GetLedgerByLedgerId(c core.Context, ledgerId int64) (*models.Ledger, error) {
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	ledger := &models.Ledger{}
+	has, err := s.UserDB().NewSession(c).ID(ledgerId).Where("deleted=?", false).Get(ledger)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLedgerNotFound
+	}
+
+	return ledger, nil
+}
+
+// GetLedgerByUid returns the shared ledger model which is created by the specified user
+func (s *LedgerService) GetLedgerByUid(c core.Context, uid int64) (*models.Ledger, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	ledger := &models.Ledger{}
+	has, err := s.UserDB().NewSession(c).Where("uid=? AND deleted=?", uid, false).Get(ledger)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLedgerNotFound
+	}
+
+	return ledger, nil
+}
+
+// GetLedgersByLedgerIds returns shared ledger models according to ledger ids
+func (s *LedgerService) GetLedgersByLedgerIds(c core.Context, ledgerIds []int64) (map[int64]*models.Ledger, error) {
+	ledgerMap := make(map[int64]*models.Ledger, len(ledgerIds))
+
+	if len(ledgerIds) < 1 {
+		return ledgerMap, nil
+	}
+
+	var ledgers []*models.Ledger
+	err := s.UserDB().NewSession(c).Where("deleted=?", false).In("ledger_id", ledgerIds).Find(&ledgers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(ledgers); i++ {
+		ledgerMap[ledgers[i].LedgerId] = ledgers[i]
+	}
+
+	return ledgerMap, nil
+}
+
+// GetMemberByMemberId returns a shared ledger member model according to member id
+func (s *LedgerService) GetMemberByMemberId(c core.Context, memberId int64) (*models.LedgerMember, error) {
+	if memberId <= 0 {
+		return nil, errs.ErrLedgerMemberIdInvalid
+	}
+
+	member := &models.LedgerMember{}
+	has, err := s.UserDB().NewSession(c).ID(memberId).Where("deleted=?", false).Get(member)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLedgerMemberNotFound
+	}
+
+	return member, nil
+}
+
+// GetMembersByLedgerId returns all the invited and accepted member models of the specified shared ledger
+func (s *LedgerService) GetMembersByLedgerId(c core.Context, ledgerId int64) ([]*models.LedgerMember, error) {
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	var members []*models.LedgerMember
+	err := s.UserDB().NewSession(c).Where("ledger_id=? AND deleted=?", ledgerId, false).OrderBy("created_unix_time asc").Find(&members)
+
+	return members, err
+}
+
+// GetAcceptedMember returns the accepted member model of the specified user in the specified shared ledger
+func (s *LedgerService) GetAcceptedMember(c core.Context, ledgerId int64, uid int64) (*models.LedgerMember, error) {
+	if ledgerId <= 0 {
+		return nil, errs.ErrLedgerIdInvalid
+	}
+
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	member := &models.LedgerMember{}
+	has, err := s.UserDB().NewSession(c).Where("ledger_id=? AND uid=? AND deleted=? AND status=?", ledgerId, uid, false, models.LEDGER_MEMBER_STATUS_ACCEPTED).Get(member)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLedgerMemberNotFound
+	}
+
+	return member, nil
+}
+
+// GetAcceptedMembersByUid returns all the accepted member models of the specified user
+func (s *LedgerService) GetAcceptedMembersByUid(c core.Context, uid int64) ([]*models.LedgerMember, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var members []*models.LedgerMember
+	err := s.UserDB().NewSession(c).Where("uid=? AND deleted=? AND status=?", uid, false, models.LEDGER_MEMBER_STATUS_ACCEPTED).Find(&members)
+
+	return members, err
+}
+
+// GetPendingInvitationsByUid returns all the member models of the specified user which are not accepted yet
+func (s *LedgerService) GetPendingInvitationsByUid(c core.Context, uid int64) ([]*models.LedgerMember, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var members []*models.LedgerMember
+	err := s.UserDB().NewSession(c).Where("uid=? AND deleted=? AND status=?", uid, false, models.LEDGER_MEMBER_STATUS_INVITED).OrderBy("created_unix_time desc").Find(&members)
+
+	return members, err
+}
+
+// CreateLedger saves a new shared ledger model, the dedicated book of the shared ledger and the owner member of the creator to database
+func (s *LedgerService) CreateLedger(c core.Context, ledger *models.Ledger, creator *models.User) error {
+	if ledger.Uid <= 0 || creator.Uid != ledger.Uid {
+		return errs.ErrUserIdInvalid
+	}
+
+	ledger.LedgerId = s.GenerateUuid(uuid.UUID_TYPE_LEDGER)
+
+	if ledger.LedgerId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	ledger.BookUid = s.GenerateUuid(uuid.UUID_TYPE_USER)
+
+	if ledger.BookUid < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	salt, err := utils.GetRandomString(10)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+
+	// the book of shared ledger inherits the preferences of creator, but it cannot login or be managed by anyone
+	book := *creator
+	book.Uid = ledger.BookUid
+	book.Username = fmt.Sprintf(ledgerBookUsernameFormat, ledger.LedgerId)
+	book.Email = fmt.Sprintf(ledgerBookEmailFormat, ledger.LedgerId)
+	book.Nickname = ledger.Name
+	book.Password = ""
+	book.Salt = salt
+	book.CustomAvatarType = ""
+	book.DefaultAccountId = 0
+	book.BudgetAlertThresholds = ""
+	book.BudgetAlertTimezone = ""
+	book.FeatureRestriction = 0
+	book.Role = models.USER_ROLE_LEDGER_BOOK
+	book.Disabled = true
+	book.Deleted = false
+	book.EmailVerified = false
+	book.CreatedUnixTime = now
+	book.UpdatedUnixTime = now
+	book.DeletedUnixTime = 0
+	book.LastLoginUnixTime = 0
+
+	owner := &models.LedgerMember{
+		MemberId:         s.GenerateUuid(uuid.UUID_TYPE_LEDGER_MEMBER),
+		LedgerId:         ledger.LedgerId,
+		Uid:              ledger.Uid,
+		Deleted:          false,
+		Role:             models.LEDGER_MEMBER_ROLE_OWNER,
+		Status:           models.LEDGER_MEMBER_STATUS_ACCEPTED,
+		InviterUid:       ledger.Uid,
+		CreatedUnixTime:  now,
+		UpdatedUnixTime:  now,
+		AcceptedUnixTime: now,
+	}
+
+	if owner.MemberId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	ledger.Deleted = false
+	ledger.CreatedUnixTime = now
+	ledger.UpdatedUnixTime = now
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("ledger_id").Where("uid=? AND deleted=?", ledger.Uid, false).Exist(&models.Ledger{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrLedgerAlreadyExists
+		}
+
+		_, err = sess.Insert(&book)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(ledger)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(owner)
+
+		return err
+	})
+}
+
+// ModifyLedger saves an existed shared ledger model to database
+func (s *LedgerService) ModifyLedger(c core.Context, ledger *models.Ledger) error {
+	if ledger.LedgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	ledger.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(ledger.LedgerId).Cols("name", "updated_unix_time").Where("deleted=?", false).Update(ledger)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteLedger deletes an existed shared ledger, its dedicated book and all its members from database
+func (s *LedgerService) DeleteLedger(c core.Context, uid int64, ledgerId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if ledgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateLedgerModel := &models.Ledger{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updateMemberModel := &models.LedgerMember{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	updateBookModel := &models.User{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		ledger := &models.Ledger{}
+		has, err := sess.ID(ledgerId).Where("uid=? AND deleted=?", uid, false).Get(ledger)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrLedgerNotFound
+		}
+
+		deletedRows, err := sess.ID(ledgerId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateLedgerModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrLedgerNotFound
+		}
+
+		_, err = sess.ID(ledger.BookUid).Cols("deleted", "deleted_unix_time").Where("role=? AND deleted=?", models.USER_ROLE_LEDGER_BOOK, false).Update(updateBookModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("ledger_id=? AND deleted=?", ledgerId, false).Update(updateMemberModel)
+
+		return err
+	})
+}
+
+// InviteMember saves a new shared ledger member model which is waiting for accepting to database
+func (s *LedgerService) InviteMember(c core.Context, member *models.LedgerMember) error {
+	if member.LedgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	if member.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if !member.Role.CanBeGrantedToMember() {
+		return errs.ErrLedgerMemberRoleInvalid
+	}
+
+	member.MemberId = s.GenerateUuid(uuid.UUID_TYPE_LEDGER_MEMBER)
+
+	if member.MemberId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	member.Deleted = false
+	member.Status = models.LEDGER_MEMBER_STATUS_INVITED
+	member.CreatedUnixTime = time.Now().Unix()
+	member.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("member_id").Where("ledger_id=? AND uid=? AND deleted=?", member.LedgerId, member.Uid, false).Exist(&models.LedgerMember{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrLedgerMemberAlreadyExists
+		}
+
+		_, err = sess.Insert(member)
+
+		return err
+	})
+}
+
+// ModifyMemberRole saves the role of an existed shared ledger member to database
+func (s *LedgerService) ModifyMemberRole(c core.Context, member *models.LedgerMember) error {
+	if member.MemberId <= 0 {
+		return errs.ErrLedgerMemberIdInvalid
+	}
+
+	if !member.Role.CanBeGrantedToMember() {
+		return errs.ErrLedgerMemberRoleInvalid
+	}
+
+	member.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(member.MemberId).Cols("role", "updated_unix_time").Where("ledger_id=? AND deleted=?", member.LedgerId, false).Update(member)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerMemberNotFound
+		}
+
+		return err
+	})
+}
+
+// RemoveMember deletes an existed shared ledger member from database
+func (s *LedgerService) RemoveMember(c core.Context, ledgerId int64, memberId int64) error {
+	if ledgerId <= 0 {
+		return errs.ErrLedgerIdInvalid
+	}
+
+	if memberId <= 0 {
+		return errs.ErrLedgerMemberIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.LedgerMember{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(memberId).Cols("deleted", "deleted_unix_time").Where("ledger_id=? AND deleted=?", ledgerId, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrLedgerMemberNotFound
+		}
+
+		return err
+	})
+}
+
+// AcceptInvitation updates the status of shared ledger member of the specified user to accepted
+func (s *LedgerService) AcceptInvitation(c core.Context, uid int64, memberId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if memberId <= 0 {
+		return errs.ErrLedgerMemberIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.LedgerMember{
+		Status:           models.LEDGER_MEMBER_STATUS_ACCEPTED,
+		UpdatedUnixTime:  now,
+		AcceptedUnixTime: now,
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(memberId).Cols("status", "updated_unix_time", "accepted_unix_time").Where("uid=? AND deleted=? AND status=?", uid, false, models.LEDGER_MEMBER_STATUS_INVITED).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrLedgerInvitationNotFound
+		}
+
+		return err
+	})
+}
+
+// DeclineInvitation deletes the shared ledger member of the specified user which is not accepted yet
+func (s *LedgerService) DeclineInvitation(c core.Context, uid int64, memberId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if memberId <= 0 {
+		return errs.ErrLedgerMemberIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.LedgerMember{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(memberId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND status=?", uid, false, models.LEDGER_MEMBER_STATUS_INVITED).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrLedgerInvitationNotFound
+		}
+
+		return err
+	})
+}
+
+// SendLedgerInvitationEmail sends shared ledger invitation email according to specified parameters
+func (s *LedgerService) SendLedgerInvitationEmail(c core.Context, inviter *models.User, invitee *models.User, ledger *models.Ledger, role models.LedgerMemberRole, invitationToken string, backupLocale string) error {
+	if !s.CurrentConfig().EnableSMTP {
+		return errs.ErrSMTPServerNotEnabled
+	}
+
+	locale := invitee.Language
+
+	if locale == "" {
+		locale = backupLocale
+	}
+
+	localeTextItems := locales.GetLocaleTextItems(locale)
+	ledgerInvitationTextItems := localeTextItems.LedgerInvitationMailTextItems
+
+	roleName := ledgerInvitationTextItems.RoleViewer
+
+	if role == models.LEDGER_MEMBER_ROLE_OWNER {
+		roleName = ledgerInvitationTextItems.RoleOwner
+	} else if role == models.LEDGER_MEMBER_ROLE_EDITOR {
+		roleName = ledgerInvitationTextItems.RoleEditor
+	}
+
+	expireTimeInHours := s.CurrentConfig().LedgerInvitationTokenExpiredTimeDuration.Hours()
+	acceptInvitationUrl := fmt.Sprintf(ledgerInvitationUrlFormat, s.CurrentConfig().RootUrl, url.QueryEscape(invitationToken))
+
+	tmpl, err := templates.GetTemplate(templates.TEMPLATE_LEDGER_INVITATION)
+
+	if err != nil {
+		return err
+	}
+
+	templateParams := map[string]any{
+		"AppName": localeTextItems.GlobalTextItems.AppName,
+		"LedgerInvitationMail": map[string]any{
+			"Title":               ledgerInvitationTextItems.Title,
+			"Salutation":          fmt.Sprintf(ledgerInvitationTextItems.SalutationFormat, invitee.Nickname),
+			"DescriptionAboveBtn": fmt.Sprintf(ledgerInvitationTextItems.DescriptionAboveBtnFormat, inviter.Nickname, ledger.Name, roleName),
+			"AcceptInvitationUrl": acceptInvitationUrl,
+			"AcceptInvitation":    ledgerInvitationTextItems.AcceptInvitation,
+			"DescriptionBelowBtn": fmt.Sprintf(ledgerInvitationTextItems.DescriptionBelowBtnFormat, expireTimeInHours),
+		},
+	}
+
+	var bodyBuffer bytes.Buffer
+	err = tmpl.Execute(&bodyBuffer, templateParams)
+
+	if err != nil {
+		return err
+	}
+
+	message := &mail.MailMessage{
+		To:      invitee.Email,
+		Subject: ledgerInvitationTextItems.Title,
+		Body:    bodyBuffer.String(),
+	}
+
+	err = s.SendMail(message)
+
+	return err
+}
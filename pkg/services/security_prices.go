@@ -0,0 +1,133 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// SecurityPriceService represents security price service
+type SecurityPriceService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a security price service singleton instance
+var (
+	SecurityPrices = &SecurityPriceService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetSecurityPricesBySecurityId returns all price models of specified security
+func (s *SecurityPriceService) GetSecurityPricesBySecurityId(c core.Context, uid int64, securityId int64) ([]*models.SecurityPrice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if securityId <= 0 {
+		return nil, errs.ErrSecurityIdInvalid
+	}
+
+	var prices []*models.SecurityPrice
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND security_id=?", uid, false, securityId).OrderBy("price_time desc, price_id desc").Find(&prices)
+
+	return prices, err
+}
+
+// GetLatestSecurityPricesByUid returns the latest price model of every security of user
+func (s *SecurityPriceService) GetLatestSecurityPricesByUid(c core.Context, uid int64) (map[int64]*models.SecurityPrice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var prices []*models.SecurityPrice
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("price_time asc, price_id asc").Find(&prices)
+
+	if err != nil {
+		return nil, err
+	}
+
+	latestPrices := make(map[int64]*models.SecurityPrice)
+
+	for i := 0; i < len(prices); i++ {
+		latestPrices[prices[i].SecurityId] = prices[i]
+	}
+
+	return latestPrices, nil
+}
+
+// CreateSecurityPrices saves new security price models to database
+func (s *SecurityPriceService) CreateSecurityPrices(c core.Context, uid int64, prices []*models.SecurityPrice) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if len(prices) < 1 {
+		return nil
+	}
+
+	priceUuids := s.GenerateUuids(uuid.UUID_TYPE_INVESTMENT, uint16(len(prices)))
+
+	if len(priceUuids) < len(prices) {
+		return errs.ErrSystemIsBusy
+	}
+
+	for i := 0; i < len(prices); i++ {
+		price := prices[i]
+		price.PriceId = priceUuids[i]
+		price.Uid = uid
+		price.Deleted = false
+		price.CreatedUnixTime = time.Now().Unix()
+		price.UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(prices); i++ {
+			_, err := sess.Insert(prices[i])
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteSecurityPrice deletes an existed security price from database
+func (s *SecurityPriceService) DeleteSecurityPrice(c core.Context, uid int64, priceId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.SecurityPrice{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(priceId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrSecurityPriceNotFound
+		}
+
+		return err
+	})
+}
@@ -107,8 +107,14 @@ func (s *TokenService) CreatePasswordResetTokenWithoutUserAgent(c core.Context,
 	return token, claims, err
 }
 
+// CreateLedgerInvitationToken generates a new shared ledger invitation token for the invited user and saves to database
+func (s *TokenService) CreateLedgerInvitationToken(c *core.WebContext, user *models.User, memberId int64) (string, *core.UserTokenClaims, error) {
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_LEDGER_INVITATION, s.getUserAgent(c), utils.Int64ToString(memberId), s.CurrentConfig().LedgerInvitationTokenExpiredTimeDuration)
+	return token, claims, err
+}
+
 // CreateAPIToken generates a new API token and saves to database
-func (s *TokenService) CreateAPIToken(c *core.WebContext, user *models.User, expiresInSeconds int64) (string, *core.UserTokenClaims, error) {
+func (s *TokenService) CreateAPIToken(c *core.WebContext, user *models.User, expiresInSeconds int64, context string) (string, *core.UserTokenClaims, error) {
 	var tokenExpiredTimeDuration time.Duration
 
 	if expiresInSeconds > 0 {
@@ -117,12 +123,12 @@ func (s *TokenService) CreateAPIToken(c *core.WebContext, user *models.User, exp
 		tokenExpiredTimeDuration = time.Unix(tokenMaxExpiredAtUnixTime, 0).Sub(time.Now())
 	}
 
-	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_API, s.getUserAgent(c), "", tokenExpiredTimeDuration)
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_API, s.getUserAgent(c), context, tokenExpiredTimeDuration)
 	return token, claims, err
 }
 
 // CreateAPITokenViaCli generates a new API token and saves to database
-func (s *TokenService) CreateAPITokenViaCli(c *core.CliContext, user *models.User, expiresInSeconds int64) (string, *models.TokenRecord, error) {
+func (s *TokenService) CreateAPITokenViaCli(c *core.CliContext, user *models.User, expiresInSeconds int64, context string) (string, *models.TokenRecord, error) {
 	var tokenExpiredTimeDuration time.Duration
 
 	if expiresInSeconds > 0 {
@@ -131,12 +137,12 @@ func (s *TokenService) CreateAPITokenViaCli(c *core.CliContext, user *models.Use
 		tokenExpiredTimeDuration = time.Unix(tokenMaxExpiredAtUnixTime, 0).Sub(time.Now())
 	}
 
-	token, _, tokenRecord, err := s.createToken(c, user, core.USER_TOKEN_TYPE_API, core.TokenUserAgentCreatedViaCli, "", tokenExpiredTimeDuration)
+	token, _, tokenRecord, err := s.createToken(c, user, core.USER_TOKEN_TYPE_API, core.TokenUserAgentCreatedViaCli, context, tokenExpiredTimeDuration)
 	return token, tokenRecord, err
 }
 
 // CreateMCPToken generates a new MCP token and saves to database
-func (s *TokenService) CreateMCPToken(c *core.WebContext, user *models.User, expiresInSeconds int64) (string, *core.UserTokenClaims, error) {
+func (s *TokenService) CreateMCPToken(c *core.WebContext, user *models.User, expiresInSeconds int64, context string) (string, *core.UserTokenClaims, error) {
 	var tokenExpiredTimeDuration time.Duration
 
 	if expiresInSeconds > 0 {
@@ -145,12 +151,12 @@ func (s *TokenService) CreateMCPToken(c *core.WebContext, user *models.User, exp
 		tokenExpiredTimeDuration = time.Unix(tokenMaxExpiredAtUnixTime, 0).Sub(time.Now())
 	}
 
-	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_MCP, s.getUserAgent(c), "", tokenExpiredTimeDuration)
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_MCP, s.getUserAgent(c), context, tokenExpiredTimeDuration)
 	return token, claims, err
 }
 
 // CreateMCPTokenViaCli generates a new MCP token and saves to database
-func (s *TokenService) CreateMCPTokenViaCli(c *core.CliContext, user *models.User, expiresInSeconds int64) (string, *models.TokenRecord, error) {
+func (s *TokenService) CreateMCPTokenViaCli(c *core.CliContext, user *models.User, expiresInSeconds int64, context string) (string, *models.TokenRecord, error) {
 	var tokenExpiredTimeDuration time.Duration
 
 	if expiresInSeconds > 0 {
@@ -159,7 +165,7 @@ func (s *TokenService) CreateMCPTokenViaCli(c *core.CliContext, user *models.Use
 		tokenExpiredTimeDuration = time.Unix(tokenMaxExpiredAtUnixTime, 0).Sub(time.Now())
 	}
 
-	token, _, tokenRecord, err := s.createToken(c, user, core.USER_TOKEN_TYPE_MCP, core.TokenUserAgentCreatedViaCli, "", tokenExpiredTimeDuration)
+	token, _, tokenRecord, err := s.createToken(c, user, core.USER_TOKEN_TYPE_MCP, core.TokenUserAgentCreatedViaCli, context, tokenExpiredTimeDuration)
 	return token, tokenRecord, err
 }
 
@@ -49,6 +49,40 @@ func (s *TransactionPictureService) GetTotalTransactionPicturesCountByUid(c core
 	return count, err
 }
 
+// GetTotalTransactionPicturesSizeByUid returns total size in bytes of transaction pictures of user
+func (s *TransactionPictureService) GetTotalTransactionPicturesSizeByUid(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	var pictureInfos []*models.TransactionPictureInfo
+	err := s.UserDataDB(uid).NewSession(c).Cols("picture_id", "uid", "picture_extension").Where("uid=? AND deleted=?", uid, false).Find(&pictureInfos)
+
+	if err != nil {
+		return 0, err
+	}
+
+	totalSize := int64(0)
+
+	for i := 0; i < len(pictureInfos); i++ {
+		pictureInfo := pictureInfos[i]
+
+		if pictureInfo.PictureExtension == "" {
+			continue
+		}
+
+		size, err := s.GetTransactionPictureSize(c, pictureInfo.Uid, pictureInfo.PictureId, pictureInfo.PictureExtension)
+
+		if err != nil {
+			return 0, err
+		}
+
+		totalSize += size
+	}
+
+	return totalSize, nil
+}
+
 // GetPictureInfoByPictureId returns a transaction picture info model according to transaction picture id
 func (s *TransactionPictureService) GetPictureInfoByPictureId(c core.Context, uid int64, pictureId int64) (*models.TransactionPictureInfo, error) {
 	if uid <= 0 {
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// TransactionSplitService represents transaction split service
+type TransactionSplitService struct {
+	ServiceUsingDB
+}
+
+// Initialize a transaction split service singleton instance
+var (
+	TransactionSplits = &TransactionSplitService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetSplitsByTransactionId returns all split line models of specified transaction
+func (s *TransactionSplitService) GetSplitsByTransactionId(c core.Context, uid int64, transactionId int64) ([]*models.TransactionSplit, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).OrderBy("display_order asc").Find(&splits)
+
+	return splits, err
+}
+
+// GetSplitsByTransactionIds returns all split line models of specified transactions
+func (s *TransactionSplitService) GetSplitsByTransactionIds(c core.Context, uid int64, transactionIds []int64) (map[int64][]*models.TransactionSplit, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionIds == nil {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).OrderBy("display_order asc").Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetSplitListMapByList(splits), nil
+}
+
+// GetAllSplitsOfAllTransactions returns all split line models of all transactions
+func (s *TransactionSplitService) GetAllSplitsOfAllTransactions(c core.Context, uid int64) (map[int64][]*models.TransactionSplit, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetSplitListMapByList(splits), nil
+}
+
+// GetSplitListMapByList returns a split line list map by a list
+func (s *TransactionSplitService) GetSplitListMapByList(splits []*models.TransactionSplit) map[int64][]*models.TransactionSplit {
+	splitMap := make(map[int64][]*models.TransactionSplit)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		splitMap[split.TransactionId] = append(splitMap[split.TransactionId], split)
+	}
+
+	return splitMap
+}
@@ -149,7 +149,7 @@ func (s *TransactionTemplateService) ModifyTemplate(c core.Context, template *mo
 			return err
 		}
 
-		updatedRows, err := sess.ID(template.TemplateId).Cols("name", "type", "category_id", "account_id", "scheduled_frequency_type", "scheduled_frequency", "scheduled_start_time", "scheduled_end_time", "scheduled_at", "scheduled_timezone_utc_offset", "tag_ids", "amount", "related_account_id", "related_account_amount", "hide_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", template.Uid, false).Update(template)
+		updatedRows, err := sess.ID(template.TemplateId).Cols("name", "type", "category_id", "account_id", "scheduled_frequency_type", "scheduled_frequency", "scheduled_start_time", "scheduled_end_time", "scheduled_at", "scheduled_timezone_utc_offset", "scheduled_adjustment_policy", "scheduled_holidays", "tag_ids", "amount", "related_account_id", "related_account_amount", "hide_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", template.Uid, false).Update(template)
 
 		if err != nil {
 			return err
@@ -226,6 +226,11 @@ func (s *TransactionTemplateService) DeleteTemplate(c core.Context, uid int64, t
 		DeletedUnixTime: now,
 	}
 
+	updateExceptionModel := &models.TransactionScheduleException{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		deletedRows, err := sess.ID(templateId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
 
@@ -235,6 +240,8 @@ func (s *TransactionTemplateService) DeleteTemplate(c core.Context, uid int64, t
 			return errs.ErrTransactionTemplateNotFound
 		}
 
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND template_id=?", uid, false, templateId).Update(updateExceptionModel)
+
 		return err
 	})
 }
@@ -252,6 +259,11 @@ func (s *TransactionTemplateService) DeleteAllTemplates(c core.Context, uid int6
 		DeletedUnixTime: now,
 	}
 
+	updateExceptionModel := &models.TransactionScheduleException{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
 
@@ -259,10 +271,122 @@ func (s *TransactionTemplateService) DeleteAllTemplates(c core.Context, uid int6
 			return err
 		}
 
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateExceptionModel)
+
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
 
+// GetScheduleExceptionsByTemplateId returns all exception models of the scheduled transaction template
+func (s *TransactionTemplateService) GetScheduleExceptionsByTemplateId(c core.Context, uid int64, templateId int64) ([]*models.TransactionScheduleException, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if templateId <= 0 {
+		return nil, errs.ErrTransactionTemplateIdInvalid
+	}
+
+	var exceptions []*models.TransactionScheduleException
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND template_id=?", uid, false, templateId).OrderBy("occurrence_date asc").Find(&exceptions)
+
+	return exceptions, err
+}
+
+// GetAllScheduleExceptionsByUid returns all exception models of all scheduled transaction templates of user
+func (s *TransactionTemplateService) GetAllScheduleExceptionsByUid(c core.Context, uid int64) ([]*models.TransactionScheduleException, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var exceptions []*models.TransactionScheduleException
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("template_id asc, occurrence_date asc").Find(&exceptions)
+
+	return exceptions, err
+}
+
+// GetScheduleExceptionByExceptionId returns a scheduled transaction exception model according to exception id
+func (s *TransactionTemplateService) GetScheduleExceptionByExceptionId(c core.Context, uid int64, exceptionId int64) (*models.TransactionScheduleException, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if exceptionId <= 0 {
+		return nil, errs.ErrScheduledTransactionExceptionIdInvalid
+	}
+
+	exception := &models.TransactionScheduleException{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(exceptionId).Where("uid=? AND deleted=?", uid, false).Get(exception)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrScheduledTransactionExceptionNotFound
+	}
+
+	return exception, nil
+}
+
+// CreateScheduleException saves a new exception of a single scheduled transaction occurrence to database
+func (s *TransactionTemplateService) CreateScheduleException(c core.Context, exception *models.TransactionScheduleException) error {
+	if exception.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	exception.ExceptionId = s.GenerateUuid(uuid.UUID_TYPE_TEMPLATE_EXCEPTION)
+
+	if exception.ExceptionId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	exception.Deleted = false
+	exception.CreatedUnixTime = time.Now().Unix()
+	exception.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(exception.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Where("uid=? AND deleted=? AND template_id=? AND occurrence_date=?", exception.Uid, false, exception.TemplateId, exception.OccurrenceDate).Exist(&models.TransactionScheduleException{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrScheduledTransactionOccurrenceAlreadyHasException
+		}
+
+		_, err = sess.Insert(exception)
+		return err
+	})
+}
+
+// DeleteScheduleException deletes an existed scheduled transaction exception from database
+func (s *TransactionTemplateService) DeleteScheduleException(c core.Context, uid int64, exceptionId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionScheduleException{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(exceptionId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrScheduledTransactionExceptionNotFound
+		}
+
+		return err
+	})
+}
+
 func (s *TransactionTemplateService) isTemplateValid(sess *xorm.Session, template *models.TransactionTemplate) error {
 	// check accounts are valid
 	sourceAccount := &models.Account{}
@@ -563,7 +563,7 @@ func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxT
 }
 
 // CreateTransaction saves a new transaction to database
-func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64) error {
+func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64, splits []*models.TransactionSplit) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -597,6 +597,13 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 		return errs.ErrSystemIsBusy
 	}
 
+	needSplitUuidCount := uint16(len(splits))
+	splitUuids := s.GenerateUuids(uuid.UUID_TYPE_SPLIT, needSplitUuidCount)
+
+	if len(splitUuids) < int(needSplitUuidCount) {
+		return errs.ErrSystemIsBusy
+	}
+
 	transaction.TransactionId = transactionUuids[0]
 
 	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
@@ -627,21 +634,33 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 		UpdatedUnixTime: now,
 	}
 
+	s.fillTransactionSplits(transaction, splits, splitUuids, now)
+
 	userDataDb := s.UserDataDB(transaction.Uid)
 
-	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
-		return s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+	err = userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
+		return s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel, splits)
 	})
+
+	if err != nil {
+		return err
+	}
+
+	webhookEvents.publishTransactionEvent(c, models.WEBHOOK_EVENT_TYPE_TRANSACTION_CREATED, transaction)
+	webhookEvents.publishAccountBalanceChangedEvents(c, transaction.Uid, []int64{transaction.AccountId, transaction.RelatedAccountId})
+
+	return nil
 }
 
 // BatchCreateTransactions saves new transactions to database
-func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, processHandler core.TaskProcessUpdateHandler) error {
+func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, allSplits map[int][]*models.TransactionSplit, processHandler core.TaskProcessUpdateHandler) error {
 	now := time.Now().Unix()
 	currentProcess := float64(0)
 	processUpdateStep := int(math.Max(100.0, float64(len(transactions)/100.0)))
 
 	needTransactionUuidCount := uint16(0)
 	needTagIndexUuidCount := uint16(0)
+	needSplitUuidCount := uint16(0)
 
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
@@ -678,7 +697,15 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 		needTagIndexUuidCount += uint16(len(uniqueTagIds))
 	}
 
-	if needTransactionUuidCount > uint16(65535) || needTagIndexUuidCount > uint16(65535) {
+	for index, splits := range allSplits {
+		if index < 0 || index >= len(transactions) {
+			return errs.ErrOperationFailed
+		}
+
+		needSplitUuidCount += uint16(len(splits))
+	}
+
+	if needTransactionUuidCount > uint16(65535) || needTagIndexUuidCount > uint16(65535) || needSplitUuidCount > uint16(65535) {
 		return errs.ErrImportTooManyTransaction
 	}
 
@@ -735,14 +762,32 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 		allTransactionTagIds[transaction.TransactionId] = uniqueTagIds
 	}
 
+	splitUuids := s.GenerateUuids(uuid.UUID_TYPE_SPLIT, needSplitUuidCount)
+	splitUuidIndex := 0
+
+	if len(splitUuids) < int(needSplitUuidCount) {
+		return errs.ErrSystemIsBusy
+	}
+
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit)
+
+	for index, splits := range allSplits {
+		transaction := transactions[index]
+		s.fillTransactionSplits(transaction, splits, splitUuids[splitUuidIndex:splitUuidIndex+len(splits)], now)
+		splitUuidIndex += len(splits)
+
+		allTransactionSplits[transaction.TransactionId] = splits
+	}
+
 	userDataDb := s.UserDataDB(uid)
 
-	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
+	err := userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
 		for i := 0; i < len(transactions); i++ {
 			transaction := transactions[i]
 			transactionTagIndexes := allTransactionTagIndexes[transaction.TransactionId]
 			transactionTagIds := allTransactionTagIds[transaction.TransactionId]
-			err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, transactionTagIds, nil, nil)
+			transactionSplits := allTransactionSplits[transaction.TransactionId]
+			err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, transactionTagIds, nil, nil, transactionSplits)
 
 			currentProcess = float64(i) / float64(len(transactions)) * 100
 
@@ -760,6 +805,33 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	accountIds := make([]int64, 0, len(transactions)*2)
+
+	for i := 0; i < len(transactions); i++ {
+		accountIds = append(accountIds, transactions[i].AccountId, transactions[i].RelatedAccountId)
+	}
+
+	webhookEvents.publishAccountBalanceChangedEvents(c, uid, accountIds)
+
+	return nil
+}
+
+// ImportTransactions saves new imported transactions to database and publishes the import completed event
+func (s *TransactionService) ImportTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, allSplits map[int][]*models.TransactionSplit, processHandler core.TaskProcessUpdateHandler) error {
+	err := s.BatchCreateTransactions(c, uid, transactions, allTagIds, allSplits, processHandler)
+
+	if err != nil {
+		return err
+	}
+
+	webhookEvents.publishImportCompletedEvent(c, uid, len(transactions))
+
+	return nil
 }
 
 // CreateScheduledTransactions saves all scheduled transactions that should be created now
@@ -784,14 +856,14 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 		var templates []*models.TransactionTemplate
 		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=?"+
 			" AND template_type=?"+
-			" AND (scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=?)"+
+			" AND (scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=? OR scheduled_frequency_type=?)"+
 			" AND (scheduled_start_time IS NULL OR scheduled_start_time<=?)"+
 			" AND (scheduled_end_time IS NULL OR scheduled_end_time>=?)"+
 			" AND scheduled_at>=?"+
 			" AND scheduled_at<?",
 			false,
 			models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE,
-			models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS,
+			models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_RRULE,
 			startTime.Unix(),
 			startTime.Unix(),
 			minScheduledAt,
@@ -823,18 +895,18 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 			continue
 		}
 
-		if (template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY &&
-			template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY &&
-			template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY &&
-			template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY &&
-			template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS) ||
-			template.ScheduledFrequency == "" {
-			skipCount++
-			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid scheduled transaction frequency", template.TemplateId)
+		var exceptions []*models.TransactionScheduleException
+		err := s.UserDataDB(template.Uid).NewSession(c).Where("uid=? AND deleted=? AND template_id=?", template.Uid, false, template.TemplateId).Find(&exceptions)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" failed to get scheduled transaction exceptions, because %s", template.TemplateId, err.Error())
 			continue
 		}
 
-		frequencyValues, err := utils.StringArrayToInt64Array(strings.Split(template.ScheduledFrequency, ","))
+		transactionUnixTime := todayFirstUnixTimeInUTC + int64(template.ScheduledAt)*60
+		transactionDate := time.Unix(transactionUnixTime, 0).In(template.GetScheduledTimezone())
+		occurrences, err := template.GetScheduledOccurrences(exceptions, transactionDate, transactionDate)
 
 		if err != nil {
 			skipCount++
@@ -842,117 +914,109 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 			continue
 		}
 
-		if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY {
-			maxDayInMonth := utils.GetMaxDayOfMonth(currentTime.Year(), currentTime.Month())
-
-			for i := 0; i < len(frequencyValues); i++ {
-				if frequencyValues[i] < 0 {
-					frequencyValues[i] = int64(maxDayInMonth) + frequencyValues[i] + 1
-				}
-			}
-		}
-
-		frequencyValueSet := utils.ToSet(frequencyValues)
-		templateTimeZone := time.FixedZone("Template Timezone", int(template.ScheduledTimezoneUtcOffset)*60)
-		transactionUnixTime := todayFirstUnixTimeInUTC + int64(template.ScheduledAt)*60
-		transactionTime := time.Unix(transactionUnixTime, 0).In(templateTimeZone)
-
-		if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY && !frequencyValueSet[int64(transactionTime.Weekday())] {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %s", template.TemplateId, startTimeInUTC.Weekday())
-			continue
-		} else if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY && !frequencyValueSet[int64(transactionTime.Day())] {
+		if len(occurrences) < 1 {
 			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %d of month", template.TemplateId, startTimeInUTC.Day())
+			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %s", template.TemplateId, transactionDate.Format("2006-01-02"))
 			continue
-		} else if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY && !frequencyValueSet[int64(transactionTime.Month())*100+int64(transactionTime.Day())] {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %d-%d of year", template.TemplateId, startTimeInUTC.Month(), startTimeInUTC.Day())
-			continue
-		} else if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_EVERY_N_DAYS {
-			if template.ScheduledStartTime == nil || len(frequencyValues) != 1 || frequencyValues[0] <= 0 {
-				skipCount++
-				log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid scheduled transaction frequency for every N days", template.TemplateId)
-				continue
-			}
+		}
 
-			n := frequencyValues[0]
-			startDate := time.Unix(*template.ScheduledStartTime, 0).In(templateTimeZone)
-			startDateOnly := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, templateTimeZone)
-			transactionDateOnly := time.Date(transactionTime.Year(), transactionTime.Month(), transactionTime.Day(), 0, 0, 0, 0, templateTimeZone)
-			daysDiff := int(transactionDateOnly.Sub(startDateOnly).Hours() / 24)
+		for j := 0; j < len(occurrences); j++ {
+			created, err := s.createScheduledTransaction(c, template, occurrences[j])
 
-			if daysDiff < 0 || int64(daysDiff)%n != 0 {
+			if err != nil {
+				failedCount++
+			} else if created {
+				successCount++
+			} else {
 				skipCount++
-				log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, days diff is %d with interval %d", template.TemplateId, daysDiff, n)
-				continue
 			}
 		}
+	}
 
-		if template.ScheduledStartTime != nil && *template.ScheduledStartTime > transactionUnixTime {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, now is earlier than the start time %d", template.TemplateId, *template.ScheduledStartTime)
-			continue
-		}
+	log.Infof(c, "[transactions.CreateScheduledTransactions] %d transactions has been created successfully, %d templates does not need to create transactions and %d transactions failed to create", successCount, skipCount, failedCount)
 
-		if template.ScheduledEndTime != nil && *template.ScheduledEndTime < transactionUnixTime {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, now is later than the end time %d", template.TemplateId, *template.ScheduledEndTime)
-			continue
-		}
+	return nil
+}
 
-		var transactionDbType models.TransactionDbType
+func (s *TransactionService) createScheduledTransaction(c core.Context, template *models.TransactionTemplate, occurrence *models.TransactionScheduleOccurrence) (bool, error) {
+	var transactionDbType models.TransactionDbType
 
-		if template.Type == models.TRANSACTION_TYPE_EXPENSE {
-			transactionDbType = models.TRANSACTION_DB_TYPE_EXPENSE
-		} else if template.Type == models.TRANSACTION_TYPE_INCOME {
-			transactionDbType = models.TRANSACTION_DB_TYPE_INCOME
-		} else if template.Type == models.TRANSACTION_TYPE_TRANSFER {
-			transactionDbType = models.TRANSACTION_DB_TYPE_TRANSFER_OUT
-		} else {
-			skipCount++
-			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid transaction type", template.TemplateId)
-			continue
-		}
+	if template.Type == models.TRANSACTION_TYPE_EXPENSE {
+		transactionDbType = models.TRANSACTION_DB_TYPE_EXPENSE
+	} else if template.Type == models.TRANSACTION_TYPE_INCOME {
+		transactionDbType = models.TRANSACTION_DB_TYPE_INCOME
+	} else if template.Type == models.TRANSACTION_TYPE_TRANSFER {
+		transactionDbType = models.TRANSACTION_DB_TYPE_TRANSFER_OUT
+	} else {
+		log.Warnf(c, "[transactions.createScheduledTransaction] transaction template \"id:%d\" has invalid transaction type", template.TemplateId)
+		return false, nil
+	}
 
-		transaction := &models.Transaction{
-			Uid:               template.Uid,
-			Type:              transactionDbType,
-			CategoryId:        template.CategoryId,
-			TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix()),
-			TimezoneUtcOffset: template.ScheduledTimezoneUtcOffset,
-			AccountId:         template.AccountId,
-			Amount:            template.Amount,
-			HideAmount:        template.HideAmount,
-			Comment:           template.Comment,
-			CreatedIp:         c.ClientIP(),
-			ScheduledCreated:  true,
-		}
+	transactionTime := time.Unix(occurrence.TransactionUnixTime, 0).In(template.GetScheduledTimezone())
 
-		if template.Type == models.TRANSACTION_TYPE_TRANSFER {
-			transaction.RelatedAccountId = template.RelatedAccountId
-			transaction.RelatedAccountAmount = template.RelatedAccountAmount
+	transaction := &models.Transaction{
+		Uid:               template.Uid,
+		Type:              transactionDbType,
+		CategoryId:        template.CategoryId,
+		TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix()),
+		TimezoneUtcOffset: template.ScheduledTimezoneUtcOffset,
+		AccountId:         template.AccountId,
+		Amount:            occurrence.Amount,
+		HideAmount:        template.HideAmount,
+		Comment:           template.Comment,
+		CreatedIp:         c.ClientIP(),
+		CreatorUid:        template.Uid,
+		ScheduledCreated:  true,
+	}
+
+	if template.Type == models.TRANSACTION_TYPE_TRANSFER {
+		transaction.RelatedAccountId = template.RelatedAccountId
+		transaction.RelatedAccountAmount = occurrence.RelatedAccountAmount
+	}
+
+	tagIds := template.GetTagIds()
+
+	if template.Type == models.TRANSACTION_TYPE_TRANSFER {
+		loan, err := s.getLoanOfPaymentTemplate(c, template)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.createScheduledTransaction] transaction template \"id:%d\" failed to get loan of destination account, because %s", template.TemplateId, err.Error())
+			return false, err
 		}
 
-		tagIds := template.GetTagIds()
-		err = s.CreateTransaction(c, transaction, tagIds, nil)
+		if loan != nil {
+			period := loan.GetPeriodByPaymentMonth(transactionTime.Year(), transactionTime.Month())
 
-		if err == nil {
-			successCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has created a new trasaction \"id:%d\"", template.TemplateId, transaction.TransactionId)
-		} else {
-			failedCount++
-			log.Errorf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" failed to create new trasaction, because %s", template.TemplateId, err.Error())
+			if period < 1 {
+				log.Infof(c, "[transactions.createScheduledTransaction] transaction template \"id:%d\" does not need to create loan payment transactions, no payment period in %d-%d", template.TemplateId, transactionTime.Year(), transactionTime.Month())
+				return false, nil
+			}
+
+			err = s.createLoanPaymentTransactions(c, transaction, loan, period, tagIds)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.createScheduledTransaction] transaction template \"id:%d\" failed to create loan payment trasactions of period %d, because %s", template.TemplateId, period, err.Error())
+				return false, err
+			}
+
+			log.Infof(c, "[transactions.createScheduledTransaction] transaction template \"id:%d\" has created loan payment trasactions of period %d", template.TemplateId, period)
+			return true, nil
 		}
 	}
 
-	log.Infof(c, "[transactions.CreateScheduledTransactions] %d transactions has been created successfully, %d templates does not need to create transactions and %d transactions failed to create", successCount, skipCount, failedCount)
+	err := s.CreateTransaction(c, transaction, tagIds, nil, nil)
 
-	return nil
+	if err != nil {
+		log.Errorf(c, "[transactions.createScheduledTransaction] transaction template \"id:%d\" failed to create new trasaction of occurrence %d, because %s", template.TemplateId, occurrence.OriginalDate, err.Error())
+		return false, err
+	}
+
+	log.Infof(c, "[transactions.createScheduledTransaction] transaction template \"id:%d\" has created a new trasaction \"id:%d\" of occurrence %d", template.TemplateId, transaction.TransactionId, occurrence.OriginalDate)
+	return true, nil
 }
 
-// ModifyTransaction saves an existed transaction to database
-func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, changeToTransfer bool, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64) error {
+// ModifyTransaction saves an existed transaction to database, the split lines will be replaced if splits is not nil
+func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, changeToTransfer bool, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64, splits []*models.TransactionSplit) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -982,6 +1046,13 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		return errs.ErrSystemIsBusy
 	}
 
+	needSplitUuidCount := uint16(len(splits))
+	splitUuids := s.GenerateUuids(uuid.UUID_TYPE_SPLIT, needSplitUuidCount)
+
+	if len(splitUuids) < int(needSplitUuidCount) {
+		return errs.ErrSystemIsBusy
+	}
+
 	updateCols := make([]string, 0, 16)
 
 	now := time.Now().Unix()
@@ -1007,6 +1078,10 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		}
 	}
 
+	s.fillTransactionSplits(transaction, splits, splitUuids, now)
+
+	var oldAccountIds []int64
+
 	err := s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
@@ -1031,6 +1106,8 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			return errs.ErrTransactionTypeInvalid
 		}
 
+		oldAccountIds = []int64{oldTransaction.AccountId, oldTransaction.RelatedAccountId}
+
 		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && oldTransaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
 			transaction.RelatedId = oldTransaction.RelatedId
 		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && oldTransaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
@@ -1084,6 +1161,29 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			return errs.ErrCannotAddTransactionToHiddenAccount
 		}
 
+		// Get and verify splits
+		allCurrentSplits, err := s.getTransactionSplitsByTransactionIds(sess, transaction.Uid, []int64{transaction.TransactionId})
+
+		if err != nil {
+			log.Errorf(c, "[transactions.ModifyTransaction] failed to get current transaction splits, because %s", err.Error())
+			return err
+		}
+
+		currentSplits := allCurrentSplits[transaction.TransactionId]
+		newSplits, err := s.getModifiedTransactionSplits(transaction, oldTransaction, splits, currentSplits)
+
+		if err != nil {
+			return err
+		}
+
+		err = s.isSplitsValid(sess, transaction, newSplits)
+
+		if err != nil {
+			return err
+		}
+
+		splitsChanged := !models.TransactionSplitSlice(newSplits).IsEquals(currentSplits)
+
 		// Append modified columns and verify
 		if transaction.Type != oldTransaction.Type || transaction.CategoryId != oldTransaction.CategoryId {
 			// Get and verify category
@@ -1354,6 +1454,32 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			}
 		}
 
+		// Update transaction split
+		if splitsChanged {
+			if len(currentSplits) > 0 {
+				splitUpdateModel := &models.TransactionSplit{
+					Deleted:         true,
+					DeletedUnixTime: now,
+				}
+
+				_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitUpdateModel)
+
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction splits, because %s", err.Error())
+					return err
+				}
+			}
+
+			for i := 0; i < len(newSplits); i++ {
+				_, err := sess.Insert(newSplits[i])
+
+				if err != nil {
+					log.Errorf(c, "[transactions.ModifyTransaction] failed to add new transaction split, because %s", err.Error())
+					return err
+				}
+			}
+		}
+
 		// Update account table
 		if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			if transaction.AccountId != oldTransaction.AccountId {
@@ -1566,6 +1692,9 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		return err
 	}
 
+	webhookEvents.publishTransactionEvent(c, models.WEBHOOK_EVENT_TYPE_TRANSACTION_MODIFIED, transaction)
+	webhookEvents.publishAccountBalanceChangedEvents(c, transaction.Uid, append(oldAccountIds, transaction.AccountId, transaction.RelatedAccountId))
+
 	return nil
 }
 
@@ -1591,7 +1720,12 @@ func (s *TransactionService) BatchUpdateTransactionsCategory(c core.Context, uid
 		UpdatedUnixTime: now,
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		updatedRows, err := sess.Cols("category_id", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", uniqueTransactionIds).Update(updateModel)
 
 		if err != nil {
@@ -1600,8 +1734,19 @@ func (s *TransactionService) BatchUpdateTransactionsCategory(c core.Context, uid
 			return errs.ErrTransactionNotFound
 		}
 
+		// The whole transaction is moved to the new category, so the split lines are no longer valid
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", uniqueTransactionIds).Update(splitUpdateModel)
+
 		return err
 	})
+
+	if err != nil {
+		return err
+	}
+
+	webhookEvents.publishTransactionsModifiedEvents(c, uid, uniqueTransactionIds)
+
+	return nil
 }
 
 // BatchAddTagsToTransactions batch adds tags to transactions
@@ -1674,7 +1819,7 @@ func (s *TransactionService) BatchAddTagsToTransactions(c core.Context, uid int6
 		tagIds = append(tagIds, tagId)
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify tags
 		err := s.isTagsValid(sess, uid, transactionTagIndexes, tagIds)
 
@@ -1693,6 +1838,20 @@ func (s *TransactionService) BatchAddTagsToTransactions(c core.Context, uid int6
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	transactionIds := make([]int64, 0, len(addTransactionTagIds))
+
+	for transactionId := range addTransactionTagIds {
+		transactionIds = append(transactionIds, transactionId)
+	}
+
+	webhookEvents.publishTransactionsModifiedEvents(c, uid, transactionIds)
+
+	return nil
 }
 
 // BatchRemoveTagsFromTransactions batch removes tags from transactions
@@ -1714,7 +1873,7 @@ func (s *TransactionService) BatchRemoveTagsFromTransactions(c core.Context, uid
 		DeletedUnixTime: now,
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", uniqueTransactionIds).In("tag_id", uniqueTagIds).Update(tagIndexUpdateModel)
 
 		if err != nil {
@@ -1725,6 +1884,14 @@ func (s *TransactionService) BatchRemoveTagsFromTransactions(c core.Context, uid
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	webhookEvents.publishTransactionsModifiedEvents(c, uid, uniqueTransactionIds)
+
+	return nil
 }
 
 // BatchClearAllTagsFromTransactions batch clears all tags from transactions
@@ -1745,7 +1912,7 @@ func (s *TransactionService) BatchClearAllTagsFromTransactions(c core.Context, u
 		DeletedUnixTime: now,
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", uniqueTransactionIds).Update(tagIndexUpdateModel)
 
 		if err != nil {
@@ -1756,6 +1923,14 @@ func (s *TransactionService) BatchClearAllTagsFromTransactions(c core.Context, u
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	webhookEvents.publishTransactionsModifiedEvents(c, uid, uniqueTransactionIds)
+
+	return nil
 }
 
 // MoveAllTransactionsBetweenAccounts moves all transactions from one account to another account, and combine balance modification transactions if necessary
@@ -1772,7 +1947,7 @@ func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context,
 		return errs.ErrCannotMoveTransactionToSameAccount
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// get and verify from and to account
 		fromAccount := &models.Account{}
 		has, err := sess.ID(fromAccountId).Where("uid=? AND deleted=?", uid, false).Get(fromAccount)
@@ -2005,6 +2180,14 @@ func (s *TransactionService) MoveAllTransactionsBetweenAccounts(c core.Context,
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	webhookEvents.publishAccountBalanceChangedEvents(c, uid, []int64{fromAccountId, toAccountId})
+
+	return nil
 }
 
 // DeleteTransaction deletes an existed transaction from database
@@ -2030,9 +2213,16 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		DeletedUnixTime: now,
 	}
 
-	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	var oldTransaction *models.Transaction
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
-		oldTransaction := &models.Transaction{}
+		oldTransaction = &models.Transaction{}
 		has, err := sess.ID(transactionId).Where("uid=? AND deleted=?", uid, false).Get(oldTransaction)
 
 		if err != nil {
@@ -2089,6 +2279,13 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 			return err
 		}
 
+		// Update transaction split
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update account table
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			if oldTransaction.RelatedAccountAmount != 0 {
@@ -2156,6 +2353,15 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 
 		return err
 	})
+
+	if err != nil {
+		return err
+	}
+
+	webhookEvents.publishTransactionEvent(c, models.WEBHOOK_EVENT_TYPE_TRANSACTION_DELETED, oldTransaction)
+	webhookEvents.publishAccountBalanceChangedEvents(c, uid, []int64{oldTransaction.AccountId, oldTransaction.RelatedAccountId})
+
+	return nil
 }
 
 // DeleteAllTransactions deletes all existed transactions from database
@@ -2181,6 +2387,11 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 		DeletedUnixTime: now,
 	}
 
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	accountUpdateModel := &models.Account{
 		Balance:         0,
 		Deleted:         deleteAccount,
@@ -2209,6 +2420,13 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 			return err
 		}
 
+		// Update all transaction splits to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update all accounts to deleted or set amount to zero
 		_, err = sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
 
@@ -2289,6 +2507,7 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 		GeoLongitude:         originalTransaction.GeoLongitude,
 		GeoLatitude:          originalTransaction.GeoLatitude,
 		CreatedIp:            originalTransaction.CreatedIp,
+		CreatorUid:           originalTransaction.CreatorUid,
 		CreatedUnixTime:      originalTransaction.CreatedUnixTime,
 		UpdatedUnixTime:      originalTransaction.UpdatedUnixTime,
 		DeletedUnixTime:      originalTransaction.DeletedUnixTime,
@@ -2421,8 +2640,102 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 	return incomeAmounts, expenseAmounts, nil
 }
 
+// GetCreatorsTotalIncomeAndExpense returns the total income and expense amount of every creator in every account by specific time range, the transactions without creator are considered to be created by the owner
+func (s *TransactionService) GetCreatorsTotalIncomeAndExpense(c core.Context, uid int64, startUnixTime int64, endUnixTime int64) ([]*models.TransactionCreatorTotalAmount, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND deleted=? AND (type=? OR type=?)"
+	conditionParams := make([]any, 0, 4)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_INCOME)
+	conditionParams = append(conditionParams, models.TRANSACTION_DB_TYPE_EXPENSE)
+
+	var minTransactionTime, maxTransactionTime int64
+
+	if startUnixTime > 0 {
+		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(startUnixTime)
+	}
+
+	if endUnixTime > 0 {
+		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(endUnixTime)
+	}
+
+	var allTransactions []*models.Transaction
+
+	for maxTransactionTime >= 0 {
+		var transactions []*models.Transaction
+
+		finalCondition := condition
+		finalConditionParams := make([]any, 0, 6)
+		finalConditionParams = append(finalConditionParams, conditionParams...)
+
+		if minTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time>=?"
+			finalConditionParams = append(finalConditionParams, minTransactionTime)
+		}
+
+		if maxTransactionTime > 0 {
+			finalCondition = finalCondition + " AND transaction_time<=?"
+			finalConditionParams = append(finalConditionParams, maxTransactionTime)
+		}
+
+		err := s.UserDataDB(uid).NewSession(c).Select("type, account_id, transaction_time, amount, creator_uid").Where(finalCondition, finalConditionParams...).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allTransactions = append(allTransactions, transactions...)
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			maxTransactionTime = -1
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	totalAmountsMap := make(map[string]*models.TransactionCreatorTotalAmount)
+	totalAmounts := make([]*models.TransactionCreatorTotalAmount, 0)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		creatorUid := transaction.CreatorUid
+
+		if creatorUid == 0 {
+			creatorUid = uid
+		}
+
+		key := fmt.Sprintf("%d_%d", creatorUid, transaction.AccountId)
+		totalAmount, exists := totalAmountsMap[key]
+
+		if !exists {
+			totalAmount = &models.TransactionCreatorTotalAmount{
+				CreatorUid: creatorUid,
+				AccountId:  transaction.AccountId,
+			}
+
+			totalAmountsMap[key] = totalAmount
+			totalAmounts = append(totalAmounts, totalAmount)
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			totalAmount.TotalIncomeAmount += transaction.Amount
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			totalAmount.TotalExpenseAmount += transaction.Amount
+		}
+
+		totalAmount.TransactionCount++
+	}
+
+	return totalAmounts, nil
+}
+
 // GetAccountsAndCategoriesTotalInflowAndOutflow returns the every accounts and categories total inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, matchMode core.MatchMode, clientTimezone *time.Location, useTransactionTimezone bool) ([]*models.TransactionTotalAmount, error) {
+func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, matchMode core.MatchMode, clientTimezone *time.Location, useTransactionTimezone bool, exchangeRateConverter *models.HistoricalExchangeRateConverter) ([]*models.TransactionTotalAmount, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -2453,6 +2766,7 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 	minTransactionTime := startTransactionTime
 	maxTransactionTime := endTransactionTime
 	var allTransactions []*models.Transaction
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit)
 
 	for maxTransactionTime >= 0 {
 		var transactions []*models.Transaction
@@ -2480,7 +2794,7 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
@@ -2489,6 +2803,16 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			return nil, err
 		}
 
+		transactionSplits, err := s.getTransactionSplitsByTransactionIds(s.UserDataDB(uid).NewSession(c), uid, s.getIncomeOrExpenseTransactionIds(transactions))
+
+		if err != nil {
+			return nil, err
+		}
+
+		for transactionId, splits := range transactionSplits {
+			allTransactionSplits[transactionId] = splits
+		}
+
 		allTransactions = append(allTransactions, transactions...)
 
 		if len(transactions) < pageCountForLoadTransactionAmounts {
@@ -2515,27 +2839,36 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 			continue
 		}
 
-		groupKey := fmt.Sprintf("%d_%d", transaction.CategoryId, transaction.AccountId)
+		categoryAmounts := s.getTransactionCategoryAmounts(transaction, allTransactionSplits[transaction.TransactionId])
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			groupKey = fmt.Sprintf("%d_%d_%d_%d", transaction.CategoryId, transaction.AccountId, transaction.RelatedAccountId, transaction.Type)
-		}
+		for j := 0; j < len(categoryAmounts); j++ {
+			categoryId := categoryAmounts[j].CategoryId
+			groupKey := fmt.Sprintf("%d_%d", categoryId, transaction.AccountId)
+
+			if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+				groupKey = fmt.Sprintf("%d_%d_%d_%d", categoryId, transaction.AccountId, transaction.RelatedAccountId, transaction.Type)
+			}
 
-		totalAmounts, exists := transactionTotalAmountsMap[groupKey]
+			totalAmounts, exists := transactionTotalAmountsMap[groupKey]
 
-		if !exists {
-			totalAmounts = &models.TransactionTotalAmount{
-				Type:             transaction.Type,
-				CategoryId:       transaction.CategoryId,
-				AccountId:        transaction.AccountId,
-				RelatedAccountId: transaction.RelatedAccountId,
-				Amount:           big.NewInt(0),
+			if !exists {
+				totalAmounts = &models.TransactionTotalAmount{
+					Type:             transaction.Type,
+					CategoryId:       categoryId,
+					AccountId:        transaction.AccountId,
+					RelatedAccountId: transaction.RelatedAccountId,
+					Amount:           big.NewInt(0),
+				}
+
+				transactionTotalAmountsMap[groupKey] = totalAmounts
 			}
 
-			transactionTotalAmountsMap[groupKey] = totalAmounts
-		}
+			totalAmounts.Amount.Add(totalAmounts.Amount, big.NewInt(categoryAmounts[j].Amount))
 
-		totalAmounts.Amount.Add(totalAmounts.Amount, big.NewInt(transaction.Amount))
+			if exchangeRateConverter != nil {
+				s.addTotalAmountInDefaultCurrency(totalAmounts, transaction, categoryAmounts[j].Amount, timeZone, exchangeRateConverter)
+			}
+		}
 	}
 
 	transactionTotalAmounts := make([]*models.TransactionTotalAmount, 0, len(transactionTotalAmountsMap))
@@ -2548,7 +2881,7 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalInflowAndOutflow(c cor
 }
 
 // GetAccountsAndCategoriesMonthlyInflowAndOutflow returns the every accounts monthly inflows and outflows amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, matchMode core.MatchMode, clientTimezone *time.Location, useTransactionTimezone bool) (map[int32][]*models.TransactionTotalAmount, error) {
+func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagFilters []*models.TransactionTagFilter, noTags bool, keyword string, matchMode core.MatchMode, clientTimezone *time.Location, useTransactionTimezone bool, exchangeRateConverter *models.HistoricalExchangeRateConverter) (map[int32][]*models.TransactionTotalAmount, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -2584,6 +2917,7 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 	minTransactionTime := startTransactionTime
 	maxTransactionTime := endTransactionTime
 	var allTransactions []*models.Transaction
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit)
 
 	for maxTransactionTime >= 0 {
 		var transactions []*models.Transaction
@@ -2611,7 +2945,7 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, related_account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagFilters, noTags)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
@@ -2620,6 +2954,16 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			return nil, err
 		}
 
+		transactionSplits, err := s.getTransactionSplitsByTransactionIds(s.UserDataDB(uid).NewSession(c), uid, s.getIncomeOrExpenseTransactionIds(transactions))
+
+		if err != nil {
+			return nil, err
+		}
+
+		for transactionId, splits := range transactionSplits {
+			allTransactionSplits[transactionId] = splits
+		}
+
 		allTransactions = append(allTransactions, transactions...)
 
 		if len(transactions) < pageCountForLoadTransactionAmounts {
@@ -2649,27 +2993,36 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyInflowAndOutflow(c c
 			continue
 		}
 
-		groupKey := fmt.Sprintf("%d_%d_%d", yearMonth, transaction.CategoryId, transaction.AccountId)
+		categoryAmounts := s.getTransactionCategoryAmounts(transaction, allTransactionSplits[transaction.TransactionId])
 
-		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-			groupKey = fmt.Sprintf("%d_%d_%d_%d_%d", yearMonth, transaction.CategoryId, transaction.AccountId, transaction.RelatedAccountId, transaction.Type)
-		}
+		for j := 0; j < len(categoryAmounts); j++ {
+			categoryId := categoryAmounts[j].CategoryId
+			groupKey := fmt.Sprintf("%d_%d_%d", yearMonth, categoryId, transaction.AccountId)
 
-		transactionAmounts, exists := transactionsMonthlyAmountsMap[groupKey]
+			if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+				groupKey = fmt.Sprintf("%d_%d_%d_%d_%d", yearMonth, categoryId, transaction.AccountId, transaction.RelatedAccountId, transaction.Type)
+			}
 
-		if !exists {
-			transactionAmounts = &models.TransactionTotalAmount{
-				Type:             transaction.Type,
-				CategoryId:       transaction.CategoryId,
-				AccountId:        transaction.AccountId,
-				RelatedAccountId: transaction.RelatedAccountId,
-				Amount:           big.NewInt(0),
+			transactionAmounts, exists := transactionsMonthlyAmountsMap[groupKey]
+
+			if !exists {
+				transactionAmounts = &models.TransactionTotalAmount{
+					Type:             transaction.Type,
+					CategoryId:       categoryId,
+					AccountId:        transaction.AccountId,
+					RelatedAccountId: transaction.RelatedAccountId,
+					Amount:           big.NewInt(0),
+				}
+
+				transactionsMonthlyAmountsMap[groupKey] = transactionAmounts
 			}
 
-			transactionsMonthlyAmountsMap[groupKey] = transactionAmounts
-		}
+			transactionAmounts.Amount.Add(transactionAmounts.Amount, big.NewInt(categoryAmounts[j].Amount))
 
-		transactionAmounts.Amount.Add(transactionAmounts.Amount, big.NewInt(transaction.Amount))
+			if exchangeRateConverter != nil {
+				s.addTotalAmountInDefaultCurrency(transactionAmounts, transaction, categoryAmounts[j].Amount, timeZone, exchangeRateConverter)
+			}
+		}
 	}
 
 	for groupKey, transaction := range transactionsMonthlyAmountsMap {
@@ -2711,7 +3064,87 @@ func (s *TransactionService) GetTransactionIds(transactions []*models.Transactio
 	return transactionIds
 }
 
-func (s *TransactionService) doCreateTransaction(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo) error {
+func (s *TransactionService) getLoanOfPaymentTemplate(c core.Context, template *models.TransactionTemplate) (*models.AccountLoanInfo, error) {
+	loanAccount := &models.Account{}
+	has, err := s.UserDataDB(template.Uid).NewSession(c).ID(template.RelatedAccountId).Where("uid=? AND deleted=?", template.Uid, false).Get(loanAccount)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+
+	if loanAccount.Category != models.ACCOUNT_CATEGORY_DEBT || loanAccount.Extend == nil || loanAccount.Extend.Loan == nil || loanAccount.Extend.Loan.PaymentTemplateId != template.TemplateId {
+		return nil, nil
+	}
+
+	return loanAccount.Extend.Loan, nil
+}
+
+func (s *TransactionService) createLoanPaymentTransactions(c core.Context, paymentTransaction *models.Transaction, loan *models.AccountLoanInfo, period int32, tagIds []int64) error {
+	schedule := loan.GetAmortizationSchedule()
+
+	if period < 1 || int(period) > len(schedule) {
+		return errs.ErrLoanNotFound
+	}
+
+	item := schedule[period-1]
+	transactions := make([]*models.Transaction, 0, 2)
+	allTagIds := make(map[int][]int64, 2)
+
+	if item.Principal > 0 {
+		paymentTransaction.Amount = item.Principal
+		paymentTransaction.RelatedAccountAmount = item.Principal
+		allTagIds[len(transactions)] = tagIds
+		transactions = append(transactions, paymentTransaction)
+	}
+
+	if item.Interest > 0 {
+		interestTransaction := &models.Transaction{
+			Uid:               paymentTransaction.Uid,
+			Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+			CategoryId:        loan.InterestCategoryId,
+			TransactionTime:   paymentTransaction.TransactionTime,
+			TimezoneUtcOffset: paymentTransaction.TimezoneUtcOffset,
+			AccountId:         paymentTransaction.AccountId,
+			Amount:            item.Interest,
+			HideAmount:        paymentTransaction.HideAmount,
+			Comment:           paymentTransaction.Comment,
+			CreatedIp:         paymentTransaction.CreatedIp,
+			CreatorUid:        paymentTransaction.CreatorUid,
+			ScheduledCreated:  true,
+		}
+
+		allTagIds[len(transactions)] = tagIds
+		transactions = append(transactions, interestTransaction)
+	}
+
+	if len(transactions) < 1 {
+		return nil
+	}
+
+	return s.BatchCreateTransactions(c, paymentTransaction.Uid, transactions, allTagIds, nil, nil)
+}
+
+func (s *TransactionService) fillTransactionSplits(transaction *models.Transaction, splits []*models.TransactionSplit, splitUuids []int64, now int64) {
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		split.SplitId = splitUuids[i]
+		split.Uid = transaction.Uid
+		split.Deleted = false
+		split.TransactionId = transaction.TransactionId
+		split.DisplayOrder = int32(i + 1)
+		split.CreatedUnixTime = now
+		split.UpdatedUnixTime = now
+	}
+
+	// The category of split transaction is the category of its first split line
+	if len(splits) > 0 {
+		transaction.CategoryId = splits[0].CategoryId
+	}
+}
+
+func (s *TransactionService) doCreateTransaction(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo, transactionSplits []*models.TransactionSplit) error {
 	// Get and verify source and destination account
 	sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
 
@@ -2758,6 +3191,13 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		return err
 	}
 
+	// Get and verify splits
+	err = s.isSplitsValid(sess, transaction, transactionSplits)
+
+	if err != nil {
+		return err
+	}
+
 	// Verify balance modification transaction and calculate real amount
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		otherTransactionExists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=?", transaction.Uid, false, sourceAccount.AccountId).Limit(1).Exist(&models.Transaction{})
@@ -2898,6 +3338,18 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		}
 	}
 
+	// Insert transaction split
+	if len(transactionSplits) > 0 {
+		for i := 0; i < len(transactionSplits); i++ {
+			_, err := sess.Insert(transactionSplits[i])
+
+			if err != nil {
+				log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction split, because %s", err.Error())
+				return err
+			}
+		}
+	}
+
 	// Update account table
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		if transaction.RelatedAccountAmount != 0 {
@@ -3388,46 +3840,188 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 			return errs.ErrBalanceModificationTransactionCannotSetCategory
 		}
 	} else {
-		category := &models.TransactionCategory{}
-		has, err := sess.ID(transaction.CategoryId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(category)
+		return s.isCategoryIdValid(sess, transaction.Uid, transaction.Type, transaction.CategoryId)
+	}
 
-		if err != nil {
-			return err
-		} else if !has {
-			return errs.ErrTransactionCategoryNotFound
-		}
+	return nil
+}
 
-		if category.Hidden {
-			return errs.ErrCannotUseHiddenTransactionCategory
-		}
+func (s *TransactionService) isCategoryIdValid(sess *xorm.Session, uid int64, transactionType models.TransactionDbType, categoryId int64) error {
+	category := &models.TransactionCategory{}
+	has, err := sess.ID(categoryId).Where("uid=? AND deleted=?", uid, false).Get(category)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrTransactionCategoryNotFound
+	}
+
+	if category.Hidden {
+		return errs.ErrCannotUseHiddenTransactionCategory
+	}
+
+	if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		return errs.ErrCannotUsePrimaryCategoryForTransaction
+	}
+
+	if (transactionType == models.TRANSACTION_DB_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_INCOME) ||
+		(transactionType == models.TRANSACTION_DB_TYPE_EXPENSE && category.Type != models.CATEGORY_TYPE_EXPENSE) ||
+		((transactionType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transactionType == models.TRANSACTION_DB_TYPE_TRANSFER_IN) && category.Type != models.CATEGORY_TYPE_TRANSFER) {
+		return errs.ErrTransactionCategoryTypeInvalid
+	}
+
+	parentCategory := &models.TransactionCategory{}
+	has, err = sess.ID(category.ParentCategoryId).Where("uid=? AND deleted=?", uid, false).Get(parentCategory)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrTransactionCategoryNotFound
+	}
+
+	if parentCategory.Hidden {
+		return errs.ErrCannotUseHiddenTransactionCategory
+	}
+
+	return nil
+}
+
+// getModifiedTransactionSplits returns the splits which the modified transaction should have, the current splits are kept if no splits are specified and the type, category and amount of transaction are not changed
+func (s *TransactionService) getModifiedTransactionSplits(transaction *models.Transaction, oldTransaction *models.Transaction, splits []*models.TransactionSplit, currentSplits []*models.TransactionSplit) ([]*models.TransactionSplit, error) {
+	if splits != nil {
+		return splits, nil
+	}
 
-		if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
-			return errs.ErrCannotUsePrimaryCategoryForTransaction
+	if transaction.Type != oldTransaction.Type || transaction.CategoryId != oldTransaction.CategoryId {
+		return nil, nil
+	}
+
+	if len(currentSplits) > 0 && transaction.Amount != oldTransaction.Amount {
+		return nil, errs.ErrTransactionSplitsRequiredWhenAmountChanged
+	}
+
+	return currentSplits, nil
+}
+
+func (s *TransactionService) isSplitsValid(sess *xorm.Session, transaction *models.Transaction, splits []*models.TransactionSplit) error {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return errs.ErrTransactionSplitNotAllowed
+	}
+
+	if len(splits) < models.MinimumSplitsCountOfTransaction {
+		return errs.ErrTransactionSplitsTooFew
+	}
+
+	if len(splits) > models.MaximumSplitsCountOfTransaction {
+		return errs.ErrTransactionHasTooManySplits
+	}
+
+	totalAmount := int64(0)
+	validCategoryIds := make(map[int64]bool, len(splits))
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+
+		if split.Amount < models.MinimumTransactionAmount || split.Amount > models.MaximumTransactionAmount {
+			return errs.ErrAmountInvalid
 		}
 
-		if (transaction.Type == models.TRANSACTION_DB_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_INCOME) ||
-			(transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE && category.Type != models.CATEGORY_TYPE_EXPENSE) ||
-			((transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) && category.Type != models.CATEGORY_TYPE_TRANSFER) {
-			return errs.ErrTransactionCategoryTypeInvalid
+		totalAmount += split.Amount
+
+		if validCategoryIds[split.CategoryId] {
+			continue
 		}
 
-		parentCategory := &models.TransactionCategory{}
-		has, err = sess.ID(category.ParentCategoryId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(parentCategory)
+		err := s.isCategoryIdValid(sess, transaction.Uid, transaction.Type, split.CategoryId)
 
 		if err != nil {
 			return err
-		} else if !has {
-			return errs.ErrTransactionCategoryNotFound
 		}
 
-		if parentCategory.Hidden {
-			return errs.ErrCannotUseHiddenTransactionCategory
-		}
+		validCategoryIds[split.CategoryId] = true
+	}
+
+	if totalAmount != transaction.Amount {
+		return errs.ErrTransactionSplitAmountsNotEqual
 	}
 
 	return nil
 }
 
+func (s *TransactionService) getIncomeOrExpenseTransactionIds(transactions []*models.Transaction) []int64 {
+	transactionIds := make([]int64, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i].Type == models.TRANSACTION_DB_TYPE_INCOME || transactions[i].Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			transactionIds = append(transactionIds, transactions[i].TransactionId)
+		}
+	}
+
+	return transactionIds
+}
+
+// getTransactionCategoryAmounts returns the split lines of transaction, or a single line with the category and amount of transaction if it has no split
+func (s *TransactionService) getTransactionCategoryAmounts(transaction *models.Transaction, splits []*models.TransactionSplit) []*models.TransactionSplit {
+	if len(splits) > 0 {
+		return splits
+	}
+
+	return []*models.TransactionSplit{
+		{
+			CategoryId: transaction.CategoryId,
+			Amount:     transaction.Amount,
+		},
+	}
+}
+
+// addTotalAmountInDefaultCurrency adds the amount converted at the exchange rates of the transaction date to the total amount in default currency
+func (s *TransactionService) addTotalAmountInDefaultCurrency(totalAmounts *models.TransactionTotalAmount, transaction *models.Transaction, amount int64, timeZone *time.Location, exchangeRateConverter *models.HistoricalExchangeRateConverter) {
+	if totalAmounts.ExchangeRateMissing {
+		return
+	}
+
+	yearMonthDay := utils.FormatUnixTimeToNumericYearMonthDay(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timeZone)
+	amountInDefaultCurrency, converted := exchangeRateConverter.ConvertAmount(transaction.AccountId, amount, yearMonthDay)
+
+	if !converted {
+		totalAmounts.AmountInDefaultCurrency = nil
+		totalAmounts.ExchangeRateMissing = true
+		return
+	}
+
+	if totalAmounts.AmountInDefaultCurrency == nil {
+		totalAmounts.AmountInDefaultCurrency = big.NewInt(0)
+	}
+
+	totalAmounts.AmountInDefaultCurrency.Add(totalAmounts.AmountInDefaultCurrency, big.NewInt(amountInDefaultCurrency))
+}
+
+func (s *TransactionService) getTransactionSplitsByTransactionIds(sess *xorm.Session, uid int64, transactionIds []int64) (map[int64][]*models.TransactionSplit, error) {
+	allSplits := make(map[int64][]*models.TransactionSplit)
+
+	if len(transactionIds) < 1 {
+		return allSplits, nil
+	}
+
+	var splits []*models.TransactionSplit
+	err := sess.Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).OrderBy("display_order asc").Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		allSplits[split.TransactionId] = append(allSplits[split.TransactionId], split)
+	}
+
+	return allSplits, nil
+}
+
 func (s *TransactionService) isTagsValid(sess *xorm.Session, uid int64, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64) error {
 	if len(transactionTagIndexes) > 0 {
 		var tags []*models.TransactionTag
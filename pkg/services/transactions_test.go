@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestGetModifiedTransactionSplits_SplitsSpecified(t *testing.T) {
+	oldTransaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, Amount: 300}
+	transaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, Amount: 500}
+	currentSplits := []*models.TransactionSplit{{CategoryId: 2, Amount: 100}, {CategoryId: 3, Amount: 200}}
+	splits := []*models.TransactionSplit{{CategoryId: 2, Amount: 200}, {CategoryId: 3, Amount: 300}}
+
+	actualSplits, err := Transactions.getModifiedTransactionSplits(transaction, oldTransaction, splits, currentSplits)
+	assert.Nil(t, err)
+	assert.Equal(t, splits, actualSplits)
+}
+
+func TestGetModifiedTransactionSplits_NilSplitsAndNothingChanged(t *testing.T) {
+	oldTransaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, Amount: 300}
+	transaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, Amount: 300}
+	currentSplits := []*models.TransactionSplit{{CategoryId: 2, Amount: 100}, {CategoryId: 3, Amount: 200}}
+
+	actualSplits, err := Transactions.getModifiedTransactionSplits(transaction, oldTransaction, nil, currentSplits)
+	assert.Nil(t, err)
+	assert.Equal(t, currentSplits, actualSplits)
+}
+
+func TestGetModifiedTransactionSplits_NilSplitsAndAmountChanged(t *testing.T) {
+	oldTransaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, Amount: 300}
+	transaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, Amount: 500}
+	currentSplits := []*models.TransactionSplit{{CategoryId: 2, Amount: 100}, {CategoryId: 3, Amount: 200}}
+
+	actualSplits, err := Transactions.getModifiedTransactionSplits(transaction, oldTransaction, nil, currentSplits)
+	assert.Equal(t, errs.ErrTransactionSplitsRequiredWhenAmountChanged, err)
+	assert.Nil(t, actualSplits)
+}
+
+func TestGetModifiedTransactionSplits_NilSplitsAndAmountChangedWithoutCurrentSplits(t *testing.T) {
+	oldTransaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, Amount: 300}
+	transaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, Amount: 500}
+
+	actualSplits, err := Transactions.getModifiedTransactionSplits(transaction, oldTransaction, nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, actualSplits)
+}
+
+func TestGetModifiedTransactionSplits_NilSplitsAndCategoryChanged(t *testing.T) {
+	oldTransaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1, Amount: 300}
+	transaction := &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 4, Amount: 500}
+	currentSplits := []*models.TransactionSplit{{CategoryId: 2, Amount: 100}, {CategoryId: 3, Amount: 200}}
+
+	actualSplits, err := Transactions.getModifiedTransactionSplits(transaction, oldTransaction, nil, currentSplits)
+	assert.Nil(t, err)
+	assert.Nil(t, actualSplits)
+}
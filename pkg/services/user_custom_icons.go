@@ -50,6 +50,34 @@ func (s *UserCustomIconService) GetTotalCustomIconsCountByUid(c core.Context, ui
 	return count, err
 }
 
+// GetTotalCustomIconsSizeByUid returns total size in bytes of custom icons of user
+func (s *UserCustomIconService) GetTotalCustomIconsSizeByUid(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	var customIcons []*models.UserCustomIcon
+	err := s.UserDataDB(uid).NewSession(c).Cols("icon_id", "uid").Where("uid=? AND deleted=?", uid, false).Find(&customIcons)
+
+	if err != nil {
+		return 0, err
+	}
+
+	totalSize := int64(0)
+
+	for i := 0; i < len(customIcons); i++ {
+		size, err := s.GetUserCustomIconSize(c, customIcons[i].Uid, customIcons[i].IconId)
+
+		if err != nil {
+			return 0, err
+		}
+
+		totalSize += size
+	}
+
+	return totalSize, nil
+}
+
 // GetAllCustomIconInfosByUid returns all custom icons of specified user
 func (s *UserCustomIconService) GetAllCustomIconInfosByUid(c core.Context, uid int64) ([]*models.UserCustomIcon, error) {
 	if uid <= 0 {
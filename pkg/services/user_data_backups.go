@@ -0,0 +1,1022 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// UserDataBackupService represents user data backup service
+type UserDataBackupService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingUuid
+	ServiceUsingStorage
+}
+
+// userDataBackupIdMapping represents the mapping from the ids in backup archive to the new ids
+type userDataBackupIdMapping struct {
+	accounts     map[int64]int64
+	categories   map[int64]int64
+	tagGroups    map[int64]int64
+	tags         map[int64]int64
+	transactions map[int64]int64
+	tagIndexes   map[int64]int64
+	splits       map[int64]int64
+	templates    map[int64]int64
+	exceptions   map[int64]int64
+	pictures     map[int64]int64
+	explorers    map[int64]int64
+	customIcons  map[int64]int64
+	budgets      map[int64]int64
+	securities   map[int64]int64
+	investments  map[int64]int64
+	prices       map[int64]int64
+	rules        map[int64]int64
+}
+
+// Initialize a user data backup service singleton instance
+var (
+	UserDataBackups = &UserDataBackupService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		ServiceUsingStorage: ServiceUsingStorage{
+			container: storage.Container,
+		},
+	}
+)
+
+// GetUserDataBackup returns all data of specified user which need to be stored in backup archive
+func (s *UserDataBackupService) GetUserDataBackup(c core.Context, uid int64) (*models.UserDataBackup, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	backup := &models.UserDataBackup{
+		Version:          models.UserDataBackupCurrentVersion,
+		ExportedUnixTime: time.Now().Unix(),
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("parent_account_id asc, display_order asc").Find(&backup.Accounts); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("type asc, parent_category_id asc, display_order asc").Find(&backup.TransactionCategories); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.TransactionTagGroups); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("tag_group_id asc, display_order asc").Find(&backup.TransactionTags); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_time asc").Find(&backup.Transactions); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).Find(&backup.TransactionTagIndexes); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_id asc, display_order asc").Find(&backup.TransactionSplits); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("template_type asc, display_order asc").Find(&backup.TransactionTemplates); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("template_id asc, occurrence_date asc").Find(&backup.TransactionScheduleExceptions); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=? AND transaction_id<>?", uid, false, models.TransactionPictureNewPictureTransactionId).Find(&backup.TransactionPictureInfos); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.InsightsExplorers); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted_unix_time=?", uid, 0).Find(&backup.UserCustomExchangeRates); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.UserCustomIcons); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.Budgets); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).Find(&backup.InvestmentSecurities); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_time asc, investment_transaction_id asc").Find(&backup.InvestmentTransactions); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("price_time asc, price_id asc").Find(&backup.SecurityPrices); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.TransactionRules); err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// ExportBackupArchive returns the backup archive which contains all data, custom icons and transaction pictures of specified user
+func (s *UserDataBackupService) ExportBackupArchive(c core.Context, uid int64) ([]byte, error) {
+	backup, err := s.GetUserDataBackup(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	backupData, err := json.Marshal(backup)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buffer)
+
+	if err := s.writeArchiveFile(zipWriter, models.UserDataBackupDataFileName, bytes.NewReader(backupData)); err != nil {
+		return nil, err
+	}
+
+	if s.CurrentConfig().EnableUserCustomIcon {
+		for i := 0; i < len(backup.UserCustomIcons); i++ {
+			iconId := backup.UserCustomIcons[i].IconId
+			object, err := s.ReadUserCustomIcon(c, uid, iconId)
+
+			if err != nil {
+				log.Warnf(c, "[user_data_backups.ExportBackupArchive] failed to read custom icon \"id:%d\" for user \"uid:%d\", because %s", iconId, uid, err.Error())
+				continue
+			}
+
+			err = s.writeArchiveFile(zipWriter, s.getCustomIconFileName(iconId), object)
+			object.Close()
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if s.CurrentConfig().EnableTransactionPictures {
+		for i := 0; i < len(backup.TransactionPictureInfos); i++ {
+			pictureInfo := backup.TransactionPictureInfos[i]
+			object, err := s.ReadTransactionPicture(c, uid, pictureInfo.PictureId, pictureInfo.PictureExtension)
+
+			if err != nil {
+				log.Warnf(c, "[user_data_backups.ExportBackupArchive] failed to read transaction picture \"id:%d\" for user \"uid:%d\", because %s", pictureInfo.PictureId, uid, err.Error())
+				continue
+			}
+
+			err = s.writeArchiveFile(zipWriter, s.getTransactionPictureFileName(pictureInfo.PictureId, pictureInfo.PictureExtension), object)
+			object.Close()
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// ParseBackupArchive returns the user data and the files in specified backup archive, the uncompressed data file in archive cannot be larger than the specified max size
+func (s *UserDataBackupService) ParseBackupArchive(data []byte, maxDataFileSize uint32) (*models.UserDataBackup, map[string]*zip.File, error) {
+	if len(data) < 1 {
+		return nil, nil, errs.ErrBackupFileEmpty
+	}
+
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		return nil, nil, errs.ErrBackupFileInvalid
+	}
+
+	files := make(map[string]*zip.File, len(zipReader.File))
+
+	for i := 0; i < len(zipReader.File); i++ {
+		files[zipReader.File[i].Name] = zipReader.File[i]
+	}
+
+	if _, exists := files[models.UserDataBackupDataFileName]; !exists {
+		return nil, nil, errs.ErrBackupFileInvalid
+	}
+
+	backupData, err := s.readArchiveFile(files, models.UserDataBackupDataFileName, maxDataFileSize)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backup := &models.UserDataBackup{}
+
+	if err := json.Unmarshal(backupData, backup); err != nil {
+		return nil, nil, errs.ErrBackupFileInvalid
+	}
+
+	if backup.Version < 1 || backup.Version > models.UserDataBackupCurrentVersion {
+		return nil, nil, errs.ErrBackupVersionNotSupported
+	}
+
+	return backup, files, nil
+}
+
+// RestoreBackupArchive restores all data, custom icons and transaction pictures in specified backup archive into the user which does not have any data
+func (s *UserDataBackupService) RestoreBackupArchive(c core.Context, uid int64, data []byte) (*models.UserDataBackup, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	backup, files, err := s.ParseBackupArchive(data, s.CurrentConfig().MaxBackupDataFileSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	isEmpty, err := s.isUserDataEmpty(c, uid)
+
+	if err != nil {
+		return nil, err
+	} else if !isEmpty {
+		return nil, errs.ErrRestoreUserDataNotEmpty
+	}
+
+	idMapping, err := s.createIdMapping(backup)
+
+	if err != nil {
+		return nil, err
+	}
+
+	originalCustomIconIds := make([]int64, len(backup.UserCustomIcons))
+	originalPictureIds := make([]int64, len(backup.TransactionPictureInfos))
+
+	for i := 0; i < len(backup.UserCustomIcons); i++ {
+		originalCustomIconIds[i] = backup.UserCustomIcons[i].IconId
+	}
+
+	for i := 0; i < len(backup.TransactionPictureInfos); i++ {
+		originalPictureIds[i] = backup.TransactionPictureInfos[i].PictureId
+	}
+
+	if err := s.remapUserDataBackup(uid, backup, idMapping); err != nil {
+		return nil, err
+	}
+
+	if s.CurrentConfig().EnableUserCustomIcon {
+		for i := 0; i < len(backup.UserCustomIcons); i++ {
+			fileData, err := s.readArchiveFile(files, s.getCustomIconFileName(originalCustomIconIds[i]), s.CurrentConfig().MaxUserCustomIconFileSize)
+
+			if err != nil {
+				return nil, err
+			} else if fileData == nil {
+				log.Warnf(c, "[user_data_backups.RestoreBackupArchive] custom icon \"id:%d\" does not exist in backup archive for user \"uid:%d\"", originalCustomIconIds[i], uid)
+				continue
+			}
+
+			if err := s.SaveUserCustomIcon(c, uid, backup.UserCustomIcons[i].IconId, storage.NewByteSliceObject(fileData)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if s.CurrentConfig().EnableTransactionPictures {
+		for i := 0; i < len(backup.TransactionPictureInfos); i++ {
+			pictureInfo := backup.TransactionPictureInfos[i]
+			fileData, err := s.readArchiveFile(files, s.getTransactionPictureFileName(originalPictureIds[i], pictureInfo.PictureExtension), s.CurrentConfig().MaxTransactionPictureFileSize)
+
+			if err != nil {
+				return nil, err
+			} else if fileData == nil {
+				log.Warnf(c, "[user_data_backups.RestoreBackupArchive] transaction picture \"id:%d\" does not exist in backup archive for user \"uid:%d\"", originalPictureIds[i], uid)
+				continue
+			}
+
+			if err := s.SaveTransactionPicture(c, uid, pictureInfo.PictureId, storage.NewByteSliceObject(fileData), pictureInfo.PictureExtension); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(backup.UserCustomIcons); i++ {
+			if _, err := sess.Insert(backup.UserCustomIcons[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Accounts); i++ {
+			if _, err := sess.Insert(backup.Accounts[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TransactionCategories); i++ {
+			if _, err := sess.Insert(backup.TransactionCategories[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TransactionTagGroups); i++ {
+			if _, err := sess.Insert(backup.TransactionTagGroups[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TransactionTags); i++ {
+			if _, err := sess.Insert(backup.TransactionTags[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Transactions); i++ {
+			if _, err := sess.Insert(backup.Transactions[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TransactionTagIndexes); i++ {
+			if _, err := sess.Insert(backup.TransactionTagIndexes[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TransactionSplits); i++ {
+			if _, err := sess.Insert(backup.TransactionSplits[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TransactionPictureInfos); i++ {
+			if _, err := sess.Insert(backup.TransactionPictureInfos[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TransactionTemplates); i++ {
+			if _, err := sess.Insert(backup.TransactionTemplates[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TransactionScheduleExceptions); i++ {
+			if _, err := sess.Insert(backup.TransactionScheduleExceptions[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.InsightsExplorers); i++ {
+			if _, err := sess.Insert(backup.InsightsExplorers[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.UserCustomExchangeRates); i++ {
+			if _, err := sess.Insert(backup.UserCustomExchangeRates[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Budgets); i++ {
+			if _, err := sess.Insert(backup.Budgets[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.InvestmentSecurities); i++ {
+			if _, err := sess.Insert(backup.InvestmentSecurities[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.InvestmentTransactions); i++ {
+			if _, err := sess.Insert(backup.InvestmentTransactions[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.SecurityPrices); i++ {
+			if _, err := sess.Insert(backup.SecurityPrices[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TransactionRules); i++ {
+			if _, err := sess.Insert(backup.TransactionRules[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+func (s *UserDataBackupService) isUserDataEmpty(c core.Context, uid int64) (bool, error) {
+	sess := s.UserDataDB(uid).NewSession(c)
+	beans := []any{
+		&models.Account{},
+		&models.TransactionCategory{},
+		&models.TransactionTagGroup{},
+		&models.TransactionTag{},
+		&models.Transaction{},
+		&models.TransactionTemplate{},
+		&models.InsightsExplorer{},
+		&models.UserCustomIcon{},
+		&models.Budget{},
+		&models.InvestmentSecurity{},
+		&models.InvestmentTransaction{},
+		&models.SecurityPrice{},
+		&models.TransactionRule{},
+	}
+
+	for i := 0; i < len(beans); i++ {
+		count, err := sess.Where("uid=? AND deleted=?", uid, false).Count(beans[i])
+
+		if err != nil {
+			return false, err
+		} else if count > 0 {
+			return false, nil
+		}
+	}
+
+	count, err := sess.Where("uid=? AND deleted_unix_time=?", uid, 0).Count(&models.UserCustomExchangeRate{})
+
+	if err != nil {
+		return false, err
+	}
+
+	return count < 1, nil
+}
+
+func (s *UserDataBackupService) createIdMapping(backup *models.UserDataBackup) (*userDataBackupIdMapping, error) {
+	idMapping := &userDataBackupIdMapping{}
+	var err error
+
+	if idMapping.accounts, err = s.generateIdMap(uuid.UUID_TYPE_ACCOUNT, len(backup.Accounts), func(i int) int64 { return backup.Accounts[i].AccountId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.categories, err = s.generateIdMap(uuid.UUID_TYPE_CATEGORY, len(backup.TransactionCategories), func(i int) int64 { return backup.TransactionCategories[i].CategoryId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.tagGroups, err = s.generateIdMap(uuid.UUID_TYPE_TAG_GROUP, len(backup.TransactionTagGroups), func(i int) int64 { return backup.TransactionTagGroups[i].TagGroupId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.tags, err = s.generateIdMap(uuid.UUID_TYPE_TAG, len(backup.TransactionTags), func(i int) int64 { return backup.TransactionTags[i].TagId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.transactions, err = s.generateIdMap(uuid.UUID_TYPE_TRANSACTION, len(backup.Transactions), func(i int) int64 { return backup.Transactions[i].TransactionId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.tagIndexes, err = s.generateIdMap(uuid.UUID_TYPE_TAG_INDEX, len(backup.TransactionTagIndexes), func(i int) int64 { return backup.TransactionTagIndexes[i].TagIndexId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.splits, err = s.generateIdMap(uuid.UUID_TYPE_SPLIT, len(backup.TransactionSplits), func(i int) int64 { return backup.TransactionSplits[i].SplitId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.templates, err = s.generateIdMap(uuid.UUID_TYPE_TEMPLATE, len(backup.TransactionTemplates), func(i int) int64 { return backup.TransactionTemplates[i].TemplateId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.exceptions, err = s.generateIdMap(uuid.UUID_TYPE_TEMPLATE_EXCEPTION, len(backup.TransactionScheduleExceptions), func(i int) int64 { return backup.TransactionScheduleExceptions[i].ExceptionId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.pictures, err = s.generateIdMap(uuid.UUID_TYPE_PICTURE, len(backup.TransactionPictureInfos), func(i int) int64 { return backup.TransactionPictureInfos[i].PictureId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.explorers, err = s.generateIdMap(uuid.UUID_TYPE_EXPLORER, len(backup.InsightsExplorers), func(i int) int64 { return backup.InsightsExplorers[i].ExplorerId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.customIcons, err = s.generateIdMap(uuid.UUID_TYPE_CUSTOM_ICON, len(backup.UserCustomIcons), func(i int) int64 { return backup.UserCustomIcons[i].IconId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.budgets, err = s.generateIdMap(uuid.UUID_TYPE_BUDGET, len(backup.Budgets), func(i int) int64 { return backup.Budgets[i].BudgetId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.securities, err = s.generateIdMap(uuid.UUID_TYPE_INVESTMENT, len(backup.InvestmentSecurities), func(i int) int64 { return backup.InvestmentSecurities[i].SecurityId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.investments, err = s.generateIdMap(uuid.UUID_TYPE_INVESTMENT, len(backup.InvestmentTransactions), func(i int) int64 { return backup.InvestmentTransactions[i].InvestmentTransactionId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.prices, err = s.generateIdMap(uuid.UUID_TYPE_INVESTMENT, len(backup.SecurityPrices), func(i int) int64 { return backup.SecurityPrices[i].PriceId }); err != nil {
+		return nil, err
+	}
+
+	if idMapping.rules, err = s.generateIdMap(uuid.UUID_TYPE_AUTOMATION, len(backup.TransactionRules), func(i int) int64 { return backup.TransactionRules[i].RuleId }); err != nil {
+		return nil, err
+	}
+
+	return idMapping, nil
+}
+
+func (s *UserDataBackupService) generateIdMap(uuidType uuid.UuidType, count int, getOriginalId func(i int) int64) (map[int64]int64, error) {
+	idMap := make(map[int64]int64, count)
+
+	for start := 0; start < count; start += math.MaxUint16 {
+		needUuidCount := min(count-start, math.MaxUint16)
+		uuids := s.GenerateUuids(uuidType, uint16(needUuidCount))
+
+		if len(uuids) < needUuidCount {
+			return nil, errs.ErrSystemIsBusy
+		}
+
+		for i := 0; i < needUuidCount; i++ {
+			originalId := getOriginalId(start + i)
+
+			if _, exists := idMap[originalId]; exists {
+				return nil, errs.ErrBackupFileInvalid
+			}
+
+			idMap[originalId] = uuids[i]
+		}
+	}
+
+	return idMap, nil
+}
+
+// remapUserDataBackup replaces all the ids and the references between the data in backup with the new ids, and sets the owner to specified user
+func (s *UserDataBackupService) remapUserDataBackup(uid int64, backup *models.UserDataBackup, idMapping *userDataBackupIdMapping) error {
+	var err error
+	now := time.Now().Unix()
+
+	for i := 0; i < len(backup.UserCustomIcons); i++ {
+		customIcon := backup.UserCustomIcons[i]
+		customIcon.IconId = idMapping.customIcons[customIcon.IconId]
+		customIcon.Uid = uid
+		customIcon.Deleted = false
+		customIcon.UpdatedUnixTime = now
+		customIcon.DeletedUnixTime = 0
+	}
+
+	for i := 0; i < len(backup.Accounts); i++ {
+		account := backup.Accounts[i]
+		account.AccountId = idMapping.accounts[account.AccountId]
+		account.Uid = uid
+		account.Deleted = false
+		account.UpdatedUnixTime = now
+		account.DeletedUnixTime = 0
+
+		if account.ParentAccountId != models.LevelOneAccountParentId {
+			if account.ParentAccountId, err = s.getNewId(idMapping.accounts, account.ParentAccountId); err != nil {
+				return err
+			}
+		}
+
+		if account.IconType == core.ICON_TYPE_USER_CUSTOM {
+			if account.Icon, err = s.getNewId(idMapping.customIcons, account.Icon); err != nil {
+				return err
+			}
+		}
+
+		if account.Extend != nil && account.Extend.Loan != nil {
+			loan := account.Extend.Loan
+
+			if loan.PaymentAccountId, err = s.getNewId(idMapping.accounts, loan.PaymentAccountId); err != nil {
+				return err
+			}
+
+			if loan.PrincipalCategoryId, err = s.getNewId(idMapping.categories, loan.PrincipalCategoryId); err != nil {
+				return err
+			}
+
+			if loan.InterestCategoryId, err = s.getNewId(idMapping.categories, loan.InterestCategoryId); err != nil {
+				return err
+			}
+
+			// the payment template may have been deleted by user, so the loan just has no scheduled payment after restoring
+			loan.PaymentTemplateId = idMapping.templates[loan.PaymentTemplateId]
+		}
+	}
+
+	for i := 0; i < len(backup.TransactionCategories); i++ {
+		category := backup.TransactionCategories[i]
+		category.CategoryId = idMapping.categories[category.CategoryId]
+		category.Uid = uid
+		category.Deleted = false
+		category.UpdatedUnixTime = now
+		category.DeletedUnixTime = 0
+
+		if category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+			if category.ParentCategoryId, err = s.getNewId(idMapping.categories, category.ParentCategoryId); err != nil {
+				return err
+			}
+		}
+
+		if category.IconType == core.ICON_TYPE_USER_CUSTOM {
+			if category.Icon, err = s.getNewId(idMapping.customIcons, category.Icon); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := 0; i < len(backup.TransactionTagGroups); i++ {
+		tagGroup := backup.TransactionTagGroups[i]
+		tagGroup.TagGroupId = idMapping.tagGroups[tagGroup.TagGroupId]
+		tagGroup.Uid = uid
+		tagGroup.Deleted = false
+		tagGroup.UpdatedUnixTime = now
+		tagGroup.DeletedUnixTime = 0
+	}
+
+	for i := 0; i < len(backup.TransactionTags); i++ {
+		tag := backup.TransactionTags[i]
+		tag.TagId = idMapping.tags[tag.TagId]
+		tag.Uid = uid
+		tag.Deleted = false
+		tag.UpdatedUnixTime = now
+		tag.DeletedUnixTime = 0
+
+		if tag.TagGroupId != 0 {
+			if tag.TagGroupId, err = s.getNewId(idMapping.tagGroups, tag.TagGroupId); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := 0; i < len(backup.Transactions); i++ {
+		transaction := backup.Transactions[i]
+		transaction.TransactionId = idMapping.transactions[transaction.TransactionId]
+		transaction.Uid = uid
+		transaction.Deleted = false
+		transaction.UpdatedUnixTime = now
+		transaction.DeletedUnixTime = 0
+
+		if transaction.AccountId, err = s.getNewId(idMapping.accounts, transaction.AccountId); err != nil {
+			return err
+		}
+
+		if transaction.CategoryId, err = s.getNewOptionalId(idMapping.categories, transaction.CategoryId); err != nil {
+			return err
+		}
+
+		if transaction.RelatedAccountId, err = s.getNewOptionalId(idMapping.accounts, transaction.RelatedAccountId); err != nil {
+			return err
+		}
+
+		if transaction.RelatedId, err = s.getNewOptionalId(idMapping.transactions, transaction.RelatedId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.TransactionTagIndexes); i++ {
+		tagIndex := backup.TransactionTagIndexes[i]
+		tagIndex.TagIndexId = idMapping.tagIndexes[tagIndex.TagIndexId]
+		tagIndex.Uid = uid
+		tagIndex.Deleted = false
+		tagIndex.UpdatedUnixTime = now
+		tagIndex.DeletedUnixTime = 0
+
+		if tagIndex.TagId, err = s.getNewId(idMapping.tags, tagIndex.TagId); err != nil {
+			return err
+		}
+
+		if tagIndex.TransactionId, err = s.getNewId(idMapping.transactions, tagIndex.TransactionId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.TransactionSplits); i++ {
+		split := backup.TransactionSplits[i]
+		split.SplitId = idMapping.splits[split.SplitId]
+		split.Uid = uid
+		split.Deleted = false
+		split.UpdatedUnixTime = now
+		split.DeletedUnixTime = 0
+
+		if split.TransactionId, err = s.getNewId(idMapping.transactions, split.TransactionId); err != nil {
+			return err
+		}
+
+		if split.CategoryId, err = s.getNewId(idMapping.categories, split.CategoryId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.TransactionPictureInfos); i++ {
+		pictureInfo := backup.TransactionPictureInfos[i]
+		pictureInfo.PictureId = idMapping.pictures[pictureInfo.PictureId]
+		pictureInfo.Uid = uid
+		pictureInfo.Deleted = false
+		pictureInfo.UpdatedUnixTime = now
+		pictureInfo.DeletedUnixTime = 0
+
+		if utils.GetImageContentType(pictureInfo.PictureExtension) == "" {
+			return errs.ErrBackupFileInvalid
+		}
+
+		if pictureInfo.TransactionId, err = s.getNewOptionalId(idMapping.transactions, pictureInfo.TransactionId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.TransactionTemplates); i++ {
+		template := backup.TransactionTemplates[i]
+		template.TemplateId = idMapping.templates[template.TemplateId]
+		template.Uid = uid
+		template.Deleted = false
+		template.UpdatedUnixTime = now
+		template.DeletedUnixTime = 0
+
+		if template.AccountId, err = s.getNewOptionalId(idMapping.accounts, template.AccountId); err != nil {
+			return err
+		}
+
+		if template.CategoryId, err = s.getNewOptionalId(idMapping.categories, template.CategoryId); err != nil {
+			return err
+		}
+
+		if template.RelatedAccountId, err = s.getNewOptionalId(idMapping.accounts, template.RelatedAccountId); err != nil {
+			return err
+		}
+
+		tagIds := template.GetTagIds()
+		newTagIds := make([]string, 0, len(tagIds))
+
+		for j := 0; j < len(tagIds); j++ {
+			if newTagId, exists := idMapping.tags[tagIds[j]]; exists {
+				newTagIds = append(newTagIds, utils.Int64ToString(newTagId))
+			}
+		}
+
+		template.TagIds = strings.Join(newTagIds, ",")
+	}
+
+	for i := 0; i < len(backup.TransactionScheduleExceptions); i++ {
+		exception := backup.TransactionScheduleExceptions[i]
+		exception.ExceptionId = idMapping.exceptions[exception.ExceptionId]
+		exception.Uid = uid
+		exception.Deleted = false
+		exception.UpdatedUnixTime = now
+		exception.DeletedUnixTime = 0
+
+		if exception.TemplateId, err = s.getNewId(idMapping.templates, exception.TemplateId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.InsightsExplorers); i++ {
+		explorer := backup.InsightsExplorers[i]
+		explorer.ExplorerId = idMapping.explorers[explorer.ExplorerId]
+		explorer.Uid = uid
+		explorer.Deleted = false
+		explorer.UpdatedUnixTime = now
+		explorer.DeletedUnixTime = 0
+		explorer.Data = s.replaceIdsInExplorerData(explorer.Data, idMapping.accounts, idMapping.categories, idMapping.tags)
+	}
+
+	for i := 0; i < len(backup.UserCustomExchangeRates); i++ {
+		customExchangeRate := backup.UserCustomExchangeRates[i]
+		customExchangeRate.Uid = uid
+		customExchangeRate.UpdatedUnixTime = now
+		customExchangeRate.DeletedUnixTime = 0
+	}
+
+	for i := 0; i < len(backup.Budgets); i++ {
+		budget := backup.Budgets[i]
+		budget.BudgetId = idMapping.budgets[budget.BudgetId]
+		budget.Uid = uid
+		budget.Deleted = false
+		budget.UpdatedUnixTime = now
+		budget.DeletedUnixTime = 0
+
+		if budget.TargetType == models.BUDGET_TARGET_TYPE_CATEGORY {
+			budget.TargetId, err = s.getNewId(idMapping.categories, budget.TargetId)
+		} else if budget.TargetType == models.BUDGET_TARGET_TYPE_ACCOUNT {
+			budget.TargetId, err = s.getNewId(idMapping.accounts, budget.TargetId)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.InvestmentSecurities); i++ {
+		security := backup.InvestmentSecurities[i]
+		security.SecurityId = idMapping.securities[security.SecurityId]
+		security.Uid = uid
+		security.Deleted = false
+		security.UpdatedUnixTime = now
+		security.DeletedUnixTime = 0
+
+		if security.AccountId, err = s.getNewId(idMapping.accounts, security.AccountId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.InvestmentTransactions); i++ {
+		investmentTransaction := backup.InvestmentTransactions[i]
+		investmentTransaction.InvestmentTransactionId = idMapping.investments[investmentTransaction.InvestmentTransactionId]
+		investmentTransaction.Uid = uid
+		investmentTransaction.Deleted = false
+		investmentTransaction.UpdatedUnixTime = now
+		investmentTransaction.DeletedUnixTime = 0
+
+		if investmentTransaction.SecurityId, err = s.getNewId(idMapping.securities, investmentTransaction.SecurityId); err != nil {
+			return err
+		}
+
+		if investmentTransaction.AccountId, err = s.getNewId(idMapping.accounts, investmentTransaction.AccountId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.SecurityPrices); i++ {
+		price := backup.SecurityPrices[i]
+		price.PriceId = idMapping.prices[price.PriceId]
+		price.Uid = uid
+		price.Deleted = false
+		price.UpdatedUnixTime = now
+		price.DeletedUnixTime = 0
+
+		if price.SecurityId, err = s.getNewId(idMapping.securities, price.SecurityId); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(backup.TransactionRules); i++ {
+		rule := backup.TransactionRules[i]
+		rule.RuleId = idMapping.rules[rule.RuleId]
+		rule.Uid = uid
+		rule.Deleted = false
+		rule.UpdatedUnixTime = now
+		rule.DeletedUnixTime = 0
+
+		if rule.AccountId, err = s.getNewOptionalId(idMapping.accounts, rule.AccountId); err != nil {
+			return err
+		}
+
+		if rule.CategoryId, err = s.getNewOptionalId(idMapping.categories, rule.CategoryId); err != nil {
+			return err
+		}
+
+		if rule.DestinationAccountId, err = s.getNewOptionalId(idMapping.accounts, rule.DestinationAccountId); err != nil {
+			return err
+		}
+
+		tagIds := rule.GetTagIds()
+		newTagIds := make([]string, 0, len(tagIds))
+
+		for j := 0; j < len(tagIds); j++ {
+			if newTagId, exists := idMapping.tags[tagIds[j]]; exists {
+				newTagIds = append(newTagIds, utils.Int64ToString(newTagId))
+			}
+		}
+
+		rule.TagIds = strings.Join(newTagIds, ",")
+	}
+
+	return nil
+}
+
+func (s *UserDataBackupService) getNewId(idMap map[int64]int64, originalId int64) (int64, error) {
+	newId, exists := idMap[originalId]
+
+	if !exists {
+		return 0, errs.ErrBackupFileInvalid
+	}
+
+	return newId, nil
+}
+
+func (s *UserDataBackupService) getNewOptionalId(idMap map[int64]int64, originalId int64) (int64, error) {
+	if originalId == 0 {
+		return 0, nil
+	}
+
+	return s.getNewId(idMap, originalId)
+}
+
+// replaceIdsInExplorerData replaces the account, category and tag ids (which are serialized as strings) in explorer query data with the new ids
+func (s *UserDataBackupService) replaceIdsInExplorerData(data string, idMaps ...map[int64]int64) string {
+	if data == "" {
+		return data
+	}
+
+	replacements := make([]string, 0)
+
+	for i := 0; i < len(idMaps); i++ {
+		for originalId, newId := range idMaps[i] {
+			replacements = append(replacements, "\""+utils.Int64ToString(originalId)+"\"", "\""+utils.Int64ToString(newId)+"\"")
+		}
+	}
+
+	if len(replacements) < 1 {
+		return data
+	}
+
+	return strings.NewReplacer(replacements...).Replace(data)
+}
+
+func (s *UserDataBackupService) writeArchiveFile(zipWriter *zip.Writer, fileName string, reader io.Reader) error {
+	writer, err := zipWriter.Create(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(writer, reader)
+
+	return err
+}
+
+func (s *UserDataBackupService) readArchiveFile(files map[string]*zip.File, fileName string, maxFileSize uint32) ([]byte, error) {
+	file, exists := files[fileName]
+
+	if !exists {
+		return nil, nil
+	}
+
+	if file.UncompressedSize64 > uint64(maxFileSize) {
+		return nil, errs.ErrExceedMaxUploadFileSize
+	}
+
+	reader, err := file.Open()
+
+	if err != nil {
+		return nil, errs.ErrBackupFileInvalid
+	}
+
+	defer reader.Close()
+
+	fileData, err := io.ReadAll(io.LimitReader(reader, int64(maxFileSize)+1))
+
+	if err != nil {
+		return nil, errs.ErrBackupFileInvalid
+	} else if len(fileData) > int(maxFileSize) {
+		return nil, errs.ErrExceedMaxUploadFileSize
+	}
+
+	return fileData, nil
+}
+
+func (s *UserDataBackupService) getCustomIconFileName(iconId int64) string {
+	return path.Join(models.UserDataBackupCustomIconDirectory, fmt.Sprintf("%d.%s", iconId, models.UserCustomIconFileExtension))
+}
+
+func (s *UserDataBackupService) getTransactionPictureFileName(pictureId int64, fileExtension string) string {
+	return path.Join(models.UserDataBackupTransactionPictureDirectory, fmt.Sprintf("%d.%s", pictureId, fileExtension))
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestRemapUserDataBackup(t *testing.T) {
+	backup := &models.UserDataBackup{
+		Accounts: []*models.Account{
+			{AccountId: 1, Uid: 100, Type: models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS},
+			{AccountId: 2, Uid: 100, ParentAccountId: 1, Icon: 31, IconType: core.ICON_TYPE_USER_CUSTOM},
+			{AccountId: 3, Uid: 100, Icon: 1, IconType: core.ICON_TYPE_SYSTEM, Extend: &models.AccountExtend{Loan: &models.AccountLoanInfo{PaymentAccountId: 2, PrincipalCategoryId: 12, InterestCategoryId: 11, PaymentTemplateId: 71}}},
+		},
+		TransactionCategories: []*models.TransactionCategory{
+			{CategoryId: 11, Uid: 100},
+			{CategoryId: 12, Uid: 100, ParentCategoryId: 11},
+		},
+		TransactionTagGroups: []*models.TransactionTagGroup{
+			{TagGroupId: 21, Uid: 100},
+		},
+		TransactionTags: []*models.TransactionTag{
+			{TagId: 22, Uid: 100, TagGroupId: 21},
+			{TagId: 23, Uid: 100},
+		},
+		UserCustomIcons: []*models.UserCustomIcon{
+			{IconId: 31, Uid: 100},
+		},
+		Transactions: []*models.Transaction{
+			{TransactionId: 41, Uid: 100, Type: models.TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2, CategoryId: 12},
+			{TransactionId: 42, Uid: 100, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 2, RelatedId: 43, RelatedAccountId: 3},
+			{TransactionId: 43, Uid: 100, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 3, RelatedId: 42, RelatedAccountId: 2},
+		},
+		TransactionTagIndexes: []*models.TransactionTagIndex{
+			{TagIndexId: 51, Uid: 100, TagId: 22, TransactionId: 41},
+		},
+		TransactionSplits: []*models.TransactionSplit{
+			{SplitId: 101, Uid: 100, TransactionId: 41, CategoryId: 12, Amount: 1},
+		},
+		TransactionPictureInfos: []*models.TransactionPictureInfo{
+			{PictureId: 61, Uid: 100, TransactionId: 41, PictureExtension: "jpg"},
+		},
+		TransactionTemplates: []*models.TransactionTemplate{
+			{TemplateId: 71, Uid: 100, AccountId: 2, CategoryId: 12, TagIds: "22,23"},
+		},
+		TransactionScheduleExceptions: []*models.TransactionScheduleException{
+			{ExceptionId: 151, Uid: 100, TemplateId: 71, OccurrenceDate: 20240101, Type: models.TRANSACTION_SCHEDULE_EXCEPTION_TYPE_SKIP},
+		},
+		InsightsExplorers: []*models.InsightsExplorer{
+			{ExplorerId: 81, Uid: 100, Data: "{\"accountIds\":[\"2\",\"3\"],\"categoryIds\":[\"12\"],\"amount\":2}"},
+		},
+		UserCustomExchangeRates: []*models.UserCustomExchangeRate{
+			{Uid: 100, Currency: "USD", Rate: 100},
+		},
+		Budgets: []*models.Budget{
+			{BudgetId: 91, Uid: 100, TargetType: models.BUDGET_TARGET_TYPE_CATEGORY, TargetId: 12},
+			{BudgetId: 92, Uid: 100, TargetType: models.BUDGET_TARGET_TYPE_ACCOUNT, TargetId: 3},
+		},
+		InvestmentSecurities: []*models.InvestmentSecurity{
+			{SecurityId: 111, Uid: 100, AccountId: 3, Symbol: "AAPL.US"},
+		},
+		InvestmentTransactions: []*models.InvestmentTransaction{
+			{InvestmentTransactionId: 121, Uid: 100, SecurityId: 111, AccountId: 3, Type: models.INVESTMENT_TRANSACTION_TYPE_BUY},
+		},
+		SecurityPrices: []*models.SecurityPrice{
+			{PriceId: 131, Uid: 100, SecurityId: 111},
+		},
+		TransactionRules: []*models.TransactionRule{
+			{RuleId: 141, Uid: 100, AccountId: 2, CategoryId: 12, TagIds: "23", DestinationAccountId: 3},
+		},
+	}
+
+	idMapping := &userDataBackupIdMapping{
+		accounts:     map[int64]int64{1: 1001, 2: 1002, 3: 1003},
+		categories:   map[int64]int64{11: 1011, 12: 1012},
+		tagGroups:    map[int64]int64{21: 1021},
+		tags:         map[int64]int64{22: 1022, 23: 1023},
+		transactions: map[int64]int64{41: 1041, 42: 1042, 43: 1043},
+		tagIndexes:   map[int64]int64{51: 1051},
+		splits:       map[int64]int64{101: 1101},
+		templates:    map[int64]int64{71: 1071},
+		exceptions:   map[int64]int64{151: 1151},
+		pictures:     map[int64]int64{61: 1061},
+		explorers:    map[int64]int64{81: 1081},
+		customIcons:  map[int64]int64{31: 1031},
+		budgets:      map[int64]int64{91: 1091, 92: 1092},
+		securities:   map[int64]int64{111: 1111},
+		investments:  map[int64]int64{121: 1121},
+		prices:       map[int64]int64{131: 1131},
+		rules:        map[int64]int64{141: 1141},
+	}
+
+	err := UserDataBackups.remapUserDataBackup(200, backup, idMapping)
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(1001), backup.Accounts[0].AccountId)
+	assert.Equal(t, int64(200), backup.Accounts[0].Uid)
+	assert.Equal(t, int64(1002), backup.Accounts[1].AccountId)
+	assert.Equal(t, int64(1001), backup.Accounts[1].ParentAccountId)
+	assert.Equal(t, int64(1031), backup.Accounts[1].Icon)
+	assert.Equal(t, int64(1), backup.Accounts[2].Icon)
+	assert.Equal(t, int64(1002), backup.Accounts[2].Extend.Loan.PaymentAccountId)
+	assert.Equal(t, int64(1012), backup.Accounts[2].Extend.Loan.PrincipalCategoryId)
+	assert.Equal(t, int64(1011), backup.Accounts[2].Extend.Loan.InterestCategoryId)
+	assert.Equal(t, int64(1071), backup.Accounts[2].Extend.Loan.PaymentTemplateId)
+
+	assert.Equal(t, int64(1012), backup.TransactionCategories[1].CategoryId)
+	assert.Equal(t, int64(1011), backup.TransactionCategories[1].ParentCategoryId)
+
+	assert.Equal(t, int64(1021), backup.TransactionTags[0].TagGroupId)
+	assert.Equal(t, int64(0), backup.TransactionTags[1].TagGroupId)
+
+	assert.Equal(t, int64(1041), backup.Transactions[0].TransactionId)
+	assert.Equal(t, int64(1002), backup.Transactions[0].AccountId)
+	assert.Equal(t, int64(1012), backup.Transactions[0].CategoryId)
+	assert.Equal(t, int64(1043), backup.Transactions[1].RelatedId)
+	assert.Equal(t, int64(1003), backup.Transactions[1].RelatedAccountId)
+	assert.Equal(t, int64(1042), backup.Transactions[2].RelatedId)
+
+	assert.Equal(t, int64(1051), backup.TransactionTagIndexes[0].TagIndexId)
+	assert.Equal(t, int64(1022), backup.TransactionTagIndexes[0].TagId)
+	assert.Equal(t, int64(1041), backup.TransactionTagIndexes[0].TransactionId)
+
+	assert.Equal(t, int64(1101), backup.TransactionSplits[0].SplitId)
+	assert.Equal(t, int64(1041), backup.TransactionSplits[0].TransactionId)
+	assert.Equal(t, int64(1012), backup.TransactionSplits[0].CategoryId)
+
+	assert.Equal(t, int64(1061), backup.TransactionPictureInfos[0].PictureId)
+	assert.Equal(t, int64(1041), backup.TransactionPictureInfos[0].TransactionId)
+
+	assert.Equal(t, int64(1071), backup.TransactionTemplates[0].TemplateId)
+	assert.Equal(t, int64(1002), backup.TransactionTemplates[0].AccountId)
+	assert.Equal(t, "1022,1023", backup.TransactionTemplates[0].TagIds)
+
+	assert.Equal(t, int64(1151), backup.TransactionScheduleExceptions[0].ExceptionId)
+	assert.Equal(t, int64(200), backup.TransactionScheduleExceptions[0].Uid)
+	assert.Equal(t, int64(1071), backup.TransactionScheduleExceptions[0].TemplateId)
+
+	assert.Equal(t, "{\"accountIds\":[\"1002\",\"1003\"],\"categoryIds\":[\"1012\"],\"amount\":2}", backup.InsightsExplorers[0].Data)
+
+	assert.Equal(t, int64(200), backup.UserCustomExchangeRates[0].Uid)
+
+	assert.Equal(t, int64(1012), backup.Budgets[0].TargetId)
+	assert.Equal(t, int64(1003), backup.Budgets[1].TargetId)
+
+	assert.Equal(t, int64(1111), backup.InvestmentSecurities[0].SecurityId)
+	assert.Equal(t, int64(1003), backup.InvestmentSecurities[0].AccountId)
+	assert.Equal(t, int64(1121), backup.InvestmentTransactions[0].InvestmentTransactionId)
+	assert.Equal(t, int64(1111), backup.InvestmentTransactions[0].SecurityId)
+	assert.Equal(t, int64(1003), backup.InvestmentTransactions[0].AccountId)
+	assert.Equal(t, int64(1131), backup.SecurityPrices[0].PriceId)
+	assert.Equal(t, int64(1111), backup.SecurityPrices[0].SecurityId)
+
+	assert.Equal(t, int64(1141), backup.TransactionRules[0].RuleId)
+	assert.Equal(t, int64(1002), backup.TransactionRules[0].AccountId)
+	assert.Equal(t, int64(1012), backup.TransactionRules[0].CategoryId)
+	assert.Equal(t, int64(1003), backup.TransactionRules[0].DestinationAccountId)
+	assert.Equal(t, "1023", backup.TransactionRules[0].TagIds)
+}
+
+func TestRemapUserDataBackup_ReferenceNotExists(t *testing.T) {
+	backup := &models.UserDataBackup{
+		Accounts: []*models.Account{
+			{AccountId: 1, ParentAccountId: 99},
+		},
+	}
+
+	idMapping := &userDataBackupIdMapping{
+		accounts: map[int64]int64{1: 1001},
+	}
+
+	err := UserDataBackups.remapUserDataBackup(200, backup, idMapping)
+	assert.Equal(t, errs.ErrBackupFileInvalid, err)
+}
+
+func TestParseBackupArchive_InvalidArchive(t *testing.T) {
+	_, _, err := UserDataBackups.ParseBackupArchive(nil, 1048576)
+	assert.Equal(t, errs.ErrBackupFileEmpty, err)
+
+	_, _, err = UserDataBackups.ParseBackupArchive([]byte("not a zip file"), 1048576)
+	assert.Equal(t, errs.ErrBackupFileInvalid, err)
+
+	_, _, err = UserDataBackups.ParseBackupArchive(createTestBackupArchive(t, "other.json", "{}"), 1048576)
+	assert.Equal(t, errs.ErrBackupFileInvalid, err)
+
+	_, _, err = UserDataBackups.ParseBackupArchive(createTestBackupArchive(t, models.UserDataBackupDataFileName, "{\"version\":99}"), 1048576)
+	assert.Equal(t, errs.ErrBackupVersionNotSupported, err)
+}
+
+func TestParseBackupArchive_ValidArchive(t *testing.T) {
+	backup, files, err := UserDataBackups.ParseBackupArchive(createTestBackupArchive(t, models.UserDataBackupDataFileName, "{\"version\":1,\"accounts\":[{\"AccountId\":1,\"Name\":\"Cash\"}]}"), 1048576)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+	assert.Equal(t, 1, len(backup.Accounts))
+	assert.Equal(t, int64(1), backup.Accounts[0].AccountId)
+	assert.Equal(t, "Cash", backup.Accounts[0].Name)
+}
+
+func TestParseBackupArchive_DataFileExceedsMaxSize(t *testing.T) {
+	content := "{\"version\":1,\"accounts\":[{\"AccountId\":1,\"Name\":\"" + strings.Repeat("a", 1024) + "\"}]}"
+
+	_, _, err := UserDataBackups.ParseBackupArchive(createTestBackupArchive(t, models.UserDataBackupDataFileName, content), 1024)
+	assert.Equal(t, errs.ErrExceedMaxUploadFileSize, err)
+
+	backup, _, err := UserDataBackups.ParseBackupArchive(createTestBackupArchive(t, models.UserDataBackupDataFileName, content), uint32(len(content)))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(backup.Accounts))
+}
+
+func createTestBackupArchive(t *testing.T, fileName string, content string) []byte {
+	buffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buffer)
+	writer, err := zipWriter.Create(fileName)
+	assert.Nil(t, err)
+
+	_, err = writer.Write([]byte(content))
+	assert.Nil(t, err)
+	assert.Nil(t, zipWriter.Close())
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/locales"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const passkeyCeremonyTimeout = 5 * time.Minute
+
+// UserPasskeyService represents user passkey service
+type UserPasskeyService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingUuid
+}
+
+// Initialize a user passkey service singleton instance
+var (
+	UserPasskeys = &UserPasskeyService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllPasskeysByUid returns all passkeys of the specified user
+func (s *UserPasskeyService) GetAllPasskeysByUid(c core.Context, uid int64) ([]*models.UserPasskey, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var passkeys []*models.UserPasskey
+	err := s.UserDB().NewSession(c).Where("uid=?", uid).OrderBy("created_unix_time desc").Find(&passkeys)
+
+	return passkeys, err
+}
+
+// GetPasskeyByPasskeyId returns the passkey of the specified user according to passkey id
+func (s *UserPasskeyService) GetPasskeyByPasskeyId(c core.Context, uid int64, passkeyId int64) (*models.UserPasskey, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if passkeyId <= 0 {
+		return nil, errs.ErrPasskeyIdInvalid
+	}
+
+	passkey := &models.UserPasskey{}
+	has, err := s.UserDB().NewSession(c).Where("uid=? AND passkey_id=?", uid, passkeyId).Get(passkey)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrPasskeyNotFound
+	}
+
+	return passkey, nil
+}
+
+// ExistsPasskey returns whether the specified user has registered any passkey
+func (s *UserPasskeyService) ExistsPasskey(c core.Context, uid int64) (bool, error) {
+	if uid <= 0 {
+		return false, errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().NewSession(c).Cols("uid").Where("uid=?", uid).Exist(&models.UserPasskey{})
+}
+
+// CreatePasskey saves a new passkey of the specified user according to the verified webauthn credential
+func (s *UserPasskeyService) CreatePasskey(c core.Context, uid int64, name string, credential *webauthn.Credential) (*models.UserPasskey, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if name == "" {
+		return nil, errs.ErrPasskeyNameInvalid
+	}
+
+	if credential == nil || len(credential.ID) < 1 {
+		return nil, errs.ErrPasskeyCredentialInvalid
+	}
+
+	passkeyId := s.GenerateUuid(uuid.UUID_TYPE_PASSKEY)
+
+	if passkeyId < 1 {
+		return nil, errs.ErrSystemIsBusy
+	}
+
+	now := time.Now().Unix()
+	passkey := &models.UserPasskey{
+		PasskeyId:       passkeyId,
+		Uid:             uid,
+		Name:            name,
+		CredentialId:    models.EncodePasskeyCredentialId(credential.ID),
+		Credential:      credential,
+		CreatedUnixTime: now,
+	}
+
+	err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("passkey_id").Where("credential_id=?", passkey.CredentialId).Exist(&models.UserPasskey{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrPasskeyAlreadyRegistered
+		}
+
+		_, err = sess.Insert(passkey)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return passkey, nil
+}
+
+// UpdatePasskeyAfterLogin updates the credential (e.g. sign count) and last used time of the specified passkey
+func (s *UserPasskeyService) UpdatePasskeyAfterLogin(c core.Context, passkey *models.UserPasskey, credential *webauthn.Credential) error {
+	if passkey == nil || credential == nil {
+		return errs.ErrPasskeyNotFound
+	}
+
+	passkey.Credential = credential
+	passkey.LastUsedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("credential", "last_used_unix_time").Where("uid=? AND passkey_id=?", passkey.Uid, passkey.PasskeyId).Update(passkey)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrPasskeyNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeletePasskey deletes the specified passkey of the user
+func (s *UserPasskeyService) DeletePasskey(c core.Context, uid int64, passkeyId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if passkeyId <= 0 {
+		return errs.ErrPasskeyIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where("uid=? AND passkey_id=?", uid, passkeyId).Delete(&models.UserPasskey{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrPasskeyNotFound
+		}
+
+		return nil
+	})
+}
+
+// BeginRegistration returns the credential creation options and the ceremony session for registering a new passkey
+func (s *UserPasskeyService) BeginRegistration(c core.Context, passkeyUser *models.PasskeyUser) (*protocol.CredentialCreation, *webauthn.SessionData, error) {
+	webAuthn, err := s.getWebAuthn()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existedCredentials := webauthn.Credentials(passkeyUser.WebAuthnCredentials())
+
+	return webAuthn.BeginRegistration(passkeyUser,
+		webauthn.WithExclusions(existedCredentials.CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+	)
+}
+
+// FinishRegistration verifies the credential creation response and returns the new credential
+func (s *UserPasskeyService) FinishRegistration(c core.Context, passkeyUser *models.PasskeyUser, session *webauthn.SessionData, response *protocol.ParsedCredentialCreationData) (*webauthn.Credential, error) {
+	webAuthn, err := s.getWebAuthn()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return webAuthn.CreateCredential(passkeyUser, *session, response)
+}
+
+// BeginLogin returns the credential request options and the ceremony session for the specified user
+func (s *UserPasskeyService) BeginLogin(c core.Context, passkeyUser *models.PasskeyUser) (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	webAuthn, err := s.getWebAuthn()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return webAuthn.BeginLogin(passkeyUser)
+}
+
+// FinishLogin verifies the credential assertion response of the specified user and returns the used passkey and updated credential
+func (s *UserPasskeyService) FinishLogin(c core.Context, passkeyUser *models.PasskeyUser, session *webauthn.SessionData, response *protocol.ParsedCredentialAssertionData) (*models.UserPasskey, *webauthn.Credential, error) {
+	webAuthn, err := s.getWebAuthn()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credential, err := webAuthn.ValidateLogin(passkeyUser, *session, response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	passkey := passkeyUser.GetPasskeyByCredentialId(credential.ID)
+
+	if passkey == nil {
+		return nil, nil, errs.ErrPasskeyNotFound
+	}
+
+	return passkey, credential, nil
+}
+
+// BeginDiscoverableLogin returns the credential request options and the ceremony session for passwordless login
+func (s *UserPasskeyService) BeginDiscoverableLogin(c core.Context) (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	webAuthn, err := s.getWebAuthn()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return webAuthn.BeginDiscoverableLogin()
+}
+
+// FinishDiscoverableLogin verifies the credential assertion response of passwordless login and returns the passkey user, the used passkey and updated credential
+func (s *UserPasskeyService) FinishDiscoverableLogin(c core.Context, session *webauthn.SessionData, response *protocol.ParsedCredentialAssertionData, userLoader func(uid int64) (*models.PasskeyUser, error)) (*models.PasskeyUser, *models.UserPasskey, *webauthn.Credential, error) {
+	webAuthn, err := s.getWebAuthn()
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var passkeyUser *models.PasskeyUser
+
+	_, credential, err := webAuthn.ValidatePasskeyLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		uid := models.GetUidFromPasskeyUserHandle(userHandle)
+
+		if uid <= 0 {
+			return nil, errs.ErrPasskeyNotFound
+		}
+
+		passkeyUser, err = userLoader(uid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return passkeyUser, nil
+	}, *session, response)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	passkey := passkeyUser.GetPasskeyByCredentialId(credential.ID)
+
+	if passkey == nil {
+		return nil, nil, nil, errs.ErrPasskeyNotFound
+	}
+
+	return passkeyUser, passkey, credential, nil
+}
+
+func (s *UserPasskeyService) getWebAuthn() (*webauthn.WebAuthn, error) {
+	config := s.CurrentConfig()
+
+	if !config.EnablePasskey {
+		return nil, errs.ErrPasskeyNotEnabled
+	}
+
+	if config.PasskeyRPID == "" || len(config.PasskeyRPOrigins) < 1 {
+		return nil, errs.ErrPasskeyRelyingPartyNotSet
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:          config.PasskeyRPID,
+		RPDisplayName: locales.DefaultLanguage.GlobalTextItems.AppName,
+		RPOrigins:     config.PasskeyRPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login: webauthn.TimeoutConfig{
+				Enforce: true,
+				Timeout: passkeyCeremonyTimeout,
+			},
+			Registration: webauthn.TimeoutConfig{
+				Enforce: true,
+				Timeout: passkeyCeremonyTimeout,
+			},
+		},
+	})
+}
@@ -103,6 +103,55 @@ func (s *UserService) GetUserById(c core.Context, uid int64) (*models.User, erro
 	return user, nil
 }
 
+// GetUsersByUids returns the user models of the specified uids, the deleted users are not included
+func (s *UserService) GetUsersByUids(c core.Context, uids []int64) (map[int64]*models.User, error) {
+	userMap := make(map[int64]*models.User, len(uids))
+
+	if len(uids) < 1 {
+		return userMap, nil
+	}
+
+	var users []*models.User
+	err := s.UserDB().NewSession(c).Where("deleted=?", false).In("uid", uids).Find(&users)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(users); i++ {
+		userMap[users[i].Uid] = users[i]
+	}
+
+	return userMap, nil
+}
+
+// GetUsersByKeywordByPage returns the user models which user name, email or nickname contains the keyword, the deleted users are not included
+func (s *UserService) GetUsersByKeywordByPage(c core.Context, keyword string, page int32, count int32) ([]*models.User, error) {
+	if page < 0 {
+		return nil, errs.ErrPageIndexInvalid
+	} else if page == 0 {
+		page = 1
+	}
+
+	if count < 1 {
+		return nil, errs.ErrPageCountInvalid
+	}
+
+	condition, conditionParams := s.buildUserQueryCondition(keyword)
+
+	var users []*models.User
+	err := s.UserDB().NewSession(c).Where(condition, conditionParams...).Limit(int(count), int(count*(page-1))).OrderBy("uid asc").Find(&users)
+
+	return users, err
+}
+
+// GetUserCountByKeyword returns the count of users which user name, email or nickname contains the keyword, the deleted users are not included
+func (s *UserService) GetUserCountByKeyword(c core.Context, keyword string) (int64, error) {
+	condition, conditionParams := s.buildUserQueryCondition(keyword)
+
+	return s.UserDB().NewSession(c).Where(condition, conditionParams...).Count(&models.User{})
+}
+
 // GetUserByUsername returns the user model according to user name
 func (s *UserService) GetUserByUsername(c core.Context, username string) (*models.User, error) {
 	if username == "" {
@@ -139,6 +188,15 @@ func (s *UserService) GetUserByEmail(c core.Context, email string) (*models.User
 	return user, nil
 }
 
+// GetUserAvatarSize returns the size in bytes of the custom avatar of user, returns 0 if user does not set custom avatar
+func (s *UserService) GetUserAvatarSize(c core.Context, user *models.User) (int64, error) {
+	if user.CustomAvatarType == "" {
+		return 0, nil
+	}
+
+	return s.GetAvatarSize(c, user.Uid, user.CustomAvatarType)
+}
+
 // GetUserAvatar returns the user avatar image data according to user uid
 func (s *UserService) GetUserAvatar(c core.Context, uid int64, fileExtension string) ([]byte, error) {
 	if uid <= 0 {
@@ -234,7 +292,7 @@ func (s *UserService) CreateUser(c core.Context, user *models.User, noPassword b
 }
 
 // UpdateUser saves an existed user model to database
-func (s *UserService) UpdateUser(c core.Context, user *models.User, modifyUserLanguage bool, modifyUseLastReconciledTime bool) (keyProfileUpdated bool, emailSetToUnverified bool, err error) {
+func (s *UserService) UpdateUser(c core.Context, user *models.User, modifyUserLanguage bool, modifyUseLastReconciledTime bool, modifyBudgetAlertThresholds bool) (keyProfileUpdated bool, emailSetToUnverified bool, err error) {
 	if user.Uid <= 0 {
 		return false, false, errs.ErrUserIdInvalid
 	}
@@ -361,6 +419,11 @@ func (s *UserService) UpdateUser(c core.Context, user *models.User, modifyUserLa
 		updateCols = append(updateCols, "income_amount_color")
 	}
 
+	if modifyBudgetAlertThresholds {
+		updateCols = append(updateCols, "budget_alert_thresholds")
+		updateCols = append(updateCols, "budget_alert_timezone")
+	}
+
 	user.UpdatedUnixTime = now
 	updateCols = append(updateCols, "updated_unix_time")
 
@@ -671,6 +734,33 @@ func (s *UserService) DeleteUser(c core.Context, username string) error {
 	return nil
 }
 
+// UpdateUserRole sets user role
+func (s *UserService) UpdateUserRole(c core.Context, username string, role models.UserRole) error {
+	if username == "" {
+		return errs.ErrUsernameIsEmpty
+	}
+
+	if !role.IsValid() {
+		return errs.ErrUserRoleInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.User{
+		Role:            role,
+		UpdatedUnixTime: now,
+	}
+
+	updatedRows, err := s.UserDB().NewSession(c).Cols("role", "updated_unix_time").Where("username=? AND deleted=?", username, false).Update(updateModel)
+
+	if err != nil {
+		return err
+	} else if updatedRows < 1 {
+		return errs.ErrUserNotFound
+	}
+	return nil
+}
+
 // ExistsUsername returns whether the given user name exists
 func (s *UserService) ExistsUsername(c core.Context, username string) (bool, error) {
 	if username == "" {
@@ -747,3 +837,15 @@ func (s *UserService) SendVerifyEmail(user *models.User, verifyEmailToken string
 func (s *UserService) IsPasswordEqualsUserPassword(password string, user *models.User) bool {
 	return user.Password == utils.EncodePassword(password, user.Salt)
 }
+
+func (s *UserService) buildUserQueryCondition(keyword string) (string, []any) {
+	condition := "deleted=? AND role<>?"
+	conditionParams := []any{false, models.USER_ROLE_LEDGER_BOOK}
+
+	if keyword != "" {
+		condition = condition + " AND (LOWER(username) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?) OR LOWER(nickname) LIKE LOWER(?))"
+		conditionParams = append(conditionParams, "%%"+keyword+"%%", "%%"+keyword+"%%", "%%"+keyword+"%%")
+	}
+
+	return condition, conditionParams
+}
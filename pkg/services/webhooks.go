@@ -0,0 +1,682 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/httpclient"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const webhookSecretLength = 32
+const webhookDeliveryMinRetryInterval = time.Minute
+const webhookDeliveryMaxRetryInterval = 6 * time.Hour
+const webhookDeliveryErrorMessageMaxLength = 255
+const pageCountForDeliveringWebhooks = 100
+
+// Webhook request header names
+const (
+	WebhookEventTypeHeaderName  = "X-Ezbookkeeping-Event"
+	WebhookDeliveryIdHeaderName = "X-Ezbookkeeping-Delivery"
+	WebhookTimestampHeaderName  = "X-Ezbookkeeping-Timestamp"
+	WebhookSignatureHeaderName  = "X-Ezbookkeeping-Signature"
+)
+
+// WebhookService represents webhook service
+type WebhookService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingUuid
+}
+
+// Initialize a webhook service singleton instance
+var (
+	Webhooks = &WebhookService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetTotalWebhookCountByUid returns total webhook count of user
+func (s *WebhookService) GetTotalWebhookCountByUid(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.Webhook{})
+
+	return count, err
+}
+
+// GetAllWebhooksByUid returns all webhook models of user
+func (s *WebhookService) GetAllWebhooksByUid(c core.Context, uid int64) ([]*models.Webhook, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var webhooks []*models.Webhook
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&webhooks)
+
+	return webhooks, err
+}
+
+// GetWebhookByWebhookId returns a webhook model according to webhook id
+func (s *WebhookService) GetWebhookByWebhookId(c core.Context, uid int64, webhookId int64) (*models.Webhook, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if webhookId <= 0 {
+		return nil, errs.ErrWebhookIdInvalid
+	}
+
+	webhook := &models.Webhook{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(webhookId).Where("uid=? AND deleted=?", uid, false).Get(webhook)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+// CreateWebhook saves a new webhook model with a new generated secret to database
+func (s *WebhookService) CreateWebhook(c core.Context, webhook *models.Webhook) error {
+	if webhook.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	webhook.WebhookId = s.GenerateUuid(uuid.UUID_TYPE_AUTOMATION)
+
+	if webhook.WebhookId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	secret, err := utils.GetRandomNumberOrLetter(webhookSecretLength)
+
+	if err != nil {
+		return err
+	}
+
+	webhook.Secret = secret
+	webhook.Deleted = false
+	webhook.CreatedUnixTime = time.Now().Unix()
+	webhook.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(webhook.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(webhook)
+		return err
+	})
+}
+
+// ModifyWebhook saves an existed webhook model to database
+func (s *WebhookService) ModifyWebhook(c core.Context, webhook *models.Webhook) error {
+	if webhook.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	webhook.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(webhook.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(webhook.WebhookId).Cols("name", "url", "event_types", "disabled", "updated_unix_time").Where("uid=? AND deleted=?", webhook.Uid, false).Update(webhook)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrWebhookNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteWebhook deletes an existed webhook from database
+func (s *WebhookService) DeleteWebhook(c core.Context, uid int64, webhookId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Webhook{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(webhookId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrWebhookNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllWebhooks deletes all existed webhooks from database
+func (s *WebhookService) DeleteAllWebhooks(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Webhook{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetDeliveriesByWebhookId returns the delivery models of the specified webhook by page, the latest delivery is returned first
+func (s *WebhookService) GetDeliveriesByWebhookId(c core.Context, uid int64, webhookId int64, page int32, count int32) ([]*models.WebhookDelivery, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if webhookId <= 0 {
+		return nil, errs.ErrWebhookIdInvalid
+	}
+
+	if page < 1 {
+		return nil, errs.ErrPageIndexInvalid
+	}
+
+	if count < 1 {
+		return nil, errs.ErrPageCountInvalid
+	}
+
+	var deliveries []*models.WebhookDelivery
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND webhook_id=?", uid, webhookId).OrderBy("created_unix_time desc, delivery_id desc").Limit(int(count), int(count*(page-1))).Find(&deliveries)
+
+	return deliveries, err
+}
+
+// GetDeliveryByDeliveryId returns a delivery model according to delivery id
+func (s *WebhookService) GetDeliveryByDeliveryId(c core.Context, uid int64, deliveryId int64) (*models.WebhookDelivery, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if deliveryId <= 0 {
+		return nil, errs.ErrWebhookDeliveryIdInvalid
+	}
+
+	delivery := &models.WebhookDelivery{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(deliveryId).Where("uid=?", uid).Get(delivery)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrWebhookDeliveryNotFound
+	}
+
+	return delivery, nil
+}
+
+// ReplayDelivery saves a new pending delivery with the same event of the specified delivery to database
+func (s *WebhookService) ReplayDelivery(c core.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	if delivery.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+	newDelivery := &models.WebhookDelivery{
+		DeliveryId:          s.GenerateUuid(uuid.UUID_TYPE_AUTOMATION),
+		Uid:                 delivery.Uid,
+		WebhookId:           delivery.WebhookId,
+		EventId:             delivery.EventId,
+		EventType:           delivery.EventType,
+		Payload:             delivery.Payload,
+		Status:              models.WEBHOOK_DELIVERY_STATUS_PENDING,
+		AttemptCount:        0,
+		NextAttemptUnixTime: now,
+		CreatedUnixTime:     now,
+		UpdatedUnixTime:     now,
+	}
+
+	if newDelivery.DeliveryId < 1 {
+		return nil, errs.ErrSystemIsBusy
+	}
+
+	err := s.UserDataDB(delivery.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(newDelivery)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newDelivery, nil
+}
+
+// DeliverPendingWebhooks sends the pending webhook deliveries which reach the next attempt time, and reschedules the failed deliveries with exponential backoff
+func (s *WebhookService) DeliverPendingWebhooks(c core.Context, currentUnixTime int64) error {
+	config := s.CurrentConfig()
+
+	if !config.EnableWebhook {
+		return errs.ErrWebhookNotEnabled
+	}
+
+	var httpClient *http.Client
+
+	if config.WebhookAllowPrivateNetwork {
+		httpClient = httpclient.NewHttpClient(config.WebhookRequestTimeout, config.WebhookProxy, config.WebhookSkipTLSVerify, core.GetOutgoingUserAgent(), false)
+	} else {
+		httpClient = httpclient.NewPublicNetworkHttpClient(config.WebhookRequestTimeout, config.WebhookProxy, config.WebhookSkipTLSVerify, core.GetOutgoingUserAgent(), false)
+	}
+
+	totalCount := 0
+	succeededCount := 0
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		database := s.UserDataDBByIndex(i)
+
+		var deliveries []*models.WebhookDelivery
+		err := database.NewSession(c).Where("status=? AND next_attempt_unix_time<=?", models.WEBHOOK_DELIVERY_STATUS_PENDING, currentUnixTime).OrderBy("next_attempt_unix_time asc").Limit(pageCountForDeliveringWebhooks).Find(&deliveries)
+
+		if err != nil {
+			log.Errorf(c, "[webhooks.DeliverPendingWebhooks] failed to get pending webhook deliveries, because %s", err.Error())
+			return err
+		}
+
+		webhooks := make(map[int64]*models.Webhook)
+
+		for j := 0; j < len(deliveries); j++ {
+			delivery := deliveries[j]
+			webhook, exists := webhooks[delivery.WebhookId]
+
+			if !exists {
+				webhook = &models.Webhook{}
+				has, err := database.NewSession(c).ID(delivery.WebhookId).Where("uid=? AND deleted=?", delivery.Uid, false).Get(webhook)
+
+				if err != nil {
+					log.Errorf(c, "[webhooks.DeliverPendingWebhooks] failed to get webhook \"id:%d\" for user \"uid:%d\", because %s", delivery.WebhookId, delivery.Uid, err.Error())
+					continue
+				} else if !has {
+					webhook = nil
+				}
+
+				webhooks[delivery.WebhookId] = webhook
+			}
+
+			totalCount++
+
+			if webhook == nil || webhook.Disabled {
+				delivery.Status = models.WEBHOOK_DELIVERY_STATUS_FAILED
+				delivery.LastErrorMessage = "webhook has been deleted or disabled"
+			} else {
+				statusCode, err := s.sendWebhookRequest(c, httpClient, webhook, delivery, currentUnixTime)
+				delivery.AttemptCount++
+				delivery.LastResponseStatusCode = int32(statusCode)
+
+				if err == nil {
+					delivery.Status = models.WEBHOOK_DELIVERY_STATUS_SUCCEEDED
+					delivery.LastErrorMessage = ""
+					succeededCount++
+				} else {
+					log.Warnf(c, "[webhooks.DeliverPendingWebhooks] failed to deliver webhook delivery \"id:%d\" for user \"uid:%d\", because %s", delivery.DeliveryId, delivery.Uid, err.Error())
+					delivery.LastErrorMessage = utils.SubString(err.Error(), 0, webhookDeliveryErrorMessageMaxLength)
+
+					if uint32(delivery.AttemptCount) > config.WebhookMaxRetryCount {
+						delivery.Status = models.WEBHOOK_DELIVERY_STATUS_FAILED
+					} else {
+						delivery.NextAttemptUnixTime = currentUnixTime + int64(getWebhookDeliveryRetryInterval(delivery.AttemptCount)/time.Second)
+					}
+				}
+			}
+
+			delivery.UpdatedUnixTime = time.Now().Unix()
+			_, err = database.NewSession(c).ID(delivery.DeliveryId).Cols("status", "attempt_count", "next_attempt_unix_time", "last_response_status_code", "last_error_message", "updated_unix_time").Where("uid=?", delivery.Uid).Update(delivery)
+
+			if err != nil {
+				log.Errorf(c, "[webhooks.DeliverPendingWebhooks] failed to update webhook delivery \"id:%d\" for user \"uid:%d\", because %s", delivery.DeliveryId, delivery.Uid, err.Error())
+			}
+		}
+	}
+
+	if totalCount > 0 {
+		log.Infof(c, "[webhooks.DeliverPendingWebhooks] %d of %d webhook deliveries have been delivered successfully", succeededCount, totalCount)
+	}
+
+	return nil
+}
+
+func (s *WebhookService) sendWebhookRequest(c core.Context, httpClient *http.Client, webhook *models.Webhook, delivery *models.WebhookDelivery, currentUnixTime int64) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := utils.Int64ToString(currentUnixTime)
+
+	req, err := http.NewRequestWithContext(c, http.MethodPost, webhook.Url, bytes.NewReader(body))
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventTypeHeaderName, string(delivery.EventType))
+	req.Header.Set(WebhookDeliveryIdHeaderName, utils.Int64ToString(delivery.DeliveryId))
+	req.Header.Set(WebhookTimestampHeaderName, timestamp)
+	req.Header.Set(WebhookSignatureHeaderName, "sha256="+GetWebhookSignature(webhook.Secret, timestamp, body))
+
+	resp, err := httpClient.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("response code is %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// GetWebhookSignature returns the hmac-sha256 signature of the webhook request, which is signed from the timestamp and the request body joined by a dot
+func GetWebhookSignature(secret string, timestamp string, body []byte) string {
+	content := make([]byte, 0, len(timestamp)+1+len(body))
+	content = append(content, timestamp...)
+	content = append(content, '.')
+	content = append(content, body...)
+
+	return utils.HmacSha256EncodeToString([]byte(secret), content)
+}
+
+func getWebhookDeliveryRetryInterval(attemptCount int32) time.Duration {
+	interval := webhookDeliveryMinRetryInterval
+
+	for i := int32(1); i < attemptCount && interval < webhookDeliveryMaxRetryInterval; i++ {
+		interval *= 2
+	}
+
+	if interval > webhookDeliveryMaxRetryInterval {
+		interval = webhookDeliveryMaxRetryInterval
+	}
+
+	return interval
+}
+
+// webhookEventPublisher represents the publisher which saves the deliveries of data change events for the subscribed webhooks of user
+type webhookEventPublisher struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingUuid
+}
+
+// Initialize a webhook event publisher singleton instance
+var (
+	webhookEvents = &webhookEventPublisher{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+func (p *webhookEventPublisher) publishTransactionEvent(c core.Context, eventType models.WebhookEventType, transaction *models.Transaction) {
+	if transaction == nil {
+		return
+	}
+
+	transactionType, err := transaction.Type.ToTransactionType()
+
+	if err != nil {
+		log.Warnf(c, "[webhooks.publishTransactionEvent] failed to get transaction type of transaction \"id:%d\", because %s", transaction.TransactionId, err.Error())
+		return
+	}
+
+	data := &models.WebhookTransactionEventData{
+		Id:              transaction.TransactionId,
+		Type:            transactionType,
+		CategoryId:      transaction.CategoryId,
+		Time:            utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime),
+		UtcOffset:       transaction.TimezoneUtcOffset,
+		SourceAccountId: transaction.AccountId,
+		SourceAmount:    transaction.Amount,
+		Comment:         transaction.Comment,
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		data.DestinationAccountId = transaction.RelatedAccountId
+		data.DestinationAmount = transaction.RelatedAccountAmount
+	}
+
+	p.publish(c, transaction.Uid, eventType, data)
+}
+
+func (p *webhookEventPublisher) publishTransactionsModifiedEvents(c core.Context, uid int64, transactionIds []int64) {
+	if !p.CurrentConfig().EnableWebhook || uid <= 0 || len(transactionIds) < 1 {
+		return
+	}
+
+	var transactions []*models.Transaction
+	err := p.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", utils.ToUniqueInt64Slice(transactionIds)).Find(&transactions)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.publishTransactionsModifiedEvents] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return
+	}
+
+	publishedTransactionIds := make(map[int64]bool, len(transactions))
+	relatedTransactionIds := make([]int64, 0)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		// the event of transfer transaction is always published with the transfer out transaction
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			relatedTransactionIds = append(relatedTransactionIds, transaction.RelatedId)
+			continue
+		}
+
+		publishedTransactionIds[transaction.TransactionId] = true
+		p.publishTransactionEvent(c, models.WEBHOOK_EVENT_TYPE_TRANSACTION_MODIFIED, transaction)
+	}
+
+	if len(relatedTransactionIds) < 1 {
+		return
+	}
+
+	var relatedTransactions []*models.Transaction
+	err = p.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", utils.ToUniqueInt64Slice(relatedTransactionIds)).Find(&relatedTransactions)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.publishTransactionsModifiedEvents] failed to get related transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return
+	}
+
+	for i := 0; i < len(relatedTransactions); i++ {
+		transaction := relatedTransactions[i]
+
+		if publishedTransactionIds[transaction.TransactionId] {
+			continue
+		}
+
+		publishedTransactionIds[transaction.TransactionId] = true
+		p.publishTransactionEvent(c, models.WEBHOOK_EVENT_TYPE_TRANSACTION_MODIFIED, transaction)
+	}
+}
+
+func (p *webhookEventPublisher) publishAccountBalanceChangedEvents(c core.Context, uid int64, accountIds []int64) {
+	if !p.CurrentConfig().EnableWebhook {
+		return
+	}
+
+	validAccountIds := make([]int64, 0, len(accountIds))
+
+	for i := 0; i < len(accountIds); i++ {
+		if accountIds[i] > 0 {
+			validAccountIds = append(validAccountIds, accountIds[i])
+		}
+	}
+
+	validAccountIds = utils.ToUniqueInt64Slice(validAccountIds)
+
+	if len(validAccountIds) < 1 {
+		return
+	}
+
+	var accounts []*models.Account
+	err := p.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("account_id", validAccountIds).Find(&accounts)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.publishAccountBalanceChangedEvents] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return
+	}
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		if account.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS {
+			continue
+		}
+
+		p.publish(c, uid, models.WEBHOOK_EVENT_TYPE_ACCOUNT_BALANCE_CHANGED, &models.WebhookAccountBalanceChangedEventData{
+			AccountId: account.AccountId,
+			Currency:  account.Currency,
+			Balance:   account.Balance,
+		})
+	}
+}
+
+func (p *webhookEventPublisher) publishImportCompletedEvent(c core.Context, uid int64, transactionCount int) {
+	p.publish(c, uid, models.WEBHOOK_EVENT_TYPE_IMPORT_COMPLETED, &models.WebhookImportCompletedEventData{
+		TransactionCount: transactionCount,
+	})
+}
+
+func (p *webhookEventPublisher) publish(c core.Context, uid int64, eventType models.WebhookEventType, data any) {
+	if !p.CurrentConfig().EnableWebhook || uid <= 0 {
+		return
+	}
+
+	var webhooks []*models.Webhook
+	err := p.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND disabled=?", uid, false, false).Find(&webhooks)
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.publish] failed to get webhooks for user \"uid:%d\", because %s", uid, err.Error())
+		return
+	}
+
+	subscribedWebhooks := make([]*models.Webhook, 0, len(webhooks))
+
+	for i := 0; i < len(webhooks); i++ {
+		if webhooks[i].IsSubscribed(eventType) {
+			subscribedWebhooks = append(subscribedWebhooks, webhooks[i])
+		}
+	}
+
+	if len(subscribedWebhooks) < 1 {
+		return
+	}
+
+	uuids := p.GenerateUuids(uuid.UUID_TYPE_AUTOMATION, uint16(len(subscribedWebhooks)+1))
+
+	if len(uuids) < len(subscribedWebhooks)+1 {
+		log.Errorf(c, "[webhooks.publish] failed to generate uuids for webhook event \"%s\" of user \"uid:%d\"", eventType, uid)
+		return
+	}
+
+	now := time.Now().Unix()
+	eventId := uuids[0]
+	payload, err := json.Marshal(&models.WebhookEventPayload{
+		EventId:   eventId,
+		EventType: eventType,
+		CreatedAt: now,
+		Data:      data,
+	})
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.publish] failed to marshal webhook event \"%s\" of user \"uid:%d\", because %s", eventType, uid, err.Error())
+		return
+	}
+
+	deliveries := make([]*models.WebhookDelivery, len(subscribedWebhooks))
+
+	for i := 0; i < len(subscribedWebhooks); i++ {
+		deliveries[i] = &models.WebhookDelivery{
+			DeliveryId:          uuids[i+1],
+			Uid:                 uid,
+			WebhookId:           subscribedWebhooks[i].WebhookId,
+			EventId:             eventId,
+			EventType:           eventType,
+			Payload:             string(payload),
+			Status:              models.WEBHOOK_DELIVERY_STATUS_PENDING,
+			NextAttemptUnixTime: now,
+			CreatedUnixTime:     now,
+			UpdatedUnixTime:     now,
+		}
+	}
+
+	err = p.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(deliveries)
+		return err
+	})
+
+	if err != nil {
+		log.Errorf(c, "[webhooks.publish] failed to save deliveries of webhook event \"%s\" for user \"uid:%d\", because %s", eventType, uid, err.Error())
+		return
+	}
+
+	log.Debugf(c, "[webhooks.publish] webhook event \"%s\" of user \"uid:%d\" has been published to %d webhooks", eventType, uid, len(deliveries))
+}
+
+// GetWebhookEventTypesString returns the textual event types separated by commas which is stored in database
+func GetWebhookEventTypesString(eventTypes []models.WebhookEventType) string {
+	items := make([]string, 0, len(eventTypes))
+	existedItems := make(map[models.WebhookEventType]bool, len(eventTypes))
+
+	for i := 0; i < len(eventTypes); i++ {
+		if existedItems[eventTypes[i]] {
+			continue
+		}
+
+		existedItems[eventTypes[i]] = true
+		items = append(items, string(eventTypes[i]))
+	}
+
+	return strings.Join(items, ",")
+}
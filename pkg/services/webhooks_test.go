@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestGetWebhookSignature(t *testing.T) {
+	expectedValue := utils.HmacSha256EncodeToString([]byte("secret"), []byte("1700000000.{\"eventType\":\"transaction.created\"}"))
+	actualValue := GetWebhookSignature("secret", "1700000000", []byte("{\"eventType\":\"transaction.created\"}"))
+	assert.Equal(t, expectedValue, actualValue)
+
+	actualValue = GetWebhookSignature("another", "1700000000", []byte("{\"eventType\":\"transaction.created\"}"))
+	assert.NotEqual(t, expectedValue, actualValue)
+}
+
+func TestGetWebhookDeliveryRetryInterval(t *testing.T) {
+	assert.Equal(t, time.Minute, getWebhookDeliveryRetryInterval(1))
+	assert.Equal(t, 2*time.Minute, getWebhookDeliveryRetryInterval(2))
+	assert.Equal(t, 4*time.Minute, getWebhookDeliveryRetryInterval(3))
+	assert.Equal(t, 256*time.Minute, getWebhookDeliveryRetryInterval(9))
+	assert.Equal(t, 6*time.Hour, getWebhookDeliveryRetryInterval(10))
+	assert.Equal(t, 6*time.Hour, getWebhookDeliveryRetryInterval(100))
+}
+
+func TestGetWebhookEventTypesString(t *testing.T) {
+	actualValue := GetWebhookEventTypesString([]models.WebhookEventType{
+		models.WEBHOOK_EVENT_TYPE_TRANSACTION_CREATED,
+		models.WEBHOOK_EVENT_TYPE_IMPORT_COMPLETED,
+		models.WEBHOOK_EVENT_TYPE_TRANSACTION_CREATED,
+	})
+	assert.Equal(t, "transaction.created,import.completed", actualValue)
+}
@@ -3,6 +3,7 @@ package settings
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -103,6 +104,7 @@ const (
 // Duplicate checker types
 const (
 	InMemoryDuplicateCheckerType string = "in_memory"
+	DatabaseDuplicateCheckerType string = "database"
 )
 
 // OAuth 2.0 user identifier types
@@ -119,6 +121,12 @@ const (
 	OAuth2ProviderGithub    string = "github"
 )
 
+// SAML 2.0 user identifier types
+const (
+	SAMLUserIdentifierEmail    string = "email"
+	SAMLUserIdentifierUsername string = "username"
+)
+
 // Map provider types
 const (
 	OpenStreetMapProvider                  string = "openstreetmap"
@@ -164,6 +172,11 @@ const (
 	UserCustomExchangeRatesDataSource  string = "user_custom"
 )
 
+// Security prices data source types
+const (
+	StooqSecurityPricesDataSource string = "stooq"
+)
+
 const (
 	defaultHttpAddr string = "0.0.0.0"
 	defaultHttpPort uint16 = 8080
@@ -187,28 +200,51 @@ const (
 	defaultLargeLanguageModelAPIRequestTimeout         uint32 = 60000 // 60 seconds
 
 	defaultInMemoryDuplicateCheckerCleanupInterval uint32 = 60  // 1 minutes
+	defaultDatabaseDuplicateCheckerCleanupInterval uint32 = 60  // 1 minutes
 	defaultDuplicateSubmissionsInterval            uint32 = 300 // 5 minutes
 
-	defaultSecretKey                     string = "ezbookkeeping"
-	defaultTrustedProxyIPs               string = "10.0.0.0/8,169.254.0.0/16,127.0.0.0/8,172.16.0.0/12,192.168.0.0/16"
-	defaultTokenExpiredTime              uint32 = 2592000 // 30 days
-	defaultTokenMinRefreshInterval       uint32 = 86400   // 1 day
-	defaultTemporaryTokenExpiredTime     uint32 = 300     // 5 minutes
-	defaultEmailVerifyTokenExpiredTime   uint32 = 3600    // 60 minutes
-	defaultPasswordResetTokenExpiredTime uint32 = 3600    // 60 minutes
-	defaultMaxFailuresPerIpPerMinute     uint32 = 5
-	defaultMaxFailuresPerUserPerMinute   uint32 = 5
+	defaultSecretKey                        string = "ezbookkeeping"
+	defaultTrustedProxyIPs                  string = "10.0.0.0/8,169.254.0.0/16,127.0.0.0/8,172.16.0.0/12,192.168.0.0/16"
+	defaultTokenExpiredTime                 uint32 = 2592000 // 30 days
+	defaultTokenMinRefreshInterval          uint32 = 86400   // 1 day
+	defaultTemporaryTokenExpiredTime        uint32 = 300     // 5 minutes
+	defaultEmailVerifyTokenExpiredTime      uint32 = 3600    // 60 minutes
+	defaultPasswordResetTokenExpiredTime    uint32 = 3600    // 60 minutes
+	defaultLedgerInvitationTokenExpiredTime uint32 = 604800  // 7 days
+	defaultMaxFailuresPerIpPerMinute        uint32 = 5
+	defaultMaxFailuresPerUserPerMinute      uint32 = 5
+
+	defaultMCPSessionExpiredTime uint32 = 3600 // 60 minutes
 
 	defaultOAuth2StateExpiredTime uint32 = 300   // 5 minutes
 	defaultOAuth2RequestTimeout   uint32 = 10000 // 10 seconds
 
+	defaultLDAPUserFilter        string = "(uid=%s)"
+	defaultLDAPUserNameAttribute string = "uid"
+	defaultLDAPEmailAttribute    string = "mail"
+	defaultLDAPNickNameAttribute string = "cn"
+	defaultLDAPRequestTimeout    uint32 = 10000 // 10 seconds
+
+	defaultSAMLUserNameAttribute string = "uid"
+	defaultSAMLEmailAttribute    string = "email"
+	defaultSAMLNickNameAttribute string = "displayName"
+	defaultSAMLAllowedClockSkew  uint32 = 180   // 3 minutes
+	defaultSAMLStateExpiredTime  uint32 = 300   // 5 minutes
+	defaultSAMLRequestTimeout    uint32 = 10000 // 10 seconds
+
 	defaultUserCustomIconFileMaxSize     uint32 = 1048576  // 1MB
 	defaultTransactionPictureFileMaxSize uint32 = 10485760 // 10MB
 	defaultUserAvatarFileMaxSize         uint32 = 1048576  // 1MB
 
-	defaultImportFileMaxSize uint32 = 10485760 // 10MB
+	defaultImportFileMaxSize     uint32 = 10485760  // 10MB
+	defaultBackupDataFileMaxSize uint32 = 104857600 // 100MB
 
 	defaultExchangeRatesDataRequestTimeout uint32 = 10000 // 10 seconds
+
+	defaultSecurityPricesDataRequestTimeout uint32 = 10000 // 10 seconds
+
+	defaultWebhookRequestTimeout uint32 = 10000 // 10 seconds
+	defaultWebhookMaxRetryCount  uint32 = 8
 )
 
 // DatabaseConfig represents the database setting config
@@ -326,8 +362,10 @@ type Config struct {
 	EnableRequestIdHeader bool
 
 	// MCP
-	EnableMCPServer     bool
-	MCPAllowedRemoteIPs []*core.IPPattern
+	EnableMCPServer               bool
+	MCPAllowedRemoteIPs           []*core.IPPattern
+	MCPSessionExpiredTime         uint32
+	MCPSessionExpiredTimeDuration time.Duration
 
 	// Database
 	DatabaseConfig     *DatabaseConfig
@@ -377,6 +415,8 @@ type Config struct {
 	DuplicateCheckerType                            string
 	InMemoryDuplicateCheckerCleanupInterval         uint32
 	InMemoryDuplicateCheckerCleanupIntervalDuration time.Duration
+	DatabaseDuplicateCheckerCleanupInterval         uint32
+	DatabaseDuplicateCheckerCleanupIntervalDuration time.Duration
 	EnableDuplicateSubmissionsCheck                 bool
 	DuplicateSubmissionsInterval                    uint32
 	DuplicateSubmissionsIntervalDuration            time.Duration
@@ -384,30 +424,37 @@ type Config struct {
 	// Cron
 	EnableRemoveExpiredTokens        bool
 	EnableCreateScheduledTransaction bool
+	EnableSendBudgetAlerts           bool
+	EnableSnapshotExchangeRates      bool
 
 	// Secret
-	SecretKeyNoSet                        bool
-	SecretKey                             string
-	TrustedProxyIPs                       []*net.IPNet
-	TrustedProxyTextualIPs                []string
-	TokenExpiredTime                      uint32
-	TokenExpiredTimeDuration              time.Duration
-	TokenMinRefreshInterval               uint32
-	TemporaryTokenExpiredTime             uint32
-	TemporaryTokenExpiredTimeDuration     time.Duration
-	EmailVerifyTokenExpiredTime           uint32
-	EmailVerifyTokenExpiredTimeDuration   time.Duration
-	PasswordResetTokenExpiredTime         uint32
-	PasswordResetTokenExpiredTimeDuration time.Duration
-	EnableAPIToken                        bool
-	APITokenAllowedRemoteIPs              []*core.IPPattern
-	MaxFailuresPerIpPerMinute             uint32
-	MaxFailuresPerUserPerMinute           uint32
+	SecretKeyNoSet                           bool
+	SecretKey                                string
+	TrustedProxyIPs                          []*net.IPNet
+	TrustedProxyTextualIPs                   []string
+	TokenExpiredTime                         uint32
+	TokenExpiredTimeDuration                 time.Duration
+	TokenMinRefreshInterval                  uint32
+	TemporaryTokenExpiredTime                uint32
+	TemporaryTokenExpiredTimeDuration        time.Duration
+	EmailVerifyTokenExpiredTime              uint32
+	EmailVerifyTokenExpiredTimeDuration      time.Duration
+	PasswordResetTokenExpiredTime            uint32
+	PasswordResetTokenExpiredTimeDuration    time.Duration
+	LedgerInvitationTokenExpiredTime         uint32
+	LedgerInvitationTokenExpiredTimeDuration time.Duration
+	EnableAPIToken                           bool
+	APITokenAllowedRemoteIPs                 []*core.IPPattern
+	MaxFailuresPerIpPerMinute                uint32
+	MaxFailuresPerUserPerMinute              uint32
 
 	// Auth
 	EnableInternalAuth                bool
 	EnableOAuth2Login                 bool
 	EnableTwoFactor                   bool
+	EnablePasskey                     bool
+	PasskeyRPID                       string
+	PasskeyRPOrigins                  []string
 	EnableUserForgetPassword          bool
 	ForgetPasswordRequireVerifyEmail  bool
 	OAuth2ClientID                    string
@@ -426,6 +473,39 @@ type Config struct {
 	OAuth2OIDCCustomDisplayNameConfig MultiLanguageContentConfig
 	OAuth2NextcloudBaseUrl            string
 	OAuth2GiteaBaseUrl                string
+	EnableLDAPLogin                   bool
+	LDAPServerUrl                     string
+	LDAPStartTLS                      bool
+	LDAPSkipTLSVerify                 bool
+	LDAPBindDN                        string
+	LDAPBindPassword                  string
+	LDAPBaseDN                        string
+	LDAPUserFilter                    string
+	LDAPGroupFilter                   string
+	LDAPUserNameAttribute             string
+	LDAPEmailAttribute                string
+	LDAPNickNameAttribute             string
+	LDAPAutoRegister                  bool
+	LDAPRequestTimeout                uint32
+	EnableSAMLLogin                   bool
+	SAMLIdPMetadataUrl                string
+	SAMLIdPMetadataFile               string
+	SAMLSPEntityID                    string
+	SAMLSPCertificateFile             string
+	SAMLSPPrivateKeyFile              string
+	SAMLSignAuthnRequest              bool
+	SAMLUserIdentifier                string
+	SAMLUserNameAttribute             string
+	SAMLEmailAttribute                string
+	SAMLNickNameAttribute             string
+	SAMLAutoRegister                  bool
+	SAMLAllowedClockSkew              uint32
+	SAMLAllowedClockSkewDuration      time.Duration
+	SAMLStateExpiredTime              uint32
+	SAMLStateExpiredTimeDuration      time.Duration
+	SAMLRequestTimeout                uint32
+	SAMLProxy                         string
+	SAMLSkipTLSVerify                 bool
 
 	// User
 	EnableUserRegister            bool
@@ -441,9 +521,10 @@ type Config struct {
 	DefaultFeatureRestrictions    core.UserFeatureRestrictions
 
 	// Data
-	EnableDataExport  bool
-	EnableDataImport  bool
-	MaxImportFileSize uint32
+	EnableDataExport      bool
+	EnableDataImport      bool
+	MaxImportFileSize     uint32
+	MaxBackupDataFileSize uint32
 
 	// Tip
 	LoginPageTips MultiLanguageContentConfig
@@ -477,6 +558,20 @@ type Config struct {
 	ExchangeRatesRequestTimeoutExceedDefaultValue bool
 	ExchangeRatesProxy                            string
 	ExchangeRatesSkipTLSVerify                    bool
+
+	// Security Prices
+	SecurityPricesDataSource     string
+	SecurityPricesRequestTimeout uint32
+	SecurityPricesProxy          string
+	SecurityPricesSkipTLSVerify  bool
+
+	// Webhook
+	EnableWebhook              bool
+	WebhookRequestTimeout      uint32
+	WebhookProxy               string
+	WebhookSkipTLSVerify       bool
+	WebhookAllowPrivateNetwork bool
+	WebhookMaxRetryCount       uint32
 }
 
 // LoadConfiguration loads setting config from given config file path
@@ -624,6 +719,18 @@ func LoadConfiguration(configFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	err = loadSecurityPricesConfiguration(config, cfgFile, "security_prices")
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadWebhookConfiguration(config, cfgFile, "webhook")
+
+	if err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -715,6 +822,14 @@ func loadMCPServerConfiguration(config *Config, configFile *ini.File, sectionNam
 		return err
 	}
 
+	config.MCPSessionExpiredTime = getConfigItemUint32Value(configFile, sectionName, "mcp_session_expired_time", defaultMCPSessionExpiredTime)
+
+	if config.MCPSessionExpiredTime < 60 {
+		return errs.ErrInvalidMCPSessionExpiredTime
+	}
+
+	config.MCPSessionExpiredTimeDuration = time.Duration(config.MCPSessionExpiredTime) * time.Second
+
 	return nil
 }
 
@@ -988,19 +1103,29 @@ func loadUuidConfiguration(config *Config, configFile *ini.File, sectionName str
 }
 
 func loadDuplicateCheckerConfiguration(config *Config, configFile *ini.File, sectionName string) error {
-	if getConfigItemStringValue(configFile, sectionName, "checker_type") == InMemoryDuplicateCheckerType {
+	checkerType := getConfigItemStringValue(configFile, sectionName, "checker_type")
+
+	if checkerType == InMemoryDuplicateCheckerType {
 		config.DuplicateCheckerType = InMemoryDuplicateCheckerType
-	} else {
-		return errs.ErrInvalidDuplicateCheckerType
-	}
+		config.InMemoryDuplicateCheckerCleanupInterval = getConfigItemUint32Value(configFile, sectionName, "cleanup_interval", defaultInMemoryDuplicateCheckerCleanupInterval)
 
-	config.InMemoryDuplicateCheckerCleanupInterval = getConfigItemUint32Value(configFile, sectionName, "cleanup_interval", defaultInMemoryDuplicateCheckerCleanupInterval)
+		if config.InMemoryDuplicateCheckerCleanupInterval < 1 {
+			return errs.ErrInvalidInMemoryDuplicateCheckerCleanupInterval
+		}
 
-	if config.InMemoryDuplicateCheckerCleanupInterval < 1 {
-		return errs.ErrInvalidInMemoryDuplicateCheckerCleanupInterval
-	}
+		config.InMemoryDuplicateCheckerCleanupIntervalDuration = time.Duration(config.InMemoryDuplicateCheckerCleanupInterval) * time.Second
+	} else if checkerType == DatabaseDuplicateCheckerType {
+		config.DuplicateCheckerType = DatabaseDuplicateCheckerType
+		config.DatabaseDuplicateCheckerCleanupInterval = getConfigItemUint32Value(configFile, sectionName, "cleanup_interval", defaultDatabaseDuplicateCheckerCleanupInterval)
 
-	config.InMemoryDuplicateCheckerCleanupIntervalDuration = time.Duration(config.InMemoryDuplicateCheckerCleanupInterval) * time.Second
+		if config.DatabaseDuplicateCheckerCleanupInterval < 1 {
+			return errs.ErrInvalidDatabaseDuplicateCheckerCleanupInterval
+		}
+
+		config.DatabaseDuplicateCheckerCleanupIntervalDuration = time.Duration(config.DatabaseDuplicateCheckerCleanupInterval) * time.Second
+	} else {
+		return errs.ErrInvalidDuplicateCheckerType
+	}
 
 	duplicateSubmissionsInterval := getConfigItemUint32Value(configFile, sectionName, "duplicate_submissions_interval", defaultDuplicateSubmissionsInterval)
 
@@ -1019,6 +1144,8 @@ func loadDuplicateCheckerConfiguration(config *Config, configFile *ini.File, sec
 func loadCronConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableRemoveExpiredTokens = getConfigItemBoolValue(configFile, sectionName, "enable_remove_expired_tokens", false)
 	config.EnableCreateScheduledTransaction = getConfigItemBoolValue(configFile, sectionName, "enable_create_scheduled_transaction", false)
+	config.EnableSendBudgetAlerts = getConfigItemBoolValue(configFile, sectionName, "enable_send_budget_alerts", false)
+	config.EnableSnapshotExchangeRates = getConfigItemBoolValue(configFile, sectionName, "enable_snapshot_exchange_rates", false)
 
 	return nil
 }
@@ -1072,6 +1199,14 @@ func loadSecurityConfiguration(config *Config, configFile *ini.File, sectionName
 
 	config.PasswordResetTokenExpiredTimeDuration = time.Duration(config.PasswordResetTokenExpiredTime) * time.Second
 
+	config.LedgerInvitationTokenExpiredTime = getConfigItemUint32Value(configFile, sectionName, "ledger_invitation_token_expired_time", defaultLedgerInvitationTokenExpiredTime)
+
+	if config.LedgerInvitationTokenExpiredTime < 60 {
+		return errs.ErrInvalidLedgerInvitationTokenExpiredTime
+	}
+
+	config.LedgerInvitationTokenExpiredTimeDuration = time.Duration(config.LedgerInvitationTokenExpiredTime) * time.Second
+
 	config.EnableAPIToken = getConfigItemBoolValue(configFile, sectionName, "enable_api_token", false)
 	config.APITokenAllowedRemoteIPs, err = getIPPatterns(configFile, sectionName, "api_token_allowed_remote_ips", "")
 
@@ -1089,6 +1224,9 @@ func loadAuthConfiguration(config *Config, configFile *ini.File, sectionName str
 	config.EnableInternalAuth = getConfigItemBoolValue(configFile, sectionName, "enable_internal_auth", true)
 	config.EnableOAuth2Login = getConfigItemBoolValue(configFile, sectionName, "enable_oauth2_auth", false)
 	config.EnableTwoFactor = getConfigItemBoolValue(configFile, sectionName, "enable_two_factor", true)
+	config.EnablePasskey = getConfigItemBoolValue(configFile, sectionName, "enable_passkey", false)
+	config.PasskeyRPID = getConfigItemStringValue(configFile, sectionName, "passkey_rp_id", config.Domain)
+	config.PasskeyRPOrigins = getStringList(configFile, sectionName, "passkey_rp_origins", getRootUrlOrigin(config.RootUrl))
 	config.EnableUserForgetPassword = getConfigItemBoolValue(configFile, sectionName, "enable_forget_password", false)
 	config.ForgetPasswordRequireVerifyEmail = getConfigItemBoolValue(configFile, sectionName, "forget_password_require_email_verify", false)
 	config.OAuth2ClientID = getConfigItemStringValue(configFile, sectionName, "oauth2_client_id")
@@ -1141,6 +1279,59 @@ func loadAuthConfiguration(config *Config, configFile *ini.File, sectionName str
 	config.OAuth2NextcloudBaseUrl = getConfigItemStringValue(configFile, sectionName, "nextcloud_base_url")
 	config.OAuth2GiteaBaseUrl = getConfigItemStringValue(configFile, sectionName, "gitea_base_url")
 
+	config.EnableLDAPLogin = getConfigItemBoolValue(configFile, sectionName, "enable_ldap_auth", false)
+	config.LDAPServerUrl = getConfigItemStringValue(configFile, sectionName, "ldap_server_url")
+	config.LDAPStartTLS = getConfigItemBoolValue(configFile, sectionName, "ldap_start_tls", false)
+	config.LDAPSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "ldap_skip_tls_verify", false)
+	config.LDAPBindDN = getConfigItemStringValue(configFile, sectionName, "ldap_bind_dn")
+	config.LDAPBindPassword = getConfigItemStringValue(configFile, sectionName, "ldap_bind_password")
+	config.LDAPBaseDN = getConfigItemStringValue(configFile, sectionName, "ldap_base_dn")
+	config.LDAPUserFilter = getConfigItemStringValue(configFile, sectionName, "ldap_user_filter", defaultLDAPUserFilter)
+	config.LDAPGroupFilter = getConfigItemStringValue(configFile, sectionName, "ldap_group_filter")
+	config.LDAPUserNameAttribute = getConfigItemStringValue(configFile, sectionName, "ldap_username_attribute", defaultLDAPUserNameAttribute)
+	config.LDAPEmailAttribute = getConfigItemStringValue(configFile, sectionName, "ldap_email_attribute", defaultLDAPEmailAttribute)
+	config.LDAPNickNameAttribute = getConfigItemStringValue(configFile, sectionName, "ldap_nickname_attribute", defaultLDAPNickNameAttribute)
+	config.LDAPAutoRegister = getConfigItemBoolValue(configFile, sectionName, "ldap_auto_register", true)
+	config.LDAPRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "ldap_request_timeout", defaultLDAPRequestTimeout)
+
+	config.EnableSAMLLogin = getConfigItemBoolValue(configFile, sectionName, "enable_saml_auth", false)
+	config.SAMLIdPMetadataUrl = getConfigItemStringValue(configFile, sectionName, "saml_idp_metadata_url")
+	config.SAMLIdPMetadataFile = getConfigItemStringValue(configFile, sectionName, "saml_idp_metadata_file")
+	config.SAMLSPEntityID = getConfigItemStringValue(configFile, sectionName, "saml_sp_entity_id", config.RootUrl+"saml/metadata")
+	config.SAMLSPCertificateFile = getConfigItemStringValue(configFile, sectionName, "saml_sp_cert_file")
+	config.SAMLSPPrivateKeyFile = getConfigItemStringValue(configFile, sectionName, "saml_sp_cert_key_file")
+	config.SAMLSignAuthnRequest = getConfigItemBoolValue(configFile, sectionName, "saml_sign_authn_request", true)
+
+	samlUserIdentifier := getConfigItemStringValue(configFile, sectionName, "saml_user_identifier", SAMLUserIdentifierEmail)
+
+	if samlUserIdentifier == SAMLUserIdentifierEmail {
+		config.SAMLUserIdentifier = SAMLUserIdentifierEmail
+	} else if samlUserIdentifier == SAMLUserIdentifierUsername {
+		config.SAMLUserIdentifier = SAMLUserIdentifierUsername
+	} else {
+		return errs.ErrInvalidSAMLUserIdentifier
+	}
+
+	config.SAMLUserNameAttribute = getConfigItemStringValue(configFile, sectionName, "saml_username_attribute", defaultSAMLUserNameAttribute)
+	config.SAMLEmailAttribute = getConfigItemStringValue(configFile, sectionName, "saml_email_attribute", defaultSAMLEmailAttribute)
+	config.SAMLNickNameAttribute = getConfigItemStringValue(configFile, sectionName, "saml_nickname_attribute", defaultSAMLNickNameAttribute)
+	config.SAMLAutoRegister = getConfigItemBoolValue(configFile, sectionName, "saml_auto_register", true)
+
+	config.SAMLAllowedClockSkew = getConfigItemUint32Value(configFile, sectionName, "saml_allowed_clock_skew", defaultSAMLAllowedClockSkew)
+	config.SAMLAllowedClockSkewDuration = time.Duration(config.SAMLAllowedClockSkew) * time.Second
+
+	config.SAMLStateExpiredTime = getConfigItemUint32Value(configFile, sectionName, "saml_state_expired_time", defaultSAMLStateExpiredTime)
+
+	if config.SAMLStateExpiredTime < 60 {
+		return errs.ErrInvalidSAMLStateExpiredTime
+	}
+
+	config.SAMLStateExpiredTimeDuration = time.Duration(config.SAMLStateExpiredTime) * time.Second
+
+	config.SAMLProxy = getConfigItemStringValue(configFile, sectionName, "saml_proxy", "system")
+	config.SAMLRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "saml_request_timeout", defaultSAMLRequestTimeout)
+	config.SAMLSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "saml_skip_tls_verify", false)
+
 	return nil
 }
 
@@ -1174,6 +1365,7 @@ func loadDataConfiguration(config *Config, configFile *ini.File, sectionName str
 	config.EnableDataExport = getConfigItemBoolValue(configFile, sectionName, "enable_export", false)
 	config.EnableDataImport = getConfigItemBoolValue(configFile, sectionName, "enable_import", false)
 	config.MaxImportFileSize = getConfigItemUint32Value(configFile, sectionName, "max_import_file_size", defaultImportFileMaxSize)
+	config.MaxBackupDataFileSize = getConfigItemUint32Value(configFile, sectionName, "max_backup_data_file_size", defaultBackupDataFileMaxSize)
 
 	return nil
 }
@@ -1294,6 +1486,33 @@ func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectio
 	return nil
 }
 
+func loadSecurityPricesConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	dataSource := getConfigItemStringValue(configFile, sectionName, "data_source")
+
+	if dataSource == "" || dataSource == StooqSecurityPricesDataSource {
+		config.SecurityPricesDataSource = dataSource
+	} else {
+		return errs.ErrInvalidSecurityPricesDataSource
+	}
+
+	config.SecurityPricesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
+	config.SecurityPricesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultSecurityPricesDataRequestTimeout)
+	config.SecurityPricesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+
+	return nil
+}
+
+func loadWebhookConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	config.EnableWebhook = getConfigItemBoolValue(configFile, sectionName, "enable_webhook", false)
+	config.WebhookRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultWebhookRequestTimeout)
+	config.WebhookProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
+	config.WebhookSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+	config.WebhookAllowPrivateNetwork = getConfigItemBoolValue(configFile, sectionName, "allow_private_network", false)
+	config.WebhookMaxRetryCount = getConfigItemUint32Value(configFile, sectionName, "max_retry_count", defaultWebhookMaxRetryCount)
+
+	return nil
+}
+
 func getWorkingPath() (string, error) {
 	workingPath := os.Getenv(ebkWorkDirEnvName)
 
@@ -1324,6 +1543,39 @@ func getFinalPath(workingPath, p string) (string, error) {
 	return p, err
 }
 
+func getStringList(configFile *ini.File, sectionName string, itemName string, defaultValue string) []string {
+	configValue := getConfigItemStringValue(configFile, sectionName, itemName, defaultValue)
+
+	if configValue == "" {
+		return nil
+	}
+
+	items := strings.Split(configValue, ",")
+	result := make([]string, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		item := strings.TrimSpace(items[i])
+
+		if item == "" {
+			continue
+		}
+
+		result = append(result, item)
+	}
+
+	return result
+}
+
+func getRootUrlOrigin(rootUrl string) string {
+	parsedUrl, err := url.Parse(rootUrl)
+
+	if err != nil || parsedUrl.Scheme == "" || parsedUrl.Host == "" {
+		return ""
+	}
+
+	return parsedUrl.Scheme + "://" + parsedUrl.Host
+}
+
 func getIPPatterns(configFile *ini.File, sectionName string, itemName string, defaultValue string) ([]*core.IPPattern, error) {
 	configValue := getConfigItemStringValue(configFile, sectionName, itemName, defaultValue)
 
@@ -33,6 +33,19 @@ func (s *LocalFileSystemObjectStorage) Exists(ctx core.Context, path string) (bo
 	return utils.IsExists(s.getFinalPath(path))
 }
 
+// Size returns the size of the file in bytes, returns 0 if the file does not exist
+func (s *LocalFileSystemObjectStorage) Size(ctx core.Context, path string) (int64, error) {
+	fileInfo, err := os.Stat(s.getFinalPath(path))
+
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return fileInfo.Size(), nil
+}
+
 // Read returns the object instance according to specified the file path
 func (s *LocalFileSystemObjectStorage) Read(ctx core.Context, path string) (ObjectInStorage, error) {
 	return os.Open(s.getFinalPath(path))
@@ -75,6 +75,25 @@ func (s *MinIOObjectStorage) Exists(ctx core.Context, path string) (bool, error)
 	return false, err
 }
 
+// Size returns the size of the file in bytes, returns 0 if the file does not exist
+func (s *MinIOObjectStorage) Size(ctx core.Context, path string) (int64, error) {
+	objectInfo, err := s.minIOClient.StatObject(ctx, s.minIOConfig.Bucket, s.getFinalPath(path), minio.StatObjectOptions{})
+
+	if err != nil {
+		if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	if objectInfo.IsDeleteMarker {
+		return 0, nil
+	}
+
+	return objectInfo.Size, nil
+}
+
 // Read returns the object instance according to specified the file path
 func (s *MinIOObjectStorage) Read(ctx core.Context, path string) (ObjectInStorage, error) {
 	return s.minIOClient.GetObject(ctx, s.minIOConfig.Bucket, s.getFinalPath(path), minio.GetObjectOptions{})
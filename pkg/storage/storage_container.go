@@ -66,6 +66,15 @@ func (s *StorageContainer) ExistsAvatar(ctx core.Context, path string) (bool, er
 	return s.avatarCurrentStorage.Exists(ctx, path)
 }
 
+// GetAvatarSize returns the size of the avatar file in bytes from the current avatar object storage
+func (s *StorageContainer) GetAvatarSize(ctx core.Context, path string) (int64, error) {
+	if s.avatarCurrentStorage == nil {
+		return 0, errs.ErrSystemError
+	}
+
+	return s.avatarCurrentStorage.Size(ctx, path)
+}
+
 // ReadAvatar returns the avatar file from the current avatar object storage
 func (s *StorageContainer) ReadAvatar(ctx core.Context, path string) (ObjectInStorage, error) {
 	if s.avatarCurrentStorage == nil {
@@ -102,6 +111,15 @@ func (s *StorageContainer) ExistsUserCustomIcon(ctx core.Context, path string) (
 	return s.userCustomIconCurrentStorage.Exists(ctx, path)
 }
 
+// GetUserCustomIconSize returns the size of the user custom icon file in bytes from the current user custom icon object storage
+func (s *StorageContainer) GetUserCustomIconSize(ctx core.Context, path string) (int64, error) {
+	if s.userCustomIconCurrentStorage == nil {
+		return 0, errs.ErrSystemError
+	}
+
+	return s.userCustomIconCurrentStorage.Size(ctx, path)
+}
+
 // ReadUserCustomIcon returns the user custom icon file from the current user custom icon object storage
 func (s *StorageContainer) ReadUserCustomIcon(ctx core.Context, path string) (ObjectInStorage, error) {
 	if s.userCustomIconCurrentStorage == nil {
@@ -138,6 +156,15 @@ func (s *StorageContainer) ExistsTransactionPicture(ctx core.Context, path strin
 	return s.transactionPictureCurrentStorage.Exists(ctx, path)
 }
 
+// GetTransactionPictureSize returns the size of the transaction picture file in bytes from the current transaction picture object storage
+func (s *StorageContainer) GetTransactionPictureSize(ctx core.Context, path string) (int64, error) {
+	if s.transactionPictureCurrentStorage == nil {
+		return 0, errs.ErrSystemError
+	}
+
+	return s.transactionPictureCurrentStorage.Size(ctx, path)
+}
+
 // ReadTransactionPicture returns the transaction picture file from the current transaction picture object storage
 func (s *StorageContainer) ReadTransactionPicture(ctx core.Context, path string) (ObjectInStorage, error) {
 	if s.transactionPictureCurrentStorage == nil {
@@ -78,6 +78,39 @@ func (s *WebDAVObjectStorage) Exists(ctx core.Context, path string) (bool, error
 	return false, errs.ErrSystemError
 }
 
+// Size returns the size of the file in bytes, returns 0 if the file does not exist
+func (s *WebDAVObjectStorage) Size(ctx core.Context, path string) (int64, error) {
+	req, err := http.NewRequest("HEAD", s.getFinalFileUrl(path), nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.SetBasicAuth(s.webDavConfig.Username, s.webDavConfig.Password)
+	resp, err := s.httpClient.Do(req)
+
+	if err != nil {
+		log.Errorf(ctx, "[webdav_storage.Size] cannot get file size, because %s", err.Error())
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	} else if resp.StatusCode != http.StatusOK {
+		log.Errorf(ctx, "[webdav_storage.Size] cannot get file size, http status code is %d", resp.StatusCode)
+		return 0, errs.ErrSystemError
+	}
+
+	if resp.ContentLength < 0 {
+		log.Errorf(ctx, "[webdav_storage.Size] cannot get file size, because content length is unknown")
+		return 0, errs.ErrSystemError
+	}
+
+	return resp.ContentLength, nil
+}
+
 // Read returns the object instance according to specified the file path
 func (s *WebDAVObjectStorage) Read(ctx core.Context, path string) (ObjectInStorage, error) {
 	req, err := http.NewRequest("GET", s.getFinalFileUrl(path), nil)
@@ -107,7 +140,7 @@ func (s *WebDAVObjectStorage) Read(ctx core.Context, path string) (ObjectInStora
 		return nil, errs.ErrSystemError
 	}
 
-	return newByteSliceObject(body), nil
+	return NewByteSliceObject(body), nil
 }
 
 // Save returns whether save the object instance successfully
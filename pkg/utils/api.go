@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 
@@ -73,10 +74,8 @@ func PrintJSONRPCSuccessResult(c *core.WebContext, jsonRPCRequest *core.JSONRPCR
 	c.JSON(http.StatusOK, core.NewJSONRPCResponse(jsonRPCRequest.ID, result))
 }
 
-// PrintJSONRPCErrorResult writes error response in JSON-RPC format to current http context
-func PrintJSONRPCErrorResult(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest, err *errs.Error) {
-	c.SetResponseError(err)
-
+// GetJSONRPCErrorResponse returns error response in JSON-RPC format
+func GetJSONRPCErrorResponse(jsonRPCRequest *core.JSONRPCRequest, err *errs.Error) *core.JSONRPCResponse {
 	var id any
 
 	if jsonRPCRequest != nil {
@@ -93,7 +92,18 @@ func PrintJSONRPCErrorResult(c *core.WebContext, jsonRPCRequest *core.JSONRPCReq
 		jsonRPCError = core.JSONRPCInvalidParamsError
 	}
 
-	c.AbortWithStatusJSON(err.HttpStatusCode, core.NewJSONRPCErrorResponseWithCause(id, jsonRPCError, GetDisplayErrorMessage(err)))
+	return core.NewJSONRPCErrorResponseWithCause(id, jsonRPCError, GetDisplayErrorMessage(err))
+}
+
+// PrintJSONRPCErrorResult writes error response in JSON-RPC format to current http context
+func PrintJSONRPCErrorResult(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest, err *errs.Error) {
+	c.SetResponseError(err)
+	c.AbortWithStatusJSON(err.HttpStatusCode, GetJSONRPCErrorResponse(jsonRPCRequest, err))
+}
+
+// PrintJSONRPCBatchResult writes all the responses of the batch request in JSON-RPC format to current http context
+func PrintJSONRPCBatchResult(c *core.WebContext, jsonRPCResponses []*core.JSONRPCResponse) {
+	c.JSON(http.StatusOK, jsonRPCResponses)
 }
 
 // PrintDataErrorResult writes error response in custom content type to current http context
@@ -110,6 +120,23 @@ func SetEventStreamHeader(c *core.WebContext) {
 	c.Writer.Header().Set("Connection", "keep-alive")
 }
 
+// IsEventStreamAccepted returns whether the client accepts event stream response
+func IsEventStreamAccepted(c *core.WebContext) bool {
+	return strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+// WriteEventStreamKeepAlive writes a comment line to the event stream to keep the connection alive
+func WriteEventStreamKeepAlive(c *core.WebContext) {
+	_, err := c.Writer.WriteString(": keep-alive\n\n")
+
+	if err != nil {
+		c.Abort()
+		return
+	}
+
+	c.Writer.Flush()
+}
+
 func WriteEventStreamJsonSuccessResult(c *core.WebContext, result any) {
 	data, err := json.Marshal(result)
 
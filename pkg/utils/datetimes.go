@@ -166,6 +166,11 @@ func FormatUnixTimeToNumericYearMonthDay(unixTime int64, timezone *time.Location
 	return int32(t.Year())*10000 + int32(t.Month())*100 + int32(t.Day())
 }
 
+// FormatNumericYearMonthDayToLongDate returns a textual representation of the numeric year, month and day in long date format
+func FormatNumericYearMonthDayToLongDate(yearMonthDay int32) string {
+	return fmt.Sprintf("%04d-%02d-%02d", yearMonthDay/10000, (yearMonthDay%10000)/100, yearMonthDay%100)
+}
+
 // FormatUnixTimeToNumericLocalDateTime returns numeric year, month, day, hour, minute and second of specified unix time
 func FormatUnixTimeToNumericLocalDateTime(unixTime int64, timezone *time.Location) int64 {
 	t := parseFromUnixTime(unixTime)
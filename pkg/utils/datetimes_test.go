@@ -147,6 +147,16 @@ func TestFormatUnixTimeToNumericYearMonthDay(t *testing.T) {
 	assert.Equal(t, expectedValue, actualValue)
 }
 
+func TestFormatNumericYearMonthDayToLongDate(t *testing.T) {
+	expectedValue := "2021-04-01"
+	actualValue := FormatNumericYearMonthDayToLongDate(20210401)
+	assert.Equal(t, expectedValue, actualValue)
+
+	expectedValue = "0999-12-31"
+	actualValue = FormatNumericYearMonthDayToLongDate(9991231)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
 func TestFormatUnixTimeToNumericLocalDateTime(t *testing.T) {
 	unixTime := int64(1617228083)
 	utcTimezone := time.FixedZone("Test Timezone", 0)      // UTC
@@ -62,3 +62,43 @@ func GetLocalIPAddresses() ([]net.IP, error) {
 
 	return localAddrs, nil
 }
+
+// nonPublicIPv4Networks represents the reserved ipv4 networks which are not covered by the standard library but should not be treated as public network
+var nonPublicIPv4Networks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+	mustParseCIDR("240.0.0.0/4"),
+}
+
+// IsPublicIPAddress returns whether the specified ip address is a public network address, loopback, private, link-local, multicast, unspecified and other reserved addresses are not public network address
+func IsPublicIPAddress(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+
+	if ipv4 := ip.To4(); ipv4 != nil {
+		for i := 0; i < len(nonPublicIPv4Networks); i++ {
+			if nonPublicIPv4Networks[i].Contains(ipv4) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(cidr)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return ipNet
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicIPAddress_PublicAddress(t *testing.T) {
+	assert.True(t, IsPublicIPAddress(net.ParseIP("1.1.1.1")))
+	assert.True(t, IsPublicIPAddress(net.ParseIP("8.8.8.8")))
+	assert.True(t, IsPublicIPAddress(net.ParseIP("2606:4700:4700::1111")))
+}
+
+func TestIsPublicIPAddress_NonPublicAddress(t *testing.T) {
+	assert.False(t, IsPublicIPAddress(nil))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("127.0.0.1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("::1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("10.0.0.1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("172.16.0.1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("192.168.1.1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("169.254.169.254")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("fe80::1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("fd00::1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("0.0.0.0")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("::")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("100.64.0.1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("224.0.0.1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("::ffff:127.0.0.1")))
+	assert.False(t, IsPublicIPAddress(net.ParseIP("::ffff:169.254.169.254")))
+}
@@ -0,0 +1,624 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+const recurrenceRulePrefix = "RRULE:"
+const recurrenceRuleUntilDateFormat = "20060102"
+const recurrenceRuleMaxExpandedPeriods = 100000
+
+// RecurrenceFrequency represents the frequency of recurrence rule
+type RecurrenceFrequency byte
+
+// Recurrence frequencies
+const (
+	RECURRENCE_FREQUENCY_DAILY   RecurrenceFrequency = 1
+	RECURRENCE_FREQUENCY_WEEKLY  RecurrenceFrequency = 2
+	RECURRENCE_FREQUENCY_MONTHLY RecurrenceFrequency = 3
+	RECURRENCE_FREQUENCY_YEARLY  RecurrenceFrequency = 4
+)
+
+var recurrenceFrequencyNames = map[RecurrenceFrequency]string{
+	RECURRENCE_FREQUENCY_DAILY:   "DAILY",
+	RECURRENCE_FREQUENCY_WEEKLY:  "WEEKLY",
+	RECURRENCE_FREQUENCY_MONTHLY: "MONTHLY",
+	RECURRENCE_FREQUENCY_YEARLY:  "YEARLY",
+}
+
+var recurrenceWeekdayNames = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// String returns a textual representation of the recurrence frequency
+func (f RecurrenceFrequency) String() string {
+	if name, exists := recurrenceFrequencyNames[f]; exists {
+		return name
+	}
+
+	return fmt.Sprintf("Invalid(%d)", int(f))
+}
+
+// RecurrenceWeekday represents a weekday with optional ordinal in BYDAY rule part, e.g. "MO", "2TU" or "-1FR"
+type RecurrenceWeekday struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// String returns a textual representation of the recurrence weekday
+func (w RecurrenceWeekday) String() string {
+	if w.Ordinal == 0 {
+		return recurrenceWeekdayNames[w.Weekday]
+	}
+
+	return fmt.Sprintf("%d%s", w.Ordinal, recurrenceWeekdayNames[w.Weekday])
+}
+
+// RecurrenceRule represents a date-based subset of RFC 5545 recurrence rule
+type RecurrenceRule struct {
+	Frequency  RecurrenceFrequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByMonth    []int
+	ByMonthDay []int
+	ByDay      []RecurrenceWeekday
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+// ParseRecurrenceRule parses a textual recurrence rule like "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1"
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	value = strings.TrimSpace(value)
+
+	if len(value) >= len(recurrenceRulePrefix) && strings.EqualFold(value[0:len(recurrenceRulePrefix)], recurrenceRulePrefix) {
+		value = value[len(recurrenceRulePrefix):]
+	}
+
+	if value == "" {
+		return nil, errs.ErrFormatInvalid
+	}
+
+	rule := &RecurrenceRule{
+		Interval:  1,
+		WeekStart: time.Monday,
+	}
+
+	parsedKeys := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+
+		items := strings.SplitN(part, "=", 2)
+
+		if len(items) != 2 || items[1] == "" {
+			return nil, errs.ErrFormatInvalid
+		}
+
+		key := strings.ToUpper(strings.TrimSpace(items[0]))
+		partValue := strings.ToUpper(strings.TrimSpace(items[1]))
+
+		if parsedKeys[key] {
+			return nil, errs.ErrFormatInvalid
+		}
+
+		parsedKeys[key] = true
+
+		var err error
+
+		switch key {
+		case "FREQ":
+			rule.Frequency, err = parseRecurrenceFrequency(partValue)
+		case "INTERVAL":
+			rule.Interval, err = parseRecurrenceRuleIntValue(partValue, 1, 1000, false)
+		case "COUNT":
+			rule.Count, err = parseRecurrenceRuleIntValue(partValue, 1, 10000, false)
+		case "UNTIL":
+			rule.Until, err = parseRecurrenceRuleUntil(partValue)
+		case "BYMONTH":
+			rule.ByMonth, err = parseRecurrenceRuleIntValues(partValue, 1, 12, false)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseRecurrenceRuleIntValues(partValue, 1, 31, true)
+		case "BYDAY":
+			rule.ByDay, err = parseRecurrenceRuleWeekdays(partValue)
+		case "BYSETPOS":
+			rule.BySetPos, err = parseRecurrenceRuleIntValues(partValue, 1, 366, true)
+		case "WKST":
+			var weekday RecurrenceWeekday
+			weekday, err = parseRecurrenceRuleWeekday(partValue)
+
+			if err == nil && weekday.Ordinal != 0 {
+				err = errs.ErrFormatInvalid
+			}
+
+			rule.WeekStart = weekday.Weekday
+		default:
+			return nil, errs.ErrFormatInvalid
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.Frequency == 0 {
+		return nil, errs.ErrFormatInvalid
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, errs.ErrParameterInvalid
+	}
+
+	if len(rule.BySetPos) > 0 && len(rule.ByMonth) < 1 && len(rule.ByMonthDay) < 1 && len(rule.ByDay) < 1 {
+		return nil, errs.ErrParameterInvalid
+	}
+
+	for i := 0; i < len(rule.ByDay); i++ {
+		if rule.ByDay[i].Ordinal == 0 {
+			continue
+		}
+
+		if rule.Frequency != RECURRENCE_FREQUENCY_MONTHLY && rule.Frequency != RECURRENCE_FREQUENCY_YEARLY {
+			return nil, errs.ErrParameterInvalid
+		}
+
+		if rule.Frequency == RECURRENCE_FREQUENCY_MONTHLY && (rule.ByDay[i].Ordinal > 5 || rule.ByDay[i].Ordinal < -5) {
+			return nil, errs.ErrParameterInvalid
+		}
+	}
+
+	if rule.Frequency == RECURRENCE_FREQUENCY_WEEKLY && len(rule.ByMonthDay) > 0 {
+		return nil, errs.ErrParameterInvalid
+	}
+
+	return rule, nil
+}
+
+// String returns the normalized textual representation of the recurrence rule
+func (r *RecurrenceRule) String() string {
+	var builder strings.Builder
+
+	builder.WriteString("FREQ=")
+	builder.WriteString(r.Frequency.String())
+
+	if r.Interval > 1 {
+		builder.WriteString(fmt.Sprintf(";INTERVAL=%d", r.Interval))
+	}
+
+	if r.Count > 0 {
+		builder.WriteString(fmt.Sprintf(";COUNT=%d", r.Count))
+	}
+
+	if r.Until != nil {
+		builder.WriteString(";UNTIL=")
+		builder.WriteString(r.Until.Format(recurrenceRuleUntilDateFormat))
+	}
+
+	if len(r.ByMonth) > 0 {
+		builder.WriteString(";BYMONTH=")
+		builder.WriteString(joinRecurrenceRuleIntValues(r.ByMonth))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		builder.WriteString(";BYMONTHDAY=")
+		builder.WriteString(joinRecurrenceRuleIntValues(r.ByMonthDay))
+	}
+
+	if len(r.ByDay) > 0 {
+		weekdays := make([]string, len(r.ByDay))
+
+		for i := 0; i < len(r.ByDay); i++ {
+			weekdays[i] = r.ByDay[i].String()
+		}
+
+		builder.WriteString(";BYDAY=")
+		builder.WriteString(strings.Join(weekdays, ","))
+	}
+
+	if len(r.BySetPos) > 0 {
+		builder.WriteString(";BYSETPOS=")
+		builder.WriteString(joinRecurrenceRuleIntValues(r.BySetPos))
+	}
+
+	if r.WeekStart != time.Monday {
+		builder.WriteString(";WKST=")
+		builder.WriteString(recurrenceWeekdayNames[r.WeekStart])
+	}
+
+	return builder.String()
+}
+
+// GetOccurrenceDates returns all occurrence dates of the recurrence rule starting from the specified start date, which are between from date and to date (both inclusive).
+// Only the calendar date of each parameter is used, and the returned dates are the midnight of each date in UTC
+func (r *RecurrenceRule) GetOccurrenceDates(startDate time.Time, fromDate time.Time, toDate time.Time) []time.Time {
+	start := toRecurrenceDate(startDate)
+	from := toRecurrenceDate(fromDate)
+	to := toRecurrenceDate(toDate)
+
+	if r.Until != nil && r.Until.Before(to) {
+		to = toRecurrenceDate(*r.Until)
+	}
+
+	if from.Before(start) {
+		from = start
+	}
+
+	result := make([]time.Time, 0)
+
+	if to.Before(from) {
+		return result
+	}
+
+	interval := r.Interval
+
+	if interval < 1 {
+		interval = 1
+	}
+
+	firstPeriodStart := r.getPeriodStart(start)
+	periodIndex := 0
+
+	// the COUNT rule part limits the occurrences from the start date, so all periods need to be enumerated
+	if r.Count < 1 {
+		periodIndex = r.getPeriodsBetween(firstPeriodStart, r.getPeriodStart(from)) / interval
+	}
+
+	occurrenceCount := 0
+
+	for i := 0; i < recurrenceRuleMaxExpandedPeriods; i++ {
+		periodStart := r.addPeriods(firstPeriodStart, periodIndex*interval)
+
+		if periodStart.After(to) {
+			break
+		}
+
+		for _, date := range r.expandPeriod(periodStart, start) {
+			if date.Before(start) {
+				continue
+			}
+
+			if r.Count > 0 {
+				occurrenceCount++
+
+				if occurrenceCount > r.Count {
+					return result
+				}
+			}
+
+			if date.After(to) {
+				return result
+			}
+
+			if !date.Before(from) {
+				result = append(result, date)
+			}
+		}
+
+		periodIndex++
+	}
+
+	return result
+}
+
+func (r *RecurrenceRule) getPeriodStart(date time.Time) time.Time {
+	switch r.Frequency {
+	case RECURRENCE_FREQUENCY_WEEKLY:
+		daysFromWeekStart := (int(date.Weekday()) - int(r.WeekStart) + 7) % 7
+		return date.AddDate(0, 0, -daysFromWeekStart)
+	case RECURRENCE_FREQUENCY_MONTHLY:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case RECURRENCE_FREQUENCY_YEARLY:
+		return time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return date
+	}
+}
+
+func (r *RecurrenceRule) getPeriodsBetween(fromPeriodStart time.Time, toPeriodStart time.Time) int {
+	switch r.Frequency {
+	case RECURRENCE_FREQUENCY_WEEKLY:
+		return int(toPeriodStart.Sub(fromPeriodStart).Hours()/24) / 7
+	case RECURRENCE_FREQUENCY_MONTHLY:
+		return (toPeriodStart.Year()-fromPeriodStart.Year())*12 + int(toPeriodStart.Month()) - int(fromPeriodStart.Month())
+	case RECURRENCE_FREQUENCY_YEARLY:
+		return toPeriodStart.Year() - fromPeriodStart.Year()
+	default:
+		return int(toPeriodStart.Sub(fromPeriodStart).Hours() / 24)
+	}
+}
+
+func (r *RecurrenceRule) addPeriods(periodStart time.Time, periods int) time.Time {
+	switch r.Frequency {
+	case RECURRENCE_FREQUENCY_WEEKLY:
+		return periodStart.AddDate(0, 0, periods*7)
+	case RECURRENCE_FREQUENCY_MONTHLY:
+		return periodStart.AddDate(0, periods, 0)
+	case RECURRENCE_FREQUENCY_YEARLY:
+		return periodStart.AddDate(periods, 0, 0)
+	default:
+		return periodStart.AddDate(0, 0, periods)
+	}
+}
+
+func (r *RecurrenceRule) expandPeriod(periodStart time.Time, start time.Time) []time.Time {
+	var periodEnd time.Time
+
+	switch r.Frequency {
+	case RECURRENCE_FREQUENCY_WEEKLY:
+		periodEnd = periodStart.AddDate(0, 0, 7)
+	case RECURRENCE_FREQUENCY_MONTHLY:
+		periodEnd = periodStart.AddDate(0, 1, 0)
+	case RECURRENCE_FREQUENCY_YEARLY:
+		periodEnd = periodStart.AddDate(1, 0, 0)
+	default:
+		periodEnd = periodStart.AddDate(0, 0, 1)
+	}
+
+	candidates := make([]time.Time, 0)
+
+	for date := periodStart; date.Before(periodEnd); date = date.AddDate(0, 0, 1) {
+		if r.isDateMatched(date, start) {
+			candidates = append(candidates, date)
+		}
+	}
+
+	if len(r.BySetPos) < 1 || len(candidates) < 1 {
+		return candidates
+	}
+
+	selected := make(map[int]bool, len(r.BySetPos))
+
+	for _, pos := range r.BySetPos {
+		index := pos - 1
+
+		if pos < 0 {
+			index = len(candidates) + pos
+		}
+
+		if index >= 0 && index < len(candidates) {
+			selected[index] = true
+		}
+	}
+
+	result := make([]time.Time, 0, len(selected))
+
+	for i := 0; i < len(candidates); i++ {
+		if selected[i] {
+			result = append(result, candidates[i])
+		}
+	}
+
+	return result
+}
+
+func (r *RecurrenceRule) isDateMatched(date time.Time, start time.Time) bool {
+	if len(r.ByMonth) > 0 && !containsRecurrenceRuleIntValue(r.ByMonth, int(date.Month())) {
+		return false
+	}
+
+	if len(r.ByMonthDay) > 0 && !r.isMonthDayMatched(date) {
+		return false
+	}
+
+	if len(r.ByDay) > 0 && !r.isWeekdayMatched(date) {
+		return false
+	}
+
+	// the missing rule parts are derived from the start date
+	switch r.Frequency {
+	case RECURRENCE_FREQUENCY_WEEKLY:
+		if len(r.ByDay) < 1 {
+			return date.Weekday() == start.Weekday()
+		}
+	case RECURRENCE_FREQUENCY_MONTHLY:
+		if len(r.ByMonthDay) < 1 && len(r.ByDay) < 1 {
+			return date.Day() == start.Day()
+		}
+	case RECURRENCE_FREQUENCY_YEARLY:
+		if len(r.ByMonthDay) < 1 && len(r.ByDay) < 1 {
+			if len(r.ByMonth) < 1 && date.Month() != start.Month() {
+				return false
+			}
+
+			return date.Day() == start.Day()
+		}
+	}
+
+	return true
+}
+
+func (r *RecurrenceRule) isMonthDayMatched(date time.Time) bool {
+	maxDayOfMonth := GetMaxDayOfMonth(date.Year(), date.Month())
+
+	for _, monthDay := range r.ByMonthDay {
+		if monthDay == date.Day() || (monthDay < 0 && maxDayOfMonth+monthDay+1 == date.Day()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *RecurrenceRule) isWeekdayMatched(date time.Time) bool {
+	for _, weekday := range r.ByDay {
+		if weekday.Weekday != date.Weekday() {
+			continue
+		}
+
+		if weekday.Ordinal == 0 {
+			return true
+		}
+
+		var dayOfScope, daysInScope int
+
+		// the ordinal is relative to the month in monthly rule or yearly rule with BYMONTH, otherwise relative to the year
+		if r.Frequency == RECURRENCE_FREQUENCY_MONTHLY || len(r.ByMonth) > 0 {
+			dayOfScope = date.Day()
+			daysInScope = GetMaxDayOfMonth(date.Year(), date.Month())
+		} else {
+			dayOfScope = date.YearDay()
+			daysInScope = time.Date(date.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		}
+
+		ordinalFromStart := (dayOfScope-1)/7 + 1
+		ordinalFromEnd := -((daysInScope-dayOfScope)/7 + 1)
+
+		if weekday.Ordinal == ordinalFromStart || weekday.Ordinal == ordinalFromEnd {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toRecurrenceDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func parseRecurrenceFrequency(value string) (RecurrenceFrequency, error) {
+	for frequency, name := range recurrenceFrequencyNames {
+		if name == value {
+			return frequency, nil
+		}
+	}
+
+	return 0, errs.ErrFormatInvalid
+}
+
+func parseRecurrenceRuleUntil(value string) (*time.Time, error) {
+	if len(value) > len(recurrenceRuleUntilDateFormat) && value[len(recurrenceRuleUntilDateFormat)] == 'T' {
+		value = value[0:len(recurrenceRuleUntilDateFormat)]
+	}
+
+	until, err := time.ParseInLocation(recurrenceRuleUntilDateFormat, value, time.UTC)
+
+	if err != nil {
+		return nil, errs.ErrFormatInvalid
+	}
+
+	return &until, nil
+}
+
+func parseRecurrenceRuleIntValue(value string, min int, max int, allowNegative bool) (int, error) {
+	intValue, err := StringToInt(value)
+
+	if err != nil {
+		return 0, errs.ErrFormatInvalid
+	}
+
+	absValue := intValue
+
+	if allowNegative && absValue < 0 {
+		absValue = -absValue
+	}
+
+	if absValue < min || absValue > max {
+		return 0, errs.ErrParameterInvalid
+	}
+
+	return intValue, nil
+}
+
+func parseRecurrenceRuleIntValues(value string, min int, max int, allowNegative bool) ([]int, error) {
+	items := strings.Split(value, ",")
+	result := make([]int, 0, len(items))
+
+	for _, item := range items {
+		intValue, err := parseRecurrenceRuleIntValue(item, min, max, allowNegative)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !containsRecurrenceRuleIntValue(result, intValue) {
+			result = append(result, intValue)
+		}
+	}
+
+	sort.Ints(result)
+
+	return result, nil
+}
+
+func parseRecurrenceRuleWeekdays(value string) ([]RecurrenceWeekday, error) {
+	items := strings.Split(value, ",")
+	result := make([]RecurrenceWeekday, 0, len(items))
+
+	for _, item := range items {
+		weekday, err := parseRecurrenceRuleWeekday(item)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, weekday)
+	}
+
+	return result, nil
+}
+
+func parseRecurrenceRuleWeekday(value string) (RecurrenceWeekday, error) {
+	if len(value) < 2 {
+		return RecurrenceWeekday{}, errs.ErrFormatInvalid
+	}
+
+	weekdayName := value[len(value)-2:]
+	ordinalValue := value[0 : len(value)-2]
+
+	for weekday, name := range recurrenceWeekdayNames {
+		if name != weekdayName {
+			continue
+		}
+
+		if ordinalValue == "" {
+			return RecurrenceWeekday{Weekday: weekday}, nil
+		}
+
+		if ordinalValue[0] == '+' {
+			ordinalValue = ordinalValue[1:]
+		}
+
+		ordinal, err := parseRecurrenceRuleIntValue(ordinalValue, 1, 53, true)
+
+		if err != nil {
+			return RecurrenceWeekday{}, err
+		}
+
+		return RecurrenceWeekday{Ordinal: ordinal, Weekday: weekday}, nil
+	}
+
+	return RecurrenceWeekday{}, errs.ErrFormatInvalid
+}
+
+func containsRecurrenceRuleIntValue(values []int, value int) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinRecurrenceRuleIntValues(values []int) string {
+	items := make([]string, len(values))
+
+	for i := 0; i < len(values); i++ {
+		items[i] = IntToString(values[i])
+	}
+
+	return strings.Join(items, ",")
+}
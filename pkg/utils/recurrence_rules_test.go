@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func formatRecurrenceDates(dates []time.Time) []string {
+	result := make([]string, len(dates))
+
+	for i := 0; i < len(dates); i++ {
+		result[i] = dates[i].Format(longDateFormat)
+	}
+
+	return result
+}
+
+func parseRecurrenceTestDate(value string) time.Time {
+	date, _ := time.ParseInLocation(longDateFormat, value, time.UTC)
+	return date
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	rule, err := ParseRecurrenceRule("RRULE:FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=15")
+	assert.Nil(t, err)
+	assert.Equal(t, RECURRENCE_FREQUENCY_MONTHLY, rule.Frequency)
+	assert.Equal(t, 3, rule.Interval)
+	assert.Equal(t, []int{15}, rule.ByMonthDay)
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=15", rule.String())
+
+	rule, err = ParseRecurrenceRule("freq=monthly;byday=mo,tu,we,th,fr;bysetpos=-1")
+	assert.Nil(t, err)
+	assert.Equal(t, []RecurrenceWeekday{{0, time.Monday}, {0, time.Tuesday}, {0, time.Wednesday}, {0, time.Thursday}, {0, time.Friday}}, rule.ByDay)
+	assert.Equal(t, []int{-1}, rule.BySetPos)
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", rule.String())
+
+	rule, err = ParseRecurrenceRule("FREQ=YEARLY;BYMONTH=11;BYDAY=+4TH;UNTIL=20301231T235959Z;WKST=SU")
+	assert.Nil(t, err)
+	assert.Equal(t, []RecurrenceWeekday{{4, time.Thursday}}, rule.ByDay)
+	assert.Equal(t, "FREQ=YEARLY;UNTIL=20301231;BYMONTH=11;BYDAY=4TH;WKST=SU", rule.String())
+}
+
+func TestParseRecurrenceRule_InvalidRule(t *testing.T) {
+	_, err := ParseRecurrenceRule("")
+	assert.EqualError(t, err, errs.ErrFormatInvalid.Message)
+
+	_, err = ParseRecurrenceRule("INTERVAL=2")
+	assert.EqualError(t, err, errs.ErrFormatInvalid.Message)
+
+	_, err = ParseRecurrenceRule("FREQ=HOURLY")
+	assert.EqualError(t, err, errs.ErrFormatInvalid.Message)
+
+	_, err = ParseRecurrenceRule("FREQ=DAILY;BYHOUR=10")
+	assert.EqualError(t, err, errs.ErrFormatInvalid.Message)
+
+	_, err = ParseRecurrenceRule("FREQ=DAILY;FREQ=WEEKLY")
+	assert.EqualError(t, err, errs.ErrFormatInvalid.Message)
+
+	_, err = ParseRecurrenceRule("FREQ=MONTHLY;BYMONTHDAY=0")
+	assert.EqualError(t, err, errs.ErrParameterInvalid.Message)
+
+	_, err = ParseRecurrenceRule("FREQ=MONTHLY;COUNT=3;UNTIL=20300101")
+	assert.EqualError(t, err, errs.ErrParameterInvalid.Message)
+
+	_, err = ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=2TU")
+	assert.EqualError(t, err, errs.ErrParameterInvalid.Message)
+
+	_, err = ParseRecurrenceRule("FREQ=MONTHLY;BYSETPOS=1")
+	assert.EqualError(t, err, errs.ErrParameterInvalid.Message)
+}
+
+func TestRecurrenceRuleGetOccurrenceDates_LastBusinessDayOfMonth(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1")
+	assert.Nil(t, err)
+
+	actualDates := rule.GetOccurrenceDates(parseRecurrenceTestDate("2024-01-01"), parseRecurrenceTestDate("2024-01-01"), parseRecurrenceTestDate("2024-06-30"))
+	assert.Equal(t, []string{"2024-01-31", "2024-02-29", "2024-03-29", "2024-04-30", "2024-05-31", "2024-06-28"}, formatRecurrenceDates(actualDates))
+}
+
+func TestRecurrenceRuleGetOccurrenceDates_SecondTuesdayOfMonth(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=MONTHLY;BYDAY=2TU")
+	assert.Nil(t, err)
+
+	actualDates := rule.GetOccurrenceDates(parseRecurrenceTestDate("2024-01-01"), parseRecurrenceTestDate("2024-02-01"), parseRecurrenceTestDate("2024-04-30"))
+	assert.Equal(t, []string{"2024-02-13", "2024-03-12", "2024-04-09"}, formatRecurrenceDates(actualDates))
+}
+
+func TestRecurrenceRuleGetOccurrenceDates_Quarterly(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=MONTHLY;INTERVAL=3")
+	assert.Nil(t, err)
+
+	actualDates := rule.GetOccurrenceDates(parseRecurrenceTestDate("2024-01-15"), parseRecurrenceTestDate("2024-05-01"), parseRecurrenceTestDate("2025-01-31"))
+	assert.Equal(t, []string{"2024-07-15", "2024-10-15", "2025-01-15"}, formatRecurrenceDates(actualDates))
+}
+
+func TestRecurrenceRuleGetOccurrenceDates_BiWeekly(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR")
+	assert.Nil(t, err)
+
+	actualDates := rule.GetOccurrenceDates(parseRecurrenceTestDate("2024-01-03"), parseRecurrenceTestDate("2024-01-01"), parseRecurrenceTestDate("2024-01-31"))
+	assert.Equal(t, []string{"2024-01-05", "2024-01-15", "2024-01-19", "2024-01-29"}, formatRecurrenceDates(actualDates))
+}
+
+func TestRecurrenceRuleGetOccurrenceDates_YearlyWithDefaultDate(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=YEARLY")
+	assert.Nil(t, err)
+
+	actualDates := rule.GetOccurrenceDates(parseRecurrenceTestDate("2020-03-10"), parseRecurrenceTestDate("2023-01-01"), parseRecurrenceTestDate("2024-12-31"))
+	assert.Equal(t, []string{"2023-03-10", "2024-03-10"}, formatRecurrenceDates(actualDates))
+}
+
+func TestRecurrenceRuleGetOccurrenceDates_LastDayOfMonth(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=MONTHLY;BYMONTHDAY=-1")
+	assert.Nil(t, err)
+
+	actualDates := rule.GetOccurrenceDates(parseRecurrenceTestDate("2023-01-01"), parseRecurrenceTestDate("2023-01-01"), parseRecurrenceTestDate("2023-04-30"))
+	assert.Equal(t, []string{"2023-01-31", "2023-02-28", "2023-03-31", "2023-04-30"}, formatRecurrenceDates(actualDates))
+}
+
+func TestRecurrenceRuleGetOccurrenceDates_Count(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=DAILY;INTERVAL=10;COUNT=3")
+	assert.Nil(t, err)
+
+	actualDates := rule.GetOccurrenceDates(parseRecurrenceTestDate("2024-01-01"), parseRecurrenceTestDate("2024-01-05"), parseRecurrenceTestDate("2024-12-31"))
+	assert.Equal(t, []string{"2024-01-11", "2024-01-21"}, formatRecurrenceDates(actualDates))
+}
+
+func TestRecurrenceRuleGetOccurrenceDates_Until(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=WEEKLY;UNTIL=20240120")
+	assert.Nil(t, err)
+
+	actualDates := rule.GetOccurrenceDates(parseRecurrenceTestDate("2024-01-01"), parseRecurrenceTestDate("2024-01-01"), parseRecurrenceTestDate("2024-12-31"))
+	assert.Equal(t, []string{"2024-01-01", "2024-01-08", "2024-01-15"}, formatRecurrenceDates(actualDates))
+}
+
+func TestRecurrenceRuleGetOccurrenceDates_FromDateEarlierThanStartDate(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=DAILY;INTERVAL=2")
+	assert.Nil(t, err)
+
+	actualDates := rule.GetOccurrenceDates(parseRecurrenceTestDate("2024-03-01"), parseRecurrenceTestDate("2024-02-01"), parseRecurrenceTestDate("2024-03-06"))
+	assert.Equal(t, []string{"2024-03-01", "2024-03-03", "2024-03-05"}, formatRecurrenceDates(actualDates))
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/sha256"
@@ -173,6 +174,13 @@ func MD5EncodeToStringWithUidAndSalt(data []byte, uid int64, salt string) string
 	return hex.EncodeToString(hash)
 }
 
+// HmacSha256EncodeToString returns a hex encoded hmac-sha256 signature of the data with the key
+func HmacSha256EncodeToString(key []byte, data []byte) string {
+	m := hmac.New(sha256.New, key)
+	m.Write(data)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
 // AESGCMEncrypt returns a encrypted string by aes-gcm
 func AESGCMEncrypt(key []byte, plainText []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
@@ -161,6 +161,12 @@ func TestMD5EncodeToStringWithUidAndSalt(t *testing.T) {
 	assert.Equal(t, expectedValue, actualValue)
 }
 
+func TestHmacSha256EncodeToString(t *testing.T) {
+	expectedValue := "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	actualValue := HmacSha256EncodeToString([]byte("key"), []byte("The quick brown fox jumps over the lazy dog"))
+	assert.Equal(t, expectedValue, actualValue)
+}
+
 func TestEncodePassword(t *testing.T) {
 	password := "foobar"
 	salt := "salt"
@@ -59,6 +59,34 @@ func TestGenerateUuid_MultiType(t *testing.T) {
 	assert.Equal(t, uint32(expectedSeqId), actualSeqId)
 }
 
+func TestGenerateUuid_SharedType(t *testing.T) {
+	generator, _ := NewInternalUuidGenerator(&settings.Config{UuidServerId: 1})
+	userUuid := generator.GenerateUuid(UUID_TYPE_USER)
+	passkeyUuid := generator.GenerateUuid(UUID_TYPE_PASSKEY)
+	assert.NotEqual(t, userUuid, passkeyUuid)
+
+	passkeyUuidInfo := generator.parseInternalUuidInfo(passkeyUuid)
+	assert.Equal(t, uint8(UUID_TYPE_USER), passkeyUuidInfo.UuidType)
+
+	ledgerUuid := generator.GenerateUuid(UUID_TYPE_LEDGER)
+	ledgerMemberUuid := generator.GenerateUuid(UUID_TYPE_LEDGER_MEMBER)
+	assert.NotEqual(t, passkeyUuid, ledgerUuid)
+	assert.NotEqual(t, ledgerUuid, ledgerMemberUuid)
+
+	ledgerUuidInfo := generator.parseInternalUuidInfo(ledgerUuid)
+	assert.Equal(t, uint8(UUID_TYPE_USER), ledgerUuidInfo.UuidType)
+
+	ledgerMemberUuidInfo := generator.parseInternalUuidInfo(ledgerMemberUuid)
+	assert.Equal(t, uint8(UUID_TYPE_USER), ledgerMemberUuidInfo.UuidType)
+
+	templateUuid := generator.GenerateUuid(UUID_TYPE_TEMPLATE)
+	templateExceptionUuid := generator.GenerateUuid(UUID_TYPE_TEMPLATE_EXCEPTION)
+	assert.NotEqual(t, templateUuid, templateExceptionUuid)
+
+	templateExceptionUuidInfo := generator.parseInternalUuidInfo(templateExceptionUuid)
+	assert.Equal(t, uint8(UUID_TYPE_TEMPLATE), templateExceptionUuidInfo.UuidType)
+}
+
 func TestGenerateUuid_2000TimesIn2Seconds(t *testing.T) {
 	generator, _ := NewInternalUuidGenerator(&settings.Config{UuidServerId: 2})
 	firstGeneratedTime := int64(0)
@@ -17,4 +17,16 @@ const (
 	UUID_TYPE_EXPLORER    UuidType = 9
 	UUID_TYPE_TAG_GROUP   UuidType = 10
 	UUID_TYPE_CUSTOM_ICON UuidType = 11
+	UUID_TYPE_BUDGET      UuidType = 12
+	UUID_TYPE_SPLIT       UuidType = 13
+	UUID_TYPE_INVESTMENT  UuidType = 14
+	UUID_TYPE_AUTOMATION  UuidType = 15
+)
+
+// Types of uuid which share the sequential number with another type, because all the values of uuid type from 0 to 15 have been used
+const (
+	UUID_TYPE_PASSKEY            UuidType = UUID_TYPE_USER
+	UUID_TYPE_LEDGER             UuidType = UUID_TYPE_USER
+	UUID_TYPE_LEDGER_MEMBER      UuidType = UUID_TYPE_USER
+	UUID_TYPE_TEMPLATE_EXCEPTION UuidType = UUID_TYPE_TEMPLATE
 )